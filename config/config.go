@@ -2,13 +2,17 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings" // Import strings package
 	"time"
 
+	"go-api-template/pkg/hasher"
+
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Config holds all configuration for the application
@@ -19,21 +23,57 @@ type Config struct {
 	JWT    JWTConfig    `mapstructure:"jwt"`
 	Blockchain BlockchainConfig `mapstructure:"blockchain"`
 	Redis      RedisConfig     `mapstructure:"redis"`
+	Auth       AuthConfig      `mapstructure:"auth"`
+	Log        LogConfig       `mapstructure:"log"`
+	Metrics    MetricsConfig   `mapstructure:"metrics"`
+	RateLimit  RateLimitConfig `mapstructure:"rate_limit"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Password   PasswordConfig  `mapstructure:"password"`
+	Outbox     OutboxConfig    `mapstructure:"outbox"`
+	Invoice    InvoiceConfig   `mapstructure:"invoice"`
+	Currency   CurrencyConfig  `mapstructure:"currency"`
+	Contractor ContractorConfig `mapstructure:"contractor"`
+	Avatar     AvatarConfig    `mapstructure:"avatar"`
+	Archiver   ArchiverConfig  `mapstructure:"archiver"`
+	AutoInvoice AutoInvoiceConfig `mapstructure:"auto_invoice"`
+	Retention  RetentionConfig `mapstructure:"retention"`
+	Scheduler  SchedulerConfig `mapstructure:"scheduler"`
+	Webhook    WebhookConfig   `mapstructure:"webhook"`
+	Mailer     MailerConfig    `mapstructure:"mailer"`
+	Flags      FlagsConfig     `mapstructure:"flags"`
+	Cache      CacheConfig     `mapstructure:"cache"`
+	Tracing    TracingConfig   `mapstructure:"tracing"`
 }
 
 // ServerConfig holds server specific configuration
 type ServerConfig struct {
-	Port int    `mapstructure:"port"`
-	Host string `mapstructure:"host"`
+	Port                  int           `mapstructure:"port" env:"SERVER_PORT" validate:"min=1,max=65535"`
+	Host                  string        `mapstructure:"host"`
+	MaxBodyBytes          int64         `mapstructure:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES" validate:"min=0"`                   // Larger request bodies get 413; 0 disables the limit
+	RequestTimeoutSeconds int           `mapstructure:"request_timeout_seconds" env:"SERVER_REQUEST_TIMEOUT_SECONDS" validate:"min=0"` // Slower requests get 503; 0 disables the timeout
+	RequestTimeout        time.Duration `mapstructure:"-"`                                                                             // Calculated duration, ignore during unmarshal
+	MaxBatchGetUsers      int           `mapstructure:"max_batch_get_users" env:"SERVER_MAX_BATCH_GET_USERS" validate:"min=1"`         // Most IDs accepted by POST /users/batch-get
+	MaxPageSize           int           `mapstructure:"max_page_size" env:"SERVER_MAX_PAGE_SIZE" validate:"min=1"`                     // Largest limit list endpoints accept; larger ones are clamped
+	ShutdownDrainSeconds  int           `mapstructure:"shutdown_drain_seconds" env:"SERVER_SHUTDOWN_DRAIN_SECONDS" validate:"min=0"`   // How long shutdown waits for in-flight requests before cutting them off
+	ShutdownDrain         time.Duration `mapstructure:"-"`                                                                             // Calculated duration, ignore during unmarshal
+	Compression           bool          `mapstructure:"compression"`                                                                   // If true, responses are gzip/deflate compressed for clients that accept it
+	CompressionMinBytes   int           `mapstructure:"compression_min_bytes" env:"SERVER_COMPRESSION_MIN_BYTES" validate:"min=0"`     // Smaller responses aren't worth compressing
+	TrustedProxies        []string      `mapstructure:"trusted_proxies"`                                                               // IPs and CIDR ranges of the reverse proxies whose X-Forwarded-For is believed; none by default
 }
 
 // DBConfig holds database specific configuration
 type DBConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Name     string `mapstructure:"name"`
+	Host                   string        `mapstructure:"host" env:"DB_HOST" validate:"required"`
+	Port                   int           `mapstructure:"port" env:"DB_PORT" validate:"min=1,max=65535"`
+	User                   string        `mapstructure:"user" env:"DB_USER" validate:"required"`
+	Password               string        `mapstructure:"password"`
+	Name                   string        `mapstructure:"name" env:"DB_NAME" validate:"required"`
+	MaxConns               int           `mapstructure:"max_conns" env:"DB_MAX_CONNS" validate:"min=0"`                                   // Upper bound on open connections; requests wait once it's reached
+	MinConns               int           `mapstructure:"min_conns" env:"DB_MIN_CONNS" validate:"min=0"`                                   // Connections kept open even when idle
+	MaxConnLifetimeMinutes int           `mapstructure:"max_conn_lifetime_minutes" env:"DB_MAX_CONN_LIFETIME_MINUTES" validate:"min=0"`   // Connections are recycled after this long
+	MaxConnLifetime        time.Duration `mapstructure:"-"`                                                                               // Calculated duration, ignore during unmarshal
+	MaxConnIdleTimeMinutes int           `mapstructure:"max_conn_idle_time_minutes" env:"DB_MAX_CONN_IDLE_TIME_MINUTES" validate:"min=0"` // Idle connections above MinConns are closed after this long
+	MaxConnIdleTime        time.Duration `mapstructure:"-"`                                                                               // Calculated duration, ignore during unmarshal
 }
 
 // CORSConfig holds CORS specific configuration
@@ -41,21 +81,221 @@ type CORSConfig struct {
 	AllowedOrigins []string `mapstructure:"allowed_origins"` // Slice of allowed origin strings
 }
 
+// defaultJWTSecret is the placeholder secret used when JWT_SECRET isn't set. It is public, so it must never sign real tokens.
+const defaultJWTSecret = "default-insecure-secret-key-change-me!"
+
 // JWTConfig holds JWT specific configuration
 type JWTConfig struct {
-	Secret           string        `mapstructure:"secret"`
-	ExpirationMinutes int           `mapstructure:"expiration_minutes"` // Store as int from config/env
-	Expiration       time.Duration `mapstructure:"-"`                  // Calculated duration, ignore during unmarshal
-	RefreshExpirationHours int           `mapstructure:"refresh_expiration"`
-	RefreshExpiration time.Duration         `mapstructure:"-"`
+	Secret                 string        `mapstructure:"secret" env:"JWT_SECRET" validate:"required,min=32"`
+	ExpirationMinutes      int           `mapstructure:"expiration_minutes" env:"JWT_EXPIRATION_MINUTES" validate:"min=1"` // Store as int from config/env
+	Expiration             time.Duration `mapstructure:"-"`                                                                // Calculated duration, ignore during unmarshal
+	RefreshExpirationHours int           `mapstructure:"refresh_expiration" env:"JWT_REFRESH_EXPIRATION" validate:"min=1"`
+	RefreshExpiration      time.Duration `mapstructure:"-"`
+	BlacklistFailOpen      bool          `mapstructure:"blacklist_fail_open"` // If true, tokens are accepted when the blacklist can't be checked
+	// Lifetime of the access tokens admins mint to act as another user; no longer than ExpirationMinutes
+	ImpersonationExpirationMinutes int           `mapstructure:"impersonation_expiration_minutes" env:"JWT_IMPERSONATION_EXPIRATION_MINUTES" validate:"min=1"`
+	ImpersonationExpiration        time.Duration `mapstructure:"-"`
+	// Keys maps key IDs (the kid header) to signing secrets. While a key is listed, tokens signed with it
+	// verify, so a new key can be made active before the old one is retired.
+	Keys        map[string]string `mapstructure:"keys"`
+	ActiveKeyID string            `mapstructure:"active_key_id" env:"JWT_ACTIVE_KEY_ID"` // Key new tokens are signed with; required when Keys is set
+}
+
+// SigningKey returns the ID and secret of the key new access tokens are signed with. Without Keys, the
+// ID is empty and tokens are signed with Secret and carry no kid header, as before rotation existed.
+func (c JWTConfig) SigningKey() (string, string) {
+	if len(c.Keys) == 0 {
+		return "", c.Secret
+	}
+	return c.ActiveKeyID, c.Keys[c.ActiveKeyID]
+}
+
+// VerificationKeys returns the secrets access tokens are accepted with, by key ID. The "" entry verifies
+// tokens without a kid header: it is Secret, unless Keys is set and Secret was left at its insecure
+// default, so unsetting JWT_SECRET retires the tokens minted before rotation.
+func (c JWTConfig) VerificationKeys() map[string]string {
+	keys := make(map[string]string, len(c.Keys)+1)
+	for kid, secret := range c.Keys {
+		keys[kid] = secret
+	}
+	if len(c.Keys) == 0 || c.Secret != defaultJWTSecret {
+		keys[""] = c.Secret
+	}
+	return keys
+}
+
+// LogConfig holds structured logging configuration
+type LogConfig struct {
+	Level  string `mapstructure:"level"`  // debug, info, warn or error
+	Format string `mapstructure:"format"` // json or text
+	Bodies     bool     `mapstructure:"bodies"`      // Log request/response bodies (redacted) on the access log; for debugging only
+	RedactKeys []string `mapstructure:"redact_keys"` // Extra JSON keys redacted from logged bodies; keys ending in token, password or secret, and key, always are
+}
+
+// MetricsConfig holds Prometheus metrics configuration
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	Port    int    `mapstructure:"port" env:"METRICS_PORT" validate:"min=0,max=65535"` // If set, metrics are served on this port instead of the API port
+}
+
+// RateLimitConfig holds limits for the authentication endpoints
+type RateLimitConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	LoginLimit      int           `mapstructure:"login_limit"`       // Login attempts per window per client IP
+	LoginEmailLimit int           `mapstructure:"login_email_limit"` // Login attempts per window per email
+	RegisterLimit   int           `mapstructure:"register_limit"`    // Registrations per window per client IP
+	WindowSeconds   int           `mapstructure:"window_seconds"`
+	Window          time.Duration `mapstructure:"-"` // Calculated duration, ignore during unmarshal
+}
+
+// IdempotencyConfig holds settings for Idempotency-Key handling on create endpoints
+type IdempotencyConfig struct {
+	TTLHours int           `mapstructure:"ttl_hours" env:"IDEMPOTENCY_TTL_HOURS" validate:"min=1"` // How long a stored response is replayed for
+	TTL      time.Duration `mapstructure:"-"`                                                      // Calculated duration, ignore during unmarshal
+}
+
+// OutboxConfig holds settings for the relay that publishes outbox events
+type OutboxConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	PollIntervalSeconds int           `mapstructure:"poll_interval_seconds"`
+	PollInterval        time.Duration `mapstructure:"-"` // Calculated duration, ignore during unmarshal
+	BatchSize           int           `mapstructure:"batch_size"` // Max events published per poll
+}
+
+// ArchiverConfig holds settings for the background sweep that archives old completed jobs
+type ArchiverConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	IntervalMinutes int           `mapstructure:"interval_minutes"`
+	Interval        time.Duration `mapstructure:"-"`                                                     // Calculated duration, ignore during unmarshal
+	AfterDays       int           `mapstructure:"after_days" env:"ARCHIVER_AFTER_DAYS" validate:"min=1"` // Complete jobs untouched for this long are archived
+	After           time.Duration `mapstructure:"-"`                                                     // Calculated duration, ignore during unmarshal
+	BatchSize       int           `mapstructure:"batch_size"`                                            // Max jobs archived per sweep
+}
+
+// AutoInvoiceConfig holds settings for the background sweep that creates due automatic invoices
+type AutoInvoiceConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	IntervalMinutes int           `mapstructure:"interval_minutes"`
+	Interval        time.Duration `mapstructure:"-"`          // Calculated duration, ignore during unmarshal
+	BatchSize       int           `mapstructure:"batch_size"` // Max jobs invoiced per sweep
+}
+
+// RetentionConfig holds settings for the background purge that permanently deletes long-archived jobs
+type RetentionConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	IntervalMinutes int           `mapstructure:"interval_minutes"`
+	Interval        time.Duration `mapstructure:"-"`                                                            // Calculated duration, ignore during unmarshal
+	ArchivedDays    int           `mapstructure:"archived_days" env:"RETENTION_ARCHIVED_DAYS" validate:"min=1"` // Archived jobs untouched for this long are deleted with their paid invoices
+	Archived        time.Duration `mapstructure:"-"`                                                            // Calculated duration, ignore during unmarshal
+	BatchSize       int           `mapstructure:"batch_size"`                                                   // Max jobs purged per sweep
+}
+
+// SchedulerConfig holds the Redis lock that keeps the background sweeps to one instance at a time
+type SchedulerConfig struct {
+	LockTTLSeconds int           `mapstructure:"lock_ttl_seconds" env:"SCHEDULER_LOCK_TTL_SECONDS" validate:"min=1"` // A crashed instance's lock is free again after this long; renewed while a sweep runs
+	LockTTL        time.Duration `mapstructure:"-"`                                                                  // Calculated duration, ignore during unmarshal
+}
+
+// WebhookConfig holds settings for the worker that delivers webhook calls queued by the outbox relay
+type WebhookConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	PollIntervalSeconds int           `mapstructure:"poll_interval_seconds"`
+	PollInterval        time.Duration `mapstructure:"-"`                                                              // Calculated duration, ignore during unmarshal
+	BatchSize           int           `mapstructure:"batch_size"`                                                     // Max deliveries attempted per poll
+	MaxAttempts         int           `mapstructure:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" validate:"min=1"`       // A delivery is marked failed after this many attempts
+	TimeoutSeconds      int           `mapstructure:"timeout_seconds" env:"WEBHOOK_TIMEOUT_SECONDS" validate:"min=1"` // How long an endpoint gets to answer
+	Timeout             time.Duration `mapstructure:"-"`                                                              // Calculated duration, ignore during unmarshal
+}
+
+// MailerConfig holds the SMTP server transactional emails are sent through. Emails are only logged unless a host is set.
+type MailerConfig struct {
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"` // Optional; sent with PLAIN auth, which needs TLS unless the host is local
+	SMTPPassword string `mapstructure:"smtp_password"`
+	From         string `mapstructure:"from"` // Sender address, e.g. "Jobs <no-reply@example.com>"
+}
+
+// FlagsConfig holds the state of feature flags; a flag missing from States is off
+type FlagsConfig struct {
+	States map[string]bool `mapstructure:"states"`
+	Redis  bool            `mapstructure:"redis"` // Let flag:<name> keys in Redis override States at runtime
+}
+
+// CacheConfig selects the repositories whose GetByID reads go through Redis
+type CacheConfig struct {
+	Users      bool          `mapstructure:"users"`
+	Jobs       bool          `mapstructure:"jobs"`
+	TTLSeconds int           `mapstructure:"ttl_seconds"` // Upper bound on how long a missed invalidation can serve stale data
+	TTL        time.Duration `mapstructure:"-"`           // Calculated duration, ignore during unmarshal
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration. Tracing is off unless an endpoint is set.
+type TracingConfig struct {
+	OTLPEndpoint string  `mapstructure:"otlp_endpoint"` // OTLP/HTTP collector URL, e.g. http://otel-collector:4318
+	ServiceName  string  `mapstructure:"service_name"`
+	SampleRatio  float64 `mapstructure:"sample_ratio" env:"TRACING_SAMPLE_RATIO" validate:"min=0,max=1"` // Fraction of new traces recorded; requests joining a trace follow its decision
+}
+
+// InvoiceConfig holds invoicing terms
+type InvoiceConfig struct {
+	PaymentTermDays      int     `mapstructure:"payment_term_days" env:"INVOICE_PAYMENT_TERM_DAYS" validate:"min=0"`           // Net-N: invoices are due this many days after creation
+	MaxAdjustmentPercent float64 `mapstructure:"max_adjustment_percent" env:"INVOICE_MAX_ADJUSTMENT_PERCENT" validate:"min=0"` // An invoice adjustment may change the base value by at most this percentage
+}
+
+// CurrencyConfig holds the currency of jobs posted without one; their invoices inherit the job's
+type CurrencyConfig struct {
+	Default string `mapstructure:"default" env:"CURRENCY_DEFAULT"` // ISO 4217 code, e.g. USD; checked against pkg/money
+}
+
+// ContractorConfig holds limits on what a contractor may take on
+type ContractorConfig struct {
+	MaxOngoingJobs int `mapstructure:"max_ongoing_jobs" env:"CONTRACTOR_MAX_ONGOING_JOBS" validate:"min=0"` // Ongoing jobs a contractor may hold at once; 0 means no limit. users.max_ongoing_jobs overrides it per user
+}
+
+// AvatarConfig holds where uploaded profile pictures are stored and how large they may be
+type AvatarConfig struct {
+	Dir      string `mapstructure:"dir" env:"AVATAR_DIR" validate:"required"`               // Directory the local store writes avatars to
+	BaseURL  string `mapstructure:"base_url" env:"AVATAR_BASE_URL" validate:"startswith=/"` // URL prefix the directory is served under; saved avatar URLs start with it
+	MaxBytes int64  `mapstructure:"max_bytes" env:"AVATAR_MAX_BYTES" validate:"min=1"`      // Larger uploads get 413; SERVER_MAX_BODY_BYTES still applies to the whole request
+}
+
+// PasswordConfig holds the password strength policy applied on registration and password reset
+type PasswordConfig struct {
+	MinLength         int    `mapstructure:"min_length" env:"PASSWORD_MIN_LENGTH" validate:"min=1"`
+	RequireUpper      bool   `mapstructure:"require_upper"`
+	RequireLower      bool   `mapstructure:"require_lower"`
+	RequireDigit      bool   `mapstructure:"require_digit"`
+	RequireSymbol     bool   `mapstructure:"require_symbol"`
+	BcryptCost        int    `mapstructure:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" validate:"min=4,max=31"` // Hashes below this cost are upgraded on the next login
+	Algorithm         string `mapstructure:"algorithm"`                                                      // bcrypt or argon2id, for new hashes; hashes of the other are upgraded on the next login
+	Argon2MemoryKiB   int    `mapstructure:"argon2_memory_kib" env:"PASSWORD_ARGON2_MEMORY_KIB" validate:"min=8"`
+	Argon2Iterations  int    `mapstructure:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS" validate:"min=1"`
+	Argon2Parallelism int    `mapstructure:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM" validate:"min=1,max=255"`
+}
+
+// AuthConfig holds account/authentication policy configuration
+type AuthConfig struct {
+	RequireVerifiedEmail   bool          `mapstructure:"require_verified_email"`                                          // If true, unverified users can't log in
+	AdminEmail             string        `mapstructure:"admin_email"`                                                     // Registered user promoted to admin on startup
+	LockoutThreshold       int           `mapstructure:"lockout_threshold" env:"AUTH_LOCKOUT_THRESHOLD" validate:"min=0"` // Consecutive failed logins before an email is locked; 0 disables lockout
+	LockoutCooldownSeconds int           `mapstructure:"lockout_cooldown_seconds"`                                        // How long a locked email stays locked
+	LockoutCooldown        time.Duration `mapstructure:"-"`                                                               // Calculated duration, ignore during unmarshal
+	IdempotentRegistration bool          `mapstructure:"idempotent_registration"`                                         // If true, a repeated registration with the right password returns the existing user
 }
 
 // BlockchainConfig holds blockchain interaction configuration
 type BlockchainConfig struct {
-	RPCURL          string `mapstructure:"rpc_url"`
-	ContractAddress string `mapstructure:"contract_address"`
-	ContractABIPath string `mapstructure:"contract_abi_path"`
-	Expiration       time.Duration `mapstructure:"-"`                  // Calculated duration, ignore during unmarshal
+	RPCURL               string        `mapstructure:"rpc_url"`
+	ContractAddress      string        `mapstructure:"contract_address"`
+	ContractABIPath      string        `mapstructure:"contract_abi_path"`
+	StartBlock           uint64        `mapstructure:"start_block"`                                                                   // Block to replay events from when no processed block is stored yet; 0 starts at the head
+	ReconnectMaxRetries  int           `mapstructure:"reconnect_max_retries" env:"BLOCKCHAIN_RECONNECT_MAX_RETRIES" validate:"min=0"` // Consecutive failed reconnects before the listener gives up; 0 retries forever
+	Expiration           time.Duration `mapstructure:"-"`                                                                             // Calculated duration, ignore during unmarshal
+	HealthRequired       bool          `mapstructure:"health_required"`                                                               // If true, a failing RPC fails readiness instead of only degrading it
+	HealthTimeoutSeconds int           `mapstructure:"health_timeout_seconds" env:"BLOCKCHAIN_HEALTH_TIMEOUT_SECONDS" validate:"min=1"`
+	HealthTimeout        time.Duration `mapstructure:"-"` // Calculated duration, ignore during unmarshal
 }
 
 // RedisConfig holds Redis connection details.
@@ -77,22 +317,100 @@ func Load() (*Config, error) {
 	// --- Set Default Values ---
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.host", "localhost")
+	viper.SetDefault("server.max_body_bytes", 1<<20) // 1 MiB
+	viper.SetDefault("server.compression", true)
+	viper.SetDefault("server.compression_min_bytes", 1024)
+	viper.SetDefault("server.request_timeout_seconds", 30)
+	viper.SetDefault("server.max_batch_get_users", 100)
+	viper.SetDefault("server.max_page_size", 100)
+	viper.SetDefault("server.shutdown_drain_seconds", 20) // Below the usual 30s termination grace period
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.user", "postgres")
 	viper.SetDefault("database.password", "postgres")
 	viper.SetDefault("database.name", "api_db")
+	viper.SetDefault("database.max_conns", 10)
+	viper.SetDefault("database.min_conns", 2)
+	viper.SetDefault("database.max_conn_lifetime_minutes", 60)
+	viper.SetDefault("database.max_conn_idle_time_minutes", 30)
 	viper.SetDefault("redis.addr", "localhost:6379")
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
-	viper.SetDefault("jwt.secret", "default-insecure-secret-key-change-me!")
+	viper.SetDefault("jwt.secret", defaultJWTSecret)
 	viper.SetDefault("jwt.expiration_minutes", 60)
 	viper.SetDefault("jwt.refresh_expiration", "24")
+	viper.SetDefault("jwt.blacklist_fail_open", true)
+	viper.SetDefault("jwt.impersonation_expiration_minutes", 15)
+	viper.SetDefault("auth.require_verified_email", false)
+	viper.SetDefault("auth.idempotent_registration", false)
+	viper.SetDefault("auth.lockout_threshold", 10)
+	viper.SetDefault("auth.lockout_cooldown_seconds", 900)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "json")
+	viper.SetDefault("log.bodies", false)
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.port", 0)
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.login_limit", 20)
+	viper.SetDefault("rate_limit.login_email_limit", 5)
+	viper.SetDefault("rate_limit.register_limit", 5)
+	viper.SetDefault("rate_limit.window_seconds", 60)
+	viper.SetDefault("idempotency.ttl_hours", 24)
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("outbox.enabled", true)
+	viper.SetDefault("outbox.poll_interval_seconds", 5)
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("invoice.payment_term_days", 30)
+	viper.SetDefault("invoice.max_adjustment_percent", 50.0)
+	viper.SetDefault("currency.default", "USD")
+	viper.SetDefault("contractor.max_ongoing_jobs", 10)
+	viper.SetDefault("avatar.dir", "uploads/avatars")
+	viper.SetDefault("avatar.base_url", "/uploads/avatars")
+	viper.SetDefault("avatar.max_bytes", 512<<10) // 512 KiB
+	viper.SetDefault("archiver.enabled", true)
+	viper.SetDefault("archiver.interval_minutes", 60)
+	viper.SetDefault("archiver.after_days", 30)
+	viper.SetDefault("archiver.batch_size", 100)
+	viper.SetDefault("auto_invoice.enabled", true)
+	viper.SetDefault("auto_invoice.interval_minutes", 15)
+	viper.SetDefault("auto_invoice.batch_size", 100)
+	viper.SetDefault("retention.enabled", false) // Deletes data for good, so it has to be asked for
+	viper.SetDefault("retention.interval_minutes", 1440)
+	viper.SetDefault("retention.archived_days", 365)
+	viper.SetDefault("retention.batch_size", 100)
+	viper.SetDefault("scheduler.lock_ttl_seconds", 30)
+	viper.SetDefault("webhook.enabled", true)
+	viper.SetDefault("webhook.poll_interval_seconds", 5)
+	viper.SetDefault("webhook.batch_size", 50)
+	viper.SetDefault("webhook.max_attempts", 8)
+	viper.SetDefault("webhook.timeout_seconds", 10)
+	viper.SetDefault("mailer.smtp_port", 587)
+	viper.SetDefault("flags.states", map[string]bool{"ratings": true})
+	viper.SetDefault("flags.redis", false)
+	viper.SetDefault("cache.users", false)
+	viper.SetDefault("cache.jobs", false)
+	viper.SetDefault("cache.ttl_seconds", 300)
+	viper.SetDefault("tracing.service_name", "go-api-template")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("password.require_upper", true)
+	viper.SetDefault("password.require_lower", true)
+	viper.SetDefault("password.require_digit", true)
+	viper.SetDefault("password.require_symbol", false)
+	viper.SetDefault("password.bcrypt_cost", bcrypt.DefaultCost)
+	viper.SetDefault("password.algorithm", string(hasher.Bcrypt))
+	viper.SetDefault("password.argon2_memory_kib", hasher.DefaultArgon2idParams().MemoryKiB)
+	viper.SetDefault("password.argon2_iterations", hasher.DefaultArgon2idParams().Iterations)
+	viper.SetDefault("password.argon2_parallelism", hasher.DefaultArgon2idParams().Parallelism)
 
 	// Defaults for Blockchain Listener 
 	viper.SetDefault("blockchain.rpc_url", "wss://ethereum-sepolia-rpc.publicnode.com") 
 	viper.SetDefault("blockchain.contract_address", "0x694AA1769357215DE4FAC081bf1f309aDC325306") // (Sepolia ETH/USD on Chainlink aggregator)
 	viper.SetDefault("blockchain.contract_abi_path", "config/abi/AggregatorV3Interface.abi.json") // Random price aggregator for example
+	viper.SetDefault("blockchain.health_required", false)
+	viper.SetDefault("blockchain.health_timeout_seconds", 2)
+	viper.SetDefault("blockchain.start_block", 0)
+	viper.SetDefault("blockchain.reconnect_max_retries", 10)
 
 	// Default CORS: Allow common local dev origins and maybe wildcard for simple setup
 	// For production, this SHOULD be overridden by environment variables.
@@ -112,12 +430,96 @@ func Load() (*Config, error) {
 	viper.AutomaticEnv()
 	// Allow environment variable CORS_ALLOWED_ORIGINS to override (comma-separated string)
 	viper.BindEnv("cors.allowed_origins", "CORS_ALLOWED_ORIGINS")
+	viper.BindEnv("server.max_body_bytes", "SERVER_MAX_BODY_BYTES")
+	viper.BindEnv("server.compression", "SERVER_COMPRESSION")
+	viper.BindEnv("server.compression_min_bytes", "SERVER_COMPRESSION_MIN_BYTES")
+	viper.BindEnv("server.request_timeout_seconds", "SERVER_REQUEST_TIMEOUT_SECONDS")
+	viper.BindEnv("server.max_batch_get_users", "SERVER_MAX_BATCH_GET_USERS")
+	viper.BindEnv("server.max_page_size", "SERVER_MAX_PAGE_SIZE")
+	viper.BindEnv("server.shutdown_drain_seconds", "SERVER_SHUTDOWN_DRAIN_SECONDS")
 	viper.BindEnv("jwt.secret", "JWT_SECRET")
 	viper.BindEnv("jwt.expiration_minutes", "JWT_EXPIRATION_MINUTES")
 	viper.BindEnv("jwt.refresh_expiration", "JWT_REFRESH_EXPIRATION")
+	viper.BindEnv("jwt.blacklist_fail_open", "JWT_BLACKLIST_FAIL_OPEN")
+	viper.BindEnv("jwt.impersonation_expiration_minutes", "JWT_IMPERSONATION_EXPIRATION_MINUTES")
+	viper.BindEnv("jwt.active_key_id", "JWT_ACTIVE_KEY_ID")
+	viper.BindEnv("auth.require_verified_email", "AUTH_REQUIRE_VERIFIED_EMAIL")
+	viper.BindEnv("auth.idempotent_registration", "AUTH_IDEMPOTENT_REGISTRATION")
+	viper.BindEnv("auth.admin_email", "AUTH_ADMIN_EMAIL")
+	viper.BindEnv("auth.lockout_threshold", "AUTH_LOCKOUT_THRESHOLD")
+	viper.BindEnv("auth.lockout_cooldown_seconds", "AUTH_LOCKOUT_COOLDOWN_SECONDS")
+	viper.BindEnv("log.level", "LOG_LEVEL")
+	viper.BindEnv("log.format", "LOG_FORMAT")
+	viper.BindEnv("log.bodies", "LOG_BODIES")
+	viper.BindEnv("metrics.enabled", "METRICS_ENABLED")
+	viper.BindEnv("metrics.path", "METRICS_PATH")
+	viper.BindEnv("metrics.port", "METRICS_PORT")
+	viper.BindEnv("rate_limit.enabled", "RATE_LIMIT_ENABLED")
+	viper.BindEnv("rate_limit.login_limit", "RATE_LIMIT_LOGIN")
+	viper.BindEnv("rate_limit.login_email_limit", "RATE_LIMIT_LOGIN_EMAIL")
+	viper.BindEnv("rate_limit.register_limit", "RATE_LIMIT_REGISTER")
+	viper.BindEnv("rate_limit.window_seconds", "RATE_LIMIT_WINDOW_SECONDS")
+	viper.BindEnv("idempotency.ttl_hours", "IDEMPOTENCY_TTL_HOURS")
+	viper.BindEnv("password.min_length", "PASSWORD_MIN_LENGTH")
+	viper.BindEnv("outbox.enabled", "OUTBOX_ENABLED")
+	viper.BindEnv("outbox.poll_interval_seconds", "OUTBOX_POLL_INTERVAL_SECONDS")
+	viper.BindEnv("outbox.batch_size", "OUTBOX_BATCH_SIZE")
+	viper.BindEnv("invoice.payment_term_days", "INVOICE_PAYMENT_TERM_DAYS")
+	viper.BindEnv("invoice.max_adjustment_percent", "INVOICE_MAX_ADJUSTMENT_PERCENT")
+	viper.BindEnv("currency.default", "CURRENCY_DEFAULT")
+	viper.BindEnv("contractor.max_ongoing_jobs", "CONTRACTOR_MAX_ONGOING_JOBS")
+	viper.BindEnv("avatar.dir", "AVATAR_DIR")
+	viper.BindEnv("avatar.base_url", "AVATAR_BASE_URL")
+	viper.BindEnv("avatar.max_bytes", "AVATAR_MAX_BYTES")
+	viper.BindEnv("archiver.enabled", "ARCHIVER_ENABLED")
+	viper.BindEnv("archiver.interval_minutes", "ARCHIVER_INTERVAL_MINUTES")
+	viper.BindEnv("archiver.after_days", "ARCHIVER_AFTER_DAYS")
+	viper.BindEnv("archiver.batch_size", "ARCHIVER_BATCH_SIZE")
+	viper.BindEnv("auto_invoice.enabled", "AUTO_INVOICE_ENABLED")
+	viper.BindEnv("auto_invoice.interval_minutes", "AUTO_INVOICE_INTERVAL_MINUTES")
+	viper.BindEnv("auto_invoice.batch_size", "AUTO_INVOICE_BATCH_SIZE")
+	viper.BindEnv("retention.enabled", "RETENTION_ENABLED")
+	viper.BindEnv("retention.interval_minutes", "RETENTION_INTERVAL_MINUTES")
+	viper.BindEnv("retention.archived_days", "RETENTION_ARCHIVED_DAYS")
+	viper.BindEnv("retention.batch_size", "RETENTION_BATCH_SIZE")
+	viper.BindEnv("scheduler.lock_ttl_seconds", "SCHEDULER_LOCK_TTL_SECONDS")
+	viper.BindEnv("webhook.enabled", "WEBHOOK_ENABLED")
+	viper.BindEnv("webhook.poll_interval_seconds", "WEBHOOK_POLL_INTERVAL_SECONDS")
+	viper.BindEnv("webhook.batch_size", "WEBHOOK_BATCH_SIZE")
+	viper.BindEnv("webhook.max_attempts", "WEBHOOK_MAX_ATTEMPTS")
+	viper.BindEnv("webhook.timeout_seconds", "WEBHOOK_TIMEOUT_SECONDS")
+	viper.BindEnv("mailer.smtp_host", "SMTP_HOST")
+	viper.BindEnv("mailer.smtp_port", "SMTP_PORT")
+	viper.BindEnv("mailer.smtp_username", "SMTP_USERNAME")
+	viper.BindEnv("mailer.smtp_password", "SMTP_PASSWORD")
+	viper.BindEnv("mailer.from", "MAIL_FROM")
+	viper.BindEnv("flags.redis", "FEATURE_FLAGS_REDIS")
+	viper.BindEnv("cache.users", "CACHE_USERS")
+	viper.BindEnv("cache.jobs", "CACHE_JOBS")
+	viper.BindEnv("cache.ttl_seconds", "CACHE_TTL_SECONDS")
+	viper.BindEnv("tracing.otlp_endpoint", "TRACING_OTLP_ENDPOINT")
+	viper.BindEnv("tracing.service_name", "TRACING_SERVICE_NAME")
+	viper.BindEnv("tracing.sample_ratio", "TRACING_SAMPLE_RATIO")
+	viper.BindEnv("database.max_conns", "DB_MAX_CONNS")
+	viper.BindEnv("database.min_conns", "DB_MIN_CONNS")
+	viper.BindEnv("database.max_conn_lifetime_minutes", "DB_MAX_CONN_LIFETIME_MINUTES")
+	viper.BindEnv("database.max_conn_idle_time_minutes", "DB_MAX_CONN_IDLE_TIME_MINUTES")
+	viper.BindEnv("password.require_upper", "PASSWORD_REQUIRE_UPPER")
+	viper.BindEnv("password.require_lower", "PASSWORD_REQUIRE_LOWER")
+	viper.BindEnv("password.require_digit", "PASSWORD_REQUIRE_DIGIT")
+	viper.BindEnv("password.require_symbol", "PASSWORD_REQUIRE_SYMBOL")
+	viper.BindEnv("password.bcrypt_cost", "PASSWORD_BCRYPT_COST")
+	viper.BindEnv("password.algorithm", "PASSWORD_ALGORITHM")
+	viper.BindEnv("password.argon2_memory_kib", "PASSWORD_ARGON2_MEMORY_KIB")
+	viper.BindEnv("password.argon2_iterations", "PASSWORD_ARGON2_ITERATIONS")
+	viper.BindEnv("password.argon2_parallelism", "PASSWORD_ARGON2_PARALLELISM")
 	viper.BindEnv("blockchain.rpc_url", "BLOCKCHAIN_RPC_URL")
 	viper.BindEnv("blockchain.contract_address", "CONTRACT_ADDRESS")
 	viper.BindEnv("blockchain.contract_abi_path", "CONTRACT_ABI_PATH")
+	viper.BindEnv("blockchain.health_required", "BLOCKCHAIN_HEALTH_REQUIRED")
+	viper.BindEnv("blockchain.health_timeout_seconds", "BLOCKCHAIN_HEALTH_TIMEOUT_SECONDS")
+	viper.BindEnv("blockchain.start_block", "BLOCKCHAIN_START_BLOCK")
+	viper.BindEnv("blockchain.reconnect_max_retries", "BLOCKCHAIN_RECONNECT_MAX_RETRIES")
 
 	// --- Unmarshal Config ---
 	var cfg Config
@@ -163,6 +565,37 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// Handle SERVER_TRUSTED_PROXIES env var (comma-separated IPs and CIDR ranges -> slice)
+	if proxiesStr := os.Getenv("SERVER_TRUSTED_PROXIES"); proxiesStr != "" {
+		cfg.Server.TrustedProxies = strings.Split(proxiesStr, ",")
+		for i, proxy := range cfg.Server.TrustedProxies {
+			cfg.Server.TrustedProxies[i] = strings.TrimSpace(proxy)
+		}
+	}
+
+	// Handle LOG_REDACT_KEYS env var (comma-separated string -> slice)
+	if keysStr := os.Getenv("LOG_REDACT_KEYS"); keysStr != "" {
+		cfg.Log.RedactKeys = strings.Split(keysStr, ",")
+		for i, key := range cfg.Log.RedactKeys {
+			cfg.Log.RedactKeys[i] = strings.TrimSpace(key)
+		}
+	}
+
+	// Handle FEATURE_FLAGS env var (comma-separated name=bool pairs, applied over the configured states)
+	if flagsStr := os.Getenv("FEATURE_FLAGS"); flagsStr != "" {
+		if cfg.Flags.States == nil {
+			cfg.Flags.States = map[string]bool{}
+		}
+		for _, pair := range strings.Split(flagsStr, ",") {
+			name, value, _ := strings.Cut(pair, "=")
+			enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("FEATURE_FLAGS entry %q must be name=true or name=false", pair)
+			}
+			cfg.Flags.States[strings.TrimSpace(name)] = enabled
+		}
+	}
+
 	// JWT Overrides
 	if secret := os.Getenv("JWT_SECRET"); secret != "" {
 		cfg.JWT.Secret = secret
@@ -177,6 +610,54 @@ func Load() (*Config, error) {
 			cfg.JWT.RefreshExpirationHours = rfrExp
 		}
 	}
+	if failOpenStr := os.Getenv("JWT_BLACKLIST_FAIL_OPEN"); failOpenStr != "" {
+		if failOpen, err := strconv.ParseBool(failOpenStr); err == nil {
+			cfg.JWT.BlacklistFailOpen = failOpen
+		}
+	}
+	// Handle JWT_KEYS env var (comma-separated kid=secret pairs, replacing the configured keys)
+	if keysStr := os.Getenv("JWT_KEYS"); keysStr != "" {
+		cfg.JWT.Keys = map[string]string{}
+		for _, pair := range strings.Split(keysStr, ",") {
+			kid, secret, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(kid) == "" {
+				return nil, fmt.Errorf("JWT_KEYS entries must be kid=secret pairs")
+			}
+			cfg.JWT.Keys[strings.TrimSpace(kid)] = strings.TrimSpace(secret)
+		}
+	}
+	if activeKeyID := os.Getenv("JWT_ACTIVE_KEY_ID"); activeKeyID != "" {
+		cfg.JWT.ActiveKeyID = activeKeyID
+	}
+
+	// Auth Overrides
+	if requireVerifiedStr := os.Getenv("AUTH_REQUIRE_VERIFIED_EMAIL"); requireVerifiedStr != "" {
+		if requireVerified, err := strconv.ParseBool(requireVerifiedStr); err == nil {
+			cfg.Auth.RequireVerifiedEmail = requireVerified
+		}
+	}
+	if adminEmail := os.Getenv("AUTH_ADMIN_EMAIL"); adminEmail != "" {
+		cfg.Auth.AdminEmail = adminEmail
+	}
+
+	// Metrics Overrides
+	if enabledStr := os.Getenv("METRICS_ENABLED"); enabledStr != "" {
+		if enabled, err := strconv.ParseBool(enabledStr); err == nil {
+			cfg.Metrics.Enabled = enabled
+		}
+	}
+	if portStr := os.Getenv("METRICS_PORT"); portStr != "" {
+		if port, err := strconv.Atoi(portStr); err == nil {
+			cfg.Metrics.Port = port
+		}
+	}
+
+	// Rate Limit Overrides
+	if enabledStr := os.Getenv("RATE_LIMIT_ENABLED"); enabledStr != "" {
+		if enabled, err := strconv.ParseBool(enabledStr); err == nil {
+			cfg.RateLimit.Enabled = enabled
+		}
+	}
 
 	// Blockchain Overrides
 	if rpcURL := os.Getenv("BLOCKCHAIN_RPC_URL"); rpcURL != "" {
@@ -203,15 +684,35 @@ func Load() (*Config, error) {
 	}
 
 	// --- Calculate derived values ---
+	cfg.Server.RequestTimeout = time.Duration(cfg.Server.RequestTimeoutSeconds) * time.Second
+	cfg.Server.ShutdownDrain = time.Duration(cfg.Server.ShutdownDrainSeconds) * time.Second
 	cfg.JWT.Expiration = time.Duration(cfg.JWT.ExpirationMinutes) * time.Minute
 	cfg.JWT.RefreshExpiration = time.Duration(cfg.JWT.RefreshExpirationHours) * time.Hour
+	cfg.JWT.ImpersonationExpiration = time.Duration(cfg.JWT.ImpersonationExpirationMinutes) * time.Minute
+	cfg.Blockchain.HealthTimeout = time.Duration(cfg.Blockchain.HealthTimeoutSeconds) * time.Second
+	cfg.RateLimit.Window = time.Duration(cfg.RateLimit.WindowSeconds) * time.Second
+	cfg.Auth.LockoutCooldown = time.Duration(cfg.Auth.LockoutCooldownSeconds) * time.Second
+	cfg.Idempotency.TTL = time.Duration(cfg.Idempotency.TTLHours) * time.Hour
+	cfg.Outbox.PollInterval = time.Duration(cfg.Outbox.PollIntervalSeconds) * time.Second
+	cfg.Archiver.Interval = time.Duration(cfg.Archiver.IntervalMinutes) * time.Minute
+	cfg.Archiver.After = time.Duration(cfg.Archiver.AfterDays) * 24 * time.Hour
+	cfg.AutoInvoice.Interval = time.Duration(cfg.AutoInvoice.IntervalMinutes) * time.Minute
+	cfg.Retention.Interval = time.Duration(cfg.Retention.IntervalMinutes) * time.Minute
+	cfg.Retention.Archived = time.Duration(cfg.Retention.ArchivedDays) * 24 * time.Hour
+	cfg.Scheduler.LockTTL = time.Duration(cfg.Scheduler.LockTTLSeconds) * time.Second
+	cfg.Webhook.PollInterval = time.Duration(cfg.Webhook.PollIntervalSeconds) * time.Second
+	cfg.Webhook.Timeout = time.Duration(cfg.Webhook.TimeoutSeconds) * time.Second
+	cfg.Cache.TTL = time.Duration(cfg.Cache.TTLSeconds) * time.Second
+	cfg.DB.MaxConnLifetime = time.Duration(cfg.DB.MaxConnLifetimeMinutes) * time.Minute
+	cfg.DB.MaxConnIdleTime = time.Duration(cfg.DB.MaxConnIdleTimeMinutes) * time.Minute
+	cfg.Currency.Default = strings.ToUpper(strings.TrimSpace(cfg.Currency.Default))
 
 	// --- Final Validation ---
-	if cfg.JWT.Secret == "default-insecure-secret-key-change-me!" {
+	if cfg.JWT.Secret == defaultJWTSecret && len(cfg.JWT.Keys) == 0 {
 		log.Println("WARNING: Using default insecure JWT secret. Set JWT_SECRET environment variable.")
 	}
-	if cfg.JWT.Secret == "" {
-		log.Fatal("FATAL: JWT_SECRET cannot be empty.") // Or return an error
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	log.Printf("Configuration loaded: Server Port=%d, DB Host=%s, Allowed Origins=%v",
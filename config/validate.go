@@ -0,0 +1,153 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go-api-template/pkg/hasher"
+	"go-api-template/pkg/money"
+
+	"github.com/go-playground/validator"
+)
+
+// ValidationError lists every problem found in a configuration, so a misconfigured deployment can be
+// fixed in one go instead of one restart per mistake.
+type ValidationError struct {
+	Problems []string // One sentence per problem, naming the environment variable to change
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks cfg for values the application can't run with. Single-field rules live in the
+// validate tags of the config structs, whose env tags name the variable reported for a bad value;
+// rules spanning several fields are checked below. The returned error is a *ValidationError.
+func (cfg *Config) Validate() error {
+	var problems []string
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("env") // Fields without one are reported by their Go name
+	})
+	if err := validate.Struct(cfg); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if !errors.As(err, &fieldErrs) {
+			return fmt.Errorf("validating configuration: %w", err)
+		}
+		for _, fe := range fieldErrs {
+			problems = append(problems, describeFieldError(fe))
+		}
+	}
+
+	problems = append(problems, validateJWTKeys(cfg.JWT)...)
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("SERVER_TRUSTED_PROXIES entry %q is not an IP address or CIDR range", proxy))
+			}
+		}
+	}
+	if cfg.JWT.ImpersonationExpirationMinutes > cfg.JWT.ExpirationMinutes {
+		problems = append(problems, fmt.Sprintf("JWT_IMPERSONATION_EXPIRATION_MINUTES (%d) must not exceed JWT_EXPIRATION_MINUTES (%d)", cfg.JWT.ImpersonationExpirationMinutes, cfg.JWT.ExpirationMinutes))
+	}
+	if cfg.DB.MaxConns > 0 && cfg.DB.MinConns > cfg.DB.MaxConns {
+		problems = append(problems, fmt.Sprintf("DB_MIN_CONNS (%d) must not exceed DB_MAX_CONNS (%d)", cfg.DB.MinConns, cfg.DB.MaxConns))
+	}
+	if cfg.RateLimit.Enabled && (cfg.RateLimit.LoginLimit <= 0 || cfg.RateLimit.LoginEmailLimit <= 0 || cfg.RateLimit.RegisterLimit <= 0 || cfg.RateLimit.WindowSeconds <= 0) {
+		problems = append(problems, "RATE_LIMIT_LOGIN, RATE_LIMIT_LOGIN_EMAIL, RATE_LIMIT_REGISTER and RATE_LIMIT_WINDOW_SECONDS must be positive when rate limiting is enabled")
+	}
+	if cfg.Auth.LockoutThreshold > 0 && cfg.Auth.LockoutCooldownSeconds <= 0 {
+		problems = append(problems, "AUTH_LOCKOUT_COOLDOWN_SECONDS must be positive when AUTH_LOCKOUT_THRESHOLD is set")
+	}
+	if cfg.Outbox.Enabled && (cfg.Outbox.PollIntervalSeconds <= 0 || cfg.Outbox.BatchSize <= 0) {
+		problems = append(problems, "OUTBOX_POLL_INTERVAL_SECONDS and OUTBOX_BATCH_SIZE must be positive when the outbox relay is enabled")
+	}
+	if cfg.Archiver.Enabled && (cfg.Archiver.IntervalMinutes <= 0 || cfg.Archiver.BatchSize <= 0) {
+		problems = append(problems, "ARCHIVER_INTERVAL_MINUTES and ARCHIVER_BATCH_SIZE must be positive when the archiver is enabled")
+	}
+	if cfg.AutoInvoice.Enabled && (cfg.AutoInvoice.IntervalMinutes <= 0 || cfg.AutoInvoice.BatchSize <= 0) {
+		problems = append(problems, "AUTO_INVOICE_INTERVAL_MINUTES and AUTO_INVOICE_BATCH_SIZE must be positive when the auto-invoicer is enabled")
+	}
+	if cfg.Retention.Enabled && (cfg.Retention.IntervalMinutes <= 0 || cfg.Retention.BatchSize <= 0) {
+		problems = append(problems, "RETENTION_INTERVAL_MINUTES and RETENTION_BATCH_SIZE must be positive when the retention purge is enabled")
+	}
+	if cfg.Webhook.Enabled && (cfg.Webhook.PollIntervalSeconds <= 0 || cfg.Webhook.BatchSize <= 0) {
+		problems = append(problems, "WEBHOOK_POLL_INTERVAL_SECONDS and WEBHOOK_BATCH_SIZE must be positive when webhook delivery is enabled")
+	}
+	if cfg.Mailer.SMTPHost != "" && (cfg.Mailer.From == "" || cfg.Mailer.SMTPPort <= 0) {
+		problems = append(problems, "MAIL_FROM and a positive SMTP_PORT are required when SMTP_HOST is set")
+	}
+	if _, err := hasher.ParseAlgorithm(cfg.Password.Algorithm); err != nil {
+		problems = append(problems, fmt.Sprintf("PASSWORD_ALGORITHM must be %q or %q, got %q", hasher.Bcrypt, hasher.Argon2id, cfg.Password.Algorithm))
+	}
+	if !money.IsValid(cfg.Currency.Default) {
+		problems = append(problems, fmt.Sprintf("CURRENCY_DEFAULT must be a supported ISO 4217 code such as USD or EUR, got %q", cfg.Currency.Default))
+	}
+	if (cfg.Cache.Users || cfg.Cache.Jobs) && cfg.Cache.TTLSeconds <= 0 {
+		problems = append(problems, "CACHE_TTL_SECONDS must be positive when caching is enabled")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateJWTKeys checks the rotation keys: each must be as long as JWT_SECRET has to be, and the active
+// one must be among them. Key IDs are named in the problems, secrets never.
+func validateJWTKeys(jwt JWTConfig) []string {
+	if len(jwt.Keys) == 0 {
+		if jwt.ActiveKeyID != "" {
+			return []string{"JWT_ACTIVE_KEY_ID requires JWT_KEYS"}
+		}
+		return nil
+	}
+
+	var problems []string
+	kids := make([]string, 0, len(jwt.Keys))
+	for kid := range jwt.Keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		if len(jwt.Keys[kid]) < 32 {
+			problems = append(problems, fmt.Sprintf("JWT_KEYS key %q must be at least 32 characters long", kid))
+		}
+	}
+	if jwt.ActiveKeyID == "" {
+		problems = append(problems, "JWT_ACTIVE_KEY_ID is required when JWT_KEYS is set")
+	} else if _, ok := jwt.Keys[jwt.ActiveKeyID]; !ok {
+		problems = append(problems, fmt.Sprintf("JWT_ACTIVE_KEY_ID %q is not one of the JWT_KEYS", jwt.ActiveKeyID))
+	}
+	return problems
+}
+
+// describeFieldError words a failed validate tag as a sentence. String values are never echoed, since
+// some of them are secrets.
+func describeFieldError(fe validator.FieldError) string {
+	got := ""
+	if fe.Kind() != reflect.String {
+		got = fmt.Sprintf(", got %v", fe.Value())
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters long", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s%s", fe.Field(), fe.Param(), got)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s%s", fe.Field(), fe.Param(), got)
+	case "startswith":
+		return fmt.Sprintf("%s must start with %q", fe.Field(), fe.Param())
+	default:
+		return fmt.Sprintf("%s is invalid (%s)%s", fe.Field(), fe.Tag(), got)
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadDefaults loads the configuration without any environment overrides set by the test.
+func loadDefaults(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load()
+	require.NoError(t, err, "the defaults must be valid")
+	return cfg
+}
+
+// problems returns the problems listed by err, which must be a *ValidationError.
+func problems(t *testing.T, err error) []string {
+	t.Helper()
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected a *ValidationError, got %v", err)
+	return validationErr.Problems
+}
+
+func TestValidate_JWTSecret(t *testing.T) {
+	cfg := loadDefaults(t)
+
+	cfg.JWT.Secret = ""
+	assert.Equal(t, []string{"JWT_SECRET is required"}, problems(t, cfg.Validate()))
+
+	cfg.JWT.Secret = "too-short-secret"
+	err := cfg.Validate()
+	assert.Equal(t, []string{"JWT_SECRET must be at least 32 characters long"}, problems(t, err))
+	assert.NotContains(t, err.Error(), "too-short-secret", "secrets are never echoed")
+}
+
+func TestValidate_JWTKeys(t *testing.T) {
+	cfg := loadDefaults(t)
+
+	cfg.JWT.ActiveKeyID = "2025-03"
+	assert.Equal(t, []string{"JWT_ACTIVE_KEY_ID requires JWT_KEYS"}, problems(t, cfg.Validate()))
+
+	cfg.JWT.Keys = map[string]string{"2025-02": "too-short-secret", "2025-03": "a-secret-that-is-long-enough-to-sign-with"}
+	err := cfg.Validate()
+	assert.Equal(t, []string{`JWT_KEYS key "2025-02" must be at least 32 characters long`}, problems(t, err))
+	assert.NotContains(t, err.Error(), "too-short-secret", "secrets are never echoed")
+
+	cfg.JWT.Keys["2025-02"] = "the-previous-secret-still-accepted-for-now"
+	cfg.JWT.ActiveKeyID = "2025-04"
+	assert.Equal(t, []string{`JWT_ACTIVE_KEY_ID "2025-04" is not one of the JWT_KEYS`}, problems(t, cfg.Validate()))
+
+	cfg.JWT.ActiveKeyID = "2025-03"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoad_JWTKeys(t *testing.T) {
+	t.Setenv("JWT_KEYS", "2025-02=the-previous-secret-still-accepted-for-now, 2025-03=a-secret-that-is-long-enough-to-sign-with")
+	t.Setenv("JWT_ACTIVE_KEY_ID", "2025-03")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	kid, secret := cfg.JWT.SigningKey()
+	assert.Equal(t, "2025-03", kid)
+	assert.Equal(t, "a-secret-that-is-long-enough-to-sign-with", secret)
+	assert.Equal(t, map[string]string{
+		"2025-02": "the-previous-secret-still-accepted-for-now",
+		"2025-03": "a-secret-that-is-long-enough-to-sign-with",
+	}, cfg.JWT.VerificationKeys(), "The default JWT_SECRET must not verify tokens without a kid")
+
+	t.Setenv("JWT_SECRET", "the-secret-used-before-key-rotation")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "the-secret-used-before-key-rotation", cfg.JWT.VerificationKeys()[""], "Tokens minted before rotation verify until JWT_SECRET is unset")
+}
+
+func TestLoad_InvalidDuration(t *testing.T) {
+	t.Setenv("SERVER_SHUTDOWN_DRAIN_SECONDS", "-5")
+	t.Setenv("IDEMPOTENCY_TTL_HOURS", "0")
+
+	cfg, err := Load()
+
+	assert.Nil(t, cfg)
+	assert.ElementsMatch(t, []string{
+		"SERVER_SHUTDOWN_DRAIN_SECONDS must be at least 0, got -5",
+		"IDEMPOTENCY_TTL_HOURS must be at least 1, got 0",
+	}, problems(t, err))
+}
+
+func TestValidate_ImpersonationExpiration(t *testing.T) {
+	cfg := loadDefaults(t)
+	assert.Less(t, cfg.JWT.ImpersonationExpiration, cfg.JWT.Expiration, "impersonation tokens are shorter-lived by default")
+
+	cfg.JWT.ImpersonationExpirationMinutes = cfg.JWT.ExpirationMinutes
+	assert.NoError(t, cfg.Validate())
+
+	cfg.JWT.ImpersonationExpirationMinutes = cfg.JWT.ExpirationMinutes + 1
+	assert.Equal(t, []string{"JWT_IMPERSONATION_EXPIRATION_MINUTES (61) must not exceed JWT_EXPIRATION_MINUTES (60)"}, problems(t, cfg.Validate()))
+
+	cfg.JWT.ImpersonationExpirationMinutes = 0
+	assert.Len(t, problems(t, cfg.Validate()), 1)
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := loadDefaults(t)
+	cfg.JWT.Secret = ""
+	cfg.DB.Port = 70000
+	cfg.DB.MaxConns, cfg.DB.MinConns = 2, 5
+	cfg.Tracing.SampleRatio = 1.5
+	cfg.Avatar.BaseURL = "uploads"
+	cfg.Archiver.Enabled, cfg.Archiver.BatchSize = true, 0
+	cfg.Password.Algorithm = "md5"
+
+	err := cfg.Validate()
+
+	assert.ElementsMatch(t, []string{
+		"JWT_SECRET is required",
+		"DB_PORT must be at most 65535, got 70000",
+		"DB_MIN_CONNS (5) must not exceed DB_MAX_CONNS (2)",
+		"TRACING_SAMPLE_RATIO must be at most 1, got 1.5",
+		`AVATAR_BASE_URL must start with "/"`,
+		"ARCHIVER_INTERVAL_MINUTES and ARCHIVER_BATCH_SIZE must be positive when the archiver is enabled",
+		`PASSWORD_ALGORITHM must be "bcrypt" or "argon2id", got "md5"`,
+	}, problems(t, err))
+	assert.Contains(t, err.Error(), "invalid configuration:\n  - ")
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	cfg := loadDefaults(t)
+	assert.Empty(t, cfg.Server.TrustedProxies, "X-Forwarded-For is ignored unless proxies are configured")
+
+	t.Setenv("SERVER_TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.10,fd00::/8")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.10", "fd00::/8"}, cfg.Server.TrustedProxies)
+
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "load-balancer", "10.0.0.0/33"}
+	assert.Equal(t, []string{
+		`SERVER_TRUSTED_PROXIES entry "load-balancer" is not an IP address or CIDR range`,
+		`SERVER_TRUSTED_PROXIES entry "10.0.0.0/33" is not an IP address or CIDR range`,
+	}, problems(t, cfg.Validate()))
+}
+
+func TestLoad_DefaultCurrency(t *testing.T) {
+	assert.Equal(t, "USD", loadDefaults(t).Currency.Default)
+
+	t.Setenv("CURRENCY_DEFAULT", " eur")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", cfg.Currency.Default)
+
+	t.Setenv("CURRENCY_DEFAULT", "EURO")
+	_, err = Load()
+	assert.Equal(t, []string{`CURRENCY_DEFAULT must be a supported ISO 4217 code such as USD or EUR, got "EURO"`}, problems(t, err))
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler holds dependencies for managing machine client API keys.
+type APIKeyHandler struct {
+	service   services.APIKeyService
+	validator *validator.Validate
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler.
+func NewAPIKeyHandler(service services.APIKeyService, validate *validator.Validate) *APIKeyHandler {
+	return &APIKeyHandler{
+		service:   service,
+		validator: validate,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Mints an API key for a machine client, sent in the X-API-Key header. The key is only returned in this response. Admin only.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        apiKey body  dto.CreateAPIKeyRequest true "Owner and granted scopes"
+// @Success      201 {object}  dto.CreateAPIKeyResponse "API key created successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/api-keys [post]
+// @Security     BearerAuth
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("CreateAPIKey: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.CreatedBy = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	key, rawKey, err := h.service.CreateAPIKey(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateAPIKeyResponse{APIKeyResponse: MapAPIKeyModelToResponse(key), Key: rawKey})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Revokes an API key; requests using it are rejected from then on. Revoking a revoked key is a no-op. Admin only.
+// @Tags         api-keys
+// @Produce      json
+// @Param        id path      string true  "API key ID" Format(uuid)
+// @Success      200 {object}  dto.APIKeyResponse "API key revoked successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      404 {object}  dto.ErrorResponse "API key Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/api-keys/{id} [delete]
+// @Security     BearerAuth
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid API key ID format"))
+		return
+	}
+
+	req := dto.RevokeAPIKeyRequest{ID: keyID}
+	key, err := h.service.RevokeAPIKey(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "API key not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapAPIKeyModelToResponse(key))
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const avatarFormField = "avatar"
+
+// AvatarHandler holds dependencies for managing users' profile pictures.
+type AvatarHandler struct {
+	service  services.AvatarService
+	maxBytes int64 // Largest accepted upload; larger ones get 413
+}
+
+// NewAvatarHandler creates a new AvatarHandler accepting uploads of up to maxBytes.
+func NewAvatarHandler(service services.AvatarService, maxBytes int64) *AvatarHandler {
+	return &AvatarHandler{
+		service:  service,
+		maxBytes: maxBytes,
+	}
+}
+
+// UploadMyAvatar godoc
+// @Summary      Upload the current user's avatar
+// @Description  Replaces the current user's profile picture with the uploaded PNG, JPEG, GIF or WebP image. The type is detected from the file contents, not the client's Content-Type.
+// @Tags         users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        avatar formData file true "Image file"
+// @Success      200 {object}  dto.UserResponse "Avatar updated; avatar_url points at the new picture"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Missing file or not an image"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      413 {object}  dto.ErrorResponse "File too large"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/avatar [post]
+// @Security     BearerAuth
+func (h *AvatarHandler) UploadMyAvatar(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("UploadMyAvatar: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	header, err := c.FormFile(avatarFormField)
+	if err != nil {
+		respondError(c, badRequest("Multipart form file '"+avatarFormField+"' is required"))
+		return
+	}
+	if header.Size > h.maxBytes {
+		respondError(c, payloadTooLarge("Avatar exceeds the maximum allowed size"))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// Read one byte past the limit, in case the part is larger than its header claims
+	data, err := io.ReadAll(io.LimitReader(file, h.maxBytes+1))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if int64(len(data)) > h.maxBytes {
+		respondError(c, payloadTooLarge("Avatar exceeds the maximum allowed size"))
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		respondError(c, badRequest("Avatar must be an image"))
+		return
+	}
+
+	req := dto.UploadAvatarRequest{UserID: userID, ContentType: contentType, Data: data}
+	user, err := h.service.SetAvatar(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapUserModelToUserResponse(user))
+}
+
+// DeleteMyAvatar godoc
+// @Summary      Delete the current user's avatar
+// @Description  Removes the current user's profile picture. Deleting an avatar that isn't set is a no-op.
+// @Tags         users
+// @Success      204 "Avatar deleted"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/avatar [delete]
+// @Security     BearerAuth
+func (h *AvatarHandler) DeleteMyAvatar(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("DeleteMyAvatar: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	req := dto.DeleteAvatarRequest{UserID: userID}
+	if _, err := h.service.DeleteAvatar(c.Request.Context(), &req); err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAvatarService records the uploads it receives and points the user at a fixed URL.
+type stubAvatarService struct {
+	services.AvatarService
+	uploads []dto.UploadAvatarRequest
+}
+
+func (s *stubAvatarService) SetAvatar(ctx context.Context, req *dto.UploadAvatarRequest) (*models.User, error) {
+	s.uploads = append(s.uploads, *req)
+	url := "/uploads/avatars/" + req.UserID.String() + ".png"
+	return &models.User{ID: req.UserID, AvatarURL: &url}, nil
+}
+
+func performUploadAvatar(t *testing.T, service services.AvatarService, userID uuid.UUID, maxBytes int64, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", userID) // What JWTAuthMiddleware stores
+		c.Next()
+	}
+	router.POST("/users/me/avatar", authenticate, NewAvatarHandler(service, maxBytes).UploadMyAvatar)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("avatar", "avatar.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/avatar", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestAvatarHandler_UploadMyAvatar_ValidPNG(t *testing.T) {
+	userID := uuid.New()
+	service := &stubAvatarService{}
+	data := encodePNG(t, 4, 4)
+
+	w := performUploadAvatar(t, service, userID, 1<<20, data)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, service.uploads, 1)
+	assert.Equal(t, userID, service.uploads[0].UserID)
+	assert.Equal(t, "image/png", service.uploads[0].ContentType)
+	assert.Equal(t, data, service.uploads[0].Data)
+
+	var resp dto.UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.AvatarURL)
+	assert.Equal(t, "/uploads/avatars/"+userID.String()+".png", *resp.AvatarURL)
+}
+
+func TestAvatarHandler_UploadMyAvatar_Rejected(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxBytes       int64
+		data           func(t *testing.T) []byte
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "TooLarge",
+			maxBytes:       64,
+			data:           func(t *testing.T) []byte { return encodePNG(t, 256, 256) },
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   dto.ErrorCodePayloadTooLarge,
+		},
+		{
+			name:           "NotAnImage",
+			maxBytes:       1 << 20,
+			data:           func(t *testing.T) []byte { return []byte("plain text, not a picture") },
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   dto.ErrorCodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubAvatarService{}
+
+			w := performUploadAvatar(t, service, uuid.New(), tt.maxBytes, tt.data(t))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Empty(t, service.uploads, "rejected uploads must not reach the service")
+
+			var resp dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedCode, resp.Code)
+		})
+	}
+}
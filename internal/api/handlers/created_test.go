@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *stubJobService) CreateJob(ctx context.Context, req *dto.CreateJobRequest) (*models.Job, error) {
+	return s.job, nil
+}
+
+func (s *stubInvoiceService) CreateInvoice(ctx context.Context, req *dto.CreateInvoiceRequest) (*models.Invoice, error) {
+	return s.invoice, nil
+}
+
+func (s *stubUserGetter) Register(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error) {
+	return s.user, nil
+}
+
+// stubJobApplicationService implements only ApplyToJob, returning application.
+type stubJobApplicationService struct {
+	services.JobApplicationService
+	application *models.JobApplication
+}
+
+func (s *stubJobApplicationService) ApplyToJob(ctx context.Context, req *dto.ApplyToJobRequest) (*models.JobApplication, error) {
+	return s.application, nil
+}
+
+func TestCreateHandlersSetLocation(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := &models.Job{ID: uuid.New(), Rate: 50, Duration: 20, InvoiceInterval: 10, EmployerID: uuid.New(), State: models.JobStateWaiting, Version: 1, CreatedAt: now, UpdatedAt: now}
+	invoice := &models.Invoice{ID: uuid.New(), Value: 500, State: models.InvoiceStateWaiting, JobID: job.ID, IntervalNumber: 1, CreatedAt: now, UpdatedAt: now}
+	application := &models.JobApplication{ID: uuid.New(), ContractorID: uuid.New(), JobID: job.ID, State: models.JobApplicationWaiting, CreatedAt: now, UpdatedAt: now}
+	user := &models.User{ID: uuid.New(), Email: "created@example.com", Name: "Created User", Role: models.UserRoleUser, CreatedAt: now, UpdatedAt: now}
+
+	tests := []struct {
+		name     string
+		route    string
+		path     string
+		body     string
+		handler  gin.HandlerFunc
+		location string
+	}{
+		{
+			name:     "Job",
+			route:    "/jobs",
+			path:     "/jobs",
+			body:     `{"rate": 50, "duration": 20, "invoice_interval": 10}`,
+			handler:  NewJobHandler(&stubJobService{job: job}, validator.New()).CreateJob,
+			location: "/api/v1/jobs/" + job.ID.String(),
+		},
+		{
+			name:     "Invoice",
+			route:    "/invoices",
+			path:     "/invoices",
+			body:     `{"job_id": "` + job.ID.String() + `"}`,
+			handler:  NewInvoiceHandler(&stubInvoiceService{invoice: invoice}, validator.New()).CreateInvoice,
+			location: "/api/v1/invoices/" + invoice.ID.String(),
+		},
+		{
+			name:     "Application",
+			route:    "/jobs/:id/apply",
+			path:     "/jobs/" + job.ID.String() + "/apply",
+			handler:  NewJobApplicationHandler(&stubJobApplicationService{application: application}, validator.New()).ApplyToJob,
+			location: "/api/v1/applications/" + application.ID.String(),
+		},
+		{
+			name:     "Register",
+			route:    "/auth/register",
+			path:     "/auth/register",
+			body:     `{"email": "created@example.com", "password": "Secret123!"}`,
+			handler:  NewUserHandler(&stubUserGetter{user: user}, validator.New()).Register,
+			location: "/api/v1/users/" + user.ID.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			authenticate := func(c *gin.Context) {
+				c.Set("userID", uuid.New()) // What JWTAuthMiddleware stores
+				c.Next()
+			}
+			router.POST(tt.route, authenticate, tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+			assert.NotEmpty(t, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardHandler holds dependencies for the aggregated dashboard views.
+type DashboardHandler struct {
+	service services.DashboardService
+}
+
+// NewDashboardHandler creates a new DashboardHandler.
+func NewDashboardHandler(service services.DashboardService) *DashboardHandler {
+	return &DashboardHandler{service: service}
+}
+
+// GetMyDashboard godoc
+// @Summary      Get the contractor dashboard
+// @Description  Returns the current user's ongoing jobs, waiting applications (up to 10 of each, with totals) and the count, total and overdue total of unpaid invoices on their jobs. If a section can't be loaded the rest is still returned, with partial set and the section named in failed_sections.
+// @Tags         users
+// @Produce      json
+// @Success      200 {object}  dto.ContractorDashboardResponse "Contractor dashboard"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/dashboard [get]
+// @Security     BearerAuth
+func (h *DashboardHandler) GetMyDashboard(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("GetMyDashboard: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	req := dto.ContractorDashboardRequest{UserID: userID}
+
+	dashboard, err := h.service.GetContractorDashboard(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MapContractorDashboardToResponse(dashboard))
+}
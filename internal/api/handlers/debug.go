@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBStatter is implemented by *pgxpool.Pool.
+type DBStatter interface {
+	Stat() *pgxpool.Stat
+}
+
+// DebugHandler serves admin-only diagnostics.
+type DebugHandler struct {
+	db DBStatter
+}
+
+// NewDebugHandler creates a new DebugHandler.
+func NewDebugHandler(db DBStatter) *DebugHandler {
+	return &DebugHandler{db: db}
+}
+
+// DBStats godoc
+// @Summary      Database pool stats
+// @Description  Returns a snapshot of the database connection pool (acquired, idle and total connections, and wait counters) for diagnosing pool exhaustion. Admin only.
+// @Tags         debug
+// @Produce      json
+// @Success      200 {object}  dto.DBStatsResponse "Current pool statistics"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Router       /debug/dbstats [get]
+// @Security     BearerAuth
+func (h *DebugHandler) DBStats(c *gin.Context) {
+	stat := h.db.Stat()
+	c.JSON(http.StatusOK, dto.DBStatsResponse{
+		MaxConns:                stat.MaxConns(),
+		TotalConns:              stat.TotalConns(),
+		AcquiredConns:           stat.AcquiredConns(),
+		IdleConns:               stat.IdleConns(),
+		ConstructingConns:       stat.ConstructingConns(),
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDurationMs:       stat.AcquireDuration().Milliseconds(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	})
+}
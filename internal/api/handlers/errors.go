@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	canceledMessage = "Request canceled or timed out"
+	internalMessage = "Internal server error"
+
+	conflictRetryAfterSeconds = 1 // Transient conflicts clear as soon as the competing transaction finishes
+)
+
+// errorMapping describes how an error returned by a service is reported to the client.
+type errorMapping struct {
+	err     error
+	status  int
+	code    string
+	message string          // Default message, used unless the handler supplies one
+	expose  bool            // The service words these errors for clients, so err.Error() replaces message
+	details func(error) any // Optional extra context for the response body
+}
+
+// errorMappings decides the status and code of every error passed to respondError. Entries are matched
+// with errors.Is in order, so an error wrapping several sentinels is reported as the first one listed.
+// A new service sentinel needs an entry here, otherwise clients see it as a 500.
+var errorMappings = []errorMapping{
+	// storage.ErrCanceled wraps the context error, so a timeout is told apart from a client hang-up first
+	{err: context.DeadlineExceeded, status: http.StatusServiceUnavailable, code: dto.ErrorCodeRequestTimeout, message: canceledMessage},
+	{err: context.Canceled, status: StatusClientClosedRequest, code: dto.ErrorCodeRequestCanceled, message: canceledMessage},
+	{err: storage.ErrCanceled, status: StatusClientClosedRequest, code: dto.ErrorCodeRequestCanceled, message: canceledMessage},
+
+	{err: services.ErrValidation, status: http.StatusBadRequest, code: dto.ErrorCodeValidationFailed, message: "Validation failed", expose: true},
+	{err: services.ErrWeakPassword, status: http.StatusBadRequest, code: dto.ErrorCodeWeakPassword, message: "Password does not meet requirements", details: func(err error) any { return weakPasswordDetails(err) }},
+	{err: services.ErrInvalidInvoiceInterval, status: http.StatusBadRequest, code: dto.ErrorCodeInvalidInvoiceInterval, message: "Invalid invoice interval"},
+	{err: services.ErrInvalidAdjustment, status: http.StatusBadRequest, code: dto.ErrorCodeInvalidAdjustment, message: "Invalid invoice adjustment", expose: true},
+	{err: services.ErrInvalidTransition, status: http.StatusBadRequest, code: dto.ErrorCodeInvalidTransition, message: "Invalid state transition", expose: true},
+	{err: services.ErrInvalidCredentials, status: http.StatusUnauthorized, code: dto.ErrorCodeInvalidCredentials, message: "Invalid credentials"},
+	{err: services.ErrEmailNotVerified, status: http.StatusForbidden, code: dto.ErrorCodeEmailNotVerified, message: "Email address has not been verified"},
+	{err: services.ErrForbidden, status: http.StatusForbidden, code: dto.ErrorCodeForbidden, message: "Forbidden", expose: true},
+	{err: services.ErrNotFound, status: http.StatusNotFound, code: dto.ErrorCodeNotFound, message: "Resource not found"},
+	{err: storage.ErrNotFound, status: http.StatusNotFound, code: dto.ErrorCodeNotFound, message: "Resource not found"},
+	{err: services.ErrLimitExceeded, status: http.StatusUnprocessableEntity, code: dto.ErrorCodeLimitExceeded, message: "Limit exceeded", expose: true},
+	{err: services.ErrInvalidState, status: http.StatusConflict, code: dto.ErrorCodeInvalidState, message: "Resource is not in a valid state for this operation", expose: true},
+	{err: services.ErrConflict, status: http.StatusConflict, code: dto.ErrorCodeConflict, message: "Resource conflict", details: conflictDetails},
+	{err: storage.ErrDuplicateEmail, status: http.StatusConflict, code: dto.ErrorCodeConflict, message: "Email address already registered", details: conflictDetails},
+	{err: storage.ErrConflict, status: http.StatusConflict, code: dto.ErrorCodeConflict, message: "Resource conflict", details: conflictDetails},
+	{err: services.ErrAccountLocked, status: http.StatusLocked, code: dto.ErrorCodeAccountLocked, message: "Account temporarily locked after too many failed login attempts"},
+}
+
+// errorMessages replaces the default message of some sentinels for one call to respondError, so a
+// handler can say which resource was missing or why the request was refused.
+type errorMessages map[error]string
+
+// apiError is an error detected by the handler itself, such as a malformed path parameter, that
+// already knows how it is reported.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(message string) error {
+	return &apiError{status: http.StatusBadRequest, code: dto.ErrorCodeBadRequest, message: message}
+}
+
+func unauthorized(message string) error {
+	return &apiError{status: http.StatusUnauthorized, code: dto.ErrorCodeUnauthorized, message: message}
+}
+
+func forbidden(message string) error {
+	return &apiError{status: http.StatusForbidden, code: dto.ErrorCodeForbidden, message: message}
+}
+
+func notFound(message string) error {
+	return &apiError{status: http.StatusNotFound, code: dto.ErrorCodeNotFound, message: message}
+}
+
+func payloadTooLarge(message string) error {
+	return &apiError{status: http.StatusRequestEntityTooLarge, code: dto.ErrorCodePayloadTooLarge, message: message}
+}
+
+// invalidToken reports a password reset or verification token that is unknown, used or expired. It
+// is a 400 rather than a 401, since the caller isn't authenticating with the token.
+func invalidToken(message string) error {
+	return &apiError{status: http.StatusBadRequest, code: dto.ErrorCodeInvalidToken, message: message}
+}
+
+// respondError writes err as a dto.ErrorResponse. An *apiError carries its own status and code; any
+// other error is looked up in errorMappings, with messages overriding the default message of the
+// matched sentinel. Errors matching nothing are unexpected, so they are logged and answered with a
+// generic 500 that doesn't leak their text.
+func respondError(c *gin.Context, err error, messages ...errorMessages) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		writeError(c, apiErr.status, apiErr.code, apiErr.message, nil)
+		return
+	}
+
+	reqLogger := logger.FromContext(c.Request.Context())
+	if mapping := matchErrorMapping(err); mapping != nil {
+		message := mapping.message
+		if mapping.expose {
+			message = err.Error()
+		}
+		for _, overrides := range messages {
+			if override, ok := overrides[mapping.err]; ok {
+				message = override
+			}
+		}
+		var details any
+		if mapping.details != nil {
+			details = mapping.details(err)
+		}
+		if conflict, ok := details.(dto.ConflictDetails); ok && conflict.RetryAfterSeconds > 0 {
+			c.Header("Retry-After", strconv.Itoa(conflict.RetryAfterSeconds))
+		}
+		if mapping.status >= StatusClientClosedRequest {
+			// Canceled and timed out requests aren't server failures, but are worth a trace
+			reqLogger.Info("Request canceled", "method", c.Request.Method, "route", c.FullPath(), "error", err)
+		}
+		writeError(c, mapping.status, mapping.code, message, details)
+		return
+	}
+
+	reqLogger.Error("Unhandled error", "method", c.Request.Method, "route", c.FullPath(), "params", c.Params, "error", err)
+	writeError(c, http.StatusInternalServerError, dto.ErrorCodeInternal, internalMessage, nil)
+}
+
+// matchErrorMapping returns the first entry of errorMappings err matches, or nil.
+func matchErrorMapping(err error) *errorMapping {
+	for i := range errorMappings {
+		if errors.Is(err, errorMappings[i].err) {
+			return &errorMappings[i]
+		}
+	}
+	return nil
+}
+
+// DescribedError is how respondError would report an error, for transports that don't answer with
+// its JSON envelope, such as GraphQL.
+type DescribedError struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+}
+
+// DescribeError reports err the way respondError does. It returns false for errors matching no
+// entry of errorMappings, which get the generic 500 and should be logged by the caller.
+func DescribeError(err error) (DescribedError, bool) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return DescribedError{Status: apiErr.status, Code: apiErr.code, Message: apiErr.message}, true
+	}
+	mapping := matchErrorMapping(err)
+	if mapping == nil {
+		return DescribedError{Status: http.StatusInternalServerError, Code: dto.ErrorCodeInternal, Message: internalMessage}, false
+	}
+
+	described := DescribedError{Status: mapping.status, Code: mapping.code, Message: mapping.message}
+	if mapping.expose {
+		described.Message = err.Error()
+	}
+	if mapping.details != nil {
+		described.Details = mapping.details(err)
+	}
+	return described, true
+}
+
+// conflictDetails tells clients whether a conflict is worth retrying. Only conflicts the database
+// reported as transient are; a duplicate or a row changed by someone else will fail the same way again.
+func conflictDetails(err error) any {
+	if storage.IsRetryableConflict(err) {
+		return dto.ConflictDetails{Retryable: true, RetryAfterSeconds: conflictRetryAfterSeconds}
+	}
+	return dto.ConflictDetails{Retryable: false}
+}
+
+// respondValidationError writes the standard 400 response for an error returned by validator.Struct.
+func respondValidationError(c *gin.Context, err error) {
+	writeError(c, http.StatusBadRequest, dto.ErrorCodeValidationFailed, "Validation failed", FormatValidationErrors(err))
+}
+
+// writeError sends the error envelope, tagged with the request ID so clients can quote it when reporting a problem.
+func writeError(c *gin.Context, status int, code, message string, details any) {
+	c.JSON(status, dto.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: middleware.GetRequestIDFromContext(c),
+		Details:   details,
+	})
+}
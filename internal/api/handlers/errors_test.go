@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/password"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveError runs respondError behind the RequestID middleware and decodes the envelope it wrote.
+func serveError(t *testing.T, err error, messages ...errorMessages) (int, dto.ErrorResponse, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.GET("/jobs/:id", func(c *gin.Context) { respondError(c, err, messages...) })
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/1", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return w.Code, body, w.Body.String()
+}
+
+func TestRespondError_Sentinels(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedCode    string
+		expectedMessage string
+		expectedDetails any
+	}{
+		{name: "NotFound", err: fmt.Errorf("%w: getting job", services.ErrNotFound), expectedStatus: http.StatusNotFound, expectedCode: dto.ErrorCodeNotFound, expectedMessage: "Resource not found"},
+		{name: "StorageNotFound", err: storage.ErrNotFound, expectedStatus: http.StatusNotFound, expectedCode: dto.ErrorCodeNotFound, expectedMessage: "Resource not found"},
+		{name: "Forbidden", err: services.ErrForbidden, expectedStatus: http.StatusForbidden, expectedCode: dto.ErrorCodeForbidden, expectedMessage: "forbidden"},
+		{name: "Conflict", err: fmt.Errorf("%w: updating job (version mismatch)", services.ErrConflict), expectedStatus: http.StatusConflict, expectedCode: dto.ErrorCodeConflict, expectedMessage: "Resource conflict", expectedDetails: map[string]any{"retryable": false}},
+		{name: "DuplicateEmail", err: storage.ErrDuplicateEmail, expectedStatus: http.StatusConflict, expectedCode: dto.ErrorCodeConflict, expectedMessage: "Email address already registered", expectedDetails: map[string]any{"retryable": false}},
+		{name: "InvalidState", err: fmt.Errorf("%w: applications are closed", services.ErrInvalidState), expectedStatus: http.StatusConflict, expectedCode: dto.ErrorCodeInvalidState, expectedMessage: "invalid state for operation: applications are closed"},
+		{name: "InvalidTransition", err: fmt.Errorf("%w: from Waiting to Complete", services.ErrInvalidTransition), expectedStatus: http.StatusBadRequest, expectedCode: dto.ErrorCodeInvalidTransition, expectedMessage: "invalid state transition: from Waiting to Complete"},
+		{name: "Validation", err: fmt.Errorf("%w: min_duration must not be greater than max_duration", services.ErrValidation), expectedStatus: http.StatusBadRequest, expectedCode: dto.ErrorCodeValidationFailed, expectedMessage: "validation failed: min_duration must not be greater than max_duration"},
+		{name: "InvalidInvoiceInterval", err: services.ErrInvalidInvoiceInterval, expectedStatus: http.StatusBadRequest, expectedCode: dto.ErrorCodeInvalidInvoiceInterval, expectedMessage: "Invalid invoice interval"},
+		{name: "InvalidAdjustment", err: fmt.Errorf("%w: adjustment of 60.00 exceeds 50%% of the base value 100.00", services.ErrInvalidAdjustment), expectedStatus: http.StatusBadRequest, expectedCode: dto.ErrorCodeInvalidAdjustment, expectedMessage: "invalid invoice adjustment: adjustment of 60.00 exceeds 50% of the base value 100.00"},
+		{name: "LimitExceeded", err: fmt.Errorf("%w: contractor already has 3 ongoing jobs, the maximum allowed", services.ErrLimitExceeded), expectedStatus: http.StatusUnprocessableEntity, expectedCode: dto.ErrorCodeLimitExceeded, expectedMessage: "limit exceeded: contractor already has 3 ongoing jobs, the maximum allowed"},
+		{name: "InvalidCredentials", err: services.ErrInvalidCredentials, expectedStatus: http.StatusUnauthorized, expectedCode: dto.ErrorCodeInvalidCredentials, expectedMessage: "Invalid credentials"},
+		{name: "EmailNotVerified", err: services.ErrEmailNotVerified, expectedStatus: http.StatusForbidden, expectedCode: dto.ErrorCodeEmailNotVerified, expectedMessage: "Email address has not been verified"},
+		{name: "AccountLocked", err: services.ErrAccountLocked, expectedStatus: http.StatusLocked, expectedCode: dto.ErrorCodeAccountLocked, expectedMessage: "Account temporarily locked after too many failed login attempts"},
+		{name: "ClientCanceled", err: fmt.Errorf("getting job: %w: %w", storage.ErrCanceled, context.Canceled), expectedStatus: StatusClientClosedRequest, expectedCode: dto.ErrorCodeRequestCanceled, expectedMessage: canceledMessage},
+		{name: "TimedOut", err: fmt.Errorf("getting job: %w: %w", storage.ErrCanceled, context.DeadlineExceeded), expectedStatus: http.StatusServiceUnavailable, expectedCode: dto.ErrorCodeRequestTimeout, expectedMessage: canceledMessage},
+		{name: "CanceledOutsideRepo", err: fmt.Errorf("internal error starting transaction: %w", context.Canceled), expectedStatus: StatusClientClosedRequest, expectedCode: dto.ErrorCodeRequestCanceled, expectedMessage: canceledMessage},
+		{name: "Unexpected", err: errors.New("connection reset by peer"), expectedStatus: http.StatusInternalServerError, expectedCode: dto.ErrorCodeInternal, expectedMessage: internalMessage},
+		{name: "HandlerError", err: badRequest("Invalid job ID format"), expectedStatus: http.StatusBadRequest, expectedCode: dto.ErrorCodeBadRequest, expectedMessage: "Invalid job ID format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body, _ := serveError(t, tt.err)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, dto.ErrorResponse{Code: tt.expectedCode, Message: tt.expectedMessage, RequestID: "req-123", Details: tt.expectedDetails}, body)
+		})
+	}
+}
+
+func TestRespondError_EveryMappingHasAStatusAndCode(t *testing.T) {
+	for _, mapping := range errorMappings {
+		status, body, _ := serveError(t, fmt.Errorf("wrapped: %w", mapping.err))
+		assert.Equal(t, mapping.status, status, "%v", mapping.err)
+		assert.Equal(t, mapping.code, body.Code, "%v", mapping.err)
+		assert.NotEmpty(t, body.Message, "%v", mapping.err)
+		assert.Equal(t, "req-123", body.RequestID, "%v", mapping.err)
+	}
+}
+
+func TestRespondError_MessageOverride(t *testing.T) {
+	messages := errorMessages{services.ErrNotFound: "Job not found", services.ErrForbidden: "Only the employer can update this job"}
+
+	status, body, _ := serveError(t, fmt.Errorf("%w: getting job", services.ErrNotFound), messages)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "Job not found", body.Message)
+
+	// Overrides for other sentinels don't leak into unrelated errors
+	status, body, _ = serveError(t, services.ErrConflict, messages)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "Resource conflict", body.Message)
+}
+
+func TestRespondError_WeakPasswordDetails(t *testing.T) {
+	err := &password.PolicyError{Unmet: []string{"at least 12 characters", "a digit"}}
+	require.ErrorIs(t, err, services.ErrWeakPassword)
+
+	status, _, raw := serveError(t, fmt.Errorf("registering user: %w", err))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{
+		"code": "weak_password",
+		"message": "Password does not meet requirements",
+		"request_id": "req-123",
+		"details": ["at least 12 characters", "a digit"]
+	}`, raw)
+}
+
+func TestRespondError_ConflictRetryable(t *testing.T) {
+	tests := []struct {
+		name               string
+		pgCode             string
+		expectedRetryable  bool
+		expectedRetryAfter string
+	}{
+		{name: "UniqueViolation", pgCode: "23505", expectedRetryable: false},
+		{name: "SerializationFailure", pgCode: "40001", expectedRetryable: true, expectedRetryAfter: "1"},
+		{name: "Deadlock", pgCode: "40P01", expectedRetryable: true, expectedRetryAfter: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// As built by the postgres repos and passed on by the services
+			repoErr := fmt.Errorf("failed to create invoice: %w", &storage.ConflictError{Code: tt.pgCode, Err: errors.New("pg error")})
+			err := fmt.Errorf("%w: %w", services.ErrConflict, repoErr)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/invoices", func(c *gin.Context) { respondError(c, err) })
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/invoices", nil))
+
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+
+			var body struct {
+				Code    string              `json:"code"`
+				Details dto.ConflictDetails `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+			assert.Equal(t, dto.ErrorCodeConflict, body.Code)
+			assert.Equal(t, tt.expectedRetryable, body.Details.Retryable)
+		})
+	}
+}
+
+func TestDescribeError_MatchesRespondError(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("%w: getting job", services.ErrNotFound),
+		fmt.Errorf("%w: only the employer can list applications", services.ErrForbidden),
+		fmt.Errorf("%w: %w", services.ErrConflict, &storage.ConflictError{Code: "40001", Err: errors.New("pg error")}),
+		badRequest("Invalid job ID format"),
+	} {
+		status, body, _ := serveError(t, err)
+		described, mapped := DescribeError(err)
+
+		assert.True(t, mapped, "%v", err)
+		assert.Equal(t, status, described.Status, "%v", err)
+		assert.Equal(t, body.Code, described.Code, "%v", err)
+		assert.Equal(t, body.Message, described.Message, "%v", err)
+	}
+
+	described, mapped := DescribeError(errors.New("connection reset by peer"))
+	assert.False(t, mapped)
+	assert.Equal(t, DescribedError{Status: http.StatusInternalServerError, Code: dto.ErrorCodeInternal, Message: internalMessage}, described)
+}
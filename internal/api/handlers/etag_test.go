@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubJobService implements only the job reads, returning job (and total for lists).
+type stubJobService struct {
+	services.JobService
+	job   *models.Job
+	total int
+}
+
+func (s *stubJobService) GetJobByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error) {
+	return s.job, nil
+}
+
+// stubInvoiceService implements only GetInvoiceByID, returning invoice.
+type stubInvoiceService struct {
+	services.InvoiceService
+	invoice *models.Invoice
+}
+
+func (s *stubInvoiceService) GetInvoiceByID(ctx context.Context, req *dto.GetInvoiceByIDRequest) (*models.Invoice, error) {
+	return s.invoice, nil
+}
+
+// stubUserGetter implements only GetByID, returning user if its ID matches and ErrNotFound otherwise,
+// and SharesActiveJob, which is true for the users in counterparts.
+type stubUserGetter struct {
+	services.UserService
+	user         *models.User
+	counterparts []uuid.UUID
+}
+
+func (s *stubUserGetter) SharesActiveJob(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	for _, id := range s.counterparts {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubUserGetter) GetByID(ctx context.Context, req *dto.GetUserByIdRequest) (*models.User, error) {
+	if s.user == nil || s.user.ID != req.ID {
+		return nil, services.ErrNotFound
+	}
+	return s.user, nil
+}
+
+// performConditionalGet calls handler for path, sending ifNoneMatch when it isn't empty.
+func performConditionalGet(t *testing.T, route, path string, handler gin.HandlerFunc, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", uuid.New()) // What JWTAuthMiddleware stores
+		c.Next()
+	}
+	router.GET(route, authenticate, handler)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestConditionalGet(t *testing.T) {
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := &models.Job{ID: uuid.New(), Rate: 50, Duration: 20, InvoiceInterval: 10, EmployerID: uuid.New(), State: models.JobStateWaiting, Version: 1, CreatedAt: updatedAt, UpdatedAt: updatedAt}
+	invoice := &models.Invoice{ID: uuid.New(), Value: 500, State: models.InvoiceStateWaiting, JobID: job.ID, IntervalNumber: 1, CreatedAt: updatedAt, UpdatedAt: updatedAt}
+	user := &models.User{ID: uuid.New(), Email: "etag@example.com", Name: "ETag User", Role: models.UserRoleUser, CreatedAt: updatedAt, UpdatedAt: updatedAt}
+
+	tests := []struct {
+		name    string
+		route   string
+		path    string
+		handler gin.HandlerFunc
+		touch   func() // Changes the resource, as an update would
+	}{
+		{
+			name:    "Job",
+			route:   "/jobs/:id",
+			path:    "/jobs/" + job.ID.String(),
+			handler: NewJobHandler(&stubJobService{job: job}, validator.New()).GetJobByID,
+			touch:   func() { job.Version++; job.UpdatedAt = job.UpdatedAt.Add(time.Second) },
+		},
+		{
+			name:    "Invoice",
+			route:   "/invoices/:id",
+			path:    "/invoices/" + invoice.ID.String(),
+			handler: NewInvoiceHandler(&stubInvoiceService{invoice: invoice}, validator.New()).GetInvoiceByID,
+			touch:   func() { invoice.State = models.InvoiceStateComplete; invoice.UpdatedAt = invoice.UpdatedAt.Add(time.Second) },
+		},
+		{
+			name:    "User",
+			route:   "/users/:id",
+			path:    "/users/" + user.ID.String(),
+			handler: NewUserHandler(&stubUserGetter{user: user}, validator.New()).GetUserByID,
+			touch:   func() { user.Name = "Renamed"; user.UpdatedAt = user.UpdatedAt.Add(time.Second) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := performConditionalGet(t, tt.route, tt.path, tt.handler, "")
+			require.Equal(t, http.StatusOK, first.Code)
+			etag := first.Header().Get("ETag")
+			require.NotEmpty(t, etag)
+			assert.NotEmpty(t, first.Body.String())
+
+			// Same resource, same ETag
+			again := performConditionalGet(t, tt.route, tt.path, tt.handler, "")
+			assert.Equal(t, etag, again.Header().Get("ETag"), "ETag must be stable for an unchanged resource")
+
+			notModified := performConditionalGet(t, tt.route, tt.path, tt.handler, etag)
+			assert.Equal(t, http.StatusNotModified, notModified.Code)
+			assert.Empty(t, notModified.Body.String())
+			assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+			tt.touch()
+			changed := performConditionalGet(t, tt.route, tt.path, tt.handler, etag)
+			assert.Equal(t, http.StatusOK, changed.Code, "A stale ETag must get the new representation")
+			assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `"abc123"`
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    bool
+	}{
+		{name: "Exact", ifNoneMatch: `"abc123"`, expected: true},
+		{name: "Weak", ifNoneMatch: `W/"abc123"`, expected: true},
+		{name: "InList", ifNoneMatch: `"other", "abc123"`, expected: true},
+		{name: "Wildcard", ifNoneMatch: `*`, expected: true},
+		{name: "Different", ifNoneMatch: `"other"`, expected: false},
+		{name: "Unquoted", ifNoneMatch: `abc123`, expected: false},
+		{name: "Empty", ifNoneMatch: ``, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, etagMatches(tt.ifNoneMatch, etag))
+		})
+	}
+}
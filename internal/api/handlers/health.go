@@ -1,20 +1,142 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"time"
+
+	"go-api-template/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
-// HealthCheck handles the health check endpoint
-// @Summary Health check
-// @Description Check if the service is up and running
+// dependencyCheckTimeout bounds each database/redis ping made by the readiness probe.
+const dependencyCheckTimeout = 2 * time.Second
+
+// RPCHealthChecker is implemented by components that can verify blockchain RPC connectivity.
+type RPCHealthChecker interface {
+	CheckRPC(ctx context.Context) (uint64, error)
+}
+
+// DBPinger is implemented by *pgxpool.Pool.
+type DBPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// RedisPinger is implemented by *redis.Client.
+type RedisPinger interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}
+
+// InFlightCounter is implemented by *middleware.InFlight.
+type InFlightCounter interface {
+	Count() int
+}
+
+// HealthHandler holds the dependencies probed by the readiness endpoint
+type HealthHandler struct {
+	db          DBPinger
+	redisClient RedisPinger
+	rpc         RPCHealthChecker // nil when no blockchain listener is configured
+	rpcRequired bool             // If true, an RPC failure fails readiness instead of degrading it
+	rpcTimeout  time.Duration
+	inFlight    InFlightCounter // nil to leave the in-flight count out of the response
+}
+
+// NewHealthHandler creates a new HealthHandler. Pass a nil rpc checker to skip the blockchain check.
+func NewHealthHandler(db DBPinger, redisClient RedisPinger, rpc RPCHealthChecker, rpcRequired bool, rpcTimeout time.Duration) *HealthHandler {
+	return &HealthHandler{db: db, redisClient: redisClient, rpc: rpc, rpcRequired: rpcRequired, rpcTimeout: rpcTimeout}
+}
+
+// WithInFlight makes readiness report how many requests the server is serving, this one included.
+func (h *HealthHandler) WithInFlight(counter InFlightCounter) *HealthHandler {
+	h.inFlight = counter
+	return h
+}
+
+// HealthCheck handles the liveness endpoints. It never checks dependencies, so a database or Redis
+// outage marks the service unready without getting it restarted.
+// @Summary Liveness check
+// @Description Check if the service process is up and serving requests. Always returns 200; use /readyz for dependencies.
 // @Tags Health
 // @Produce json
-// @Success 200 {object} map[string]string "API is healthy"
+// @Success 200 {object} map[string]string "API is alive"
 // @Router /health [get]
+// @Router /healthz [get]
 func HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "ok",
 	})
 }
+
+// Readiness godoc
+// @Summary Readiness check
+// @Description Check whether the service's dependencies are reachable. The blockchain RPC is only checked when a listener is configured;
+// @Description a failing RPC reports "degraded" unless blockchain.health_required is set, in which case readiness fails.
+// @Description in_flight_requests is the number of requests being served, this one included.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Service is ready (status ok or degraded)"
+// @Failure 503 {object} map[string]interface{} "A required dependency is unavailable"
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx := c.Request.Context()
+	status := "ok"
+	httpStatus := http.StatusOK
+	checks := gin.H{}
+
+	markUnavailable := func() {
+		status = "unavailable"
+		httpStatus = http.StatusServiceUnavailable
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	err := h.db.Ping(dbCtx)
+	cancel()
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Warn("Readiness database ping failed", "error", err)
+		checks["database"] = gin.H{"status": "error", "error": err.Error()}
+		markUnavailable()
+	} else {
+		checks["database"] = gin.H{"status": "ok"}
+	}
+
+	redisCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	err = h.redisClient.Ping(redisCtx).Err()
+	cancel()
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Warn("Readiness redis ping failed", "error", err)
+		checks["redis"] = gin.H{"status": "error", "error": err.Error()}
+		markUnavailable()
+	} else {
+		checks["redis"] = gin.H{"status": "ok"}
+	}
+
+	// Blockchain RPC is optional: skipped entirely when no listener is configured
+	if h.rpc != nil {
+		rpcCtx, cancel := context.WithTimeout(ctx, h.rpcTimeout)
+		blockNumber, err := h.rpc.CheckRPC(rpcCtx)
+		cancel()
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("Readiness blockchain RPC check failed", "error", err)
+			checks["blockchain_rpc"] = gin.H{"status": "error", "error": err.Error()}
+			if h.rpcRequired {
+				markUnavailable()
+			} else if status == "ok" {
+				status = "degraded"
+			}
+		} else {
+			checks["blockchain_rpc"] = gin.H{"status": "ok", "block_number": blockNumber}
+		}
+	}
+
+	response := gin.H{
+		"status": status,
+		"checks": checks,
+	}
+	if h.inFlight != nil {
+		response["in_flight_requests"] = h.inFlight.Count()
+	}
+	c.JSON(httpStatus, response)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePool stands in for *pgxpool.Pool; a non-nil err makes every ping fail.
+type fakePool struct {
+	err error
+}
+
+func (p fakePool) Ping(ctx context.Context) error { return p.err }
+
+// fakeRPC stands in for the blockchain listener.
+type fakeRPC struct {
+	err error
+}
+
+func (r fakeRPC) CheckRPC(ctx context.Context) (uint64, error) { return 42, r.err }
+
+type readinessBody struct {
+	Status   string                    `json:"status"`
+	Checks   map[string]map[string]any `json:"checks"`
+	InFlight *int                      `json:"in_flight_requests"`
+}
+
+// fixedCount stands in for the server's in-flight request tracker.
+type fixedCount int
+
+func (n fixedCount) Count() int { return int(n) }
+
+func performReadiness(t *testing.T, h *HealthHandler) (int, readinessBody) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/readyz", h.Readiness)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var body readinessBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		dbErr          error
+		redisErr       error
+		rpc            RPCHealthChecker
+		rpcRequired    bool
+		expectedStatus int
+		expectedBody   string
+		failedChecks   []string
+	}{
+		{name: "AllHealthy", expectedStatus: http.StatusOK, expectedBody: "ok"},
+		{name: "DatabaseDown", dbErr: errors.New("connection refused"), expectedStatus: http.StatusServiceUnavailable, expectedBody: "unavailable", failedChecks: []string{"database"}},
+		{name: "RedisDown", redisErr: errors.New("connection refused"), expectedStatus: http.StatusServiceUnavailable, expectedBody: "unavailable", failedChecks: []string{"redis"}},
+		{name: "BothDown", dbErr: errors.New("connection refused"), redisErr: errors.New("connection refused"), expectedStatus: http.StatusServiceUnavailable, expectedBody: "unavailable", failedChecks: []string{"database", "redis"}},
+		{name: "OptionalRPCDown", rpc: fakeRPC{err: errors.New("dial timeout")}, expectedStatus: http.StatusOK, expectedBody: "degraded", failedChecks: []string{"blockchain_rpc"}},
+		{name: "RequiredRPCDown", rpc: fakeRPC{err: errors.New("dial timeout")}, rpcRequired: true, expectedStatus: http.StatusServiceUnavailable, expectedBody: "unavailable", failedChecks: []string{"blockchain_rpc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, mock := redismock.NewClientMock()
+			if tt.redisErr != nil {
+				mock.ExpectPing().SetErr(tt.redisErr)
+			} else {
+				mock.ExpectPing().SetVal("PONG")
+			}
+			h := NewHealthHandler(fakePool{err: tt.dbErr}, redisClient, tt.rpc, tt.rpcRequired, time.Second)
+
+			code, body := performReadiness(t, h)
+
+			assert.Equal(t, tt.expectedStatus, code)
+			assert.Equal(t, tt.expectedBody, body.Status)
+			for _, name := range tt.failedChecks {
+				require.Contains(t, body.Checks, name)
+				assert.Equal(t, "error", body.Checks[name]["status"], "%s should be reported as failed", name)
+				assert.NotEmpty(t, body.Checks[name]["error"])
+			}
+			for _, name := range []string{"database", "redis"} {
+				if !slices.Contains(tt.failedChecks, name) {
+					assert.Equal(t, "ok", body.Checks[name]["status"])
+				}
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReadiness_ReportsInFlightRequests(t *testing.T) {
+	redisClient, mock := redismock.NewClientMock()
+	mock.ExpectPing().SetVal("PONG")
+	mock.ExpectPing().SetVal("PONG")
+
+	_, body := performReadiness(t, NewHealthHandler(fakePool{}, redisClient, nil, false, time.Second))
+	assert.Nil(t, body.InFlight, "left out unless a counter is configured")
+
+	_, body = performReadiness(t, NewHealthHandler(fakePool{}, redisClient, nil, false, time.Second).WithInFlight(fixedCount(3)))
+	require.NotNil(t, body.InFlight)
+	assert.Equal(t, 3, *body.InFlight)
+}
+
+func TestHealthCheck_AlwaysOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/healthz", HealthCheck)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
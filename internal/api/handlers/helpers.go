@@ -1,38 +1,179 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"go-api-template/internal/models"
 	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/money"
+	"go-api-template/pkg/password"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
+	"github.com/google/uuid"
 )
 
-func FormatValidationErrors(err error) map[string]string {
-	errorsMap := make(map[string]string)
-	validationErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		errorsMap["error"] = "Invalid validation error type"
-		return errorsMap
+// APIBasePath is the prefix all API routes are mounted under; Location headers are built from it.
+const APIBasePath = "/api/v1"
+
+// StatusClientClosedRequest is the non-standard status (from nginx) for a request the client gave up on.
+const StatusClientClosedRequest = 499
+
+// FormatValidationErrors turns the error returned by validator.Struct into one FieldError per failed rule.
+func FormatValidationErrors(err error) []dto.FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []dto.FieldError{{Message: "Invalid validation error type"}}
 	}
+
+	fieldErrors := make([]dto.FieldError, 0, len(validationErrors))
 	for _, fieldError := range validationErrors {
-		fieldName := fieldError.Field()
-		errorsMap[fieldName] = fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", fieldName, fieldError.Tag())
-		switch fieldError.Tag() {
-		case "required":
-			errorsMap[fieldName] = fmt.Sprintf("Field '%s' is required", fieldName)
-		case "email":
-			errorsMap[fieldName] = fmt.Sprintf("Field '%s' must be a valid email address", fieldName)
-		case "min":
-			errorsMap[fieldName] = fmt.Sprintf("Field '%s' must be at least %s characters long", fieldName, fieldError.Param())
-		case "max":
-			errorsMap[fieldName] = fmt.Sprintf("Field '%s' must be at most %s characters long", fieldName, fieldError.Param())
-		case "uuid":
-			errorsMap[fieldName] = fmt.Sprintf("Field '%s' must be a valid UUID", fieldName)
+		// Namespace starts with the request struct name, which means nothing to clients
+		field := fieldError.Namespace()
+		if _, rest, found := strings.Cut(field, "."); found {
+			field = rest
+		}
+		fieldErrors = append(fieldErrors, dto.FieldError{
+			Field:   field,
+			Tag:     fieldError.Tag(),
+			Message: validationMessage(field, fieldError),
+		})
+	}
+	return fieldErrors
+}
+
+// validationMessage describes a failed rule for the common tags, falling back to a generic message.
+func validationMessage(field string, fieldError validator.FieldError) string {
+	isLength := fieldError.Kind() == reflect.String || fieldError.Kind() == reflect.Slice || fieldError.Kind() == reflect.Map
+	switch fieldError.Tag() {
+	case "required":
+		return fmt.Sprintf("Field '%s' is required", field)
+	case "email":
+		return fmt.Sprintf("Field '%s' must be a valid email address", field)
+	case "min":
+		if isLength {
+			return fmt.Sprintf("Field '%s' must be at least %s characters long", field, fieldError.Param())
+		}
+		return fmt.Sprintf("Field '%s' must be at least %s", field, fieldError.Param())
+	case "max":
+		if isLength {
+			return fmt.Sprintf("Field '%s' must be at most %s characters long", field, fieldError.Param())
+		}
+		return fmt.Sprintf("Field '%s' must be at most %s", field, fieldError.Param())
+	case "gt":
+		return fmt.Sprintf("Field '%s' must be greater than %s", field, fieldError.Param())
+	case "gte":
+		return fmt.Sprintf("Field '%s' must be greater than or equal to %s", field, fieldError.Param())
+	case "gtefield":
+		return fmt.Sprintf("Field '%s' must be greater than or equal to '%s'", field, fieldError.Param())
+	case "ltefield":
+		return fmt.Sprintf("Field '%s' must be less than or equal to '%s'", field, fieldError.Param())
+	case "oneof":
+		return fmt.Sprintf("Field '%s' must be one of: %s", field, fieldError.Param())
+	case "uuid":
+		return fmt.Sprintf("Field '%s' must be a valid UUID", field)
+	case "url":
+		return fmt.Sprintf("Field '%s' must be a valid URL", field)
+	default:
+		return fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", field, fieldError.Tag())
+	}
+}
+
+// respondWithETag writes body as a 200 JSON response carrying an ETag, or a bodiless 304 if the
+// request's If-None-Match already names that ETag. The ETag is a hash of the encoded body, so it
+// only changes when the response would.
+func respondWithETag(c *gin.Context, body any) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sum := sha256.Sum256(encoded)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache") // Clients may keep a copy but must revalidate it
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", encoded)
+}
+
+// etagMatches reports whether an If-None-Match header value names etag. Weak validators are
+// compared by their opaque tag, as RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
 		}
 	}
-	return errorsMap
+	return false
+}
+
+// respondCreated writes body as a 201 JSON response whose Location header points at the new
+// resource. location is relative to APIBasePath, e.g. "/jobs/<id>".
+func respondCreated(c *gin.Context, location string, body any) {
+	c.Header("Location", APIBasePath+location)
+	c.JSON(http.StatusCreated, body)
+}
+
+// mimeCSV is the media type of CSV exports.
+const mimeCSV = "text/csv"
+
+// wantsCSV reports whether the request's Accept header prefers CSV over JSON. Without an Accept
+// header, or with */*, JSON is preferred.
+func wantsCSV(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, mimeCSV) == mimeCSV
+}
+
+// setPaginationHeaders writes X-Total-Count and an RFC 5988 Link header (first, prev, next, last)
+// for a list response, so clients can paginate without reading the body. The links keep the
+// request's path and other query parameters and only change limit and offset.
+func setPaginationHeaders(c *gin.Context, total, limit, offset int) {
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	if limit <= 0 {
+		return
+	}
+
+	pageURL := func(pageOffset int) string {
+		query := c.Request.URL.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(pageOffset))
+		return c.Request.URL.Path + "?" + query.Encode()
+	}
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / limit * limit
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(0))}
+	if offset > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(max(offset-limit, 0))))
+	}
+	if offset+limit < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(offset+limit)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastOffset)))
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+// weakPasswordDetails lists the password requirements that weren't met, for the 400 response body.
+func weakPasswordDetails(err error) []string {
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr.Unmet
+	}
+	return []string{err.Error()}
 }
 
 // MapUserModelToUserResponse converts a models.User to a dto.UserResponse
@@ -41,41 +182,217 @@ func MapUserModelToUserResponse(user *models.User) dto.UserResponse {
 		ID:        user.ID,
 		Name:      user.Name,
 		Email:     user.Email,
+		Verified:  user.Verified,
+		Role:      string(user.Role),
+		AvatarURL: user.AvatarURL,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
 }
 
+// MapUserModelToPublicUserResponse converts a models.User to the dto.PublicUserResponse shown to unrelated users
+func MapUserModelToPublicUserResponse(user *models.User) dto.PublicUserResponse {
+	return dto.PublicUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
 // MapJobModelToJobResponse converts a models.Job to a dto.JobResponse
 func MapJobModelToJobResponse(job *models.Job) dto.JobResponse {
 	// ... (implementation from previous step) ...
 	resp := dto.JobResponse{
 		ID:              job.ID,
 		Rate:            job.Rate,
+		Currency:        job.Currency,
+		FormattedRate:   money.Format(job.Rate, job.Currency),
 		Duration:        job.Duration,
 		ContractorID:    job.ContractorID,
 		EmployerID:      job.EmployerID,
 		State:           string(job.State), // Convert enum to string
 		InvoiceInterval: job.InvoiceInterval,
+		Description:     job.Description,
+		ApplicationDeadline: job.ApplicationDeadline,
+		AutoInvoice:         job.AutoInvoice,
+		AutoInvoiceCadence:  string(job.AutoInvoiceCadence),
+		NextAutoInvoiceAt:   job.NextAutoInvoiceAt,
+		Version:         job.Version,
+		Skills:          job.Skills,
+		CreatedBy:       job.CreatedBy,
+		UpdatedBy:       job.UpdatedBy,
 		CreatedAt:       job.CreatedAt,
 		UpdatedAt:       job.UpdatedAt,
 	}
+	if resp.Skills == nil {
+		resp.Skills = []string{} // Always an array, so clients don't need a null check
+	}
 	return resp
 }
 
+// MapEmployerStatsToResponse converts a models.EmployerStats to a dto.EmployerStatsResponse
+func MapEmployerStatsToResponse(stats *models.EmployerStats) dto.EmployerStatsResponse {
+	jobsByState := make(map[string]int, len(stats.JobsByState))
+	for state, count := range stats.JobsByState {
+		jobsByState[string(state)] = count
+	}
+	return dto.EmployerStatsResponse{
+		JobsByState:   jobsByState,
+		TotalJobs:     stats.TotalJobs,
+		AverageRate:   stats.AverageRate,
+		TotalInvoiced: stats.TotalInvoiced,
+	}
+}
+
+// MapContractorDashboardToResponse converts a models.ContractorDashboard to a dto.ContractorDashboardResponse
+func MapContractorDashboardToResponse(dashboard *models.ContractorDashboard) dto.ContractorDashboardResponse {
+	response := dto.ContractorDashboardResponse{
+		ActiveJobs:               make([]dto.JobResponse, 0, len(dashboard.ActiveJobs)),
+		TotalActiveJobs:          dashboard.TotalActiveJobs,
+		PendingApplications:      make([]dto.JobApplicationResponse, 0, len(dashboard.PendingApplications)),
+		TotalPendingApplications: dashboard.TotalPendingApplications,
+		UnpaidInvoices: dto.UnpaidInvoiceTotalsResponse{
+			Count:        dashboard.UnpaidInvoices.Count,
+			Total:        dashboard.UnpaidInvoices.Total,
+			OverdueTotal: dashboard.UnpaidInvoices.OverdueTotal,
+		},
+		Partial:        dashboard.Partial,
+		FailedSections: append([]string{}, dashboard.FailedSections...),
+	}
+	for i := range dashboard.ActiveJobs {
+		response.ActiveJobs = append(response.ActiveJobs, MapJobModelToJobResponse(&dashboard.ActiveJobs[i]))
+	}
+	for i := range dashboard.PendingApplications {
+		response.PendingApplications = append(response.PendingApplications, MapJobApplicationModelToResponse(&dashboard.PendingApplications[i]))
+	}
+	return response
+}
+
 // MapInvoiceModelToInvoiceResponse converts a models.Invoice to a dto.InvoiceResponse
 func MapInvoiceModelToInvoiceResponse(invoice *models.Invoice) dto.InvoiceResponse {
 	return dto.InvoiceResponse{
 		ID:             invoice.ID,
+		InvoiceNumber:  invoice.InvoiceNumber,
 		Value:          invoice.Value,
+		Currency:       invoice.Currency,
+		FormattedValue: money.Format(invoice.Value, invoice.Currency),
 		State:          string(invoice.State), // Convert enum to string
 		JobID:          invoice.JobID,
 		IntervalNumber: invoice.IntervalNumber,
+		PaidAt:         invoice.PaidAt,
+		DueDate:        invoice.DueDate,
+		IsOverdue:      invoice.IsOverdue(time.Now()),
+		CreatedBy:      invoice.CreatedBy,
+		UpdatedBy:      invoice.UpdatedBy,
 		CreatedAt:      invoice.CreatedAt,
 		UpdatedAt:      invoice.UpdatedAt,
 	}
 }
 
+// MapJobDeleteBlockersToResponse converts the blockers returned by CanDeleteJob to a dto.JobDeletableResponse
+func MapJobDeleteBlockersToResponse(jobID uuid.UUID, blockers []models.JobDeleteBlocker) dto.JobDeletableResponse {
+	response := dto.JobDeletableResponse{JobID: jobID, Deletable: len(blockers) == 0, Blockers: []dto.JobDeleteBlockerResponse{}}
+	for _, blocker := range blockers {
+		response.Blockers = append(response.Blockers, dto.JobDeleteBlockerResponse{Code: blocker.Code, Message: blocker.Message})
+	}
+	return response
+}
+
+// MapJobStateMachineToResponse converts a models.JobStateMachine to a dto.JobStateMachineResponse
+func MapJobStateMachineToResponse(machine *models.JobStateMachine) dto.JobStateMachineResponse {
+	response := dto.JobStateMachineResponse{States: machine.States, Transitions: []dto.JobStateTransitionResponse{}}
+	for _, transition := range machine.Transitions {
+		response.Transitions = append(response.Transitions, dto.JobStateTransitionResponse{
+			From:      transition.From,
+			To:        transition.To,
+			AllowedBy: transition.AllowedBy,
+			Note:      transition.Note,
+		})
+	}
+	return response
+}
+
+// MapJobBalanceToResponse converts a models.JobBalance to a dto.JobBalanceResponse
+func MapJobBalanceToResponse(balance *models.JobBalance) dto.JobBalanceResponse {
+	return dto.JobBalanceResponse{
+		JobID:         balance.JobID,
+		TotalValue:    balance.TotalValue,
+		TotalInvoiced: balance.TotalInvoiced,
+		TotalPaid:     balance.TotalPaid,
+		Remaining:     balance.Remaining,
+		Currency:      balance.Currency,
+	}
+}
+
+// MapRatingModelToResponse converts a models.Rating to a dto.RatingResponse
+func MapRatingModelToResponse(rating *models.Rating) dto.RatingResponse {
+	return dto.RatingResponse{
+		ID:        rating.ID,
+		JobID:     rating.JobID,
+		RaterID:   rating.RaterID,
+		RateeID:   rating.RateeID,
+		Score:     rating.Score,
+		Comment:   rating.Comment,
+		CreatedAt: rating.CreatedAt,
+	}
+}
+
+// MapUserRatingSummaryToResponse converts a models.UserRatingSummary to a dto.UserRatingResponse
+func MapUserRatingSummaryToResponse(summary *models.UserRatingSummary) dto.UserRatingResponse {
+	return dto.UserRatingResponse{
+		UserID:       summary.UserID,
+		AverageScore: summary.AverageScore,
+		RatingCount:  summary.RatingCount,
+	}
+}
+
+// MapAPIKeyModelToResponse converts a models.APIKey to a dto.APIKeyResponse
+func MapAPIKeyModelToResponse(key *models.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:        key.ID,
+		Owner:     key.Owner,
+		Scopes:    key.Scopes,
+		CreatedBy: key.CreatedBy,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// MapWebhookModelToResponse converts a models.Webhook to a dto.WebhookResponse
+func MapWebhookModelToResponse(webhook *models.Webhook) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:         webhook.ID,
+		Owner:      webhook.Owner,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+		CreatedBy:  webhook.CreatedBy,
+		CreatedAt:  webhook.CreatedAt,
+		UpdatedAt:  webhook.UpdatedAt,
+	}
+}
+
+// MapWebhookDeliveryModelToResponse converts a models.WebhookDelivery to a dto.WebhookDeliveryResponse
+func MapWebhookDeliveryModelToResponse(delivery *models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	resp := dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return resp
+}
+
 // MapJobApplicationModelToResponse converts a models.JobApplication to a dto.JobApplicationResponse
 func MapJobApplicationModelToResponse(app *models.JobApplication) dto.JobApplicationResponse {
 	return dto.JobApplicationResponse{
@@ -83,7 +400,17 @@ func MapJobApplicationModelToResponse(app *models.JobApplication) dto.JobApplica
 		ContractorID: app.ContractorID,
 		JobID:        app.JobID,
 		State:        app.State, // Assuming JobApplicationState is already a string or has a String() method
-		CreatedAt:    app.CreatedAt.Format(time.RFC3339), // Format time for consistency
-		UpdatedAt:    app.UpdatedAt.Format(time.RFC3339), // Format time for consistency
+		CoverMessage: app.CoverMessage,
+		ProposedRate: app.ProposedRate,
+		CreatedAt:    app.CreatedAt,
+		UpdatedAt:    app.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+
+// MapAppliedJobToResponse converts a models.AppliedJob to a dto.AppliedJobResponse
+func MapAppliedJobToResponse(applied *models.AppliedJob) dto.AppliedJobResponse {
+	return dto.AppliedJobResponse{
+		Job:         MapJobModelToJobResponse(&applied.Job),
+		Application: MapJobApplicationModelToResponse(&applied.Application),
+	}
+}
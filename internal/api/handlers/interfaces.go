@@ -5,13 +5,23 @@ import "github.com/gin-gonic/gin"
 // UserHandlerInterface defines the methods needed by the user routes.
 type UserHandlerInterface interface {
 	GetUserByID(c *gin.Context)
+	GetMe(c *gin.Context)
+	GetMyPermissions(c *gin.Context)
+	BatchGetUsers(c *gin.Context)
 	Login(c *gin.Context)
 	GetUsers(c *gin.Context)
 	Register(c *gin.Context)
 	UpdateUser(c *gin.Context)
 	DeleteUser(c *gin.Context)
+	RestoreUser(c *gin.Context)
+	ImpersonateUser(c *gin.Context)
 	Refresh(c *gin.Context)
 	Logout(c *gin.Context)
+	RequestPasswordReset(c *gin.Context)
+	ConfirmPasswordReset(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	ResendVerificationEmail(c *gin.Context)
 }
 
 // JobHandlerInterface defines the methods needed by the job routes.
@@ -23,7 +33,19 @@ type JobHandlerInterface interface {
 	ListContractorJobs(c *gin.Context) // Handler for contractor's own jobs
 	UpdateJobDetails(c *gin.Context)   // For Rate/Duration by Employer (before assignment)
 	UpdateJobState(c *gin.Context)
+	GetJobStateMachine(c *gin.Context) // Documents the transitions UpdateJobState accepts
+	TransferJob(c *gin.Context) // Employer only, before a contractor is assigned
+	ReassignContractor(c *gin.Context) // Employer only; swaps the contractor of an Ongoing job
+	ReopenJob(c *gin.Context) // Employer only; Complete back to Ongoing
+	CancelJob(c *gin.Context) // Employer only; Ongoing to Cancelled
+	SetJobAutoInvoice(c *gin.Context)
 	DeleteJob(c *gin.Context)
+	CanDeleteJob(c *gin.Context) // Dry run of DeleteJob
+	RestoreJob(c *gin.Context) // Admin only
+	GetEmployerStats(c *gin.Context)
+	SaveJob(c *gin.Context)
+	UnsaveJob(c *gin.Context)
+	ListSavedJobs(c *gin.Context) // Saved jobs of the authenticated user
 }
 
 // JobApplicationHandlerInterface defines methods for job application routes.
@@ -31,23 +53,90 @@ type JobApplicationHandlerInterface interface {
 	ApplyToJob(c *gin.Context)
 	GetApplicationByID(c *gin.Context)
 	ListApplicationsByContractor(c *gin.Context)
+	ListAppliedJobs(c *gin.Context) // Jobs the authenticated user applied to, with their application
 	ListApplicationsByJob(c *gin.Context)
+	ExportApplicationsCSV(c *gin.Context)
 	AcceptApplication(c *gin.Context)
 	RejectApplication(c *gin.Context)
+	BulkRejectApplications(c *gin.Context)
 	WithdrawApplication(c *gin.Context)
+	InviteContractor(c *gin.Context)
+	AcceptInvitation(c *gin.Context)
+	DeclineInvitation(c *gin.Context)
 }
 
 // InvoiceHandlerInterface defines the methods needed by the invoice routes.
 type InvoiceHandlerInterface interface {
 	CreateInvoice(c *gin.Context) // Will handle calculation logic
+	GenerateAllInvoices(c *gin.Context)
 	GetInvoiceByID(c *gin.Context)
-	ListInvoicesByJob(c *gin.Context)
+	ListInvoicesByJob(c *gin.Context) // JSON, or CSV for Accept: text/csv
+	ListEmployerInvoices(c *gin.Context) // JSON, or CSV for Accept: text/csv
+	StreamJobInvoices(c *gin.Context)
+	GetJobBalance(c *gin.Context)
+	ListOverdueInvoices(c *gin.Context)
 	UpdateInvoiceState(c *gin.Context)
+	MarkInvoicePaid(c *gin.Context)
 	DeleteInvoice(c *gin.Context)
 }
 
+// RatingHandlerInterface defines the methods needed by the rating routes.
+type RatingHandlerInterface interface {
+	SubmitRating(c *gin.Context)
+	GetUserRating(c *gin.Context)
+}
+
+// DashboardHandlerInterface defines the methods needed by the dashboard routes.
+type DashboardHandlerInterface interface {
+	GetMyDashboard(c *gin.Context)
+}
+
+// AvatarHandlerInterface defines the methods needed by the avatar routes.
+type AvatarHandlerInterface interface {
+	UploadMyAvatar(c *gin.Context)
+	DeleteMyAvatar(c *gin.Context)
+}
+
+// NotificationHandlerInterface defines the methods needed by the notification routes.
+type NotificationHandlerInterface interface {
+	Subscribe(c *gin.Context)
+}
+
+// HealthHandlerInterface defines the methods needed by the health routes.
+type HealthHandlerInterface interface {
+	Readiness(c *gin.Context)
+}
+
+// DebugHandlerInterface defines methods for the admin diagnostics routes.
+type DebugHandlerInterface interface {
+	DBStats(c *gin.Context)
+}
+
+// APIKeyHandlerInterface defines methods for the admin API key routes.
+type APIKeyHandlerInterface interface {
+	CreateAPIKey(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+}
+
+// WebhookHandlerInterface defines methods for the admin webhook routes.
+type WebhookHandlerInterface interface {
+	CreateWebhook(c *gin.Context)
+	ListWebhooks(c *gin.Context)
+	UpdateWebhook(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	ListWebhookDeliveries(c *gin.Context)
+}
+
 // Ensure handlers implements the interface (compile-time check)
 var _ UserHandlerInterface = (*UserHandler)(nil)
 var _ JobHandlerInterface = (*JobHandler)(nil)
 var _ JobApplicationHandlerInterface = (*JobApplicationHandler)(nil) // Add this when handler is created
-var _ InvoiceHandlerInterface = (*InvoiceHandler)(nil)
\ No newline at end of file
+var _ InvoiceHandlerInterface = (*InvoiceHandler)(nil)
+var _ RatingHandlerInterface = (*RatingHandler)(nil)
+var _ DashboardHandlerInterface = (*DashboardHandler)(nil)
+var _ AvatarHandlerInterface = (*AvatarHandler)(nil)
+var _ NotificationHandlerInterface = (*NotificationHandler)(nil)
+var _ HealthHandlerInterface = (*HealthHandler)(nil)
+var _ DebugHandlerInterface = (*DebugHandler)(nil)
+var _ APIKeyHandlerInterface = (*APIKeyHandler)(nil)
+var _ WebhookHandlerInterface = (*WebhookHandler)(nil)
@@ -1,12 +1,18 @@
 package handlers
 
 import (
-	"errors"
+	"encoding/csv"
+	"fmt"
 	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
+	"go-api-template/internal/notifications"
 	"go-api-template/internal/services"
 	"go-api-template/internal/transport/dto"
-	"log"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/money"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
@@ -17,6 +23,8 @@ import (
 type InvoiceHandler struct {
 	service services.InvoiceService
 	validator   *validator.Validate
+	maxPageSize int // Largest limit a list request may use; larger ones are clamped
+	streams     *notifications.JobStreams // Serves StreamJobInvoices; nil disables it
 }
 
 // NewInvoiceHandler creates a new InvoiceHandler.
@@ -24,66 +32,126 @@ func NewInvoiceHandler(service services.InvoiceService, validate *validator.Vali
 	return &InvoiceHandler{
 		service: service,
 		validator:   validate,
+		maxPageSize: dto.DefaultMaxPageLimit,
 	}
 }
 
+// WithMaxPageSize sets the largest limit ListInvoicesByJob accepts. Values below 1 keep the default.
+func (h *InvoiceHandler) WithMaxPageSize(n int) *InvoiceHandler {
+	if n > 0 {
+		h.maxPageSize = n
+	}
+	return h
+}
+
+// WithJobStreams sets the broker StreamJobInvoices serves invoice state changes from.
+func (h *InvoiceHandler) WithJobStreams(streams *notifications.JobStreams) *InvoiceHandler {
+	h.streams = streams
+	return h
+}
+
 // CreateInvoice godoc
 // @Summary      Create an invoice for a job
-// @Description  Creates the next sequential invoice for a specified job, calculating value based on job rate/interval and applying optional adjustment. Handles partial final intervals. Requires user to be the assigned contractor and job to be 'Ongoing'.
+// @Description  Creates the next sequential invoice for a specified job, calculating value based on job rate/interval and applying optional adjustment. Handles partial final intervals. Requires user to be the assigned contractor and job to be 'Ongoing'. Send `interval_number` to make the request replay-safe: if that interval is already invoiced, e.g. by a retry or a concurrent request, the create fails with 409 instead of billing the next one.
 // @Tags         invoices
 // @Accept       json
 // @Produce      json
-// @Param        invoice body      dto.CreateInvoiceRequest true  "Invoice creation details (JobID and optional Adjustment)"
+// @Param        invoice body      dto.CreateInvoiceRequest true  "Invoice creation details (JobID, optional Adjustment and optional IntervalNumber)"
+// @Param        Idempotency-Key header string false "Makes retries safe: a repeated key replays the first successful response"
 // @Success      201 {object}  dto.InvoiceResponse "Invoice created successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid input, job not found, or invoice not allowed (e.g., max intervals reached)"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not the contractor for this job or job not ongoing"
-// @Failure      409 {object}  map[string]string "Conflict - Invoice for this interval already exists"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Header       201 {string} Location "URL of the created resource"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input, job not found, invoice not allowed (e.g., max intervals reached), an adjustment that makes the value negative or exceeds the allowed percentage of the base value, or an interval_number ahead of the next interval"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the contractor for this job or job not ongoing"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Invoice for this interval already exists, or a request with the same Idempotency-Key is still being processed"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /invoices [post]
 // @Security     BearerAuth
 func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("CreateInvoice: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("CreateInvoice: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	var req dto.CreateInvoiceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 	req.UserId = userID
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 
 	createdInvoice, err := h.service.CreateInvoice(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrConflict) {
-			c.JSON(http.StatusConflict, gin.H{"error": "Invoice for this interval already exists"})
-		} else if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "User is not the contractor for this job or job not ongoing"})
-		} else if errors.Is(err, services.ErrInvalidState) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Job is not in a valid state for invoice creation"})
-		} else if errors.Is(err, services.ErrInvalidInvoiceInterval) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice interval exceeds job duration"})
-		} else {
-			log.Printf("CreateInvoice: Error saving invoice for job %s: %v", req.JobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invoice"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrConflict:               "Invoice for this interval already exists",
+			services.ErrNotFound:               "Job not found",
+			services.ErrForbidden:              "User is not the contractor for this job or job not ongoing",
+			services.ErrInvalidState:           "Job is not in a valid state for invoice creation",
+			services.ErrInvalidInvoiceInterval: "Invoice interval exceeds job duration",
+		})
 		return
 	}
 
 	// 10. Map and Return
-	c.JSON(http.StatusCreated, MapInvoiceModelToInvoiceResponse(createdInvoice))
+	respondCreated(c, "/invoices/"+createdInvoice.ID.String(), MapInvoiceModelToInvoiceResponse(createdInvoice))
+}
+
+// GenerateAllInvoices godoc
+// @Summary      Generate all outstanding invoices for a job
+// @Description  Creates an invoice for every interval of the job that hasn't been invoiced yet, in a single transaction. Existing intervals are skipped. Requires user to be the assigned contractor and job to be 'Ongoing'.
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Job ID" Format(uuid)
+// @Param        Idempotency-Key header string false "Makes retries safe: a repeated key replays the first successful response"
+// @Success      201 {array}   dto.InvoiceResponse "Invoices created successfully (empty if every interval was already invoiced)"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID format or job has no valid invoice interval"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the contractor for this job or job not ongoing"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - An interval was invoiced concurrently, or a request with the same Idempotency-Key is still being processed"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/invoices/generate [post]
+// @Security     BearerAuth
+func (h *InvoiceHandler) GenerateAllInvoices(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("GenerateAllInvoices: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+	req := dto.GenerateAllInvoicesRequest{JobID: jobID, UserId: userID}
+
+	createdInvoices, err := h.service.GenerateAllInvoices(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrConflict:               "An interval was invoiced concurrently, please retry",
+			services.ErrNotFound:               "Job not found",
+			services.ErrForbidden:              "User is not the contractor for this job",
+			services.ErrInvalidState:           "Job is not in a valid state for invoice creation",
+			services.ErrInvalidInvoiceInterval: "Job has no valid invoice interval",
+		})
+		return
+	}
+
+	invoiceResponses := make([]dto.InvoiceResponse, 0, len(createdInvoices))
+	for _, invoice := range createdInvoices {
+		invoiceResponses = append(invoiceResponses, MapInvoiceModelToInvoiceResponse(&invoice))
+	}
+	c.JSON(http.StatusCreated, invoiceResponses)
 }
 
 // GetInvoiceByID godoc
@@ -93,20 +161,22 @@ func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id path      string true  "Invoice ID" Format(uuid)
+// @Param        If-None-Match header string false "ETag from a previous response"
 // @Success      200 {object}  dto.InvoiceResponse "Successfully retrieved invoice"
-// @Failure      400 {object}  map[string]string "Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User not associated with this invoice's job"
-// @Failure      404 {object}  map[string]string "Invoice Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Success      304 "Not Modified - The ETag in If-None-Match is still current"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User not associated with this invoice's job"
+// @Failure      404 {object}  dto.ErrorResponse "Invoice Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /invoices/{id} [get]
 // @Security     BearerAuth
 func (h *InvoiceHandler) GetInvoiceByID(c *gin.Context) {
 	// Get UserID from auth context
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("GetInvoiceByID: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("GetInvoiceByID: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
@@ -114,7 +184,7 @@ func (h *InvoiceHandler) GetInvoiceByID(c *gin.Context) {
 	idStr := c.Param("id")
 	invoiceID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID format"})
+		respondError(c, badRequest("Invalid invoice ID format"))
 		return
 	}
 
@@ -123,48 +193,49 @@ func (h *InvoiceHandler) GetInvoiceByID(c *gin.Context) {
 
 	invoice, err := h.service.GetInvoiceByID(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "User not associated with this invoice's job"})
-		}else {
-			log.Printf("GetInvoiceByID: Error fetching invoice %s: %v", invoiceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoice"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Invoice not found",
+			services.ErrForbidden: "User not associated with this invoice's job",
+		})
 		return
 	}
 
 	// Map result to dto.InvoiceResponse
 	invoiceResponse := MapInvoiceModelToInvoiceResponse(invoice)
 
-	// Return JSON response
-	c.JSON(http.StatusOK, invoiceResponse)
+	// Return JSON response, or 304 if the client's copy is current
+	respondWithETag(c, invoiceResponse)
 }
 
 // ListInvoicesByJob godoc
 // @Summary      List invoices for a specific job
-// @Description  Retrieves a list of invoices associated with a given job ID. Requires user to be associated with the job. Supports filtering and pagination.
+// @Description  Retrieves a list of invoices associated with a given job ID. Requires user to be associated with the job. Supports filtering and pagination. With `Accept: text/csv` every invoice matching the filters is streamed as a CSV file instead (invoice number, job, interval, value, currency, state and dates), ignoring limit and offset.
 // @Tags         invoices
 // @Accept       json
-// @Produce      json
+// @Produce      json,text/csv
 // @Param        id path string true "Job ID" Format(uuid)
-// @Param        limit query int false "Pagination limit" default(10)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
 // @Param        offset query int false "Pagination offset" default(0)
 // @Param        state query string false "Filter by state (Waiting, Complete)" Enums(Waiting, Complete)
-// @Success      200 {array}   dto.InvoiceResponse "Successfully retrieved list of invoices"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid Job ID format or query parameters"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User not associated with this job"
-// @Failure      404 {object}  map[string]string "Job Not Found" // Check if job exists first
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Param        min_interval query int false "Only invoices of this interval number or later" minimum(1)
+// @Param        max_interval query int false "Only invoices of this interval number or earlier" minimum(1)
+// @Param        updated_since query string false "Only invoices changed after this RFC 3339 time, ordered by updated_at ascending" Format(date-time)
+// @Success      200 {object}  dto.PaginatedResponse[dto.InvoiceResponse] "Successfully retrieved list of invoices"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID format or query parameters, or min_interval greater than max_interval"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User not associated with this job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found" // Check if job exists first
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{jobId}/invoices [get] // Example route nesting
 // @Security     BearerAuth
 func (h *InvoiceHandler) ListInvoicesByJob(c *gin.Context) {
 	// Get UserID from auth context
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("ListInvoicesByJob: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("ListInvoicesByJob: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
@@ -172,38 +243,40 @@ func (h *InvoiceHandler) ListInvoicesByJob(c *gin.Context) {
 	jobIdStr := c.Param("id") // Matches route param name
 	jobID, err := uuid.Parse(jobIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
 	// Bind/Validate query params into dto.ListInvoicesByJobRequest
 	var req dto.ListInvoicesByJobRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
 		return
 	}
 	req.JobID = jobID // Set JobID from path
 	req.UserId = userID
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
-	if req.Limit <= 0 { req.Limit = 10 }
-	if req.Offset < 0 { req.Offset = 0 }
 
+	messages := errorMessages{
+		services.ErrNotFound:  "Job not found",
+		services.ErrForbidden: "User not associated with this job",
+	}
+	c.Header("Vary", "Accept") // The same URL answers JSON or CSV
+	if wantsCSV(c) {
+		respondInvoicesCSV(c, fmt.Sprintf("job-%s-invoices.csv", jobID), func(fn func(invoice *models.Invoice) error) error {
+			return h.service.StreamInvoicesByJob(c.Request.Context(), &req, fn)
+		}, messages)
+		return
+	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
 
-	invoices, err := h.service.ListInvoicesByJob(c.Request.Context(), &req)
+	invoices, total, err := h.service.ListInvoicesByJob(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "User not associated with this job"})
-		} else {
-			log.Printf("ListInvoicesByJob: Error listing invoices for job %s: %v", jobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
-		}
+		respondError(c, err, messages)
 		return
 	}
 
@@ -214,6 +287,257 @@ func (h *InvoiceHandler) ListInvoicesByJob(c *gin.Context) {
 	}
 
 	// Return JSON response
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(invoiceResponses, total, req.Limit, req.Offset))
+}
+
+// ListEmployerInvoices godoc
+// @Summary      List my invoices as employer
+// @Description  Lists the invoices on all of the current user's jobs as employer, newest first, with an optional state filter. Invoices of deleted jobs are left out. With `Accept: text/csv` every matching invoice is streamed as a CSV file instead (invoice number, job, interval, value, currency, state and dates), ignoring limit and offset.
+// @Tags         invoices
+// @Produce      json,text/csv
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
+// @Param        offset query int false "Pagination offset" default(0)
+// @Param        state query string false "Filter by state" Enums(Waiting, Complete, Paid)
+// @Success      200 {object}  dto.PaginatedResponse[dto.InvoiceResponse] "Successfully retrieved invoices, or a CSV file of them"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters (JSON only)"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages (JSON only)"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/invoices [get]
+// @Security     BearerAuth
+func (h *InvoiceHandler) ListEmployerInvoices(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("ListEmployerInvoices: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	var req dto.ListEmployerInvoicesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, badRequest("Invalid query parameters: "+err.Error()))
+		return
+	}
+	req.EmployerID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	c.Header("Vary", "Accept") // The same URL answers JSON or CSV
+	if wantsCSV(c) {
+		respondInvoicesCSV(c, "invoices.csv", func(fn func(invoice *models.Invoice) error) error {
+			return h.service.StreamEmployerInvoices(c.Request.Context(), &req, fn)
+		}, nil)
+		return
+	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
+
+	invoices, total, err := h.service.ListEmployerInvoices(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoiceResponses := make([]dto.InvoiceResponse, 0, len(invoices))
+	for _, invoice := range invoices {
+		invoiceResponses = append(invoiceResponses, MapInvoiceModelToInvoiceResponse(&invoice))
+	}
+
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(invoiceResponses, total, req.Limit, req.Offset))
+}
+
+// invoiceCSVHeader is the header row of invoice CSV exports.
+var invoiceCSVHeader = []string{"invoice_number", "job_id", "interval_number", "value", "currency", "state", "due_date", "paid_at", "created_at", "updated_at"}
+
+// invoiceCSVRecord renders an invoice as a row under invoiceCSVHeader. Values have the decimals of their
+// currency, times are RFC 3339 in UTC and paid_at is empty until the invoice is paid.
+func invoiceCSVRecord(invoice *models.Invoice) []string {
+	paidAt := ""
+	if invoice.PaidAt != nil {
+		paidAt = invoice.PaidAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		invoice.InvoiceNumber,
+		invoice.JobID.String(),
+		strconv.Itoa(invoice.IntervalNumber),
+		money.FormatAmount(invoice.Value, invoice.Currency),
+		invoice.Currency,
+		string(invoice.State),
+		invoice.DueDate.UTC().Format(time.RFC3339),
+		paidAt,
+		invoice.CreatedAt.UTC().Format(time.RFC3339),
+		invoice.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// respondInvoicesCSV writes the invoices stream yields as a CSV attachment named filename, as they are
+// read rather than all at once. An error before anything reached the client is answered as JSON with messages.
+func respondInvoicesCSV(c *gin.Context, filename string, stream func(fn func(invoice *models.Invoice) error) error, messages errorMessages) {
+	// Headers are only sent on the first write, so errors before any row is written can still be reported as JSON
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	cw := csv.NewWriter(c.Writer) // Buffers a few KB at a time, so long exports go out in chunks
+	headerWritten := false
+	writeHeader := func() error {
+		headerWritten = true
+		return cw.Write(invoiceCSVHeader)
+	}
+
+	err := stream(func(invoice *models.Invoice) error {
+		if !headerWritten {
+			if err := writeHeader(); err != nil {
+				return err
+			}
+		}
+		return cw.Write(invoiceCSVRecord(invoice))
+	})
+	if err == nil && !headerWritten {
+		err = writeHeader() // No invoices match; the file still has its header
+	}
+	if err != nil {
+		if c.Writer.Written() {
+			// Part of the file is already on the wire; all we can do is log and cut the stream short
+			logger.FromContext(c.Request.Context()).Error("Error while streaming invoices as CSV", "error", err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		respondError(c, err, messages)
+		return
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Error flushing invoice CSV", "error", err)
+	}
+}
+
+// StreamJobInvoices godoc
+// @Summary      Stream invoice state changes for a job
+// @Description  Opens a server-sent events stream that receives an invoice.state_changed event whenever an invoice of the job changes state (e.g. Waiting to Complete, or paid). A keep-alive comment is sent periodically. Requires user to be associated with the job.
+// @Tags         invoices
+// @Produce      text/event-stream
+// @Param        id path string true "Job ID" Format(uuid)
+// @Success      200 {string}  string "Event stream"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User not associated with this job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/invoices/stream [get]
+// @Security     BearerAuth
+func (h *InvoiceHandler) StreamJobInvoices(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("StreamJobInvoices: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	if h.streams == nil {
+		respondError(c, notFound("Invoice streaming is not enabled"))
+		return
+	}
+
+	req := dto.StreamJobInvoicesRequest{JobID: jobID, UserID: userID}
+	if err := h.service.AuthorizeInvoiceStream(c.Request.Context(), &req); err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "User not associated with this job",
+		})
+		return
+	}
+
+	// Blocks until the client disconnects; headers are already sent, so errors can only be logged
+	if err := h.streams.ServeSSE(c.Writer, c.Request, jobID); err != nil {
+		logger.FromContext(c.Request.Context()).Info("Invoice stream ended", "job_id", jobID, "error", err)
+	}
+}
+
+// GetJobBalance godoc
+// @Summary      Get job balance
+// @Description  Returns the total invoiced and paid on a job, and the projected amount left to invoice (Rate x Duration minus invoiced). Requires user to be associated with the job.
+// @Tags         invoices
+// @Produce      json
+// @Param        id path string true "Job ID" Format(uuid)
+// @Success      200 {object}  dto.JobBalanceResponse "Successfully retrieved job balance"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User not associated with this job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/balance [get]
+// @Security     BearerAuth
+func (h *InvoiceHandler) GetJobBalance(c *gin.Context) {
+	// Get UserID from auth context
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("GetJobBalance: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	// Parse JobID from path
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	req := dto.GetJobBalanceRequest{JobID: jobID, UserId: userID}
+
+	balance, err := h.service.GetJobBalance(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "User not associated with this job",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobBalanceToResponse(balance))
+}
+
+// ListOverdueInvoices godoc
+// @Summary      List my overdue invoices
+// @Description  Lists the invoices on the current user's jobs (as employer) that were marked Complete but are still unpaid after their due date, oldest due first.
+// @Tags         invoices
+// @Produce      json
+// @Success      200 {array}   dto.InvoiceResponse "Successfully retrieved overdue invoices"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/overdue-invoices [get]
+// @Security     BearerAuth
+func (h *InvoiceHandler) ListOverdueInvoices(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("ListOverdueInvoices: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	invoices, err := h.service.ListOverdueInvoices(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoiceResponses := make([]dto.InvoiceResponse, 0, len(invoices))
+	for _, invoice := range invoices {
+		invoiceResponses = append(invoiceResponses, MapInvoiceModelToInvoiceResponse(&invoice))
+	}
 	c.JSON(http.StatusOK, invoiceResponses)
 }
 
@@ -226,19 +550,19 @@ func (h *InvoiceHandler) ListInvoicesByJob(c *gin.Context) {
 // @Param        id path      string true  "Invoice ID" Format(uuid)
 // @Param        state body      dto.UpdateInvoiceStateRequest true  "New state for the invoice"
 // @Success      200 {object}  dto.InvoiceResponse "Invoice state updated successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid input or state transition not allowed"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not the contractor for this invoice's job"
-// @Failure      404 {object}  map[string]string "Invoice Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input or state transition not allowed"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the contractor for this invoice's job"
+// @Failure      404 {object}  dto.ErrorResponse "Invoice Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /invoices/{id}/state [patch]
 // @Security     BearerAuth
 func (h *InvoiceHandler) UpdateInvoiceState(c *gin.Context) {
 	// Get UserID
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("UpdateInvoiceState: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("UpdateInvoiceState: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
@@ -246,37 +570,30 @@ func (h *InvoiceHandler) UpdateInvoiceState(c *gin.Context) {
 	idStr := c.Param("id")
 	invoiceID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID format"})
+		respondError(c, badRequest("Invalid invoice ID format"))
 		return
 	}
 
 	// Bind/Validate Request Body
 	var req dto.UpdateInvoiceStateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 	req.ID = invoiceID // Set ID from path
 	req.UserId = userID
 	
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 
 	updatedInvoice, err := h.service.UpdateInvoiceState(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found during update"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "User is not the contractor for this invoice's job"})
-		} else if errors.Is(err, services.ErrInvalidTransition) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state transition"})
-		} else {
-			log.Printf("UpdateInvoiceState: Error updating invoice state %s: %v", invoiceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice state"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Invoice not found during update",
+			services.ErrForbidden: "User is not the contractor for this invoice's job",
+		})
 		return
 	}
 
@@ -285,6 +602,60 @@ func (h *InvoiceHandler) UpdateInvoiceState(c *gin.Context) {
 }
 
 
+// MarkInvoicePaid godoc
+// @Summary      Mark invoice as paid
+// @Description  Records payment of a 'Complete' invoice, moving it to 'Paid'. ONLY allowed by the job's employer, or under /service by a machine client whose API key has the 'invoices:pay' scope.
+// @Tags         invoices
+// @Produce      json
+// @Param        id path      string true  "Invoice ID" Format(uuid)
+// @Success      200 {object}  dto.InvoiceResponse "Invoice marked as paid"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID or invoice is not Complete"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer for this invoice's job"
+// @Failure      404 {object}  dto.ErrorResponse "Invoice Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /invoices/{id}/pay [post]
+// @Router       /service/invoices/{id}/pay [post]
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+func (h *InvoiceHandler) MarkInvoicePaid(c *gin.Context) {
+	// Get UserID; machine clients authenticate with an API key instead of a user token
+	var req dto.MarkInvoicePaidRequest
+	if _, err := middleware.GetServiceIdentityFromContext(c); err == nil {
+		req.ByService = true
+	} else {
+		userID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("MarkInvoicePaid: Error getting user ID from context", "error", err)
+			respondError(c, unauthorized("Unauthorized"))
+			return
+		}
+		req.UserId = userID
+	}
+
+	// Parse InvoiceID
+	idStr := c.Param("id")
+	invoiceID, err := uuid.Parse(idStr)
+	if err != nil {
+		respondError(c, badRequest("Invalid invoice ID format"))
+		return
+	}
+
+	req.ID = invoiceID
+
+	paidInvoice, err := h.service.MarkInvoicePaid(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:          "Invoice not found",
+			services.ErrForbidden:         "User is not the employer for this invoice's job",
+			services.ErrInvalidTransition: "Only Complete invoices can be marked as paid",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapInvoiceModelToInvoiceResponse(paidInvoice))
+}
+
 // DeleteInvoice godoc
 // @Summary      Delete an invoice
 // @Description  Deletes an invoice. Only allowed by the assigned contractor if the invoice state is 'Waiting'.
@@ -293,19 +664,19 @@ func (h *InvoiceHandler) UpdateInvoiceState(c *gin.Context) {
 // @Produce      json
 // @Param        id path      string true  "Invoice ID" Format(uuid)
 // @Success      204 {object}  nil "Invoice deleted successfully"
-// @Failure      400 {object}  map[string]string "Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not contractor or invoice state prevents deletion"
-// @Failure      404 {object}  map[string]string "Invoice Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not contractor or invoice state prevents deletion"
+// @Failure      404 {object}  dto.ErrorResponse "Invoice Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /invoices/{id} [delete]
 // @Security     BearerAuth
 func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
 	// Get UserID
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("DeleteInvoice: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("DeleteInvoice: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
@@ -313,7 +684,7 @@ func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
 	idStr := c.Param("id")
 	invoiceID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID format"})
+		respondError(c, badRequest("Invalid invoice ID format"))
 		return
 	}
 
@@ -322,16 +693,10 @@ func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
 
 	err = h.service.DeleteInvoice(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found during update"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "User is not the contractor for this invoice's job"})
-		} else if errors.Is(err, services.ErrInvalidTransition) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state transition"})
-		} else {
-			log.Printf("UpdateInvoiceState: Error updating invoice state %s: %v", invoiceID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice state"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Invoice not found during update",
+			services.ErrForbidden: "User is not the contractor for this invoice's job",
+		})
 		return
 	}
 
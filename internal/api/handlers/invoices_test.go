@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubInvoiceListService serves the job invoice list and its stream from invoices, or fails with err
+// before anything is streamed; any other method panics through the nil embedded interface.
+type stubInvoiceListService struct {
+	services.InvoiceService
+	invoices []models.Invoice
+	err      error
+	streamed bool // Whether the stream, rather than the page, was asked for
+}
+
+func (s *stubInvoiceListService) ListInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, int, error) {
+	if s.err != nil {
+		return nil, 0, s.err
+	}
+	return s.invoices, len(s.invoices), nil
+}
+
+func (s *stubInvoiceListService) StreamInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest, fn func(invoice *models.Invoice) error) error {
+	s.streamed = true
+	if s.err != nil {
+		return s.err
+	}
+	for i := range s.invoices {
+		if err := fn(&s.invoices[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestInvoiceHandler_ListInvoicesByJob_ContentNegotiation(t *testing.T) {
+	jobID, userID := uuid.New(), uuid.New()
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	paid := created.Add(48 * time.Hour)
+	invoices := []models.Invoice{
+		{ID: uuid.New(), InvoiceNumber: "INV-000001-2025", JobID: jobID, IntervalNumber: 1, Value: 500, Currency: "EUR", State: models.InvoiceStatePaid, PaidAt: &paid, DueDate: created.AddDate(0, 0, 30), CreatedAt: created, UpdatedAt: paid},
+		{ID: uuid.New(), InvoiceNumber: "INV-000002-2025", JobID: jobID, IntervalNumber: 2, Value: 1212.5, Currency: "EUR", State: models.InvoiceStateWaiting, DueDate: created.AddDate(0, 0, 37), CreatedAt: created.AddDate(0, 0, 7), UpdatedAt: created.AddDate(0, 0, 7)},
+	}
+
+	tests := []struct {
+		name           string
+		accept         string
+		serviceErr     error
+		expectedStatus int
+		expectedCSV    bool
+	}{
+		{name: "DefaultIsJSON", accept: "", expectedStatus: http.StatusOK},
+		{name: "AnyIsJSON", accept: "*/*", expectedStatus: http.StatusOK},
+		{name: "CSV", accept: "text/csv", expectedStatus: http.StatusOK, expectedCSV: true},
+		{name: "CSVPreferred", accept: "text/csv, application/json;q=0.5", expectedStatus: http.StatusOK, expectedCSV: true},
+		{name: "CSVForbiddenIsJSONError", accept: "text/csv", serviceErr: services.ErrForbidden, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubInvoiceListService{invoices: invoices, err: tt.serviceErr}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			authenticate := func(c *gin.Context) {
+				c.Set("userID", userID) // What JWTAuthMiddleware stores
+				c.Next()
+			}
+			router.GET("/jobs/:id/invoices", authenticate, NewInvoiceHandler(service, validator.New()).ListInvoicesByJob)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+jobID.String()+"/invoices?limit=1", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			if tt.serviceErr != nil {
+				assert.Contains(t, w.Header().Get("Content-Type"), "application/json", "Errors are reported as JSON, not as a broken CSV file")
+				assert.Empty(t, w.Header().Get("Content-Disposition"))
+				var body dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, dto.ErrorCodeForbidden, body.Code)
+				return
+			}
+			assert.Equal(t, tt.expectedCSV, service.streamed)
+
+			if !tt.expectedCSV {
+				assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+				var page dto.PaginatedResponse[dto.InvoiceResponse]
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+				assert.Equal(t, 2, page.Total)
+				require.Len(t, page.Items, 2) // The stub ignores the limit
+				assert.Equal(t, "EUR", page.Items[1].Currency)
+				assert.Equal(t, "€1,212.50", page.Items[1].FormattedValue)
+				return
+			}
+
+			assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="job-`+jobID.String()+`-invoices.csv"`, w.Header().Get("Content-Disposition"))
+			assert.Empty(t, w.Header().Get("X-Total-Count"), "Exports aren't paginated")
+
+			records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, 3, "The header and every invoice, whatever the limit")
+			assert.Equal(t, []string{"invoice_number", "job_id", "interval_number", "value", "currency", "state", "due_date", "paid_at", "created_at", "updated_at"}, records[0])
+			assert.Equal(t, []string{"INV-000001-2025", jobID.String(), "1", "500.00", "EUR", "Paid", "2025-03-31T12:00:00Z", "2025-03-03T12:00:00Z", "2025-03-01T12:00:00Z", "2025-03-03T12:00:00Z"}, records[1])
+			assert.Equal(t, []string{"INV-000002-2025", jobID.String(), "2", "1212.50", "EUR", "Waiting", "2025-04-07T12:00:00Z", "", "2025-03-08T12:00:00Z", "2025-03-08T12:00:00Z"}, records[2])
+		})
+	}
+}
+
+func TestInvoiceHandler_ListInvoicesByJob_EmptyCSVHasHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/jobs/:id/invoices", func(c *gin.Context) {
+		c.Set("userID", uuid.New())
+		c.Next()
+	}, NewInvoiceHandler(&stubInvoiceListService{}, validator.New()).ListInvoicesByJob)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+uuid.New().String()+"/invoices", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "invoice_number,job_id,interval_number,value,currency,state,due_date,paid_at,created_at,updated_at\n", w.Body.String())
+}
@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"errors"
+	"fmt"
 	"go-api-template/internal/api/middleware"
 	"go-api-template/internal/services"
 	"go-api-template/internal/transport/dto"
-	"log"
+	"go-api-template/pkg/logger"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -15,79 +15,93 @@ import (
 
 // JobApplicationHandler holds dependencies for job application operations.
 type JobApplicationHandler struct {
-	service   services.JobApplicationService
-	validator *validator.Validate
+	service     services.JobApplicationService
+	validator   *validator.Validate
+	maxPageSize int // Largest limit a list request may use; larger ones are clamped
 }
 
 // NewJobApplicationHandler creates a new JobApplicationHandler.
 func NewJobApplicationHandler(service services.JobApplicationService, validate *validator.Validate) *JobApplicationHandler {
 	return &JobApplicationHandler{
-		service:   service,
-		validator: validate,
+		service:     service,
+		validator:   validate,
+		maxPageSize: dto.DefaultMaxPageLimit,
 	}
 }
 
+// WithMaxPageSize sets the largest limit the list endpoints accept. Values below 1 keep the default.
+func (h *JobApplicationHandler) WithMaxPageSize(n int) *JobApplicationHandler {
+	if n > 0 {
+		h.maxPageSize = n
+	}
+	return h
+}
+
 // Compile-time check to ensure JobApplicationHandler implements JobApplicationHandlerInterface
 var _ JobApplicationHandlerInterface = (*JobApplicationHandler)(nil)
 
 // ApplyToJob godoc
 // @Summary      Apply for a job
-// @Description  Allows a logged-in user (contractor) to apply for a specific job.
+// @Description  Allows a logged-in user (contractor) to apply for a specific job. A contractor whose earlier application was withdrawn or rejected may apply again.
 // @Tags         job_applications
 // @Accept       json
 // @Produce      json
 // @Param        id path      string true  "Job ID to apply for" Format(uuid)
+// @Param        application body dto.ApplyToJobRequest false "Optional cover message and proposed rate (job_id is taken from the path)"
 // @Success      201 {object}  dto.JobApplicationResponse "Application created successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid Job ID or already applied"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - Cannot apply (e.g., employer applying to own job, job not available)"
-// @Failure      404 {object}  map[string]string "Not Found - Job not found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Header       201 {string} Location "URL of the created resource"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID or already applied"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Cannot apply (e.g., employer applying to own job, job not available)"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Job not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Job not available, applications closed, or an application is already Waiting or Accepted"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{job_id}/apply [post]
 // @Security     BearerAuth
 func (h *JobApplicationHandler) ApplyToJob(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("ApplyToJob: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("ApplyToJob: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	jobIDStr := c.Param("id") // Assuming the job ID is in the path like /jobs/{job_id}/apply
 	jobID, err := uuid.Parse(jobIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
-	req := dto.ApplyToJobRequest{
-		JobID:        jobID,
-		ContractorID: userID, // Set the contractor ID from the authenticated user
+	var req dto.ApplyToJobRequest
+	// The body is optional and only carries the cover message and proposed rate
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, badRequest("Invalid request body: " + err.Error()))
+			return
+		}
 	}
+	req.JobID = jobID
+	req.ContractorID = userID // Set the contractor ID from the authenticated user
 
-	// No need to validate req here as fields are set internally or from path param
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
 
 	application, err := h.service.ApplyToJob(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()}) // Use specific error message from service
-		} else if errors.Is(err, services.ErrInvalidState) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // Use 409 Conflict for state issues like job not available
-		} else if errors.Is(err, services.ErrConflict) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // Use 409 Conflict for already applied
-		} else {
-			log.Printf("ApplyToJob: Error applying to job %s: %v", jobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply for job"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Job not found",
+			services.ErrConflict: "You have already applied to this job",
+		})
 		return
 	}
 
 	// Map result to dto.JobApplicationResponse (Need to add this mapping function)
 	appResponse := MapJobApplicationModelToResponse(application) // Assuming this function exists/will be created
 
-	c.JSON(http.StatusCreated, appResponse)
+	respondCreated(c, "/applications/"+application.ID.String(), appResponse)
 }
 
 // GetApplicationByID godoc
@@ -98,25 +112,25 @@ func (h *JobApplicationHandler) ApplyToJob(c *gin.Context) {
 // @Produce      json
 // @Param        id path      string true  "Application ID" Format(uuid)
 // @Success      200 {object}  dto.JobApplicationResponse "Successfully retrieved application"
-// @Failure      400 {object}  map[string]string "Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User not associated with this application"
-// @Failure      404 {object}  map[string]string "Application Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User not associated with this application"
+// @Failure      404 {object}  dto.ErrorResponse "Application Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /applications/{id} [get]
 // @Security     BearerAuth
 func (h *JobApplicationHandler) GetApplicationByID(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("GetApplicationByID: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("GetApplicationByID: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	appIDStr := c.Param("id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID format"})
+		respondError(c, badRequest("Invalid application ID format"))
 		return
 	}
 
@@ -127,14 +141,10 @@ func (h *JobApplicationHandler) GetApplicationByID(c *gin.Context) {
 
 	application, err := h.service.GetApplicationByID(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view this application"})
-		} else {
-			log.Printf("GetApplicationByID: Error fetching application %s: %v", appID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve application"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Application not found",
+			services.ErrForbidden: "You are not authorized to view this application",
+		})
 		return
 	}
 
@@ -144,50 +154,45 @@ func (h *JobApplicationHandler) GetApplicationByID(c *gin.Context) {
 
 // ListApplicationsByContractor godoc
 // @Summary      List applications submitted by the authenticated user
-// @Description  Retrieves a list of job applications submitted by the currently authenticated user (contractor). Supports pagination.
+// @Description  Retrieves a list of job applications submitted by the currently authenticated user (contractor). Supports pagination and filtering by state.
 // @Tags         job_applications
 // @Accept       json
 // @Produce      json
-// @Param        limit query int false "Pagination limit" default(10)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
 // @Param        offset query int false "Pagination offset" default(0)
-// @Success      200 {array}   dto.JobApplicationResponse "Successfully retrieved list of applications"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid query parameters"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Param        state query string false "Filter by state (Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)" Enums(Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)
+// @Param        updated_since query string false "Only applications changed after this RFC 3339 time, ordered by updated_at ascending" Format(date-time)
+// @Success      200 {object}  dto.PaginatedResponse[dto.JobApplicationResponse] "Successfully retrieved list of applications"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /applications/my [get] // Example route
 // @Security     BearerAuth
 func (h *JobApplicationHandler) ListApplicationsByContractor(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("ListApplicationsByContractor: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("ListApplicationsByContractor: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	var req dto.ListJobApplicationsByContractorRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
 		return
 	}
 	req.ContractorID = userID // Set the contractor ID from context
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 	// Ensure defaults if not provided by binding
-	if req.Limit <= 0 {
-		req.Limit = 10
-	}
-	if req.Offset < 0 {
-		req.Offset = 0
-	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
 
-	applications, err := h.service.ListApplicationsByContractor(c.Request.Context(), &req)
+	applications, total, err := h.service.ListApplicationsByContractor(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("ListApplicationsByContractor: Error listing applications for user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve applications"})
+		respondError(c, err)
 		return
 	}
 
@@ -196,72 +201,114 @@ func (h *JobApplicationHandler) ListApplicationsByContractor(c *gin.Context) {
 		appResponses = append(appResponses, MapJobApplicationModelToResponse(&app))
 	}
 
-	c.JSON(http.StatusOK, appResponses)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(appResponses, total, req.Limit, req.Offset))
+}
+
+// ListAppliedJobs godoc
+// @Summary      List jobs the authenticated user applied to
+// @Description  Retrieves the jobs the currently authenticated user (contractor) applied to, each with their application, whatever its outcome. Unlike /applications/my the job details are included. Most recent application first; deleted jobs are left out.
+// @Tags         job_applications
+// @Accept       json
+// @Produce      json
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
+// @Param        offset query int false "Pagination offset" default(0)
+// @Param        state query string false "Filter by application state (Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)" Enums(Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)
+// @Success      200 {object}  dto.PaginatedResponse[dto.AppliedJobResponse] "Successfully retrieved list of applied jobs"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/applied-jobs [get]
+// @Security     BearerAuth
+func (h *JobApplicationHandler) ListAppliedJobs(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("ListAppliedJobs: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	var req dto.ListAppliedJobsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.ContractorID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
+
+	appliedJobs, total, err := h.service.ListAppliedJobs(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]dto.AppliedJobResponse, 0, len(appliedJobs))
+	for i := range appliedJobs {
+		responses = append(responses, MapAppliedJobToResponse(&appliedJobs[i]))
+	}
+
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(responses, total, req.Limit, req.Offset))
 }
 
 // ListApplicationsByJob godoc
 // @Summary      List applications for a specific job
-// @Description  Retrieves a list of applications for a specific job. Only allowed for the employer who posted the job. Supports pagination.
+// @Description  Retrieves a list of applications for a specific job. Only allowed for the employer who posted the job. Supports pagination and filtering by state, e.g. state=Waiting for the applicants still awaiting a decision.
 // @Tags         job_applications
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Job ID" Format(uuid)
-// @Param        limit query int false "Pagination limit" default(10)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
 // @Param        offset query int false "Pagination offset" default(0)
-// @Success      200 {array}   dto.JobApplicationResponse "Successfully retrieved list of applications"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid Job ID or query parameters"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not the employer for this job"
-// @Failure      404 {object}  map[string]string "Not Found - Job not found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Param        state query string false "Filter by state (Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)" Enums(Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)
+// @Param        updated_since query string false "Only applications changed after this RFC 3339 time, ordered by updated_at ascending" Format(date-time)
+// @Success      200 {object}  dto.PaginatedResponse[dto.JobApplicationResponse] "Successfully retrieved list of applications"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID or query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer for this job"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Job not found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{job_id}/applications [get] // Example route
 // @Security     BearerAuth
 func (h *JobApplicationHandler) ListApplicationsByJob(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("ListApplicationsByJob: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("ListApplicationsByJob: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	jobIDStr := c.Param("id")
 	jobID, err := uuid.Parse(jobIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
 	var req dto.ListJobApplicationsByJobRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
 		return
 	}
 	req.JobID = jobID
 	req.UserID = userID // Pass UserID for authorization check in service
 	
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 	// Ensure defaults if not provided by binding
-	if req.Limit <= 0 {
-		req.Limit = 10
-	}
-	if req.Offset < 0 {
-		req.Offset = 0
-	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
 
-	applications, err := h.service.ListApplicationsByJob(c.Request.Context(), &req)
+	applications, total, err := h.service.ListApplicationsByJob(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view applications for this job"})
-		} else {
-			log.Printf("ListApplicationsByJob: Error listing applications for job %s: %v", jobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve applications"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "You are not authorized to view applications for this job",
+		})
 		return
 	}
 
@@ -270,37 +317,101 @@ func (h *JobApplicationHandler) ListApplicationsByJob(c *gin.Context) {
 		appResponses = append(appResponses, MapJobApplicationModelToResponse(&app))
 	}
 
-	c.JSON(http.StatusOK, appResponses)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(appResponses, total, req.Limit, req.Offset))
+}
+
+// ExportApplicationsCSV godoc
+// @Summary      Export applications for a job as CSV
+// @Description  Streams every application for a specific job as a CSV file (contractor name, application date, state, cover message, employer note), optionally only those in one state. Only allowed for the employer who posted the job.
+// @Tags         job_applications
+// @Produce      text/csv
+// @Param        id path string true "Job ID" Format(uuid)
+// @Param        state query string false "Only export applications in this state" Enums(Waiting, Invited, Accepted, Rejected, Withdrawn, Declined)
+// @Success      200 {file}    file "CSV file of applications"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid Job ID or state"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer for this job"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Job not found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/applications/export [get]
+// @Security     BearerAuth
+func (h *JobApplicationHandler) ExportApplicationsCSV(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("ExportApplicationsCSV: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobIDStr := c.Param("id")
+	jobID, err := uuid.Parse(jobIDStr)
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.ExportJobApplicationsCSVRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.JobID = jobID
+	req.UserID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// Headers are only sent on the first write, so errors before any row is written can still be reported as JSON
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"job-%s-applications.csv\"", jobID))
+
+	err = h.service.ExportApplicationsCSV(c.Request.Context(), &req, c.Writer)
+	if err != nil {
+		if c.Writer.Written() {
+			// Part of the file is already on the wire; all we can do is log and cut the stream short
+			logger.FromContext(c.Request.Context()).Error("ExportApplicationsCSV: Error while streaming applications", "job_id", jobID, "error", err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "You are not authorized to export applications for this job",
+		})
+		return
+	}
 }
 
 // AcceptApplication godoc
 // @Summary      Accept a job application
-// @Description  Allows the employer to accept a 'Waiting' application. This assigns the contractor to the job, changes the job state to 'Ongoing', and rejects other pending applications for the same job.
+// @Description  Allows the employer to accept a 'Waiting' application. This assigns the contractor to the job, changes the job state to 'Ongoing', replaces the job's rate with the application's proposed rate if it has one, and rejects other pending applications for the same job.
 // @Tags         job_applications
 // @Accept       json
 // @Produce      json
 // @Param        id path      string true  "Application ID" Format(uuid)
 // @Success      200 {object}  dto.JobResponse "Application accepted, job updated"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not the employer or job/application state is invalid"
-// @Failure      404 {object}  map[string]string "Not Found - Application or Job not found"
-// @Failure      409 {object}  map[string]string "Conflict - Job/Application state prevents acceptance"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer or job/application state is invalid"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Application or Job not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Job/Application state prevents acceptance"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /applications/{id}/accept [patch]
 // @Security     BearerAuth
 func (h *JobApplicationHandler) AcceptApplication(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("AcceptApplication: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("AcceptApplication: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	appIDStr := c.Param("id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID format"})
+		respondError(c, badRequest("Invalid application ID format"))
 		return
 	}
 
@@ -311,16 +422,11 @@ func (h *JobApplicationHandler) AcceptApplication(c *gin.Context) {
 
 	updatedJob, err := h.service.AcceptApplication(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()}) // Could be app or job not found
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You are not the employer for this job"})
-		} else if errors.Is(err, services.ErrInvalidState) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // Use 409 Conflict for state issues
-		} else {
-			log.Printf("AcceptApplication: Error accepting application %s: %v", appID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept application"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Application or job not found",
+			services.ErrForbidden: "Forbidden: You are not the employer for this job",
+			services.ErrConflict:  "Job has already been assigned a contractor",
+		})
 		return
 	}
 
@@ -337,26 +443,26 @@ func (h *JobApplicationHandler) AcceptApplication(c *gin.Context) {
 // @Produce      json
 // @Param        id path      string true  "Application ID" Format(uuid)
 // @Success      200 {object}  dto.JobApplicationResponse "Application rejected successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not the employer or application state is invalid"
-// @Failure      404 {object}  map[string]string "Not Found - Application or Job not found"
-// @Failure      409 {object}  map[string]string "Conflict - Application state prevents rejection"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer or application state is invalid"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Application or Job not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Application state prevents rejection"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /applications/{id}/reject [patch]
 // @Security     BearerAuth
 func (h *JobApplicationHandler) RejectApplication(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("RejectApplication: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("RejectApplication: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	appIDStr := c.Param("id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID format"})
+		respondError(c, badRequest("Invalid application ID format"))
 		return
 	}
 
@@ -367,16 +473,10 @@ func (h *JobApplicationHandler) RejectApplication(c *gin.Context) {
 
 	updatedApp, err := h.service.RejectApplication(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()}) // Could be app or job not found
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You are not the employer for this job"})
-		} else if errors.Is(err, services.ErrInvalidState) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // Use 409 Conflict for state issues
-		} else {
-			log.Printf("RejectApplication: Error rejecting application %s: %v", appID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reject application"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Application or job not found",
+			services.ErrForbidden: "Forbidden: You are not the employer for this job",
+		})
 		return
 	}
 
@@ -384,6 +484,66 @@ func (h *JobApplicationHandler) RejectApplication(c *gin.Context) {
 	c.JSON(http.StatusOK, appResponse)
 }
 
+// BulkRejectApplications godoc
+// @Summary      Reject several job applications
+// @Description  Allows the employer to reject up to 100 'Waiting' applications at once. Each application is checked on its own: those that don't exist, belong to another employer's job or aren't 'Waiting' are reported with the error the single reject endpoint would give, and the rest are still rejected.
+// @Tags         job_applications
+// @Accept       json
+// @Produce      json
+// @Param        applications body dto.BulkRejectRequest true "IDs of the applications to reject"
+// @Success      200 {object}  dto.BulkRejectResponse "Outcome for every application, in request order"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid body or validation failed"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /applications/reject [post]
+// @Security     BearerAuth
+func (h *JobApplicationHandler) BulkRejectApplications(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("BulkRejectApplications: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	var req dto.BulkRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.UserID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	results, err := h.service.BulkRejectApplications(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := dto.BulkRejectResponse{Results: make([]dto.BulkRejectItemResponse, 0, len(results))}
+	for _, result := range results {
+		item := dto.BulkRejectItemResponse{ApplicationID: result.ApplicationID}
+		if result.Err != nil {
+			described, mapped := DescribeError(result.Err)
+			if !mapped {
+				logger.FromContext(c.Request.Context()).Error("BulkRejectApplications: Unhandled item error", "application_id", result.ApplicationID, "error", result.Err)
+			}
+			item.Error = &dto.ErrorResponse{Code: described.Code, Message: described.Message, Details: described.Details}
+			response.Failed++
+		} else {
+			appResponse := MapJobApplicationModelToResponse(result.Application)
+			item.Rejected = true
+			item.Application = &appResponse
+			response.Rejected++
+		}
+		response.Results = append(response.Results, item)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // WithdrawApplication godoc
 // @Summary      Withdraw a job application
 // @Description  Allows the applicant (contractor) to withdraw their 'Waiting' application.
@@ -392,26 +552,26 @@ func (h *JobApplicationHandler) RejectApplication(c *gin.Context) {
 // @Produce      json
 // @Param        id path      string true  "Application ID" Format(uuid)
 // @Success      200 {object}  dto.JobApplicationResponse "Application withdrawn successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User is not the applicant or application state is invalid"
-// @Failure      404 {object}  map[string]string "Not Found - Application not found"
-// @Failure      409 {object}  map[string]string "Conflict - Application state prevents withdrawal"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the applicant or application state is invalid"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Application not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Application state prevents withdrawal"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /applications/{id}/withdraw [patch]
 // @Security     BearerAuth
 func (h *JobApplicationHandler) WithdrawApplication(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("WithdrawApplication: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("WithdrawApplication: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	appIDStr := c.Param("id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID format"})
+		respondError(c, badRequest("Invalid application ID format"))
 		return
 	}
 
@@ -422,19 +582,156 @@ func (h *JobApplicationHandler) WithdrawApplication(c *gin.Context) {
 
 	updatedApp, err := h.service.WithdrawApplication(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You are not the applicant for this application"})
-		} else if errors.Is(err, services.ErrInvalidState) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // Use 409 Conflict for state issues
-		} else {
-			log.Printf("WithdrawApplication: Error withdrawing application %s: %v", appID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Application not found",
+			services.ErrForbidden: "Forbidden: You are not the applicant for this application",
+		})
 		return
 	}
 
 	appResponse := MapJobApplicationModelToResponse(updatedApp)
 	c.JSON(http.StatusOK, appResponse)
-}
\ No newline at end of file
+}
+// InviteContractor godoc
+// @Summary      Invite a contractor to a job
+// @Description  Allows the employer of a 'Waiting' job to invite a contractor directly. The invitation is an application in the 'Invited' state, which the contractor can accept or decline; the contractor is notified. Employers can't invite themselves, nor a contractor who already has a pending application or invitation for the job.
+// @Tags         job_applications
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        invitation body dto.InviteContractorRequest true "Contractor to invite and an optional message"
+// @Success      201 {object}  dto.JobApplicationResponse "Invitation created successfully"
+// @Header       201 {string} Location "URL of the created resource"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID format or validation failed"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer, or invited themselves"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Job or contractor not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Job not Waiting, or the contractor already applied or was invited"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/invitations [post]
+// @Security     BearerAuth
+func (h *JobApplicationHandler) InviteContractor(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("InviteContractor: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.InviteContractorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.JobID = jobID
+	req.EmployerID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	invitation, err := h.service.InviteContractor(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job or contractor not found",
+			services.ErrForbidden: "Forbidden: You are not the employer for this job",
+			services.ErrConflict:  "This contractor has already applied or been invited to this job",
+		})
+		return
+	}
+
+	respondCreated(c, "/applications/"+invitation.ID.String(), MapJobApplicationModelToResponse(invitation))
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept an invitation to a job
+// @Description  Allows the invited contractor to accept an 'Invited' application. This assigns them to the job, changes the job state to 'Ongoing' and rejects other pending applications for the same job; the employer is notified.
+// @Tags         job_applications
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Application ID of the invitation" Format(uuid)
+// @Success      200 {object}  dto.JobResponse "Invitation accepted, job updated"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the invited contractor"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Invitation or Job not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Invitation already answered, or the job is no longer available"
+// @Failure      422 {object}  dto.ErrorResponse "Unprocessable Entity - The contractor is at their ongoing job limit"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /applications/{id}/accept-invitation [patch]
+// @Security     BearerAuth
+func (h *JobApplicationHandler) AcceptInvitation(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("AcceptInvitation: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid application ID format"))
+		return
+	}
+
+	updatedJob, err := h.service.AcceptInvitation(c.Request.Context(), &dto.RespondToInvitationRequest{ApplicationID: appID, UserID: userID})
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Invitation or job not found",
+			services.ErrForbidden: "Forbidden: This invitation is not for you",
+			services.ErrConflict:  "Job has already been assigned a contractor",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobModelToJobResponse(updatedJob))
+}
+
+// DeclineInvitation godoc
+// @Summary      Decline an invitation to a job
+// @Description  Allows the invited contractor to decline an 'Invited' application, which becomes 'Declined'; the employer is notified.
+// @Tags         job_applications
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Application ID of the invitation" Format(uuid)
+// @Success      200 {object}  dto.JobApplicationResponse "Invitation declined"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the invited contractor"
+// @Failure      404 {object}  dto.ErrorResponse "Not Found - Invitation not found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Invitation already answered"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /applications/{id}/decline-invitation [patch]
+// @Security     BearerAuth
+func (h *JobApplicationHandler) DeclineInvitation(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("DeclineInvitation: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid application ID format"))
+		return
+	}
+
+	declined, err := h.service.DeclineInvitation(c.Request.Context(), &dto.RespondToInvitationRequest{ApplicationID: appID, UserID: userID})
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Invitation not found",
+			services.ErrForbidden: "Forbidden: This invitation is not for you",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobApplicationModelToResponse(declined))
+}
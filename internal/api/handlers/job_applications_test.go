@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBulkRejectService answers BulkRejectApplications with results and records the request; any other
+// method panics through the nil embedded interface.
+type stubBulkRejectService struct {
+	services.JobApplicationService
+	results []dto.BulkRejectResult
+	req     *dto.BulkRejectRequest
+}
+
+func (s *stubBulkRejectService) BulkRejectApplications(ctx context.Context, req *dto.BulkRejectRequest) ([]dto.BulkRejectResult, error) {
+	s.req = req
+	return s.results, nil
+}
+
+func TestJobApplicationHandler_BulkRejectApplications(t *testing.T) {
+	employerID := uuid.New()
+	rejected := models.JobApplication{ID: uuid.New(), JobID: uuid.New(), ContractorID: uuid.New(), State: models.JobApplicationRejected}
+	forbiddenID, notWaitingID := uuid.New(), uuid.New()
+	service := &stubBulkRejectService{results: []dto.BulkRejectResult{
+		{ApplicationID: rejected.ID, Application: &rejected},
+		{ApplicationID: forbiddenID, Err: services.ErrForbidden},
+		{ApplicationID: notWaitingID, Err: fmt.Errorf("%w: application is not in 'Waiting' state, current state: Accepted", services.ErrInvalidState)},
+	}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/applications/reject", func(c *gin.Context) {
+		c.Set("userID", employerID) // What JWTAuthMiddleware stores
+		c.Next()
+	}, NewJobApplicationHandler(service, validator.New()).BulkRejectApplications)
+
+	body := fmt.Sprintf(`{"application_ids": [%q, %q, %q]}`, rejected.ID, forbiddenID, notWaitingID)
+	req := httptest.NewRequest(http.MethodPost, "/applications/reject", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, service.req)
+	assert.Equal(t, employerID, service.req.UserID)
+	assert.Equal(t, []uuid.UUID{rejected.ID, forbiddenID, notWaitingID}, service.req.ApplicationIDs)
+
+	var response dto.BulkRejectResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Rejected)
+	assert.Equal(t, 2, response.Failed)
+	require.Len(t, response.Results, 3)
+
+	assert.True(t, response.Results[0].Rejected)
+	require.NotNil(t, response.Results[0].Application)
+	assert.Equal(t, models.JobApplicationRejected, response.Results[0].Application.State)
+	assert.Nil(t, response.Results[0].Error)
+
+	// Failed items carry the code the single reject endpoint would answer with
+	assert.False(t, response.Results[1].Rejected)
+	require.NotNil(t, response.Results[1].Error)
+	assert.Equal(t, dto.ErrorCodeForbidden, response.Results[1].Error.Code)
+	require.NotNil(t, response.Results[2].Error)
+	assert.Equal(t, dto.ErrorCodeInvalidState, response.Results[2].Error.Code)
+	assert.Contains(t, response.Results[2].Error.Message, "not in 'Waiting' state")
+}
+
+func TestJobApplicationHandler_BulkRejectApplications_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "Empty", body: `{"application_ids": []}`},
+		{name: "Missing", body: `{}`},
+		{name: "TooMany", body: `{"application_ids": [` + strings.TrimSuffix(strings.Repeat(`"`+uuid.NewString()+`",`, 101), ",") + `]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubBulkRejectService{}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/applications/reject", func(c *gin.Context) {
+				c.Set("userID", uuid.New())
+				c.Next()
+			}, NewJobApplicationHandler(service, validator.New()).BulkRejectApplications)
+
+			req := httptest.NewRequest(http.MethodPost, "/applications/reject", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Nil(t, service.req, "Invalid requests never reach the service")
+		})
+	}
+}
@@ -2,14 +2,13 @@
 package handlers
 
 import (
-	"errors"
-	"log"
 	"net/http"
 
 	"go-api-template/internal/api/middleware" // Import middleware for GetUserIDFromContext
 	// Import models for mapping
 	"go-api-template/internal/services"
 	"go-api-template/internal/transport/dto" // Import DTOs
+	"go-api-template/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
@@ -20,6 +19,7 @@ import (
 type JobHandler struct {
 	service services.JobService 
 	validator *validator.Validate
+	maxPageSize int // Largest limit a list request may use; larger ones are clamped
 }
 
 // NewJobHandler creates a new JobHandler.
@@ -27,53 +27,61 @@ func NewJobHandler(service services.JobService, validate *validator.Validate) *J
 	return &JobHandler{
 		service: service,
 		validator: validate,
+		maxPageSize: dto.DefaultMaxPageLimit,
 	}
 }
 
+// WithMaxPageSize sets the largest limit the list endpoints accept. Values below 1 keep the default.
+func (h *JobHandler) WithMaxPageSize(n int) *JobHandler {
+	if n > 0 {
+		h.maxPageSize = n
+	}
+	return h
+}
+
 // CreateJob godoc
 // @Summary      Create a new job posting
-// @Description  Adds a new job available for contractors. Employer ID is taken from auth context.
+// @Description  Adds a new job available for contractors. Employer ID is taken from auth context. Unknown skill names are created on demand. Rate, duration and invoice_interval must be positive, and invoice_interval must not exceed duration. currency is an ISO 4217 code such as EUR and defaults to the configured currency; invoices of the job use it.
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        job body      dto.CreateJobRequest true  "Job details (EmployerID ignored)"
+// @Param        Idempotency-Key header string false "Makes retries safe: a repeated key replays the first successful response"
 // @Success      201 {object}  dto.JobResponse "Job created successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid input"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Header       201 {string} Location "URL of the created resource"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      409 {object}  dto.ErrorResponse "A request with the same Idempotency-Key is still being processed"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs [post]
 // @Security     BearerAuth
 func (h *JobHandler) CreateJob(c *gin.Context) {
 	// Get EmployerID from auth context
 	employerID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"}) // Or Internal Server Error if context missing is unexpected
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized")) // Or Internal Server Error if context missing is unexpected
 		return
 	}
 
 	var req dto.CreateJobRequest
 	// Bind/Validate dto.CreateJobRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 	// Set EmployerID from context
 	req.EmployerID = employerID
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 
 	// Call h.repo.Create
 	createdJob, err := h.service.CreateJob(c.Request.Context(), &req)
 	if err != nil {
-		// Handle potential repo errors (e.g., conflict, db error)
-		log.Printf("Error creating job in repository: %v", err)
-		// Check for specific errors if repo returns them (e.g., services.ErrConflict)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		respondError(c, err)
 		return
 	}
 
@@ -81,7 +89,7 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 	jobResponse := MapJobModelToJobResponse(createdJob)
 
 	// Return JSON response
-	c.JSON(http.StatusCreated, jobResponse)
+	respondCreated(c, "/jobs/"+createdJob.ID.String(), jobResponse)
 }
 
 // GetJobByID godoc
@@ -91,11 +99,13 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        If-None-Match header string false "ETag from a previous response"
 // @Success      200 {object}  dto.JobResponse "Successfully retrieved job"
-// @Failure      400 {object}  map[string]string "Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      404 {object}  map[string]string "Job Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Success      304 "Not Modified - The ETag in If-None-Match is still current"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{id} [get]
 // @Security     BearerAuth
 func (h *JobHandler) GetJobByID(c *gin.Context) {
@@ -103,7 +113,7 @@ func (h *JobHandler) GetJobByID(c *gin.Context) {
 	idStr := c.Param("id")
 	jobID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
@@ -113,20 +123,17 @@ func (h *JobHandler) GetJobByID(c *gin.Context) {
 	// Call h.repo.GetByID
 	job, err := h.service.GetJobByID(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-		} else {
-			log.Printf("Error fetching job by ID %s: %v", idStr, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Job not found",
+		})
 		return
 	}
 
 	// Map result to dto.JobResponse
 	jobResponse := MapJobModelToJobResponse(job)
 
-	// Return JSON response
-	c.JSON(http.StatusOK, jobResponse)
+	// Return JSON response, or 304 if the client's copy is current
+	respondWithETag(c, jobResponse)
 }
 
 // ListAvailableJobs godoc
@@ -135,14 +142,24 @@ func (h *JobHandler) GetJobByID(c *gin.Context) {
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
-// @Param        limit query int false "Pagination limit" default(10)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
 // @Param        offset query int false "Pagination offset" default(0)
 // @Param        min_rate query number false "Minimum rate filter"
 // @Param        max_rate query number false "Maximum rate filter"
-// @Success      200 {array}   dto.JobResponse "Successfully retrieved list of available jobs"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid query parameters"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Param        min_duration query int false "Minimum duration filter (hours)"
+// @Param        max_duration query int false "Maximum duration filter (hours); must not be below min_duration"
+// @Param        sort_by query string false "Sort field (default created_at)" Enums(rate, duration, created_at)
+// @Param        sort_order query string false "Sort direction (default desc)" Enums(asc, desc)
+// @Param        search query string false "Full-text search term matched against the job description"
+// @Param        skills query []string false "Skill names to match; repeat the parameter for several" collectionFormat(multi)
+// @Param        skills_match query string false "Whether jobs must have all (default) or any of the skills" Enums(all, any)
+// @Param        updated_since query string false "Only jobs changed after this RFC 3339 time, ordered by updated_at ascending unless sort_by is set" Format(date-time)
+// @Success      200 {object}  dto.PaginatedResponse[dto.JobResponse] "Successfully retrieved list of available jobs"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/available [get]
 // @Security     BearerAuth
 func (h *JobHandler) ListAvailableJobs(c *gin.Context) {
@@ -150,29 +167,21 @@ func (h *JobHandler) ListAvailableJobs(c *gin.Context) {
 
 	// Bind/Validate query params into dto.ListAvailableJobsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
 		return
 	}
 
 	// Explicitly validate the struct if needed 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
-	// Set defaults if binding didn't 
-	if req.Limit <= 0 {
-		req.Limit = 10
-	}
-	if req.Offset < 0 {
-		req.Offset = 0
-	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
 
 	// Call h.repo.ListAvailable
-	jobs, err := h.service.ListAvailableJobs(c.Request.Context(), &req)
+	jobs, total, err := h.service.ListAvailableJobs(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("Error listing available jobs: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve available jobs"})
+		respondError(c, err)
 		return
 	}
 
@@ -183,7 +192,8 @@ func (h *JobHandler) ListAvailableJobs(c *gin.Context) {
 	}
 
 	// Return JSON response
-	c.JSON(http.StatusOK, jobResponses)
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(jobResponses, total, req.Limit, req.Offset))
 }
 
 // ListEmployerJobs godoc
@@ -192,48 +202,51 @@ func (h *JobHandler) ListAvailableJobs(c *gin.Context) {
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
-// @Param        limit query int false "Pagination limit" default(10)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
 // @Param        offset query int false "Pagination offset" default(0)
-// @Param        state query string false "Filter by state (Waiting, Ongoing, Complete, Archived)" Enums(Waiting, Ongoing, Complete, Archived)
+// @Param        state query string false "Filter by state (Waiting, Ongoing, Complete, Archived, Cancelled)" Enums(Waiting, Ongoing, Complete, Archived, Cancelled)
+// @Param        states query []string false "Filter by any of several states, e.g. states=Ongoing&states=Complete" collectionFormat(multi) Enums(Waiting, Ongoing, Complete, Archived, Cancelled)
 // @Param        min_rate query number false "Minimum rate filter"
 // @Param        max_rate query number false "Maximum rate filter"
-// @Success      200 {array}   dto.JobResponse "Successfully retrieved list of employer's jobs"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid query parameters"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Param        sort_by query string false "Sort field (default created_at)" Enums(rate, duration, created_at)
+// @Param        sort_order query string false "Sort direction (default desc)" Enums(asc, desc)
+// @Param        updated_since query string false "Only jobs changed after this RFC 3339 time, ordered by updated_at ascending unless sort_by is set" Format(date-time)
+// @Success      200 {object}  dto.PaginatedResponse[dto.JobResponse] "Successfully retrieved list of employer's jobs"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/my/employer [get] // Example route
 // @Security     BearerAuth
 func (h *JobHandler) ListEmployerJobs(c *gin.Context) {
 	// Get EmployerID from auth context
 	employerID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	var req dto.ListJobsByEmployerRequest
 	// Bind/Validate query params
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
 		return
 	}
 	// Set EmployerID on DTO
 	req.EmployerID = employerID
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
-	if req.Limit <= 0 { req.Limit = 10 }
-	if req.Offset < 0 { req.Offset = 0 }
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
 
 	// Call h.repo.ListByEmployer
-	jobs, err := h.service.ListJobsByEmployer(c.Request.Context(), &req)
+	jobs, total, err := h.service.ListJobsByEmployer(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("Error listing employer jobs for user %s: %v", employerID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve employer jobs"})
+		respondError(c, err)
 		return
 	}
 
@@ -244,7 +257,8 @@ func (h *JobHandler) ListEmployerJobs(c *gin.Context) {
 	}
 
 	// Return JSON response
-	c.JSON(http.StatusOK, jobResponses)
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(jobResponses, total, req.Limit, req.Offset))
 }
 
 // ListContractorJobs godoc
@@ -253,48 +267,51 @@ func (h *JobHandler) ListEmployerJobs(c *gin.Context) {
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
-// @Param        limit query int false "Pagination limit" default(10)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
 // @Param        offset query int false "Pagination offset" default(0)
 // @Param        state query string false "Filter by state (Ongoing, Complete, Archived)" Enums(Ongoing, Complete, Archived)
+// @Param        states query []string false "Filter by any of several states, e.g. states=Ongoing&states=Complete" collectionFormat(multi) Enums(Ongoing, Complete, Archived)
 // @Param        min_rate query number false "Minimum rate filter"
 // @Param        max_rate query number false "Maximum rate filter"
-// @Success      200 {array}   dto.JobResponse "Successfully retrieved list of contractor's jobs"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid query parameters"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Param        sort_by query string false "Sort field (default created_at)" Enums(rate, duration, created_at)
+// @Param        sort_order query string false "Sort direction (default desc)" Enums(asc, desc)
+// @Param        updated_since query string false "Only jobs changed after this RFC 3339 time, ordered by updated_at ascending unless sort_by is set" Format(date-time)
+// @Success      200 {object}  dto.PaginatedResponse[dto.JobResponse] "Successfully retrieved list of contractor's jobs"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/my/contractor [get] // Example route
 // @Security     BearerAuth
 func (h *JobHandler) ListContractorJobs(c *gin.Context) {
 	// Get ContractorID from auth context
 	contractorID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	var req dto.ListJobsByContractorRequest
 	// Bind/Validate query params
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
 		return
 	}
 	// Set ContractorID on DTO
 	req.ContractorID = contractorID
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
-	if req.Limit <= 0 { req.Limit = 10 }
-	if req.Offset < 0 { req.Offset = 0 }
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
 
 	// Call h.repo.ListByContractor
-	jobs, err := h.service.ListJobsByContractor(c.Request.Context(), &req)
+	jobs, total, err := h.service.ListJobsByContractor(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("Error listing contractor jobs for user %s: %v", contractorID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve contractor jobs"})
+		respondError(c, err)
 		return
 	}
 
@@ -305,68 +322,66 @@ func (h *JobHandler) ListContractorJobs(c *gin.Context) {
 	}
 
 	// Return JSON response
-	c.JSON(http.StatusOK, jobResponses)
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(jobResponses, total, req.Limit, req.Offset))
 }
 
 // UpdateJobDetails godoc
-// @Summary      Update job rate or duration
-// @Description  Allows the employer to update the rate or duration ONLY if the job is in 'Waiting' state and has no contractor assigned.
+// @Summary      Update job rate, duration or description
+// @Description  Allows the employer to update the rate, duration, description or skills ONLY if the job is in 'Waiting' state and has no contractor assigned. Omitting skills keeps them; an empty list clears them.
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        id path      string true  "Job ID" Format(uuid)
-// @Param        details body dto.UpdateJobDetailsRequest true "Rate and/or Duration to update"
+// @Param        details body dto.UpdateJobDetailsRequest true "Rate, Duration, Description and/or ApplicationDeadline to update"
 // @Success      200 {object}  dto.JobResponse "Job details updated successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid input"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User cannot update details or job state prevents it"
-// @Failure      404 {object}  map[string]string "Job Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User cannot update details or job state prevents it"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Job was modified since the given version; re-fetch and retry"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{id}/details [patch]
 // @Security     BearerAuth
 func (h *JobHandler) UpdateJobDetails(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("UpdateJobDetails: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("UpdateJobDetails: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	idStr := c.Param("id")
 	jobID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
 	var req dto.UpdateJobDetailsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 	req.UserID = userID
 	req.JobID = jobID
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
-	if req.Rate == nil && req.Duration == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No update fields (rate, duration) provided"})
+	if req.Rate == nil && req.Duration == nil && req.Description == nil && req.ApplicationDeadline == nil {
+		respondError(c, badRequest("No update fields (rate, duration, description, application_deadline) provided"))
 		return
 	}
 
 	updatedJob, err := h.service.UpdateJobDetails(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found during update"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Cannot update job in its current state"})
-		} else {
-			log.Printf("UpdateJobDetails: Error updating job %s: %v", jobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job details"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found during update",
+			services.ErrForbidden: "Forbidden: Cannot update job in its current state",
+			services.ErrConflict:  "Job was modified by someone else; re-fetch it and retry",
+		})
 		return
 	}
 
@@ -375,65 +390,353 @@ func (h *JobHandler) UpdateJobDetails(c *gin.Context) {
 
 // UpdateJobState godoc
 // @Summary      Update job state
-// @Description  Allows the employer or the assigned contractor to update the job state according to valid transitions (Waiting -> Ongoing -> Complete -> Archived).
+// @Description  Allows the employer or the assigned contractor to update the job state according to the transitions listed by GET /jobs/state-machine.
 // @Tags         jobs
 // @Accept       json
 // @Produce      json
 // @Param        id path      string true  "Job ID" Format(uuid)
 // @Param        state body dto.UpdateJobStateRequest true "New state for the job"
 // @Success      200 {object}  dto.JobResponse "Job state updated successfully"
-// @Failure      400 {object}  map[string]string "Bad Request - Invalid input or invalid state transition"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User cannot update state for this job"
-// @Failure      404 {object}  map[string]string "Job Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input or invalid state transition"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User cannot update state for this job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Job was modified concurrently; retry"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{id}/state [patch]
 // @Security     BearerAuth
 func (h *JobHandler) UpdateJobState(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("UpdateJobState: Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("UpdateJobState: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	idStr := c.Param("id")
 	jobID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
 	var req dto.UpdateJobStateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 	req.JobID = jobID
 	req.UserID = userID
 	
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 
 	updatedJob, err := h.service.UpdateJobState(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found during update"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Cannot update job state in current state"})
-		} else {
-			log.Printf("UpdateJobState: Error updating job state %s: %v", jobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job state"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found during update",
+			services.ErrForbidden: "Forbidden: Cannot update job state in current state",
+			services.ErrConflict:  "Job was modified by someone else; retry",
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, MapJobModelToJobResponse(updatedJob))
 }
 
+// GetJobStateMachine godoc
+// @Summary      Describe the job state machine
+// @Description  Lists the job states and every transition PATCH /jobs/{id}/state accepts, with who may make it (employer, contractor, or system when the API makes it as a side effect of another operation).
+// @Tags         jobs
+// @Produce      json
+// @Success      200 {object}  dto.JobStateMachineResponse "Job states and allowed transitions"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Router       /jobs/state-machine [get]
+// @Security     BearerAuth
+func (h *JobHandler) GetJobStateMachine(c *gin.Context) {
+	c.JSON(http.StatusOK, MapJobStateMachineToResponse(h.service.GetJobStateMachine(c.Request.Context())))
+}
+
+// TransferJob godoc
+// @Summary      Transfer a job to another employer
+// @Description  Moves the job to another employer account, e.g. after a business restructure. Only the current employer can transfer a job, and only before a contractor is assigned. The transfer is recorded in the audit log.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        transfer body dto.TransferJobRequest true "The employer taking over the job"
+// @Success      200 {object}  dto.JobResponse "Job transferred successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input or unknown new employer"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Only the employer can transfer the job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - A contractor is already assigned"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/transfer [post]
+// @Security     BearerAuth
+func (h *JobHandler) TransferJob(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("TransferJob: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.TransferJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.UserID = userID
+	req.JobID = jobID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	job, err := h.service.TransferOwnership(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "Only the employer can transfer this job",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobModelToJobResponse(job))
+}
+
+// ReassignContractor godoc
+// @Summary      Reassign an ongoing job to another contractor
+// @Description  Replaces the contractor of an Ongoing job in one step; the job stays Ongoing instead of going through cancellation and a new hire. Only the employer can reassign a job, and the new contractor must be within their limit of Ongoing jobs. Existing invoices are kept unchanged and stay attributed to the prior contractor; the new contractor invoices from the next interval. The change is recorded in the audit log.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        reassignment body dto.ReassignContractorRequest true "The contractor taking over and an optional reason"
+// @Success      200 {object}  dto.JobResponse "Contractor reassigned successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input, unknown contractor, or the contractor is already assigned"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Only the employer can reassign the job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - The job is not Ongoing, or was modified concurrently; retry"
+// @Failure      422 {object}  dto.ErrorResponse "The new contractor already has the maximum number of ongoing jobs"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/reassign [post]
+// @Security     BearerAuth
+func (h *JobHandler) ReassignContractor(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("ReassignContractor: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.ReassignContractorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.UserID = userID
+	req.JobID = jobID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	job, err := h.service.ReassignContractor(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "Only the employer can reassign this job",
+			services.ErrConflict:  "Job was modified by someone else; retry",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobModelToJobResponse(job))
+}
+
+// ReopenJob godoc
+// @Summary      Re-open a completed job
+// @Description  Moves a Complete job back to Ongoing when it needs more work, with the same contractor. Only the employer can re-open a job, and only while a contractor is still assigned; Archived jobs can't be re-opened. The change is recorded in the audit log.
+// @Tags         jobs
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Success      200 {object}  dto.JobResponse "Job re-opened successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid job ID or the job is not Complete"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Only the employer can re-open the job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - No contractor is assigned, or the job was modified concurrently; retry"
+// @Failure      422 {object}  dto.ErrorResponse "The contractor already has the maximum number of ongoing jobs"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/reopen [post]
+// @Security     BearerAuth
+func (h *JobHandler) ReopenJob(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("ReopenJob: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	job, err := h.service.ReopenJob(c.Request.Context(), &dto.ReopenJobRequest{JobID: jobID, UserID: userID})
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "Only the employer can re-open this job",
+			services.ErrConflict:  "Job was modified by someone else; retry",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobModelToJobResponse(job))
+}
+
+// CancelJob godoc
+// @Summary      Cancel an ongoing job
+// @Description  Stops an Ongoing job: the contractor is unassigned and the job moves to Cancelled, which is final. Send hours_worked to bill the work done in the first interval not yet invoiced with a final invoice at the job's rate; it can't exceed the interval's hours. Only the employer can cancel a job, and the cancellation is recorded in the audit log.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        cancellation body dto.CancelJobRequest false "Reason and hours worked to bill"
+// @Success      200 {object}  dto.CancelJobResponse "Job cancelled successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input, the job is not Ongoing, or more hours than the interval has"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Only the employer can cancel the job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - The job was modified concurrently; retry"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/cancel [post]
+// @Security     BearerAuth
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("CancelJob: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.CancelJobRequest
+	// The body is optional and only carries the reason and the hours to bill
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, badRequest("Invalid request body: "+err.Error()))
+			return
+		}
+	}
+	req.JobID = jobID
+	req.UserID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	job, finalInvoice, err := h.service.CancelJob(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "Only the employer can cancel this job",
+			services.ErrConflict:  "Job was modified by someone else; retry",
+		})
+		return
+	}
+
+	resp := dto.CancelJobResponse{Job: MapJobModelToJobResponse(job)}
+	if finalInvoice != nil {
+		invoiceResp := MapInvoiceModelToInvoiceResponse(finalInvoice)
+		resp.FinalInvoice = &invoiceResp
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetJobAutoInvoice godoc
+// @Summary      Configure automatic invoicing
+// @Description  Turns automatic periodic invoicing on or off. While on and the job is Ongoing, the next interval invoice is created every cadence (daily, weekly, biweekly or monthly) until every interval is invoiced. Enabling it or changing the cadence schedules the first invoice one cadence from now. The employer or the assigned contractor can change it until the job is Complete.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        autoInvoice body dto.SetJobAutoInvoiceRequest true "Whether to invoice automatically, and how often"
+// @Success      200 {object}  dto.JobResponse "Automatic invoicing updated"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User not associated with this job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - The job is already Complete or Archived"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/auto-invoice [put]
+// @Security     BearerAuth
+func (h *JobHandler) SetJobAutoInvoice(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("SetJobAutoInvoice: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.SetJobAutoInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.UserID = userID
+	req.JobID = jobID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	job, err := h.service.SetAutoInvoice(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "User not associated with this job",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobModelToJobResponse(job))
+}
+
 // DeleteJob
 // @Summary      Delete a job
 // @Description  Deletes a job posting. Allowed only by the employer if the job is in 'Waiting' state and has no contractor.
@@ -442,19 +745,19 @@ func (h *JobHandler) UpdateJobState(c *gin.Context) {
 // @Produce      json
 // @Param        id path      string true  "Job ID" Format(uuid)
 // @Success      204 {object}  nil "Job deleted successfully"
-// @Failure      400 {object}  map[string]string "Invalid ID format"
-// @Failure      401 {object}  map[string]string "Unauthorized"
-// @Failure      403 {object}  map[string]string "Forbidden - User cannot delete this job or job state prevents deletion"
-// @Failure      404 {object}  map[string]string "Job Not Found"
-// @Failure      500 {object}  map[string]string "Internal Server Error"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User cannot delete this job or job state prevents deletion"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
 // @Router       /jobs/{id} [delete]
 // @Security     BearerAuth
 func (h *JobHandler) DeleteJob(c *gin.Context) {
 	// Get UserID from auth context
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("Error getting user ID from context: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
@@ -462,7 +765,7 @@ func (h *JobHandler) DeleteJob(c *gin.Context) {
 	idStr := c.Param("id")
 	jobID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID format"})
+		respondError(c, badRequest("Invalid job ID format"))
 		return
 	}
 
@@ -472,19 +775,266 @@ func (h *JobHandler) DeleteJob(c *gin.Context) {
 	// Call h.repo.Delete
 	err = h.service.DeleteJob(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) { 
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-		} else if errors.Is(err, services.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Cannot delete job in current state"})
-		} else if errors.Is(err, services.ErrInvalidState) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Job is not in a deletable state"})
-		} else {
-			log.Printf("Error deleting job %s: %v", jobID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:     "Job not found",
+			services.ErrForbidden:    "Forbidden: Cannot delete job in current state",
+			services.ErrInvalidState: "Job is not in a deletable state",
+		})
 		return
 	}
 
 	// Return 204 No Content
 	c.Status(http.StatusNoContent)
 }
+
+// CanDeleteJob godoc
+// @Summary      Check whether a job can be deleted
+// @Description  Runs the same checks as DELETE /jobs/{id} without deleting anything, and lists every reason the job can't be deleted (invalid_state, contractor_assigned, invoices_exist). Employer only.
+// @Tags         jobs
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Success      200 {object}  dto.JobDeletableResponse "Deletability and any blockers"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the job's employer"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/deletable [get]
+// @Security     BearerAuth
+func (h *JobHandler) CanDeleteJob(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	blockers, err := h.service.CanDeleteJob(c.Request.Context(), &dto.DeleteJobRequest{ID: jobID, UserID: userID})
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:  "Job not found",
+			services.ErrForbidden: "Forbidden: Only the employer can delete this job",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobDeleteBlockersToResponse(jobID, blockers))
+}
+
+// RestoreJob godoc
+// @Summary      Restore a deleted job
+// @Description  Undoes a soft delete so the job shows up in listings again. Admin only.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Success      200 {object}  dto.JobResponse "Job restored successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin role required"
+// @Failure      404 {object}  dto.ErrorResponse "Deleted job not found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/restore [post]
+// @Security     BearerAuth
+func (h *JobHandler) RestoreJob(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	idStr := c.Param("id")
+	jobID, err := uuid.Parse(idStr)
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	req := dto.RestoreJobRequest{ID: jobID, UserID: userID}
+
+	job, err := h.service.RestoreJob(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Deleted job not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapJobModelToJobResponse(job))
+}
+
+// GetEmployerStats godoc
+// @Summary      Get employer posting statistics
+// @Description  Returns the number of jobs per state, the average rate and the total invoiced value across the employer's jobs. Deleted jobs are not counted. Only the employer themself may view their stats.
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Employer (user) ID" Format(uuid)
+// @Success      200 {object}  dto.EmployerStatsResponse "Employer statistics"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Not the employer in question"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/{id}/employer-stats [get]
+// @Security     BearerAuth
+func (h *JobHandler) GetEmployerStats(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("GetEmployerStats: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	employerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid user ID format"))
+		return
+	}
+
+	req := dto.EmployerStatsRequest{EmployerID: employerID, UserID: userID}
+
+	stats, err := h.service.GetEmployerStats(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MapEmployerStatsToResponse(stats))
+}
+
+// SaveJob godoc
+// @Summary      Save a job
+// @Description  Bookmarks a job for the authenticated user. Saving an already saved job has no effect.
+// @Tags         jobs
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Success      204 {object}  nil "Job saved"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/save [post]
+// @Security     BearerAuth
+func (h *JobHandler) SaveJob(c *gin.Context) {
+	// Get UserID from auth context
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	// Parse JobID from path
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	req := dto.SaveJobRequest{JobID: jobID, UserID: userID}
+	if err := h.service.SaveJob(c.Request.Context(), &req); err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Job not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnsaveJob godoc
+// @Summary      Unsave a job
+// @Description  Removes a job from the authenticated user's saved jobs. Removing a job that isn't saved has no effect.
+// @Tags         jobs
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Success      204 {object}  nil "Job unsaved"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/save [delete]
+// @Security     BearerAuth
+func (h *JobHandler) UnsaveJob(c *gin.Context) {
+	// Get UserID from auth context
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	// Parse JobID from path
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	req := dto.UnsaveJobRequest{JobID: jobID, UserID: userID}
+	if err := h.service.UnsaveJob(c.Request.Context(), &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSavedJobs godoc
+// @Summary      List saved jobs
+// @Description  Retrieves the authenticated user's saved jobs that are still available, most recently saved first. Saved jobs that have been filled, closed or deleted are left out.
+// @Tags         jobs
+// @Produce      json
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
+// @Param        offset query int false "Pagination offset" default(0)
+// @Success      200 {object}  dto.PaginatedResponse[dto.JobResponse] "Successfully retrieved saved jobs"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/saved-jobs [get]
+// @Security     BearerAuth
+func (h *JobHandler) ListSavedJobs(c *gin.Context) {
+	// Get UserID from auth context
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	var req dto.ListSavedJobsRequest
+	// Bind/Validate query params
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.UserID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
+
+	jobs, total, err := h.service.ListSavedJobs(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	jobResponses := make([]dto.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobResponses = append(jobResponses, MapJobModelToJobResponse(&job))
+	}
+
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(jobResponses, total, req.Limit, req.Offset))
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobHandler_CreateJobValidatesTerms(t *testing.T) {
+	now := time.Now()
+	job := &models.Job{ID: uuid.New(), Rate: 50, Duration: 20, InvoiceInterval: 20, EmployerID: uuid.New(), State: models.JobStateWaiting, Version: 1, CreatedAt: now, UpdatedAt: now}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedField  string // Field of the single validation error; empty when the request is valid
+		expectedTag    string
+	}{
+		{name: "ZeroInterval", body: `{"rate": 50, "duration": 20, "invoice_interval": 0}`, expectedStatus: http.StatusBadRequest, expectedField: "InvoiceInterval", expectedTag: "required"},
+		{name: "IntervalLongerThanDuration", body: `{"rate": 50, "duration": 20, "invoice_interval": 30}`, expectedStatus: http.StatusBadRequest, expectedField: "InvoiceInterval", expectedTag: "ltefield"},
+		{name: "NegativeRate", body: `{"rate": -5, "duration": 20, "invoice_interval": 10}`, expectedStatus: http.StatusBadRequest, expectedField: "Rate", expectedTag: "gt"},
+		{name: "IntervalEqualToDuration", body: `{"rate": 50, "duration": 20, "invoice_interval": 20}`, expectedStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			authenticate := func(c *gin.Context) {
+				c.Set("userID", job.EmployerID) // What JWTAuthMiddleware stores
+				c.Next()
+			}
+			router.POST("/jobs", authenticate, NewJobHandler(&stubJobService{job: job}, validator.New()).CreateJob)
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedField == "" {
+				return
+			}
+			var body struct {
+				Code    string           `json:"code"`
+				Details []dto.FieldError `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, dto.ErrorCodeValidationFailed, body.Code)
+			require.Len(t, body.Details, 1)
+			assert.Equal(t, tt.expectedField, body.Details[0].Field)
+			assert.Equal(t, tt.expectedTag, body.Details[0].Tag)
+		})
+	}
+}
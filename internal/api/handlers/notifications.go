@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/notifications"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler streams real-time notifications over WebSocket.
+type NotificationHandler struct {
+	hub *notifications.Hub
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(hub *notifications.Hub) *NotificationHandler {
+	return &NotificationHandler{hub: hub}
+}
+
+// Subscribe godoc
+// @Summary      Subscribe to notifications
+// @Description  Upgrades to a WebSocket that receives the authenticated user's events (e.g. application.received, application.accepted, application.rejected). Browsers can't set headers on WebSocket requests, so the token may also be passed as the access_token query parameter.
+// @Tags         notifications
+// @Param        access_token query string false "JWT access token, if not sent in the Authorization header"
+// @Success      101 {string}  string "Switching Protocols"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Router       /ws/notifications [get]
+// @Security     BearerAuth
+func (h *NotificationHandler) Subscribe(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	// On failure the upgrader has already written an error response
+	if err := h.hub.ServeWS(c.Writer, c.Request, userID); err != nil {
+		logger.FromContext(c.Request.Context()).Info("WebSocket upgrade failed", "error", err)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *stubJobService) ListAvailableJobs(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, int, error) {
+	return []models.Job{*s.job}, s.total, nil
+}
+
+func TestListAvailableJobs_PaginationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	job := &models.Job{ID: uuid.New(), Rate: 50, Duration: 20, InvoiceInterval: 10, EmployerID: uuid.New(), State: models.JobStateWaiting}
+	router := gin.New()
+	router.GET("/api/v1/jobs/available", NewJobHandler(&stubJobService{job: job, total: 45}, validator.New()).ListAvailableJobs)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/available?limit=10&offset=20&min_rate=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "45", w.Header().Get("X-Total-Count"))
+	// Other query parameters are kept; only limit and offset change
+	assert.Equal(t,
+		`</api/v1/jobs/available?limit=10&min_rate=5&offset=0>; rel="first", `+
+			`</api/v1/jobs/available?limit=10&min_rate=5&offset=10>; rel="prev", `+
+			`</api/v1/jobs/available?limit=10&min_rate=5&offset=30>; rel="next", `+
+			`</api/v1/jobs/available?limit=10&min_rate=5&offset=40>; rel="last"`,
+		w.Header().Get("Link"))
+}
+
+func TestSetPaginationHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		limit    int
+		offset   int
+		expected string
+	}{
+		{
+			name:     "FirstPage",
+			total:    25,
+			limit:    10,
+			offset:   0,
+			expected: `</items?limit=10&offset=0>; rel="first", </items?limit=10&offset=10>; rel="next", </items?limit=10&offset=20>; rel="last"`,
+		},
+		{
+			name:     "LastPage",
+			total:    25,
+			limit:    10,
+			offset:   20,
+			expected: `</items?limit=10&offset=0>; rel="first", </items?limit=10&offset=10>; rel="prev", </items?limit=10&offset=20>; rel="last"`,
+		},
+		{
+			name:     "ExactMultiple",
+			total:    20,
+			limit:    10,
+			offset:   0,
+			expected: `</items?limit=10&offset=0>; rel="first", </items?limit=10&offset=10>; rel="next", </items?limit=10&offset=10>; rel="last"`,
+		},
+		{
+			// An offset that isn't a multiple of limit still steps back by one page, stopping at 0
+			name:     "UnalignedOffset",
+			total:    25,
+			limit:    10,
+			offset:   5,
+			expected: `</items?limit=10&offset=0>; rel="first", </items?limit=10&offset=0>; rel="prev", </items?limit=10&offset=15>; rel="next", </items?limit=10&offset=20>; rel="last"`,
+		},
+		{
+			name:     "Empty",
+			total:    0,
+			limit:    10,
+			offset:   0,
+			expected: `</items?limit=10&offset=0>; rel="first", </items?limit=10&offset=0>; rel="last"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/items", nil)
+
+			setPaginationHeaders(c, tt.total, tt.limit, tt.offset)
+			assert.Equal(t, tt.expected, w.Header().Get("Link"))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+// RatingHandler holds dependencies for rating operations.
+type RatingHandler struct {
+	service   services.RatingService
+	validator *validator.Validate
+}
+
+// NewRatingHandler creates a new RatingHandler.
+func NewRatingHandler(service services.RatingService, validate *validator.Validate) *RatingHandler {
+	return &RatingHandler{
+		service:   service,
+		validator: validate,
+	}
+}
+
+// SubmitRating godoc
+// @Summary      Rate a job's contractor
+// @Description  Lets the employer rate the contractor of a 'Complete' job with a score from 1 to 5 and an optional comment. Each job can be rated once.
+// @Tags         ratings
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Job ID" Format(uuid)
+// @Param        rating body  dto.SubmitRatingRequest true "Score and optional comment"
+// @Success      201 {object}  dto.RatingResponse "Rating submitted successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid ID or input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - User is not the employer for this job"
+// @Failure      404 {object}  dto.ErrorResponse "Job Not Found"
+// @Failure      409 {object}  dto.ErrorResponse "Conflict - Job not complete or already rated"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /jobs/{id}/rating [post]
+// @Security     BearerAuth
+func (h *RatingHandler) SubmitRating(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("SubmitRating: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid job ID format"))
+		return
+	}
+
+	var req dto.SubmitRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.JobID = jobID
+	req.UserID = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	rating, err := h.service.SubmitRating(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound:     "Job not found",
+			services.ErrForbidden:    "Forbidden: You are not the employer for this job",
+			services.ErrInvalidState: "Only completed jobs can be rated",
+			services.ErrConflict:     "Job has already been rated",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, MapRatingModelToResponse(rating))
+}
+
+// GetUserRating godoc
+// @Summary      Get a user's average rating
+// @Description  Returns the average score and number of ratings a user has received as a contractor.
+// @Tags         ratings
+// @Produce      json
+// @Param        id path      string true  "User ID" Format(uuid)
+// @Success      200 {object}  dto.UserRatingResponse "Successfully retrieved rating"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      404 {object}  dto.ErrorResponse "User Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /users/{id}/rating [get]
+// @Security     BearerAuth
+func (h *RatingHandler) GetUserRating(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid user ID format"))
+		return
+	}
+
+	req := dto.GetUserRatingRequest{UserID: userID}
+	summary, err := h.service.GetUserAverageRating(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapUserRatingSummaryToResponse(summary))
+}
@@ -2,45 +2,95 @@ package handlers
 
 import (
 	"errors" // Import errors for checking specific storage errors
-	"log"
+	"fmt"
 	"net/http"
 
 	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
 	"go-api-template/internal/services"
 	"go-api-template/internal/storage" // Use the interface package
 	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
 	"github.com/google/uuid"
 )
 
+// DefaultMaxBatchGetUsers is how many IDs BatchGetUsers accepts unless WithMaxBatchGetUsers says otherwise.
+const DefaultMaxBatchGetUsers = 100
+
 // UserHandler holds the repository dependency for user operations
 type UserHandler struct {
 	service services.UserService // Use the service interface
 	validator *validator.Validate
+	maxBatchGetUsers int // Most IDs a single BatchGetUsers request may ask for
+	idempotentRegistration bool // Whether Register honors return_existing
+	maxPageSize int // Largest limit GetUsers accepts; larger ones are clamped
 }
 
 // NewUserHandler creates a new UserHandler with the given service
 func NewUserHandler(userService services.UserService, validate *validator.Validate) *UserHandler {
-	return &UserHandler{service: userService, validator: validate}
+	return &UserHandler{service: userService, validator: validate, maxBatchGetUsers: DefaultMaxBatchGetUsers, maxPageSize: dto.DefaultMaxPageLimit}
+}
+
+// WithMaxPageSize sets the largest limit GetUsers accepts. Values below 1 keep the default.
+func (h *UserHandler) WithMaxPageSize(n int) *UserHandler {
+	if n > 0 {
+		h.maxPageSize = n
+	}
+	return h
+}
+
+// WithMaxBatchGetUsers sets how many IDs BatchGetUsers accepts per request. Values below 1 keep the default.
+func (h *UserHandler) WithMaxBatchGetUsers(n int) *UserHandler {
+	if n > 0 {
+		h.maxBatchGetUsers = n
+	}
+	return h
+}
+
+// WithIdempotentRegistration lets Register return the existing user for a repeated registration whose
+// password matches, when the request sets return_existing. Off by default: it tells the caller the
+// password of an existing account.
+func (h *UserHandler) WithIdempotentRegistration(enabled bool) *UserHandler {
+	h.idempotentRegistration = enabled
+	return h
 }
 
 // GetUsers godoc
-// @Summary      List all users
-// @Description  Retrieves a list of all registered users.
+// @Summary      List users
+// @Description  Retrieves a page of registered users ordered by name, optionally filtered by a case-insensitive search of name and email. Admin only.
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   dto.UserResponse "Successfully retrieved list of users" // UPDATED response type
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
+// @Param        offset query int false "Pagination offset" default(0)
+// @Param        search query string false "Substring matched case-insensitively against name and email"
+// @Success      200  {object}  dto.PaginatedResponse[dto.UserResponse] "Successfully retrieved list of users"
+// @Header       200  {integer} X-Total-Count "Number of users matching the search"
+// @Header       200  {string}  Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid query parameters"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized - Invalid token"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Requires admin role"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /users [get]
 // @Security     BearerAuth
 func (h *UserHandler) GetUsers(c *gin.Context) {
-	users, err := h.service.GetAll(c.Request.Context()) // Use h.repo and pass context
+	var req dto.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, badRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
+
+	users, total, err := h.service.ListUsers(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("Error fetching users: %v", err) // Log the actual error
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve users"})
+		respondError(c, err)
 		return
 	}
 
@@ -50,20 +100,24 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 		userResponses = append(userResponses, MapUserModelToUserResponse(&user))
 	}
 
-	c.JSON(http.StatusOK, userResponses)
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(userResponses, total, req.Limit, req.Offset))
 }
 
 // GetUserByID godoc
 // @Summary      Get a user by ID
-// @Description  Retrieves details for a specific user by their ID.
+// @Description  Retrieves details for a specific user by their ID. The full profile, including the email, is returned to the user themselves, to admins, and to the employer or contractor of an Ongoing job with the user; anyone else gets the public view (ID, name and avatar).
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "User ID" Format(uuid) // Specify path param
-// @Success      200  {object}  dto.UserResponse "Successfully retrieved user" // Ensure this is already dto.UserResponse
-// @Failure      400  {object}  map[string]string{error=string} "Invalid user ID format"
-// @Failure      404  {object}  map[string]string{error=string} "User Not Found"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Param        If-None-Match header string false "ETag from a previous response"
+// @Success      200  {object}  dto.UserResponse "Successfully retrieved user; unrelated requesters get the fields of dto.PublicUserResponse only" // Ensure this is already dto.UserResponse
+// @Success      304  "Not Modified - The ETag in If-None-Match is still current"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Invalid user ID format"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User Not Found"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /users/{id} [get]
 // @Security     BearerAuth
 func (h *UserHandler) GetUserByID(c *gin.Context) {
@@ -72,77 +126,221 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	// Parse UUID and handle error
 	parsedID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondError(c, badRequest("Invalid user ID format"))
 		return
 	}
 
-	req := dto.GetUserByIdRequest{ID: parsedID}
+	requesterID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
 
-	user, err := h.service.GetByID(c.Request.Context(), &req)
+	user, err := h.getUser(c, parsedID)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			log.Printf("Error fetching user by ID %s: %v", idStr, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		return
+	}
+
+	// Contact details are only shared with people the user is actually working with
+	fullView := requesterID == user.ID
+	if !fullView {
+		role, _ := middleware.GetUserRoleFromContext(c)
+		fullView = models.UserRole(role).Can(models.CapabilityManageUsers)
+	}
+	if !fullView {
+		fullView, err = h.service.SharesActiveJob(c.Request.Context(), requesterID, user.ID)
+		if err != nil {
+			respondError(c, err)
+			return
 		}
+	}
+
+	if !fullView {
+		respondWithETag(c, MapUserModelToPublicUserResponse(user))
 		return
 	}
+	respondWithETag(c, MapUserModelToUserResponse(user))
+}
 
-	// Map to response DTO
-	userResponse := MapUserModelToUserResponse(user) // Ensure mapping happens here too
-	c.JSON(http.StatusOK, userResponse)
+// GetMe godoc
+// @Summary      Get the current user
+// @Description  Retrieves the profile of the authenticated user, so clients don't need to know their own ID.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        If-None-Match header string false "ETag from a previous response"
+// @Success      200  {object}  dto.UserResponse "Successfully retrieved user"
+// @Success      304  "Not Modified - The ETag in If-None-Match is still current"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User Not Found - The account was deleted after the token was issued"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /users/me [get]
+// @Security     BearerAuth
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	user, err := h.getUser(c, userID)
+	if err != nil {
+		return
+	}
+	respondWithETag(c, MapUserModelToUserResponse(user))
+}
+
+// GetMyPermissions godoc
+// @Summary      Get the current user's permissions
+// @Description  Lists every capability (can_create_job, can_access_admin, ...) with whether the authenticated user has it, from their role and their jobs, so clients can show or hide features. The API still checks every request.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.PermissionsResponse "Successfully computed permissions"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User Not Found - The account was deleted after the token was issued"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /users/me/permissions [get]
+// @Security     BearerAuth
+func (h *UserHandler) GetMyPermissions(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	permissions, err := h.service.GetPermissions(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+		})
+		return
+	}
+
+	response := dto.PermissionsResponse{Role: string(permissions.Role), Capabilities: make(map[string]bool, len(models.AllCapabilities))}
+	for _, capability := range models.AllCapabilities {
+		response.Capabilities[string(capability)] = false
+	}
+	for _, capability := range permissions.Capabilities {
+		response.Capabilities[string(capability)] = true
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// getUser fetches the user with the given ID. If there is no such (undeleted) user, or the lookup
+// fails, it has already written the error response.
+func (h *UserHandler) getUser(c *gin.Context, id uuid.UUID) (*models.User, error) {
+	user, err := h.service.GetByID(c.Request.Context(), &dto.GetUserByIdRequest{ID: id})
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+		})
+		return nil, err
+	}
+	return user, nil
+}
+
+// BatchGetUsers godoc
+// @Summary      Get several users by ID
+// @Description  Looks up a batch of users in one request, e.g. the contractors of a list of applications. Users are returned in the order their IDs were sent, duplicates are collapsed and unknown IDs are silently omitted. At most a configured number of IDs (default 100) may be sent.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request body      dto.BatchGetUsersRequest true  "IDs of the users to fetch"
+// @Success      200  {array}   dto.UserResponse "Users that were found"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input or too many IDs"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /users/batch-get [post]
+// @Security     BearerAuth
+func (h *UserHandler) BatchGetUsers(c *gin.Context) {
+	var req dto.BatchGetUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if len(req.IDs) > h.maxBatchGetUsers {
+		respondError(c, badRequest(fmt.Sprintf("At most %d user IDs may be requested at once", h.maxBatchGetUsers)))
+		return
+	}
+
+	users, err := h.service.GetByIDs(c.Request.Context(), req.IDs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	userResponses := make([]dto.UserResponse, 0, len(users))
+	for i := range users {
+		userResponses = append(userResponses, MapUserModelToUserResponse(&users[i]))
+	}
+	c.JSON(http.StatusOK, userResponses)
 }
 
 // --- Authentication Handlers ---
 
 // Register godoc
 // @Summary      Register a new user
-// @Description  Adds a new user to the database with a hashed password.
+// @Description  Adds a new user to the database with a hashed password. When idempotent registration is enabled and return_existing is set, repeating a registration with the same email and password returns the existing user with 200 instead of 409.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        user body      dto.CreateUserRequest true  "User registration details (ID is ignored/generated)"
 // @Success      201  {object}  dto.UserResponse "User registered successfully"
-// @Failure      400  {object}  map[string]string{error=string} "Bad Request - Invalid input or validation failed"
-// @Failure      409  {object}  map[string]string{error=string} "Conflict - Email already exists"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Success      200  {object}  dto.UserResponse "Existing user returned for a repeated registration (return_existing)"
+// @Header       201 {string}  Location "URL of the created resource"
+// @Failure      400  {object}  dto.ErrorResponse "Bad Request - Invalid input, validation failed (one entry per field in details), or password does not meet the policy (unmet requirements in details)"
+// @Failure      409  {object}  dto.ErrorResponse{error=string} "Conflict - Email already exists (and, with return_existing, the password doesn't match)"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /auth/register [post]
 func (h *UserHandler) Register(c *gin.Context) {
 	var req dto.CreateUserRequest
 
 	// Bind JSON body
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 
 	// Validate the request struct
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
+		return
+	}
+
+	if req.ReturnExisting && h.idempotentRegistration {
+		user, created, err := h.service.RegisterOrGet(c.Request.Context(), &req)
+		if err != nil {
+			respondError(c, err, errorMessages{
+				storage.ErrConflict: "User conflict",
+			})
+			return
+		}
+		if !created {
+			c.JSON(http.StatusOK, MapUserModelToUserResponse(user))
+			return
+		}
+		respondCreated(c, "/users/"+user.ID.String(), MapUserModelToUserResponse(user))
 		return
 	}
 
 	createdUser, err := h.service.Register(c.Request.Context(), &req) // Call storage Create
 	if err != nil {
-		// Check for specific duplicate email error
-		if errors.Is(err, storage.ErrDuplicateEmail) {
-			c.JSON(http.StatusConflict, gin.H{"error": "Email address already registered"})
-		// Check for general conflict (e.g., if ID was somehow duplicated, though unlikely now)
-		} else if errors.Is(err, storage.ErrConflict) {
-			c.JSON(http.StatusConflict, gin.H{"error": "User conflict"})
-		} else {
-			log.Printf("Error registering user: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
-		}
+		respondError(c, err, errorMessages{
+			storage.ErrConflict: "User conflict",
+		})
 		return
 	}
 
 	// Map to response DTO to exclude sensitive info (like password hash)
 	userResponse := MapUserModelToUserResponse(createdUser)
 
-	c.JSON(http.StatusCreated, userResponse)
+	respondCreated(c, "/users/"+createdUser.ID.String(), userResponse)
 }
 
 // Login godoc
@@ -153,32 +351,32 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Produce      json
 // @Param        credentials body      dto.LoginRequest true  "User login credentials"
 // @Success      200  {object}  dto.LoginResponse "Login successful"
-// @Failure      400  {object}  map[string]string{error=string} "Bad Request - Invalid input or validation failed"
-// @Failure      401  {object}  map[string]string{error=string} "Unauthorized - Invalid credentials"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input or validation failed"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized - Invalid credentials"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Email not verified"
+// @Failure      423  {object}  dto.ErrorResponse{error=string} "Locked - Too many failed login attempts"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /auth/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 
 	user, accessToken, refreshToken, err := h.service.Login(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
-		} else {
-			log.Printf("Error logging in user %s: %v", req.Email, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrInvalidCredentials: "Invalid email or password",
+			services.ErrEmailNotVerified:   "Email address has not been verified",
+			services.ErrAccountLocked:      "Account temporarily locked after too many failed login attempts",
+		})
 		return
 	}
 
@@ -189,7 +387,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		RefreshToken: refreshToken,
 	}
 
-	log.Printf("User logged in successfully: %s", user.Email)
+	logger.FromContext(c.Request.Context()).Info("User logged in successfully", "email", user.Email)
 	c.JSON(http.StatusOK, loginResponse)
 }
 
@@ -201,26 +399,23 @@ func (h *UserHandler) Login(c *gin.Context) {
 // @Produce      json
 // @Param        refreshRequest body      dto.RefreshRequest true  "Refresh token"
 // @Success      200  {object}  dto.LoginResponse "Token refreshed successfully" // Reusing LoginResponse structure
-// @Failure      400  {object}  map[string]string{error=string} "Bad Request - Invalid input"
-// @Failure      401  {object}  map[string]string{error=string} "Unauthorized - Invalid or expired refresh token"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized - Invalid or expired refresh token"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /auth/refresh [post]
 func (h *UserHandler) Refresh(c *gin.Context) {
 	var req dto.RefreshRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 
 	newAccessToken, newRefreshToken, err := h.service.Refresh(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidCredentials) { // Reuse error for invalid/expired refresh token
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
-		} else {
-			log.Printf("Error refreshing token: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrInvalidCredentials: "Invalid or expired refresh token",
+		})
 		return
 	}
 
@@ -229,52 +424,246 @@ func (h *UserHandler) Refresh(c *gin.Context) {
 		"refreshToken": newRefreshToken,
 	}
 
-	log.Println("Token refreshed successfully")
+	logger.FromContext(c.Request.Context()).Info("Token refreshed successfully")
 	c.JSON(http.StatusOK, refreshResponse) // Consider a dedicated RefreshResponse DTO later
 }
 
 // Logout godoc
 // @Summary      Log out user
-// @Description  Invalidates the user's refresh token.
+// @Description  Invalidates the user's refresh token. When the request carries a valid access token, that token is revoked too, so it stops working immediately; an expired or missing access token doesn't prevent logging out.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        refreshRequest body      dto.RefreshRequest true  "Refresh token to invalidate" // Reusing RefreshRequest DTO
 // @Success      204  {object}  nil "Logout successful"
-// @Failure      400  {object}  map[string]string{error=string} "Bad Request - Invalid input"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /auth/logout [post]
 func (h *UserHandler) Logout(c *gin.Context) {
 	var req dto.LogoutRequest // Reuse RefreshRequest to get the token
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
+	if accessToken, ok := middleware.GetAccessTokenFromContext(c); ok {
+		req.AccessTokenID, req.AccessTokenExpiresAt = accessToken.ID, accessToken.ExpiresAt
+	}
 
 	if err := h.service.Logout(c.Request.Context(), &req); err != nil {
-		log.Printf("Error during logout for token %s: %v", req.RefreshToken, err)
+		logger.FromContext(c.Request.Context()).Error("Error during logout", "refresh_token", req.RefreshToken, "error", err)
+	}
+
+	logger.FromContext(c.Request.Context()).Info("Logout successful", "refresh_token", req.RefreshToken)
+	c.Status(http.StatusNoContent)
+}
+
+// RequestPasswordReset godoc
+// @Summary      Request a password reset
+// @Description  Emails a single-use password reset token. Always returns 202 so it can't be used to check which emails are registered.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body      dto.PasswordResetRequest true  "Email of the account to reset"
+// @Success      202  {object}  map[string]string{message=string} "Reset email sent if the account exists"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input or validation failed"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /auth/password-reset [post]
+func (h *UserHandler) RequestPasswordReset(c *gin.Context) {
+	var req dto.PasswordResetRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(c.Request.Context(), &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account with that email exists, a password reset email has been sent"})
+}
+
+// ConfirmPasswordReset godoc
+// @Summary      Confirm a password reset
+// @Description  Sets a new password using a password reset token. The token can only be used once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body      dto.ConfirmPasswordResetRequest true  "Reset token and new password"
+// @Success      204  {object}  nil "Password reset successfully"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input, weak password (unmet requirements in details), or invalid/expired token"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /auth/password-reset/confirm [post]
+func (h *UserHandler) ConfirmPasswordReset(c *gin.Context) {
+	var req dto.ConfirmPasswordResetRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.ConfirmPasswordReset(c.Request.Context(), &req); err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) || errors.Is(err, services.ErrNotFound) {
+			err = invalidToken("Invalid or expired password reset token")
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Changes the authenticated user's own password after checking the current one. All of the user's refresh tokens are revoked, so other sessions must log in again.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                    true  "User ID" Format(uuid)
+// @Param        request body      dto.ChangePasswordRequest true  "Current and new password"
+// @Success      204  {object}  nil "Password changed successfully"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input or password does not meet the policy (unmet requirements in details)"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized - Invalid token or wrong current password"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Not allowed to change this user's password"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User Not Found"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /users/{id}/change-password [post]
+// @Security     BearerAuth
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid user ID format"))
+		return
+	}
+
+	requestingUserId, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+	if requestingUserId != parsedID {
+		respondError(c, forbidden("You are not allowed to change this user's password"))
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
+		return
+	}
+	req.ID = parsedID // Set ID from path
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), &req); err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrInvalidCredentials: "Current password is incorrect",
+			services.ErrNotFound:           "User not found",
+		})
+		return
 	}
 
-	log.Printf("Logout successful for token: %s", req.RefreshToken)
 	c.Status(http.StatusNoContent)
 }
 
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Marks the user's email as verified using the token sent at registration. The token can only be used once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body      dto.VerifyEmailRequest true  "Verification token"
+// @Success      200  {object}  dto.UserResponse "Email verified successfully"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input or invalid/expired token"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /auth/verify-email [post]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	user, err := h.service.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) || errors.Is(err, services.ErrNotFound) {
+			err = invalidToken("Invalid or expired verification token")
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MapUserModelToUserResponse(user))
+}
+
+// ResendVerificationEmail godoc
+// @Summary      Resend verification email
+// @Description  Sends a new email verification token. Always returns 202 so it can't be used to check which emails are registered.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body      dto.SendVerificationEmailRequest true  "Email to verify"
+// @Success      202  {object}  map[string]string{message=string} "Verification email sent if the account exists and is unverified"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input or validation failed"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /auth/verify-email/resend [post]
+func (h *UserHandler) ResendVerificationEmail(c *gin.Context) {
+	var req dto.SendVerificationEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.SendVerificationEmail(c.Request.Context(), &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an unverified account with that email exists, a verification email has been sent"})
+}
+
 // UpdateUser godoc
 // @Summary      Update an existing user
-// @Description  Updates details for an existing user identified by ID.
+// @Description  Updates details for an existing user identified by ID. Changing the email marks the user unverified and sends a verification email to the new address.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string      true  "User ID" Format(uuid)
 // @Param        user body      dto.UpdateUserRequest true  "User object with updated fields" // Use DTO for body param
 // @Success      200  {object}  dto.UserResponse "User updated successfully" // UPDATED response type
-// @Failure      400  {object}  map[string]string{error=string} "Bad Request - Invalid input or validation failed"
-// @Failure 	 401  {object}  map[string]string{error=string} "Unauthorized - Invalid token"
-// @Failure      403  {object}  map[string]string{error=string} "Forbidden - Not allowed to update this user"
-// @Failure      404  {object}  map[string]string{error=string} "User Not Found"
-// @Failure      409  {object}  map[string]string{error=string} "Conflict - e.g., duplicate email"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid input, validation failed (e.g. invalid email format), or email unchanged"
+// @Failure 	 401  {object}  dto.ErrorResponse{error=string} "Unauthorized - Invalid token"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Not allowed to update this user"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User Not Found"
+// @Failure      409  {object}  dto.ErrorResponse{error=string} "Conflict - e.g., duplicate email"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /users/{id} [put]
 // @Security     BearerAuth
 func (h *UserHandler) UpdateUser(c *gin.Context) {
@@ -283,44 +672,39 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	// Parse UUID and handle error
 	parsedID, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondError(c, badRequest("Invalid user ID format"))
 		return
 	}
 
 	var req dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondError(c, badRequest("Invalid request body: " + err.Error()))
 		return
 	}
 	req.ID = parsedID // Set ID from path
 
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := FormatValidationErrors(err.(validator.ValidationErrors))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		respondValidationError(c, err)
 		return
 	}
 
 	requestingUserId, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	if requestingUserId != parsedID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this user"})
+		respondError(c, forbidden("You are not allowed to update this user"))
 		return
 	}
 
 	updatedUser, err := h.service.Update(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else if errors.Is(err, storage.ErrConflict) {
-			c.JSON(http.StatusConflict, gin.H{"error": "Update resulted in a conflict"})
-		} else {
-			log.Printf("Error updating user %s: %v", idStr, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
-		}
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+			services.ErrConflict: "Email address already registered",
+		})
 		return
 	}
 
@@ -337,11 +721,11 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      string  true  "User ID" Format(uuid)
 // @Success      204  {object}  nil "User deleted successfully" // 204 No Content
-// @Failure      400  {object}  map[string]string{error=string} "Bad Request - Invalid user ID format"
-// @Failure 	 401  {object}  map[string]string{error=string} "Unauthorized - Invalid token"
-// @Failure      403  {object}  map[string]string{error=string} "Forbidden - Not allowed to delete this user"
-// @Failure      404  {object}  map[string]string{error=string} "User Not Found"
-// @Failure      500  {object}  map[string]string{error=string} "Internal Server Error"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Bad Request - Invalid user ID format"
+// @Failure 	 401  {object}  dto.ErrorResponse{error=string} "Unauthorized - Invalid token"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Not allowed to delete this user"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User Not Found"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
 // @Router       /users/{id} [delete]
 // @Security     BearerAuth
 func (h *UserHandler) DeleteUser(c *gin.Context) {
@@ -352,33 +736,108 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userDelete.ID = uuid.MustParse(id)
 
 	if err := h.validator.Struct(userDelete); err != nil {
-        // Handle validation errors
-        validationErrors := err.(validator.ValidationErrors)
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": FormatValidationErrors(validationErrors)})
+        respondValidationError(c, err)
         return
     }
 
 	requestingUserId, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, unauthorized("Unauthorized"))
 		return
 	}
 
 	if requestingUserId != userDelete.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete this user"})
+		respondError(c, forbidden("You are not allowed to delete this user"))
 		return
 	}
 
 	err = h.service.Delete(c.Request.Context(), &userDelete) // Use h.repo
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			log.Printf("Error deleting user %s: %v", id, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
-		}
+		respondError(c, err, errorMessages{
+			storage.ErrNotFound: "User not found",
+		})
 		return
 	}
 
 	c.Status(http.StatusNoContent) // Standard response for successful DELETE
 }
+
+// RestoreUser godoc
+// @Summary      Restore a deleted user
+// @Description  Undoes a soft delete so the user can log in again. Admin only.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "User ID" Format(uuid)
+// @Success      200  {object}  dto.UserResponse "User restored successfully"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Invalid user ID format"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Admin role required"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "Deleted user not found"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /users/{id}/restore [post]
+// @Security     BearerAuth
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	idStr := c.Param("id")
+	parsedID, err := uuid.Parse(idStr)
+	if err != nil {
+		respondError(c, badRequest("Invalid user ID format"))
+		return
+	}
+
+	req := dto.RestoreUserRequest{ID: parsedID}
+
+	user, err := h.service.RestoreUser(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Deleted user not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapUserModelToUserResponse(user))
+}
+
+// ImpersonateUser godoc
+// @Summary      Impersonate a user
+// @Description  Mints a short-lived access token that authenticates as the user, for support staff reproducing an issue. The token names the admin in its act claim, so changes made with it are audited against both; it can't be refreshed. Admins can't be impersonated. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID" Format(uuid)
+// @Success      200  {object}  dto.ImpersonationResponse "Impersonation token issued"
+// @Failure      400  {object}  dto.ErrorResponse{error=string} "Invalid user ID format, or the admin's own ID"
+// @Failure      401  {object}  dto.ErrorResponse{error=string} "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse{error=string} "Forbidden - Admin role required, or the user is an admin"
+// @Failure      404  {object}  dto.ErrorResponse{error=string} "User not found"
+// @Failure      500  {object}  dto.ErrorResponse{error=string} "Internal Server Error"
+// @Router       /admin/users/{id}/impersonate [post]
+// @Security     BearerAuth
+func (h *UserHandler) ImpersonateUser(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid user ID format"))
+		return
+	}
+
+	req := dto.ImpersonateUserRequest{ID: parsedID, AdminID: adminID}
+
+	user, accessToken, expiresAt, err := h.service.ImpersonateUser(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "User not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ImpersonationResponse{
+		User:      MapUserModelToUserResponse(user),
+		Token:     accessToken,
+		ExpiresAt: expiresAt,
+	})
+}
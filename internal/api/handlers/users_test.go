@@ -0,0 +1,607 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubUserService implements only Update and Login; any other method panics through the nil embedded interface.
+type stubUserService struct {
+	services.UserService
+	updateCalls int
+	updateErr   error
+	loginErr    error
+}
+
+func (s *stubUserService) Login(ctx context.Context, req *dto.LoginRequest) (*models.User, string, string, error) {
+	if s.loginErr != nil {
+		return nil, "", "", s.loginErr
+	}
+	return &models.User{ID: uuid.New(), Email: req.Email}, "access", "refresh", nil
+}
+
+func (s *stubUserService) Update(ctx context.Context, req *dto.UpdateUserRequest) (*models.User, error) {
+	s.updateCalls++
+	if s.updateErr != nil {
+		return nil, s.updateErr
+	}
+	return &models.User{ID: req.ID, Email: *req.Email, Verified: false}, nil
+}
+
+func performUpdateUser(t *testing.T, service services.UserService, userID uuid.UUID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", userID) // What JWTAuthMiddleware stores
+		c.Next()
+	}
+	router.PUT("/users/:id", authenticate, NewUserHandler(service, validator.New()).UpdateUser)
+
+	req := httptest.NewRequest(http.MethodPut, "/users/"+userID.String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUserHandler_UpdateUserEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{name: "Success", body: `{"email":"new@test.com"}`, expectedStatus: http.StatusOK, expectCall: true},
+		{name: "InvalidFormat", body: `{"email":"not-an-email"}`, expectedStatus: http.StatusBadRequest, expectCall: false},
+		{name: "Conflict", body: `{"email":"taken@test.com"}`, serviceErr: fmt.Errorf("%w: updating user", services.ErrConflict), expectedStatus: http.StatusConflict, expectCall: true},
+		{name: "Unchanged", body: `{"email":"same@test.com"}`, serviceErr: fmt.Errorf("%w: email is unchanged", services.ErrValidation), expectedStatus: http.StatusBadRequest, expectCall: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubUserService{updateErr: tt.serviceErr}
+
+			w := performUpdateUser(t, service, uuid.New(), tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectCall {
+				require.Equal(t, 1, service.updateCalls)
+			} else {
+				require.Equal(t, 0, service.updateCalls, "Invalid input must be rejected before reaching the service")
+			}
+		})
+	}
+}
+
+func TestUserHandler_RegisterValidationErrorShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// The stub panics if Register is reached, so invalid input must stop in the handler
+	router.POST("/auth/register", NewUserHandler(&stubUserService{}, validator.New()).Register)
+
+	body := `{"email":"not-an-email","name":"` + strings.Repeat("n", 101) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{
+		"code": "validation_failed",
+		"message": "Validation failed",
+		"details": [
+			{"field": "Email", "tag": "email", "message": "Field 'Email' must be a valid email address"},
+			{"field": "Name", "tag": "max", "message": "Field 'Name' must be at most 100 characters long"},
+			{"field": "Password", "tag": "required", "message": "Field 'Password' is required"}
+		]
+	}`, w.Body.String())
+}
+
+// stubRegisterService records which registration method the handler called.
+type stubRegisterService struct {
+	services.UserService
+	existing      *models.User // Returned by RegisterOrGet with created false when set
+	err           error
+	registerCalls int
+	getCalls      int
+}
+
+func (s *stubRegisterService) Register(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error) {
+	s.registerCalls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &models.User{ID: uuid.New(), Email: req.Email}, nil
+}
+
+func (s *stubRegisterService) RegisterOrGet(ctx context.Context, req *dto.CreateUserRequest) (*models.User, bool, error) {
+	s.getCalls++
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	if s.existing != nil {
+		return s.existing, false, nil
+	}
+	return &models.User{ID: uuid.New(), Email: req.Email}, true, nil
+}
+
+func TestUserHandler_RegisterReturnExisting(t *testing.T) {
+	existing := &models.User{ID: uuid.New(), Email: "taken@test.com", Name: "Existing"}
+	conflict := fmt.Errorf("%w: duplicate email", services.ErrConflict)
+
+	tests := []struct {
+		name                string
+		enabled             bool
+		body                string
+		service             *stubRegisterService
+		expectedStatus      int
+		expectRegisterOrGet bool // RegisterOrGet rather than Register
+	}{
+		{name: "PasswordMatches", enabled: true, body: `{"email":"taken@test.com","password":"Password123","return_existing":true}`, service: &stubRegisterService{existing: existing}, expectedStatus: http.StatusOK, expectRegisterOrGet: true},
+		{name: "PasswordMismatch", enabled: true, body: `{"email":"taken@test.com","password":"Wrong123","return_existing":true}`, service: &stubRegisterService{err: conflict}, expectedStatus: http.StatusConflict, expectRegisterOrGet: true},
+		{name: "NewUser", enabled: true, body: `{"email":"new@test.com","password":"Password123","return_existing":true}`, service: &stubRegisterService{}, expectedStatus: http.StatusCreated, expectRegisterOrGet: true},
+		{name: "NotRequested", enabled: true, body: `{"email":"taken@test.com","password":"Password123"}`, service: &stubRegisterService{err: conflict}, expectedStatus: http.StatusConflict},
+		{name: "Disabled", enabled: false, body: `{"email":"taken@test.com","password":"Password123","return_existing":true}`, service: &stubRegisterService{existing: existing, err: conflict}, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/auth/register", NewUserHandler(tt.service, validator.New()).WithIdempotentRegistration(tt.enabled).Register)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectRegisterOrGet {
+				assert.Equal(t, 1, tt.service.getCalls)
+				assert.Zero(t, tt.service.registerCalls)
+			} else {
+				assert.Zero(t, tt.service.getCalls)
+				assert.Equal(t, 1, tt.service.registerCalls)
+			}
+			switch w.Code {
+			case http.StatusOK:
+				var body dto.UserResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, existing.ID, body.ID)
+				assert.Empty(t, w.Header().Get("Location"), "nothing was created")
+			case http.StatusCreated:
+				assert.NotEmpty(t, w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestUserHandler_LoginErrorStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusOK},
+		{name: "InvalidCredentials", serviceErr: services.ErrInvalidCredentials, expectedStatus: http.StatusUnauthorized},
+		{name: "EmailNotVerified", serviceErr: services.ErrEmailNotVerified, expectedStatus: http.StatusForbidden},
+		{name: "AccountLocked", serviceErr: services.ErrAccountLocked, expectedStatus: http.StatusLocked},
+		{name: "InternalError", serviceErr: fmt.Errorf("boom"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/auth/login", NewUserHandler(&stubUserService{loginErr: tt.serviceErr}, validator.New()).Login)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"user@example.com","password":"Password1"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+// stubBatchUserService implements only GetByIDs, returning the users whose IDs it knows in request order.
+type stubBatchUserService struct {
+	services.UserService
+	users map[uuid.UUID]models.User
+	calls int
+}
+
+func (s *stubBatchUserService) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	s.calls++
+	users := []models.User{}
+	for _, id := range ids {
+		if user, ok := s.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func TestUserHandler_BatchGetUsers(t *testing.T) {
+	first := models.User{ID: uuid.New(), Name: "First", Email: "first@example.com", Role: models.UserRoleUser}
+	second := models.User{ID: uuid.New(), Name: "Second", Email: "second@example.com", Role: models.UserRoleUser}
+	idList := func(ids ...uuid.UUID) string {
+		quoted := make([]string, len(ids))
+		for i, id := range ids {
+			quoted[i] = `"` + id.String() + `"`
+		}
+		return `{"ids":[` + strings.Join(quoted, ",") + `]}`
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedNames  []string
+		expectCall     bool
+	}{
+		{name: "OrderFollowsRequest", body: idList(second.ID, uuid.New(), first.ID), expectedStatus: http.StatusOK, expectedNames: []string{"Second", "First"}, expectCall: true},
+		{name: "AtCap", body: idList(first.ID, second.ID, uuid.New()), expectedStatus: http.StatusOK, expectedNames: []string{"First", "Second"}, expectCall: true},
+		{name: "OverCap", body: idList(first.ID, second.ID, uuid.New(), uuid.New()), expectedStatus: http.StatusBadRequest},
+		{name: "Empty", body: `{"ids":[]}`, expectedStatus: http.StatusBadRequest},
+		{name: "InvalidID", body: `{"ids":["not-a-uuid"]}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubBatchUserService{users: map[uuid.UUID]models.User{first.ID: first, second.ID: second}}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/users/batch-get", NewUserHandler(service, validator.New()).WithMaxBatchGetUsers(3).BatchGetUsers)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/batch-get", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.expectCall, service.calls == 1)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var users []dto.UserResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+			names := make([]string, 0, len(users))
+			for _, user := range users {
+				names = append(names, user.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+// stubListUserService records the request ListUsers received and returns users as the whole match.
+type stubListUserService struct {
+	services.UserService
+	users    []models.User
+	received *dto.ListUsersRequest
+}
+
+func (s *stubListUserService) ListUsers(ctx context.Context, req *dto.ListUsersRequest) ([]models.User, int, error) {
+	s.received = req
+	return s.users, len(s.users), nil
+}
+
+func TestUserHandler_GetUsers(t *testing.T) {
+	users := []models.User{
+		{ID: uuid.New(), Name: "Ada", Email: "ada@example.com", Role: models.UserRoleUser},
+		{ID: uuid.New(), Name: "Grace", Email: "grace@example.com", Role: models.UserRoleAdmin},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedLimit  int
+		expectedOffset int
+		expectedSearch *string
+	}{
+		{name: "Defaults", query: "", expectedStatus: http.StatusOK, expectedLimit: dto.DefaultPageLimit},
+		{name: "ZeroLimitUsesDefault", query: "?limit=0&offset=5", expectedStatus: http.StatusOK, expectedLimit: dto.DefaultPageLimit, expectedOffset: 5},
+		{name: "LimitAboveMaxIsClamped", query: "?limit=1000", expectedStatus: http.StatusOK, expectedLimit: 50},
+		{name: "NegativeOffsetIsZero", query: "?limit=20&offset=-3", expectedStatus: http.StatusOK, expectedLimit: 20},
+		{name: "Search", query: "?search=ADA", expectedStatus: http.StatusOK, expectedLimit: dto.DefaultPageLimit, expectedSearch: func() *string { s := "ADA"; return &s }()},
+		{name: "SearchTooLong", query: "?search=" + strings.Repeat("a", 201), expectedStatus: http.StatusBadRequest},
+		{name: "InvalidLimit", query: "?limit=many", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubListUserService{users: users}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/users", NewUserHandler(service, validator.New()).WithMaxPageSize(50).GetUsers)
+
+			req := httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				assert.Nil(t, service.received, "An invalid request must not reach the service")
+				return
+			}
+			require.NotNil(t, service.received)
+			assert.Equal(t, tt.expectedLimit, service.received.Limit)
+			assert.Equal(t, tt.expectedOffset, service.received.Offset)
+			assert.Equal(t, tt.expectedSearch, service.received.Search)
+
+			var page dto.PaginatedResponse[dto.UserResponse]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			assert.Equal(t, 2, page.Total)
+			assert.Equal(t, tt.expectedLimit, page.Limit)
+			require.Len(t, page.Items, 2)
+			assert.Equal(t, "Ada", page.Items[0].Name)
+			assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+		})
+	}
+}
+
+func TestUserHandler_GetMe(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "me@example.com", Name: "Me", Role: models.UserRoleUser}
+	handler := NewUserHandler(&stubUserGetter{user: user}, validator.New())
+
+	tests := []struct {
+		name           string
+		userID         *uuid.UUID // What the auth middleware stored; nil means unauthenticated
+		expectedStatus int
+	}{
+		{name: "Success", userID: &user.ID, expectedStatus: http.StatusOK},
+		{name: "DeletedSinceTokenIssued", userID: func() *uuid.UUID { id := uuid.New(); return &id }(), expectedStatus: http.StatusNotFound},
+		{name: "Unauthenticated", userID: nil, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			authenticate := func(c *gin.Context) {
+				if tt.userID != nil {
+					c.Set("userID", *tt.userID) // What JWTAuthMiddleware stores
+				}
+				c.Next()
+			}
+			router.GET("/users/me", authenticate, handler.GetMe)
+			router.GET("/users/:id", authenticate, handler.GetUserByID)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/me", nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp dto.UserResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, user.ID, resp.ID)
+				assert.Equal(t, user.Email, resp.Email)
+			}
+		})
+	}
+}
+
+// stubPermissionsService answers GetPermissions for users, from their role plus any extra capabilities
+// their jobs grant; any other method panics through the nil embedded interface.
+type stubPermissionsService struct {
+	services.UserService
+	users map[uuid.UUID]models.UserRole
+	extra []models.Capability
+}
+
+func (s *stubPermissionsService) GetPermissions(ctx context.Context, userID uuid.UUID) (*models.UserPermissions, error) {
+	role, ok := s.users[userID]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return &models.UserPermissions{Role: role, Capabilities: append(models.RoleCapabilities(role), s.extra...)}, nil
+}
+
+func TestUserHandler_GetMyPermissions(t *testing.T) {
+	adminID, userID, employerID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		userID         uuid.UUID
+		extra          []models.Capability
+		expectedStatus int
+		expectedRole   string
+		granted        []models.Capability
+	}{
+		{
+			name:           "Admin",
+			userID:         adminID,
+			expectedStatus: http.StatusOK,
+			expectedRole:   "admin",
+			granted: []models.Capability{
+				models.CapabilityCreateJob, models.CapabilityApplyToJobs, models.CapabilityAccessAdmin, models.CapabilityManageUsers,
+				models.CapabilityRestoreJobs, models.CapabilityManageWebhooks, models.CapabilityManageAPIKeys, models.CapabilityImpersonateUsers,
+			},
+		},
+		{
+			name:           "RegularUser",
+			userID:         userID,
+			expectedStatus: http.StatusOK,
+			expectedRole:   "user",
+			granted:        []models.Capability{models.CapabilityCreateJob, models.CapabilityApplyToJobs},
+		},
+		{
+			name:           "EmployerOfWaitingJob",
+			userID:         employerID,
+			extra:          []models.Capability{models.CapabilityReviewApplications},
+			expectedStatus: http.StatusOK,
+			expectedRole:   "user",
+			granted:        []models.Capability{models.CapabilityCreateJob, models.CapabilityApplyToJobs, models.CapabilityReviewApplications},
+		},
+		{name: "DeletedSinceTokenIssued", userID: uuid.New(), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubPermissionsService{
+				users: map[uuid.UUID]models.UserRole{adminID: models.UserRoleAdmin, userID: models.UserRoleUser, employerID: models.UserRoleUser},
+				extra: tt.extra,
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/users/me/permissions", func(c *gin.Context) {
+				c.Set("userID", tt.userID) // What JWTAuthMiddleware stores
+				c.Next()
+			}, NewUserHandler(service, validator.New()).GetMyPermissions)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/me/permissions", nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var resp dto.PermissionsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedRole, resp.Role)
+
+			// Every capability is listed, so clients can tell "denied" from "unknown"
+			expected := make(map[string]bool, len(models.AllCapabilities))
+			for _, capability := range models.AllCapabilities {
+				expected[string(capability)] = false
+			}
+			for _, capability := range tt.granted {
+				expected[string(capability)] = true
+			}
+			assert.Equal(t, expected, resp.Capabilities)
+		})
+	}
+}
+
+func TestUserHandler_GetUserByIDMasksEmail(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "worker@example.com", Name: "Worker", Role: models.UserRoleUser}
+	employerID := uuid.New()
+	handler := NewUserHandler(&stubUserGetter{user: user, counterparts: []uuid.UUID{employerID}}, validator.New())
+
+	tests := []struct {
+		name          string
+		requesterID   uuid.UUID
+		requesterRole string
+		expectEmail   bool
+	}{
+		{name: "Self", requesterID: user.ID, requesterRole: "user", expectEmail: true},
+		{name: "ActiveJobTogether", requesterID: employerID, requesterRole: "user", expectEmail: true},
+		{name: "Unrelated", requesterID: uuid.New(), requesterRole: "user", expectEmail: false},
+		{name: "Admin", requesterID: uuid.New(), requesterRole: "admin", expectEmail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			authenticate := func(c *gin.Context) {
+				c.Set("userID", tt.requesterID) // What JWTAuthMiddleware stores
+				c.Set("userRole", tt.requesterRole)
+				c.Next()
+			}
+			router.GET("/users/:id", authenticate, handler.GetUserByID)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+user.ID.String(), nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, user.ID.String(), resp["id"])
+			assert.Equal(t, user.Name, resp["name"])
+			if tt.expectEmail {
+				assert.Equal(t, user.Email, resp["email"])
+			} else {
+				assert.NotContains(t, resp, "email")
+				assert.NotContains(t, resp, "role", "the public view carries no account details")
+			}
+		})
+	}
+}
+
+// stubImpersonationService answers ImpersonateUser with a token for the requested user, or err, and
+// records the request.
+type stubImpersonationService struct {
+	services.UserService
+	err error
+	req *dto.ImpersonateUserRequest
+}
+
+func (s *stubImpersonationService) ImpersonateUser(ctx context.Context, req *dto.ImpersonateUserRequest) (*models.User, string, time.Time, error) {
+	s.req = req
+	if s.err != nil {
+		return nil, "", time.Time{}, s.err
+	}
+	return &models.User{ID: req.ID, Email: "impersonated@test.com"}, "impersonation-token", time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC), nil
+}
+
+func TestUserHandler_ImpersonateUser(t *testing.T) {
+	adminID, targetID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Success", path: targetID.String(), expectedStatus: http.StatusOK},
+		{name: "AdminTarget", path: targetID.String(), serviceErr: fmt.Errorf("%w: admins cannot be impersonated", services.ErrForbidden), expectedStatus: http.StatusForbidden},
+		{name: "UnknownUser", path: targetID.String(), serviceErr: services.ErrNotFound, expectedStatus: http.StatusNotFound},
+		{name: "InvalidID", path: "not-a-uuid", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubImpersonationService{err: tt.serviceErr}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/admin/users/:id/impersonate", func(c *gin.Context) {
+				c.Set("userID", adminID) // What JWTAuthMiddleware stores
+				c.Next()
+			}, NewUserHandler(service, validator.New()).ImpersonateUser)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.path+"/impersonate", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Nil(t, service.req, "Invalid requests never reach the service")
+				return
+			}
+			require.NotNil(t, service.req)
+			assert.Equal(t, dto.ImpersonateUserRequest{ID: targetID, AdminID: adminID}, *service.req)
+			if tt.serviceErr != nil {
+				return
+			}
+
+			var response dto.ImpersonationResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, targetID, response.User.ID)
+			assert.Equal(t, "impersonation-token", response.Token)
+			assert.Equal(t, time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC), response.ExpiresAt)
+			assert.NotContains(t, w.Body.String(), "refreshToken", "Impersonation tokens can't be refreshed")
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler holds dependencies for managing the webhooks of external integrations.
+type WebhookHandler struct {
+	service     services.WebhookService
+	validator   *validator.Validate
+	maxPageSize int // Largest limit ListWebhookDeliveries accepts; larger ones are clamped
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(service services.WebhookService, validate *validator.Validate) *WebhookHandler {
+	return &WebhookHandler{
+		service:     service,
+		validator:   validate,
+		maxPageSize: dto.DefaultMaxPageLimit,
+	}
+}
+
+// WithMaxPageSize sets the largest limit ListWebhookDeliveries accepts. Values below 1 keep the default.
+func (h *WebhookHandler) WithMaxPageSize(n int) *WebhookHandler {
+	if n > 0 {
+		h.maxPageSize = n
+	}
+	return h
+}
+
+// CreateWebhook godoc
+// @Summary      Register a webhook
+// @Description  Subscribes an external integration's URL to job and invoice events. Each event is POSTed as JSON with an X-Signature header holding "sha256=" and the hex HMAC-SHA256 of the body, keyed with the secret. The secret is only returned in this response. Admin only.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhook body  dto.CreateWebhookRequest true "Owner, URL and subscribed event types"
+// @Success      201 {object}  dto.CreateWebhookResponse "Webhook registered successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/webhooks [post]
+// @Security     BearerAuth
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("CreateWebhook: Error getting user ID from context", "error", err)
+		respondError(c, unauthorized("Unauthorized"))
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.CreatedBy = userID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	webhook, secret, err := h.service.CreateWebhook(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateWebhookResponse{WebhookResponse: MapWebhookModelToResponse(webhook), Secret: secret})
+}
+
+// ListWebhooks godoc
+// @Summary      List webhooks
+// @Description  Lists every registered webhook, oldest first, without their secrets. Admin only.
+// @Tags         webhooks
+// @Produce      json
+// @Success      200 {array}   dto.WebhookResponse "Successfully retrieved webhooks"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/webhooks [get]
+// @Security     BearerAuth
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.service.ListWebhooks(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]dto.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		responses = append(responses, MapWebhookModelToResponse(&webhook))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// UpdateWebhook godoc
+// @Summary      Pause or resume a webhook
+// @Description  Sets whether a webhook is active. A paused webhook gets no new deliveries, and those already queued wait until it is resumed. Admin only.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id path      string true  "Webhook ID" Format(uuid)
+// @Param        webhook body  dto.UpdateWebhookRequest true "Whether the webhook is active"
+// @Success      200 {object}  dto.WebhookResponse "Webhook updated successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid input"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      404 {object}  dto.ErrorResponse "Webhook Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/webhooks/{id} [patch]
+// @Security     BearerAuth
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid webhook ID format"))
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, badRequest("Invalid request body: "+err.Error()))
+		return
+	}
+	req.ID = webhookID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Webhook not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MapWebhookModelToResponse(webhook))
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Deletes a webhook and its delivery history; deliveries not yet made are dropped. Admin only.
+// @Tags         webhooks
+// @Param        id path      string true  "Webhook ID" Format(uuid)
+// @Success      204 "Webhook deleted successfully"
+// @Failure      400 {object}  dto.ErrorResponse "Invalid ID format"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      404 {object}  dto.ErrorResponse "Webhook Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/webhooks/{id} [delete]
+// @Security     BearerAuth
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid webhook ID format"))
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), &dto.DeleteWebhookRequest{ID: webhookID}); err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Webhook not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary      List a webhook's deliveries
+// @Description  Lists the deliveries queued for a webhook, newest first, with their status, attempt count and the outcome of the last attempt. Admin only.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id path      string true  "Webhook ID" Format(uuid)
+// @Param        status query string false "Filter by delivery status" Enums(pending, delivered, failed)
+// @Param        limit query int false "Pagination limit; values above the configured maximum (default 100) are clamped" default(10)
+// @Param        offset query int false "Pagination offset" default(0)
+// @Success      200 {object}  dto.PaginatedResponse[dto.WebhookDeliveryResponse] "Successfully retrieved deliveries"
+// @Header       200 {integer} X-Total-Count "Number of items matching the filters"
+// @Header       200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
+// @Failure      400 {object}  dto.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure      401 {object}  dto.ErrorResponse "Unauthorized"
+// @Failure      403 {object}  dto.ErrorResponse "Forbidden - Admin only"
+// @Failure      404 {object}  dto.ErrorResponse "Webhook Not Found"
+// @Failure      500 {object}  dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/webhooks/{id}/deliveries [get]
+// @Security     BearerAuth
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, badRequest("Invalid webhook ID format"))
+		return
+	}
+
+	var req dto.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, badRequest("Invalid query parameters: "+err.Error()))
+		return
+	}
+	req.WebhookID = webhookID
+
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	req.Limit, req.Offset = dto.ClampPagination(req.Limit, req.Offset, h.maxPageSize)
+
+	deliveries, total, err := h.service.ListWebhookDeliveries(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, errorMessages{
+			services.ErrNotFound: "Webhook not found",
+		})
+		return
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, MapWebhookDeliveryModelToResponse(&delivery))
+	}
+
+	setPaginationHeaders(c, total, req.Limit, req.Offset)
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(responses, total, req.Limit, req.Offset))
+}
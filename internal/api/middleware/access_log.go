@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxLoggedBodyBytes = 4096         // Bodies are only captured up to this size; larger ones aren't logged
+	redactedValue      = "[REDACTED]" // Replaces the value of every redacted key
+)
+
+// Keys are compared after normalizeKey, so accessToken, access_token and ACCESS_TOKEN are the same key.
+var (
+	// DefaultRedactSuffixes redact every key ending in one of them, such as refreshToken or currentPassword.
+	DefaultRedactSuffixes = []string{"token", "password", "secret"}
+	// DefaultRedactKeys are redacted on an exact match, such as a minted API key.
+	DefaultRedactKeys = []string{"key"}
+)
+
+// AccessLogConfig configures AccessLog.
+type AccessLogConfig struct {
+	LogBodies  bool     // Also log request and response bodies; meant for debugging only
+	RedactKeys []string // JSON keys (matched at any depth) redacted in addition to the defaults, which are always applied
+}
+
+// normalizeKey lowercases key and strips underscores, so camelCase and snake_case spellings match.
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "")
+}
+
+// redactor decides which JSON keys have their values redacted.
+type redactor struct {
+	keys     map[string]bool // Normalized keys redacted on an exact match
+	suffixes []string        // Normalized suffixes that redact any key ending in them
+}
+
+func newRedactor(extraKeys []string) *redactor {
+	r := &redactor{keys: make(map[string]bool, len(DefaultRedactKeys)+len(extraKeys))}
+	for _, key := range append(append([]string{}, DefaultRedactKeys...), extraKeys...) {
+		if key = normalizeKey(strings.TrimSpace(key)); key != "" {
+			r.keys[key] = true
+		}
+	}
+	for _, suffix := range DefaultRedactSuffixes {
+		r.suffixes = append(r.suffixes, normalizeKey(suffix))
+	}
+	return r
+}
+
+func (r *redactor) redacts(key string) bool {
+	key = normalizeKey(key)
+	if r.keys[key] {
+		return true
+	}
+	for _, suffix := range r.suffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyCapture copies the start of the response body while passing every write through unchanged,
+// so streamed responses still reach the client as they are written.
+type bodyCapture struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCapture) capture(b []byte) {
+	if room := maxLoggedBodyBytes + 1 - w.body.Len(); room > 0 { // One extra byte marks the body as too large
+		w.body.Write(b[:min(len(b), room)])
+	}
+}
+
+func (w *bodyCapture) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCapture) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// AccessLog logs one line per request with the method, path, status and latency through the
+// request-scoped logger, so each line carries the request (and user) ID.
+//
+// With cfg.LogBodies set, the line also carries the request and response bodies. Only JSON bodies
+// up to maxLoggedBodyBytes are logged, after the values of credential keys are replaced; anything
+// else is reported as omitted so credentials in other formats can't leak. The request body is
+// read ahead without consuming it, and the response is captured as it is written.
+func AccessLog(cfg AccessLogConfig) gin.HandlerFunc {
+	redact := newRedactor(cfg.RedactKeys)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		var requestBody []byte
+		var capture *bodyCapture
+		if cfg.LogBodies {
+			if c.Request.Body != nil {
+				// Read ahead, then hand the handler the same bytes followed by whatever wasn't read
+				requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, maxLoggedBodyBytes+1))
+				c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(requestBody), c.Request.Body), c.Request.Body}
+			}
+			capture = &bodyCapture{ResponseWriter: c.Writer}
+			c.Writer = capture
+		}
+
+		c.Next()
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"client_ip", GetClientIP(c),
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+		}
+		if cfg.LogBodies {
+			attrs = append(attrs,
+				"request_body", redactBody(requestBody, redact),
+				"response_body", redactBody(capture.body.Bytes(), redact),
+			)
+		}
+		logger.FromContext(c.Request.Context()).Info("request completed", attrs...)
+	}
+}
+
+// readCloser reads from Reader but closes the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// redactBody returns body as compact JSON with the values of the redact keys replaced, or a
+// placeholder when it is empty, too large or not JSON.
+func redactBody(body []byte, redact *redactor) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if len(body) > maxLoggedBodyBytes {
+		return "[omitted: too large]"
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber() // Keep numbers exactly as sent
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "[omitted: not JSON]"
+	}
+	redacted, err := json.Marshal(redactValue(value, redact))
+	if err != nil {
+		return "[omitted: not JSON]"
+	}
+	return string(redacted)
+}
+
+func redactValue(value any, redact *redactor) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if redact.redacts(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(inner, redact)
+			}
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = redactValue(inner, redact)
+		}
+	}
+	return value
+}
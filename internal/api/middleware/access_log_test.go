@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAccessLogRouter returns a router that logs through AccessLog into the returned buffer as JSON.
+func newAccessLogRouter(cfg AccessLogConfig, handler gin.HandlerFunc) (*gin.Engine, *bytes.Buffer) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(RequestID(slog.New(slog.NewJSONHandler(&logs, nil))), AccessLog(cfg))
+	router.POST("/login", handler)
+	return router, &logs
+}
+
+// accessLogLine decodes the single line logged for a request.
+func accessLogLine(t *testing.T, logs *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 1)
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	return line
+}
+
+func TestAccessLog_RedactsBodies(t *testing.T) {
+	loginResponse := dto.LoginResponse{
+		User:         dto.UserResponse{Name: "User", Email: "user@example.com"},
+		Token:        "secret-access-token",
+		RefreshToken: "secret-refresh-token",
+	}
+	var handlerBody string
+	router, logs := newAccessLogRouter(AccessLogConfig{LogBodies: true}, func(c *gin.Context) {
+		b, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		handlerBody = string(b)
+		c.JSON(http.StatusOK, loginResponse)
+	})
+
+	requestBody, err := json.Marshal(dto.ChangePasswordRequest{CurrentPassword: "hunter2", NewPassword: "correct-horse"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(requestBody))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(requestBody), handlerBody, "The handler must still see the whole body")
+	assert.Contains(t, w.Body.String(), "secret-access-token", "The client must still get the unredacted response")
+
+	line := accessLogLine(t, logs)
+	assert.Equal(t, "POST", line["method"])
+	assert.Equal(t, "/login", line["path"])
+	assert.EqualValues(t, http.StatusOK, line["status"])
+	assert.NotEmpty(t, line["request_id"])
+	assert.Equal(t, `{"currentPassword":"[REDACTED]","newPassword":"[REDACTED]"}`, line["request_body"])
+
+	var responseBody map[string]any
+	require.NoError(t, json.Unmarshal([]byte(line["response_body"].(string)), &responseBody))
+	assert.Equal(t, redactedValue, responseBody["accessToken"])
+	assert.Equal(t, redactedValue, responseBody["refreshToken"])
+	assert.Equal(t, "user@example.com", responseBody["user"].(map[string]any)["email"])
+	for _, secret := range []string{"hunter2", "correct-horse", "secret-access-token", "secret-refresh-token"} {
+		assert.NotContains(t, logs.String(), secret)
+	}
+}
+
+func TestRedactor_Redacts(t *testing.T) {
+	redact := newRedactor([]string{"IBAN"})
+
+	for _, key := range []string{"password", "Password", "token", "accessToken", "refresh_token", "REFRESH_TOKEN", "currentPassword", "new_password", "secret", "webhookSecret", "key", "Key", "iban"} {
+		assert.True(t, redact.redacts(key), "%s must be redacted", key)
+	}
+	for _, key := range []string{"email", "keyPrefix", "monkey", "tokens", "secretary", "expiresAt"} {
+		assert.False(t, redact.redacts(key), "%s must not be redacted", key)
+	}
+}
+
+func TestAccessLog_BodiesOnlyWhenEnabled(t *testing.T) {
+	router, logs := newAccessLogRouter(AccessLogConfig{}, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"password":"hunter2"}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := accessLogLine(t, logs)
+	assert.EqualValues(t, http.StatusOK, line["status"])
+	assert.NotContains(t, line, "request_body")
+	assert.NotContains(t, line, "response_body")
+}
+
+func TestAccessLog_LargeAndNonJSONBodiesAreOmitted(t *testing.T) {
+	large := `{"password":"` + strings.Repeat("x", 2*maxLoggedBodyBytes) + `"}`
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedBody string
+	}{
+		{name: "TooLarge", body: large, expectedBody: "[omitted: too large]"},
+		{name: "Form", body: "email=user%40example.com&password=hunter2", expectedBody: "[omitted: not JSON]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerBody string
+			router, logs := newAccessLogRouter(AccessLogConfig{LogBodies: true}, func(c *gin.Context) {
+				b, err := io.ReadAll(c.Request.Body)
+				require.NoError(t, err)
+				handlerBody = string(b)
+				c.Status(http.StatusNoContent)
+			})
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.body, handlerBody, "Reading ahead must not consume the body")
+			line := accessLogLine(t, logs)
+			assert.Equal(t, tt.expectedBody, line["request_body"])
+			assert.NotContains(t, logs.String(), "hunter2")
+		})
+	}
+}
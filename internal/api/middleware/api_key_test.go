@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAPIKeys looks keys up in a map from raw key, failing every lookup when err is set.
+type memoryAPIKeys struct {
+	keys map[string]*models.APIKey
+	err  error
+}
+
+func (m memoryAPIKeys) LookupAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.keys[rawKey], nil
+}
+
+// newAPIKeyRouter builds a router with a POST /pay route requiring the invoices:pay scope,
+// which echoes the owner of the calling key.
+func newAPIKeyRouter(keys APIKeyLookup) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/pay", APIKeyAuth(keys), RequireScope(models.APIKeyScopeInvoicesPay), func(c *gin.Context) {
+		identity, err := GetServiceIdentityFromContext(c)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, identity.Owner)
+	})
+	return router
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour)
+	keys := memoryAPIKeys{keys: map[string]*models.APIKey{
+		"valid":    {ID: uuid.New(), Owner: "billing", Scopes: []string{models.APIKeyScopeInvoicesPay}},
+		"revoked":  {ID: uuid.New(), Owner: "billing", Scopes: []string{models.APIKeyScopeInvoicesPay}, RevokedAt: &revokedAt},
+		"unscoped": {ID: uuid.New(), Owner: "reporting", Scopes: []string{"reports:read"}},
+	}}
+
+	tests := []struct {
+		name         string
+		keys         APIKeyLookup
+		apiKey       string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "Valid", keys: keys, apiKey: "valid", expectedCode: http.StatusOK, expectedBody: "billing"},
+		{name: "Revoked", keys: keys, apiKey: "revoked", expectedCode: http.StatusUnauthorized, expectedBody: "API key has been revoked"},
+		{name: "InsufficientScope", keys: keys, apiKey: "unscoped", expectedCode: http.StatusForbidden, expectedBody: "requires scope 'invoices:pay'"},
+		{name: "Unknown", keys: keys, apiKey: "unknown", expectedCode: http.StatusUnauthorized, expectedBody: "Invalid API key"},
+		{name: "Missing", keys: keys, apiKey: "", expectedCode: http.StatusUnauthorized, expectedBody: "X-API-Key header required"},
+		{name: "LookupFails", keys: memoryAPIKeys{err: errors.New("db down")}, apiKey: "valid", expectedCode: http.StatusServiceUnavailable, expectedBody: "Unable to verify API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/pay", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			newAPIKeyRouter(tt.keys).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestRequireScope_WithoutAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/pay", RequireScope(models.APIKeyScopeInvoicesPay), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
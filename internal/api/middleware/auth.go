@@ -2,11 +2,17 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/auth"
+	"go-api-template/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -15,64 +21,176 @@ import (
 
 const (
 	authorizationHeader = "Authorization"
-	userCtx             = "userID" // Key to store user ID in context
+	userCtx             = "userID"         // Key to store user ID in context
+	roleCtx             = "userRole"       // Key to store the user's role in context
+	impersonatorCtx     = "impersonatorID" // Key to store the admin behind an impersonation token
+	accessTokenCtx      = "accessToken"    // Key to store the AccessToken the request was authenticated with
+	defaultRole         = "user"           // Assumed for tokens minted before roles existed
 )
 
+// TokenRevocationChecker reports whether an access token has been blacklisted by its jti claim.
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// authFailure is why a request couldn't be authenticated, as answered to the client.
+type authFailure struct {
+	status  int
+	code    string
+	message string
+}
+
+func unauthenticated(message string) *authFailure {
+	return &authFailure{status: http.StatusUnauthorized, code: dto.ErrorCodeUnauthorized, message: message}
+}
+
 // JWTAuthMiddleware creates a Gin middleware for JWT authentication.
-func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// Tokens are verified with the key named by their kid header in keys, tokens without one with the "" entry;
+// a kid missing from keys, such as that of a retired key, makes the token invalid.
+// Tokens carrying a jti are checked against the revocation blacklist; if that lookup fails,
+// failOpen decides whether the request is let through (true) or rejected (false).
+// Impersonation tokens authenticate as their subject; the admin named by their act claim is stored
+// alongside, in the Gin context and in the request context for the audit log.
+func JWTAuthMiddleware(keys map[string]string, revocations TokenRevocationChecker, failOpen bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader(authorizationHeader)
-		if authHeader == "" {
-			log.Println("Auth middleware: Authorization header missing")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		if failure := authenticate(c, keys, revocations, failOpen); failure != nil {
+			abortWithError(c, failure.status, failure.code, failure.message)
 			return
 		}
+		c.Next() // Proceed to the next handler
+	}
+}
 
-		headerParts := strings.Split(authHeader, " ")
-		if len(headerParts) != 2 || strings.ToLower(headerParts[0]) != "bearer" {
-			log.Println("Auth middleware: Invalid Authorization header format")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
-			return
+// OptionalJWTAuthMiddleware authenticates the request like JWTAuthMiddleware when it carries a valid
+// access token, and lets it through unauthenticated otherwise, e.g. a logout whose access token
+// has already expired. Handlers must check GetUserIDFromContext themselves.
+func OptionalJWTAuthMiddleware(keys map[string]string, revocations TokenRevocationChecker, failOpen bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(authorizationHeader) != "" {
+			if failure := authenticate(c, keys, revocations, failOpen); failure != nil {
+				logger.FromContext(c.Request.Context()).Info("Optional auth middleware: Continuing unauthenticated", "reason", failure.message)
+			}
 		}
+		c.Next()
+	}
+}
+
+// authenticate verifies the request's bearer token and stores who it authenticates in the Gin and
+// request contexts. It returns why the request is rejected, or nil once it is authenticated.
+func authenticate(c *gin.Context, keys map[string]string, revocations TokenRevocationChecker, failOpen bool) *authFailure {
+	reqLogger := logger.FromContext(c.Request.Context())
+	authHeader := c.GetHeader(authorizationHeader)
+	if authHeader == "" {
+		reqLogger.Info("Auth middleware: Authorization header missing")
+		return unauthenticated("Authorization header required")
+	}
+
+	headerParts := strings.Split(authHeader, " ")
+	if len(headerParts) != 2 || strings.ToLower(headerParts[0]) != "bearer" {
+		reqLogger.Info("Auth middleware: Invalid Authorization header format")
+		return unauthenticated("Invalid Authorization header format")
+	}
 
-		tokenString := headerParts[1]
+	tokenString := headerParts[1]
 
-		// Parse and validate the token
-		token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-			// Validate the alg is what you expect:
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	// Parse and validate the token
+	token, err := jwt.ParseWithClaims(tokenString, &auth.AccessTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Validate the alg is what you expect:
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid := ""
+		if header, ok := token.Header["kid"]; ok {
+			if kid, ok = header.(string); !ok || kid == "" {
+				return nil, fmt.Errorf("invalid kid header: %v", header)
 			}
-			return []byte(jwtSecret), nil
-		})
+		}
+		secret, ok := keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return []byte(secret), nil
+	})
 
+	if err != nil {
+		reqLogger.Info("Auth middleware: Error parsing token", "error", err)
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return unauthenticated("Token has expired")
+		}
+		return unauthenticated("Invalid token")
+	}
+
+	claims, ok := token.Claims.(*auth.AccessTokenClaims)
+	if !ok || !token.Valid {
+		reqLogger.Info("Auth middleware: Invalid token claims or token is not valid")
+		return unauthenticated("Invalid token")
+	}
+
+	// Token is valid, extract user ID (subject)
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		reqLogger.Info("Auth middleware: Error parsing user ID from token subject", "subject", claims.Subject, "error", err)
+		return unauthenticated("Invalid user identifier in token")
+	}
+
+	// Tag every log line for the rest of the request with the authenticated user.
+	reqLogger = reqLogger.With("user_id", userID.String())
+	ctx := c.Request.Context()
+	var impersonatorID uuid.UUID
+	if claims.Act != nil {
+		impersonatorID, err = uuid.Parse(claims.Act.Subject)
 		if err != nil {
-			log.Printf("Auth middleware: Error parsing token: %v", err)
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token has expired"})
-			} else {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			}
-			return
+			reqLogger.Info("Auth middleware: Error parsing impersonator ID from act claim", "act_subject", claims.Act.Subject, "error", err)
+			return unauthenticated("Invalid token")
 		}
+		reqLogger = reqLogger.With("impersonator_id", impersonatorID.String())
+		ctx = models.WithImpersonator(ctx, impersonatorID)
+	}
 
-		if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
-			// Token is valid, extract user ID (subject)
-			userID, err := uuid.Parse(claims.Subject)
-			if err != nil {
-				log.Printf("Auth middleware: Error parsing user ID from token subject '%s': %v", claims.Subject, err)
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid user identifier in token"})
-				return
+	// Reject blacklisted tokens. Tokens minted before jti was added have none and skip the check.
+	if claims.ID != "" {
+		revoked, err := revocations.IsAccessTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			if !failOpen {
+				reqLogger.Error("Auth middleware: Blacklist lookup failed, rejecting", "token_id", claims.ID, "error", err)
+				return &authFailure{status: http.StatusServiceUnavailable, code: dto.ErrorCodeServiceUnavailable, message: "Unable to verify token"}
 			}
+			reqLogger.Warn("Auth middleware: Blacklist lookup failed, allowing (fail-open)", "token_id", claims.ID, "error", err)
+		} else if revoked {
+			reqLogger.Info("Auth middleware: Token has been revoked", "token_id", claims.ID)
+			return unauthenticated("Token has been revoked")
+		}
+	}
+
+	role := claims.Role
+	if role == "" {
+		role = defaultRole
+	}
+
+	// Store the user, their role and the token in context for downstream handlers
+	c.Request = c.Request.WithContext(logger.WithContext(ctx, reqLogger))
+	c.Set(userCtx, userID)
+	c.Set(roleCtx, role)
+	if claims.Act != nil {
+		c.Set(impersonatorCtx, impersonatorID)
+	}
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		c.Set(accessTokenCtx, AccessToken{ID: claims.ID, ExpiresAt: claims.ExpiresAt.Time})
+	}
+	reqLogger.Debug("Auth middleware: User authenticated")
+	return nil
+}
 
-			// Store user ID in context for downstream handlers
-			c.Set(userCtx, userID)
-			log.Printf("Auth middleware: User %s authenticated", userID)
-			c.Next() // Proceed to the next handler
-		} else {
-			log.Println("Auth middleware: Invalid token claims or token is not valid")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+// TokenFromQuery copies the access_token query parameter into the Authorization header when no header is set.
+// Browsers can't set headers on WebSocket handshakes, so only use it on those routes and run it before JWTAuthMiddleware.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(authorizationHeader) == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set(authorizationHeader, "Bearer "+token)
+			}
 		}
+		c.Next()
 	}
 }
 
@@ -90,3 +208,67 @@ func GetUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 
 	return userID, nil
 }
+
+// AccessToken identifies the access token a request was authenticated with, for revoking it.
+type AccessToken struct {
+	ID        string    // jti claim
+	ExpiresAt time.Time // exp claim
+}
+
+// GetAccessTokenFromContext returns the access token the request was authenticated with, and false for
+// unauthenticated requests and tokens minted without a jti or exp.
+func GetAccessTokenFromContext(c *gin.Context) (AccessToken, bool) {
+	tokenAny, exists := c.Get(accessTokenCtx)
+	if !exists {
+		return AccessToken{}, false
+	}
+	token, ok := tokenAny.(AccessToken)
+	return token, ok
+}
+
+// GetImpersonatorFromContext returns the admin acting through an impersonation token, and false for
+// requests made with the user's own token.
+func GetImpersonatorFromContext(c *gin.Context) (uuid.UUID, bool) {
+	impersonatorAny, exists := c.Get(impersonatorCtx)
+	if !exists {
+		return uuid.Nil, false
+	}
+	impersonatorID, ok := impersonatorAny.(uuid.UUID)
+	return impersonatorID, ok
+}
+
+// RequireCapability creates a Gin middleware that only lets through users whose role grants capability,
+// as decided by models.RoleCapabilities. It must run after JWTAuthMiddleware.
+func RequireCapability(capability models.Capability) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := GetUserIDFromContext(c); err != nil {
+			logger.FromContext(c.Request.Context()).Info("RequireCapability middleware: No authenticated user in context")
+			abortWithError(c, http.StatusUnauthorized, dto.ErrorCodeUnauthorized, "Authentication required")
+			return
+		}
+
+		userRole, err := GetUserRoleFromContext(c)
+		if err != nil || !models.UserRole(userRole).Can(capability) {
+			logger.FromContext(c.Request.Context()).Info("RequireCapability middleware: Capability is not granted", "role", userRole, "required_capability", capability)
+			abortWithError(c, http.StatusForbidden, dto.ErrorCodeForbidden, fmt.Sprintf("Insufficient permissions: requires '%s'", capability))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetUserRoleFromContext returns the role of the authenticated user from context.
+func GetUserRoleFromContext(c *gin.Context) (string, error) {
+	roleAny, exists := c.Get(roleCtx)
+	if !exists {
+		return "", errors.New("user role not found in context")
+	}
+
+	role, ok := roleAny.(string)
+	if !ok {
+		return "", errors.New("user role in context is of invalid type")
+	}
+
+	return role, nil
+}
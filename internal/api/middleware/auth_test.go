@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noRevocations reports every token as still valid.
+type noRevocations struct{}
+
+func (noRevocations) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return false, nil
+}
+
+// newAuthRouter builds a router with a GET /me route behind JWTAuthMiddleware, which echoes the authenticated user ID.
+func newAuthRouter(keys map[string]string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", JWTAuthMiddleware(keys, noRevocations{}, false), func(c *gin.Context) {
+		userID, err := GetUserIDFromContext(c)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, userID.String())
+	})
+	return router
+}
+
+// signToken mints an access token for userID signed with secret, naming kid in its header unless it is empty.
+func signToken(t *testing.T, userID uuid.UUID, kid, secret string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTAuthMiddleware_KeyRotation(t *testing.T) {
+	const (
+		retiredSecret = "the-secret-retired-in-february-2025"
+		olderSecret   = "the-previous-secret-still-accepted-for-now"
+		activeSecret  = "a-secret-that-is-long-enough-to-sign-with"
+		legacySecret  = "the-secret-used-before-key-rotation"
+	)
+	// 2025-01 was retired by removing it; 2025-02 still verifies while 2025-03 signs new tokens
+	rotating := map[string]string{"2025-02": olderSecret, "2025-03": activeSecret}
+	withLegacy := map[string]string{"": legacySecret, "2025-03": activeSecret}
+	userID := uuid.New()
+
+	tests := []struct {
+		name         string
+		keys         map[string]string
+		token        string
+		expectedCode int
+	}{
+		{name: "ActiveKey", keys: rotating, token: signToken(t, userID, "2025-03", activeSecret), expectedCode: http.StatusOK},
+		{name: "OlderKeyStillValid", keys: rotating, token: signToken(t, userID, "2025-02", olderSecret), expectedCode: http.StatusOK},
+		{name: "RetiredKey", keys: rotating, token: signToken(t, userID, "2025-01", retiredSecret), expectedCode: http.StatusUnauthorized},
+		{name: "KidOfAnotherKey", keys: rotating, token: signToken(t, userID, "2025-02", activeSecret), expectedCode: http.StatusUnauthorized},
+		{name: "NoKidWithoutLegacyKey", keys: rotating, token: signToken(t, userID, "", olderSecret), expectedCode: http.StatusUnauthorized},
+		{name: "NoKidWithLegacyKey", keys: withLegacy, token: signToken(t, userID, "", legacySecret), expectedCode: http.StatusOK},
+		{name: "KidWithLegacyKeyOnly", keys: map[string]string{"": legacySecret}, token: signToken(t, userID, "2025-03", legacySecret), expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set(authorizationHeader, "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			newAuthRouter(tt.keys).ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, userID.String(), w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), "Invalid token")
+			}
+		})
+	}
+}
+
+func TestJWTAuthMiddleware_Impersonation(t *testing.T) {
+	const secret = "a-secret-that-is-long-enough-to-sign-with"
+	targetID, adminID := uuid.New(), uuid.New()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", JWTAuthMiddleware(map[string]string{"": secret}, noRevocations{}, false), func(c *gin.Context) {
+		userID, _ := GetUserIDFromContext(c)
+		impersonatorID, impersonating := GetImpersonatorFromContext(c)
+		fromRequest, _ := models.ImpersonatorFromContext(c.Request.Context()) // What services record in the audit log
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "impersonating": impersonating, "impersonator_id": impersonatorID, "request_impersonator_id": fromRequest})
+	})
+
+	sign := func(act *auth.ActorClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.AccessTokenClaims{
+			Act: act,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        uuid.NewString(),
+				Subject:   targetID.String(),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			},
+		})
+		signed, err := token.SignedString([]byte(secret))
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name         string
+		token        string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "ImpersonationToken",
+			token:        sign(&auth.ActorClaims{Subject: adminID.String()}),
+			expectedCode: http.StatusOK,
+			expectedBody: `{"user_id": "` + targetID.String() + `", "impersonating": true, "impersonator_id": "` + adminID.String() + `", "request_impersonator_id": "` + adminID.String() + `"}`,
+		},
+		{
+			name:         "OwnToken",
+			token:        sign(nil),
+			expectedCode: http.StatusOK,
+			expectedBody: `{"user_id": "` + targetID.String() + `", "impersonating": false, "impersonator_id": "` + uuid.Nil.String() + `", "request_impersonator_id": "` + uuid.Nil.String() + `"}`,
+		},
+		{name: "MalformedActor", token: sign(&auth.ActorClaims{Subject: "support"}), expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set(authorizationHeader, "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+			if tt.expectedCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), "Invalid token")
+			}
+		})
+	}
+}
+
+func TestOptionalJWTAuthMiddleware(t *testing.T) {
+	const secret = "a-secret-that-is-long-enough-to-sign-with"
+	userID := uuid.New()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/logout", OptionalJWTAuthMiddleware(map[string]string{"": secret}, noRevocations{}, false), func(c *gin.Context) {
+		accessToken, authenticated := GetAccessTokenFromContext(c)
+		c.JSON(http.StatusOK, gin.H{"authenticated": authenticated, "token_id": accessToken.ID, "expires_at": accessToken.ExpiresAt.Unix()})
+	})
+
+	expiresAt := time.Now().Add(15 * time.Minute).Truncate(time.Second)
+	sign := func(expiresAt time.Time) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "the-jti",
+				Subject:   userID.String(),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		})
+		signed, err := token.SignedString([]byte(secret))
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name         string
+		header       string
+		expectedBody string
+	}{
+		{name: "ValidToken", header: "Bearer " + sign(expiresAt), expectedBody: fmt.Sprintf(`{"authenticated": true, "token_id": "the-jti", "expires_at": %d}`, expiresAt.Unix())},
+		{name: "ExpiredToken", header: "Bearer " + sign(time.Now().Add(-time.Minute)), expectedBody: fmt.Sprintf(`{"authenticated": false, "token_id": "", "expires_at": %d}`, time.Time{}.Unix())},
+		{name: "NoToken", expectedBody: fmt.Sprintf(`{"authenticated": false, "token_id": "", "expires_at": %d}`, time.Time{}.Unix())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+			if tt.header != "" {
+				req.Header.Set(authorizationHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, "A missing or expired token must not block the request")
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestRequireCapability(t *testing.T) {
+	tests := []struct {
+		name         string
+		role         string // What JWTAuthMiddleware stored; empty means unauthenticated
+		capability   models.Capability
+		expectedCode int
+	}{
+		{name: "AdminAccessesAdmin", role: "admin", capability: models.CapabilityAccessAdmin, expectedCode: http.StatusOK},
+		{name: "AdminCreatesJobs", role: "admin", capability: models.CapabilityCreateJob, expectedCode: http.StatusOK},
+		{name: "UserCreatesJobs", role: "user", capability: models.CapabilityCreateJob, expectedCode: http.StatusOK},
+		{name: "UserDeniedAdmin", role: "user", capability: models.CapabilityAccessAdmin, expectedCode: http.StatusForbidden},
+		{name: "UserDeniedImpersonation", role: "user", capability: models.CapabilityImpersonateUsers, expectedCode: http.StatusForbidden},
+		{name: "UnknownRole", role: "auditor", capability: models.CapabilityCreateJob, expectedCode: http.StatusForbidden},
+		{name: "RelationshipCapabilityNeverFromRole", role: "admin", capability: models.CapabilityCreateInvoice, expectedCode: http.StatusForbidden},
+		{name: "Unauthenticated", capability: models.CapabilityCreateJob, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/admin", func(c *gin.Context) {
+				if tt.role != "" {
+					c.Set(userCtx, uuid.New())
+					c.Set(roleCtx, tt.role)
+				}
+				c.Next()
+			}, RequireCapability(tt.capability), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+			require.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+			if tt.expectedCode == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), string(tt.capability))
+			}
+		})
+	}
+}
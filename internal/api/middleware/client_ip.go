@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	forwardedForHeader = "X-Forwarded-For"
+	clientIPCtx        = "clientIP" // Key to store the resolved client IP in context
+)
+
+// TrustedProxies are the networks of the reverse proxies in front of the API. Only their
+// X-Forwarded-For headers are believed; anyone else could put any address in theirs.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges, e.g. "10.0.0.0/8" or "192.0.2.10".
+func ParseTrustedProxies(entries []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not an IP address or CIDR range", entry)
+		}
+		addr = addr.Unmap()
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+func (p TrustedProxies) trusts(addr netip.Addr) bool {
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that sent r. It is the peer's address, unless the peer is
+// a trusted proxy: then X-Forwarded-For is read from the right, past any further trusted proxies, up to
+// the first address one of them didn't vouch for. Entries left of it may have been made up by the
+// client, so they are never used.
+func (p TrustedProxies) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(r.RemoteAddr) // No port, e.g. in tests
+	}
+	client, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	client = client.Unmap()
+
+	forwarded := strings.Split(strings.Join(r.Header.Values(forwardedForHeader), ","), ",")
+	for i := len(forwarded) - 1; i >= 0 && p.trusts(client); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break // Nothing left of a malformed entry can be relied on
+		}
+		client = hop.Unmap()
+	}
+	return client.String()
+}
+
+// ResolveClientIP stores the address of the client, as proxies.ClientIP resolves it, for GetClientIP.
+// It must run before anything keyed on the client's address, such as rate limits.
+func ResolveClientIP(proxies TrustedProxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(clientIPCtx, proxies.ClientIP(c.Request))
+		c.Next()
+	}
+}
+
+// GetClientIP returns the client address stored by ResolveClientIP, or the peer's address if it
+// didn't run.
+func GetClientIP(c *gin.Context) string {
+	if ip := c.GetString(clientIPCtx); ip != "" {
+		return ip
+	}
+	return c.RemoteIP()
+}
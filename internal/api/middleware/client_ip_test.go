@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies_ClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10", "fd00::/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expected     string
+	}{
+		{name: "Direct", remoteAddr: "203.0.113.7:5123", expected: "203.0.113.7"},
+		{name: "SpoofedFromUntrustedPeer", remoteAddr: "203.0.113.7:5123", forwardedFor: []string{"198.51.100.1"}, expected: "203.0.113.7"},
+		{name: "BehindTrustedProxy", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "TrustedSingleIP", remoteAddr: "192.0.2.10:443", forwardedFor: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "TrustedProxyWithoutHeader", remoteAddr: "10.1.2.3:443", expected: "10.1.2.3"},
+		{name: "ChainOfTrustedProxies", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"198.51.100.1, 10.9.9.9"}, expected: "198.51.100.1"},
+		// The client sent "1.1.1.1" itself; the proxy appended the address it actually saw
+		{name: "SpoofedBehindTrustedProxy", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"1.1.1.1, 198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "SeveralHeaders", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"1.1.1.1", "198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "MalformedEntry", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"1.1.1.1, not-an-ip"}, expected: "10.1.2.3"},
+		{name: "IPv6TrustedProxy", remoteAddr: "[fd00::1]:443", forwardedFor: []string{"2001:db8::5"}, expected: "2001:db8::5"},
+		{name: "IPv4MappedPeer", remoteAddr: "[::ffff:10.1.2.3]:443", forwardedFor: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add(forwardedForHeader, value)
+			}
+			assert.Equal(t, tt.expected, proxies.ClientIP(req))
+		})
+	}
+}
+
+func TestTrustedProxies_NoneConfigured(t *testing.T) {
+	proxies, err := ParseTrustedProxies(nil)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.1.2.3:443"
+	req.Header.Set(forwardedForHeader, "198.51.100.1")
+	assert.Equal(t, "10.1.2.3", proxies.ClientIP(req), "X-Forwarded-For is ignored unless proxies are configured")
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"10.0.0.0/8", "load-balancer"})
+	assert.ErrorContains(t, err, `"load-balancer"`)
+}
+
+func TestResolveClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ResolveClientIP(proxies))
+	router.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, ClientIPKey(c))
+	})
+
+	send := func(remoteAddr, forwardedFor string) string {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(forwardedForHeader, forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	// Rate limits key on the real client behind the proxy, and a client can't dodge them with a made-up header
+	assert.Equal(t, "ip:198.51.100.1", send("10.1.2.3:443", "198.51.100.1"))
+	assert.Equal(t, "ip:203.0.113.7", send("203.0.113.7:5123", "198.51.100.2"))
+}
@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// incompressibleTypes are media types that are already compressed, or streamed in a way compression
+// would hold back. Types ending in "/" match every subtype.
+var incompressibleTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/pdf",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/octet-stream",
+	"text/event-stream",
+}
+
+// compressWriter holds back the start of a response until it knows whether to compress it: once
+// minSize bytes are buffered, or the handler flushes, the response is compressed if its type allows;
+// a response that ends smaller is sent as is.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string // Negotiated Content-Encoding; empty when the client accepts neither
+	minSize  int
+	buf      []byte
+	size     int            // Uncompressed bytes written by the handler
+	decided  bool           // Headers have been settled and the buffer passed on
+	encoder  io.WriteCloser // Set once the response is being compressed
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.size += len(b)
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.minSize {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers as they are; whatever follows is not compressed.
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends what the handler wrote so far. A handler flushing before minSize is streaming, so the
+// response is compressed if its type allows, whatever its size.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(len(w.buf) > 0)
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Size() int {
+	if !w.Written() {
+		return w.ResponseWriter.Size()
+	}
+	return w.size
+}
+
+// decide settles the headers, starting compression if large is set and the response qualifies, and
+// passes on the buffered bytes.
+func (w *compressWriter) decide(large bool) error {
+	w.decided = true
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding") // Whether or not this one is compressed, it depends on the header
+
+	if large && w.compressible() {
+		if header.Get("Content-Type") == "" {
+			// Left unset, net/http would sniff the compressed bytes instead
+			header.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			header.Set("ETag", "W/"+etag) // The bytes differ from the identity response's
+		}
+		if w.encoding == encodingGzip {
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		} else {
+			w.encoder = zlib.NewWriter(w.ResponseWriter) // HTTP's deflate is the zlib format, not raw DEFLATE
+		}
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// compressible reports whether the response may be compressed: the client accepts an encoding, the
+// status carries a body, it isn't encoded already and its type isn't compressed already.
+func (w *compressWriter) compressible() bool {
+	if w.encoding == "" || w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	if status := w.Status(); status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	for _, skipped := range incompressibleTypes {
+		if mediaType == skipped || (strings.HasSuffix(skipped, "/") && strings.HasPrefix(mediaType, skipped)) {
+			return false
+		}
+	}
+	return true
+}
+
+// finish passes on a response smaller than minSize and ends the compressed stream.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.encoder != nil {
+		w.encoder.Close()
+	}
+}
+
+// Compression compresses responses of at least minSize bytes with gzip or deflate, whichever the
+// request's Accept-Encoding prefers (gzip on a tie). Responses that are already compressed (images,
+// PDFs, ...) or already carry a Content-Encoding are sent as is, and every response gets
+// Vary: Accept-Encoding so caches keep the variants apart. WebSocket upgrades and HEAD requests are
+// passed through untouched.
+func Compression(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.IsWebsocket() || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := c.Writer
+		cw := &compressWriter{ResponseWriter: writer, encoding: negotiateEncoding(c.GetHeader("Accept-Encoding")), minSize: minSize}
+		c.Writer = cw
+
+		c.Next()
+
+		cw.finish()
+		c.Writer = writer
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header by their q-values, or returns
+// "" if the client accepts neither. A wildcard counts for gzip.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if coding != "" {
+			qualities[coding] = q
+		}
+	}
+
+	gzipQ, ok := qualities[encodingGzip]
+	if !ok {
+		gzipQ = qualities["*"]
+	}
+	deflateQ := qualities[encodingDeflate]
+	switch {
+	case gzipQ > 0 && gzipQ >= deflateQ:
+		return encodingGzip
+	case deflateQ > 0:
+		return encodingDeflate
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeList is a JSON list response well above the test threshold.
+func largeList() []map[string]any {
+	items := make([]map[string]any, 200)
+	for i := range items {
+		items[i] = map[string]any{"id": i, "title": "Backend developer for an invoicing API", "state": "Waiting"}
+	}
+	return items
+}
+
+// newCompressionRouter builds a router compressing responses of 512 bytes and more, behind timeout if set,
+// with routes serving a large and a small JSON body, a large PNG, a streamed body and one that outlives
+// the timeout.
+func newCompressionRouter(timeout time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compression(512))
+	if timeout > 0 {
+		router.Use(Timeout(timeout))
+	}
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, largeList())
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 1024))
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Writer.WriteString("id,title\n")
+		c.Writer.Flush() // Streams start before reaching the threshold
+		c.Writer.WriteString("1,Backend developer\n")
+	})
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	return router
+}
+
+// decode undoes the Content-Encoding of a recorded response.
+func decode(t *testing.T, w *httptest.ResponseRecorder) []byte {
+	t.Helper()
+	var reader io.Reader = w.Body
+	switch w.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		reader = gz
+	case "deflate":
+		zr, err := zlib.NewReader(w.Body)
+		require.NoError(t, err)
+		reader = zr
+	}
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return body
+}
+
+func TestCompression(t *testing.T) {
+	largeJSON, err := json.Marshal(largeList())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		path             string
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{name: "LargeJSONGzipped", path: "/large", acceptEncoding: "gzip, deflate, br", expectedEncoding: "gzip"},
+		{name: "LargeJSONWithoutHeader", path: "/large", expectedEncoding: ""},
+		{name: "DeflatePreferred", path: "/large", acceptEncoding: "gzip;q=0.5, deflate", expectedEncoding: "deflate"},
+		{name: "GzipRefused", path: "/large", acceptEncoding: "gzip;q=0, identity", expectedEncoding: ""},
+		{name: "Wildcard", path: "/large", acceptEncoding: "*", expectedEncoding: "gzip"},
+		{name: "SmallJSON", path: "/small", acceptEncoding: "gzip", expectedEncoding: ""},
+		{name: "Image", path: "/image", acceptEncoding: "gzip", expectedEncoding: ""},
+		{name: "StreamFlushedEarly", path: "/stream", acceptEncoding: "gzip", expectedEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			newCompressionRouter(0).ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedEncoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"), "Caches must keep compressed and plain responses apart")
+
+			body := decode(t, w)
+			switch tt.path {
+			case "/large":
+				assert.JSONEq(t, string(largeJSON), string(body))
+				assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+				if tt.expectedEncoding != "" {
+					assert.Less(t, w.Body.Len(), len(largeJSON), "Compressed responses must be smaller")
+				}
+			case "/stream":
+				assert.Equal(t, "id,title\n1,Backend developer\n", string(body))
+			}
+		})
+	}
+}
+
+func TestCompression_TimeoutResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	newCompressionRouter(20*time.Millisecond).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Contains(t, string(decode(t, w)), "Request timed out")
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"identity":                  "",
+		"br":                        "",
+		"gzip":                      "gzip",
+		"GZIP":                      "gzip",
+		"deflate":                   "deflate",
+		"deflate, gzip":             "gzip",
+		"gzip;q=0.8, deflate;q=0.9": "deflate",
+		"gzip;q=0":                  "",
+		"*;q=0.1":                   "gzip",
+		"*, gzip;q=0, deflate;q=0":  "",
+	}
+	for header, expected := range tests {
+		assert.Equal(t, expected, negotiateEncoding(header), "Accept-Encoding: %q", header)
+	}
+}
@@ -0,0 +1,12 @@
+package middleware
+
+import (
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError stops the chain and answers with the same dto.ErrorResponse envelope the handlers use.
+func abortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{Code: code, Message: message, RequestID: GetRequestIDFromContext(c)})
+}
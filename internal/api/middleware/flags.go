@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-api-template/internal/flags"
+
+	"github.com/gin-gonic/gin"
+)
+
+// notFoundBody is what Gin answers for a route that doesn't exist.
+const notFoundBody = "404 page not found"
+
+// RequireFlag creates a Gin middleware that hides a route while the named feature flag is off. The
+// response matches Gin's answer for an unknown route, so clients can't tell a disabled feature from a
+// missing one. Register it before authentication, otherwise a 401 gives the route away.
+func RequireFlag(provider flags.Provider, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !provider.Enabled(c.Request.Context(), name) {
+			c.Data(http.StatusNotFound, "text/plain", []byte(notFoundBody))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticFlags is a flags.Provider answering from a map.
+type staticFlags map[string]bool
+
+func (f staticFlags) Enabled(ctx context.Context, name string) bool {
+	return f[name]
+}
+
+func TestRequireFlag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := staticFlags{}
+	authCalled := false
+	router := gin.New()
+	router.GET("/ratings", RequireFlag(provider, "ratings"), func(c *gin.Context) {
+		authCalled = true // Stands in for the auth middleware, which must not run for a hidden route
+		c.Next()
+	}, func(c *gin.Context) {
+		c.String(http.StatusOK, "rated")
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("Off", func(t *testing.T) {
+		w := serve("/ratings")
+		unknown := serve("/no-such-route")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.False(t, authCalled)
+		assert.Equal(t, unknown.Code, w.Code, "a disabled route looks like an unknown one")
+		assert.Equal(t, unknown.Body.String(), w.Body.String())
+		assert.Equal(t, unknown.Header().Get("Content-Type"), w.Header().Get("Content-Type"))
+	})
+
+	t.Run("On", func(t *testing.T) {
+		provider["ratings"] = true
+		w := serve("/ratings")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, authCalled)
+		assert.Equal(t, "rated", w.Body.String())
+	})
+}
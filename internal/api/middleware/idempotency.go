@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed" // Set to "true" on responses served from the cache
+	idempotencyKeyPrefix     = "idempotency:"
+	maxIdempotencyKeyLength  = 255
+	idempotencyInFlightTTL   = time.Minute // Bounds how long a crashed request can keep its key locked
+)
+
+// idempotencyRecord is what's stored in Redis for a key: either a marker for a request still
+// being processed, or the response to replay.
+type idempotencyRecord struct {
+	InFlight    bool   `json:"in_flight,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Location    string `json:"location,omitempty"` // Created resources must replay their Location header
+	Body        []byte `json:"body,omitempty"`
+}
+
+// inFlightRecord is stored while the first request with a key is being handled.
+var inFlightRecord, _ = json.Marshal(idempotencyRecord{InFlight: true})
+
+// responseRecorder copies everything written to the response so it can be cached.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency creates a Gin middleware that makes retried requests safe. The first response for an
+// Idempotency-Key (scoped to the authenticated user and route) is stored in Redis for ttl and replayed
+// for later requests with the same key. Only 2xx responses are stored; anything else frees the key so
+// the client can retry. A duplicate arriving while the first is still being handled gets 409.
+//
+// Requests without the header are passed through. It must run after JWTAuthMiddleware. If Redis can't
+// be reached the request is handled normally, without idempotency protection.
+func Idempotency(redisClient *redis.Client, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqLogger := logger.FromContext(c.Request.Context())
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			abortWithError(c, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Idempotency-Key header is too long")
+			return
+		}
+
+		userID, err := GetUserIDFromContext(c)
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, dto.ErrorCodeUnauthorized, "Authentication required")
+			return
+		}
+		key := idempotencyKeyPrefix + userID.String() + ":" + c.FullPath() + ":" + idempotencyKey
+		ctx := c.Request.Context()
+
+		// Claim the key; only one request can hold it at a time
+		claimed, err := redisClient.SetNX(ctx, key, inFlightRecord, idempotencyInFlightTTL).Result()
+		if err != nil {
+			reqLogger.Warn("Idempotency middleware: Redis unavailable, handling request without idempotency", "error", err)
+			c.Next()
+			return
+		}
+
+		if !claimed {
+			raw, err := redisClient.Get(ctx, key).Bytes()
+			if err != nil {
+				if errors.Is(err, redis.Nil) {
+					// The first request failed and released the key between our two calls
+					abortWithError(c, http.StatusConflict, dto.ErrorCodeConflict, "A request with this Idempotency-Key is being processed, please retry")
+					return
+				}
+				reqLogger.Warn("Idempotency middleware: Redis unavailable, handling request without idempotency", "error", err)
+				c.Next()
+				return
+			}
+
+			var record idempotencyRecord
+			if err := json.Unmarshal(raw, &record); err != nil {
+				reqLogger.Error("Idempotency middleware: Corrupt cached response", "key", key, "error", err)
+				abortWithError(c, http.StatusInternalServerError, dto.ErrorCodeInternal, "Failed to replay idempotent response")
+				return
+			}
+			if record.InFlight {
+				reqLogger.Info("Idempotency middleware: Duplicate request while first is in flight", "idempotency_key", idempotencyKey)
+				abortWithError(c, http.StatusConflict, dto.ErrorCodeConflict, "A request with this Idempotency-Key is being processed, please retry")
+				return
+			}
+
+			reqLogger.Info("Idempotency middleware: Replaying cached response", "idempotency_key", idempotencyKey)
+			c.Header(IdempotentReplayedHeader, "true")
+			if record.Location != "" {
+				c.Header("Location", record.Location)
+			}
+			c.Data(record.Status, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < 200 || status >= 300 {
+			if err := redisClient.Del(ctx, key).Err(); err != nil {
+				reqLogger.Warn("Idempotency middleware: Failed to release key after unsuccessful response", "key", key, "error", err)
+			}
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Location:    c.Writer.Header().Get("Location"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := redisClient.Set(ctx, key, record, ttl).Err(); err != nil {
+			// Don't leave the in-flight marker behind, or retries would get 409 until it expires
+			reqLogger.Warn("Idempotency middleware: Failed to store response", "key", key, "error", err)
+			redisClient.Del(ctx, key)
+		}
+	}
+}
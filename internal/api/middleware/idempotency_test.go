@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdempotentRouter builds a router with a single idempotent POST /jobs route that answers with
+// status and counts how many times the handler actually ran.
+func newIdempotentRouter(t *testing.T, userID uuid.UUID, ttl time.Duration, status int, calls *int) (*gin.Engine, redismock.ClientMock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	redisClient, mock := redismock.NewClientMock()
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		c.Set(userCtx, userID)
+		c.Next()
+	}
+	router.POST("/jobs", authenticate, Idempotency(redisClient, ttl), func(c *gin.Context) {
+		*calls++
+		c.Header("Location", "/api/v1/jobs/1")
+		c.JSON(status, gin.H{"call": *calls})
+	})
+	return router, mock
+}
+
+func postWithKey(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysOriginalResponse(t *testing.T) {
+	userID := uuid.New()
+	ttl := time.Hour
+	calls := 0
+	router, mock := newIdempotentRouter(t, userID, ttl, http.StatusCreated, &calls)
+	key := "idempotency:" + userID.String() + ":/jobs:abc-123"
+
+	stored, err := json.Marshal(idempotencyRecord{
+		Status:      http.StatusCreated,
+		ContentType: "application/json; charset=utf-8",
+		Location:    "/api/v1/jobs/1",
+		Body:        []byte(`{"call":1}`),
+	})
+	require.NoError(t, err)
+
+	mock.ExpectSetNX(key, inFlightRecord, idempotencyInFlightTTL).SetVal(true)
+	mock.ExpectSet(key, stored, ttl).SetVal("OK")
+	mock.ExpectSetNX(key, inFlightRecord, idempotencyInFlightTTL).SetVal(false)
+	mock.ExpectGet(key).SetVal(string(stored))
+
+	first := postWithKey(router, "abc-123")
+	second := postWithKey(router, "abc-123")
+
+	assert.Equal(t, 1, calls, "Handler should only run once")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.JSONEq(t, `{"call":1}`, second.Body.String())
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	assert.Equal(t, first.Header().Get("Location"), second.Header().Get("Location"))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_InFlightDuplicateConflicts(t *testing.T) {
+	userID := uuid.New()
+	calls := 0
+	router, mock := newIdempotentRouter(t, userID, time.Hour, http.StatusCreated, &calls)
+	key := "idempotency:" + userID.String() + ":/jobs:abc-123"
+
+	mock.ExpectSetNX(key, inFlightRecord, idempotencyInFlightTTL).SetVal(false)
+	mock.ExpectGet(key).SetVal(string(inFlightRecord))
+
+	w := postWithKey(router, "abc-123")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_DoesNotCacheFailures(t *testing.T) {
+	userID := uuid.New()
+	calls := 0
+	router, mock := newIdempotentRouter(t, userID, time.Hour, http.StatusBadRequest, &calls)
+	key := "idempotency:" + userID.String() + ":/jobs:abc-123"
+
+	// The key is released so a corrected retry can go through
+	mock.ExpectSetNX(key, inFlightRecord, idempotencyInFlightTTL).SetVal(true)
+	mock.ExpectDel(key).SetVal(1)
+
+	w := postWithKey(router, "abc-123")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 1, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_PassesThroughWithoutKey(t *testing.T) {
+	calls := 0
+	router, mock := newIdempotentRouter(t, uuid.New(), time.Hour, http.StatusCreated, &calls)
+
+	postWithKey(router, "")
+	postWithKey(router, "")
+
+	assert.Equal(t, 2, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_AllowsWhenRedisFails(t *testing.T) {
+	calls := 0
+	router, mock := newIdempotentRouter(t, uuid.New(), time.Hour, http.StatusCreated, &calls)
+	mock.MatchExpectationsInOrder(false) // No expectations: every command returns an error
+
+	w := postWithKey(router, "abc-123")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlight counts the requests currently being served, so shutdown can wait for them and the health
+// probes can report them. Unlike http.Server.Shutdown it also sees hijacked connections such as
+// WebSockets, whose handlers keep running until the socket closes.
+type InFlight struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{} // Closed once count drops back to zero; nil while idle
+}
+
+// NewInFlight creates an InFlight with no requests in progress.
+func NewInFlight() *InFlight {
+	return &InFlight{}
+}
+
+// TrackInFlight is a middleware counting the request in tracker until its handlers return.
+func TrackInFlight(tracker *InFlight) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tracker.add()
+		defer tracker.done() // Deferred so a panicking handler is still uncounted
+		c.Next()
+	}
+}
+
+func (f *InFlight) add() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.count == 0 {
+		f.idle = make(chan struct{})
+	}
+	f.count++
+}
+
+func (f *InFlight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count--
+	if f.count == 0 {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// Count returns how many requests are being served right now.
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Wait blocks until no request is in progress, or returns ctx's error once ctx is done.
+func (f *InFlight) Wait(ctx context.Context) error {
+	f.mu.Lock()
+	idle := f.idle
+	f.mu.Unlock()
+	if idle == nil {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain gracefully stops srv: it stops accepting connections, waits until the requests tracked here
+// have finished or ctx is done, and then closes whatever connections are left. It returns ctx's error
+// if requests had to be cut off.
+func (f *InFlight) Drain(ctx context.Context, srv *http.Server) error {
+	err := srv.Shutdown(ctx)
+	if err == nil {
+		err = f.Wait(ctx) // Shutdown doesn't wait for hijacked connections
+	}
+	if err != nil {
+		return errors.Join(err, srv.Close())
+	}
+	return nil
+}
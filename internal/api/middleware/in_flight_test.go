@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowServer serves GET /slow, which blocks until release is closed, through TrackInFlight.
+func slowServer(t *testing.T, tracker *InFlight, started chan<- struct{}, release <-chan struct{}) (*http.Server, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TrackInFlight(tracker))
+	router.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http.Server{Handler: router}
+	go srv.Serve(listener)
+	return srv, listener.Addr().String()
+}
+
+type slowResult struct {
+	status int
+	body   string
+	err    error
+}
+
+func getSlow(addr string) <-chan slowResult {
+	results := make(chan slowResult, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			results <- slowResult{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		results <- slowResult{status: resp.StatusCode, body: string(body), err: err}
+	}()
+	return results
+}
+
+func TestInFlight_DrainWaitsForActiveRequest(t *testing.T) {
+	tracker := NewInFlight()
+	started, release := make(chan struct{}), make(chan struct{})
+	srv, addr := slowServer(t, tracker, started, release)
+
+	results := getSlow(addr)
+	<-started
+	assert.Equal(t, 1, tracker.Count())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	drained := make(chan error, 1)
+	go func() { drained <- tracker.Drain(ctx, srv) }()
+
+	// New connections are refused while the active request keeps running
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned before the active request finished: %v", err)
+	default:
+	}
+
+	close(release)
+	require.NoError(t, <-drained)
+	result := <-results
+	require.NoError(t, result.err)
+	assert.Equal(t, http.StatusOK, result.status)
+	assert.Equal(t, "done", result.body)
+	assert.Equal(t, 0, tracker.Count())
+}
+
+func TestInFlight_DrainTimeoutClosesConnections(t *testing.T) {
+	tracker := NewInFlight()
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	srv, addr := slowServer(t, tracker, started, release)
+
+	results := getSlow(addr)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := tracker.Drain(ctx, srv)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Error(t, (<-results).err, "the request still running is cut off")
+}
+
+func TestInFlight_WaitWhenIdle(t *testing.T) {
+	tracker := NewInFlight()
+	assert.NoError(t, tracker.Wait(context.Background()))
+
+	tracker.add()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, tracker.Wait(ctx), context.Canceled)
+
+	tracker.done()
+	assert.NoError(t, tracker.Wait(ctx))
+	assert.Equal(t, 0, tracker.Count())
+}
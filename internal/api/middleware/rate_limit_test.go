@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitedRouter builds a router with a single rate limited POST /login route.
+func newRateLimitedRouter(t *testing.T, limit int, window time.Duration, keyFunc func(*gin.Context) string) (*gin.Engine, redismock.ClientMock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	redisClient, mock := redismock.NewClientMock()
+	router := gin.New()
+	router.POST("/login", RateLimit(redisClient, limit, window, keyFunc), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router, mock
+}
+
+// fixClock pins the rate limiter clock for the duration of a test.
+func fixClock(t *testing.T, now time.Time) {
+	t.Helper()
+	rateLimitNow = func() time.Time { return now }
+	t.Cleanup(func() { rateLimitNow = time.Now })
+}
+
+func TestRateLimit_IncrementsAndBlocks(t *testing.T) {
+	window := time.Minute
+	now := time.Unix(1_700_000_080, 0) // 40s into its window
+	fixClock(t, now)
+	router, mock := newRateLimitedRouter(t, 2, window, ClientIPKey)
+
+	windowStart := now.Truncate(window)
+	currentKey := "ratelimit:/login:ip:192.0.2.1:" + itoa(windowStart.Unix())
+	previousKey := "ratelimit:/login:ip:192.0.2.1:" + itoa(windowStart.Add(-window).Unix())
+
+	for i := int64(1); i <= 3; i++ {
+		mock.ExpectIncr(currentKey).SetVal(i)
+		mock.ExpectPExpire(currentKey, 2*window).SetVal(true)
+		mock.ExpectGet(previousKey).RedisNil()
+	}
+
+	codes := make([]int, 0, 3)
+	var lastRetryAfter string
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+		lastRetryAfter = w.Header().Get("Retry-After")
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, "20", lastRetryAfter, "Retry-After should be the rest of the current window")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimit_WeightsPreviousWindow(t *testing.T) {
+	window := time.Minute
+	now := time.Unix(1_700_000_070, 0) // 30s into its window, so the previous one still counts half
+	fixClock(t, now)
+	router, mock := newRateLimitedRouter(t, 5, window, ClientIPKey)
+
+	windowStart := now.Truncate(window)
+	currentKey := "ratelimit:/login:ip:192.0.2.1:" + itoa(windowStart.Unix())
+	previousKey := "ratelimit:/login:ip:192.0.2.1:" + itoa(windowStart.Add(-window).Unix())
+
+	// 10 in the previous window * 0.5 + 1 now = 6 > 5
+	mock.ExpectIncr(currentKey).SetVal(1)
+	mock.ExpectPExpire(currentKey, 2*window).SetVal(true)
+	mock.ExpectGet(previousKey).SetVal("10")
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimit_KeysOnLoginEmail(t *testing.T) {
+	window := time.Minute
+	now := time.Unix(1_700_000_080, 0)
+	fixClock(t, now)
+	router, mock := newRateLimitedRouter(t, 1, window, LoginEmailKey)
+
+	windowStart := now.Truncate(window)
+	currentKey := "ratelimit:/login:email:victim@test.com:" + itoa(windowStart.Unix())
+	previousKey := "ratelimit:/login:email:victim@test.com:" + itoa(windowStart.Add(-window).Unix())
+
+	// Two different IPs hitting the same account share one counter
+	mock.ExpectIncr(currentKey).SetVal(1)
+	mock.ExpectPExpire(currentKey, 2*window).SetVal(true)
+	mock.ExpectGet(previousKey).RedisNil()
+	mock.ExpectIncr(currentKey).SetVal(2)
+	mock.ExpectPExpire(currentKey, 2*window).SetVal(true)
+	mock.ExpectGet(previousKey).RedisNil()
+
+	codes := make([]int, 0, 2)
+	for _, ip := range []string{"192.0.2.1:1234", "198.51.100.7:4321"} {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":" Victim@Test.com ","password":"x"}`))
+		req.RemoteAddr = ip
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimit_AllowsWhenRedisFails(t *testing.T) {
+	router, mock := newRateLimitedRouter(t, 1, time.Minute, ClientIPKey)
+	mock.MatchExpectationsInOrder(false) // No expectations: every command returns an error
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
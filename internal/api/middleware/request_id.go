@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	requestIDCtx       = "requestID" // Key to store the request ID in context
+	maxRequestIDLength = 128         // Longer client-supplied IDs are replaced to keep log lines bounded
+)
+
+// RequestID assigns every request an X-Request-ID (reusing the client's if present), echoes it
+// in the response, and stores a logger tagged with it in the request context.
+func RequestID(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDCtx, requestID)
+		c.Header(requestIDHeader, requestID)
+
+		reqLogger := base.With("request_id", requestID)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), reqLogger))
+
+		c.Next()
+	}
+}
+
+// GetRequestIDFromContext returns the request ID assigned by RequestID, or "" if none.
+func GetRequestIDFromContext(c *gin.Context) string {
+	return c.GetString(requestIDCtx)
+}
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodyBytes rejects request bodies larger than n bytes with 413. The body is read up front through
+// http.MaxBytesReader, so bodies without a Content-Length are limited too and handlers never see a
+// truncated body.
+func MaxBodyBytes(n int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > n {
+			abortWithError(c, http.StatusRequestEntityTooLarge, dto.ErrorCodePayloadTooLarge, "Request body too large")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, n))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortWithError(c, http.StatusRequestEntityTooLarge, dto.ErrorCodePayloadTooLarge, "Request body too large")
+				return
+			}
+			abortWithError(c, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.Next()
+	}
+}
+
+// timeoutWriter sits between the handler and the real writer so Timeout can answer with 503 while the
+// handler is still running. Headers are kept in a private map until the handler first writes, and
+// once timed out every later write from the handler is dropped.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu        sync.Mutex
+	header    http.Header
+	status    int
+	committed bool // Headers and status were handed to the real writer
+	timedOut  bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: w, header: w.Header().Clone(), status: w.Status()}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut && !w.committed && code > 0 {
+		w.status = code
+	}
+}
+
+// commitLocked hands the handler's headers and status to the real writer. Callers must hold mu.
+func (w *timeoutWriter) commitLocked() {
+	if w.committed {
+		return
+	}
+	dst := w.ResponseWriter.Header()
+	clear(dst)
+	maps.Copy(dst, w.header)
+	w.ResponseWriter.WriteHeader(w.status)
+	w.committed = true
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.commitLocked()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.commitLocked()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.commitLocked()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.commitLocked()
+	w.ResponseWriter.Flush()
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.committed || w.timedOut {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ResponseWriter.Written()
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ResponseWriter.Size()
+}
+
+// timeout sends the 503 with body, unless the handler has already started its response. Reports whether it did.
+func (w *timeoutWriter) timeout(body []byte) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ResponseWriter.Written() {
+		return false
+	}
+	w.timedOut = true
+	w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+	w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush() // Don't make the client wait for the handler to notice the cancellation
+	return true
+}
+
+// finish passes a response the handler set up but never wrote (e.g. c.Status(204)) to the real writer.
+func (w *timeoutWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.commitLocked()
+	}
+}
+
+// Timeout gives every request a context deadline of d. Handlers pass the request context down to
+// pgx, so queries still running at the deadline are cancelled. If the handler hasn't started its
+// response by then the client gets 503 straight away and anything the handler writes afterwards is
+// discarded. The middleware still waits for the handler to return before finishing, since the gin
+// context is reused for the next request.
+//
+// WebSocket upgrades and server-sent event streams are passed through untouched; the connection is
+// meant to outlive the request.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.IsWebsocket() || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		// Captured now; c.Request must not be read here while the handler goroutine may replace it
+		reqLogger := logger.FromContext(ctx)
+		method, path := c.Request.Method, c.Request.URL.Path
+		timeoutBody, _ := json.Marshal(dto.ErrorResponse{Code: dto.ErrorCodeRequestTimeout, Message: "Request timed out", RequestID: GetRequestIDFromContext(c)})
+
+		writer := c.Writer
+		tw := newTimeoutWriter(writer)
+		c.Writer = tw
+
+		done := make(chan struct{})
+		var panicValue any
+		go func() {
+			defer close(done)
+			defer func() { panicValue = recover() }() // Re-raised below so gin.Recovery still handles it
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && tw.timeout(timeoutBody) {
+				reqLogger.Warn("Request timed out", "method", method, "path", path, "timeout", d)
+			}
+			<-done
+		}
+
+		tw.finish()
+		c.Writer = writer
+		if panicValue != nil {
+			panic(panicValue)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onlyReader hides the concrete type of a reader, so the request is sent without a Content-Length.
+type onlyReader struct{ io.Reader }
+
+func TestMaxBodyBytes(t *testing.T) {
+	const limit = 16
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		expectedBody   string // What the handler read
+	}{
+		{name: "WithinLimit", body: strings.NewReader(`{"a":"b"}`), expectedStatus: http.StatusOK, expectedBody: `{"a":"b"}`},
+		{name: "ExactlyAtLimit", body: strings.NewReader(strings.Repeat("x", limit)), expectedStatus: http.StatusOK, expectedBody: strings.Repeat("x", limit)},
+		{name: "OversizedContentLength", body: strings.NewReader(strings.Repeat("x", limit+1)), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "OversizedWithoutContentLength", body: onlyReader{strings.NewReader(strings.Repeat("x", 10*limit))}, expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "NoBody", body: nil, expectedStatus: http.StatusOK, expectedBody: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			handlerCalled := false
+			var handlerBody string
+			router := gin.New()
+			router.POST("/upload", MaxBodyBytes(limit), func(c *gin.Context) {
+				handlerCalled = true
+				b, err := io.ReadAll(c.Request.Body)
+				require.NoError(t, err)
+				handlerBody = string(b)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/upload", tt.body)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.True(t, handlerCalled)
+				assert.Equal(t, tt.expectedBody, handlerBody)
+			} else {
+				assert.False(t, handlerCalled, "Oversized bodies must not reach the handler")
+				assert.JSONEq(t, `{"code":"payload_too_large","message":"Request body too large"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func newTimeoutRouter(timeout time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/work", Timeout(timeout), handler)
+	return router
+}
+
+func TestTimeout_SlowHandlerGets503AndContextIsCancelled(t *testing.T) {
+	handlerErr := make(chan error, 1)
+	router := newTimeoutRouter(20*time.Millisecond, func(c *gin.Context) {
+		// Stands in for a pgx query, which returns once its context is cancelled
+		select {
+		case <-c.Request.Context().Done():
+			handlerErr <- c.Request.Context().Err()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query cancelled"})
+		case <-time.After(5 * time.Second):
+			handlerErr <- nil
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		}
+	})
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/work", nil))
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"code":"request_timeout","message":"Request timed out"}`, w.Body.String(), "The handler's late response must be discarded")
+	assert.ErrorIs(t, <-handlerErr, context.DeadlineExceeded)
+}
+
+func TestTimeout_FastHandlerIsUnaffected(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        gin.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "JSONBody",
+			handler: func(c *gin.Context) {
+				c.Header("X-Custom", "yes")
+				c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status":"ok"}`,
+		},
+		{
+			name:           "StatusOnly",
+			handler:        func(c *gin.Context) { c.Header("X-Custom", "yes"); c.Status(http.StatusNoContent) },
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTimeoutRouter(time.Second, tt.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/work", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "yes", w.Header().Get("X-Custom"))
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTimeout_EventStreamIsPassedThrough(t *testing.T) {
+	router := newTimeoutRouter(10*time.Millisecond, func(c *gin.Context) {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, c.Request.Context().Err(), "streams have no deadline")
+		c.String(http.StatusOK, "data: done\n\n")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/work", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "data: done\n\n", w.Body.String())
+}
+
+func TestTimeout_HandlerPanicReachesRecovery(t *testing.T) {
+	router := newTimeoutRouter(time.Second, func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	require.NotPanics(t, func() {
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/work", nil))
+	})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing creates a server span for every request, continuing the caller's trace when the request
+// carries a traceparent header. The span is tagged with the request ID and the request logger with
+// the trace ID, so logs and traces can be joined either way. It must run after RequestID.
+func Tracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) gin.HandlerFunc {
+	tracer := provider.Tracer(tracing.InstrumentationName)
+	return func(c *gin.Context) {
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				attribute.String("request_id", GetRequestIDFromContext(c)),
+			),
+		)
+		defer span.End()
+
+		if spanCtx := span.SpanContext(); spanCtx.IsValid() {
+			reqLogger := logger.FromContext(ctx).With("trace_id", spanCtx.TraceID().String(), "span_id", spanCtx.SpanID().String())
+			ctx = logger.WithContext(ctx, reqLogger)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracingRouter returns a router that records spans into the returned exporter and logs
+// access lines as JSON into the returned buffer.
+func newTracingRouter(handler gin.HandlerFunc) (*gin.Engine, *tracetest.InMemoryExporter, *bytes.Buffer) {
+	gin.SetMode(gin.TestMode)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(
+		RequestID(slog.New(slog.NewJSONHandler(&logs, nil))),
+		Tracing(provider, propagation.TraceContext{}),
+		AccessLog(AccessLogConfig{}),
+	)
+	router.GET("/jobs/:id", handler)
+	return router, exporter, &logs
+}
+
+// spanAttributes flattens the attributes of span for lookups by key.
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracing_RecordsServerSpan(t *testing.T) {
+	var handlerSpan trace.SpanContext
+	router, exporter, logs := newTracingRouter(func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/42", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /jobs/:id", span.Name)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	assert.Equal(t, span.SpanContext, handlerSpan, "Handlers must see the request span in their context")
+
+	attrs := spanAttributes(span)
+	assert.Equal(t, "GET", attrs["http.request.method"].AsString())
+	assert.Equal(t, "/jobs/:id", attrs["http.route"].AsString())
+	assert.Equal(t, "/jobs/42", attrs["url.path"].AsString())
+	assert.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, "req-123", attrs["request_id"].AsString())
+
+	line := accessLogLine(t, logs)
+	assert.Equal(t, "req-123", line["request_id"])
+	assert.Equal(t, span.SpanContext.TraceID().String(), line["trace_id"], "Log lines must carry the trace ID")
+}
+
+func TestTracing_ContinuesIncomingTrace(t *testing.T) {
+	router, exporter, _ := newTracingRouter(func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "Server errors must mark the span as failed")
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAPIKeyRoutes registers the admin-only endpoints that mint and revoke API keys.
+func RegisterAPIKeyRoutes(rg *gin.RouterGroup, apiKeyHandler handlers.APIKeyHandlerInterface, authMiddleware gin.HandlerFunc) {
+	apiKeys := rg.Group("/admin/api-keys")
+	apiKeys.Use(authMiddleware, middleware.RequireCapability(models.CapabilityManageAPIKeys))
+	{
+		apiKeys.POST("/", apiKeyHandler.CreateAPIKey)      // Mint a key; it is only returned once
+		apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey) // Revoke a key
+	}
+}
+
+// RegisterServiceRoutes registers the endpoints called by machine clients with an X-API-Key header.
+// Each route requires the scope it acts under.
+func RegisterServiceRoutes(rg *gin.RouterGroup, invoiceHandler handlers.InvoiceHandlerInterface, apiKeyMiddleware gin.HandlerFunc) {
+	service := rg.Group("/service")
+	service.Use(apiKeyMiddleware)
+	{
+		service.POST("/invoices/:id/pay", middleware.RequireScope(models.APIKeyScopeInvoicesPay), invoiceHandler.MarkInvoicePaid) // Billing reports a payment
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAvatarRoutes registers the routes for managing the current user's profile picture.
+func RegisterAvatarRoutes(
+	rg *gin.RouterGroup,
+	avatarHandler handlers.AvatarHandlerInterface,
+	authMiddleware gin.HandlerFunc,
+) {
+	usersGroup := rg.Group("/users")
+	usersGroup.Use(authMiddleware)
+	{
+		usersGroup.POST("/me/avatar", avatarHandler.UploadMyAvatar)   // Multipart upload, replaces any previous avatar
+		usersGroup.DELETE("/me/avatar", avatarHandler.DeleteMyAvatar) // Clears the avatar
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterDashboardRoutes registers the aggregated dashboard routes.
+func RegisterDashboardRoutes(
+	rg *gin.RouterGroup,
+	dashboardHandler handlers.DashboardHandlerInterface,
+	authMiddleware gin.HandlerFunc,
+) {
+	usersGroup := rg.Group("/users")
+	usersGroup.Use(authMiddleware)
+	{
+		usersGroup.GET("/me/dashboard", dashboardHandler.GetMyDashboard) // Active jobs, pending applications and unpaid invoice totals
+	}
+}
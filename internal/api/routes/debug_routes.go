@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterDebugRoutes registers the admin-only diagnostics endpoints.
+func RegisterDebugRoutes(rg *gin.RouterGroup, debugHandler handlers.DebugHandlerInterface, authMiddleware gin.HandlerFunc) {
+	debug := rg.Group("/debug")
+	debug.Use(authMiddleware, middleware.RequireCapability(models.CapabilityAccessAdmin))
+	{
+		debug.GET("/dbstats", debugHandler.DBStats) // Connection pool saturation
+	}
+}
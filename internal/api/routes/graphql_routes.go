@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterGraphQLRoutes registers the read-only GraphQL endpoint. It sits outside /api/v1, where
+// GraphQL clients expect it, behind the same JWT auth as the REST routes.
+func RegisterGraphQLRoutes(router *gin.Engine, graphQLHandler gin.HandlerFunc, authMiddleware gin.HandlerFunc) {
+	router.GET("/graphql", authMiddleware, graphQLHandler)
+	router.POST("/graphql", authMiddleware, graphQLHandler)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/app"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterHealthRoutes registers the unauthenticated liveness and readiness probes at the root,
+// where orchestrators expect them.
+func RegisterHealthRoutes(router *gin.Engine, app *app.Application, inFlight *middleware.InFlight) {
+	// Only probe the blockchain RPC when a listener is running; a typed nil would defeat the nil check
+	var rpcChecker handlers.RPCHealthChecker
+	if app.EventListener != nil {
+		rpcChecker = app.EventListener
+	}
+	healthHandler := handlers.NewHealthHandler(app.DBPool, app.RedisClient, rpcChecker, app.Config.Blockchain.HealthRequired, app.Config.Blockchain.HealthTimeout).
+		WithInFlight(inFlight)
+
+	router.GET("/healthz", handlers.HealthCheck)   // Liveness: the process is serving requests
+	router.GET("/readyz", healthHandler.Readiness) // Readiness: Postgres, Redis and (optionally) the blockchain RPC are reachable
+}
@@ -12,14 +12,16 @@ func RegisterInvoiceRoutes(
 	rg *gin.RouterGroup, 
 	invoiceHandler handlers.InvoiceHandlerInterface, 
 	authMiddleware gin.HandlerFunc,
+	idempotency gin.HandlerFunc, // Applied to invoice creation and generation
 ) {
 	// Create a group for general invoice actions (e.g., /api/v1/invoices)
 	invoices := rg.Group("/invoices")
 	invoices.Use(authMiddleware) // Apply auth middleware
 	{
-		invoices.POST("/", invoiceHandler.CreateInvoice)       // Create a new invoice (handler calculates value/interval)
+		invoices.POST("/", idempotency, invoiceHandler.CreateInvoice) // Create a new invoice (handler calculates value/interval)
 		invoices.GET("/:id", invoiceHandler.GetInvoiceByID)    // Get a specific invoice by ID
 		invoices.PATCH("/:id/state", invoiceHandler.UpdateInvoiceState) // Update the state of an invoice
+		invoices.POST("/:id/pay", invoiceHandler.MarkInvoicePaid)       // Record payment of a Complete invoice
 		invoices.DELETE("/:id", invoiceHandler.DeleteInvoice)  // Delete an invoice
 	}
 
@@ -27,6 +29,16 @@ func RegisterInvoiceRoutes(
 	jobsGroupForInvoices.Use(authMiddleware)
 	{
 		jobsGroupForInvoices.GET("/:id/invoices", invoiceHandler.ListInvoicesByJob)
+		jobsGroupForInvoices.GET("/:id/invoices/stream", invoiceHandler.StreamJobInvoices) // Server-sent events on invoice state changes
+		jobsGroupForInvoices.GET("/:id/balance", invoiceHandler.GetJobBalance) // Invoiced, paid and remaining totals
+		jobsGroupForInvoices.POST("/:id/invoices/generate", idempotency, invoiceHandler.GenerateAllInvoices) // Create every outstanding interval invoice
+	}
+
+	usersGroupForInvoices := rg.Group("/users")
+	usersGroupForInvoices.Use(authMiddleware)
+	{
+		usersGroupForInvoices.GET("/me/overdue-invoices", invoiceHandler.ListOverdueInvoices) // Unpaid invoices past due on the current user's jobs
+		usersGroupForInvoices.GET("/me/invoices", invoiceHandler.ListEmployerInvoices) // Invoices on all of the current user's jobs; CSV with Accept: text/csv
 	}
 }
 
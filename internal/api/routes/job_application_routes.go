@@ -20,6 +20,10 @@ func RegisterJobApplicationRoutes(
 		jobsGroup.POST("/:id/apply", jobAppHandler.ApplyToJob)
 		// List applications for a specific job (Employer view)
 		jobsGroup.GET("/:id/applications", jobAppHandler.ListApplicationsByJob)
+		// Export applications for a specific job as CSV (Employer view)
+		jobsGroup.GET("/:id/applications/export", jobAppHandler.ExportApplicationsCSV)
+		// Invite a contractor directly (Employer only)
+		jobsGroup.POST("/:id/invitations", jobAppHandler.InviteContractor)
 	}
 
 	// Group for actions related to applications themselves
@@ -27,10 +31,20 @@ func RegisterJobApplicationRoutes(
 	appsGroup.Use(authMiddleware)
 	{
 		appsGroup.GET("/my", jobAppHandler.ListApplicationsByContractor) // List applications submitted by the current user
+		appsGroup.POST("/reject", jobAppHandler.BulkRejectApplications)  // Reject a selection of applications, reporting each outcome
 		appsGroup.GET("/:id", jobAppHandler.GetApplicationByID)
 		appsGroup.PATCH("/:id/accept", jobAppHandler.AcceptApplication)
 		appsGroup.PATCH("/:id/reject", jobAppHandler.RejectApplication)
 		appsGroup.PATCH("/:id/withdraw", jobAppHandler.WithdrawApplication)
+		appsGroup.PATCH("/:id/accept-invitation", jobAppHandler.AcceptInvitation) // Invited contractor only
+		appsGroup.PATCH("/:id/decline-invitation", jobAppHandler.DeclineInvitation)
 		// Note: Delete route is omitted for now, favoring Withdraw/Reject logic.
 	}
+
+	// The contractor's view of the jobs they applied to lives under /users with the other per-user lists
+	usersGroup := rg.Group("/users")
+	usersGroup.Use(authMiddleware)
+	{
+		usersGroup.GET("/me/applied-jobs", jobAppHandler.ListAppliedJobs) // Job details with the application inline
+	}
 }
\ No newline at end of file
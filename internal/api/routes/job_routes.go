@@ -3,6 +3,8 @@ package routes
 
 import (
 	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,17 +15,36 @@ func RegisterJobRoutes(
 	rg *gin.RouterGroup, // Base group (e.g., /api/v1)
 	jobHandler handlers.JobHandlerInterface, // Use interface
 	authMiddleware gin.HandlerFunc,
+	idempotency gin.HandlerFunc, // Applied to job creation
 ) {
 	jobs := rg.Group("/jobs")
 	jobs.Use(authMiddleware) // Apply auth middleware to all job routes
 	{
-		jobs.POST("/", jobHandler.CreateJob)             // Create a new job posting
+		jobs.POST("/", idempotency, jobHandler.CreateJob) // Create a new job posting
 		jobs.GET("/available", jobHandler.ListAvailableJobs) // List jobs available for contractors
 		jobs.GET("/my/employer", jobHandler.ListEmployerJobs) // List jobs posted by the authenticated employer
 		jobs.GET("/my/contractor", jobHandler.ListContractorJobs) // List jobs taken by the authenticated contractor
+		jobs.GET("/state-machine", jobHandler.GetJobStateMachine) // Allowed state transitions and who may make them
 		jobs.GET("/:id", jobHandler.GetJobByID)          // Get a specific job by ID
 		jobs.PATCH("/:id/details", jobHandler.UpdateJobDetails)     // Update Rate/Duration
 		jobs.PATCH("/:id/state", jobHandler.UpdateJobState) 
+		jobs.POST("/:id/transfer", jobHandler.TransferJob) // Hand the job over to another employer
+		jobs.POST("/:id/reassign", jobHandler.ReassignContractor) // Swap the contractor of an Ongoing job, keeping it Ongoing
+		jobs.POST("/:id/reopen", jobHandler.ReopenJob) // Move a Complete job back to Ongoing for more work
+		jobs.POST("/:id/cancel", jobHandler.CancelJob) // Stop an Ongoing job, optionally billing the work done so far
+		jobs.PUT("/:id/auto-invoice", jobHandler.SetJobAutoInvoice) // Turn scheduled interval invoicing on or off
 		jobs.DELETE("/:id", jobHandler.DeleteJob)        // Delete a job
+		jobs.GET("/:id/deletable", jobHandler.CanDeleteJob) // Dry run of the delete, listing what blocks it
+		jobs.POST("/:id/restore", middleware.RequireCapability(models.CapabilityRestoreJobs), jobHandler.RestoreJob) // Admin only: undo a soft delete
+		jobs.POST("/:id/save", jobHandler.SaveJob)     // Bookmark a job (idempotent)
+		jobs.DELETE("/:id/save", jobHandler.UnsaveJob) // Remove a bookmark (idempotent)
+	}
+
+	// Per-user job summaries live under /users but are served by the job handler
+	users := rg.Group("/users")
+	users.Use(authMiddleware)
+	{
+		users.GET("/:id/employer-stats", jobHandler.GetEmployerStats)
+		users.GET("/me/saved-jobs", jobHandler.ListSavedJobs) // Saved jobs that are still available
 	}
 }
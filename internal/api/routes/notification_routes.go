@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterNotificationRoutes registers the WebSocket notification endpoint.
+// It sits outside /api/v1 and accepts the token as a query parameter, since browsers can't send headers on the handshake.
+func RegisterNotificationRoutes(router *gin.Engine, notificationHandler handlers.NotificationHandlerInterface, authMiddleware gin.HandlerFunc) {
+	router.GET("/ws/notifications", middleware.TokenFromQuery(), authMiddleware, notificationHandler.Subscribe)
+}
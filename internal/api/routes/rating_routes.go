@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRatingRoutes registers all routes related to ratings.
+func RegisterRatingRoutes(
+	rg *gin.RouterGroup,
+	ratingHandler handlers.RatingHandlerInterface,
+	authMiddleware gin.HandlerFunc,
+	ratingsFlag gin.HandlerFunc, // Hides the routes while the ratings feature is off; runs before auth
+) {
+	jobsGroup := rg.Group("/jobs")
+	jobsGroup.Use(ratingsFlag, authMiddleware)
+	{
+		jobsGroup.POST("/:id/rating", ratingHandler.SubmitRating) // Employer rates the contractor of a completed job
+	}
+
+	usersGroup := rg.Group("/users")
+	usersGroup.Use(ratingsFlag, authMiddleware)
+	{
+		usersGroup.GET("/:id/rating", ratingHandler.GetUserRating) // Average rating received by a user
+	}
+}
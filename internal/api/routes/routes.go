@@ -6,7 +6,12 @@ import (
 	"go-api-template/internal/api/handlers"
 	"go-api-template/internal/api/middleware" // Import postgres implementation
 	"go-api-template/internal/app"
+	"go-api-template/internal/flags"
+	"go-api-template/internal/graph"
 	"go-api-template/internal/services"
+	"go-api-template/pkg/filestore"
+	"go-api-template/pkg/hasher"
+	"go-api-template/pkg/password"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -19,31 +24,101 @@ import (
 func RegisterRoutes(router *gin.Engine, app *app.Application) {
 
 	// --- Base API Group ---
-	apiV1 := router.Group("/api/v1")
+	apiV1 := router.Group(handlers.APIBasePath)
 
 
+	passwordPolicy := password.Policy{
+		MinLength:     app.Config.Password.MinLength,
+		RequireUpper:  app.Config.Password.RequireUpper,
+		RequireLower:  app.Config.Password.RequireLower,
+		RequireDigit:  app.Config.Password.RequireDigit,
+		RequireSymbol: app.Config.Password.RequireSymbol,
+	}
+
+	passwordHasher, err := hasher.New(hasher.Config{
+		Algorithm:  hasher.Algorithm(app.Config.Password.Algorithm),
+		BcryptCost: app.Config.Password.BcryptCost,
+		Argon2id: hasher.Argon2idParams{
+			MemoryKiB:   uint32(app.Config.Password.Argon2MemoryKiB),
+			Iterations:  uint32(app.Config.Password.Argon2Iterations),
+			Parallelism: uint8(app.Config.Password.Argon2Parallelism),
+		},
+	})
+	if err != nil {
+		log.Fatalf("Invalid password hashing configuration: %v", err) // config.Load validates this already
+	}
+
+	avatarStore, err := filestore.NewLocal(app.Config.Avatar.Dir, app.Config.Avatar.BaseURL)
+	if err != nil {
+		log.Fatalf("Failed to set up avatar storage: %v", err)
+	}
+
 	// Create services
-	userService := services.NewUserService(app.RedisClient,app.Config.JWT.Secret, app.Config.JWT.Expiration, app.Config.JWT.RefreshExpiration, app.DBPool)
-	jobService := services.NewJobService(app.DBPool)
-	invoiceService := services.NewInvoiceService(app.DBPool)
-	jobAppService := services.NewJobApplicationService(app.DBPool)
+	jwtKeyID, jwtSecret := app.Config.JWT.SigningKey()
+	userService := services.NewUserService(app.RedisClient, jwtKeyID, jwtSecret, app.Config.JWT.Expiration, app.Config.JWT.RefreshExpiration, app.Config.JWT.ImpersonationExpiration, app.DBPool, app.Mailer, app.Config.Auth.RequireVerifiedEmail, passwordPolicy, passwordHasher, app.Config.Auth.LockoutThreshold, app.Config.Auth.LockoutCooldown, app.ReadCache)
+	jobService := services.NewJobService(app.DBPool, app.Config.Invoice.PaymentTermDays, app.Config.Currency.Default, app.Config.Contractor.MaxOngoingJobs, app.ReadCache)
+	invoiceService := services.NewInvoiceService(app.DBPool, app.JobStreams, app.Config.Invoice.PaymentTermDays, app.Config.Invoice.MaxAdjustmentPercent, app.ReadCache)
+	jobAppService := services.NewJobApplicationService(app.DBPool, app.Notifications, app.Config.Contractor.MaxOngoingJobs, app.ReadCache)
+	ratingService := services.NewRatingService(app.DBPool, app.ReadCache)
+	apiKeyService := services.NewAPIKeyService(app.DBPool)
+	webhookService := services.NewWebhookService(app.DBPool)
+	dashboardService := services.NewDashboardService(app.DBPool)
+	avatarService := services.NewAvatarService(app.DBPool, avatarStore, app.ReadCache)
 
 	//Create handlers
-	userHandler := handlers.NewUserHandler(userService, app.Validator)
-	jobHandler := handlers.NewJobHandler(jobService, app.Validator)
-	invoiceHandler := handlers.NewInvoiceHandler(invoiceService, app.Validator)
-	jobAppHandler := handlers.NewJobApplicationHandler(jobAppService, app.Validator)
+	userHandler := handlers.NewUserHandler(userService, app.Validator).WithMaxBatchGetUsers(app.Config.Server.MaxBatchGetUsers).WithIdempotentRegistration(app.Config.Auth.IdempotentRegistration).WithMaxPageSize(app.Config.Server.MaxPageSize)
+	jobHandler := handlers.NewJobHandler(jobService, app.Validator).WithMaxPageSize(app.Config.Server.MaxPageSize)
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceService, app.Validator).WithMaxPageSize(app.Config.Server.MaxPageSize).WithJobStreams(app.JobStreams)
+	jobAppHandler := handlers.NewJobApplicationHandler(jobAppService, app.Validator).WithMaxPageSize(app.Config.Server.MaxPageSize)
+	ratingHandler := handlers.NewRatingHandler(ratingService, app.Validator)
+	notificationHandler := handlers.NewNotificationHandler(app.Notifications)
+	debugHandler := handlers.NewDebugHandler(app.DBPool)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService, app.Validator)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, app.Validator).WithMaxPageSize(app.Config.Server.MaxPageSize)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	avatarHandler := handlers.NewAvatarHandler(avatarService, app.Config.Avatar.MaxBytes)
+	graphQLHandler := graph.NewHandler(graph.NewResolver(jobService, invoiceService, jobAppService, userService).WithMaxPageSize(app.Config.Server.MaxPageSize))
 
 	// --- Middleware ---
-	authMiddleware := middleware.JWTAuthMiddleware(app.Config.JWT.Secret)
+	authMiddleware := middleware.JWTAuthMiddleware(app.Config.JWT.VerificationKeys(), userService, app.Config.JWT.BlacklistFailOpen)
+	optionalAuthMiddleware := middleware.OptionalJWTAuthMiddleware(app.Config.JWT.VerificationKeys(), userService, app.Config.JWT.BlacklistFailOpen) // Logout revokes the access token it is sent with
+	apiKeyMiddleware := middleware.APIKeyAuth(apiKeyService) // Machine clients on the /service routes
+
+	// Brute-force protection for the auth endpoints; login is limited per IP and per targeted email
+	var loginLimits, registerLimits gin.HandlersChain
+	if rl := app.Config.RateLimit; rl.Enabled {
+		loginLimits = gin.HandlersChain{
+			middleware.RateLimit(app.RedisClient, rl.LoginLimit, rl.Window, middleware.ClientIPKey),
+			middleware.RateLimit(app.RedisClient, rl.LoginEmailLimit, rl.Window, middleware.LoginEmailKey),
+		}
+		registerLimits = gin.HandlersChain{
+			middleware.RateLimit(app.RedisClient, rl.RegisterLimit, rl.Window, middleware.ClientIPKey),
+		}
+	}
+
+	// Makes retried creates safe; runs after auth since keys are scoped per user
+	idempotency := middleware.Idempotency(app.RedisClient, app.Config.Idempotency.TTL)
 
 	// --- Register Resource Routes ---
-	RegisterUserRoutes(apiV1, userHandler, authMiddleware)
-	RegisterInvoiceRoutes(apiV1, invoiceHandler, authMiddleware)
-	RegisterJobRoutes(apiV1, jobHandler, authMiddleware)
+	RegisterUserRoutes(apiV1, userHandler, authMiddleware, optionalAuthMiddleware, loginLimits, registerLimits)
+	RegisterInvoiceRoutes(apiV1, invoiceHandler, authMiddleware, idempotency)
+	RegisterJobRoutes(apiV1, jobHandler, authMiddleware, idempotency)
 	RegisterJobApplicationRoutes(apiV1, jobAppHandler, authMiddleware)
+	RegisterRatingRoutes(apiV1, ratingHandler, authMiddleware, middleware.RequireFlag(app.Flags, flags.Ratings))
+	RegisterDashboardRoutes(apiV1, dashboardHandler, authMiddleware)
+	RegisterAvatarRoutes(apiV1, avatarHandler, authMiddleware)
+	RegisterNotificationRoutes(router, notificationHandler, authMiddleware)
+	RegisterGraphQLRoutes(router, graphQLHandler, authMiddleware)
+	RegisterDebugRoutes(apiV1, debugHandler, authMiddleware)
+	RegisterAPIKeyRoutes(apiV1, apiKeyHandler, authMiddleware)
+	RegisterWebhookRoutes(apiV1, webhookHandler, authMiddleware)
+	RegisterServiceRoutes(apiV1, invoiceHandler, apiKeyMiddleware)
+
+	// --- Uploaded Files ---
+	router.Static(app.Config.Avatar.BaseURL, app.Config.Avatar.Dir)
 
 	// --- Health Check ---
+	// Kept for existing clients; /healthz and /readyz are registered by the server (see RegisterHealthRoutes)
 	apiV1.GET("/health", handlers.HealthCheck)
 
 	// --- Swagger UI ---)
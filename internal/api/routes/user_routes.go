@@ -2,29 +2,49 @@ package routes
 
 import (
 	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 // registerUserRoutes registers all routes related to users
-func RegisterUserRoutes(rg *gin.RouterGroup, userHandler handlers.UserHandlerInterface, authMiddleware gin.HandlerFunc) {
+// loginLimits and registerLimits run before the login/register handlers and may be empty.
+// optionalAuthMiddleware authenticates logout when it carries an access token, so that token can be revoked.
+func RegisterUserRoutes(rg *gin.RouterGroup, userHandler handlers.UserHandlerInterface, authMiddleware, optionalAuthMiddleware gin.HandlerFunc, loginLimits, registerLimits gin.HandlersChain) {
 	// Define the sub-group for users (e.g., /api/v1/users)
 	users := rg.Group("/users")
 	users.Use(authMiddleware) // Apply JWT authentication middleware to all user routes
 	{
-		users.GET("/", userHandler.GetUsers)
+		users.GET("/", middleware.RequireCapability(models.CapabilityManageUsers), userHandler.GetUsers) // Admin only
+		users.GET("/me", userHandler.GetMe) // Static segment, matched before /:id
+		users.GET("/me/permissions", userHandler.GetMyPermissions)
 		users.GET("/:id", userHandler.GetUserByID)
+		users.POST("/batch-get", userHandler.BatchGetUsers)
 		users.PUT("/:id", userHandler.UpdateUser)
 		users.DELETE("/:id", userHandler.DeleteUser)
+		users.POST("/:id/change-password", userHandler.ChangePassword)
+		users.POST("/:id/restore", middleware.RequireCapability(models.CapabilityManageUsers), userHandler.RestoreUser) // Admin only
+	}
+
+	// --- Admin Routes ---
+	adminUsers := rg.Group("/admin/users")
+	adminUsers.Use(authMiddleware, middleware.RequireCapability(models.CapabilityImpersonateUsers))
+	{
+		adminUsers.POST("/:id/impersonate", userHandler.ImpersonateUser) // Short-lived token acting as the user
 	}
 
 	// --- Authentication Routes ---
 	// Create a sub-group for authentication (e.g., /api/v1/auth)
 	auth := rg.Group("/auth")
 	{
-		auth.POST("/register", userHandler.Register) // Route for user registration
-		auth.POST("/login", userHandler.Login)       // Route for user login
+		auth.POST("/register", append(registerLimits, userHandler.Register)...) // Route for user registration
+		auth.POST("/login", append(loginLimits, userHandler.Login)...)          // Route for user login
 		auth.POST("/refresh", userHandler.Refresh) 
-		auth.POST("/logout", userHandler.Logout)
+		auth.POST("/logout", optionalAuthMiddleware, userHandler.Logout)
+		auth.POST("/password-reset", userHandler.RequestPasswordReset)
+		auth.POST("/password-reset/confirm", userHandler.ConfirmPasswordReset)
+		auth.POST("/verify-email", userHandler.VerifyEmail)
+		auth.POST("/verify-email/resend", userHandler.ResendVerificationEmail)
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterWebhookRoutes registers the admin-only endpoints that manage the webhooks of external integrations.
+func RegisterWebhookRoutes(rg *gin.RouterGroup, webhookHandler handlers.WebhookHandlerInterface, authMiddleware gin.HandlerFunc) {
+	webhooks := rg.Group("/admin/webhooks")
+	webhooks.Use(authMiddleware, middleware.RequireCapability(models.CapabilityManageWebhooks))
+	{
+		webhooks.POST("/", webhookHandler.CreateWebhook)                      // Register; the secret is only returned once
+		webhooks.GET("/", webhookHandler.ListWebhooks)                        // List every webhook
+		webhooks.PATCH("/:id", webhookHandler.UpdateWebhook)                  // Pause or resume
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)                 // Delete with its deliveries
+		webhooks.GET("/:id/deliveries", webhookHandler.ListWebhookDeliveries) // Delivery status and attempts
+	}
+}
@@ -2,7 +2,14 @@
 package app
 
 import (
+	"log/slog"
+
 	"go-api-template/config"
+	"go-api-template/internal/blockchain"
+	"go-api-template/internal/flags"
+	"go-api-template/internal/mailer"
+	"go-api-template/internal/notifications"
+	"go-api-template/internal/storage/cache"
 
 	"github.com/go-playground/validator"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -15,4 +22,11 @@ type Application struct {
 	DBPool   *pgxpool.Pool
 	RedisClient *redis.Client
 	Validator *validator.Validate
+	EventListener *blockchain.EventListener // nil when the blockchain listener is not configured
+	Notifications *notifications.Hub // WebSocket notification hub, fanned out across instances via Redis
+	JobStreams    *notifications.JobStreams // Server-sent event streams of job events, fanned out the same way
+	Mailer        mailer.Mailer             // What services send emails through; queues them in the outbox when the relay runs
+	Flags         flags.Provider            // Feature flags gating gradually rolled out endpoints
+	Logger    *slog.Logger // Base logger; request-scoped loggers are derived from it
+	ReadCache cache.Config // Repositories whose reads go through Redis; the zero value caches nothing
 }
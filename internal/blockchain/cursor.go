@@ -0,0 +1,76 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const RedisLastBlockPrefix = "blockchain_last_block:"
+
+// BlockCursor persists the number of the last block whose events the listener has handled,
+// so a restarted listener can replay what it missed instead of starting at the current head.
+type BlockCursor interface {
+	// Load returns the stored block number, and false if nothing has been stored yet.
+	Load(ctx context.Context) (uint64, bool, error)
+	Save(ctx context.Context, block uint64) error
+}
+
+// RedisBlockCursor keeps the marker in Redis, keyed by the watched contract address.
+type RedisBlockCursor struct {
+	redisClient *redis.Client
+	key         string
+}
+
+// NewRedisBlockCursor creates a cursor for contractAddrHex.
+func NewRedisBlockCursor(redisClient *redis.Client, contractAddrHex string) *RedisBlockCursor {
+	return &RedisBlockCursor{redisClient: redisClient, key: RedisLastBlockPrefix + strings.ToLower(contractAddrHex)}
+}
+
+func (c *RedisBlockCursor) Load(ctx context.Context) (uint64, bool, error) {
+	val, err := c.redisClient.Get(ctx, c.key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	block, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return block, true, nil
+}
+
+func (c *RedisBlockCursor) Save(ctx context.Context, block uint64) error {
+	return c.redisClient.Set(ctx, c.key, strconv.FormatUint(block, 10), 0).Err()
+}
+
+// MemoryBlockCursor keeps the marker in memory. It only covers reconnects, not restarts.
+type MemoryBlockCursor struct {
+	mu    sync.Mutex
+	block uint64
+	set   bool
+}
+
+// NewMemoryBlockCursor creates an empty in-memory cursor.
+func NewMemoryBlockCursor() *MemoryBlockCursor {
+	return &MemoryBlockCursor{}
+}
+
+func (c *MemoryBlockCursor) Load(ctx context.Context) (uint64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.block, c.set, nil
+}
+
+func (c *MemoryBlockCursor) Save(ctx context.Context, block uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.block, c.set = block, true
+	return nil
+}
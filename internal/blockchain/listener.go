@@ -2,13 +2,16 @@ package blockchain
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/big"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -27,29 +30,106 @@ type AnswerUpdatedEvent struct {
 	Raw       types.Log // Optionally keep raw log
 }
 
+// ChainClient is the part of *ethclient.Client the listener uses, so tests can stand in for a node.
+type ChainClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	Close()
+}
+
+// backfillChunkSize bounds the block range of each eth_getLogs call while replaying, as nodes cap it.
+const backfillChunkSize = 2000
+
+const (
+	defaultReconnectBaseDelay = time.Second
+	defaultReconnectMaxDelay  = time.Minute
+)
+
+// ConnectionState is the listener's subscription health, as reported to the readiness probe.
+type ConnectionState int32
+
+const (
+	StateConnecting   ConnectionState = iota // Started, first subscription not up yet
+	StateConnected                           // Subscribed and handling live events
+	StateReconnecting                        // The subscription dropped; retrying with backoff
+	StateFailed                              // Gave up after the maximum number of reconnect attempts
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
 type EventListener struct {
-	client         *ethclient.Client
+	client         ChainClient
+	dial           func(ctx context.Context, rpcURL string) (ChainClient, error) // Used to reconnect; replaced in tests
+	clientMu       sync.RWMutex // Guards client swaps against concurrent health checks
+	lastBlock      atomic.Uint64 // Block number of the last log handled, 0 until one arrives
 	contractAddr   common.Address
 	contractAddrAgg common.Address
 	contractABI    abi.ABI
 	// service *services.BlockchainService // Example service to handle data
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
-	logger         *log.Logger
+	logger         *slog.Logger
 	rpcURL         string // Store for potential reconnection
 	filterQuery    ethereum.FilterQuery
 	eventSignature common.Hash // Store the signature hash for the event we care about
+	cursor         BlockCursor // Persists the last handled block across restarts
+	startBlock     uint64      // Where to replay from when the cursor is empty; 0 starts at the head
+	handle         func(vLog types.Log) // Handles a matching log; replaced in tests
+	state          atomic.Int32 // A ConnectionState
+	maxReconnects  int          // Consecutive failed attempts before giving up; 0 retries forever
+	reconnectBaseDelay time.Duration
+	reconnectMaxDelay  time.Duration
+}
+
+func dialChainClient(ctx context.Context, rpcURL string) (ChainClient, error) {
+	return ethclient.DialContext(ctx, rpcURL)
 }
 
-// NewEventListener creates and initializes the listener
-func NewEventListener(rpcURL, contractAddrHex, abiPath string /*, other services */) (*EventListener, error) {
-	logger := log.New(os.Stdout, "[Blockchain] ", log.LstdFlags|log.Lshortfile) // Added Lshortfile for debugging
+// NewEventListener creates and initializes the listener.
+// On Start it first replays the events after the block stored in cursor, or from startBlock when
+// the cursor is empty, so events emitted while the service was down are not lost. A nil cursor keeps
+// the marker in memory, which only covers reconnects. If the subscription drops it reconnects with
+// backoff, giving up after maxReconnects consecutive failures (0 retries forever).
+func NewEventListener(rpcURL, contractAddrHex, abiPath string, cursor BlockCursor, startBlock uint64, maxReconnects int, baseLogger *slog.Logger /*, other services */) (*EventListener, error) {
+	if baseLogger == nil {
+		baseLogger = slog.Default()
+	}
+	logger := baseLogger.With("component", "blockchain")
 
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum client at %s: %w", rpcURL, err)
 	}
-	logger.Printf("Connected to Ethereum node: %s", rpcURL)
+	logger.Info("Connected to Ethereum node", "rpc_url", rpcURL)
+
+	listener, err := newEventListener(client, contractAddrHex, abiPath, cursor, startBlock, maxReconnects, logger)
+	if err != nil {
+		client.Close() // Close client if we fail early
+		return nil, err
+	}
+	listener.rpcURL = rpcURL // Store for potential reconnection
+	return listener, nil
+}
+
+// newEventListener resolves the contract's aggregator through client and builds the listener around it.
+func newEventListener(client ChainClient, contractAddrHex, abiPath string, cursor BlockCursor, startBlock uint64, maxReconnects int, logger *slog.Logger) (*EventListener, error) {
+	if cursor == nil {
+		cursor = NewMemoryBlockCursor()
+	}
 
 	contractAddr := common.HexToAddress(contractAddrHex)
 
@@ -59,22 +139,19 @@ func NewEventListener(rpcURL, contractAddrHex, abiPath string /*, other services
 		// Assuming relative path is from project root
 		wd, err := os.Getwd()
 		if err != nil {
-			client.Close() // Close client if we fail early
 			return nil, fmt.Errorf("failed to get working directory: %w", err)
 		}
 		absAbiPath = filepath.Join(wd, absAbiPath)
 	}
 
-	logger.Printf("Attempting to read ABI file: %s", absAbiPath)
+	logger.Info("Attempting to read ABI file", "path", absAbiPath)
 	abiBytes, err := os.ReadFile(absAbiPath)
 	if err != nil {
-		client.Close()
 		return nil, fmt.Errorf("failed to read ABI file '%s': %w", absAbiPath, err)
 	}
 
 	contractABI, err := abi.JSON(strings.NewReader(string(abiBytes)))
 	if err != nil {
-		client.Close()
 		return nil, fmt.Errorf("failed to parse contract ABI from '%s': %w", absAbiPath, err)
 	}
 
@@ -82,27 +159,26 @@ func NewEventListener(rpcURL, contractAddrHex, abiPath string /*, other services
 	eventName := "AnswerUpdated" //Example event with price feed data just for demo
 	eventABI, ok := contractABI.Events[eventName]
 	if !ok {
-		client.Close()
 		return nil, fmt.Errorf("event '%s' not found in ABI file '%s'", eventName, absAbiPath)
 	}
 	eventSignature := eventABI.ID
-	logger.Printf("Targeting event '%s' with signature: %s", eventName, eventSignature.Hex())
+	logger.Info("Targeting event", "event", eventName, "signature", eventSignature.Hex())
 
 	methodName := "aggregator"
 	// Pack the method call (no arguments for 'aggregator')
 	callData, err := contractABI.Pack(methodName)
 	if err != nil {
-		log.Fatalf("Failed to pack data for %s: %v", methodName, err)
+		return nil, fmt.Errorf("failed to pack data for %s: %w", methodName, err)
 	}
 
 	// Make the call
-	log.Printf("Calling method '%s' on contract %s...", methodName, contractAddrHex)
+	logger.Info("Calling contract method", "method", methodName, "contract", contractAddrHex)
 	resultBytes, err := client.CallContract(context.Background(), ethereum.CallMsg{
 		To:   &contractAddr,
 		Data: callData,
 	}, nil) // nil for latest block
 	if err != nil {
-		log.Fatalf("Failed to call contract method %s: %v", methodName, err)
+		return nil, fmt.Errorf("failed to call contract method %s: %w", methodName, err)
 	}
 
 	// Unpack the result - the 'aggregator' method returns a single address
@@ -111,12 +187,12 @@ func NewEventListener(rpcURL, contractAddrHex, abiPath string /*, other services
 	if err != nil {
 		results, errAlt := contractABI.Unpack(methodName, resultBytes)
 		if errAlt != nil || len(results) == 0 {
-			log.Fatalf("Failed to unpack %s result (tried both ways): %v / %v", methodName, err, errAlt)
+			return nil, fmt.Errorf("failed to unpack %s result (tried both ways): %v / %v", methodName, err, errAlt)
 		}
 		var ok bool
 		aggregatorAddress, ok = results[0].(common.Address)
 		if !ok {
-			log.Fatalf("Failed type assertion for %s result: expected common.Address, got %T", methodName, results[0])
+			return nil, fmt.Errorf("failed type assertion for %s result: expected common.Address, got %T", methodName, results[0])
 		}
 	}
 
@@ -128,8 +204,9 @@ func NewEventListener(rpcURL, contractAddrHex, abiPath string /*, other services
 		},
 	}
 
-	return &EventListener{
+	listener := &EventListener{
 		client:         client,
+		dial:           dialChainClient,
 		contractAddr:   contractAddr,
 		contractAddrAgg: aggregatorAddress,
 		contractABI:    contractABI,
@@ -137,112 +214,248 @@ func NewEventListener(rpcURL, contractAddrHex, abiPath string /*, other services
 		// service: service,
 		stopChan:     make(chan struct{}),
 		logger:       logger,
-		rpcURL:       rpcURL, // Store for potential reconnection
 		filterQuery:  query,
-	}, nil
+		cursor:       cursor,
+		startBlock:   startBlock,
+		maxReconnects:      maxReconnects,
+		reconnectBaseDelay: defaultReconnectBaseDelay,
+		reconnectMaxDelay:  defaultReconnectMaxDelay,
+	}
+	listener.handle = listener.handleAnswerUpdated
+	return listener, nil
 }
 
 // Start begins listening for events in a separate goroutine
 func (l *EventListener) Start(ctx context.Context) {
 	l.wg.Add(1)
 	go l.listenLoop(ctx)
-	l.logger.Printf("Started event listener for contract: %s, Event: AnswerUpdated", l.contractAddrAgg.Hex())
+	l.logger.Info("Started event listener", "contract", l.contractAddrAgg.Hex(), "event", "AnswerUpdated")
 }
 
 // Stop signals the listener to shut down and waits for it to complete
 func (l *EventListener) Stop() {
-	l.logger.Printf("Stopping event listener for contract: %s", l.contractAddrAgg.Hex())
+	l.logger.Info("Stopping event listener", "contract", l.contractAddrAgg.Hex())
 	close(l.stopChan) // Signal the loop to stop
 	l.wg.Wait()       // Wait for the loop goroutine to finish
-	l.client.Close()  // Close the connection
-	l.logger.Printf("Event listener stopped.")
+	if l.client != nil {
+		l.client.Close() // Close the connection
+	}
+	l.logger.Info("Event listener stopped")
+}
+
+// State reports whether the listener's subscription is up.
+func (l *EventListener) State() ConnectionState {
+	return ConnectionState(l.state.Load())
 }
 
-// listenLoop is the main event subscription loop with reconnection logic
+// setClient swaps the RPC client under lock so CheckRPC never sees a half-updated value.
+// Only listenLoop writes the client, so it may keep reading l.client without locking.
+func (l *EventListener) setClient(client ChainClient) {
+	l.clientMu.Lock()
+	l.client = client
+	l.clientMu.Unlock()
+}
+
+// CheckRPC fails while the subscription is down, and otherwise performs a lightweight eth_blockNumber
+// call against the node. On success it also refreshes the block lag gauge from the last handled log.
+func (l *EventListener) CheckRPC(ctx context.Context) (uint64, error) {
+	if state := l.State(); state != StateConnected {
+		return 0, fmt.Errorf("event subscription is %s", state)
+	}
+	l.clientMu.RLock()
+	client := l.client
+	l.clientMu.RUnlock()
+	if client == nil {
+		return 0, errors.New("rpc client is not connected")
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("eth_blockNumber failed: %w", err)
+	}
+
+	if last := l.lastBlock.Load(); last > 0 && head >= last {
+		blockLagGauge.Set(float64(head - last))
+	}
+	return head, nil
+}
+
+// listenLoop subscribes to the watched logs and handles them, reconnecting with backoff whenever the
+// connection or subscription fails. It gives up after maxReconnects consecutive failed attempts.
 func (l *EventListener) listenLoop(ctx context.Context) {
 	defer l.wg.Done() // Signal that this goroutine has finished when it exits
 
-	var sub ethereum.Subscription
-	var logs chan types.Log
-
-	connectAndSubscribe := func(loopCtx context.Context) error {
-		var err error
-		// Attempt reconnection if client is nil or connection is lost
-		if l.client == nil {
-			l.logger.Println("Attempting to reconnect client...")
-			l.client, err = ethclient.DialContext(loopCtx, l.rpcURL)
-			if err != nil {
-				return fmt.Errorf("reconnection failed: %w", err)
-			}
-			l.logger.Println("Reconnected to Ethereum node.")
+	// Stop cancels everything in flight, including a dial or replay during a reconnect
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-l.stopChan:
+			cancel()
+		case <-ctx.Done():
 		}
+	}()
 
-		logs = make(chan types.Log, 10) // Buffered channel
-		l.logger.Println("Attempting to subscribe to logs...")
-		l.logger.Printf("Listening for events on contracts: %v\n", l.filterQuery.Addresses)
-		sub, err = l.client.SubscribeFilterLogs(loopCtx, l.filterQuery, logs)
-		if err != nil {
+	attempt := 0
+	for {
+		err := l.subscribeAndHandle(ctx)
+		if ctx.Err() != nil {
+			l.logger.Info("Listener stopped, shutting down listener loop")
+			return
+		}
+		if l.state.Load() == int32(StateConnected) {
+			attempt = 0 // The subscription was up, so this is a fresh outage
+		}
+		l.state.Store(int32(StateReconnecting))
+		if l.client != nil {
 			l.client.Close() // Close potentially bad connection
-			l.client = nil   // Mark client as nil for next attempt
-			return fmt.Errorf("failed to subscribe: %w", err)
 		}
-		l.logger.Println("Subscription active. Waiting for events...")
-		return nil
+		l.setClient(nil) // Ensure Dial is called on next attempt
+
+		attempt++
+		if l.maxReconnects > 0 && attempt > l.maxReconnects {
+			l.state.Store(int32(StateFailed))
+			l.logger.Error("Giving up on the blockchain listener", "attempts", attempt-1, "error", err)
+			return
+		}
+		delay := l.reconnectDelay(attempt)
+		l.logger.Warn("Subscription failed, reconnecting", "attempt", attempt, "max_retries", l.maxReconnects, "retry_after", delay, "error", err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			l.logger.Info("Listener stopped during reconnect delay")
+			return
+		}
+	}
+}
+
+// subscribeAndHandle (re)connects if needed, subscribes, replays missed events and then handles live
+// logs until the subscription fails or ctx is cancelled.
+func (l *EventListener) subscribeAndHandle(ctx context.Context) error {
+	if l.client == nil {
+		l.logger.Info("Attempting to reconnect client")
+		client, err := l.dial(ctx, l.rpcURL)
+		if err != nil {
+			return fmt.Errorf("reconnection failed: %w", err)
+		}
+		l.setClient(client)
+		l.logger.Info("Reconnected to Ethereum node")
 	}
 
-	// Initial connection attempt
-	if err := connectAndSubscribe(ctx); err != nil {
-		l.logger.Printf("ERROR: Initial connection/subscription failed: %v. Listener will not run.", err)
-		return // Exit if initial connection fails
+	logs := make(chan types.Log, 10) // Buffered channel
+	l.logger.Info("Attempting to subscribe to logs", "contracts", l.filterQuery.Addresses)
+	sub, err := l.client.SubscribeFilterLogs(ctx, l.filterQuery, logs)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
 	}
+	defer sub.Unsubscribe()
 
-	reconnectDelay := 5 * time.Second // Initial delay
+	// Subscribe before replaying so nothing emitted during the backfill slips between the two;
+	// the subscription buffers new logs until the loop below reads them.
+	replayedThrough, err := l.backfill(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to replay missed events: %w", err)
+	}
+	l.state.Store(int32(StateConnected))
+	l.logger.Info("Subscription active, waiting for events")
 
 	for {
 		select {
-		case <-l.stopChan:
-			l.logger.Println("Received stop signal, shutting down listener loop.")
-			if sub != nil {
-				sub.Unsubscribe()
-			}
-			return
 		case <-ctx.Done():
-			l.logger.Println("Context cancelled, shutting down listener loop.")
-			if sub != nil {
-				sub.Unsubscribe()
-			}
-			return
+			return ctx.Err()
 		case err := <-sub.Err():
-			l.logger.Printf("ERROR: Subscription error: %v. Attempting to reconnect...", err)
-			sub.Unsubscribe() // Unsubscribe from the broken subscription
-			if l.client != nil {
-				l.client.Close() // Close the client connection
-			}
-			l.client = nil    // Ensure Dial is called on next attempt
-			sub = nil         // Mark subscription as inactive
-
-			select {
-			case <-time.After(reconnectDelay):
-				if err := connectAndSubscribe(ctx); err != nil {
-					l.logger.Printf("ERROR: Re-subscription failed: %v. Retrying after %v.", err, reconnectDelay)
-				} else {
-					reconnectDelay = 5 * time.Second // Reset delay on successful reconnect
-				}
-			case <-l.stopChan:
-				l.logger.Println("Stop signal received during reconnect delay.")
-				return
-			case <-ctx.Done():
-				l.logger.Println("Context cancelled during reconnect delay.")
-				return
-			}
+			return fmt.Errorf("subscription error: %w", err)
 		case vLog := <-logs:
-			if len(vLog.Topics) > 0 && vLog.Topics[0] == l.eventSignature {
-				l.logger.Printf("Received log: Block %d, Tx %s", vLog.BlockNumber, vLog.TxHash.Hex())
-				l.handleAnswerUpdated(vLog)
-			} else {
-				l.logger.Printf("WARN: Received unexpected log signature: %s (Expected: %s)", vLog.Topics[0].Hex(), l.eventSignature.Hex())
+			if vLog.BlockNumber <= replayedThrough {
+				continue // Already handled while replaying
 			}
+			l.processLog(ctx, vLog)
+		}
+	}
+}
+
+// reconnectDelay returns the wait before the given attempt: exponential from reconnectBaseDelay up to
+// reconnectMaxDelay, with jitter so instances don't reconnect in lockstep after a node restart.
+func (l *EventListener) reconnectDelay(attempt int) time.Duration {
+	delay := l.reconnectMaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if grown := l.reconnectBaseDelay << shift; grown > 0 { // Guards against overflow
+			delay = min(grown, l.reconnectMaxDelay)
+		}
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1) // Somewhere in [delay/2, delay]
+}
+
+// backfill handles the logs between the stored marker (or startBlock on a cold start) and the
+// current head, then records the head as processed. Returns the head it replayed through.
+func (l *EventListener) backfill(ctx context.Context) (uint64, error) {
+	head, err := l.client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("eth_blockNumber failed: %w", err)
+	}
+
+	from := l.startBlock
+	last, ok, err := l.cursor.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load last processed block: %w", err)
+	}
+	if ok {
+		from = last + 1
+	}
+	if from == 0 || from > head {
+		// Nothing to replay; a cold start without a start block begins at the head
+		l.saveCursor(ctx, head)
+		return head, nil
+	}
+
+	l.logger.Info("Replaying missed events", "from_block", from, "to_block", head)
+	replayed := 0
+	for chunkStart := from; chunkStart <= head; chunkStart += backfillChunkSize {
+		chunkEnd := min(chunkStart+backfillChunkSize-1, head)
+		query := l.filterQuery
+		query.FromBlock = new(big.Int).SetUint64(chunkStart)
+		query.ToBlock = new(big.Int).SetUint64(chunkEnd)
+
+		chunk, err := l.client.FilterLogs(ctx, query)
+		if err != nil {
+			return 0, fmt.Errorf("eth_getLogs for blocks %d-%d failed: %w", chunkStart, chunkEnd, err)
+		}
+		for _, vLog := range chunk {
+			l.processLog(ctx, vLog)
 		}
+		replayed += len(chunk)
+		l.saveCursor(ctx, chunkEnd)
+	}
+	l.logger.Info("Replayed missed events", "events", replayed, "to_block", head)
+	return head, nil
+}
+
+// processLog handles a log of the watched event and advances the processed-block marker past it.
+// Logs removed by a reorg are skipped.
+func (l *EventListener) processLog(ctx context.Context, vLog types.Log) {
+	if vLog.Removed {
+		l.logger.Warn("Skipping log removed by a reorg", "block", vLog.BlockNumber, "tx", vLog.TxHash.Hex())
+		return
+	}
+	if len(vLog.Topics) == 0 || vLog.Topics[0] != l.eventSignature {
+		l.logger.Warn("Received unexpected log signature", "topics", len(vLog.Topics), "expected", l.eventSignature.Hex())
+		return
+	}
+	l.logger.Info("Received log", "block", vLog.BlockNumber, "tx", vLog.TxHash.Hex())
+	l.lastBlock.Store(vLog.BlockNumber)
+	l.handle(vLog)
+	l.saveCursor(ctx, vLog.BlockNumber)
+}
+
+// saveCursor records block as processed. A failure only means a restart replays some events again.
+func (l *EventListener) saveCursor(ctx context.Context, block uint64) {
+	if err := l.cursor.Save(ctx, block); err != nil {
+		l.logger.Warn("Failed to save last processed block", "block", block, "error", err)
 	}
 }
 
@@ -252,7 +465,7 @@ func (l *EventListener) handleAnswerUpdated(vLog types.Log) {
 	eventABI, ok := l.contractABI.Events[eventName]
 	if !ok {
 		// This should ideally not happen as we check in NewEventListener, but good practice
-		l.logger.Printf("CRITICAL: ABI definition for event '%s' not found during handling.", eventName)
+		l.logger.Error("ABI definition for event not found during handling", "event", eventName)
 		return
 	}
 
@@ -261,7 +474,7 @@ func (l *EventListener) handleAnswerUpdated(vLog types.Log) {
 
 	// --- Unpack Indexed Fields from Topics ---
 	if len(vLog.Topics) < 3 { // This demo event should have at least 3 topics
-		l.logger.Printf("ERROR: Expected at least 3 topics for %s, got %d. Log: %+v", eventName, len(vLog.Topics), vLog)
+		l.logger.Error("Unexpected topic count", "event", eventName, "expected_min", 3, "got", len(vLog.Topics), "tx", vLog.TxHash.Hex())
 		return
 	}
 	eventData.Current = new(big.Int).SetBytes(vLog.Topics[1].Bytes())
@@ -270,14 +483,14 @@ func (l *EventListener) handleAnswerUpdated(vLog types.Log) {
 	// --- Unpack Non-Indexed Fields from Data ---
 	nonIndexedArgs := eventABI.Inputs.NonIndexed()
 	if len(nonIndexedArgs) == 0 && len(vLog.Data) > 0 {
-		l.logger.Printf("WARN: Event %s has non-empty data (%x) but no non-indexed arguments in ABI", eventName, vLog.Data)
+		l.logger.Warn("Event has non-empty data but no non-indexed arguments in ABI", "event", eventName, "data", fmt.Sprintf("%x", vLog.Data))
 		// If only indexed fields are needed, we might continue here.
 		// For AnswerUpdated, updatedAt is crucial, so we likely should return or handle differently.
 	} else if len(nonIndexedArgs) > 0 {
 		// Unpack the non-indexed fields from vLog.Data
 		unpackedData, err := nonIndexedArgs.Unpack(vLog.Data)
 		if err != nil {
-			l.logger.Printf("ERROR: Failed to unpack non-indexed data for %s: %v. Data: %x", eventName, err, vLog.Data)
+			l.logger.Error("Failed to unpack non-indexed data", "event", eventName, "error", err, "data", fmt.Sprintf("%x", vLog.Data))
 			return
 		}
 
@@ -286,29 +499,29 @@ func (l *EventListener) handleAnswerUpdated(vLog types.Log) {
 			var ok bool
 			eventData.UpdatedAt, ok = unpackedData[0].(*big.Int) // Type assertion
 			if !ok {
-				l.logger.Printf("ERROR: Type assertion failed for non-indexed argument 'updatedAt' (expected *big.Int). Value: %+v", unpackedData[0])
+				l.logger.Error("Type assertion failed for non-indexed argument 'updatedAt' (expected *big.Int)", "value", fmt.Sprintf("%+v", unpackedData[0]))
 				return // Stop processing if the type is wrong
 			}
 		} else {
-			l.logger.Printf("WARN: Unpack returned empty slice for non-indexed args of %s, though ABI defines them.", eventName)
+			l.logger.Warn("Unpack returned empty slice for non-indexed args, though ABI defines them", "event", eventName)
 		}
 	} else if len(vLog.Data) > 0 {
 		// ABI has no non-indexed args, but data is present. Log it.
-		l.logger.Printf("INFO: Event %s has data (%x) but no non-indexed arguments defined in ABI.", eventName, vLog.Data)
+		l.logger.Info("Event has data but no non-indexed arguments defined in ABI", "event", eventName, "data", fmt.Sprintf("%x", vLog.Data))
 	}
 
 	// Check if UpdatedAt was successfully unpacked if it's required
 	if eventData.UpdatedAt == nil {
-		l.logger.Printf("ERROR: Failed to obtain 'updatedAt' value for event %s. Log: %+v", eventName, vLog)
+		l.logger.Error("Failed to obtain 'updatedAt' value", "event", eventName, "tx", vLog.TxHash.Hex())
 	}
 
 
-	l.logger.Printf("Successfully unpacked %s: Current=%s, RoundId=%s, UpdatedAt=%s (Block: %d)",
-		eventName,
-		eventData.Current.String(),
-		eventData.RoundId.String(),
-		eventData.UpdatedAt.String(), // Use the unpacked value
-		vLog.BlockNumber,
+	l.logger.Info("Successfully unpacked event",
+		"event", eventName,
+		"current", eventData.Current.String(),
+		"round_id", eventData.RoundId.String(),
+		"updated_at", eventData.UpdatedAt.String(), // Use the unpacked value
+		"block", vLog.BlockNumber,
 	)
 
 	// If implemented, a service to handle the data, we could call it here
@@ -0,0 +1,268 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testABIPath = "../../config/abi/AggregatorV3Interface.abi.json"
+
+var testAggregator = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+// mockSubscription is an ethereum.Subscription that fails when the test calls fail.
+type mockSubscription struct {
+	errs chan error
+	once sync.Once
+}
+
+func (s *mockSubscription) Unsubscribe() { s.once.Do(func() { close(s.errs) }) }
+
+func (s *mockSubscription) Err() <-chan error { return s.errs }
+
+func (s *mockSubscription) fail(err error) { s.errs <- err }
+
+// mockChainClient serves history from logs up to head and pushes live onto each subscription.
+type mockChainClient struct {
+	mu      sync.Mutex
+	head    uint64
+	logs    []types.Log // Already on chain when the listener starts
+	live    []types.Log // Delivered through the subscription
+	queries []ethereum.FilterQuery
+	subs    []*mockSubscription
+}
+
+func (c *mockChainClient) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+// mine adds vLog to the chain and moves the head to its block.
+func (c *mockChainClient) mine(vLog types.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, vLog)
+	c.head = vLog.BlockNumber
+}
+
+func (c *mockChainClient) subscriptions() []*mockSubscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*mockSubscription(nil), c.subs...)
+}
+
+func (c *mockChainClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return common.LeftPadBytes(testAggregator.Bytes(), 32), nil // The ABI-encoded aggregator() result
+}
+
+func (c *mockChainClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, q)
+	var matched []types.Log
+	for _, vLog := range c.logs {
+		if vLog.BlockNumber >= q.FromBlock.Uint64() && vLog.BlockNumber <= q.ToBlock.Uint64() {
+			matched = append(matched, vLog)
+		}
+	}
+	return matched, nil
+}
+
+func (c *mockChainClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	go func() {
+		for _, vLog := range c.live {
+			select {
+			case ch <- vLog:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	sub := &mockSubscription{errs: make(chan error, 1)}
+	c.mu.Lock()
+	c.subs = append(c.subs, sub)
+	c.mu.Unlock()
+	return sub, nil
+}
+
+func (c *mockChainClient) Close() {}
+
+// newTestListener builds a listener on client that records the block of every handled log.
+func newTestListener(t *testing.T, client *mockChainClient, cursor BlockCursor, startBlock uint64) (*EventListener, func() []uint64) {
+	t.Helper()
+	listener, err := newEventListener(client, "0x00000000000000000000000000000000000000c0", testABIPath, cursor, startBlock, 3, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	require.Equal(t, testAggregator, listener.contractAddrAgg)
+
+	var mu sync.Mutex
+	var handled []uint64
+	listener.handle = func(vLog types.Log) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, vLog.BlockNumber)
+	}
+	return listener, func() []uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]uint64(nil), handled...)
+	}
+}
+
+func eventLog(listener *EventListener, block uint64) types.Log {
+	return types.Log{BlockNumber: block, Topics: []common.Hash{listener.eventSignature, {}, {}}}
+}
+
+func TestEventListener_ReplaysMissedEvents(t *testing.T) {
+	cursor := NewMemoryBlockCursor()
+	require.NoError(t, cursor.Save(context.Background(), 100)) // Processed through block 100 before going down
+
+	client := &mockChainClient{head: 110}
+	listener, handled := newTestListener(t, client, cursor, 0)
+	client.logs = []types.Log{eventLog(listener, 95), eventLog(listener, 101), eventLog(listener, 105)}
+	// The subscription also delivers a log the backfill already covered
+	client.live = []types.Log{eventLog(listener, 105), eventLog(listener, 111)}
+
+	listener.Start(context.Background())
+	defer listener.Stop()
+
+	require.Eventually(t, func() bool { return len(handled()) >= 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []uint64{101, 105, 111}, handled(), "Missed events must be replayed once, in order, before live ones")
+
+	require.NotEmpty(t, client.queries)
+	assert.Equal(t, uint64(101), client.queries[0].FromBlock.Uint64(), "Replay must resume after the stored block")
+	assert.Equal(t, uint64(110), client.queries[len(client.queries)-1].ToBlock.Uint64())
+
+	require.Eventually(t, func() bool {
+		block, ok, err := cursor.Load(context.Background())
+		return err == nil && ok && block == 111
+	}, time.Second, 5*time.Millisecond, "The marker must follow the last handled event")
+}
+
+func TestEventListener_ColdStart(t *testing.T) {
+	tests := []struct {
+		name       string
+		startBlock uint64
+		expected   []uint64
+	}{
+		{name: "FromStartBlock", startBlock: 50, expected: []uint64{60, 3050}},
+		{name: "FromHead", startBlock: 0, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := NewMemoryBlockCursor()
+			client := &mockChainClient{head: 4000}
+			listener, handled := newTestListener(t, client, cursor, tt.startBlock)
+			client.logs = []types.Log{eventLog(listener, 10), eventLog(listener, 60), eventLog(listener, 3050)}
+
+			replayedThrough, err := listener.backfill(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, uint64(4000), replayedThrough)
+			assert.Equal(t, tt.expected, handled())
+
+			block, ok, err := cursor.Load(context.Background())
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, uint64(4000), block)
+		})
+	}
+}
+
+func TestEventListener_ReconnectsAfterSubscriptionFailure(t *testing.T) {
+	client := &mockChainClient{head: 100}
+	listener, handled := newTestListener(t, client, NewMemoryBlockCursor(), 0)
+	listener.reconnectBaseDelay = time.Millisecond
+	listener.reconnectMaxDelay = 5 * time.Millisecond
+
+	// The first redial fails, as it would while the node is still down; the second recovers
+	var dialMu sync.Mutex
+	dials := 0
+	listener.dial = func(ctx context.Context, rpcURL string) (ChainClient, error) {
+		dialMu.Lock()
+		defer dialMu.Unlock()
+		dials++
+		if dials == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return client, nil
+	}
+
+	listener.Start(context.Background())
+	defer listener.Stop()
+
+	require.Eventually(t, func() bool { return listener.State() == StateConnected }, time.Second, time.Millisecond)
+	_, err := listener.CheckRPC(context.Background())
+	require.NoError(t, err)
+
+	// An event is emitted while the connection is down
+	client.mine(eventLog(listener, 105))
+	client.subscriptions()[0].fail(errors.New("websocket: close 1006"))
+
+	require.Eventually(t, func() bool { return len(client.subscriptions()) == 2 }, time.Second, time.Millisecond, "The subscription must be re-established")
+	require.Eventually(t, func() bool { return listener.State() == StateConnected }, time.Second, time.Millisecond)
+	dialMu.Lock()
+	assert.Equal(t, 2, dials)
+	dialMu.Unlock()
+	require.Eventually(t, func() bool { return len(handled()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []uint64{105}, handled(), "The event missed during the outage must be replayed")
+}
+
+func TestEventListener_GivesUpAfterMaxRetries(t *testing.T) {
+	client := &mockChainClient{head: 100}
+	listener, _ := newTestListener(t, client, NewMemoryBlockCursor(), 0)
+	listener.reconnectBaseDelay = time.Millisecond
+	listener.reconnectMaxDelay = time.Millisecond
+
+	var dials atomic.Int32
+	listener.dial = func(ctx context.Context, rpcURL string) (ChainClient, error) {
+		dials.Add(1)
+		return nil, errors.New("connection refused")
+	}
+
+	listener.Start(context.Background())
+	require.Eventually(t, func() bool { return listener.State() == StateConnected }, time.Second, time.Millisecond)
+	client.subscriptions()[0].fail(errors.New("websocket: close 1006"))
+
+	require.Eventually(t, func() bool { return listener.State() == StateFailed }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), dials.Load(), "Every allowed reconnect must be attempted before giving up")
+	_, err := listener.CheckRPC(context.Background())
+	assert.ErrorContains(t, err, "failed")
+
+	listener.Stop() // Must return even though the loop already exited
+}
+
+func TestEventListener_StopCancelsReconnect(t *testing.T) {
+	client := &mockChainClient{head: 100}
+	listener, _ := newTestListener(t, client, NewMemoryBlockCursor(), 0)
+	listener.reconnectBaseDelay = time.Hour // Stop must not wait this out
+	listener.reconnectMaxDelay = time.Hour
+
+	listener.Start(context.Background())
+	require.Eventually(t, func() bool { return listener.State() == StateConnected }, time.Second, time.Millisecond)
+	client.subscriptions()[0].fail(errors.New("websocket: close 1006"))
+	require.Eventually(t, func() bool { return listener.State() == StateReconnecting }, time.Second, time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		listener.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not cancel the reconnect delay")
+	}
+}
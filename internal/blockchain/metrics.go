@@ -0,0 +1,13 @@
+package blockchain
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// blockLagGauge tracks how many blocks the listener trails the chain head by.
+// It is refreshed whenever CheckRPC succeeds and stays unset until the first log arrives.
+var blockLagGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "blockchain_listener_block_lag",
+	Help: "Number of blocks between the chain head and the last block processed by the event listener.",
+})
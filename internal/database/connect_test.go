@@ -0,0 +1,52 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"go-api-template/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPoolConfig_AppliesSizing(t *testing.T) {
+	cfg := config.DBConfig{
+		Host:            "db.example.com",
+		Port:            5433,
+		User:            "app",
+		Password:        "secret",
+		Name:            "app_db",
+		MaxConns:        25,
+		MinConns:        5,
+		MaxConnLifetime: 45 * time.Minute,
+		MaxConnIdleTime: 10 * time.Minute,
+	}
+
+	poolConfig, err := newPoolConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int32(25), poolConfig.MaxConns)
+	assert.Equal(t, int32(5), poolConfig.MinConns)
+	assert.Equal(t, 45*time.Minute, poolConfig.MaxConnLifetime)
+	assert.Equal(t, 10*time.Minute, poolConfig.MaxConnIdleTime)
+
+	assert.Equal(t, "db.example.com", poolConfig.ConnConfig.Host)
+	assert.Equal(t, uint16(5433), poolConfig.ConnConfig.Port)
+	assert.Equal(t, "app", poolConfig.ConnConfig.User)
+	assert.Equal(t, "app_db", poolConfig.ConnConfig.Database)
+}
+
+func TestNewPoolConfig_ZeroKeepsDefaults(t *testing.T) {
+	base := config.DBConfig{Host: "localhost", Port: 5432, User: "postgres", Password: "postgres", Name: "api_db"}
+	defaults, err := newPoolConfig(base)
+	require.NoError(t, err)
+
+	// Only MaxConns is set; everything else must be left alone
+	base.MaxConns = 3
+	poolConfig, err := newPoolConfig(base)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), poolConfig.MaxConns)
+	assert.Equal(t, defaults.MinConns, poolConfig.MinConns)
+	assert.Equal(t, defaults.MaxConnLifetime, poolConfig.MaxConnLifetime)
+	assert.Equal(t, defaults.MaxConnIdleTime, poolConfig.MaxConnIdleTime)
+}
@@ -0,0 +1,2 @@
+ALTER TABLE job_application DROP COLUMN IF EXISTS employer_note;
+ALTER TABLE job_application DROP COLUMN IF EXISTS cover_message;
@@ -0,0 +1,4 @@
+-- Free-text fields attached to a job application
+ALTER TABLE job_application
+ADD COLUMN cover_message TEXT NULL, -- Written by the contractor when applying
+ADD COLUMN employer_note TEXT NULL; -- Private note kept by the employer
@@ -0,0 +1,2 @@
+-- Remove the verified column
+ALTER TABLE users DROP COLUMN IF EXISTS verified;
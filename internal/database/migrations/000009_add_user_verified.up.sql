@@ -0,0 +1,7 @@
+-- Track whether the user has confirmed ownership of their email address
+-- Existing users are backfilled as verified so enabling the login check doesn't lock them out
+ALTER TABLE users
+ADD COLUMN verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE users
+ALTER COLUMN verified SET DEFAULT FALSE;
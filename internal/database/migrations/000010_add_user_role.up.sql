@@ -0,0 +1,5 @@
+CREATE TYPE user_role AS ENUM ('user', 'admin');
+
+-- Every existing and new user starts as a regular user; admins are promoted explicitly
+ALTER TABLE users
+ADD COLUMN role user_role NOT NULL DEFAULT 'user';
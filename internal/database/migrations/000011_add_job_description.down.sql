@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_description_fts;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS description;
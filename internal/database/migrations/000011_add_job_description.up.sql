@@ -0,0 +1,6 @@
+-- Free-text description shown on job postings and used for search
+ALTER TABLE jobs
+ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+-- Expression index backing the full-text search in ListAvailable
+CREATE INDEX IF NOT EXISTS idx_jobs_description_fts ON jobs USING GIN (to_tsvector('english', description));
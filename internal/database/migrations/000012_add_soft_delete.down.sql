@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,6 @@
+-- Soft delete: rows are kept (and stay referenced by invoices/applications) but hidden from reads
+ALTER TABLE jobs
+ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+ALTER TABLE users
+ADD COLUMN deleted_at TIMESTAMPTZ NULL;
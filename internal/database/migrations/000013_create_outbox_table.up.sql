@@ -0,0 +1,14 @@
+-- Transactional outbox: domain events are written in the same transaction as the state change
+-- and published asynchronously by the relay
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY, -- Publish order
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ NULL
+);
+
+-- The relay only ever reads unpublished rows
+CREATE INDEX idx_outbox_unpublished ON outbox(id) WHERE published_at IS NULL;
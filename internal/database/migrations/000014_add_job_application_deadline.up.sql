@@ -0,0 +1,3 @@
+-- Applications to a job are closed once its deadline has passed; NULL means no deadline
+ALTER TABLE jobs
+ADD COLUMN application_deadline TIMESTAMPTZ NULL;
@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every write bumps the version, and updates only apply to the version the client last read
+ALTER TABLE jobs
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS paid_at;
+
+-- Enum values can't be dropped, so rebuild the type without 'Paid'
+UPDATE invoices SET state = 'Complete' WHERE state = 'Paid';
+ALTER TYPE invoice_state RENAME TO invoice_state_old;
+CREATE TYPE invoice_state AS ENUM ('Waiting', 'Complete');
+ALTER TABLE invoices
+    ALTER COLUMN state DROP DEFAULT,
+    ALTER COLUMN state TYPE invoice_state USING state::text::invoice_state,
+    ALTER COLUMN state SET DEFAULT 'Waiting';
+DROP TYPE invoice_state_old;
@@ -0,0 +1,5 @@
+-- Complete means the work was accepted; Paid means the money was received
+ALTER TYPE invoice_state ADD VALUE IF NOT EXISTS 'Paid';
+
+ALTER TABLE invoices
+ADD COLUMN paid_at TIMESTAMPTZ NULL;
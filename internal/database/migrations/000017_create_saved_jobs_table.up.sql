@@ -0,0 +1,10 @@
+CREATE TABLE saved_jobs (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, job_id)
+);
+
+-- Index foreign key (user_id is covered by the primary key)
+CREATE INDEX idx_saved_jobs_job_id ON saved_jobs(job_id);
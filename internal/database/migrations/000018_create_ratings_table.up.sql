@@ -0,0 +1,14 @@
+CREATE TABLE ratings (
+    id UUID PRIMARY KEY,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    rater_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ratee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 5),
+    comment TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_job_rating UNIQUE (job_id, rater_id)
+);
+
+-- Index foreign key; averages are computed per ratee
+CREATE INDEX idx_ratings_ratee_id ON ratings(ratee_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS job_skills;
+DROP TABLE IF EXISTS skills;
@@ -0,0 +1,17 @@
+CREATE TABLE skills (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL, -- Stored lowercased and trimmed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_skill_name UNIQUE (name)
+);
+
+CREATE TABLE job_skills (
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (job_id, skill_id)
+);
+
+-- Index foreign key (job_id is covered by the primary key); used when filtering jobs by skill
+CREATE INDEX idx_job_skills_skill_id ON job_skills(skill_id);
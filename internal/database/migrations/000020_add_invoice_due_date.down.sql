@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_invoices_complete_due_date;
+
+ALTER TABLE invoices
+DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,11 @@
+-- Net-N payment terms: a Complete invoice still unpaid after due_date is overdue
+ALTER TABLE invoices
+ADD COLUMN due_date TIMESTAMPTZ NULL;
+
+-- Existing invoices get the default 30 day term
+UPDATE invoices SET due_date = created_at + INTERVAL '30 days';
+
+ALTER TABLE invoices
+ALTER COLUMN due_date SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_invoices_complete_due_date ON invoices (due_date) WHERE state = 'Complete';
@@ -0,0 +1 @@
+ALTER TABLE job_application DROP COLUMN IF EXISTS proposed_rate;
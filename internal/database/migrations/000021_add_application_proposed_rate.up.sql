@@ -0,0 +1,3 @@
+-- Counter-offer on the job's rate; NULL means the contractor accepts the posted rate
+ALTER TABLE job_application
+ADD COLUMN proposed_rate NUMERIC(10, 2) NULL CHECK (proposed_rate > 0);
@@ -0,0 +1,10 @@
+-- Keys for machine clients (e.g. the billing system). Only the SHA-256 of a key is stored.
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    key_hash TEXT NOT NULL UNIQUE,
+    owner TEXT NOT NULL, -- The client the key was issued to
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NULL REFERENCES users(id) ON DELETE SET NULL, -- The admin who minted it
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ NULL -- Set when revoked; revoked keys are kept for auditing
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS unique_active_application;
+
+-- Keep only the latest application per contractor and job so the old constraint can be restored
+DELETE FROM job_application a
+USING job_application b
+WHERE a.job_id = b.job_id
+  AND a.contractor_id = b.contractor_id
+  AND (a.created_at, a.id) < (b.created_at, b.id);
+
+ALTER TABLE job_application ADD CONSTRAINT unique_application UNIQUE (job_id, contractor_id);
@@ -0,0 +1,6 @@
+-- Only one active (Waiting or Accepted) application per contractor and job; withdrawn and
+-- rejected applications are kept as history and don't block re-applying
+ALTER TABLE job_application DROP CONSTRAINT IF EXISTS unique_application;
+
+CREATE UNIQUE INDEX unique_active_application ON job_application(job_id, contractor_id)
+WHERE state IN ('Waiting', 'Accepted');
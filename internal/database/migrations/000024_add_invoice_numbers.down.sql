@@ -0,0 +1,4 @@
+ALTER TABLE invoices
+DROP COLUMN IF EXISTS invoice_number;
+
+DROP TABLE IF EXISTS invoice_number_sequences;
@@ -0,0 +1,29 @@
+-- Per-employer invoice counter. The row is locked by the increment until the creating transaction
+-- ends, so numbers are handed out in order and a rolled back invoice gives its number back.
+CREATE TABLE invoice_number_sequences (
+    employer_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_value BIGINT NOT NULL
+);
+
+ALTER TABLE invoices
+ADD COLUMN invoice_number TEXT NULL;
+
+-- Number existing invoices per employer in creation order
+WITH numbered AS (
+    SELECT i.id, i.created_at, ROW_NUMBER() OVER (PARTITION BY j.employer_id ORDER BY i.created_at, i.id) AS seq
+    FROM invoices i
+    JOIN jobs j ON j.id = i.job_id
+)
+UPDATE invoices
+SET invoice_number = 'INV-' || LPAD(numbered.seq::TEXT, 6, '0') || '-' || EXTRACT(YEAR FROM numbered.created_at AT TIME ZONE 'UTC')::TEXT
+FROM numbered
+WHERE invoices.id = numbered.id;
+
+INSERT INTO invoice_number_sequences (employer_id, last_value)
+SELECT j.employer_id, COUNT(*)
+FROM invoices i
+JOIN jobs j ON j.id = i.job_id
+GROUP BY j.employer_id;
+
+ALTER TABLE invoices
+ALTER COLUMN invoice_number SET NOT NULL;
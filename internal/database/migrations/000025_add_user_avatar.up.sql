@@ -0,0 +1,3 @@
+-- URL of the user's profile picture; NULL means no avatar
+ALTER TABLE users
+ADD COLUMN avatar_url TEXT NULL;
@@ -0,0 +1,12 @@
+-- Who changed what, for changes that need to be traceable later (e.g. a job moving to another employer).
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id UUID NULL REFERENCES users(id) ON DELETE SET NULL, -- The user who made the change
+    action TEXT NOT NULL, -- e.g. 'job.ownership_transferred'
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at);
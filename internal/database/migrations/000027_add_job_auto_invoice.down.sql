@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_jobs_next_auto_invoice_at;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS next_auto_invoice_at,
+    DROP COLUMN IF EXISTS auto_invoice_cadence,
+    DROP COLUMN IF EXISTS auto_invoice;
+
+DROP TYPE IF EXISTS invoice_cadence;
@@ -0,0 +1,9 @@
+CREATE TYPE invoice_cadence AS ENUM ('daily', 'weekly', 'biweekly', 'monthly');
+
+-- Opt-in periodic invoicing: the scheduler creates the next interval invoice once next_auto_invoice_at passes.
+ALTER TABLE jobs
+    ADD COLUMN auto_invoice BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN auto_invoice_cadence invoice_cadence NOT NULL DEFAULT 'weekly',
+    ADD COLUMN next_auto_invoice_at TIMESTAMPTZ NULL; -- NULL once every interval has been invoiced
+
+CREATE INDEX idx_jobs_next_auto_invoice_at ON jobs (next_auto_invoice_at) WHERE auto_invoice AND deleted_at IS NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS created_by;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS created_by;
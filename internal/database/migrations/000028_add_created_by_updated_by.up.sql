@@ -0,0 +1,10 @@
+-- Who created a job or invoice and who changed it last. No foreign key: changes the API makes on its own
+-- (e.g. scheduled invoicing) record the well-known system actor, which isn't a user. NULL for rows from
+-- before this migration.
+ALTER TABLE jobs
+    ADD COLUMN created_by UUID NULL,
+    ADD COLUMN updated_by UUID NULL;
+
+ALTER TABLE invoices
+    ADD COLUMN created_by UUID NULL,
+    ADD COLUMN updated_by UUID NULL;
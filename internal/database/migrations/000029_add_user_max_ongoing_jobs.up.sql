@@ -0,0 +1,4 @@
+-- Per-user override of how many Ongoing jobs a contractor may hold at once, e.g. for premium accounts.
+-- NULL means the configured default applies.
+ALTER TABLE users
+    ADD COLUMN max_ongoing_jobs INTEGER NULL CHECK (max_ongoing_jobs >= 0);
@@ -0,0 +1,34 @@
+-- HTTP callbacks external integrations register to be told about domain events.
+CREATE TABLE webhooks (
+    id UUID PRIMARY KEY,
+    owner TEXT NOT NULL, -- The integration the subscription belongs to
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL, -- Signs each delivery; kept in plain text since it is needed to sign
+    event_types TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NULL REFERENCES users(id) ON DELETE SET NULL, -- The admin who registered it
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per event and subscription, tracking the attempts to deliver it
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL, -- The outbox event delivered
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL, -- The body POSTed, fixed when the delivery is queued
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INT NULL, -- HTTP status of the last attempt; NULL if no response came back
+    last_error TEXT NULL,
+    delivered_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (webhook_id, event_id) -- The outbox relay may hand over an event twice
+);
+
+-- The worker only ever reads pending rows that are due
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
@@ -0,0 +1,12 @@
+-- Enum values can't be dropped, so the type is rebuilt without 'Cancelled'.
+-- Cancelled jobs are kept as Archived, the other state no longer in use.
+UPDATE jobs SET state = 'Archived' WHERE state = 'Cancelled';
+
+ALTER TYPE job_state RENAME TO job_state_old;
+CREATE TYPE job_state AS ENUM ('Waiting', 'Ongoing', 'Complete', 'Archived');
+
+ALTER TABLE jobs ALTER COLUMN state DROP DEFAULT;
+ALTER TABLE jobs ALTER COLUMN state TYPE job_state USING state::text::job_state;
+ALTER TABLE jobs ALTER COLUMN state SET DEFAULT 'Waiting';
+
+DROP TYPE job_state_old;
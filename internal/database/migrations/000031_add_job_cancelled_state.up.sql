@@ -0,0 +1,2 @@
+-- An employer can cancel an Ongoing job; Cancelled is terminal
+ALTER TYPE job_state ADD VALUE IF NOT EXISTS 'Cancelled';
@@ -0,0 +1,3 @@
+-- The admin acting as actor_id through an impersonation token, for changes made while impersonating.
+ALTER TABLE audit_log
+    ADD COLUMN impersonator_id UUID NULL REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+-- Enum values can't be dropped, so the type is rebuilt without 'Invited' and 'Declined'.
+-- Accepted invitations are ordinary Accepted applications; open and declined ones are dropped.
+DELETE FROM job_application WHERE state IN ('Invited', 'Declined');
+
+DROP INDEX IF EXISTS unique_active_application;
+
+ALTER TYPE job_application_state RENAME TO job_application_state_old;
+CREATE TYPE job_application_state AS ENUM ('Waiting', 'Accepted', 'Rejected', 'Withdrawn');
+
+ALTER TABLE job_application ALTER COLUMN state DROP DEFAULT;
+ALTER TABLE job_application ALTER COLUMN state TYPE job_application_state USING state::text::job_application_state;
+ALTER TABLE job_application ALTER COLUMN state SET DEFAULT 'Waiting';
+
+DROP TYPE job_application_state_old;
+
+CREATE UNIQUE INDEX unique_active_application ON job_application(job_id, contractor_id)
+WHERE state IN ('Waiting', 'Accepted');
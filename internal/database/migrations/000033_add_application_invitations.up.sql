@@ -0,0 +1,4 @@
+-- An employer can invite a contractor to a job; the invitation is an application that starts Invited
+-- and ends Accepted or Declined by the contractor
+ALTER TYPE job_application_state ADD VALUE IF NOT EXISTS 'Invited';
+ALTER TYPE job_application_state ADD VALUE IF NOT EXISTS 'Declined';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS unique_active_application;
+
+CREATE UNIQUE INDEX unique_active_application ON job_application(job_id, contractor_id)
+WHERE state IN ('Waiting', 'Accepted');
@@ -0,0 +1,7 @@
+-- An open invitation counts as an active application: a contractor can't be invited twice to the
+-- same job, nor invited while they have an application Waiting. Kept apart from 000033 because a
+-- new enum value can't be used in the transaction that adds it.
+DROP INDEX IF EXISTS unique_active_application;
+
+CREATE UNIQUE INDEX unique_active_application ON job_application(job_id, contractor_id)
+WHERE state IN ('Waiting', 'Invited', 'Accepted');
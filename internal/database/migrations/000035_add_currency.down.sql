@@ -0,0 +1,2 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS currency;
+ALTER TABLE jobs DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,9 @@
+-- Rates and invoice values are amounts in an ISO 4217 currency. Existing rows are backfilled with USD, the
+-- built-in default (models.DefaultCurrency), whatever CURRENCY_DEFAULT is set to: migrations can't read the
+-- app config. Deployments that configure another default and already hold data should update those rows
+-- themselves. The column default only serves the backfill and is dropped, so new rows always get the
+-- currency from the application.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE jobs ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE invoices ALTER COLUMN currency DROP DEFAULT;
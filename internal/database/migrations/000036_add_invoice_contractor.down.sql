@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_invoices_contractor_id;
+
+ALTER TABLE invoices
+DROP COLUMN IF EXISTS contractor_id;
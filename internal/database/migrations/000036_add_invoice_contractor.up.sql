@@ -0,0 +1,35 @@
+-- The contractor who issued the invoice. Access to an invoice and a contractor's unpaid totals follow it
+-- rather than the job's current contractor, which changes on reassignment and is cleared on cancellation.
+ALTER TABLE invoices
+ADD COLUMN contractor_id UUID NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_invoices_contractor_id ON invoices(contractor_id);
+
+-- Backfill from the job's contractor, or from the cancellation that unassigned them
+UPDATE invoices i
+SET contractor_id = COALESCE(j.contractor_id, (
+    SELECT (a.details->>'contractor_id')::UUID
+    FROM audit_log a
+    WHERE a.entity_type = 'job' AND a.entity_id = i.job_id AND a.action = 'job.cancelled'
+    ORDER BY a.created_at DESC
+    LIMIT 1
+))
+FROM jobs j
+WHERE j.id = i.job_id;
+
+-- Intervals invoiced before a reassignment belong to the contractor the job was taken from
+UPDATE invoices i
+SET contractor_id = (
+    SELECT (a.details->>'from_contractor_id')::UUID
+    FROM audit_log a
+    WHERE a.entity_type = 'job' AND a.entity_id = i.job_id AND a.action = 'job.contractor_reassigned'
+      AND (a.details->>'last_invoiced_interval')::INTEGER >= i.interval_number
+    ORDER BY a.created_at ASC
+    LIMIT 1
+)
+WHERE EXISTS (
+    SELECT 1
+    FROM audit_log a
+    WHERE a.entity_type = 'job' AND a.entity_id = i.job_id AND a.action = 'job.contractor_reassigned'
+      AND (a.details->>'last_invoiced_interval')::INTEGER >= i.interval_number
+);
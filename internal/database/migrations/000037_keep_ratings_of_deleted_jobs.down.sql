@@ -0,0 +1,11 @@
+-- Ratings whose job is gone can't be kept under the NOT NULL cascade constraint
+DELETE FROM ratings WHERE job_id IS NULL;
+
+ALTER TABLE ratings
+DROP CONSTRAINT IF EXISTS ratings_job_id_fkey;
+
+ALTER TABLE ratings
+ADD CONSTRAINT ratings_job_id_fkey FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE ratings
+ALTER COLUMN job_id SET NOT NULL;
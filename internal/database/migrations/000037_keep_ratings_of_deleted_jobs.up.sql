@@ -0,0 +1,10 @@
+-- A rating outlives its job, so purging archived jobs doesn't change a contractor's average or rating
+-- count. job_id is cleared when the job is hard-deleted.
+ALTER TABLE ratings
+ALTER COLUMN job_id DROP NOT NULL;
+
+ALTER TABLE ratings
+DROP CONSTRAINT IF EXISTS ratings_job_id_fkey;
+
+ALTER TABLE ratings
+ADD CONSTRAINT ratings_job_id_fkey FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE SET NULL;
@@ -6,8 +6,10 @@ import (
 	"log"
 
 	"go-api-template/config"
+	"go-api-template/pkg/tracing"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
 )
 
 // NewRedisClient creates and returns a new Redis client based on the provided configuration.
@@ -18,6 +20,8 @@ func NewRedisClient(cfg config.RedisConfig) (*redis.Client, error) {
 		DB:       cfg.DB,       
 	})
 
+	rdb.AddHook(tracing.NewRedisHook(otel.GetTracerProvider())) // No-op unless tracing is configured
+
 	// Ping the Redis server to ensure connectivity
 	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", cfg.Addr, err)
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SeedAdmin promotes the user registered with the given email to the admin role.
+// It is a no-op (with a warning) if no such user exists yet, so the account must be registered first.
+func SeedAdmin(ctx context.Context, pool *pgxpool.Pool, email string) error {
+	cmdTag, err := pool.Exec(ctx, `UPDATE users SET role = 'admin' WHERE email = $1 AND role <> 'admin' AND deleted_at IS NULL;`, email)
+	if err != nil {
+		return fmt.Errorf("failed to promote %s to admin: %w", email, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		log.Printf("Admin seed: no non-admin user found with email %s, nothing to promote", email)
+		return nil
+	}
+	log.Printf("Admin seed: promoted %s to admin", email)
+	return nil
+}
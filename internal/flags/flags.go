@@ -0,0 +1,64 @@
+// Package flags decides whether gradually rolled out features are on.
+package flags
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go-api-template/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisFlagPrefix prefixes the Redis keys that override a flag at runtime, e.g. "flag:ratings" set to
+// "true" or "false". Deleting the key restores the configured state.
+const RedisFlagPrefix = "flag:"
+
+// Feature flags.
+const (
+	Ratings = "ratings" // Submitting and reading user ratings
+)
+
+// Provider reports whether a feature flag is on. Unknown flags are off.
+type Provider interface {
+	Enabled(ctx context.Context, name string) bool
+}
+
+// Flags is a Provider reading flags from configuration, optionally overridden at runtime through Redis.
+type Flags struct {
+	defaults    map[string]bool
+	redisClient *redis.Client // nil to use the configured states only
+}
+
+// New creates a Flags with the configured flag states. If redisClient is non-nil, a flag:<name> key
+// holding a boolean overrides the configured state, so a feature can be toggled on every instance
+// without a restart.
+func New(defaults map[string]bool, redisClient *redis.Client) *Flags {
+	copied := make(map[string]bool, len(defaults))
+	for name, enabled := range defaults {
+		copied[name] = enabled
+	}
+	return &Flags{defaults: copied, redisClient: redisClient}
+}
+
+// Compile-time check to ensure Flags implements Provider
+var _ Provider = (*Flags)(nil)
+
+// Enabled reports whether the flag is on. If Redis can't be read or holds something that isn't a
+// boolean, the configured state is used, so an outage doesn't flip features.
+func (f *Flags) Enabled(ctx context.Context, name string) bool {
+	if f.redisClient != nil {
+		value, err := f.redisClient.Get(ctx, RedisFlagPrefix+name).Result()
+		switch {
+		case err == nil:
+			if enabled, parseErr := strconv.ParseBool(value); parseErr == nil {
+				return enabled
+			}
+			logger.FromContext(ctx).Warn("Ignoring feature flag override that isn't a boolean", "flag", name, "value", value)
+		case !errors.Is(err, redis.Nil):
+			logger.FromContext(ctx).Warn("Failed to read feature flag override, using configured state", "flag", name, "error", err)
+		}
+	}
+	return f.defaults[name]
+}
@@ -0,0 +1,44 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags_ConfiguredStates(t *testing.T) {
+	defaults := map[string]bool{Ratings: true, "beta": false}
+	f := New(defaults, nil)
+
+	assert.True(t, f.Enabled(context.Background(), Ratings))
+	assert.False(t, f.Enabled(context.Background(), "beta"))
+	assert.False(t, f.Enabled(context.Background(), "unknown"), "unknown flags are off")
+
+	defaults[Ratings] = false
+	assert.True(t, f.Enabled(context.Background(), Ratings), "New copies the configured states")
+}
+
+func TestFlags_RedisOverrides(t *testing.T) {
+	redisClient, mock := redismock.NewClientMock()
+	f := New(map[string]bool{Ratings: true, "beta": false}, redisClient)
+
+	mock.ExpectGet(RedisFlagPrefix + Ratings).SetVal("false")
+	assert.False(t, f.Enabled(context.Background(), Ratings), "Redis can turn a flag off")
+
+	mock.ExpectGet(RedisFlagPrefix + "beta").SetVal("true")
+	assert.True(t, f.Enabled(context.Background(), "beta"), "Redis can turn a flag on")
+
+	mock.ExpectGet(RedisFlagPrefix + "beta").RedisNil()
+	assert.False(t, f.Enabled(context.Background(), "beta"), "without an override the configured state applies")
+
+	mock.ExpectGet(RedisFlagPrefix + Ratings).SetVal("maybe")
+	assert.True(t, f.Enabled(context.Background(), Ratings), "a malformed override is ignored")
+
+	mock.ExpectGet(RedisFlagPrefix + Ratings).SetErr(errors.New("connection refused"))
+	assert.True(t, f.Enabled(context.Background(), Ratings), "an outage falls back to the configured state")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
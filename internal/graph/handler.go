@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/api/middleware"
+	"go-api-template/internal/graph/generated"
+	"go-api-template/pkg/logger"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// maxQueryComplexity bounds how many fields one query may resolve, so nesting lists can't turn a
+// single request into thousands of service calls.
+const maxQueryComplexity = 500
+
+// NewHandler serves GraphQL queries over GET and POST. It must run after the JWT auth middleware,
+// whose user every resolver acts as.
+func NewHandler(resolver *Resolver) gin.HandlerFunc {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(maxQueryComplexity))
+	srv.SetErrorPresenter(presentError)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+			ctx = withRequester(ctx, userID)
+		}
+		srv.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	}
+}
+
+// presentError reports a resolver's error with the code and message the REST API would use for
+// it, in extensions.code and extensions.details. gqlgen hands resolver errors over wrapped in a
+// gqlerror.Error with the field's path; errors it raised itself, such as a malformed query, wrap
+// nothing and are left as they are.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+
+	described, mapped := handlers.DescribeError(gqlErr.Err)
+	if !mapped {
+		logger.FromContext(ctx).Error("GraphQL: Unhandled error", "path", gqlErr.Path.String(), "error", err)
+	}
+	gqlErr.Message = described.Message
+	gqlErr.Extensions = map[string]any{"code": described.Code}
+	if described.Details != nil {
+		gqlErr.Extensions["details"] = described.Details
+	}
+	return gqlErr
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/graph/model"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+)
+
+// Shared by the top-level list queries and the fields of the same name on Job.
+
+// listInvoices returns a page of a job's invoices, if the requester is its employer or contractor.
+func (r *Resolver) listInvoices(ctx context.Context, jobID uuid.UUID, limit, offset int) (*model.InvoicePage, error) {
+	userID, err := requesterFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := &dto.ListInvoicesByJobRequest{JobID: jobID, UserId: userID}
+	req.Limit, req.Offset = dto.ClampPagination(limit, offset, r.maxPageSize)
+
+	invoices, total, err := r.invoices.ListInvoicesByJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page := &model.InvoicePage{Items: make([]dto.InvoiceResponse, 0, len(invoices)), Total: total}
+	for i := range invoices {
+		page.Items = append(page.Items, handlers.MapInvoiceModelToInvoiceResponse(&invoices[i]))
+	}
+	return page, nil
+}
+
+// listApplications returns a page of the applications to a job, if the requester is its employer.
+func (r *Resolver) listApplications(ctx context.Context, jobID uuid.UUID, limit, offset int) (*model.JobApplicationPage, error) {
+	userID, err := requesterFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := &dto.ListJobApplicationsByJobRequest{JobID: jobID, UserID: userID}
+	req.Limit, req.Offset = dto.ClampPagination(limit, offset, r.maxPageSize)
+
+	applications, total, err := r.applications.ListApplicationsByJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page := &model.JobApplicationPage{Items: make([]dto.JobApplicationResponse, 0, len(applications)), Total: total}
+	for i := range applications {
+		page.Items = append(page.Items, handlers.MapJobApplicationModelToResponse(&applications[i]))
+	}
+	return page, nil
+}
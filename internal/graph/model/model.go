@@ -0,0 +1,64 @@
+// Package model holds the GraphQL types that have no REST DTO to bind to. It is separate from
+// package graph because the generated executor imports both.
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"go-api-template/internal/transport/dto"
+)
+
+// JobScope selects which jobs the jobs query lists.
+type JobScope string
+
+const (
+	JobScopeAvailable  JobScope = "AVAILABLE"
+	JobScopeEmployer   JobScope = "EMPLOYER"
+	JobScopeContractor JobScope = "CONTRACTOR"
+)
+
+func (s JobScope) IsValid() bool {
+	switch s {
+	case JobScopeAvailable, JobScopeEmployer, JobScopeContractor:
+		return true
+	}
+	return false
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler.
+func (s *JobScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("JobScope must be a string")
+	}
+	*s = JobScope(str)
+	if !s.IsValid() {
+		return fmt.Errorf("%s is not a valid JobScope", str)
+	}
+	return nil
+}
+
+// MarshalGQL implements graphql.Marshaler.
+func (s JobScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(s)))
+}
+
+// JobPage is a page of jobs and the total number matching the query.
+type JobPage struct {
+	Items []dto.JobResponse
+	Total int
+}
+
+// InvoicePage is a page of invoices and the total number matching the query.
+type InvoicePage struct {
+	Items []dto.InvoiceResponse
+	Total int
+}
+
+// JobApplicationPage is a page of applications and the total number matching the query.
+type JobApplicationPage struct {
+	Items []dto.JobApplicationResponse
+	Total int
+}
@@ -0,0 +1,64 @@
+// Package graph serves a read-only GraphQL API over the existing services, so a client can fetch a
+// job together with its applications and invoices in one request. The executor in package generated
+// is produced by gqlgen from schema.graphqls and gqlgen.yml.
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate --config gqlgen.yml
+
+import (
+	"context"
+	"errors"
+
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+)
+
+// errNoRequester is returned when a resolver runs without an authenticated user, which the auth
+// middleware in front of /graphql rules out.
+var errNoRequester = errors.New("no authenticated user in context")
+
+// Resolver is the root resolver. It delegates to the services, which check what the requester may see.
+type Resolver struct {
+	jobs         services.JobService
+	invoices     services.InvoiceService
+	applications services.JobApplicationService
+	users        services.UserService
+	maxPageSize  int // Largest limit a list field may use; larger ones are clamped
+}
+
+// NewResolver creates a new Resolver with the given services
+func NewResolver(jobService services.JobService, invoiceService services.InvoiceService, jobAppService services.JobApplicationService, userService services.UserService) *Resolver {
+	return &Resolver{
+		jobs:         jobService,
+		invoices:     invoiceService,
+		applications: jobAppService,
+		users:        userService,
+		maxPageSize:  dto.DefaultMaxPageLimit,
+	}
+}
+
+// WithMaxPageSize sets the largest limit list fields accept; larger ones are clamped. Values below 1 keep the default.
+func (r *Resolver) WithMaxPageSize(n int) *Resolver {
+	if n > 0 {
+		r.maxPageSize = n
+	}
+	return r
+}
+
+type requesterKey struct{}
+
+// withRequester stores the ID of the authenticated user for the resolvers.
+func withRequester(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, requesterKey{}, userID)
+}
+
+// requesterFrom returns the ID stored by withRequester.
+func requesterFrom(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := ctx.Value(requesterKey{}).(uuid.UUID)
+	if !ok {
+		return uuid.Nil, errNoRequester
+	}
+	return userID, nil
+}
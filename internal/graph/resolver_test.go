@@ -0,0 +1,184 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubJobService implements only GetJobByID, knowing a single job.
+type stubJobService struct {
+	services.JobService
+	job *models.Job
+}
+
+func (s *stubJobService) GetJobByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error) {
+	if s.job == nil || s.job.ID != req.ID {
+		return nil, services.ErrNotFound
+	}
+	return s.job, nil
+}
+
+// stubInvoiceService implements only ListInvoicesByJob, with the service's rule that only the
+// employer and the contractor may list.
+type stubInvoiceService struct {
+	services.InvoiceService
+	job      *models.Job
+	invoices []models.Invoice
+}
+
+func (s *stubInvoiceService) ListInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, int, error) {
+	if req.UserId != s.job.EmployerID && (s.job.ContractorID == nil || req.UserId != *s.job.ContractorID) {
+		return nil, 0, services.ErrForbidden
+	}
+	return s.invoices, len(s.invoices), nil
+}
+
+// stubApplicationService implements only ListApplicationsByJob, with the service's rule that only
+// the employer may list.
+type stubApplicationService struct {
+	services.JobApplicationService
+	job          *models.Job
+	applications []models.JobApplication
+}
+
+func (s *stubApplicationService) ListApplicationsByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) ([]models.JobApplication, int, error) {
+	if req.UserID != s.job.EmployerID {
+		return nil, 0, services.ErrForbidden
+	}
+	return s.applications, len(s.applications), nil
+}
+
+const jobWithRelationsQuery = `query($id: UUID!) {
+	job(id: $id) {
+		id
+		state
+		applications { total items { id contractorId state createdAt } }
+		invoices { total items { id intervalNumber value } }
+	}
+}`
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// performQuery posts query to the GraphQL handler as requesterID.
+func performQuery(t *testing.T, resolver *Resolver, requesterID uuid.UUID, query string, variables map[string]any) graphQLResponse {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", requesterID) // What JWTAuthMiddleware stores
+		c.Next()
+	}
+	router.POST("/graphql", authenticate, NewHandler(resolver))
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp graphQLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return resp
+}
+
+func TestResolver_JobWithRelations(t *testing.T) {
+	employerID, contractorID := uuid.New(), uuid.New()
+	job := &models.Job{ID: uuid.New(), EmployerID: employerID, ContractorID: &contractorID, State: models.JobStateOngoing, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	invoices := []models.Invoice{
+		{ID: uuid.New(), JobID: job.ID, IntervalNumber: 1, Value: 400},
+		{ID: uuid.New(), JobID: job.ID, IntervalNumber: 2, Value: 400},
+	}
+	appliedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	applications := []models.JobApplication{{ID: uuid.New(), JobID: job.ID, ContractorID: contractorID, State: models.JobApplicationAccepted, CreatedAt: appliedAt, UpdatedAt: appliedAt}}
+	resolver := NewResolver(
+		&stubJobService{job: job},
+		&stubInvoiceService{job: job, invoices: invoices},
+		&stubApplicationService{job: job, applications: applications},
+		nil,
+	)
+
+	type jobData struct {
+		Job *struct {
+			ID           uuid.UUID `json:"id"`
+			State        string    `json:"state"`
+			Applications *struct {
+				Total int `json:"total"`
+				Items []struct {
+					ContractorID uuid.UUID `json:"contractorId"`
+					CreatedAt    time.Time `json:"createdAt"`
+				} `json:"items"`
+			} `json:"applications"`
+			Invoices *struct {
+				Total int `json:"total"`
+				Items []struct {
+					IntervalNumber int `json:"intervalNumber"`
+				} `json:"items"`
+			} `json:"invoices"`
+		} `json:"job"`
+	}
+
+	t.Run("Employer", func(t *testing.T) {
+		resp := performQuery(t, resolver, employerID, jobWithRelationsQuery, map[string]any{"id": job.ID})
+
+		require.Empty(t, resp.Errors)
+		var data jobData
+		require.NoError(t, json.Unmarshal(resp.Data, &data))
+		require.NotNil(t, data.Job)
+		assert.Equal(t, job.ID, data.Job.ID)
+		assert.Equal(t, string(models.JobStateOngoing), data.Job.State)
+		require.NotNil(t, data.Job.Applications)
+		assert.Equal(t, 1, data.Job.Applications.Total)
+		assert.Equal(t, contractorID, data.Job.Applications.Items[0].ContractorID)
+		assert.True(t, appliedAt.Equal(data.Job.Applications.Items[0].CreatedAt))
+		require.NotNil(t, data.Job.Invoices)
+		assert.Equal(t, 2, data.Job.Invoices.Total)
+		assert.Equal(t, 2, data.Job.Invoices.Items[1].IntervalNumber)
+	})
+
+	t.Run("ContractorCantSeeApplications", func(t *testing.T) {
+		resp := performQuery(t, resolver, contractorID, jobWithRelationsQuery, map[string]any{"id": job.ID})
+
+		var data jobData
+		require.NoError(t, json.Unmarshal(resp.Data, &data))
+		require.NotNil(t, data.Job, "The rest of the job is still returned")
+		assert.Nil(t, data.Job.Applications)
+		require.NotNil(t, data.Job.Invoices)
+		assert.Equal(t, 2, data.Job.Invoices.Total)
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, []any{"job", "applications"}, resp.Errors[0].Path)
+		assert.Equal(t, dto.ErrorCodeForbidden, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		resp := performQuery(t, resolver, employerID, jobWithRelationsQuery, map[string]any{"id": uuid.New()})
+
+		assert.JSONEq(t, "null", string(resp.Data))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, dto.ErrorCodeNotFound, resp.Errors[0].Extensions["code"])
+		assert.Equal(t, "Resource not found", resp.Errors[0].Message)
+	})
+}
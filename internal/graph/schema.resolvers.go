@@ -0,0 +1,115 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.75
+
+import (
+	"context"
+	"fmt"
+	"go-api-template/internal/api/handlers"
+	"go-api-template/internal/graph/generated"
+	"go-api-template/internal/graph/model"
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+)
+
+// Applications is the resolver for the applications field.
+func (r *jobResolver) Applications(ctx context.Context, obj *dto.JobResponse, limit int, offset int) (*model.JobApplicationPage, error) {
+	return r.listApplications(ctx, obj.ID, limit, offset)
+}
+
+// Invoices is the resolver for the invoices field.
+func (r *jobResolver) Invoices(ctx context.Context, obj *dto.JobResponse, limit int, offset int) (*model.InvoicePage, error) {
+	return r.listInvoices(ctx, obj.ID, limit, offset)
+}
+
+// State is the resolver for the state field.
+func (r *jobApplicationResolver) State(ctx context.Context, obj *dto.JobApplicationResponse) (string, error) {
+	return string(obj.State), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*dto.UserResponse, error) {
+	userID, err := requesterFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.users.GetByID(ctx, &dto.GetUserByIdRequest{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+	resp := handlers.MapUserModelToUserResponse(user)
+	return &resp, nil
+}
+
+// Job is the resolver for the job field.
+func (r *queryResolver) Job(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	if _, err := requesterFrom(ctx); err != nil {
+		return nil, err
+	}
+	job, err := r.jobs.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	resp := handlers.MapJobModelToJobResponse(job)
+	return &resp, nil
+}
+
+// Jobs is the resolver for the jobs field.
+func (r *queryResolver) Jobs(ctx context.Context, scope model.JobScope, limit int, offset int) (*model.JobPage, error) {
+	userID, err := requesterFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset = dto.ClampPagination(limit, offset, r.maxPageSize)
+
+	var jobs []models.Job
+	var total int
+	switch scope {
+	case model.JobScopeAvailable:
+		jobs, total, err = r.jobs.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: limit, Offset: offset})
+	case model.JobScopeEmployer:
+		jobs, total, err = r.jobs.ListJobsByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: userID, Limit: limit, Offset: offset})
+	case model.JobScopeContractor:
+		jobs, total, err = r.jobs.ListJobsByContractor(ctx, &dto.ListJobsByContractorRequest{ContractorID: userID, Limit: limit, Offset: offset})
+	default:
+		return nil, fmt.Errorf("unknown job scope %q", scope) // Rejected by the schema before it gets here
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.JobPage{Items: make([]dto.JobResponse, 0, len(jobs)), Total: total}
+	for i := range jobs {
+		page.Items = append(page.Items, handlers.MapJobModelToJobResponse(&jobs[i]))
+	}
+	return page, nil
+}
+
+// InvoicesByJob is the resolver for the invoicesByJob field.
+func (r *queryResolver) InvoicesByJob(ctx context.Context, jobID uuid.UUID, limit int, offset int) (*model.InvoicePage, error) {
+	return r.listInvoices(ctx, jobID, limit, offset)
+}
+
+// ApplicationsByJob is the resolver for the applicationsByJob field.
+func (r *queryResolver) ApplicationsByJob(ctx context.Context, jobID uuid.UUID, limit int, offset int) (*model.JobApplicationPage, error) {
+	return r.listApplications(ctx, jobID, limit, offset)
+}
+
+// Job returns generated.JobResolver implementation.
+func (r *Resolver) Job() generated.JobResolver { return &jobResolver{r} }
+
+// JobApplication returns generated.JobApplicationResolver implementation.
+func (r *Resolver) JobApplication() generated.JobApplicationResolver {
+	return &jobApplicationResolver{r}
+}
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type jobResolver struct{ *Resolver }
+type jobApplicationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
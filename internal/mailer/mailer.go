@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// Mailer defines the interface for sending transactional emails. template names one of the embedded
+// templates (the Template* constants) and data is what it is rendered with.
+type Mailer interface {
+	Send(ctx context.Context, to, template string, data any) error
+}
+
+// New returns the Mailer selected by the SMTP settings: an SMTPMailer when a host is configured,
+// otherwise a LogMailer, so development setups work without a mail server.
+func New(cfg SMTPConfig) Mailer {
+	if cfg.Host == "" {
+		return NewLogMailer()
+	}
+	return NewSMTPMailer(cfg)
+}
+
+// LogMailer is a Mailer that writes messages to the application log instead of delivering them.
+// Useful for local development until a real email provider is wired in.
+type LogMailer struct{}
+
+// NewLogMailer creates a new LogMailer.
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Compile-time check to ensure LogMailer implements Mailer
+var _ Mailer = (*LogMailer)(nil)
+
+// Send renders the email and logs it instead of sending it.
+func (m *LogMailer) Send(ctx context.Context, to, template string, data any) error {
+	msg, err := Render(template, data)
+	if err != nil {
+		return err
+	}
+	log.Printf("LogMailer: To: %s | Subject: %s | Body: %s", to, msg.Subject, msg.HTML)
+	return nil
+}
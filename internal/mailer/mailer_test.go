@@ -0,0 +1,163 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_EveryTemplateHasSubjectAndBody(t *testing.T) {
+	for name := range templates {
+		t.Run(name, func(t *testing.T) {
+			msg, err := Render(name, map[string]any{"Token": "tok123", "ExpiresIn": "1h0m0s"})
+			require.NoError(t, err)
+			assert.NotEmpty(t, msg.Subject)
+			assert.NotContains(t, msg.Subject, "\n")
+			assert.Contains(t, msg.HTML, "tok123")
+		})
+	}
+	assert.Contains(t, templates, TemplatePasswordReset)
+	assert.Contains(t, templates, TemplateVerifyEmail)
+}
+
+func TestRender_PasswordReset(t *testing.T) {
+	msg, err := Render(TemplatePasswordReset, PasswordResetData{Token: "tok123", ExpiresIn: "1h0m0s"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Password reset", msg.Subject)
+	assert.True(t, strings.HasPrefix(msg.HTML, "<!DOCTYPE html>"))
+	assert.Contains(t, msg.HTML, "<code>tok123</code>")
+	assert.Contains(t, msg.HTML, "It expires in 1h0m0s.")
+}
+
+func TestRender_EscapesData(t *testing.T) {
+	msg, err := Render(TemplateVerifyEmail, VerifyEmailData{Token: `<script>alert("x")</script>`, ExpiresIn: "1h"})
+
+	require.NoError(t, err)
+	assert.NotContains(t, msg.HTML, "<script>")
+	assert.Contains(t, msg.HTML, "&lt;script&gt;")
+}
+
+func TestRender_Errors(t *testing.T) {
+	_, err := Render("no_such_template", nil)
+	assert.Error(t, err)
+
+	_, err = Render(TemplatePasswordReset, map[string]any{"Token": "tok123"})
+	assert.Error(t, err, "missing keys must not render as <no value>")
+}
+
+func TestNew_UsesLogMailerWhenSMTPIsNotConfigured(t *testing.T) {
+	assert.IsType(t, &LogMailer{}, New(SMTPConfig{}))
+	assert.IsType(t, &LogMailer{}, New(SMTPConfig{Port: 587, From: "no-reply@example.com"}), "a port alone doesn't configure SMTP")
+	assert.IsType(t, &SMTPMailer{}, New(SMTPConfig{Host: "smtp.example.com", Port: 587, From: "no-reply@example.com"}))
+}
+
+func TestLogMailer_RejectsUnknownTemplate(t *testing.T) {
+	assert.NoError(t, NewLogMailer().Send(context.Background(), "user@example.com", TemplateVerifyEmail, VerifyEmailData{Token: "tok", ExpiresIn: "1h"}))
+	assert.Error(t, NewLogMailer().Send(context.Background(), "user@example.com", "no_such_template", nil))
+}
+
+// smtpCall is one call of SMTPMailer.sendMail.
+type smtpCall struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func newTestSMTPMailer(cfg SMTPConfig, calls *[]smtpCall, sendErr error) *SMTPMailer {
+	m := NewSMTPMailer(cfg)
+	m.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		*calls = append(*calls, smtpCall{addr: addr, auth: a, from: from, to: to, msg: string(msg)})
+		return sendErr
+	}
+	m.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+	return m
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	var calls []smtpCall
+	m := newTestSMTPMailer(SMTPConfig{Host: "smtp.example.com", Port: 587, Username: "api", Password: "secret", From: "Jobs <no-reply@example.com>"}, &calls, nil)
+
+	err := m.Send(context.Background(), "user@example.com", TemplatePasswordReset, PasswordResetData{Token: "tok123", ExpiresIn: "1h0m0s"})
+
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "smtp.example.com:587", calls[0].addr)
+	assert.NotNil(t, calls[0].auth)
+	assert.Equal(t, "no-reply@example.com", calls[0].from)
+	assert.Equal(t, []string{"user@example.com"}, calls[0].to)
+
+	headers, body, found := strings.Cut(calls[0].msg, "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, headers, `From: "Jobs" <no-reply@example.com>`)
+	assert.Contains(t, headers, "To: <user@example.com>")
+	assert.Contains(t, headers, "Subject: Password reset")
+	assert.Contains(t, headers, "Date: Sun, 01 Jun 2025 12:00:00 +0000")
+	assert.Contains(t, headers, "Content-Type: text/html; charset=UTF-8")
+	assert.Contains(t, body, "<code>tok123</code>")
+}
+
+func TestSMTPMailer_SendWithoutCredentialsSkipsAuth(t *testing.T) {
+	var calls []smtpCall
+	m := newTestSMTPMailer(SMTPConfig{Host: "localhost", Port: 25, From: "no-reply@example.com"}, &calls, nil)
+
+	require.NoError(t, m.Send(context.Background(), "user@example.com", TemplateVerifyEmail, VerifyEmailData{Token: "tok", ExpiresIn: "1h"}))
+	require.Len(t, calls, 1)
+	assert.Nil(t, calls[0].auth)
+}
+
+func TestSMTPMailer_SendErrors(t *testing.T) {
+	cfg := SMTPConfig{Host: "smtp.example.com", Port: 587, From: "no-reply@example.com"}
+	data := VerifyEmailData{Token: "tok", ExpiresIn: "1h"}
+
+	t.Run("HeaderInjection", func(t *testing.T) {
+		var calls []smtpCall
+		err := newTestSMTPMailer(cfg, &calls, nil).Send(context.Background(), "user@example.com\r\nBcc: victim@example.com", TemplateVerifyEmail, data)
+		assert.Error(t, err)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("ServerFailure", func(t *testing.T) {
+		var calls []smtpCall
+		err := newTestSMTPMailer(cfg, &calls, errors.New("connection refused")).Send(context.Background(), "user@example.com", TemplateVerifyEmail, data)
+		assert.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		var calls []smtpCall
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := newTestSMTPMailer(cfg, &calls, nil).Send(ctx, "user@example.com", TemplateVerifyEmail, data)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, calls)
+	})
+}
+
+// insertRecorder is an EventStore keeping the inserted events.
+type insertRecorder struct {
+	events []*models.OutboxEvent
+}
+
+func (r *insertRecorder) Insert(ctx context.Context, event *models.OutboxEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestQueue_RejectsEmailsThatDontRender(t *testing.T) {
+	store := &insertRecorder{}
+
+	err := NewQueue(store).Send(context.Background(), "user@example.com", "no_such_template", nil)
+
+	assert.Error(t, err)
+	assert.Empty(t, store.events, "nothing is queued for the relay to fail on")
+}
@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-api-template/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// EventStore is the subset of storage.OutboxRepository the queue needs.
+type EventStore interface {
+	Insert(ctx context.Context, event *models.OutboxEvent) error
+}
+
+// QueuedEmail is the payload of an email.requested outbox event.
+type QueuedEmail struct {
+	To       string          `json:"to"`
+	Template string          `json:"template"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Queue is a Mailer that records each email as an outbox event instead of sending it, so a slow or
+// unavailable mail server never holds up a request. The outbox relay delivers queued emails through
+// the real Mailer (see outbox.MailPublisher) and retries them until they are sent.
+type Queue struct {
+	store EventStore
+}
+
+// NewQueue creates a new Queue.
+func NewQueue(store EventStore) *Queue {
+	return &Queue{store: store}
+}
+
+// Compile-time check to ensure Queue implements Mailer
+var _ Mailer = (*Queue)(nil)
+
+// Send checks that the email renders, so a bad template or data fails the caller rather than the
+// relay, and queues it.
+func (q *Queue) Send(ctx context.Context, to, template string, data any) error {
+	if _, err := Render(template, data); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s email data: %w", template, err)
+	}
+	payload, err := json.Marshal(QueuedEmail{To: to, Template: template, Data: encoded})
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s email: %w", template, err)
+	}
+	event := &models.OutboxEvent{
+		AggregateType: models.OutboxAggregateEmail,
+		AggregateID:   uuid.New(), // Emails are independent, so none waits for another to be delivered
+		EventType:     models.OutboxEventEmailRequested,
+		Payload:       payload,
+	}
+	if err := q.store.Insert(ctx, event); err != nil {
+		return fmt.Errorf("failed to queue %s email: %w", template, err)
+	}
+	return nil
+}
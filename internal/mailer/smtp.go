@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig holds how to reach the SMTP server. Username and Password are optional; when set, the
+// mailer authenticates with PLAIN, which net/smtp only allows over TLS or to localhost.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // Sender address, e.g. "Jobs <no-reply@example.com>"
+}
+
+// SMTPMailer delivers emails through an SMTP server.
+type SMTPMailer struct {
+	cfg      SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error // Replaced in tests
+	now      func() time.Time
+}
+
+// NewSMTPMailer creates a new SMTPMailer.
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg, sendMail: smtp.SendMail, now: time.Now}
+}
+
+// Compile-time check to ensure SMTPMailer implements Mailer
+var _ Mailer = (*SMTPMailer)(nil)
+
+// Send renders the email and delivers it. net/smtp doesn't take a context, so ctx is only checked
+// before connecting.
+func (m *SMTPMailer) Send(ctx context.Context, to, template string, data any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+	msg, err := Render(template, data)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	if err := m.sendMail(addr, auth, from.Address, []string{recipient.Address}, m.buildMessage(from, recipient, msg)); err != nil {
+		return fmt.Errorf("failed to send %s email: %w", template, err)
+	}
+	return nil
+}
+
+// buildMessage formats msg as an RFC 5322 message with an HTML body. The addresses come from
+// mail.ParseAddress, so they can't smuggle extra headers in.
+func (m *SMTPMailer) buildMessage(from, to *mail.Address, msg *Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", m.now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.HTML)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
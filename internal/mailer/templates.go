@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Email templates. Each is templates/<name>.html, whose "subject" block is the subject line and whose
+// main body is the HTML body.
+const (
+	TemplatePasswordReset = "password_reset"
+	TemplateVerifyEmail   = "verify_email"
+)
+
+// PasswordResetData is the data of the TemplatePasswordReset email.
+type PasswordResetData struct {
+	Token     string
+	ExpiresIn string
+}
+
+// VerifyEmailData is the data of the TemplateVerifyEmail email.
+type VerifyEmailData struct {
+	Token     string
+	ExpiresIn string
+}
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// templates holds every parsed template by name. Each file is parsed on its own so their "subject"
+// blocks don't clash.
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]*template.Template {
+	files, err := fs.Glob(templateFS, "templates/*.html")
+	if err != nil {
+		panic(fmt.Sprintf("mailer: listing templates: %v", err))
+	}
+	parsed := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".html")
+		tmpl, err := template.New(path.Base(file)).Option("missingkey=error").ParseFS(templateFS, file)
+		if err != nil {
+			panic(fmt.Sprintf("mailer: parsing template %s: %v", file, err))
+		}
+		if tmpl.Lookup("subject") == nil {
+			panic(fmt.Sprintf("mailer: template %s has no subject block", file))
+		}
+		parsed[name] = tmpl
+	}
+	return parsed
+}
+
+// Message is a rendered email.
+type Message struct {
+	Subject string
+	HTML    string
+}
+
+// Render executes the named template with data. Data is usually the template's *Data struct, but a
+// map with the same keys works too, which is what an email queued through the outbox arrives as.
+func Render(name string, data any) (*Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render subject of %s email: %w", name, err)
+	}
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s email: %w", name, err)
+	}
+	return &Message{
+		// The subject is a header, not HTML, so undo the escaping html/template applied
+		Subject: html.UnescapeString(strings.TrimSpace(subject.String())),
+		HTML:    strings.TrimSpace(body.String()),
+	}, nil
+}
@@ -1,13 +1,123 @@
 package models
 
 import (
+	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// --- User Role Enum ---
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+)
+
+// Scan implements the sql.Scanner interface for UserRole
+func (ur *UserRole) Scan(value interface{}) error {
+	strVal, ok := value.(string)
+	if !ok {
+		byteVal, ok := value.([]byte)
+		if ok {
+			strVal = string(byteVal)
+		} else {
+			return fmt.Errorf("failed to scan UserRole: value is not string or []byte")
+		}
+	}
+	v := UserRole(strVal)
+	switch v {
+	case UserRoleUser, UserRoleAdmin:
+		*ur = v
+		return nil
+	default:
+		return fmt.Errorf("invalid UserRole value: %s", strVal)
+	}
+}
+
+// Value implements the driver.Valuer interface for UserRole
+func (ur UserRole) Value() (driver.Value, error) {
+	return string(ur), nil
+}
+
+// --- Capabilities ---
+
+// Capability names something a user may do. Role capabilities come from RoleCapabilities, the single
+// source of truth that middleware.RequireCapability enforces on routes; relationship capabilities depend
+// on the user's jobs and are worked out per user. Either way, services still check every request.
+type Capability string
+
+const (
+	// Granted by role
+	CapabilityCreateJob        Capability = "can_create_job"
+	CapabilityApplyToJobs      Capability = "can_apply_to_jobs"
+	CapabilityAccessAdmin      Capability = "can_access_admin"
+	CapabilityManageUsers      Capability = "can_manage_users" // List and restore users
+	CapabilityRestoreJobs      Capability = "can_restore_jobs"
+	CapabilityManageWebhooks   Capability = "can_manage_webhooks"
+	CapabilityManageAPIKeys    Capability = "can_manage_api_keys"
+	CapabilityImpersonateUsers Capability = "can_impersonate_users"
+
+	// Granted by relationships
+	CapabilityCreateInvoice      Capability = "can_create_invoice"      // Contractor of an Ongoing job
+	CapabilityReviewApplications Capability = "can_review_applications" // Employer of a job still Waiting for a contractor
+)
+
+// AllCapabilities lists every capability, role ones first.
+var AllCapabilities = []Capability{
+	CapabilityCreateJob,
+	CapabilityApplyToJobs,
+	CapabilityAccessAdmin,
+	CapabilityManageUsers,
+	CapabilityRestoreJobs,
+	CapabilityManageWebhooks,
+	CapabilityManageAPIKeys,
+	CapabilityImpersonateUsers,
+	CapabilityCreateInvoice,
+	CapabilityReviewApplications,
+}
+
+var userCapabilities = []Capability{CapabilityCreateJob, CapabilityApplyToJobs}
+
+// roleCapabilities lists what each role may do. Admins may also do everything users may.
+var roleCapabilities = map[UserRole][]Capability{
+	UserRoleUser: userCapabilities,
+	UserRoleAdmin: append([]Capability{
+		CapabilityAccessAdmin,
+		CapabilityManageUsers,
+		CapabilityRestoreJobs,
+		CapabilityManageWebhooks,
+		CapabilityManageAPIKeys,
+		CapabilityImpersonateUsers,
+	}, userCapabilities...),
+}
+
+// RoleCapabilities returns the capabilities granted by role; none for an unknown role.
+func RoleCapabilities(role UserRole) []Capability {
+	return append([]Capability(nil), roleCapabilities[role]...)
+}
+
+// UserPermissions is what one user may do.
+type UserPermissions struct {
+	Role         UserRole
+	Capabilities []Capability // The granted ones, in AllCapabilities order
+}
+
+// Can reports whether role grants capability.
+func (ur UserRole) Can(capability Capability) bool {
+	for _, granted := range roleCapabilities[ur] {
+		if granted == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // --- Job State Enum ---
 type JobState string
 
@@ -16,6 +126,7 @@ const (
 	JobStateOngoing   JobState = "Ongoing"
 	JobStateComplete  JobState = "Complete"
 	JobStateArchived  JobState = "Archived"
+	JobStateCancelled JobState = "Cancelled" // Stopped by the employer while Ongoing; terminal
 )
 
 // Scan implements the sql.Scanner interface for JobState
@@ -31,7 +142,7 @@ func (js *JobState) Scan(value interface{}) error {
 	}
 	v := JobState(strVal)
 	switch v {
-	case JobStateOngoing, JobStateComplete, JobStateArchived, JobStateWaiting:
+	case JobStateOngoing, JobStateComplete, JobStateArchived, JobStateWaiting, JobStateCancelled:
 		*js = v
 		return nil
 	default:
@@ -44,12 +155,63 @@ func (js JobState) Value() (driver.Value, error) {
 	return string(js), nil
 }
 
+// --- Invoice Cadence Enum ---
+type InvoiceCadence string
+
+const (
+	InvoiceCadenceDaily    InvoiceCadence = "daily"
+	InvoiceCadenceWeekly   InvoiceCadence = "weekly"
+	InvoiceCadenceBiweekly InvoiceCadence = "biweekly"
+	InvoiceCadenceMonthly  InvoiceCadence = "monthly"
+)
+
+// Next returns when the invoice after one due at t is due.
+func (c InvoiceCadence) Next(t time.Time) time.Time {
+	switch c {
+	case InvoiceCadenceDaily:
+		return t.AddDate(0, 0, 1)
+	case InvoiceCadenceBiweekly:
+		return t.AddDate(0, 0, 14)
+	case InvoiceCadenceMonthly:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 7)
+	}
+}
+
+// Scan implements the sql.Scanner interface for InvoiceCadence
+func (c *InvoiceCadence) Scan(value interface{}) error {
+	strVal, ok := value.(string)
+	if !ok {
+		byteVal, ok := value.([]byte)
+		if ok {
+			strVal = string(byteVal)
+		} else {
+			return fmt.Errorf("failed to scan InvoiceCadence: value is not string or []byte")
+		}
+	}
+	v := InvoiceCadence(strVal)
+	switch v {
+	case InvoiceCadenceDaily, InvoiceCadenceWeekly, InvoiceCadenceBiweekly, InvoiceCadenceMonthly:
+		*c = v
+		return nil
+	default:
+		return fmt.Errorf("invalid InvoiceCadence value: %s", strVal)
+	}
+}
+
+// Value implements the driver.Valuer interface for InvoiceCadence
+func (c InvoiceCadence) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
 // --- Invoice State Enum ---
 type InvoiceState string
 
 const (
-	InvoiceStateWaiting  InvoiceState = "Waiting"  // Waiting for employer action/payment
-	InvoiceStateComplete InvoiceState = "Complete" // Paid or otherwise resolved
+	InvoiceStateWaiting  InvoiceState = "Waiting"  // Waiting for employer action
+	InvoiceStateComplete InvoiceState = "Complete" // Work accepted, awaiting payment
+	InvoiceStatePaid     InvoiceState = "Paid"     // Payment received
 )
 
 // Scan implements the sql.Scanner interface for InvoiceState
@@ -65,7 +227,7 @@ func (is *InvoiceState) Scan(value interface{}) error {
 	}
 	v := InvoiceState(strVal)
 	switch v {
-	case InvoiceStateWaiting, InvoiceStateComplete:
+	case InvoiceStateWaiting, InvoiceStateComplete, InvoiceStatePaid:
 		*is = v
 		return nil
 	default:
@@ -86,6 +248,8 @@ const (
 	JobApplicationAccepted   JobApplicationState = "Accepted"
 	JobApplicationRejected  JobApplicationState = "Rejected"
 	JobApplicationWithdrawn  JobApplicationState = "Withdrawn"
+	JobApplicationInvited    JobApplicationState = "Invited"  // The employer invited the contractor, who hasn't answered yet
+	JobApplicationDeclined   JobApplicationState = "Declined" // The contractor turned the invitation down
 )
 
 // Scan implements the sql.Scanner interface for JobApplicationState
@@ -101,7 +265,7 @@ func (jas *JobApplicationState) Scan(value interface{}) error {
 	}
 	v := JobApplicationState(strVal)
 	switch v {
-	case JobApplicationAccepted, JobApplicationRejected, JobApplicationWithdrawn, JobApplicationWaiting:
+	case JobApplicationAccepted, JobApplicationRejected, JobApplicationWithdrawn, JobApplicationWaiting, JobApplicationInvited, JobApplicationDeclined:
 		*jas = v
 		return nil
 	default:
@@ -128,44 +292,380 @@ type User struct {
 	// Assuming 'password_hash' in DB is VARCHAR/TEXT NOT NULL
 	PasswordHash string    `json:"-" db:"password_hash"`
 
+	// Whether the user has confirmed their email address, BOOLEAN NOT NULL DEFAULT FALSE
+	Verified bool `json:"verified" db:"verified"`
+
+	// Assuming 'role' in DB is user_role ENUM NOT NULL DEFAULT 'user'
+	Role UserRole `json:"role" db:"role"`
+
+	// URL of the uploaded profile picture, TEXT NULL; nil means no avatar
+	AvatarURL *string `json:"avatar_url,omitempty" db:"avatar_url"`
+
 	// Assuming 'created_at' in DB is TIMESTAMPTZ NOT NULL
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 
 	// Assuming 'updated_at' in DB is TIMESTAMPTZ NOT NULL
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Set when the user is soft-deleted, TIMESTAMPTZ NULL
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // Job represents a work contract between an employer and a contractor.
 type Job struct {
 	ID              uuid.UUID  `json:"id" db:"id"`
 	Rate            float64    `json:"rate" db:"rate"`
+	Currency        string     `json:"currency" db:"currency"` // ISO 4217 code the rate, and the job's invoices, are in
 	Duration        int        `json:"duration" db:"duration"` // In hours (or define unit clearly)
 	ContractorID    *uuid.UUID `json:"contractor_id,omitempty" db:"contractor_id"` // Pointer for NULLable UUID
 	EmployerID      uuid.UUID  `json:"employer_id" db:"employer_id"`
 	State           JobState   `json:"state" db:"state"`
 	InvoiceInterval int        `json:"invoice_interval" db:"invoice_interval"` // In hours
+	Description     string     `json:"description" db:"description"`
+	ApplicationDeadline *time.Time `json:"application_deadline,omitempty" db:"application_deadline"` // Applications close after this; nil means no deadline
+	AutoInvoice         bool           `json:"auto_invoice" db:"auto_invoice"`                           // The scheduler creates interval invoices on AutoInvoiceCadence
+	AutoInvoiceCadence  InvoiceCadence `json:"auto_invoice_cadence" db:"auto_invoice_cadence"`
+	NextAutoInvoiceAt   *time.Time     `json:"next_auto_invoice_at,omitempty" db:"next_auto_invoice_at"` // When the next automatic invoice is due; nil when none is scheduled
+	Version         int        `json:"version" db:"version"` // Incremented on every write; used for optimistic concurrency
+	Skills          []string   `json:"skills,omitempty" db:"-"` // Loaded from job_skills by the service, sorted by name
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty" db:"created_by"` // User who posted the job; nil for jobs from before tracking
+	UpdatedBy       *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"` // User (or SystemActorID) behind the last change
 	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // Set when soft-deleted
+}
+
+// Skill match modes for filtering jobs by several skills.
+const (
+	SkillsMatchAll = "all" // Jobs must have every requested skill
+	SkillsMatchAny = "any" // Jobs need at least one of the requested skills
+)
+
+// Skill is an entry of the skills taxonomy jobs are tagged with.
+type Skill struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"` // Normalized, see NormalizeSkillName
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NormalizeSkillName trims and lowercases a skill name, the form skills are stored and looked up in,
+// so "Go " and "go" are the same skill.
+func NormalizeSkillName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Reasons a job can't be deleted, reported by JobService.CanDeleteJob.
+const (
+	JobDeleteBlockerInvalidState       = "invalid_state"       // Only Waiting jobs can be deleted
+	JobDeleteBlockerContractorAssigned = "contractor_assigned" // Someone is (still) assigned to the job
+	JobDeleteBlockerInvoicesExist      = "invoices_exist"      // Deleting would hide the job's billing history
+)
+
+// JobDeleteBlocker is one reason a job can't be deleted.
+type JobDeleteBlocker struct {
+	Code    string
+	Message string
+}
+
+// SystemActorID is recorded as the creator or last updater of records the API changes on its own, e.g.
+// invoices created by the scheduler or jobs archived in the background. No user has this ID.
+var SystemActorID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// JobParty is who moves a job between states: one of the users on the job, or the API itself.
+type JobParty string
+
+const (
+	JobPartyEmployer   JobParty = "employer"
+	JobPartyContractor JobParty = "contractor"
+	JobPartySystem     JobParty = "system" // Side effect of another operation, e.g. accepting an application
+)
+
+// JobStateTransition is one move allowed by the job state machine.
+type JobStateTransition struct {
+	From      JobState
+	To        JobState
+	AllowedBy []JobParty
+	Note      string // How the transition happens when no user can make it directly
+}
+
+// JobStateMachine describes the job lifecycle.
+type JobStateMachine struct {
+	States      []JobState // In lifecycle order
+	Transitions []JobStateTransition
+}
+
+// EmployerStats summarizes an employer's job postings. Soft-deleted jobs and their invoices are not counted.
+// Amounts in different currencies are never added up: they are keyed by ISO 4217 code, and a currency
+// only appears if the employer has jobs or invoices in it.
+type EmployerStats struct {
+	JobsByState   map[JobState]int   `json:"jobs_by_state"` // Every state is present, with 0 if the employer has no such jobs
+	TotalJobs     int                `json:"total_jobs"`
+	AverageRate   map[string]float64 `json:"average_rate"`   // Average rate of the jobs in each currency
+	TotalInvoiced map[string]float64 `json:"total_invoiced"` // Sum of all invoices on the employer's jobs, whatever their state
+}
+
+// UnpaidInvoiceTotals sums the invoices on a contractor's jobs that haven't been paid yet, per ISO 4217
+// currency. Total and OverdueTotal have the same keys.
+type UnpaidInvoiceTotals struct {
+	Count        int                `json:"count"` // Over all currencies
+	Total        map[string]float64 `json:"total"`
+	OverdueTotal map[string]float64 `json:"overdue_total"` // Part of Total that is past its due date
+}
+
+// ContractorDashboard gathers what a contractor needs at a glance. Sections that couldn't be loaded
+// are left empty and named in FailedSections, with Partial set.
+type ContractorDashboard struct {
+	ActiveJobs               []Job               `json:"active_jobs"` // Ongoing jobs, newest first; at most a page
+	TotalActiveJobs          int                 `json:"total_active_jobs"`
+	PendingApplications      []JobApplication    `json:"pending_applications"` // Waiting applications, newest first; at most a page
+	TotalPendingApplications int                 `json:"total_pending_applications"`
+	UnpaidInvoices           UnpaidInvoiceTotals `json:"unpaid_invoices"`
+	Partial                  bool                `json:"partial"`
+	FailedSections           []string            `json:"failed_sections"`
+}
+
+// JobBalance summarizes how much of a job has been invoiced and paid.
+type JobBalance struct {
+	JobID         uuid.UUID `json:"job_id"`
+	TotalValue    float64   `json:"total_value"`    // Rate x Duration
+	TotalInvoiced float64   `json:"total_invoiced"` // Sum of all invoices, whatever their state
+	TotalPaid     float64   `json:"total_paid"`     // Sum of Paid invoices
+	Remaining     float64   `json:"remaining"`      // TotalValue minus TotalInvoiced, never below 0
+	Currency      string    `json:"currency"`       // The job's; every amount above is in it
 }
 
 // Invoice represents a bill generated for a Job based on the interval.
 type Invoice struct {
 	ID        uuid.UUID    `json:"id" db:"id"`
+	InvoiceNumber string   `json:"invoice_number" db:"invoice_number"` // Sequential per employer, e.g. INV-000042-2025
 	Value     float64      `json:"value" db:"value"`
+	Currency  string       `json:"currency" db:"currency"` // ISO 4217 code, copied from the job when the invoice is created
 	State     InvoiceState `json:"state" db:"state"`
 	JobID     uuid.UUID    `json:"job_id" db:"job_id"`
+	ContractorID *uuid.UUID `json:"contractor_id,omitempty" db:"contractor_id"` // Who issued it; kept when the job is reassigned or cancelled
 	IntervalNumber int          `json:"interval_number" db:"interval_number"`
+	PaidAt    *time.Time   `json:"paid_at,omitempty" db:"paid_at"` // Set when the invoice moves to Paid
+	DueDate   time.Time    `json:"due_date" db:"due_date"`         // Payment is late once a Complete invoice passes it
+	CreatedBy *uuid.UUID   `json:"created_by,omitempty" db:"created_by"` // User (or SystemActorID) who created the invoice
+	UpdatedBy *uuid.UUID   `json:"updated_by,omitempty" db:"updated_by"` // User (or SystemActorID) behind the last change
 	CreatedAt time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
 }
 
+// DefaultInvoicePaymentTermDays is the net-N payment term used when none is configured.
+const DefaultInvoicePaymentTermDays = 30
+
+// DefaultCurrency is the ISO 4217 currency of jobs posted without one when none is configured.
+const DefaultCurrency = "USD"
+
+// DefaultInvoiceMaxAdjustmentPercent bounds invoice adjustments when no limit is configured.
+const DefaultInvoiceMaxAdjustmentPercent = 50.0
+
+// IsOverdue reports whether the invoice was accepted but is still unpaid after its due date.
+func (i *Invoice) IsOverdue(now time.Time) bool {
+	return i.State == InvoiceStateComplete && now.After(i.DueDate)
+}
+
+// Rating is a score given to a user for their work on a completed Job.
+type Rating struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	JobID     uuid.UUID `json:"job_id" db:"job_id"` // NULL in the database once the job is purged; such ratings only count towards averages
+	RaterID   uuid.UUID `json:"rater_id" db:"rater_id"` // The job's employer
+	RateeID   uuid.UUID `json:"ratee_id" db:"ratee_id"` // The job's contractor
+	Score     int       `json:"score" db:"score"`       // 1 to 5
+	Comment   *string   `json:"comment,omitempty" db:"comment"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// UserRatingSummary is the average of all ratings a user has received.
+type UserRatingSummary struct {
+	UserID       uuid.UUID `json:"user_id"`
+	AverageScore float64   `json:"average_score"` // 0 if the user has no ratings
+	RatingCount  int       `json:"rating_count"`
+}
+
+// API key scopes. Routes served to machine clients require one of them.
+const (
+	APIKeyScopeInvoicesPay = "invoices:pay" // Mark Complete invoices as paid, e.g. from the billing system
+)
+
+// APIKey authenticates a machine client through the X-API-Key header.
+type APIKey struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	KeyHash   string     `json:"-" db:"key_hash"` // SHA-256 of the key; the key itself is only shown when minted
+	Owner     string     `json:"owner" db:"owner"`
+	Scopes    []string   `json:"scopes" db:"scopes"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Webhook delivery states.
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its next attempt
+	WebhookDeliveryDelivered = "delivered" // The endpoint answered 2xx
+	WebhookDeliveryFailed    = "failed"    // Every attempt failed; no more are made
+)
+
+// Webhook is an external integration's subscription to domain events, delivered as signed HTTP POSTs.
+type Webhook struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Owner      string     `json:"owner" db:"owner"`
+	URL        string     `json:"url" db:"url"`
+	Secret     string     `json:"-" db:"secret"` // Signs deliveries; only shown when registered
+	EventTypes []string   `json:"event_types" db:"event_types"`
+	Active     bool       `json:"active" db:"active"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery is one outbox event to be POSTed to one webhook, with the outcome of its attempts.
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	EventID        int64           `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string         `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ClaimedWebhookDelivery is a delivery taken by a worker, with where to send it and how to sign it.
+type ClaimedWebhookDelivery struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
+
 // JobApplication represents a user application for a Job.
 type JobApplication struct {
 	ID        uuid.UUID    `json:"id" db:"id"`
 	ContractorID     uuid.UUID    `json:"contractor_id" db:"contractor_id"`
 	JobID     uuid.UUID    `json:"job_id" db:"job_id"`
 	State     JobApplicationState `json:"state" db:"state"`
+	CoverMessage *string   `json:"cover_message,omitempty" db:"cover_message"`
+	EmployerNote *string   `json:"-" db:"employer_note"` // Only visible to the job's employer
+	ProposedRate *float64  `json:"proposed_rate,omitempty" db:"proposed_rate"` // Counter-offer on the job's rate; nil accepts the posted rate
 	CreatedAt time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
 }
 
+// AppliedJob is a job a contractor applied to, together with their application.
+type AppliedJob struct {
+	Job         Job
+	Application JobApplication
+}
+
+// JobApplicationExportRow is a flattened view of an application used for employer exports.
+type JobApplicationExportRow struct {
+	ContractorName string              `db:"contractor_name"`
+	AppliedAt      time.Time           `db:"created_at"`
+	State          JobApplicationState `db:"state"`
+	CoverMessage   *string             `db:"cover_message"`
+	EmployerNote   *string             `db:"employer_note"`
+}
+
+// Outbox aggregate and event types.
+const (
+	OutboxAggregateJob     = "job"
+	OutboxAggregateInvoice = "invoice"
+	OutboxAggregateEmail   = "email"
+
+	OutboxEventJobCreated          = "job.created"
+	OutboxEventJobUpdated          = "job.updated"
+	OutboxEventJobStateChanged     = "job.state_changed"
+	OutboxEventJobDeleted          = "job.deleted"
+	OutboxEventInvoiceCreated      = "invoice.created"
+	OutboxEventInvoiceStateChanged = "invoice.state_changed"
+	OutboxEventInvoiceDeleted      = "invoice.deleted"
+	OutboxEventEmailRequested      = "email.requested"
+)
+
+// Audit entity types and actions.
+const (
+	AuditEntityJob  = "job"
+	AuditEntityUser = "user"
+
+	AuditActionJobOwnershipTransferred = "job.ownership_transferred"
+	AuditActionJobReopened             = "job.reopened"
+	AuditActionJobCancelled            = "job.cancelled"
+	AuditActionJobContractorReassigned = "job.contractor_reassigned"
+	AuditActionUserImpersonated        = "user.impersonated"
+)
+
+// AuditEntry records a change made by a user, kept so it can be traced later.
+type AuditEntry struct {
+	ID             int64           `json:"id" db:"id"`
+	ActorID        *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`               // nil once the user is hard-deleted
+	ImpersonatorID *uuid.UUID      `json:"impersonator_id,omitempty" db:"impersonator_id"` // The admin acting as ActorID, if the change was made while impersonating
+	Action         string          `json:"action" db:"action"`
+	EntityType     string          `json:"entity_type" db:"entity_type"`
+	EntityID       uuid.UUID       `json:"entity_id" db:"entity_id"`
+	Details        json.RawMessage `json:"details" db:"details"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+type impersonatorKey struct{}
+
+// WithImpersonator marks ctx as a request made by adminID through an impersonation token, so audit
+// entries recorded with it name the admin as well as the impersonated user.
+func WithImpersonator(ctx context.Context, adminID uuid.UUID) context.Context {
+	return context.WithValue(ctx, impersonatorKey{}, adminID)
+}
+
+// ImpersonatorFromContext returns the admin stored by WithImpersonator, if any.
+func ImpersonatorFromContext(ctx context.Context) (uuid.UUID, bool) {
+	adminID, ok := ctx.Value(impersonatorKey{}).(uuid.UUID)
+	return adminID, ok
+}
+
+// UserImpersonation is the audit detail of an admin minting an impersonation token for a user.
+type UserImpersonation struct {
+	TokenID   string    `json:"token_id"` // jti of the token, for revoking it
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// JobOwnershipTransfer is the audit detail of a job moving to another employer.
+type JobOwnershipTransfer struct {
+	FromEmployerID uuid.UUID `json:"from_employer_id"`
+	ToEmployerID   uuid.UUID `json:"to_employer_id"`
+}
+
+// JobReopening is the audit detail of a Complete job moving back to Ongoing.
+type JobReopening struct {
+	ContractorID uuid.UUID `json:"contractor_id"` // Still assigned; the job goes back to them
+}
+
+// JobCancellation is the audit detail of an Ongoing job being cancelled.
+type JobCancellation struct {
+	ContractorID   uuid.UUID  `json:"contractor_id"` // Unassigned by the cancellation
+	Reason         *string    `json:"reason,omitempty"`
+	FinalInvoiceID *uuid.UUID `json:"final_invoice_id,omitempty"` // The prorated invoice for work done so far, if one was created
+}
+
+// JobContractorReassignment is the audit detail of an Ongoing job moving to another contractor. The job's
+// invoices stay as they were; those up to LastInvoicedInterval were billed by FromContractorID.
+type JobContractorReassignment struct {
+	FromContractorID     uuid.UUID `json:"from_contractor_id"`
+	ToContractorID       uuid.UUID `json:"to_contractor_id"`
+	Reason               *string   `json:"reason,omitempty"`
+	LastInvoicedInterval int       `json:"last_invoiced_interval"` // 0 if nothing was invoiced yet
+}
+
+// OutboxEvent is a domain event waiting to be (or already) published to external systems.
+type OutboxEvent struct {
+	ID            int64           `json:"id" db:"id"` // Increases with insertion; events are published in this order
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id" db:"aggregate_id"`
+	EventType     string          `json:"event_type" db:"event_type"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty" db:"published_at"`
+}
@@ -0,0 +1,265 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// RedisNotificationsChannel is the pub/sub channel every instance publishes to and listens on.
+	RedisNotificationsChannel = "notifications"
+
+	writeWait      = 10 * time.Second    // Max time to write a message to the client
+	pongWait       = 60 * time.Second    // Max time to wait for the next pong from the client
+	pingPeriod     = (pongWait * 9) / 10 // Must be less than pongWait
+	maxMessageSize = 512                 // Clients only send control frames; anything bigger is dropped
+	sendBufferSize = 16                  // Queued messages per connection before it's considered too slow
+)
+
+// Event types sent to clients.
+const (
+	EventApplicationReceived = "application.received" // Sent to the employer when someone applies
+	EventApplicationAccepted = "application.accepted" // Sent to the contractor
+	EventApplicationRejected = "application.rejected" // Sent to the contractor
+	EventInvitationReceived  = "invitation.received"  // Sent to the contractor an employer invited
+	EventInvitationAccepted  = "invitation.accepted"  // Sent to the employer
+	EventInvitationDeclined  = "invitation.declined"  // Sent to the employer
+)
+
+// Event is the JSON payload delivered over the WebSocket.
+type Event struct {
+	Type      string    `json:"type"`
+	Data      any       `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Notifier delivers events to a user's active connections.
+type Notifier interface {
+	Notify(ctx context.Context, userID uuid.UUID, event Event) error
+}
+
+// envelope is what travels over Redis, so any instance can deliver to its own connections.
+type envelope struct {
+	UserID uuid.UUID       `json:"user_id"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// client is a single WebSocket connection.
+type client struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// Hub keeps track of the WebSocket connections on this instance, keyed by user ID.
+// With a Redis client, events are published to RedisNotificationsChannel and delivered by every
+// instance's Run loop; without one they are delivered to local connections directly.
+type Hub struct {
+	redisClient *redis.Client
+	upgrader    websocket.Upgrader
+
+	mu      sync.RWMutex
+	clients map[uuid.UUID]map[*client]struct{}
+}
+
+// NewHub creates a new Hub. redisClient may be nil for single-instance setups and tests.
+// allowedOrigins follows the CORS config: "*" allows any origin.
+func NewHub(redisClient *redis.Client, allowedOrigins []string) *Hub {
+	return &Hub{
+		redisClient: redisClient,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true // Non-browser clients don't send an Origin
+				}
+				for _, allowed := range allowedOrigins {
+					if allowed == "*" || allowed == origin {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		clients: make(map[uuid.UUID]map[*client]struct{}),
+	}
+}
+
+// Compile-time check to ensure Hub implements Notifier
+var _ Notifier = (*Hub)(nil)
+
+// Notify sends an event to every connection the user has open, on any instance.
+func (h *Hub) Notify(ctx context.Context, userID uuid.UUID, event Event) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	if h.redisClient == nil {
+		h.deliver(userID, payload)
+		return nil
+	}
+
+	msg, err := json.Marshal(envelope{UserID: userID, Event: payload})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification envelope: %w", err)
+	}
+	if err := h.redisClient.Publish(ctx, RedisNotificationsChannel, msg).Err(); err != nil {
+		return fmt.Errorf("failed to publish notification: %w", err)
+	}
+	return nil
+}
+
+// Run delivers events published on Redis to this instance's connections until ctx is cancelled.
+// It returns immediately when the hub has no Redis client.
+func (h *Hub) Run(ctx context.Context) {
+	if h.redisClient == nil {
+		return
+	}
+
+	sub := h.redisClient.Subscribe(ctx, RedisNotificationsChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var env envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				log.Printf("Notifications: Dropping malformed message: %v", err)
+				continue
+			}
+			h.deliver(env.UserID, env.Event)
+		}
+	}
+}
+
+// ServeWS upgrades the request to a WebSocket and streams the user's events to it until the client disconnects.
+func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request, userID uuid.UUID) error {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade connection: %w", err)
+	}
+
+	c := &client{conn: conn, send: make(chan []byte, sendBufferSize)}
+	h.register(userID, c)
+
+	go h.writePump(c)
+	h.readPump(userID, c) // Blocks until the connection closes
+	return nil
+}
+
+// register adds a connection for the user.
+func (h *Hub) register(userID uuid.UUID, c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*client]struct{})
+	}
+	h.clients[userID][c] = struct{}{}
+}
+
+// unregister removes a connection and stops its write pump. Safe to call more than once.
+func (h *Hub) unregister(userID uuid.UUID, c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conns, ok := h.clients[userID]
+	if !ok {
+		return
+	}
+	if _, ok := conns[c]; !ok {
+		return
+	}
+	delete(conns, c)
+	close(c.send)
+	if len(conns) == 0 {
+		delete(h.clients, userID)
+	}
+}
+
+// deliver queues a payload on each of the user's local connections.
+// Connections whose buffer is full are dropped rather than blocking everyone else.
+func (h *Hub) deliver(userID uuid.UUID, payload []byte) {
+	h.mu.RLock()
+	var slow []*client
+	for c := range h.clients[userID] {
+		select {
+		case c.send <- payload:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		log.Printf("Notifications: Dropping slow connection for user %s", userID)
+		h.unregister(userID, c)
+	}
+}
+
+// readPump discards client messages and keeps the read deadline fresh on pongs.
+// Reading is needed to process control frames and notice when the client goes away.
+func (h *Hub) readPump(userID uuid.UUID, c *client) {
+	defer func() {
+		h.unregister(userID, c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump sends queued events and periodic pings to the client.
+func (h *Hub) writePump(c *client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// The hub closed the channel
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
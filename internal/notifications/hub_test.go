@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connectionCount returns how many connections the hub holds for a user.
+func (h *Hub) connectionCount(userID uuid.UUID) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID])
+}
+
+// dialHub starts a test server serving the hub for userID and connects a client to it.
+func dialHub(t *testing.T, hub *Hub, userID uuid.UUID) *websocket.Conn {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = hub.ServeWS(w, r, userID)
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	// The server registers the connection just after the handshake completes
+	require.Eventually(t, func() bool { return hub.connectionCount(userID) == 1 }, time.Second, 10*time.Millisecond)
+	return conn
+}
+
+func TestHub_NotifyDeliversToUserConnection(t *testing.T) {
+	hub := NewHub(nil, nil)
+	userID := uuid.New()
+	conn := dialHub(t, hub, userID)
+
+	applicationID := uuid.New()
+	err := hub.Notify(context.Background(), userID, Event{
+		Type: EventApplicationReceived,
+		Data: map[string]string{"application_id": applicationID.String()},
+	})
+	require.NoError(t, err)
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var got struct {
+		Type      string            `json:"type"`
+		Data      map[string]string `json:"data"`
+		CreatedAt time.Time         `json:"created_at"`
+	}
+	require.NoError(t, json.Unmarshal(msg, &got))
+	assert.Equal(t, EventApplicationReceived, got.Type)
+	assert.Equal(t, applicationID.String(), got.Data["application_id"])
+	assert.False(t, got.CreatedAt.IsZero(), "created_at should be filled in")
+}
+
+func TestHub_NotifyIgnoresOtherUsers(t *testing.T) {
+	hub := NewHub(nil, nil)
+	conn := dialHub(t, hub, uuid.New())
+
+	require.NoError(t, hub.Notify(context.Background(), uuid.New(), Event{Type: EventApplicationAccepted}))
+
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	_, _, err := conn.ReadMessage()
+	require.Error(t, err, "Another user's event should not be delivered")
+}
+
+func TestHub_UnregistersOnClientClose(t *testing.T) {
+	hub := NewHub(nil, nil)
+	userID := uuid.New()
+	conn := dialHub(t, hub, userID)
+
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return hub.connectionCount(userID) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestHub_RejectsDisallowedOrigin(t *testing.T) {
+	hub := NewHub(nil, []string{"https://app.example.com"})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = hub.ServeWS(w, r, uuid.New())
+	}))
+	defer srv.Close()
+
+	header := http.Header{"Origin": []string{"https://evil.example.com"}}
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), header)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
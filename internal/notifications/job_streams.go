@@ -0,0 +1,218 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// RedisJobEventsChannel is the pub/sub channel job-scoped events travel on between instances.
+	RedisJobEventsChannel = "job-events"
+
+	streamKeepAlive = 15 * time.Second // Comment sent on idle streams so proxies don't close them
+)
+
+// EventInvoiceStateChanged is streamed to everyone watching a job when one of its invoices changes state.
+const EventInvoiceStateChanged = "invoice.state_changed"
+
+// JobEventPublisher delivers events to everyone watching a job.
+type JobEventPublisher interface {
+	PublishJobEvent(ctx context.Context, jobID uuid.UUID, event Event) error
+}
+
+// jobEnvelope is what travels over Redis, so any instance can deliver to its own streams.
+type jobEnvelope struct {
+	JobID uuid.UUID       `json:"job_id"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// streamMessage is one event queued for a stream.
+type streamMessage struct {
+	eventType string
+	payload   []byte
+}
+
+// JobStreams keeps track of the server-sent event streams on this instance, keyed by job ID. It
+// fans events out the same way Hub does: through RedisJobEventsChannel when it has a Redis client,
+// straight to local streams otherwise.
+type JobStreams struct {
+	redisClient *redis.Client
+	keepAlive   time.Duration
+
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan streamMessage]struct{}
+}
+
+// NewJobStreams creates a new JobStreams. redisClient may be nil for single-instance setups and tests.
+func NewJobStreams(redisClient *redis.Client) *JobStreams {
+	return &JobStreams{
+		redisClient: redisClient,
+		keepAlive:   streamKeepAlive,
+		subscribers: make(map[uuid.UUID]map[chan streamMessage]struct{}),
+	}
+}
+
+// Compile-time check to ensure JobStreams implements JobEventPublisher
+var _ JobEventPublisher = (*JobStreams)(nil)
+
+// PublishJobEvent sends an event to every stream watching the job, on any instance.
+func (s *JobStreams) PublishJobEvent(ctx context.Context, jobID uuid.UUID, event Event) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job event: %w", err)
+	}
+
+	if s.redisClient == nil {
+		s.deliver(jobID, streamMessage{eventType: event.Type, payload: payload})
+		return nil
+	}
+
+	msg, err := json.Marshal(jobEnvelope{JobID: jobID, Type: event.Type, Event: payload})
+	if err != nil {
+		return fmt.Errorf("failed to marshal job event envelope: %w", err)
+	}
+	if err := s.redisClient.Publish(ctx, RedisJobEventsChannel, msg).Err(); err != nil {
+		return fmt.Errorf("failed to publish job event: %w", err)
+	}
+	return nil
+}
+
+// Run delivers events published on Redis to this instance's streams until ctx is cancelled.
+// It returns immediately when there is no Redis client.
+func (s *JobStreams) Run(ctx context.Context) {
+	if s.redisClient == nil {
+		return
+	}
+
+	sub := s.redisClient.Subscribe(ctx, RedisJobEventsChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var env jobEnvelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				log.Printf("Job streams: Dropping malformed message: %v", err)
+				continue
+			}
+			s.deliver(env.JobID, streamMessage{eventType: env.Type, payload: env.Event})
+		}
+	}
+}
+
+// ServeSSE streams the job's events to the client as server-sent events, with a keep-alive comment
+// whenever the stream has been idle for a while. It returns once the client disconnects, which
+// cancels the request context, or once the stream is dropped for falling behind.
+func (s *JobStreams) ServeSSE(w http.ResponseWriter, r *http.Request, jobID uuid.UUID) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("response writer does not support streaming")
+	}
+
+	messages, unsubscribe := s.subscribe(jobID)
+	defer unsubscribe()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil // Dropped for being too slow; the client reconnects
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.eventType, msg.payload); err != nil {
+				return err
+			}
+			flusher.Flush()
+			ticker.Reset(s.keepAlive)
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// subscribe adds a stream for the job. The returned function removes it and is safe to call more than once.
+func (s *JobStreams) subscribe(jobID uuid.UUID) (<-chan streamMessage, func()) {
+	ch := make(chan streamMessage, sendBufferSize)
+
+	s.mu.Lock()
+	if s.subscribers[jobID] == nil {
+		s.subscribers[jobID] = make(map[chan streamMessage]struct{})
+	}
+	s.subscribers[jobID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() { s.unsubscribe(jobID, ch) }
+}
+
+// unsubscribe removes a stream and closes its channel. Safe to call more than once.
+func (s *JobStreams) unsubscribe(jobID uuid.UUID, ch chan streamMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	streams, ok := s.subscribers[jobID]
+	if !ok {
+		return
+	}
+	if _, ok := streams[ch]; !ok {
+		return
+	}
+	delete(streams, ch)
+	close(ch)
+	if len(streams) == 0 {
+		delete(s.subscribers, jobID)
+	}
+}
+
+// deliver queues a message on each of the job's local streams.
+// Streams whose buffer is full are dropped rather than blocking everyone else.
+func (s *JobStreams) deliver(jobID uuid.UUID, msg streamMessage) {
+	s.mu.RLock()
+	var slow []chan streamMessage
+	for ch := range s.subscribers[jobID] {
+		select {
+		case ch <- msg:
+		default:
+			slow = append(slow, ch)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, ch := range slow {
+		log.Printf("Job streams: Dropping slow stream for job %s", jobID)
+		s.unsubscribe(jobID, ch)
+	}
+}
@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamCount returns how many streams are watching a job.
+func (s *JobStreams) streamCount(jobID uuid.UUID) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subscribers[jobID])
+}
+
+// sseEvent is one event read off a stream; comments are returned with only comment set.
+type sseEvent struct {
+	event   string
+	data    string
+	comment string
+}
+
+// readSSEEvent reads lines up to the blank line ending the next event or comment.
+func readSSEEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return ev
+		case strings.HasPrefix(line, ":"):
+			ev.comment = strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// openStream starts a test server streaming jobID and connects a client to it.
+func openStream(t *testing.T, streams *JobStreams, jobID uuid.UUID) (*http.Response, context.CancelFunc) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = streams.ServeSSE(w, r, jobID)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Eventually(t, func() bool { return streams.streamCount(jobID) == 1 }, time.Second, 10*time.Millisecond)
+	return resp, cancel
+}
+
+func TestJobStreams_ServeSSEStreamsJobEvents(t *testing.T) {
+	streams := NewJobStreams(nil)
+	jobID := uuid.New()
+	resp, _ := openStream(t, streams, jobID)
+	reader := bufio.NewReader(resp.Body)
+
+	// Another job's event is not delivered, so the first event read is the first invoice's
+	require.NoError(t, streams.PublishJobEvent(context.Background(), uuid.New(), Event{Type: EventInvoiceStateChanged}))
+	for _, state := range []string{"Complete", "Paid"} {
+		require.NoError(t, streams.PublishJobEvent(context.Background(), jobID, Event{
+			Type: EventInvoiceStateChanged,
+			Data: map[string]string{"state": state},
+		}))
+	}
+
+	for _, expectedState := range []string{"Complete", "Paid"} {
+		ev := readSSEEvent(t, reader)
+		assert.Equal(t, EventInvoiceStateChanged, ev.event)
+
+		var got struct {
+			Type      string            `json:"type"`
+			Data      map[string]string `json:"data"`
+			CreatedAt time.Time         `json:"created_at"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(ev.data), &got))
+		assert.Equal(t, EventInvoiceStateChanged, got.Type)
+		assert.Equal(t, expectedState, got.Data["state"])
+		assert.False(t, got.CreatedAt.IsZero(), "created_at should be filled in")
+	}
+}
+
+func TestJobStreams_ServeSSESendsKeepAlive(t *testing.T) {
+	streams := NewJobStreams(nil)
+	streams.keepAlive = 20 * time.Millisecond
+	resp, _ := openStream(t, streams, uuid.New())
+
+	ev := readSSEEvent(t, bufio.NewReader(resp.Body))
+	assert.Equal(t, "keep-alive", ev.comment)
+	assert.Empty(t, ev.event)
+}
+
+func TestJobStreams_UnsubscribesOnClientDisconnect(t *testing.T) {
+	streams := NewJobStreams(nil)
+	jobID := uuid.New()
+	_, cancel := openStream(t, streams, jobID)
+
+	cancel()
+	assert.Eventually(t, func() bool { return streams.streamCount(jobID) == 0 }, time.Second, 10*time.Millisecond)
+}
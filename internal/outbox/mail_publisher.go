@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-api-template/internal/mailer"
+	"go-api-template/internal/models"
+)
+
+// MailPublisher delivers email.requested events, queued by mailer.Queue, through a Mailer and hands
+// every other event to the next Publisher. A failed delivery fails Publish, so the relay retries it.
+type MailPublisher struct {
+	mailer mailer.Mailer
+	next   Publisher
+}
+
+// NewMailPublisher creates a new MailPublisher.
+func NewMailPublisher(m mailer.Mailer, next Publisher) *MailPublisher {
+	return &MailPublisher{mailer: m, next: next}
+}
+
+// Publish sends the event's email, or passes the event on if it isn't one.
+func (p *MailPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	if event.EventType != models.OutboxEventEmailRequested {
+		return p.next.Publish(ctx, event)
+	}
+
+	var email mailer.QueuedEmail
+	if err := json.Unmarshal(event.Payload, &email); err != nil {
+		return fmt.Errorf("failed to decode queued email %d: %w", event.ID, err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(email.Data, &data); err != nil {
+		return fmt.Errorf("failed to decode data of queued email %d: %w", event.ID, err)
+	}
+	return p.mailer.Send(ctx, email.To, email.Template, data)
+}
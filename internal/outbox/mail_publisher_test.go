@@ -0,0 +1,83 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-template/internal/mailer"
+	"go-api-template/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder is a mailer.EventStore keeping the inserted events.
+type eventRecorder struct {
+	events []models.OutboxEvent
+}
+
+func (r *eventRecorder) Insert(ctx context.Context, event *models.OutboxEvent) error {
+	event.ID = int64(len(r.events) + 1)
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// renderingMailer renders every email it is asked to send; err makes Send fail instead.
+type renderingMailer struct {
+	to   []string
+	sent []*mailer.Message
+	err  error
+}
+
+func (m *renderingMailer) Send(ctx context.Context, to, template string, data any) error {
+	if m.err != nil {
+		return m.err
+	}
+	msg, err := mailer.Render(template, data)
+	if err != nil {
+		return err
+	}
+	m.to = append(m.to, to)
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestMailPublisher_DeliversQueuedEmail(t *testing.T) {
+	store := &eventRecorder{}
+	err := mailer.NewQueue(store).Send(context.Background(), "user@example.com", mailer.TemplatePasswordReset, mailer.PasswordResetData{Token: "tok123", ExpiresIn: "1h0m0s"})
+	require.NoError(t, err)
+	require.Len(t, store.events, 1)
+	assert.Equal(t, models.OutboxEventEmailRequested, store.events[0].EventType)
+	assert.Equal(t, models.OutboxAggregateEmail, store.events[0].AggregateType)
+
+	m := &renderingMailer{}
+	next := &recordingPublisher{}
+	require.NoError(t, NewMailPublisher(m, next).Publish(context.Background(), store.events[0]))
+
+	assert.Empty(t, next.deliveredIDs(), "Emails aren't passed on to the broker")
+	require.Len(t, m.sent, 1)
+	assert.Equal(t, []string{"user@example.com"}, m.to)
+	assert.Equal(t, "Password reset", m.sent[0].Subject)
+	assert.Contains(t, m.sent[0].HTML, "tok123")
+	assert.Contains(t, m.sent[0].HTML, "1h0m0s")
+}
+
+func TestMailPublisher_FailedDeliveryFailsPublish(t *testing.T) {
+	store := &eventRecorder{}
+	require.NoError(t, mailer.NewQueue(store).Send(context.Background(), "user@example.com", mailer.TemplateVerifyEmail, mailer.VerifyEmailData{Token: "tok"}))
+
+	err := NewMailPublisher(&renderingMailer{err: errors.New("smtp unavailable")}, &recordingPublisher{}).Publish(context.Background(), store.events[0])
+	assert.Error(t, err, "The relay must keep the email to retry it")
+}
+
+func TestMailPublisher_PassesOtherEventsOn(t *testing.T) {
+	m := &renderingMailer{}
+	next := &recordingPublisher{}
+
+	require.NoError(t, NewMailPublisher(m, next).Publish(context.Background(), newEvent(7, uuid.New())))
+
+	assert.Equal(t, []int64{7}, next.deliveredIDs())
+	assert.Empty(t, m.sent)
+}
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+
+	"go-api-template/internal/models"
+)
+
+// Publisher delivers an event to an external system (message broker, webhook, ...).
+// Delivery is at-least-once, so implementations and their consumers should tolerate duplicates,
+// e.g. by deduplicating on event.ID.
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// LogPublisher is a Publisher that only logs events. Useful until a real broker is wired in.
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+// NewLogPublisher creates a new LogPublisher.
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the event and always succeeds.
+func (p *LogPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	p.logger.Info("Outbox event published",
+		"event_id", event.ID,
+		"event_type", event.EventType,
+		"aggregate_type", event.AggregateType,
+		"aggregate_id", event.AggregateID,
+		"payload", string(event.Payload),
+	)
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package outbox publishes domain events recorded in the outbox table to external systems.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+)
+
+// Store is the subset of storage.OutboxRepository the relay needs.
+type Store interface {
+	ListUnpublished(ctx context.Context, req *dto.ListUnpublishedOutboxEventsRequest) ([]models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, req *dto.MarkOutboxEventPublishedRequest) error
+}
+
+// Relay polls the outbox for unpublished events and hands them to a Publisher.
+//
+// An event is only marked published after Publish succeeds, so delivery is at-least-once: a crash
+// or a failed mark means the event is published again on a later poll. Events of the same aggregate
+// are published in the order they were recorded; once one fails, the rest of that aggregate's events
+// wait for the next poll. Other aggregates are not held up.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewRelay creates a new Relay that checks for events every interval, up to batchSize at a time.
+func NewRelay(store Store, publisher Publisher, interval time.Duration, batchSize int, logger *slog.Logger) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run polls until ctx is cancelled. A full batch is followed immediately by another poll, so a
+// backlog drains without waiting for the interval.
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started", "interval", r.interval, "batch_size", r.batchSize)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.ProcessBatch(ctx)
+		if err != nil {
+			r.logger.Error("Outbox relay: Failed to process batch", "error", err)
+		}
+		if err == nil && published == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch publishes the next batch of unpublished events and returns how many were published.
+// Failures to publish or mark a single event are logged and don't fail the batch.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := r.store.ListUnpublished(ctx, &dto.ListUnpublishedOutboxEventsRequest{Limit: r.batchSize})
+	if err != nil {
+		return 0, err
+	}
+
+	blocked := make(map[uuid.UUID]bool) // Aggregates with an earlier event that wasn't published
+	published := 0
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+		if blocked[event.AggregateID] {
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			r.logger.Warn("Outbox relay: Failed to publish event, will retry", "event_id", event.ID, "event_type", event.EventType, "aggregate_id", event.AggregateID, "error", err)
+			blocked[event.AggregateID] = true
+			continue
+		}
+
+		if err := r.store.MarkPublished(ctx, &dto.MarkOutboxEventPublishedRequest{ID: event.ID}); err != nil {
+			// Already delivered; it will be published again, which at-least-once consumers must handle.
+			// Later events for the aggregate wait so they can't overtake the repeat.
+			r.logger.Error("Outbox relay: Failed to mark event as published", "event_id", event.ID, "error", err)
+			blocked[event.AggregateID] = true
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store; markErrs makes MarkPublished fail for the given event IDs once.
+type memoryStore struct {
+	mu        sync.Mutex
+	events    []models.OutboxEvent
+	published map[int64]bool
+	markErrs  map[int64]bool
+}
+
+func newMemoryStore(events ...models.OutboxEvent) *memoryStore {
+	return &memoryStore{events: events, published: map[int64]bool{}, markErrs: map[int64]bool{}}
+}
+
+func (s *memoryStore) ListUnpublished(ctx context.Context, req *dto.ListUnpublishedOutboxEventsRequest) ([]models.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var unpublished []models.OutboxEvent
+	for _, event := range s.events {
+		if !s.published[event.ID] {
+			unpublished = append(unpublished, event)
+		}
+	}
+	sort.Slice(unpublished, func(i, j int) bool { return unpublished[i].ID < unpublished[j].ID })
+	if len(unpublished) > req.Limit {
+		unpublished = unpublished[:req.Limit]
+	}
+	return unpublished, nil
+}
+
+func (s *memoryStore) MarkPublished(ctx context.Context, req *dto.MarkOutboxEventPublishedRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.markErrs[req.ID] {
+		delete(s.markErrs, req.ID)
+		return errors.New("database unavailable")
+	}
+	s.published[req.ID] = true
+	return nil
+}
+
+// recordingPublisher records every delivery; failures makes Publish fail for an event ID that many times.
+type recordingPublisher struct {
+	mu        sync.Mutex
+	delivered []int64
+	failures  map[int64]int
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures[event.ID] > 0 {
+		p.failures[event.ID]--
+		return errors.New("broker unavailable")
+	}
+	p.delivered = append(p.delivered, event.ID)
+	return nil
+}
+
+func (p *recordingPublisher) deliveredIDs() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int64(nil), p.delivered...)
+}
+
+func newEvent(id int64, aggregateID uuid.UUID) models.OutboxEvent {
+	return models.OutboxEvent{
+		ID:            id,
+		AggregateType: models.OutboxAggregateJob,
+		AggregateID:   aggregateID,
+		EventType:     models.OutboxEventJobStateChanged,
+		Payload:       []byte(`{}`),
+	}
+}
+
+func newTestRelay(store Store, publisher Publisher, batchSize int) *Relay {
+	return NewRelay(store, publisher, 10*time.Millisecond, batchSize, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestRelay_PublishesInOrderAndMarksPublished(t *testing.T) {
+	jobA, jobB := uuid.New(), uuid.New()
+	store := newMemoryStore(newEvent(1, jobA), newEvent(2, jobB), newEvent(3, jobA))
+	publisher := &recordingPublisher{}
+
+	published, err := newTestRelay(store, publisher, 10).ProcessBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Equal(t, []int64{1, 2, 3}, publisher.deliveredIDs())
+
+	// Nothing is delivered twice once marked
+	published, err = newTestRelay(store, publisher, 10).ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, []int64{1, 2, 3}, publisher.deliveredIDs())
+}
+
+func TestRelay_FailedPublishIsRetriedAndKeepsAggregateOrder(t *testing.T) {
+	jobA, jobB := uuid.New(), uuid.New()
+	store := newMemoryStore(newEvent(1, jobA), newEvent(2, jobB), newEvent(3, jobA), newEvent(4, jobB))
+	publisher := &recordingPublisher{failures: map[int64]int{1: 1}}
+	relay := newTestRelay(store, publisher, 10)
+
+	// First pass: event 1 fails, so event 3 (same aggregate) must wait; jobB is unaffected
+	published, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{2, 4}, publisher.deliveredIDs())
+
+	// Second pass: the failed event is retried before its successor
+	published, err = relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{2, 4, 1, 3}, publisher.deliveredIDs())
+}
+
+func TestRelay_FailedMarkRedeliversAtLeastOnce(t *testing.T) {
+	jobA := uuid.New()
+	store := newMemoryStore(newEvent(1, jobA), newEvent(2, jobA))
+	store.markErrs[1] = true
+	publisher := &recordingPublisher{}
+	relay := newTestRelay(store, publisher, 10)
+
+	// Event 1 is delivered but not marked, so event 2 waits to avoid overtaking the redelivery
+	published, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, []int64{1}, publisher.deliveredIDs())
+
+	published, err = relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{1, 1, 2}, publisher.deliveredIDs(), "Event 1 is delivered again, then event 2")
+}
+
+func TestRelay_RunDrainsBacklogAndStops(t *testing.T) {
+	jobA := uuid.New()
+	var events []models.OutboxEvent
+	for id := int64(1); id <= 25; id++ {
+		events = append(events, newEvent(id, jobA))
+	}
+	store := newMemoryStore(events...)
+	publisher := &recordingPublisher{}
+	relay := newTestRelay(store, publisher, 10) // Smaller than the backlog
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return len(publisher.deliveredIDs()) == 25 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Relay did not stop after context cancellation")
+	}
+
+	delivered := publisher.deliveredIDs()
+	assert.True(t, sort.SliceIsSorted(delivered, func(i, j int) bool { return delivered[i] < delivered[j] }), "Events of one aggregate must be delivered in order")
+}
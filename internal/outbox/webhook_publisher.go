@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/webhooks"
+)
+
+// WebhookQueue is the subset of storage.WebhookRepository the webhook publisher needs.
+type WebhookQueue interface {
+	EnqueueDeliveries(ctx context.Context, event models.OutboxEvent, payload []byte) (int, error)
+}
+
+// WebhookPublisher queues a delivery of each job and invoice event for every webhook subscribed to
+// it, then hands the event to the next Publisher. The deliveries themselves are made, and retried,
+// by webhooks.Worker, so a slow or failing endpoint never holds up the relay. A failure to queue
+// fails Publish, so the relay retries the event; webhooks that already got it are skipped then.
+type WebhookPublisher struct {
+	queue WebhookQueue
+	next  Publisher
+}
+
+// NewWebhookPublisher creates a new WebhookPublisher.
+func NewWebhookPublisher(queue WebhookQueue, next Publisher) *WebhookPublisher {
+	return &WebhookPublisher{queue: queue, next: next}
+}
+
+// Publish queues the event's webhook deliveries and passes the event on.
+func (p *WebhookPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	if event.AggregateType == models.OutboxAggregateJob || event.AggregateType == models.OutboxAggregateInvoice {
+		payload, err := webhooks.NewPayload(event)
+		if err != nil {
+			return err
+		}
+		if _, err := p.queue.EnqueueDeliveries(ctx, event, payload); err != nil {
+			return fmt.Errorf("failed to queue webhook deliveries for event %d: %w", event.ID, err)
+		}
+	}
+	return p.next.Publish(ctx, event)
+}
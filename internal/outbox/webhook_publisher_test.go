@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/webhooks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookQueueRecorder is a WebhookQueue keeping the queued payloads by event ID; err makes it fail instead.
+type webhookQueueRecorder struct {
+	payloads map[int64][]byte
+	err      error
+}
+
+func (q *webhookQueueRecorder) EnqueueDeliveries(ctx context.Context, event models.OutboxEvent, payload []byte) (int, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	if q.payloads == nil {
+		q.payloads = map[int64][]byte{}
+	}
+	q.payloads[event.ID] = payload
+	return 1, nil
+}
+
+func TestWebhookPublisher_QueuesJobAndInvoiceEvents(t *testing.T) {
+	queue := &webhookQueueRecorder{}
+	next := &recordingPublisher{}
+	publisher := NewWebhookPublisher(queue, next)
+
+	jobEvent := newEvent(1, uuid.New())
+	invoiceEvent := models.OutboxEvent{ID: 2, AggregateType: models.OutboxAggregateInvoice, AggregateID: uuid.New(), EventType: models.OutboxEventInvoiceStateChanged, Payload: []byte(`{"state":"Paid"}`)}
+	emailEvent := models.OutboxEvent{ID: 3, AggregateType: models.OutboxAggregateEmail, AggregateID: uuid.New(), EventType: models.OutboxEventEmailRequested, Payload: []byte(`{}`)}
+	for _, event := range []models.OutboxEvent{jobEvent, invoiceEvent, emailEvent} {
+		require.NoError(t, publisher.Publish(context.Background(), event))
+	}
+
+	assert.Equal(t, []int64{1, 2, 3}, next.deliveredIDs(), "Every event is passed on")
+	require.Contains(t, queue.payloads, int64(2))
+	assert.NotContains(t, queue.payloads, int64(3), "Emails are never sent to webhooks")
+	var payload webhooks.Payload
+	require.NoError(t, json.Unmarshal(queue.payloads[2], &payload))
+	assert.Equal(t, models.OutboxEventInvoiceStateChanged, payload.Type)
+	assert.Equal(t, invoiceEvent.AggregateID, payload.AggregateID)
+	assert.JSONEq(t, `{"state":"Paid"}`, string(payload.Data))
+}
+
+func TestWebhookPublisher_QueueFailureFailsPublish(t *testing.T) {
+	next := &recordingPublisher{}
+
+	err := NewWebhookPublisher(&webhookQueueRecorder{err: errors.New("database unavailable")}, next).Publish(context.Background(), newEvent(1, uuid.New()))
+
+	assert.Error(t, err, "The relay must keep the event to queue it again")
+	assert.Empty(t, next.deliveredIDs())
+}
@@ -0,0 +1,89 @@
+// Package scheduler runs periodic background maintenance jobs.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+)
+
+// JobArchiveStore is the subset of services.JobService the archiver needs.
+type JobArchiveStore interface {
+	ArchiveStaleJobs(ctx context.Context, req *dto.ArchiveStaleJobsRequest) (int, error)
+}
+
+// JobArchiver periodically moves jobs that have been Complete for longer than maxAge to Archived.
+// The transition itself goes through the job service, so it gets the same state validation and
+// domain event as a manual archive.
+type JobArchiver struct {
+	store     JobArchiveStore
+	interval  time.Duration
+	maxAge    time.Duration
+	batchSize int
+	logger    *slog.Logger
+	lock      SingletonLock    // Nil runs every sweep, for a single instance
+	now       func() time.Time // Replaced in tests
+}
+
+// NewJobArchiver creates a new JobArchiver that sweeps every interval, archiving up to batchSize jobs
+// completed more than maxAge ago at a time.
+func NewJobArchiver(store JobArchiveStore, interval, maxAge time.Duration, batchSize int, logger *slog.Logger) *JobArchiver {
+	return &JobArchiver{
+		store:     store,
+		interval:  interval,
+		maxAge:    maxAge,
+		batchSize: batchSize,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// WithLock makes each sweep take the ArchiverLockName lock first and skip if another instance has
+// it, so running several instances doesn't archive the same jobs several times over.
+func (a *JobArchiver) WithLock(lock SingletonLock) *JobArchiver {
+	a.lock = lock
+	return a
+}
+
+// Run sweeps until ctx is cancelled. A full batch is followed immediately by another sweep, so a
+// backlog drains without waiting for the interval.
+func (a *JobArchiver) Run(ctx context.Context) {
+	a.logger.Info("Job archiver started", "interval", a.interval, "max_age", a.maxAge, "batch_size", a.batchSize)
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		archived, err := a.Sweep(ctx)
+		if err != nil && ctx.Err() == nil {
+			a.logger.Error("Job archiver: Failed to sweep", "error", err)
+		}
+		if err == nil && archived == a.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Job archiver stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep archives the next batch of stale completed jobs and returns how many were archived. Nothing
+// is archived while another instance holds the lock.
+func (a *JobArchiver) Sweep(ctx context.Context) (int, error) {
+	return sweepLocked(ctx, a.lock, ArchiverLockName, a.logger, a.sweep)
+}
+
+func (a *JobArchiver) sweep(ctx context.Context) (int, error) {
+	cutoff := a.now().Add(-a.maxAge)
+	archived, err := a.store.ArchiveStaleJobs(ctx, &dto.ArchiveStaleJobsRequest{CompletedBefore: cutoff, Limit: a.batchSize})
+	if err != nil {
+		return archived, err
+	}
+	a.logger.Info("Job archiver: Sweep finished", "archived", archived, "completed_before", cutoff)
+	return archived, nil
+}
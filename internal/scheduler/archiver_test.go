@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryArchiveStore archives from an in-memory list of completion times, recording each request.
+type memoryArchiveStore struct {
+	mu        sync.Mutex
+	completed []time.Time // Completion times of jobs not archived yet
+	requests  []dto.ArchiveStaleJobsRequest
+}
+
+func (s *memoryArchiveStore) ArchiveStaleJobs(ctx context.Context, req *dto.ArchiveStaleJobsRequest) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, *req)
+	var remaining []time.Time
+	archived := 0
+	for _, completedAt := range s.completed {
+		if archived < req.Limit && completedAt.Before(req.CompletedBefore) {
+			archived++
+			continue
+		}
+		remaining = append(remaining, completedAt)
+	}
+	s.completed = remaining
+	return archived, nil
+}
+
+func (s *memoryArchiveStore) remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.completed)
+}
+
+func newTestArchiver(store JobArchiveStore, maxAge time.Duration, batchSize int, now time.Time) *JobArchiver {
+	archiver := NewJobArchiver(store, 10*time.Millisecond, maxAge, batchSize, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	archiver.now = func() time.Time { return now }
+	return archiver
+}
+
+func TestJobArchiver_SweepUsesMaxAgeCutoff(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	maxAge := 30 * 24 * time.Hour
+	store := &memoryArchiveStore{completed: []time.Time{
+		now.Add(-40 * 24 * time.Hour), // Stale
+		now.Add(-24 * time.Hour),      // Recent
+	}}
+
+	archived, err := newTestArchiver(store, maxAge, 10, now).Sweep(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+	assert.Equal(t, 1, store.remaining(), "The recently completed job must stay")
+	require.Len(t, store.requests, 1)
+	assert.True(t, store.requests[0].CompletedBefore.Equal(now.Add(-maxAge)))
+	assert.Equal(t, 10, store.requests[0].Limit)
+}
+
+func TestJobArchiver_RunDrainsBacklogAndStops(t *testing.T) {
+	now := time.Now()
+	store := &memoryArchiveStore{}
+	for i := 0; i < 25; i++ {
+		store.completed = append(store.completed, now.Add(-48*time.Hour))
+	}
+	archiver := newTestArchiver(store, 24*time.Hour, 10, now) // Smaller than the backlog
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		archiver.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return store.remaining() == 0 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Archiver did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+)
+
+// AutoInvoiceStore is the subset of services.InvoiceService the auto-invoicer needs.
+type AutoInvoiceStore interface {
+	GenerateDueAutoInvoices(ctx context.Context, req *dto.GenerateDueAutoInvoicesRequest) (int, error)
+}
+
+// AutoInvoicer periodically creates the next interval invoice of Ongoing jobs that have automatic
+// invoicing on and whose next invoice is due. Invoices go through the invoice service, so they are
+// numbered, valued and announced exactly like ones a contractor creates.
+type AutoInvoicer struct {
+	store     AutoInvoiceStore
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+	lock      SingletonLock    // Nil runs every sweep, for a single instance
+	now       func() time.Time // Replaced in tests
+}
+
+// NewAutoInvoicer creates a new AutoInvoicer that sweeps every interval, invoicing up to batchSize
+// jobs at a time.
+func NewAutoInvoicer(store AutoInvoiceStore, interval time.Duration, batchSize int, logger *slog.Logger) *AutoInvoicer {
+	return &AutoInvoicer{
+		store:     store,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// WithLock makes each sweep take the AutoInvoicerLockName lock first and skip if another instance
+// has it, so running several instances doesn't have them race for the same due invoices.
+func (a *AutoInvoicer) WithLock(lock SingletonLock) *AutoInvoicer {
+	a.lock = lock
+	return a
+}
+
+// Run sweeps until ctx is cancelled. A full batch is followed immediately by another sweep, so a
+// backlog drains without waiting for the interval.
+func (a *AutoInvoicer) Run(ctx context.Context) {
+	a.logger.Info("Auto-invoicer started", "interval", a.interval, "batch_size", a.batchSize)
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := a.Sweep(ctx)
+		if err != nil && ctx.Err() == nil {
+			a.logger.Error("Auto-invoicer: Failed to sweep", "error", err)
+		}
+		if err == nil && created == a.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Auto-invoicer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep invoices the next batch of jobs that are due and returns how many invoices were created.
+// Nothing is invoiced while another instance holds the lock.
+func (a *AutoInvoicer) Sweep(ctx context.Context) (int, error) {
+	return sweepLocked(ctx, a.lock, AutoInvoicerLockName, a.logger, a.sweep)
+}
+
+func (a *AutoInvoicer) sweep(ctx context.Context) (int, error) {
+	dueBefore := a.now()
+	created, err := a.store.GenerateDueAutoInvoices(ctx, &dto.GenerateDueAutoInvoicesRequest{DueBefore: dueBefore, Limit: a.batchSize})
+	if err != nil {
+		return created, err
+	}
+	a.logger.Info("Auto-invoicer: Sweep finished", "created", created, "due_before", dueBefore)
+	return created, nil
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autoInvoicedJob is a job with automatic invoicing on, as seen by memoryAutoInvoiceStore.
+type autoInvoicedJob struct {
+	nextAt       time.Time
+	cadence      time.Duration
+	invoiced     int // Intervals invoiced so far
+	maxIntervals int
+}
+
+// memoryAutoInvoiceStore invoices in-memory jobs the way the invoice service does: one interval per due
+// job per call, moving its due time on by the cadence and never past the last interval.
+type memoryAutoInvoiceStore struct {
+	mu       sync.Mutex
+	jobs     []*autoInvoicedJob
+	requests []dto.GenerateDueAutoInvoicesRequest
+}
+
+func (s *memoryAutoInvoiceStore) GenerateDueAutoInvoices(ctx context.Context, req *dto.GenerateDueAutoInvoicesRequest) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, *req)
+	created := 0
+	for _, job := range s.jobs {
+		if created == req.Limit || job.nextAt.After(req.DueBefore) || job.invoiced >= job.maxIntervals {
+			continue
+		}
+		job.invoiced++
+		job.nextAt = job.nextAt.Add(job.cadence)
+		created++
+	}
+	return created, nil
+}
+
+func (s *memoryAutoInvoiceStore) invoiced() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make([]int, len(s.jobs))
+	for i, job := range s.jobs {
+		counts[i] = job.invoiced
+	}
+	return counts
+}
+
+func newTestAutoInvoicer(store AutoInvoiceStore, batchSize int, now func() time.Time) *AutoInvoicer {
+	invoicer := NewAutoInvoicer(store, 10*time.Millisecond, batchSize, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	invoicer.now = now
+	return invoicer
+}
+
+func TestAutoInvoicer_SweepCreatesOneInvoiceWhenDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	store := &memoryAutoInvoiceStore{jobs: []*autoInvoicedJob{
+		{nextAt: now.Add(-time.Hour), cadence: week, maxIntervals: 4},              // Due
+		{nextAt: now.Add(time.Hour), cadence: week, maxIntervals: 4},               // Not due yet
+		{nextAt: now.Add(-time.Hour), cadence: week, invoiced: 4, maxIntervals: 4}, // Caught up
+	}}
+	invoicer := newTestAutoInvoicer(store, 10, func() time.Time { return now })
+
+	created, err := invoicer.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, []int{1, 0, 4}, store.invoiced())
+	require.Len(t, store.requests, 1)
+	assert.True(t, store.requests[0].DueBefore.Equal(now))
+	assert.Equal(t, 10, store.requests[0].Limit)
+
+	// The due job moved on by its cadence, so sweeping again within the week creates nothing
+	created, err = invoicer.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, []int{1, 0, 4}, store.invoiced())
+}
+
+func TestAutoInvoicer_SweepCreatesNothingWhenCaughtUp(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryAutoInvoiceStore{jobs: []*autoInvoicedJob{
+		{nextAt: now.Add(-time.Hour), cadence: time.Hour, invoiced: 2, maxIntervals: 2},
+	}}
+
+	created, err := newTestAutoInvoicer(store, 10, func() time.Time { return now }).Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, []int{2}, store.invoiced())
+}
+
+func TestAutoInvoicer_RunDrainsBacklogAndStops(t *testing.T) {
+	now := time.Now()
+	store := &memoryAutoInvoiceStore{}
+	for i := 0; i < 25; i++ {
+		store.jobs = append(store.jobs, &autoInvoicedJob{nextAt: now.Add(-time.Minute), cadence: 24 * time.Hour, maxIntervals: 3})
+	}
+	invoicer := newTestAutoInvoicer(store, 10, func() time.Time { return now }) // Smaller than the backlog
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		invoicer.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		for _, count := range store.invoiced() {
+			if count != 1 {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Auto-invoicer did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Names of the locks the schedulers take before each sweep.
+const (
+	ArchiverLockName     = "scheduler:archiver"
+	AutoInvoicerLockName = "scheduler:auto_invoicer"
+	PurgerLockName       = "scheduler:purger"
+)
+
+// SingletonLock lets only one of several instances run a sweep at a time. lock.Locker implements it.
+type SingletonLock interface {
+	// Do runs fn while holding the lock called name, and reports false without running it if
+	// another instance holds the lock.
+	Do(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// sweepLocked runs sweep while holding the lock called name, or right away when lock is nil. It
+// returns what sweep did, or nothing if another instance holds the lock and is doing the work.
+func sweepLocked(ctx context.Context, lock SingletonLock, name string, logger *slog.Logger, sweep func(ctx context.Context) (int, error)) (int, error) {
+	if lock == nil {
+		return sweep(ctx)
+	}
+
+	var done int
+	ran, err := lock.Do(ctx, name, func(ctx context.Context) error {
+		var err error
+		done, err = sweep(ctx)
+		return err
+	})
+	if !ran && err == nil {
+		logger.Debug("Scheduler: Skipping sweep, another instance holds the lock", "lock", name)
+	}
+	return done, err
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLock is a SingletonLock held in memory, shared by the schedulers of several "instances".
+type memoryLock struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *memoryLock) Do(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	l.mu.Lock()
+	if l.held[name] {
+		l.mu.Unlock()
+		return false, nil
+	}
+	if l.held == nil {
+		l.held = map[string]bool{}
+	}
+	l.held[name] = true
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.held, name)
+		l.mu.Unlock()
+	}()
+	return true, fn(ctx)
+}
+
+func TestJobArchiver_SweepSkipsWhileAnotherInstanceHoldsTheLock(t *testing.T) {
+	now := time.Now()
+	lock := &memoryLock{}
+	store := &memoryArchiveStore{completed: []time.Time{now.Add(-48 * time.Hour), now.Add(-48 * time.Hour)}}
+	first := newTestArchiver(store, 24*time.Hour, 10, now).WithLock(lock)
+	second := newTestArchiver(store, 24*time.Hour, 10, now).WithLock(lock)
+
+	var archivedBySecond int
+	_, err := sweepLocked(context.Background(), lock, ArchiverLockName, first.logger, func(ctx context.Context) (int, error) {
+		// first is mid-sweep when second's turn comes
+		var err error
+		archivedBySecond, err = second.Sweep(ctx)
+		require.NoError(t, err)
+		return first.sweep(ctx)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, archivedBySecond, "The instance without the lock must not sweep")
+	assert.Len(t, store.requests, 1)
+	assert.Equal(t, 0, store.remaining())
+
+	archived, err := second.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, archived)
+	assert.Len(t, store.requests, 2, "Once released, the lock can be taken by the other instance")
+}
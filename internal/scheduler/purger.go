@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+)
+
+// JobPurgeStore is the subset of services.JobService the purger needs.
+type JobPurgeStore interface {
+	PurgeArchivedJobs(ctx context.Context, req *dto.PurgeArchivedJobsRequest) (*dto.PurgeArchivedJobsResult, error)
+}
+
+// JobPurger periodically deletes jobs that have been Archived for longer than retention, with their paid
+// invoices, for good. It is the data-retention counterpart of JobArchiver and is off unless configured.
+type JobPurger struct {
+	store     JobPurgeStore
+	interval  time.Duration
+	retention time.Duration
+	batchSize int
+	logger    *slog.Logger
+	lock      SingletonLock    // Nil runs every sweep, for a single instance
+	now       func() time.Time // Replaced in tests
+}
+
+// NewJobPurger creates a new JobPurger that sweeps every interval, purging up to batchSize jobs archived
+// more than retention ago at a time.
+func NewJobPurger(store JobPurgeStore, interval, retention time.Duration, batchSize int, logger *slog.Logger) *JobPurger {
+	return &JobPurger{
+		store:     store,
+		interval:  interval,
+		retention: retention,
+		batchSize: batchSize,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// WithLock makes each sweep take the PurgerLockName lock first and skip if another instance has it.
+func (p *JobPurger) WithLock(lock SingletonLock) *JobPurger {
+	p.lock = lock
+	return p
+}
+
+// Run sweeps until ctx is cancelled. A full batch is followed immediately by another sweep, so a
+// backlog drains without waiting for the interval.
+func (p *JobPurger) Run(ctx context.Context) {
+	p.logger.Info("Job purger started", "interval", p.interval, "retention", p.retention, "batch_size", p.batchSize)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.Sweep(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.logger.Error("Job purger: Failed to sweep", "error", err)
+		}
+		if err == nil && purged == p.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Job purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep purges the next batch of expired archived jobs and returns how many were purged. Nothing is
+// purged while another instance holds the lock.
+func (p *JobPurger) Sweep(ctx context.Context) (int, error) {
+	return sweepLocked(ctx, p.lock, PurgerLockName, p.logger, p.sweep)
+}
+
+func (p *JobPurger) sweep(ctx context.Context) (int, error) {
+	cutoff := p.now().Add(-p.retention)
+	result, err := p.store.PurgeArchivedJobs(ctx, &dto.PurgeArchivedJobsRequest{ArchivedBefore: cutoff, Limit: p.batchSize})
+	if err != nil {
+		return 0, err
+	}
+	p.logger.Info("Job purger: Sweep finished", "jobs", result.Jobs, "invoices", result.Invoices, "archived_before", cutoff)
+	return result.Jobs, nil
+}
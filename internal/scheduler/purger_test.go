@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/transport/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPurgeStore purges from an in-memory list of archive times, each job having one paid invoice,
+// recording each request.
+type memoryPurgeStore struct {
+	mu       sync.Mutex
+	archived []time.Time // Archive times of jobs not purged yet
+	requests []dto.PurgeArchivedJobsRequest
+}
+
+func (s *memoryPurgeStore) PurgeArchivedJobs(ctx context.Context, req *dto.PurgeArchivedJobsRequest) (*dto.PurgeArchivedJobsResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, *req)
+	var remaining []time.Time
+	purged := 0
+	for _, archivedAt := range s.archived {
+		if purged < req.Limit && archivedAt.Before(req.ArchivedBefore) {
+			purged++
+			continue
+		}
+		remaining = append(remaining, archivedAt)
+	}
+	s.archived = remaining
+	return &dto.PurgeArchivedJobsResult{Jobs: purged, Invoices: purged}, nil
+}
+
+func (s *memoryPurgeStore) remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.archived)
+}
+
+func newTestPurger(store JobPurgeStore, retention time.Duration, batchSize int, now time.Time) *JobPurger {
+	purger := NewJobPurger(store, 10*time.Millisecond, retention, batchSize, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	purger.now = func() time.Time { return now }
+	return purger
+}
+
+func TestJobPurger_SweepUsesRetentionCutoff(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	retention := 365 * 24 * time.Hour
+	store := &memoryPurgeStore{archived: []time.Time{
+		now.Add(-400 * 24 * time.Hour), // Past retention
+		now.Add(-30 * 24 * time.Hour),  // Recent
+	}}
+
+	purged, err := newTestPurger(store, retention, 10, now).Sweep(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, store.remaining(), "The recently archived job must stay")
+	require.Len(t, store.requests, 1)
+	assert.True(t, store.requests[0].ArchivedBefore.Equal(now.Add(-retention)))
+	assert.Equal(t, 10, store.requests[0].Limit)
+}
+
+func TestJobPurger_RunDrainsBacklogAndStops(t *testing.T) {
+	now := time.Now()
+	store := &memoryPurgeStore{}
+	for i := 0; i < 25; i++ {
+		store.archived = append(store.archived, now.Add(-48*time.Hour))
+	}
+	purger := newTestPurger(store, 24*time.Hour, 10, now) // Smaller than the backlog
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		purger.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return store.remaining() == 0 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Purger did not stop after context cancellation")
+	}
+}
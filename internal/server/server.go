@@ -1,27 +1,63 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"time"
 
+	"go-api-template/internal/api/middleware"
 	"go-api-template/internal/api/routes"
 	"go-api-template/internal/app"
+	"go-api-template/internal/services"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
 )
 
 type Server struct {
-	router *gin.Engine
-	app    *app.Application // Store the application container
+	router     *gin.Engine
+	app        *app.Application // Store the application container
+	httpServer *http.Server
+	inFlight   *middleware.InFlight // Requests being served; Shutdown waits for them
 }
 
 func NewServer(app *app.Application) *Server {
-	router := gin.Default()
+	inFlight := middleware.NewInFlight()
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.TrackInFlight(inFlight))
+	router.Use(middleware.RequestID(app.Logger)) // Must run before anything that logs per request
+
+	// --- Client IP ---
+	// X-Forwarded-For is only believed from the configured proxies, so clients can't pick the IP rate limits key on
+	trustedProxies, err := middleware.ParseTrustedProxies(app.Config.Server.TrustedProxies)
+	if err != nil {
+		app.Logger.Error("Ignoring trusted proxies", "error", err) // Not reached: config.Validate rejects them
+	}
+	if err := router.SetTrustedProxies(app.Config.Server.TrustedProxies); err != nil {
+		app.Logger.Error("Failed to set gin's trusted proxies", "error", err)
+	}
+	router.RemoteIPHeaders = []string{"X-Forwarded-For"} // So c.ClientIP() agrees with middleware.GetClientIP
+	router.Use(middleware.ResolveClientIP(trustedProxies))
+	if app.Config.Tracing.OTLPEndpoint != "" {
+		router.Use(middleware.Tracing(otel.GetTracerProvider(), otel.GetTextMapPropagator())) // Tags the request logger with the trace ID
+	}
+	router.Use(middleware.AccessLog(middleware.AccessLogConfig{LogBodies: app.Config.Log.Bodies, RedactKeys: app.Config.Log.RedactKeys}))
+
+	// --- Metrics ---
+	if app.Config.Metrics.Enabled {
+		registerCollectors(app, middleware.MetricsCollectors()...)
+		registerCollectors(app, services.MetricsCollectors()...)
+		router.Use(middleware.Metrics())
+	}
 	
 	// --- Configure and Apply CORS Middleware ---
-	log.Printf("Configuring CORS for origins: %v", app.Config.CORS.AllowedOrigins)
+	app.Logger.Info("Configuring CORS", "allowed_origins", app.Config.CORS.AllowedOrigins)
 	corsConfig := cors.Config{
 		// AllowOrigins: app.Config.CORS.AllowedOrigins, // Use specific origins from config
 		AllowOriginFunc: func(origin string) bool {
@@ -34,8 +70,8 @@ func NewServer(app *app.Application) *Server {
 			return false
 		},
 		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}, // Common methods
-		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"}, // Common headers
-		ExposeHeaders:    []string{"Content-Length"}, // Headers the browser is allowed to access
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "Idempotency-Key"}, // Common headers
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "Idempotent-Replayed", "X-Total-Count", "Link"}, // Headers the browser is allowed to access
 		AllowCredentials: true, // Allow cookies to be sent (if your frontend needs it)
 		// AllowAllOrigins: true, // Alternative: Use this for very permissive CORS (less secure)
 		MaxAge: 12 * time.Hour, // How long the result of a preflight request can be cached
@@ -43,19 +79,88 @@ func NewServer(app *app.Application) *Server {
 	router.Use(cors.New(corsConfig))
 	// --- End CORS Configuration ---
 
-	router.SetTrustedProxies(nil) // Remove the gin warning about untrusted proxies
+	// Runs outside the timeout so a timed-out request's 503 goes through it like any other response
+	if app.Config.Server.Compression {
+		router.Use(middleware.Compression(app.Config.Server.CompressionMinBytes))
+	}
+
+	// --- Request Limits ---
+	// The timeout runs first so that reading a slow body counts against it
+	if timeout := app.Config.Server.RequestTimeout; timeout > 0 {
+		router.Use(middleware.Timeout(timeout))
+	}
+	if maxBytes := app.Config.Server.MaxBodyBytes; maxBytes > 0 {
+		router.Use(middleware.MaxBodyBytes(maxBytes))
+	}
+
+	// Probes are registered up front and outside any auth group so orchestrators can always reach them
+	routes.RegisterHealthRoutes(router, app, inFlight)
 
+	addr := fmt.Sprintf("%s:%d", app.Config.Server.Host, app.Config.Server.Port) // Get config from container
 	return &Server{
-		router: router,
-		app:    app,
+		router:     router,
+		app:        app,
+		httpServer: &http.Server{Addr: addr, Handler: router},
+		inFlight:   inFlight,
+	}
+}
+
+// registerCollectors adds collectors to the default Prometheus registry.
+// Collectors are package-level, so a second server in the same process reuses the existing registration.
+func registerCollectors(app *app.Application, collectors ...prometheus.Collector) {
+	for _, collector := range collectors {
+		if err := prometheus.Register(collector); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if !errors.As(err, &alreadyRegistered) {
+				app.Logger.Error("Failed to register metrics collector", "error", err)
+			}
+		}
 	}
 }
 
+// startMetricsServer serves metrics on their own port so they aren't exposed alongside the public API.
+func (s *Server) startMetricsServer() {
+	cfg := s.app.Config.Metrics
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, promhttp.Handler())
+
+	addr := fmt.Sprintf("%s:%d", s.app.Config.Server.Host, cfg.Port)
+	go func() {
+		s.app.Logger.Info("Metrics server starting", "addr", addr, "path", cfg.Path)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			s.app.Logger.Error("Metrics server error", "error", err)
+		}
+	}()
+}
+
 func (s *Server) Start() error {
 	// Pass the container to routes
 	routes.RegisterRoutes(s.router, s.app)
 
-	addr := fmt.Sprintf("%s:%d", s.app.Config.Server.Host, s.app.Config.Server.Port) // Get config from container
-	log.Printf("Server starting on %s", addr)
-	return s.router.Run(addr)
+	if s.app.Config.Metrics.Enabled {
+		if s.app.Config.Metrics.Port != 0 {
+			s.startMetricsServer()
+		} else {
+			s.router.GET(s.app.Config.Metrics.Path, gin.WrapH(promhttp.Handler()))
+		}
+	}
+
+	s.app.Logger.Info("Server starting", "addr", s.httpServer.Addr)
+	if err := s.httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections and gives in-flight requests until ctx is done to finish, then
+// closes the connections that are left. Streams (WebSockets, server-sent events) only end when the client
+// disconnects, so they are usually the ones cut off.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.app.Logger.Info("Draining in-flight requests", "in_flight", s.inFlight.Count())
+	if err := s.inFlight.Drain(ctx, s.httpServer); err != nil {
+		s.app.Logger.Warn("Drain timed out, closed remaining connections", "in_flight", s.inFlight.Count(), "error", err)
+		return err
+	}
+	s.app.Logger.Info("All in-flight requests finished")
+	return nil
 }
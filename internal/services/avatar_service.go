@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/cache"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/filestore"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// avatarExtensions lists the accepted avatar content types and the extension they are stored with.
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type avatarService struct {
+	userRepo storage.UserRepository
+	store    filestore.Storage
+}
+
+// NewAvatarService creates a new instance of AvatarService, keeping uploaded pictures in store.
+func NewAvatarService(db *pgxpool.Pool, store filestore.Storage, readCache cache.Config) AvatarService {
+	return &avatarService{
+		userRepo: readCache.UserRepository(postgres.NewUserRepo(db)),
+		store:    store,
+	}
+}
+
+// SetAvatar stores the uploaded picture under a fresh name and points the user at it. The previous
+// picture is deleted once the user row is updated; failing to delete it only leaves an orphaned file.
+func (s *avatarService) SetAvatar(ctx context.Context, req *dto.UploadAvatarRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AvatarService.SetAvatar")
+	defer span.End()
+
+	ext, ok := avatarExtensions[req.ContentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported avatar content type %q", ErrValidation, req.ContentType)
+	}
+
+	current, err := s.userRepo.GetByID(ctx, &dto.GetUserByIdRequest{ID: req.UserID})
+	if err != nil {
+		return nil, mapRepoError(err, "getting user to set avatar")
+	}
+
+	// A new name per upload, so clients and proxies never serve a stale cached picture
+	url, err := s.store.Put(ctx, req.UserID.String()+"-"+uuid.NewString()+ext, req.ContentType, req.Data)
+	if err != nil {
+		logger.FromContext(ctx).Error("SetAvatar: Error storing avatar", "user_id", req.UserID, "error", err)
+		return nil, fmt.Errorf("storing avatar: %w", err)
+	}
+
+	user, err := s.userRepo.SetAvatarURL(ctx, &dto.SetUserAvatarRequest{ID: req.UserID, AvatarURL: &url})
+	if err != nil {
+		s.deleteFile(ctx, url)
+		return nil, mapRepoError(err, "setting user avatar")
+	}
+
+	if current.AvatarURL != nil {
+		s.deleteFile(ctx, *current.AvatarURL)
+	}
+	logger.FromContext(ctx).Info("Avatar updated", "user_id", req.UserID, "avatar_url", url)
+	return user, nil
+}
+
+// DeleteAvatar clears the user's avatar and deletes the stored picture. Clearing an unset avatar is a no-op.
+func (s *avatarService) DeleteAvatar(ctx context.Context, req *dto.DeleteAvatarRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AvatarService.DeleteAvatar")
+	defer span.End()
+
+	current, err := s.userRepo.GetByID(ctx, &dto.GetUserByIdRequest{ID: req.UserID})
+	if err != nil {
+		return nil, mapRepoError(err, "getting user to delete avatar")
+	}
+
+	user, err := s.userRepo.SetAvatarURL(ctx, &dto.SetUserAvatarRequest{ID: req.UserID})
+	if err != nil {
+		return nil, mapRepoError(err, "clearing user avatar")
+	}
+
+	if current.AvatarURL != nil {
+		s.deleteFile(ctx, *current.AvatarURL)
+		logger.FromContext(ctx).Info("Avatar deleted", "user_id", req.UserID)
+	}
+	return user, nil
+}
+
+// deleteFile removes a picture no user points at anymore. Failures are only logged, since the request itself succeeded.
+func (s *avatarService) deleteFile(ctx context.Context, url string) {
+	if err := s.store.Delete(context.WithoutCancel(ctx), url); err != nil {
+		logger.FromContext(ctx).Warn("Error deleting avatar file", "avatar_url", url, "error", err)
+	}
+}
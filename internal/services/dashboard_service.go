@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/sync/errgroup"
+)
+
+// Names of the contractor dashboard sections, as reported in FailedSections.
+const (
+	DashboardSectionActiveJobs          = "active_jobs"
+	DashboardSectionPendingApplications = "pending_applications"
+	DashboardSectionUnpaidInvoices      = "unpaid_invoices"
+)
+
+const dashboardPageSize = 10 // Jobs and applications listed per dashboard section
+
+type dashboardService struct {
+	jobRepo     storage.JobRepository
+	appRepo     storage.JobApplicationRepository
+	invoiceRepo storage.InvoiceRepository
+	skillRepo   storage.SkillRepository
+}
+
+// NewDashboardService creates a new instance of DashboardService.
+func NewDashboardService(db *pgxpool.Pool) DashboardService {
+	return &dashboardService{
+		jobRepo:     postgres.NewJobRepo(db),
+		appRepo:     postgres.NewJobApplicationRepo(db),
+		invoiceRepo: postgres.NewInvoiceRepo(db),
+		skillRepo:   postgres.NewSkillRepo(db),
+	}
+}
+
+// GetContractorDashboard loads the user's ongoing jobs, waiting applications and unpaid invoice totals
+// concurrently. Every query filters on req.UserID as the contractor, so users only ever see their own data.
+// A section that fails is left empty and named in FailedSections rather than failing the dashboard;
+// an error is only returned if every section failed or the request was cancelled.
+func (s *dashboardService) GetContractorDashboard(ctx context.Context, req *dto.ContractorDashboardRequest) (*models.ContractorDashboard, error) {
+	ctx, span := tracing.Start(ctx, "DashboardService.GetContractorDashboard")
+	defer span.End()
+
+	dashboard := &models.ContractorDashboard{
+		ActiveJobs:          []models.Job{},
+		PendingApplications: []models.JobApplication{},
+		UnpaidInvoices:      models.UnpaidInvoiceTotals{Total: map[string]float64{}, OverdueTotal: map[string]float64{}},
+		FailedSections:      []string{},
+	}
+
+	// Each section only writes its own dashboard fields and slot in errs, so no locking is needed
+	sections := []struct {
+		name string
+		load func(ctx context.Context) error
+	}{
+		{DashboardSectionActiveJobs, func(ctx context.Context) error {
+			state := models.JobStateOngoing
+			listReq := &dto.ListJobsByContractorRequest{ContractorID: req.UserID, State: &state, Limit: dashboardPageSize}
+			jobs, err := s.jobRepo.ListByContractor(ctx, listReq)
+			if err != nil {
+				return err
+			}
+			total, err := s.jobRepo.CountByContractor(ctx, listReq)
+			if err != nil {
+				return err
+			}
+			if err := attachJobSkills(ctx, s.skillRepo, jobPointers(jobs)...); err != nil {
+				return err
+			}
+			dashboard.ActiveJobs, dashboard.TotalActiveJobs = jobs, total
+			return nil
+		}},
+		{DashboardSectionPendingApplications, func(ctx context.Context) error {
+			state := models.JobApplicationWaiting
+			listReq := &dto.ListJobApplicationsByContractorRequest{ContractorID: req.UserID, State: &state, Limit: dashboardPageSize}
+			applications, err := s.appRepo.ListByContractor(ctx, listReq)
+			if err != nil {
+				return err
+			}
+			total, err := s.appRepo.CountByContractor(ctx, listReq)
+			if err != nil {
+				return err
+			}
+			dashboard.PendingApplications, dashboard.TotalPendingApplications = applications, total
+			return nil
+		}},
+		{DashboardSectionUnpaidInvoices, func(ctx context.Context) error {
+			totals, err := s.invoiceRepo.SumUnpaidByContractor(ctx, req)
+			if err != nil {
+				return err
+			}
+			dashboard.UnpaidInvoices = *totals
+			return nil
+		}},
+	}
+
+	errs := make([]error, len(sections))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, section := range sections {
+		g.Go(func() error {
+			errs[i] = section.load(gctx)
+			// A failed section doesn't stop the others; only a cancelled request does
+			if errs[i] != nil && gctx.Err() != nil {
+				return gctx.Err()
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("loading contractor dashboard: %w", err)
+	}
+
+	var lastErr error
+	for i, err := range errs {
+		if err != nil {
+			logger.FromContext(ctx).Error("GetContractorDashboard: Error loading section", "section", sections[i].name, "user_id", req.UserID, "error", err)
+			dashboard.FailedSections = append(dashboard.FailedSections, sections[i].name)
+			lastErr = err
+		}
+	}
+	if len(dashboard.FailedSections) == len(sections) {
+		return nil, mapRepoError(lastErr, "loading contractor dashboard")
+	}
+	dashboard.Partial = len(dashboard.FailedSections) > 0
+	return dashboard, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDashboardJobs lists one ongoing job for the contractor, or fails with err.
+type stubDashboardJobs struct {
+	storage.JobRepository
+	err error
+}
+
+func (r stubDashboardJobs) ListByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []models.Job{{ID: uuid.New(), ContractorID: &req.ContractorID, State: *req.State}}, nil
+}
+
+func (r stubDashboardJobs) CountByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) (int, error) {
+	return 1, r.err
+}
+
+// stubDashboardApplications lists one waiting application for the contractor, or fails with err.
+type stubDashboardApplications struct {
+	storage.JobApplicationRepository
+	err error
+}
+
+func (r stubDashboardApplications) ListByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) ([]models.JobApplication, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []models.JobApplication{{ID: uuid.New(), ContractorID: req.ContractorID, State: *req.State}}, nil
+}
+
+func (r stubDashboardApplications) CountByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) (int, error) {
+	return 1, r.err
+}
+
+// stubDashboardInvoices returns fixed unpaid totals, or fails with err.
+type stubDashboardInvoices struct {
+	storage.InvoiceRepository
+	err error
+}
+
+func (r stubDashboardInvoices) SumUnpaidByContractor(ctx context.Context, req *dto.ContractorDashboardRequest) (*models.UnpaidInvoiceTotals, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &models.UnpaidInvoiceTotals{Count: 2, Total: map[string]float64{"USD": 300}, OverdueTotal: map[string]float64{"USD": 200}}, nil
+}
+
+type stubDashboardSkills struct {
+	storage.SkillRepository
+}
+
+func (stubDashboardSkills) ListByJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	return map[uuid.UUID][]string{}, nil
+}
+
+func TestGetContractorDashboard_DegradesPerSection(t *testing.T) {
+	dbDown := errors.New("connection refused")
+	req := &dto.ContractorDashboardRequest{UserID: uuid.New()}
+
+	service := &dashboardService{
+		jobRepo:     stubDashboardJobs{},
+		appRepo:     stubDashboardApplications{err: dbDown},
+		invoiceRepo: stubDashboardInvoices{},
+		skillRepo:   stubDashboardSkills{},
+	}
+	dashboard, err := service.GetContractorDashboard(context.Background(), req)
+	require.NoError(t, err, "One failed section must not fail the dashboard")
+	assert.True(t, dashboard.Partial)
+	assert.Equal(t, []string{DashboardSectionPendingApplications}, dashboard.FailedSections)
+	assert.Len(t, dashboard.ActiveJobs, 1)
+	assert.Empty(t, dashboard.PendingApplications)
+	assert.Equal(t, map[string]float64{"USD": 300}, dashboard.UnpaidInvoices.Total)
+
+	service = &dashboardService{
+		jobRepo:     stubDashboardJobs{err: dbDown},
+		appRepo:     stubDashboardApplications{err: dbDown},
+		invoiceRepo: stubDashboardInvoices{err: dbDown},
+		skillRepo:   stubDashboardSkills{},
+	}
+	_, err = service.GetContractorDashboard(context.Background(), req)
+	assert.ErrorIs(t, err, dbDown, "The dashboard must fail when no section could be loaded")
+}
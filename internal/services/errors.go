@@ -1,6 +1,10 @@
 package services
 
-import "errors"
+import (
+	"errors"
+
+	"go-api-template/pkg/password"
+)
 
 // Define common service errors
 var (
@@ -9,7 +13,12 @@ var (
 	ErrConflict           = errors.New("conflict") // e.g., duplicate email, state conflict
 	ErrValidation         = errors.New("validation failed")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrEmailNotVerified   = errors.New("email not verified")
+	ErrAccountLocked      = errors.New("account temporarily locked") // Too many consecutive failed logins
 	ErrInvalidState       = errors.New("invalid state for operation")
 	ErrInvalidTransition  = errors.New("invalid state transition")
 	ErrInvalidInvoiceInterval = errors.New("invalid invoice interval")
+	ErrInvalidAdjustment  = errors.New("invalid invoice adjustment") // Wrapped with the bound the adjustment broke
+	ErrLimitExceeded      = errors.New("limit exceeded") // Wrapped with the limit that was reached
+	ErrWeakPassword       = password.ErrWeakPassword // Wrapped in a *password.PolicyError listing the unmet requirements
 )
\ No newline at end of file
@@ -1,31 +1,49 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go-api-template/internal/models"
 	"go-api-template/internal/storage"
 	"log"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// isValidJobStateTransition defines the allowed state changes.
-func isValidJobStateTransition(from, to models.JobState) bool {
-	//Assign and Unassign already handle state changes (This validates all other transitions)
-	switch from {
-	case models.JobStateWaiting:
-		return to == models.JobStateArchived
-	case models.JobStateOngoing:
-		// Can transition to Complete (by contractor/employer) or back to Waiting (if unassigned)
-		return to == models.JobStateComplete
-	case models.JobStateComplete:
-		// Can transition to Archived (by employer)
-		return to == models.JobStateArchived
-	case models.JobStateArchived:
-		// Terminal state
-		return false
-	default:
-		return false
+// beginSnapshotTx starts a read-only transaction in which every statement sees the same snapshot,
+// so a page of results and its total count agree even while rows are being written concurrently.
+func beginSnapshotTx(ctx context.Context, db *pgxpool.Pool) (pgx.Tx, error) {
+	return db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+}
+
+// systemIdentityKey marks a context as belonging to work the API does on its own behalf.
+type systemIdentityKey struct{}
+
+// asSystem returns a context for an operation made by the API itself, such as scheduled invoicing, which
+// skips the checks that the requesting user is allowed to act. Only this package can create one.
+func asSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemIdentityKey{}, true)
+}
+
+// isSystem reports whether ctx was created by asSystem.
+func isSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemIdentityKey{}).(bool)
+	return system
+}
+
+// actorID returns who a change is recorded against (created_by/updated_by): models.SystemActorID for
+// work the API does on its own, otherwise userID.
+func actorID(ctx context.Context, userID uuid.UUID) uuid.UUID {
+	if isSystem(ctx) {
+		return models.SystemActorID
 	}
+	return userID
 }
 
 // isValidInvoiceStateTransition checks if moving from current to next state is allowed.
@@ -34,7 +52,9 @@ func isValidInvoiceStateTransition(current, next models.InvoiceState) bool {
 	case models.InvoiceStateWaiting:
 		return next == models.InvoiceStateComplete
 	case models.InvoiceStateComplete:
-		return false // Cannot transition from Complete
+		return next == models.InvoiceStatePaid
+	case models.InvoiceStatePaid:
+		return false // Terminal state
 	default:
 		return false
 	}
@@ -47,12 +67,129 @@ func mapRepoError(err error, operation string) error {
 	}
 	if errors.Is(err, storage.ErrConflict) {
 		// The repo layer should provide more context for conflict errors if possible
-		return fmt.Errorf("%w: %s (%v)", ErrConflict, operation, err)
+		// Wrapped rather than formatted, so handlers can still tell if the conflict is retryable
+		return fmt.Errorf("%w: %s (%w)", ErrConflict, operation, err)
 	}
 	if errors.Is(err, storage.ErrDuplicateEmail) { // Example specific conflict
 		return fmt.Errorf("%w: %s (duplicate email)", ErrConflict, operation)
 	}
+	if errors.Is(err, storage.ErrCanceled) {
+		// The client went away or the request timed out; not worth logging as a failure
+		return fmt.Errorf("%s: %w", operation, err)
+	}
 	// Log other unexpected errors
 	log.Printf("Unexpected repository error during %s: %v", operation, err)
 	return fmt.Errorf("internal error during %s: %w", operation, err)
-}
\ No newline at end of file
+}
+
+// invalidateCached drops the cached copies of ids once the transaction that wrote them has committed.
+// Repositories without a cache are left alone.
+func invalidateCached(ctx context.Context, repo any, ids ...uuid.UUID) {
+	if cached, ok := repo.(storage.CacheInvalidator); ok {
+		cached.Invalidate(ctx, ids...)
+	}
+}
+
+// derefString returns the value of an optional string, or "" when it is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// csvCell makes a user-supplied value safe to open in a spreadsheet: one starting with a character
+// that spreadsheets read as a formula gets a leading ' so it is shown as text rather than evaluated.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// recordOutboxEvent stores a domain event through a transaction-bound outbox repo, so the event is
+// only published if the state change it describes commits.
+func recordOutboxEvent(ctx context.Context, txOutboxRepo storage.OutboxRepository, aggregateType string, aggregateID uuid.UUID, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s event: %w", eventType, err)
+	}
+	event := &models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	}
+	if err := txOutboxRepo.Insert(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// recordAuditEntry serializes details and writes an audit entry through txAuditRepo, so it commits
+// or rolls back with the change it describes.
+func recordAuditEntry(ctx context.Context, txAuditRepo storage.AuditRepository, actorID uuid.UUID, action, entityType string, entityID uuid.UUID, details any) error {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s audit details: %w", action, err)
+	}
+	entry := &models.AuditEntry{
+		ActorID:    &actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    data,
+	}
+	if adminID, ok := models.ImpersonatorFromContext(ctx); ok {
+		entry.ImpersonatorID = &adminID
+	}
+	if err := txAuditRepo.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record %s audit entry: %w", action, err)
+	}
+	return nil
+}
+
+// normalizeSkillNames lowercases and trims skill names, dropping blanks and duplicates, so the same
+// skill always maps to one row. The result is sorted.
+func normalizeSkillNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = models.NormalizeSkillName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// attachJobSkills loads the skills of all the given jobs with one query and sets Job.Skills.
+func attachJobSkills(ctx context.Context, skillRepo storage.SkillRepository, jobs ...*models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+	skills, err := skillRepo.ListByJobIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		job.Skills = skills[job.ID]
+	}
+	return nil
+}
+
+// jobPointers returns pointers into jobs, so helpers like attachJobSkills can fill them in place.
+func jobPointers(jobs []models.Job) []*models.Job {
+	pointers := make([]*models.Job, len(jobs))
+	for i := range jobs {
+		pointers[i] = &jobs[i]
+	}
+	return pointers
+}
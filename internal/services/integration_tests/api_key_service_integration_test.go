@@ -0,0 +1,71 @@
+package integration_tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Test Setup ---
+
+// setupAPIKeyServiceIntegrationTest initializes the service with a real DB pool.
+func setupAPIKeyServiceIntegrationTest(t *testing.T) (context.Context, services.APIKeyService, *pgxpool.Pool) {
+	t.Helper()
+	pool, _ := getTestClients(t)
+	apiKeyService := services.NewAPIKeyService(pool)
+	ctx := context.Background()
+	return ctx, apiKeyService, pool
+}
+
+// --- Test Cases ---
+
+func TestAPIKeyService_Integration_Lifecycle(t *testing.T) {
+	ctx, apiKeyService, pool := setupAPIKeyServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "api_keys")
+
+	admin := createTestUser(t, ctx, pool, "apikey-admin@test.com", "API Key Admin")
+
+	key, rawKey, err := apiKeyService.CreateAPIKey(ctx, &dto.CreateAPIKeyRequest{
+		Owner:     "billing",
+		Scopes:    []string{models.APIKeyScopeInvoicesPay},
+		CreatedBy: admin.ID,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rawKey, "gat_"))
+	assert.NotContains(t, key.KeyHash, rawKey, "Only the hash of the key may be stored")
+	require.NotNil(t, key.CreatedBy)
+	assert.Equal(t, admin.ID, *key.CreatedBy)
+
+	found, err := apiKeyService.LookupAPIKey(ctx, rawKey)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, []string{models.APIKeyScopeInvoicesPay}, found.Scopes)
+	assert.Nil(t, found.RevokedAt)
+
+	unknown, err := apiKeyService.LookupAPIKey(ctx, rawKey+"x")
+	require.NoError(t, err)
+	assert.Nil(t, unknown, "An unknown key must not match")
+
+	revoked, err := apiKeyService.RevokeAPIKey(ctx, &dto.RevokeAPIKeyRequest{ID: key.ID})
+	require.NoError(t, err)
+	require.NotNil(t, revoked.RevokedAt)
+
+	found, err = apiKeyService.LookupAPIKey(ctx, rawKey)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.NotNil(t, found.RevokedAt, "A revoked key must still be found, marked as revoked")
+
+	_, err = apiKeyService.RevokeAPIKey(ctx, &dto.RevokeAPIKeyRequest{ID: uuid.New()})
+	assert.True(t, errors.Is(err, services.ErrNotFound), "Expected ErrNotFound, got %v", err)
+}
@@ -0,0 +1,97 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Test Setup ---
+
+// setupDashboardServiceIntegrationTest initializes the service with a real DB pool.
+func setupDashboardServiceIntegrationTest(t *testing.T) (context.Context, services.DashboardService, *pgxpool.Pool) {
+	t.Helper()
+	pool, _ := getTestClients(t)
+	dashboardService := services.NewDashboardService(pool)
+	ctx := context.Background()
+	return ctx, dashboardService, pool
+}
+
+// --- Test Cases ---
+
+func TestDashboardService_Integration_GetContractorDashboard(t *testing.T) {
+	ctx, dashboardService, pool := setupDashboardServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "dashboard-employer@test.com", "Dashboard Employer")
+	contractor := createTestUser(t, ctx, pool, "dashboard-contractor@test.com", "Dashboard Contractor")
+	otherContractor := createTestUser(t, ctx, pool, "dashboard-other@test.com", "Other Contractor")
+
+	activeJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	otherActiveJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &otherContractor.ID)
+	openJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	closedJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	pending := createTestApplication(t, ctx, pool, openJob.ID, contractor.ID, models.JobApplicationWaiting)
+	createTestApplication(t, ctx, pool, closedJob.ID, contractor.ID, models.JobApplicationRejected)
+	createTestApplication(t, ctx, pool, openJob.ID, otherContractor.ID, models.JobApplicationWaiting)
+
+	createTestInvoice(t, ctx, pool, activeJob.ID, 1, 100, models.InvoiceStateWaiting)
+	_, err := postgres.NewInvoiceRepo(pool).Create(ctx, &models.Invoice{JobID: activeJob.ID, ContractorID: &contractor.ID, IntervalNumber: 2, Value: 200, State: models.InvoiceStateComplete, DueDate: time.Now().Add(-24 * time.Hour)})
+	require.NoError(t, err)
+	createTestInvoice(t, ctx, pool, activeJob.ID, 3, 300, models.InvoiceStatePaid)
+	createTestInvoice(t, ctx, pool, otherActiveJob.ID, 1, 999, models.InvoiceStateWaiting)
+
+	// Invoices in another currency are totalled separately, not added to the USD ones
+	euroJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	_, err = pool.Exec(ctx, "UPDATE jobs SET currency = 'EUR' WHERE id = $1", euroJob.ID)
+	require.NoError(t, err)
+	createTestInvoice(t, ctx, pool, euroJob.ID, 1, 80, models.InvoiceStateWaiting)
+
+	dashboard, err := dashboardService.GetContractorDashboard(ctx, &dto.ContractorDashboardRequest{UserID: contractor.ID})
+	require.NoError(t, err)
+	require.NotNil(t, dashboard)
+
+	assert.False(t, dashboard.Partial)
+	assert.Empty(t, dashboard.FailedSections)
+
+	// Only the contractor's own data is included in every section
+	require.Len(t, dashboard.ActiveJobs, 2)
+	assert.ElementsMatch(t, []uuid.UUID{activeJob.ID, euroJob.ID}, []uuid.UUID{dashboard.ActiveJobs[0].ID, dashboard.ActiveJobs[1].ID})
+	assert.Equal(t, 2, dashboard.TotalActiveJobs)
+
+	require.Len(t, dashboard.PendingApplications, 1)
+	assert.Equal(t, pending.ID, dashboard.PendingApplications[0].ID)
+	assert.Equal(t, 1, dashboard.TotalPendingApplications)
+
+	assert.Equal(t, models.UnpaidInvoiceTotals{
+		Count:        3,
+		Total:        map[string]float64{"USD": 300, "EUR": 80},
+		OverdueTotal: map[string]float64{"USD": 200, "EUR": 0},
+	}, dashboard.UnpaidInvoices)
+}
+
+func TestDashboardService_Integration_GetContractorDashboard_Empty(t *testing.T) {
+	ctx, dashboardService, pool := setupDashboardServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users")
+
+	newcomer := createTestUser(t, ctx, pool, "dashboard-newcomer@test.com", "Newcomer")
+
+	dashboard, err := dashboardService.GetContractorDashboard(ctx, &dto.ContractorDashboardRequest{UserID: newcomer.ID})
+	require.NoError(t, err)
+	assert.False(t, dashboard.Partial)
+	assert.Empty(t, dashboard.ActiveJobs)
+	assert.Empty(t, dashboard.PendingApplications)
+	assert.Equal(t, models.UnpaidInvoiceTotals{Total: map[string]float64{}, OverdueTotal: map[string]float64{}}, dashboard.UnpaidInvoices)
+}
@@ -28,6 +28,9 @@ func ptrFloat64(f float64) *float64 { return &f }
 // Helper to create a pointer to an int
 func ptrInt(i int) *int { return &i }
 
+// Helper to create a pointer to a string
+func ptrString(s string) *string { return &s }
+
 // Helper function to create a user for tests
 func createTestUser(t *testing.T, ctx context.Context, pool *pgxpool.Pool, email, name string) *models.User {
 	t.Helper()
@@ -40,6 +43,11 @@ func createTestUser(t *testing.T, ctx context.Context, pool *pgxpool.Pool, email
 	user, err := userRepo.Create(ctx, userReq)
 	require.NoError(t, err, "Failed to create test user %s", email)
 	require.NotNil(t, user)
+
+	// Test users are verified by default so login-gated flows behave like before verification existed
+	verified := true
+	user, err = userRepo.Update(ctx, &dto.UpdateUserRequest{ID: user.ID, Verified: &verified})
+	require.NoError(t, err, "Failed to verify test user %s", email)
 	return user
 }
 
@@ -59,7 +67,7 @@ func createTestJob(t *testing.T, ctx context.Context, pool *pgxpool.Pool, employ
 
 	// Update state/contractor if needed for the test scenario
 	if state != models.JobStateWaiting || contractorID != nil {
-		updateReq := dto.UpdateJobRequest{ID: job.ID}
+		updateReq := dto.UpdateJobRequest{ID: job.ID, Version: job.Version, UpdatedBy: employerID}
 		if state != models.JobStateWaiting {
 			updateReq.State = &state
 		}
@@ -165,6 +173,16 @@ func runMigrations(t *testing.T, dsn string) {
 	log.Println("Migrations applied successfully (or no change).")
 }
 
+// latestUpdatedAt returns the newest updated_at in table, as a cutoff for updated_since filters
+// that no row written so far passes. It's read from the database so clock skew doesn't matter.
+func latestUpdatedAt(t *testing.T, ctx context.Context, pool *pgxpool.Pool, table string) time.Time {
+	t.Helper()
+	var latest time.Time
+	err := pool.QueryRow(ctx, fmt.Sprintf("SELECT MAX(updated_at) FROM %s", table)).Scan(&latest)
+	require.NoError(t, err, "Failed to read latest updated_at of %s", table)
+	return latest
+}
+
 // cleanupTables truncates specified tables for test isolation.
 func cleanupTables(t *testing.T, pool *pgxpool.Pool, tables ...string) {
 	t.Helper()
@@ -3,16 +3,23 @@ package integration_tests
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"go-api-template/internal/models"
+	"go-api-template/internal/notifications"
 	"go-api-template/internal/services"
 	"go-api-template/internal/storage"          // For storage errors
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres" // Need concrete repos for setup/assertion
 	"go-api-template/internal/transport/dto"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,19 +38,23 @@ func setupInvoiceServiceIntegrationTest(t *testing.T) (context.Context, services
 	t.Helper()
 	pool, _ := getTestClients(t)
 	// Instantiate the real service
-	invoiceService := services.NewInvoiceService(pool)
+	invoiceService := services.NewInvoiceService(pool, notifications.NewJobStreams(nil), models.DefaultInvoicePaymentTermDays, models.DefaultInvoiceMaxAdjustmentPercent, cache.Config{})
 	ctx := context.Background()
 	return ctx, invoiceService, pool
 }
 
-// Helper function to create an invoice for tests
+// Helper function to create an invoice for tests, issued by the job's current contractor
 func createTestInvoice(t *testing.T, ctx context.Context, pool *pgxpool.Pool, jobID uuid.UUID, interval int, value float64, state models.InvoiceState) *models.Invoice {
 	t.Helper()
+	job, err := postgres.NewJobRepo(pool).GetByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+	require.NoError(t, err, "Failed to fetch job %s for test invoice", jobID)
 	invoiceRepo := postgres.NewInvoiceRepo(pool)
 	invoice := &models.Invoice{
 		JobID:          jobID,
+		ContractorID:   job.ContractorID,
 		IntervalNumber: interval,
 		Value:          value,
+		Currency:       job.Currency,
 		State:          state,
 	}
 	createdInvoice, err := invoiceRepo.Create(ctx, invoice)
@@ -68,7 +79,7 @@ func TestInvoiceService_Integration_CreateInvoice(t *testing.T) {
 	jobWaiting := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, &contractor.ID)
 	jobPartial := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
 	jobPartial.Duration = 25 // e.g., 2 full intervals (10) + 1 partial (5)
-	_, err := postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: jobPartial.ID, Duration: &jobPartial.Duration})
+	_, err := postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: jobPartial.ID, Version: jobPartial.Version, Duration: &jobPartial.Duration})
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -193,6 +204,306 @@ func TestInvoiceService_Integration_CreateInvoice(t *testing.T) {
 	}
 }
 
+func TestInvoiceService_Integration_CreateInvoice_AdjustmentBounds(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "adjust-employer@test.com", "Adjust Employer")
+	contractor := createTestUser(t, ctx, pool, "adjust-contractor@test.com", "Adjust Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID) // Base value 50 rate * 10 interval = 500
+
+	// Drives the value below zero
+	_, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID, Adjustment: ptrFloat64(-600)})
+	require.ErrorIs(t, err, services.ErrInvalidAdjustment)
+	assert.Contains(t, err.Error(), "negative")
+
+	// More than the default 50% of the base value
+	_, err = invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID, Adjustment: ptrFloat64(300)})
+	require.ErrorIs(t, err, services.ErrInvalidAdjustment)
+	assert.Contains(t, err.Error(), "exceeds 50%")
+
+	// Within bounds; the rejected attempts didn't use up the interval
+	invoice, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID, Adjustment: ptrFloat64(-100)})
+	require.NoError(t, err)
+	assert.Equal(t, 400.0, invoice.Value)
+	assert.Equal(t, 1, invoice.IntervalNumber)
+}
+
+func TestInvoiceService_Integration_CreateInvoice_Currency(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "currency-invoice-employer@test.com", "Currency Employer")
+	contractor := createTestUser(t, ctx, pool, "currency-invoice-contractor@test.com", "Currency Contractor")
+	jobRepo := postgres.NewJobRepo(pool)
+	job, err := jobRepo.Create(ctx, &dto.CreateJobRequest{Rate: 1234.56, Currency: "JPY", Duration: 20, InvoiceInterval: 10, EmployerID: employer.ID})
+	require.NoError(t, err)
+	ongoing := models.JobStateOngoing
+	_, err = jobRepo.Update(ctx, &dto.UpdateJobRequest{ID: job.ID, Version: job.Version, State: &ongoing, ContractorID: &contractor.ID, UpdatedBy: employer.ID})
+	require.NoError(t, err)
+
+	invoice, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "JPY", invoice.Currency, "Invoices are in the job's currency")
+	assert.Equal(t, 12346.0, invoice.Value, "1234.56 x 10 hours, rounded to whole yen")
+
+	fetched, err := invoiceService.GetInvoiceByID(ctx, &dto.GetInvoiceByIDRequest{ID: invoice.ID, UserId: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "JPY", fetched.Currency)
+
+	generated, err := invoiceService.GenerateAllInvoices(ctx, &dto.GenerateAllInvoicesRequest{JobID: job.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	require.Len(t, generated, 1)
+	assert.Equal(t, "JPY", generated[0].Currency)
+
+	balance, err := invoiceService.GetJobBalance(ctx, &dto.GetJobBalanceRequest{JobID: job.ID, UserId: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "JPY", balance.Currency)
+	assert.Equal(t, 24691.0, balance.TotalValue)
+}
+
+func TestInvoiceService_Integration_CreateInvoice_IntervalNumber(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "nonce-employer@test.com", "Nonce Employer")
+	contractor := createTestUser(t, ctx, pool, "nonce-contractor@test.com", "Nonce Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	create := func(interval int) (*models.Invoice, error) {
+		return invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID, IntervalNumber: ptrInt(interval)})
+	}
+
+	invoice, err := create(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, invoice.IntervalNumber)
+
+	// Replaying the request doesn't bill interval 2
+	_, err = create(1)
+	require.ErrorIs(t, err, services.ErrConflict)
+	assert.Contains(t, err.Error(), "interval 1 is already invoiced")
+
+	// Intervals can't be skipped
+	_, err = create(3)
+	require.ErrorIs(t, err, services.ErrValidation)
+
+	invoice, err = create(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, invoice.IntervalNumber)
+}
+
+func TestInvoiceService_Integration_CreateInvoiceConcurrent(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "race-inv-employer@test.com", "Race Employer")
+	contractor := createTestUser(t, ctx, pool, "race-inv-contractor@test.com", "Race Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+
+	// Fire the same create twice at once, as a client retrying a request that seemed to time out would
+	const attempts = 2
+	errs := make([]error, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID, IntervalNumber: ptrInt(1)})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// Exactly one create wins; the loser conflicts whether it read before or after the winner committed
+	successes := 0
+	for _, err := range errs {
+		if err == nil {
+			successes++
+			continue
+		}
+		assert.ErrorIs(t, err, services.ErrConflict)
+	}
+	require.Equal(t, 1, successes, "exactly one concurrent create should succeed")
+
+	var count int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM invoices WHERE job_id = $1`, job.ID).Scan(&count))
+	assert.Equal(t, 1, count)
+	invoices, total, err := invoiceService.ListInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: job.ID, UserId: contractor.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, 1, invoices[0].IntervalNumber)
+	assert.Equal(t, "INV-000001-"+fmt.Sprint(time.Now().UTC().Year()), invoices[0].InvoiceNumber, "the loser's invoice number was rolled back")
+}
+
+func TestInvoiceService_Integration_GenerateAllInvoices(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	invoiceRepo := postgres.NewInvoiceRepo(pool) // For verification
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "generate-employer@test.com", "Generate Employer")
+	contractor := createTestUser(t, ctx, pool, "generate-contractor@test.com", "Generate Contractor")
+
+	// 45 hours at 10 per interval: 4 full intervals + 1 partial (5h)
+	jobPartial := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	duration := 45
+	_, err := postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: jobPartial.ID, Version: jobPartial.Version, Duration: &duration})
+	require.NoError(t, err)
+	// Intervals 1 and 3 already invoiced, leaving a gap
+	createTestInvoice(t, ctx, pool, jobPartial.ID, 1, 123, models.InvoiceStateComplete)
+	createTestInvoice(t, ctx, pool, jobPartial.ID, 3, 456, models.InvoiceStateWaiting)
+
+	jobWaiting := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, &contractor.ID)
+
+	tests := []struct {
+		name              string
+		req               *dto.GenerateAllInvoicesRequest
+		expectedIntervals []int
+		expectedValues    []float64
+		expectedErr       error
+	}{
+		{
+			name:              "Success_SkipsExistingIntervals",
+			req:               &dto.GenerateAllInvoicesRequest{JobID: jobPartial.ID, UserId: contractor.ID},
+			expectedIntervals: []int{2, 4, 5},
+			expectedValues:    []float64{50.0 * 10, 50.0 * 10, 50.0 * 5},
+		},
+		{
+			name:              "Success_NothingOutstanding",
+			req:               &dto.GenerateAllInvoicesRequest{JobID: jobPartial.ID, UserId: contractor.ID},
+			expectedIntervals: []int{},
+			expectedValues:    []float64{},
+		},
+		{
+			name:        "Error_JobNotFound",
+			req:         &dto.GenerateAllInvoicesRequest{JobID: uuid.New(), UserId: contractor.ID},
+			expectedErr: services.ErrNotFound,
+		},
+		{
+			name:        "Error_Forbidden_NotContractor",
+			req:         &dto.GenerateAllInvoicesRequest{JobID: jobPartial.ID, UserId: employer.ID},
+			expectedErr: services.ErrForbidden,
+		},
+		{
+			name:        "Error_InvalidState_JobNotOngoing",
+			req:         &dto.GenerateAllInvoicesRequest{JobID: jobWaiting.ID, UserId: contractor.ID},
+			expectedErr: services.ErrInvalidState,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices, err := invoiceService.GenerateAllInvoices(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, invoices)
+				return
+			}
+
+			require.NoError(t, err)
+			intervals := make([]int, 0, len(invoices))
+			values := make([]float64, 0, len(invoices))
+			for _, invoice := range invoices {
+				assert.Equal(t, tt.req.JobID, invoice.JobID)
+				assert.Equal(t, models.InvoiceStateWaiting, invoice.State)
+				intervals = append(intervals, invoice.IntervalNumber)
+				values = append(values, invoice.Value)
+			}
+			assert.Equal(t, tt.expectedIntervals, intervals)
+			assert.Equal(t, tt.expectedValues, values)
+		})
+	}
+
+	// Every interval is invoiced exactly once and the pre-existing invoices are untouched
+	stored, err := invoiceRepo.ListIntervalNumbersForJob(ctx, &dto.ListIntervalNumbersForJobRequest{JobID: jobPartial.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, stored)
+	total, err := invoiceRepo.CountByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: jobPartial.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+}
+
+func TestInvoiceService_Integration_InvoiceNumbersAreSequentialPerEmployer(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "numbering-employer@test.com", "Numbering Employer")
+	otherEmployer := createTestUser(t, ctx, pool, "numbering-other@test.com", "Other Employer")
+	contractor := createTestUser(t, ctx, pool, "numbering-contractor@test.com", "Numbering Contractor")
+
+	// Each test job has 2 intervals; single creations and generations race for the same employer
+	const jobCount = 20
+	jobs := make([]*models.Job, jobCount)
+	for i := range jobs {
+		jobs[i] = createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var numbers []string
+	errs := make(chan error, jobCount)
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, jobID uuid.UUID) {
+			defer wg.Done()
+			var created []models.Invoice
+			if i%2 == 0 {
+				invoice, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: jobID, UserId: contractor.ID})
+				if err != nil {
+					errs <- err
+					return
+				}
+				created = append(created, *invoice)
+			} else {
+				invoices, err := invoiceService.GenerateAllInvoices(ctx, &dto.GenerateAllInvoicesRequest{JobID: jobID, UserId: contractor.ID})
+				if err != nil {
+					errs <- err
+					return
+				}
+				created = invoices
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, invoice := range created {
+				numbers = append(numbers, invoice.InvoiceNumber)
+			}
+		}(i, job.ID)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// 10 single invoices and 10 generations of 2 invoices each
+	const expectedCount = jobCount/2 + jobCount
+	require.Len(t, numbers, expectedCount)
+	year := time.Now().UTC().Year()
+	expected := make([]string, 0, expectedCount)
+	for seq := 1; seq <= expectedCount; seq++ {
+		expected = append(expected, fmt.Sprintf("INV-%06d-%d", seq, year))
+	}
+	assert.ElementsMatch(t, expected, numbers, "Invoice numbers must be unique and contiguous from 1")
+
+	rows, err := pool.Query(ctx, "SELECT i.invoice_number FROM invoices i JOIN jobs j ON j.id = i.job_id WHERE j.employer_id = $1", employer.ID)
+	require.NoError(t, err)
+	stored, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, stored)
+
+	// Another employer's numbering starts at 1
+	otherJob := createTestJob(t, ctx, pool, otherEmployer.ID, models.JobStateOngoing, &contractor.ID)
+	invoice, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: otherJob.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("INV-000001-%d", year), invoice.InvoiceNumber)
+
+	fetched, err := invoiceService.GetInvoiceByID(ctx, &dto.GetInvoiceByIDRequest{ID: invoice.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, invoice.InvoiceNumber, fetched.InvoiceNumber)
+}
+
 func TestInvoiceService_Integration_GetInvoiceByID(t *testing.T) {
 	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
 	defer cleanupTables(t, pool, "users", "jobs", "invoices")
@@ -314,6 +625,18 @@ func TestInvoiceService_Integration_UpdateInvoiceState(t *testing.T) {
 			expectedState: models.InvoiceStateComplete, // Should not change
 			expectedErr:   services.ErrInvalidTransition,
 		},
+		{
+			name: "Error_InvalidTransition_PaidRequiresMarkInvoicePaid",
+			setupFunc: func() uuid.UUID {
+				return createTestInvoice(t, ctx, pool, job.ID, 4, 500, models.InvoiceStateComplete).ID
+			},
+			req: &dto.UpdateInvoiceStateRequest{
+				NewState: models.InvoiceStatePaid,
+				UserId:   employer.ID,
+			},
+			expectedState: models.InvoiceStateComplete, // Payment must go through MarkInvoicePaid
+			expectedErr:   services.ErrInvalidTransition,
+		},
 		{
 			name: "Error_NotFound",
 			setupFunc: func() uuid.UUID {
@@ -370,6 +693,105 @@ func TestInvoiceService_Integration_UpdateInvoiceState(t *testing.T) {
 	}
 }
 
+func TestInvoiceService_Integration_MarkInvoicePaid(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	invoiceRepo := postgres.NewInvoiceRepo(pool) // For verification
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "payinv-employer@test.com", "PayInv Employer")
+	contractor := createTestUser(t, ctx, pool, "payinv-contractor@test.com", "PayInv Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+
+	tests := []struct {
+		name          string
+		setupFunc     func() uuid.UUID // Function to setup/get the target invoice ID for the test
+		userID        uuid.UUID
+		expectedState models.InvoiceState // Expected final state (or initial state if error)
+		expectedErr   error
+	}{
+		{
+			name: "Success_CompleteToPaid",
+			setupFunc: func() uuid.UUID {
+				return createTestInvoice(t, ctx, pool, job.ID, 1, 500, models.InvoiceStateComplete).ID
+			},
+			userID:        employer.ID,
+			expectedState: models.InvoiceStatePaid,
+		},
+		{
+			name: "Error_Forbidden_Contractor",
+			setupFunc: func() uuid.UUID {
+				return createTestInvoice(t, ctx, pool, job.ID, 2, 500, models.InvoiceStateComplete).ID
+			},
+			userID:        contractor.ID, // Only the employer pays
+			expectedState: models.InvoiceStateComplete,
+			expectedErr:   services.ErrForbidden,
+		},
+		{
+			name: "Error_InvalidTransition_WaitingToPaid",
+			setupFunc: func() uuid.UUID {
+				return createTestInvoice(t, ctx, pool, job.ID, 3, 500, models.InvoiceStateWaiting).ID
+			},
+			userID:        employer.ID,
+			expectedState: models.InvoiceStateWaiting,
+			expectedErr:   services.ErrInvalidTransition,
+		},
+		{
+			name: "Error_InvalidTransition_AlreadyPaid",
+			setupFunc: func() uuid.UUID {
+				return createTestInvoice(t, ctx, pool, job.ID, 4, 500, models.InvoiceStatePaid).ID
+			},
+			userID:        employer.ID,
+			expectedState: models.InvoiceStatePaid,
+			expectedErr:   services.ErrInvalidTransition,
+		},
+		{
+			name: "Error_NotFound",
+			setupFunc: func() uuid.UUID {
+				return uuid.New() // Non-existent ID
+			},
+			userID:      employer.ID,
+			expectedErr: services.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetID := tt.setupFunc()
+			req := &dto.MarkInvoicePaidRequest{ID: targetID, UserId: tt.userID}
+
+			paidInvoice, err := invoiceService.MarkInvoicePaid(ctx, req)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, paidInvoice)
+
+				if !errors.Is(tt.expectedErr, services.ErrNotFound) {
+					dbInvoice, dbErr := invoiceRepo.GetByID(ctx, &dto.GetInvoiceByIDRequest{ID: targetID})
+					require.NoError(t, dbErr)
+					assert.Equal(t, tt.expectedState, dbInvoice.State, "Invoice state should not have changed on error")
+					if tt.expectedState != models.InvoiceStatePaid {
+						assert.Nil(t, dbInvoice.PaidAt)
+					}
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, paidInvoice)
+			assert.Equal(t, tt.expectedState, paidInvoice.State)
+			require.NotNil(t, paidInvoice.PaidAt)
+			assert.WithinDuration(t, time.Now(), *paidInvoice.PaidAt, 5*time.Second)
+
+			// Verify in DB
+			dbInvoice, dbErr := invoiceRepo.GetByID(ctx, &dto.GetInvoiceByIDRequest{ID: targetID})
+			require.NoError(t, dbErr)
+			assert.Equal(t, models.InvoiceStatePaid, dbInvoice.State)
+			require.NotNil(t, dbInvoice.PaidAt)
+		})
+	}
+}
+
 func TestInvoiceService_Integration_DeleteInvoice(t *testing.T) {
 	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
 	invoiceRepo := postgres.NewInvoiceRepo(pool) // For verification
@@ -495,6 +917,7 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 		name             string
 		req              dto.ListInvoicesByJobRequest
 		expectedCount    int
+		expectedTotal    int                   // Rows matching the filters, regardless of limit/offset
 		expectedStates   []models.InvoiceState // Optional: check states if count > 0
 		expectedErr      error
 		errorContains    string
@@ -507,6 +930,7 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 				Limit:  10, Offset: 0,
 			},
 			expectedCount: 3,
+			expectedTotal: 3,
 			expectedErr:   nil,
 		},
 		{
@@ -517,6 +941,7 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 				Limit:  10, Offset: 0,
 			},
 			expectedCount: 3,
+			expectedTotal: 3,
 			expectedErr:   nil,
 		},
 		{
@@ -528,6 +953,7 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 				Limit:  10, Offset: 0,
 			},
 			expectedCount:  2,
+			expectedTotal: 2,
 			expectedStates: []models.InvoiceState{models.InvoiceStateWaiting, models.InvoiceStateWaiting},
 			expectedErr:    nil,
 		},
@@ -540,9 +966,95 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 				Limit:  10, Offset: 0,
 			},
 			expectedCount:  1,
+			expectedTotal: 1,
 			expectedStates: []models.InvoiceState{models.InvoiceStateComplete},
 			expectedErr:    nil,
 		},
+		{
+			name: "Success_PageOfFilteredState",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:  job1.ID,
+				UserId: employer1.ID,
+				State:  ptrInvoiceState(models.InvoiceStateWaiting),
+				Limit:  1, Offset: 0,
+			},
+			expectedCount: 1,
+			expectedTotal: 2, // Both Waiting invoices counted even though only one is returned
+			expectedErr:   nil,
+		},
+		{
+			name: "Success_FilterIntervalRange",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:       job1.ID,
+				UserId:      contractor1.ID,
+				MinInterval: ptrInt(2),
+				MaxInterval: ptrInt(3),
+				Limit:       10, Offset: 0,
+			},
+			expectedCount:  2,
+			expectedTotal:  2,
+			expectedStates: []models.InvoiceState{models.InvoiceStateComplete, models.InvoiceStateWaiting}, // Intervals 2 and 3
+		},
+		{
+			name: "Success_FilterMinIntervalOnly",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:       job1.ID,
+				UserId:      employer1.ID,
+				MinInterval: ptrInt(3),
+				Limit:       10, Offset: 0,
+			},
+			expectedCount: 1,
+			expectedTotal: 1,
+		},
+		{
+			name: "Success_FilterStateAndInterval",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:       job1.ID,
+				UserId:      employer1.ID,
+				State:       ptrInvoiceState(models.InvoiceStateWaiting), // Unpaid invoices of the first two intervals
+				MaxInterval: ptrInt(2),
+				Limit:       10, Offset: 0,
+			},
+			expectedCount:  1,
+			expectedTotal:  1,
+			expectedStates: []models.InvoiceState{models.InvoiceStateWaiting},
+		},
+		{
+			name: "Success_FilterSingleInterval",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:       job1.ID,
+				UserId:      employer1.ID,
+				MinInterval: ptrInt(2),
+				MaxInterval: ptrInt(2),
+				Limit:       10, Offset: 0,
+			},
+			expectedCount:  1,
+			expectedTotal:  1,
+			expectedStates: []models.InvoiceState{models.InvoiceStateComplete},
+		},
+		{
+			name: "Error_MinIntervalAfterMaxInterval",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:       job1.ID,
+				UserId:      employer1.ID,
+				MinInterval: ptrInt(3),
+				MaxInterval: ptrInt(2),
+				Limit:       10, Offset: 0,
+			},
+			expectedErr:   services.ErrValidation,
+			errorContains: "min_interval",
+		},
+		{
+			name: "Error_Forbidden_WithFilters",
+			req: dto.ListInvoicesByJobRequest{
+				JobID:       job1.ID,
+				UserId:      otherUser.ID, // Filters don't bypass the party check
+				MinInterval: ptrInt(1),
+				MaxInterval: ptrInt(3),
+				Limit:       10, Offset: 0,
+			},
+			expectedErr: services.ErrForbidden,
+		},
 		{
 			name: "Error_Forbidden",
 			req: dto.ListInvoicesByJobRequest{
@@ -565,7 +1077,7 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			invoices, err := invoiceService.ListInvoicesByJob(ctx, &tt.req)
+			invoices, total, err := invoiceService.ListInvoicesByJob(ctx, &tt.req)
 
 			if tt.expectedErr != nil {
 				require.Error(t, err)
@@ -577,6 +1089,7 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.Len(t, invoices, tt.expectedCount)
+				assert.Equal(t, tt.expectedTotal, total)
 				if tt.expectedStates != nil {
 					require.Equal(t, len(tt.expectedStates), len(invoices), "Mismatch in expected states count")
 					for i, inv := range invoices {
@@ -590,4 +1103,386 @@ func TestInvoiceService_Integration_ListInvoicesByJob(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestInvoiceService_Integration_GetJobBalance(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "balance-employer@test.com", "Balance Employer")
+	contractor := createTestUser(t, ctx, pool, "balance-contractor@test.com", "Balance Contractor")
+	otherUser := createTestUser(t, ctx, pool, "balance-other@test.com", "Balance Other")
+
+	// Rate 50 x Duration 20 = 1000
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	createTestInvoice(t, ctx, pool, job.ID, 1, 150, models.InvoiceStatePaid)
+	createTestInvoice(t, ctx, pool, job.ID, 2, 250, models.InvoiceStatePaid)
+	createTestInvoice(t, ctx, pool, job.ID, 3, 200, models.InvoiceStateComplete)
+	createTestInvoice(t, ctx, pool, job.ID, 4, 100, models.InvoiceStateWaiting)
+
+	emptyJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+
+	overInvoicedJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	createTestInvoice(t, ctx, pool, overInvoicedJob.ID, 1, 1200, models.InvoiceStateComplete) // Adjusted past the job value
+
+	tests := []struct {
+		name            string
+		req             *dto.GetJobBalanceRequest
+		expectedBalance *models.JobBalance
+		expectedErr     error
+	}{
+		{
+			name: "Success_Employer_MixedStates",
+			req:  &dto.GetJobBalanceRequest{JobID: job.ID, UserId: employer.ID},
+			expectedBalance: &models.JobBalance{
+				JobID: job.ID, TotalValue: 1000, TotalInvoiced: 700, TotalPaid: 400, Remaining: 300,
+			},
+		},
+		{
+			name: "Success_Contractor",
+			req:  &dto.GetJobBalanceRequest{JobID: job.ID, UserId: contractor.ID},
+			expectedBalance: &models.JobBalance{
+				JobID: job.ID, TotalValue: 1000, TotalInvoiced: 700, TotalPaid: 400, Remaining: 300,
+			},
+		},
+		{
+			name: "Success_NoInvoices",
+			req:  &dto.GetJobBalanceRequest{JobID: emptyJob.ID, UserId: employer.ID},
+			expectedBalance: &models.JobBalance{
+				JobID: emptyJob.ID, TotalValue: 1000, TotalInvoiced: 0, TotalPaid: 0, Remaining: 1000,
+			},
+		},
+		{
+			name: "Success_OverInvoicedRemainingIsZero",
+			req:  &dto.GetJobBalanceRequest{JobID: overInvoicedJob.ID, UserId: employer.ID},
+			expectedBalance: &models.JobBalance{
+				JobID: overInvoicedJob.ID, TotalValue: 1000, TotalInvoiced: 1200, TotalPaid: 0, Remaining: 0,
+			},
+		},
+		{
+			name:        "Error_Forbidden_OtherUser",
+			req:         &dto.GetJobBalanceRequest{JobID: job.ID, UserId: otherUser.ID},
+			expectedErr: services.ErrForbidden,
+		},
+		{
+			name:        "Error_JobNotFound",
+			req:         &dto.GetJobBalanceRequest{JobID: uuid.New(), UserId: employer.ID},
+			expectedErr: services.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, err := invoiceService.GetJobBalance(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, balance)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, balance)
+			assert.Equal(t, tt.expectedBalance.JobID, balance.JobID)
+			assert.InDelta(t, tt.expectedBalance.TotalValue, balance.TotalValue, 0.001)
+			assert.InDelta(t, tt.expectedBalance.TotalInvoiced, balance.TotalInvoiced, 0.001)
+			assert.InDelta(t, tt.expectedBalance.TotalPaid, balance.TotalPaid, 0.001)
+			assert.InDelta(t, tt.expectedBalance.Remaining, balance.Remaining, 0.001)
+		})
+	}
+}
+
+func TestInvoiceService_Integration_CreateInvoiceIncrementsMetric(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "metrics-inv-employer@test.com", "Metrics Employer")
+	contractor := createTestUser(t, ctx, pool, "metrics-inv-contractor@test.com", "Metrics Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	before := testutil.ToFloat64(services.InvoicesCreatedTotal)
+
+	_, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(services.InvoicesCreatedTotal))
+
+	// A forbidden create must not be counted
+	_, err = invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: employer.ID})
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Equal(t, before+1, testutil.ToFloat64(services.InvoicesCreatedTotal))
+}
+
+func TestInvoiceService_Integration_GenerateDueAutoInvoices(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+	jobRepo := postgres.NewJobRepo(pool)
+	invoiceRepo := postgres.NewInvoiceRepo(pool)
+
+	employer := createTestUser(t, ctx, pool, "auto-inv-employer@test.com", "Auto Employer")
+	contractor := createTestUser(t, ctx, pool, "auto-inv-contractor@test.com", "Auto Contractor")
+	now := time.Now().Truncate(time.Microsecond)
+
+	// Each job has 2 intervals (duration 20, interval 10)
+	enableAutoInvoice := func(job *models.Job, nextAt time.Time) {
+		enabled := true
+		_, err := jobRepo.SetAutoInvoice(ctx, &dto.SetJobAutoInvoiceRequest{JobID: job.ID, Enabled: &enabled, Cadence: models.InvoiceCadenceWeekly, NextAutoInvoiceAt: &nextAt})
+		require.NoError(t, err)
+	}
+	dueJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	enableAutoInvoice(dueJob, now.Add(-time.Hour))
+	notDueJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	enableAutoInvoice(notDueJob, now.Add(time.Hour))
+	caughtUpJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	createTestInvoice(t, ctx, pool, caughtUpJob.ID, 1, 500, models.InvoiceStateWaiting)
+	createTestInvoice(t, ctx, pool, caughtUpJob.ID, 2, 500, models.InvoiceStateWaiting)
+	enableAutoInvoice(caughtUpJob, now.Add(-time.Hour))
+
+	countInvoices := func(jobID uuid.UUID) int {
+		count, err := invoiceRepo.CountByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: jobID})
+		require.NoError(t, err)
+		return count
+	}
+
+	req := &dto.GenerateDueAutoInvoicesRequest{DueBefore: now, Limit: 10}
+	created, err := invoiceService.GenerateDueAutoInvoices(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 1, countInvoices(dueJob.ID))
+	assert.Equal(t, 0, countInvoices(notDueJob.ID))
+	assert.Equal(t, 2, countInvoices(caughtUpJob.ID))
+
+	// The due job is scheduled a week on, and the caught-up job isn't scheduled any more
+	dbDueJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: dueJob.ID})
+	require.NoError(t, err)
+	require.NotNil(t, dbDueJob.NextAutoInvoiceAt)
+	assert.WithinDuration(t, now.Add(-time.Hour).AddDate(0, 0, 7), *dbDueJob.NextAutoInvoiceAt, time.Second)
+	dbCaughtUpJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: caughtUpJob.ID})
+	require.NoError(t, err)
+	assert.Nil(t, dbCaughtUpJob.NextAutoInvoiceAt)
+
+	// Sweeping again creates nothing until the next invoice is due
+	created, err = invoiceService.GenerateDueAutoInvoices(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, 1, countInvoices(dueJob.ID))
+}
+
+func TestInvoiceService_Integration_ListOverdueInvoices(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "overdue-employer@test.com", "Overdue Employer")
+	otherEmployer := createTestUser(t, ctx, pool, "overdue-other@test.com", "Overdue Other")
+	contractor := createTestUser(t, ctx, pool, "overdue-contractor@test.com", "Overdue Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	otherJob := createTestJob(t, ctx, pool, otherEmployer.ID, models.JobStateOngoing, &contractor.ID)
+
+	// New invoices get the configured net-N due date
+	created, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	assert.WithinDuration(t, created.CreatedAt.AddDate(0, 0, models.DefaultInvoicePaymentTermDays), created.DueDate, time.Minute)
+	assert.False(t, created.IsOverdue(time.Now()))
+
+	setDueDate := func(invoice *models.Invoice, dueDate time.Time) {
+		_, err := pool.Exec(ctx, "UPDATE invoices SET due_date = $1 WHERE id = $2", dueDate, invoice.ID)
+		require.NoError(t, err)
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	future := time.Now().Add(48 * time.Hour)
+
+	overdue := createTestInvoice(t, ctx, pool, job.ID, 2, 100, models.InvoiceStateComplete)
+	setDueDate(overdue, past)
+	olderOverdue := createTestInvoice(t, ctx, pool, job.ID, 3, 100, models.InvoiceStateComplete)
+	setDueDate(olderOverdue, past.Add(-24*time.Hour))
+	notYetDue := createTestInvoice(t, ctx, pool, job.ID, 4, 100, models.InvoiceStateComplete)
+	setDueDate(notYetDue, future)
+	paid := createTestInvoice(t, ctx, pool, job.ID, 5, 100, models.InvoiceStatePaid) // Paid late, but paid
+	setDueDate(paid, past)
+	waiting := createTestInvoice(t, ctx, pool, job.ID, 6, 100, models.InvoiceStateWaiting) // Not accepted yet
+	setDueDate(waiting, past)
+	otherOverdue := createTestInvoice(t, ctx, pool, otherJob.ID, 1, 100, models.InvoiceStateComplete)
+	setDueDate(otherOverdue, past)
+
+	invoices, err := invoiceService.ListOverdueInvoices(ctx, employer.ID)
+	require.NoError(t, err)
+	require.Len(t, invoices, 2)
+	assert.Equal(t, olderOverdue.ID, invoices[0].ID, "Oldest due should come first")
+	assert.Equal(t, overdue.ID, invoices[1].ID)
+	for _, invoice := range invoices {
+		assert.True(t, invoice.IsOverdue(time.Now()))
+	}
+
+	// The contractor has no jobs as employer, so nothing is overdue for them
+	invoices, err = invoiceService.ListOverdueInvoices(ctx, contractor.ID)
+	require.NoError(t, err)
+	assert.Empty(t, invoices)
+
+	invoices, err = invoiceService.ListOverdueInvoices(ctx, otherEmployer.ID)
+	require.NoError(t, err)
+	require.Len(t, invoices, 1)
+	assert.Equal(t, otherOverdue.ID, invoices[0].ID)
+}
+
+func TestInvoiceService_Integration_ListInvoicesByJob_UpdatedSince(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "sync-invoice-employer@test.com", "Sync Invoice Employer")
+	contractor := createTestUser(t, ctx, pool, "sync-invoice-contractor@test.com", "Sync Invoice Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	first := createTestInvoice(t, ctx, pool, job.ID, 1, 500, models.InvoiceStateWaiting)
+	createTestInvoice(t, ctx, pool, job.ID, 2, 500, models.InvoiceStateWaiting)
+	third := createTestInvoice(t, ctx, pool, job.ID, 3, 500, models.InvoiceStateWaiting)
+	cutoff := latestUpdatedAt(t, ctx, pool, "invoices")
+
+	// Changed after the cutoff, third before first
+	_, err := pool.Exec(ctx, "UPDATE invoices SET state = $1 WHERE id = $2", models.InvoiceStateComplete, third.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "UPDATE invoices SET state = $1 WHERE id = $2", models.InvoiceStateComplete, first.ID)
+	require.NoError(t, err)
+
+	invoices, total, err := invoiceService.ListInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: job.ID, UserId: employer.ID, Limit: 10, UpdatedSince: &cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total, "Only invoices changed after the cutoff must be counted")
+	require.Len(t, invoices, 2)
+	assert.Equal(t, []uuid.UUID{third.ID, first.ID}, []uuid.UUID{invoices[0].ID, invoices[1].ID}, "Invoices must be ordered by updated_at ascending")
+}
+
+func TestInvoiceService_Integration_StreamInvoicesByJob(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "stream-invoice-employer@test.com", "Stream Invoice Employer")
+	contractor := createTestUser(t, ctx, pool, "stream-invoice-contractor@test.com", "Stream Invoice Contractor")
+	stranger := createTestUser(t, ctx, pool, "stream-invoice-stranger@test.com", "Stream Invoice Stranger")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	for i := 1; i <= 3; i++ {
+		createTestInvoice(t, ctx, pool, job.ID, i, 100, models.InvoiceStateWaiting)
+	}
+
+	// Every matching invoice in interval order, whatever the page size
+	var intervals []int
+	collect := func(invoice *models.Invoice) error {
+		intervals = append(intervals, invoice.IntervalNumber)
+		return nil
+	}
+	err := invoiceService.StreamInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: job.ID, UserId: contractor.ID, Limit: 1, MinInterval: ptrInt(2)}, collect)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, intervals)
+
+	// Same access rule as the list; nothing is streamed to others
+	intervals = nil
+	err = invoiceService.StreamInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: job.ID, UserId: stranger.ID}, collect)
+	assert.True(t, errors.Is(err, services.ErrForbidden), "Expected ErrForbidden, got %v", err)
+	assert.Empty(t, intervals)
+	err = invoiceService.StreamInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: uuid.New(), UserId: employer.ID}, collect)
+	assert.True(t, errors.Is(err, services.ErrNotFound), "Expected ErrNotFound, got %v", err)
+
+	// An error from the callback stops the stream
+	calls := 0
+	err = invoiceService.StreamInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: job.ID, UserId: employer.ID}, func(invoice *models.Invoice) error {
+		calls++
+		return errors.New("client went away")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestInvoiceService_Integration_ListEmployerInvoices(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "all-invoices-employer@test.com", "All Invoices Employer")
+	otherEmployer := createTestUser(t, ctx, pool, "all-invoices-other@test.com", "All Invoices Other")
+	contractor := createTestUser(t, ctx, pool, "all-invoices-contractor@test.com", "All Invoices Contractor")
+	firstJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	secondJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	otherJob := createTestJob(t, ctx, pool, otherEmployer.ID, models.JobStateOngoing, &contractor.ID)
+
+	oldest := createTestInvoice(t, ctx, pool, firstJob.ID, 1, 100, models.InvoiceStatePaid)
+	middle := createTestInvoice(t, ctx, pool, secondJob.ID, 1, 200, models.InvoiceStateWaiting)
+	newest := createTestInvoice(t, ctx, pool, firstJob.ID, 2, 300, models.InvoiceStateWaiting)
+	createTestInvoice(t, ctx, pool, otherJob.ID, 1, 400, models.InvoiceStateWaiting)
+	for i, invoice := range []*models.Invoice{oldest, middle, newest} {
+		_, err := pool.Exec(ctx, "UPDATE invoices SET created_at = $1 WHERE id = $2", time.Now().Add(time.Duration(i-3)*time.Hour), invoice.ID)
+		require.NoError(t, err)
+	}
+
+	invoices, total, err := invoiceService.ListEmployerInvoices(ctx, &dto.ListEmployerInvoicesRequest{EmployerID: employer.ID, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "Only the employer's invoices, across all their jobs")
+	require.Len(t, invoices, 2)
+	assert.Equal(t, []uuid.UUID{newest.ID, middle.ID}, []uuid.UUID{invoices[0].ID, invoices[1].ID}, "Newest first")
+
+	invoices, total, err = invoiceService.ListEmployerInvoices(ctx, &dto.ListEmployerInvoicesRequest{EmployerID: employer.ID, Limit: 10, State: ptrInvoiceState(models.InvoiceStatePaid)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, invoices, 1)
+	assert.Equal(t, oldest.ID, invoices[0].ID)
+
+	// The export has the same rows in the same order, unpaginated
+	var streamed []uuid.UUID
+	err = invoiceService.StreamEmployerInvoices(ctx, &dto.ListEmployerInvoicesRequest{EmployerID: employer.ID, Limit: 1}, func(invoice *models.Invoice) error {
+		streamed = append(streamed, invoice.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{newest.ID, middle.ID, oldest.ID}, streamed)
+
+	// Invoices of deleted jobs are left out
+	_, err = pool.Exec(ctx, "UPDATE jobs SET deleted_at = NOW() WHERE id = $1", secondJob.ID)
+	require.NoError(t, err)
+	_, total, err = invoiceService.ListEmployerInvoices(ctx, &dto.ListEmployerInvoicesRequest{EmployerID: employer.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+func TestInvoiceService_Integration_CreatedByUpdatedBy(t *testing.T) {
+	ctx, invoiceService, pool := setupInvoiceServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices", "outbox")
+	invoiceRepo := postgres.NewInvoiceRepo(pool)
+
+	employer := createTestUser(t, ctx, pool, "audit-inv-employer@test.com", "Audit Employer")
+	contractor := createTestUser(t, ctx, pool, "audit-inv-contractor@test.com", "Audit Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+
+	// The contractor invoicing the job is both its creator and its last updater
+	invoice, err := invoiceService.CreateInvoice(ctx, &dto.CreateInvoiceRequest{JobID: job.ID, UserId: contractor.ID})
+	require.NoError(t, err)
+	require.NotNil(t, invoice.CreatedBy)
+	require.NotNil(t, invoice.UpdatedBy)
+	assert.Equal(t, contractor.ID, *invoice.CreatedBy)
+	assert.Equal(t, contractor.ID, *invoice.UpdatedBy)
+
+	// The employer completing it moves updated_by but keeps created_by
+	updated, err := invoiceService.UpdateInvoiceState(ctx, &dto.UpdateInvoiceStateRequest{ID: invoice.ID, NewState: models.InvoiceStateComplete, UserId: employer.ID})
+	require.NoError(t, err)
+	require.NotNil(t, updated.UpdatedBy)
+	assert.Equal(t, contractor.ID, *updated.CreatedBy)
+	assert.Equal(t, employer.ID, *updated.UpdatedBy)
+
+	dbInvoice, err := invoiceRepo.GetByID(ctx, &dto.GetInvoiceByIDRequest{ID: invoice.ID})
+	require.NoError(t, err)
+	assert.Equal(t, contractor.ID, *dbInvoice.CreatedBy)
+	assert.Equal(t, employer.ID, *dbInvoice.UpdatedBy)
+
+	// A payment reported by a machine client is recorded against the system actor
+	paid, err := invoiceService.MarkInvoicePaid(ctx, &dto.MarkInvoicePaidRequest{ID: invoice.ID, ByService: true})
+	require.NoError(t, err)
+	assert.Equal(t, contractor.ID, *paid.CreatedBy)
+	assert.Equal(t, models.SystemActorID, *paid.UpdatedBy)
+
+	// So are invoices the scheduler creates
+	autoJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	enabled, nextAt := true, time.Now().Add(-time.Hour)
+	_, err = postgres.NewJobRepo(pool).SetAutoInvoice(ctx, &dto.SetJobAutoInvoiceRequest{JobID: autoJob.ID, UserID: employer.ID, Enabled: &enabled, Cadence: models.InvoiceCadenceWeekly, NextAutoInvoiceAt: &nextAt})
+	require.NoError(t, err)
+	created, err := invoiceService.GenerateDueAutoInvoices(ctx, &dto.GenerateDueAutoInvoicesRequest{DueBefore: time.Now(), Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+	autoInvoices, _, err := invoiceService.ListInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: autoJob.ID, UserId: employer.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, autoInvoices, 1)
+	assert.Equal(t, models.SystemActorID, *autoInvoices[0].CreatedBy)
+	assert.Equal(t, models.SystemActorID, *autoInvoices[0].UpdatedBy)
+}
@@ -1,13 +1,19 @@
 package integration_tests
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"go-api-template/internal/models"
+	"go-api-template/internal/notifications"
 	"go-api-template/internal/services"
 	"go-api-template/internal/storage"          // For storage errors
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres" // Need concrete repos for setup/assertion
 	"go-api-template/internal/transport/dto"
 
@@ -24,11 +30,38 @@ func setupJobApplicationServiceIntegrationTest(t *testing.T) (context.Context, s
 	t.Helper()
 	pool, _ := getTestClients(t)
 	// Instantiate the real service
-	jobAppService := services.NewJobApplicationService(pool)
+	jobAppService := services.NewJobApplicationService(pool, notifications.NewHub(nil, nil), 0, cache.Config{})
 	ctx := context.Background()
 	return ctx, jobAppService, pool
 }
 
+// recordingNotifier captures notifications instead of delivering them.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events map[uuid.UUID][]notifications.Event
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, userID uuid.UUID, event notifications.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.events == nil {
+		n.events = make(map[uuid.UUID][]notifications.Event)
+	}
+	n.events[userID] = append(n.events[userID], event)
+	return nil
+}
+
+// eventTypes returns the types of the events sent to userID, in order.
+func (n *recordingNotifier) eventTypes(userID uuid.UUID) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var types []string
+	for _, e := range n.events[userID] {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
 // Helper function to create an application for tests
 func createTestApplication(t *testing.T, ctx context.Context, pool *pgxpool.Pool, jobID, contractorID uuid.UUID, state models.JobApplicationState) *models.JobApplication {
 	t.Helper()
@@ -169,6 +202,108 @@ func TestJobApplicationService_Integration_ApplyToJob(t *testing.T) {
 	}
 }
 
+func TestJobApplicationService_Integration_ReapplyAfterWithdrawOrReject(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	appRepo := postgres.NewJobApplicationRepo(pool)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "reapply-employer@test.com", "Reapply Employer")
+	contractor := createTestUser(t, ctx, pool, "reapply-contractor@test.com", "Reapply Contractor")
+
+	tests := []struct {
+		name       string
+		closeFirst func(t *testing.T, app *models.JobApplication)
+	}{
+		{
+			name: "AfterWithdraw",
+			closeFirst: func(t *testing.T, app *models.JobApplication) {
+				_, err := jobAppService.WithdrawApplication(ctx, &dto.WithdrawApplicationRequest{ApplicationID: app.ID, UserID: contractor.ID})
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AfterReject",
+			closeFirst: func(t *testing.T, app *models.JobApplication) {
+				_, err := jobAppService.RejectApplication(ctx, &dto.RejectApplicationRequest{ApplicationID: app.ID, UserID: employer.ID})
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+			applyReq := &dto.ApplyToJobRequest{JobID: job.ID, ContractorID: contractor.ID}
+
+			first, err := jobAppService.ApplyToJob(ctx, applyReq)
+			require.NoError(t, err)
+			tt.closeFirst(t, first)
+
+			second, err := jobAppService.ApplyToJob(ctx, applyReq)
+			require.NoError(t, err, "Re-applying after the first application was closed must succeed")
+			assert.NotEqual(t, first.ID, second.ID)
+			assert.Equal(t, models.JobApplicationWaiting, second.State)
+
+			// The closed application is kept as history
+			old, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: first.ID})
+			require.NoError(t, err)
+			assert.NotEqual(t, models.JobApplicationWaiting, old.State)
+
+			// A third application is rejected while the second one is still Waiting
+			_, err = jobAppService.ApplyToJob(ctx, applyReq)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, services.ErrConflict), "Expected ErrConflict, got %v", err)
+		})
+	}
+}
+
+func TestJobApplicationService_Integration_ApplyToJobAfterDeadline(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "deadline-employer@test.com", "Deadline Employer")
+	contractor := createTestUser(t, ctx, pool, "deadline-contractor@test.com", "Deadline Contractor")
+	openJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	closedJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	// Deadlines can't be set in the past through the service, so write them directly
+	_, err := pool.Exec(ctx, "UPDATE jobs SET application_deadline = NOW() + INTERVAL '1 day' WHERE id = $1", openJob.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "UPDATE jobs SET application_deadline = NOW() - INTERVAL '1 minute' WHERE id = $1", closedJob.ID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		jobID         uuid.UUID
+		expectedErr   error
+		errorContains string
+	}{
+		{name: "Success_BeforeDeadline", jobID: openJob.ID},
+		{name: "Error_DeadlinePassed", jobID: closedJob.ID, expectedErr: services.ErrInvalidState, errorContains: "applications are closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			application, err := jobAppService.ApplyToJob(ctx, &dto.ApplyToJobRequest{JobID: tt.jobID, ContractorID: contractor.ID})
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.Nil(t, application)
+
+				var count int
+				require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM job_application WHERE job_id = $1", tt.jobID).Scan(&count))
+				assert.Zero(t, count, "No application should be stored for a closed job")
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, application)
+				assert.Equal(t, tt.jobID, application.JobID)
+			}
+		})
+	}
+}
+
 func TestJobApplicationService_Integration_AcceptApplication(t *testing.T) {
 	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
 	jobRepo := postgres.NewJobRepo(pool)     // For verification
@@ -377,6 +512,111 @@ func TestJobApplicationService_Integration_AcceptApplication(t *testing.T) {
 }
 
 // TestJobApplicationService_Integration_RejectApplication tests rejecting an application.
+func TestJobApplicationService_Integration_AcceptApplicationProposedRate(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool) // For verification
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "proposed-employer@test.com", "Proposed Employer")
+	contractor := createTestUser(t, ctx, pool, "proposed-contractor@test.com", "Proposed Contractor")
+	proposed := 65.5
+
+	tests := []struct {
+		name         string
+		proposedRate *float64
+		expectedRate float64
+	}{
+		{name: "WithProposedRate", proposedRate: &proposed, expectedRate: 65.5},
+		{name: "WithoutProposedRate", proposedRate: nil, expectedRate: 50.0}, // createTestJob's posted rate
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+			app, err := jobAppService.ApplyToJob(ctx, &dto.ApplyToJobRequest{JobID: job.ID, ContractorID: contractor.ID, ProposedRate: tt.proposedRate})
+			require.NoError(t, err)
+			assert.Equal(t, tt.proposedRate, app.ProposedRate)
+
+			updatedJob, err := jobAppService.AcceptApplication(ctx, &dto.AcceptApplicationRequest{ApplicationID: app.ID, UserID: employer.ID})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRate, updatedJob.Rate)
+			assert.Equal(t, models.JobStateOngoing, updatedJob.State)
+			require.NotNil(t, updatedJob.ContractorID)
+			assert.Equal(t, contractor.ID, *updatedJob.ContractorID)
+
+			// The rate change committed together with the assignment
+			dbJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRate, dbJob.Rate)
+			assert.Equal(t, models.JobStateOngoing, dbJob.State)
+		})
+	}
+}
+
+func TestJobApplicationService_Integration_AcceptApplicationOngoingJobLimit(t *testing.T) {
+	pool, _ := getTestClients(t)
+	ctx := context.Background()
+	jobAppService := services.NewJobApplicationService(pool, notifications.NewHub(nil, nil), 2, cache.Config{})
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "limit-employer@test.com", "Limit Employer")
+	unlimited, three := 0, 3
+
+	tests := []struct {
+		name        string
+		override    *int // users.max_ongoing_jobs; nil uses the configured limit of 2
+		ongoing     int  // Ongoing jobs the contractor already holds
+		others      int  // Jobs in other states, which don't count towards the limit
+		expectLimit bool
+	}{
+		{name: "UnderLimit", ongoing: 1, others: 2},
+		{name: "AtLimit", ongoing: 2, expectLimit: true},
+		{name: "OverrideRaisesLimit", override: &three, ongoing: 2},
+		{name: "OverrideReached", override: &three, ongoing: 3, expectLimit: true},
+		{name: "OverrideRemovesLimit", override: &unlimited, ongoing: 2},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contractor := createTestUser(t, ctx, pool, fmt.Sprintf("limit-contractor-%d@test.com", i), "Limit Contractor")
+			if tt.override != nil {
+				_, err := pool.Exec(ctx, `UPDATE users SET max_ongoing_jobs = $1 WHERE id = $2`, *tt.override, contractor.ID)
+				require.NoError(t, err)
+			}
+			for j := 0; j < tt.ongoing; j++ {
+				createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+			}
+			for j := 0; j < tt.others; j++ {
+				createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+			}
+			job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+			app := createTestApplication(t, ctx, pool, job.ID, contractor.ID, models.JobApplicationWaiting)
+
+			updatedJob, err := jobAppService.AcceptApplication(ctx, &dto.AcceptApplicationRequest{ApplicationID: app.ID, UserID: employer.ID})
+
+			if tt.expectLimit {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, services.ErrLimitExceeded)
+				assert.Nil(t, updatedJob)
+
+				// Nothing changed: the job is still open and the application still waiting
+				dbJob, getErr := postgres.NewJobRepo(pool).GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+				require.NoError(t, getErr)
+				assert.Equal(t, models.JobStateWaiting, dbJob.State)
+				assert.Nil(t, dbJob.ContractorID)
+				dbApp, getErr := postgres.NewJobApplicationRepo(pool).GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: app.ID})
+				require.NoError(t, getErr)
+				assert.Equal(t, models.JobApplicationWaiting, dbApp.State)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.JobStateOngoing, updatedJob.State)
+			require.NotNil(t, updatedJob.ContractorID)
+			assert.Equal(t, contractor.ID, *updatedJob.ContractorID)
+		})
+	}
+}
+
 func TestJobApplicationService_Integration_RejectApplication(t *testing.T) {
 	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
 	appRepo := postgres.NewJobApplicationRepo(pool) // For verification
@@ -483,6 +723,74 @@ func TestJobApplicationService_Integration_RejectApplication(t *testing.T) {
 }
 
 // TestJobApplicationService_Integration_WithdrawApplication tests withdrawing an application.
+func TestJobApplicationService_Integration_BulkRejectApplications(t *testing.T) {
+	pool, _ := getTestClients(t)
+	notifier := &recordingNotifier{}
+	jobAppService := services.NewJobApplicationService(pool, notifier, 0, cache.Config{})
+	ctx := context.Background()
+	appRepo := postgres.NewJobApplicationRepo(pool) // For verification
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "bulk-employer@test.com", "Bulk Employer")
+	otherEmployer := createTestUser(t, ctx, pool, "bulk-other@test.com", "Bulk Other Employer")
+	first := createTestUser(t, ctx, pool, "bulk-first@test.com", "Bulk First")
+	second := createTestUser(t, ctx, pool, "bulk-second@test.com", "Bulk Second")
+
+	ownJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	otherJob := createTestJob(t, ctx, pool, otherEmployer.ID, models.JobStateWaiting, nil)
+	waitingA := createTestApplication(t, ctx, pool, ownJob.ID, first.ID, models.JobApplicationWaiting)
+	waitingB := createTestApplication(t, ctx, pool, ownJob.ID, second.ID, models.JobApplicationWaiting)
+	withdrawn := createTestApplication(t, ctx, pool, createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil).ID, first.ID, models.JobApplicationWithdrawn)
+	unauthorized := createTestApplication(t, ctx, pool, otherJob.ID, first.ID, models.JobApplicationWaiting)
+	missing := uuid.New()
+
+	results, err := jobAppService.BulkRejectApplications(ctx, &dto.BulkRejectRequest{
+		ApplicationIDs: []uuid.UUID{waitingA.ID, unauthorized.ID, missing, withdrawn.ID, waitingB.ID, waitingA.ID},
+		UserID:         employer.ID,
+	})
+	require.NoError(t, err, "Per-application failures must not fail the batch")
+
+	// One result per distinct ID, in request order
+	require.Len(t, results, 5)
+	expected := []struct {
+		id  uuid.UUID
+		err error
+	}{
+		{id: waitingA.ID},
+		{id: unauthorized.ID, err: services.ErrForbidden},
+		{id: missing, err: services.ErrNotFound},
+		{id: withdrawn.ID, err: services.ErrInvalidState},
+		{id: waitingB.ID},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want.id, results[i].ApplicationID)
+		if want.err != nil {
+			assert.ErrorIs(t, results[i].Err, want.err, "result %d", i)
+			assert.Nil(t, results[i].Application)
+			continue
+		}
+		require.NoError(t, results[i].Err, "result %d", i)
+		require.NotNil(t, results[i].Application)
+		assert.Equal(t, models.JobApplicationRejected, results[i].Application.State)
+	}
+
+	// Only the employer's own waiting applications changed
+	for id, state := range map[uuid.UUID]models.JobApplicationState{
+		waitingA.ID:     models.JobApplicationRejected,
+		waitingB.ID:     models.JobApplicationRejected,
+		unauthorized.ID: models.JobApplicationWaiting,
+		withdrawn.ID:    models.JobApplicationWithdrawn,
+	} {
+		app, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: id})
+		require.NoError(t, err)
+		assert.Equal(t, state, app.State, "application %s", id)
+	}
+
+	// Each rejected contractor is notified once
+	assert.Equal(t, []string{notifications.EventApplicationRejected}, notifier.eventTypes(first.ID))
+	assert.Equal(t, []string{notifications.EventApplicationRejected}, notifier.eventTypes(second.ID))
+}
+
 func TestJobApplicationService_Integration_WithdrawApplication(t *testing.T) {
 	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
 	appRepo := postgres.NewJobApplicationRepo(pool) // For verification
@@ -589,6 +897,142 @@ func TestJobApplicationService_Integration_WithdrawApplication(t *testing.T) {
 }
 
 // TestJobApplicationService_Integration_GetApplicationByID tests getting an application by ID.
+func TestJobApplicationService_Integration_InviteContractor(t *testing.T) {
+	pool, _ := getTestClients(t)
+	notifier := &recordingNotifier{}
+	jobAppService := services.NewJobApplicationService(pool, notifier, 0, cache.Config{})
+	ctx := context.Background()
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "invite-employer@test.com", "Invite Employer")
+	contractor := createTestUser(t, ctx, pool, "invite-contractor@test.com", "Invite Contractor")
+	otherUser := createTestUser(t, ctx, pool, "invite-other@test.com", "Invite Other")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	message := "Your portfolio looks like a great fit"
+	invitation, err := jobAppService.InviteContractor(ctx, &dto.InviteContractorRequest{JobID: job.ID, ContractorID: contractor.ID, Message: &message, EmployerID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobApplicationInvited, invitation.State)
+	assert.Equal(t, contractor.ID, invitation.ContractorID)
+	require.NotNil(t, invitation.CoverMessage)
+	assert.Equal(t, message, *invitation.CoverMessage)
+	assert.Equal(t, []string{notifications.EventInvitationReceived}, notifier.eventTypes(contractor.ID), "The invited contractor is notified")
+
+	// An open invitation is an active application: neither a second invitation nor an application fits next to it
+	_, err = jobAppService.InviteContractor(ctx, &dto.InviteContractorRequest{JobID: job.ID, ContractorID: contractor.ID, EmployerID: employer.ID})
+	assert.ErrorIs(t, err, services.ErrConflict)
+	_, err = jobAppService.ApplyToJob(ctx, &dto.ApplyToJobRequest{JobID: job.ID, ContractorID: contractor.ID})
+	assert.ErrorIs(t, err, services.ErrConflict)
+
+	ongoingJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &otherUser.ID)
+	tests := []struct {
+		name        string
+		req         dto.InviteContractorRequest
+		expectedErr error
+	}{
+		{name: "SelfInvite", req: dto.InviteContractorRequest{JobID: job.ID, ContractorID: employer.ID, EmployerID: employer.ID}, expectedErr: services.ErrForbidden},
+		{name: "NotEmployer", req: dto.InviteContractorRequest{JobID: job.ID, ContractorID: otherUser.ID, EmployerID: contractor.ID}, expectedErr: services.ErrForbidden},
+		{name: "JobNotWaiting", req: dto.InviteContractorRequest{JobID: ongoingJob.ID, ContractorID: contractor.ID, EmployerID: employer.ID}, expectedErr: services.ErrInvalidState},
+		{name: "UnknownContractor", req: dto.InviteContractorRequest{JobID: job.ID, ContractorID: uuid.New(), EmployerID: employer.ID}, expectedErr: services.ErrNotFound},
+		{name: "UnknownJob", req: dto.InviteContractorRequest{JobID: uuid.New(), ContractorID: contractor.ID, EmployerID: employer.ID}, expectedErr: services.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jobAppService.InviteContractor(ctx, &tt.req)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+	assert.Len(t, notifier.eventTypes(contractor.ID), 1, "Failed invitations send nothing")
+	assert.Empty(t, notifier.eventTypes(employer.ID))
+}
+
+func TestJobApplicationService_Integration_AcceptInvitation(t *testing.T) {
+	pool, _ := getTestClients(t)
+	notifier := &recordingNotifier{}
+	jobAppService := services.NewJobApplicationService(pool, notifier, 0, cache.Config{})
+	ctx := context.Background()
+	appRepo := postgres.NewJobApplicationRepo(pool) // For verification
+	jobRepo := postgres.NewJobRepo(pool)            // For verification
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "accept-invite-employer@test.com", "Accept Invite Employer")
+	contractor := createTestUser(t, ctx, pool, "accept-invite-contractor@test.com", "Accept Invite Contractor")
+	applicant := createTestUser(t, ctx, pool, "accept-invite-applicant@test.com", "Accept Invite Applicant")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	application := createTestApplication(t, ctx, pool, job.ID, applicant.ID, models.JobApplicationWaiting)
+	invitation, err := jobAppService.InviteContractor(ctx, &dto.InviteContractorRequest{JobID: job.ID, ContractorID: contractor.ID, EmployerID: employer.ID})
+	require.NoError(t, err)
+
+	// Only the invited contractor can accept, and the employer can't accept it as an application
+	_, err = jobAppService.AcceptInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: employer.ID})
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	_, err = jobAppService.AcceptApplication(ctx, &dto.AcceptApplicationRequest{ApplicationID: invitation.ID, UserID: employer.ID})
+	assert.ErrorIs(t, err, services.ErrInvalidState)
+	_, err = jobAppService.AcceptInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: application.ID, UserID: applicant.ID})
+	assert.ErrorIs(t, err, services.ErrInvalidState, "A plain application isn't an invitation")
+
+	updatedJob, err := jobAppService.AcceptInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateOngoing, updatedJob.State)
+	require.NotNil(t, updatedJob.ContractorID)
+	assert.Equal(t, contractor.ID, *updatedJob.ContractorID)
+	assert.Equal(t, job.Rate, updatedJob.Rate, "Invitations keep the job's rate")
+
+	dbJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateOngoing, dbJob.State)
+	dbInvitation, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: invitation.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobApplicationAccepted, dbInvitation.State)
+	dbApplication, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: application.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobApplicationRejected, dbApplication.State, "Other pending applications are rejected")
+	assert.Equal(t, []string{notifications.EventInvitationAccepted}, notifier.eventTypes(employer.ID))
+
+	// An answered invitation can't be answered again
+	_, err = jobAppService.AcceptInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: contractor.ID})
+	assert.ErrorIs(t, err, services.ErrInvalidState)
+	_, err = jobAppService.DeclineInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: contractor.ID})
+	assert.ErrorIs(t, err, services.ErrInvalidState)
+}
+
+func TestJobApplicationService_Integration_DeclineInvitation(t *testing.T) {
+	pool, _ := getTestClients(t)
+	notifier := &recordingNotifier{}
+	jobAppService := services.NewJobApplicationService(pool, notifier, 0, cache.Config{})
+	ctx := context.Background()
+	jobRepo := postgres.NewJobRepo(pool) // For verification
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "decline-invite-employer@test.com", "Decline Invite Employer")
+	contractor := createTestUser(t, ctx, pool, "decline-invite-contractor@test.com", "Decline Invite Contractor")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	invitation, err := jobAppService.InviteContractor(ctx, &dto.InviteContractorRequest{JobID: job.ID, ContractorID: contractor.ID, EmployerID: employer.ID})
+	require.NoError(t, err)
+
+	_, err = jobAppService.DeclineInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: employer.ID})
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	_, err = jobAppService.DeclineInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: uuid.New(), UserID: contractor.ID})
+	assert.ErrorIs(t, err, services.ErrNotFound)
+
+	declined, err := jobAppService.DeclineInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobApplicationDeclined, declined.State)
+	assert.Equal(t, []string{notifications.EventInvitationDeclined}, notifier.eventTypes(employer.ID))
+
+	// The job stays open, and the declined invitation doesn't block inviting or applying again
+	dbJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateWaiting, dbJob.State)
+	assert.Nil(t, dbJob.ContractorID)
+	_, err = jobAppService.AcceptInvitation(ctx, &dto.RespondToInvitationRequest{ApplicationID: invitation.ID, UserID: contractor.ID})
+	assert.ErrorIs(t, err, services.ErrInvalidState)
+	_, err = jobAppService.ApplyToJob(ctx, &dto.ApplyToJobRequest{JobID: job.ID, ContractorID: contractor.ID})
+	assert.NoError(t, err)
+}
+
 func TestJobApplicationService_Integration_GetApplicationByID(t *testing.T) {
 	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
 	defer cleanupTables(t, pool, "users", "jobs", "job_application")
@@ -669,10 +1113,17 @@ func TestJobApplicationService_Integration_ListApplicationsByContractor(t *testi
 		Offset:       0,
 	}
 
-	apps, err := jobAppService.ListApplicationsByContractor(ctx, req)
+	apps, total, err := jobAppService.ListApplicationsByContractor(ctx, req)
 
 	require.NoError(t, err)
 	assert.Len(t, apps, 2)
+	assert.Equal(t, 2, total)
+
+	// Total ignores the page size but still excludes other contractors' applications
+	page, total, err := jobAppService.ListApplicationsByContractor(ctx, &dto.ListJobApplicationsByContractorRequest{ContractorID: contractor1.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, 2, total)
 
 	// Check if the correct applications were returned (order might vary based on DB)
 	foundApp1 := false
@@ -692,6 +1143,163 @@ func TestJobApplicationService_Integration_ListApplicationsByContractor(t *testi
 	assert.True(t, foundApp2, "Application 2 for contractor 1 not found")
 }
 
+func TestJobApplicationService_Integration_ListApplicationsByContractorStateFilter(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "listconstate-emp@test.com", "ListConState Emp")
+	contractor := createTestUser(t, ctx, pool, "listconstate-con@test.com", "ListConState Con")
+	otherContractor := createTestUser(t, ctx, pool, "listconstate-other@test.com", "ListConState Other")
+
+	// One application per state, plus a second waiting one to exercise paging within the filter
+	seeded := map[models.JobApplicationState][]uuid.UUID{}
+	states := []models.JobApplicationState{
+		models.JobApplicationWaiting,
+		models.JobApplicationWaiting,
+		models.JobApplicationAccepted,
+		models.JobApplicationRejected,
+		models.JobApplicationWithdrawn,
+	}
+	for _, state := range states {
+		job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+		app := createTestApplication(t, ctx, pool, job.ID, contractor.ID, state)
+		seeded[state] = append(seeded[state], app.ID)
+		// Another contractor's application in the same state must never leak into the results
+		_ = createTestApplication(t, ctx, pool, job.ID, otherContractor.ID, state)
+	}
+
+	for _, state := range []models.JobApplicationState{
+		models.JobApplicationWaiting,
+		models.JobApplicationAccepted,
+		models.JobApplicationRejected,
+		models.JobApplicationWithdrawn,
+	} {
+		t.Run(string(state), func(t *testing.T) {
+			apps, total, err := jobAppService.ListApplicationsByContractor(ctx, &dto.ListJobApplicationsByContractorRequest{
+				ContractorID: contractor.ID,
+				State:        &state,
+				Limit:        10,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, len(seeded[state]), total)
+			require.Len(t, apps, len(seeded[state]))
+			for _, app := range apps {
+				assert.Equal(t, contractor.ID, app.ContractorID)
+				assert.Equal(t, state, app.State)
+				assert.Contains(t, seeded[state], app.ID)
+			}
+		})
+	}
+
+	t.Run("NoFilter", func(t *testing.T) {
+		apps, total, err := jobAppService.ListApplicationsByContractor(ctx, &dto.ListJobApplicationsByContractorRequest{
+			ContractorID: contractor.ID,
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Len(t, apps, len(states))
+		assert.Equal(t, len(states), total)
+	})
+
+	t.Run("FilterWithPagination", func(t *testing.T) {
+		waiting := models.JobApplicationWaiting
+		req := &dto.ListJobApplicationsByContractorRequest{ContractorID: contractor.ID, State: &waiting, Limit: 1}
+
+		first, total, err := jobAppService.ListApplicationsByContractor(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+		assert.Equal(t, 2, total)
+
+		req.Offset = 1
+		second, total, err := jobAppService.ListApplicationsByContractor(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Equal(t, 2, total)
+		assert.NotEqual(t, first[0].ID, second[0].ID)
+	})
+}
+
+func TestJobApplicationService_Integration_ListAppliedJobs(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+	jobRepo := postgres.NewJobRepo(pool)
+
+	employer := createTestUser(t, ctx, pool, "applied-emp@test.com", "Applied Emp")
+	contractor := createTestUser(t, ctx, pool, "applied-con@test.com", "Applied Con")
+	otherContractor := createTestUser(t, ctx, pool, "applied-other@test.com", "Applied Other")
+
+	// One job per application outcome, each with its own description so the join can be checked
+	states := []models.JobApplicationState{
+		models.JobApplicationWaiting,
+		models.JobApplicationAccepted,
+		models.JobApplicationRejected,
+		models.JobApplicationWithdrawn,
+	}
+	descriptions := map[uuid.UUID]string{} // By application ID
+	for _, state := range states {
+		job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+		description := "Job for a " + string(state) + " application"
+		_, err := jobRepo.Update(ctx, &dto.UpdateJobRequest{ID: job.ID, Version: job.Version, Description: &description, UpdatedBy: employer.ID})
+		require.NoError(t, err)
+		app := createTestApplication(t, ctx, pool, job.ID, contractor.ID, state)
+		descriptions[app.ID] = description
+		_ = createTestApplication(t, ctx, pool, job.ID, otherContractor.ID, state) // Must not leak into the results
+	}
+
+	// Applications to deleted jobs are left out
+	deletedJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	createTestApplication(t, ctx, pool, deletedJob.ID, contractor.ID, models.JobApplicationWaiting)
+	require.NoError(t, jobRepo.Delete(ctx, &dto.DeleteJobRequest{ID: deletedJob.ID, UserID: employer.ID}))
+
+	t.Run("AllOutcomes", func(t *testing.T) {
+		appliedJobs, total, err := jobAppService.ListAppliedJobs(ctx, &dto.ListAppliedJobsRequest{ContractorID: contractor.ID, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, len(states), total)
+		require.Len(t, appliedJobs, len(states))
+
+		seenStates := []models.JobApplicationState{}
+		for _, applied := range appliedJobs {
+			assert.Equal(t, contractor.ID, applied.Application.ContractorID)
+			assert.Equal(t, applied.Application.JobID, applied.Job.ID, "each application comes with its own job")
+			assert.Equal(t, employer.ID, applied.Job.EmployerID)
+			assert.Equal(t, descriptions[applied.Application.ID], applied.Job.Description)
+			assert.NotEqual(t, deletedJob.ID, applied.Job.ID)
+			seenStates = append(seenStates, applied.Application.State)
+		}
+		assert.ElementsMatch(t, states, seenStates)
+		for i := 1; i < len(appliedJobs); i++ {
+			assert.False(t, appliedJobs[i].Application.CreatedAt.After(appliedJobs[i-1].Application.CreatedAt), "most recent application first")
+		}
+	})
+
+	t.Run("StateFilter", func(t *testing.T) {
+		rejected := models.JobApplicationRejected
+		appliedJobs, total, err := jobAppService.ListAppliedJobs(ctx, &dto.ListAppliedJobsRequest{ContractorID: contractor.ID, State: &rejected, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, appliedJobs, 1)
+		assert.Equal(t, models.JobApplicationRejected, appliedJobs[0].Application.State)
+		assert.Equal(t, "Job for a Rejected application", appliedJobs[0].Job.Description)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		req := &dto.ListAppliedJobsRequest{ContractorID: contractor.ID, Limit: 3}
+		first, total, err := jobAppService.ListAppliedJobs(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, len(states), total)
+		require.Len(t, first, 3)
+
+		req.Offset = 3
+		second, total, err := jobAppService.ListAppliedJobs(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, len(states), total)
+		require.Len(t, second, 1)
+		for _, applied := range first {
+			assert.NotEqual(t, applied.Application.ID, second[0].Application.ID)
+		}
+	})
+}
+
 // TestJobApplicationService_Integration_ListApplicationsByJob tests listing applications for a job.
 func TestJobApplicationService_Integration_ListApplicationsByJob(t *testing.T) {
 	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
@@ -711,10 +1319,13 @@ func TestJobApplicationService_Integration_ListApplicationsByJob(t *testing.T) {
 	// App for job2
 	_ = createTestApplication(t, ctx, pool, job2.ID, contractor1.ID, models.JobApplicationWaiting)
 
+	waiting := models.JobApplicationWaiting
+
 	tests := []struct {
 		name          string
 		req           *dto.ListJobApplicationsByJobRequest
 		expectedCount int
+		expectedTotal int // Rows for the job, regardless of limit/offset
 		expectedErr   error
 	}{
 		{
@@ -725,8 +1336,43 @@ func TestJobApplicationService_Integration_ListApplicationsByJob(t *testing.T) {
 				Limit:  10, Offset: 0,
 			},
 			expectedCount: 2,
+			expectedTotal: 2,
+			expectedErr:   nil,
+		},
+		{
+			name: "Success_SecondPage",
+			req: &dto.ListJobApplicationsByJobRequest{
+				JobID:  job1.ID,
+				UserID: employer.ID,
+				Limit:  1, Offset: 1,
+			},
+			expectedCount: 1,
+			expectedTotal: 2,
+			expectedErr:   nil,
+		},
+		{
+			name: "Success_FilterByState",
+			req: &dto.ListJobApplicationsByJobRequest{
+				JobID:  job1.ID,
+				UserID: employer.ID,
+				Limit:  10, Offset: 0,
+				State:  &waiting, // The rejected application must be left out
+			},
+			expectedCount: 1,
+			expectedTotal: 1,
 			expectedErr:   nil,
 		},
+		{
+			name: "Error_Forbidden_NotEmployer_FilterByState",
+			req: &dto.ListJobApplicationsByJobRequest{
+				JobID:  job1.ID,
+				UserID: otherUser.ID,
+				Limit:  10, Offset: 0,
+				State:  &waiting,
+			},
+			expectedCount: 0,
+			expectedErr:   services.ErrForbidden,
+		},
 		{
 			name: "Error_Forbidden_NotEmployer",
 			req: &dto.ListJobApplicationsByJobRequest{
@@ -751,7 +1397,7 @@ func TestJobApplicationService_Integration_ListApplicationsByJob(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			apps, err := jobAppService.ListApplicationsByJob(ctx, tt.req)
+			apps, total, err := jobAppService.ListApplicationsByJob(ctx, tt.req)
 
 			if tt.expectedErr != nil {
 				require.Error(t, err)
@@ -760,6 +1406,7 @@ func TestJobApplicationService_Integration_ListApplicationsByJob(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.Len(t, apps, tt.expectedCount)
+				assert.Equal(t, tt.expectedTotal, total)
 				// Verify apps belong to the correct job
 				for _, app := range apps {
 					assert.Equal(t, tt.req.JobID, app.JobID)
@@ -775,7 +1422,190 @@ func TestJobApplicationService_Integration_ListApplicationsByJob(t *testing.T) {
 					assert.True(t, foundApp1, "App1 for job1 not found")
 					assert.True(t, foundApp2, "App2 for job1 not found")
 				}
+				if tt.req.State != nil {
+					for _, app := range apps {
+						assert.Equal(t, *tt.req.State, app.State)
+						assert.NotEqual(t, app2Job1.ID, app.ID, "Rejected application should be filtered out")
+					}
+				}
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestJobApplicationService_Integration_ExportApplicationsCSV(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "export-employer@test.com", "Export Employer")
+	contractor1 := createTestUser(t, ctx, pool, "export-contractor1@test.com", "Export Contractor 1")
+	contractor2 := createTestUser(t, ctx, pool, "export-contractor2@test.com", "Export Contractor 2")
+	contractor3 := createTestUser(t, ctx, pool, "export-contractor3@test.com", "=1+1")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	createTestApplication(t, ctx, pool, job.ID, contractor1.ID, models.JobApplicationWaiting)
+	createTestApplication(t, ctx, pool, job.ID, contractor2.ID, models.JobApplicationRejected)
+	_, err := postgres.NewJobApplicationRepo(pool).Create(ctx, &dto.CreateJobApplicationRequest{JobID: job.ID, ContractorID: contractor3.ID, CoverMessage: ptrString(`=HYPERLINK("https://attacker.example","Click")`)})
+	require.NoError(t, err)
+
+	t.Run("Success_AsEmployer", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := jobAppService.ExportApplicationsCSV(ctx, &dto.ExportJobApplicationsCSVRequest{JobID: job.ID, UserID: employer.ID}, &buf)
+		require.NoError(t, err)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4) // Header + 3 applications
+		assert.Equal(t, []string{"contractor_name", "application_date", "state", "cover_message", "employer_note"}, records[0])
+		assert.Equal(t, contractor1.Name, records[1][0])
+		assert.Equal(t, string(models.JobApplicationWaiting), records[1][2])
+		assert.Equal(t, contractor2.Name, records[2][0])
+		assert.Equal(t, string(models.JobApplicationRejected), records[2][2])
+
+		// Cells a spreadsheet would evaluate as formulas are exported as text
+		assert.Equal(t, "'=1+1", records[3][0])
+		assert.Equal(t, `'=HYPERLINK("https://attacker.example","Click")`, records[3][3])
+	})
+
+	t.Run("Success_FilteredByState", func(t *testing.T) {
+		rejected := models.JobApplicationRejected
+		var buf bytes.Buffer
+		err := jobAppService.ExportApplicationsCSV(ctx, &dto.ExportJobApplicationsCSVRequest{JobID: job.ID, UserID: employer.ID, State: &rejected}, &buf)
+		require.NoError(t, err)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2) // Header + the rejected application
+		assert.Equal(t, contractor2.Name, records[1][0])
+		assert.Equal(t, string(models.JobApplicationRejected), records[1][2])
+	})
+
+	t.Run("Error_Forbidden_NotEmployer", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := jobAppService.ExportApplicationsCSV(ctx, &dto.ExportJobApplicationsCSVRequest{JobID: job.ID, UserID: contractor1.ID}, &buf)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, services.ErrForbidden), "Expected ErrForbidden, got %v", err)
+		assert.Zero(t, buf.Len(), "Nothing should be written for an unauthorized export")
+	})
+
+	t.Run("Error_JobNotFound", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := jobAppService.ExportApplicationsCSV(ctx, &dto.ExportJobApplicationsCSVRequest{JobID: uuid.New(), UserID: employer.ID}, &buf)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, services.ErrNotFound), "Expected ErrNotFound, got %v", err)
+	})
+}
+
+func TestJobApplicationService_Integration_AcceptApplicationConcurrent(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)
+	appRepo := postgres.NewJobApplicationRepo(pool)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "race-employer@test.com", "Race Employer")
+	contractor1 := createTestUser(t, ctx, pool, "race-contractor1@test.com", "Race Contractor 1")
+	contractor2 := createTestUser(t, ctx, pool, "race-contractor2@test.com", "Race Contractor 2")
+
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	app1 := createTestApplication(t, ctx, pool, job.ID, contractor1.ID, models.JobApplicationWaiting)
+	app2 := createTestApplication(t, ctx, pool, job.ID, contractor2.ID, models.JobApplicationWaiting)
+
+	// Fire both accepts at once so they race on the same job
+	appIDs := []uuid.UUID{app1.ID, app2.ID}
+	errs := make([]error, len(appIDs))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, appID := range appIDs {
+		wg.Add(1)
+		go func(i int, appID uuid.UUID) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = jobAppService.AcceptApplication(ctx, &dto.AcceptApplicationRequest{ApplicationID: appID, UserID: employer.ID})
+		}(i, appID)
+	}
+	close(start)
+	wg.Wait()
+
+	// Exactly one accept wins; the loser sees a conflict (or an invalid state if it read after the winner committed)
+	successes := 0
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			successes++
+			winner = i
+			continue
+		}
+		assert.True(t, errors.Is(err, services.ErrConflict) || errors.Is(err, services.ErrInvalidState), "unexpected error: %v", err)
+	}
+	require.Equal(t, 1, successes, "exactly one concurrent accept should succeed")
+
+	dbJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateOngoing, dbJob.State)
+	require.NotNil(t, dbJob.ContractorID)
+	winnerContractor := []uuid.UUID{contractor1.ID, contractor2.ID}[winner]
+	assert.Equal(t, winnerContractor, *dbJob.ContractorID)
+
+	winnerApp, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: appIDs[winner]})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobApplicationAccepted, winnerApp.State)
+	loserApp, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: appIDs[1-winner]})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobApplicationRejected, loserApp.State)
+}
+
+func TestJobApplicationService_Integration_Notifications(t *testing.T) {
+	pool, _ := getTestClients(t)
+	notifier := &recordingNotifier{}
+	jobAppService := services.NewJobApplicationService(pool, notifier, 0, cache.Config{})
+	ctx := context.Background()
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "notify-employer@test.com", "Notify Employer")
+	accepted := createTestUser(t, ctx, pool, "notify-accepted@test.com", "Notify Accepted")
+	rejected := createTestUser(t, ctx, pool, "notify-rejected@test.com", "Notify Rejected")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	// Applying notifies the employer
+	acceptedApp, err := jobAppService.ApplyToJob(ctx, &dto.ApplyToJobRequest{JobID: job.ID, ContractorID: accepted.ID})
+	require.NoError(t, err)
+	rejectedApp, err := jobAppService.ApplyToJob(ctx, &dto.ApplyToJobRequest{JobID: job.ID, ContractorID: rejected.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{notifications.EventApplicationReceived, notifications.EventApplicationReceived}, notifier.eventTypes(employer.ID))
+
+	// Rejecting and accepting notify the contractor
+	_, err = jobAppService.RejectApplication(ctx, &dto.RejectApplicationRequest{ApplicationID: rejectedApp.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{notifications.EventApplicationRejected}, notifier.eventTypes(rejected.ID))
+
+	_, err = jobAppService.AcceptApplication(ctx, &dto.AcceptApplicationRequest{ApplicationID: acceptedApp.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{notifications.EventApplicationAccepted}, notifier.eventTypes(accepted.ID))
+
+	// Failed operations send nothing
+	_, err = jobAppService.RejectApplication(ctx, &dto.RejectApplicationRequest{ApplicationID: rejectedApp.ID, UserID: employer.ID})
+	require.Error(t, err)
+	assert.Len(t, notifier.eventTypes(rejected.ID), 1)
+}
+
+func TestJobApplicationService_Integration_ListApplicationsByContractor_UpdatedSince(t *testing.T) {
+	ctx, jobAppService, pool := setupJobApplicationServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "job_application")
+
+	employer := createTestUser(t, ctx, pool, "sync-app-employer@test.com", "Sync App Employer")
+	contractor := createTestUser(t, ctx, pool, "sync-app-contractor@test.com", "Sync App Contractor")
+	var apps []*models.JobApplication
+	for range 3 {
+		job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+		apps = append(apps, createTestApplication(t, ctx, pool, job.ID, contractor.ID, models.JobApplicationWaiting))
+	}
+	cutoff := latestUpdatedAt(t, ctx, pool, "job_application")
+
+	_, err := pool.Exec(ctx, "UPDATE job_application SET state = $1 WHERE id = $2", models.JobApplicationRejected, apps[1].ID)
+	require.NoError(t, err)
+
+	applications, total, err := jobAppService.ListApplicationsByContractor(ctx, &dto.ListJobApplicationsByContractorRequest{ContractorID: contractor.ID, Limit: 10, UpdatedSince: &cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total, "Only applications changed after the cutoff must be counted")
+	require.Len(t, applications, 1)
+	assert.Equal(t, apps[1].ID, applications[0].ID)
+	assert.Equal(t, models.JobApplicationRejected, applications[0].State)
+}
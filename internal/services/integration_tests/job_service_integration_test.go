@@ -2,18 +2,26 @@ package integration_tests
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
+	"sync"
 	"testing"
 	"time"
 
 	"go-api-template/internal/models"
+	"go-api-template/internal/notifications"
+	"go-api-template/internal/scheduler"
 	"go-api-template/internal/services"
 	"go-api-template/internal/storage"          // For storage errors
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres" // Need concrete repo for setup/assertion
 	"go-api-template/internal/transport/dto"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,7 +34,7 @@ func setupJobServiceIntegrationTest(t *testing.T) (context.Context, services.Job
 	t.Helper() // Mark as test helper
 	pool, _ := getTestClients(t)
 	// Instantiate the real service using the constructor that creates repos internally
-	jobService := services.NewJobService(pool)
+	jobService := services.NewJobService(pool, models.DefaultInvoicePaymentTermDays, models.DefaultCurrency, 0, cache.Config{})
 	ctx := context.Background()
 	return ctx, jobService, pool
 }
@@ -58,6 +66,7 @@ func TestJobService_Integration_CreateJobAndGetByID(t *testing.T) {
 	assert.Equal(t, createReq.InvoiceInterval, createdJob.InvoiceInterval)
 	assert.Equal(t, createReq.EmployerID, createdJob.EmployerID)
 	assert.Equal(t, models.JobStateWaiting, createdJob.State)
+	assert.Equal(t, models.DefaultCurrency, createdJob.Currency, "Jobs posted without a currency get the configured one")
 	assert.Nil(t, createdJob.ContractorID)
 
 	// Verify directly in DB
@@ -93,6 +102,34 @@ func TestJobService_Integration_CreateJobAndGetByID(t *testing.T) {
 	assert.True(t, errors.Is(err, services.ErrNotFound))
 }
 
+func TestJobService_Integration_CreateJob_Currency(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	employer := createTestUser(t, ctx, pool, "currency-employer@test.com", "Currency Employer")
+	newRequest := func(currency string) *dto.CreateJobRequest {
+		return &dto.CreateJobRequest{Rate: 80, Duration: 40, InvoiceInterval: 10, Currency: currency, EmployerID: employer.ID}
+	}
+
+	job, err := jobService.CreateJob(ctx, newRequest("eur"))
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", job.Currency, "Codes are stored upper-case")
+
+	fetched, err := jobService.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", fetched.Currency)
+
+	for _, invalid := range []string{"XYZ", "EURO", "$"} {
+		_, err := jobService.CreateJob(ctx, newRequest(invalid))
+		require.ErrorIs(t, err, services.ErrValidation, "currency %q", invalid)
+		assert.Contains(t, err.Error(), "ISO 4217")
+	}
+
+	var count int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM jobs WHERE employer_id = $1", employer.ID).Scan(&count))
+	assert.Equal(t, 1, count, "Rejected jobs must not be saved")
+}
+
 func TestJobService_Integration_UpdateJobDetails(t *testing.T) {
 	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
 	jobRepo := postgres.NewJobRepo(pool)   // Need for verification
@@ -141,6 +178,17 @@ func TestJobService_Integration_UpdateJobDetails(t *testing.T) {
 			expectedDur:  50, // First success will update it to 50
 			expectedErr:  nil,
 		},
+		{
+			// Another editor's changes (the two successes above) landed after version 1 was read
+			name: "Error_StaleVersion",
+			req: &dto.UpdateJobDetailsRequest{
+				UserID:  employer.ID,
+				Version: 1,
+				Rate:    ptrFloat64(130.0),
+			},
+			targetJobID: jobWaiting.ID,
+			expectedErr: services.ErrConflict,
+		},
 		{
 			name: "Error_Forbidden_WrongUser",
 			req: &dto.UpdateJobDetailsRequest{
@@ -185,6 +233,9 @@ func TestJobService_Integration_UpdateJobDetails(t *testing.T) {
 			initialJob, _ := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: tt.targetJobID})
 
 			tt.req.JobID = tt.targetJobID // Set JobID for the request
+			if tt.req.Version == 0 && initialJob != nil {
+				tt.req.Version = initialJob.Version // Unless the case says otherwise, update the latest version
+			}
 
 			updatedJob, err := jobService.UpdateJobDetails(ctx, tt.req)
 
@@ -215,6 +266,7 @@ func TestJobService_Integration_UpdateJobDetails(t *testing.T) {
 				assert.Nil(t, updatedJob.ContractorID)                    // Should remain nil
 				require.NotNil(t, initialJob)                              // Should exist for success case
 				assert.True(t, updatedJob.UpdatedAt.After(initialJob.UpdatedAt))
+				assert.Equal(t, initialJob.Version+1, updatedJob.Version)
 
 				// Verify in DB
 				dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: tt.targetJobID})
@@ -378,7 +430,7 @@ func TestJobService_Integration_UpdateJobState(t *testing.T) {
 func TestJobService_Integration_DeleteJob(t *testing.T) {
 	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
 	jobRepo := postgres.NewJobRepo(pool)   // Need for verification
-	defer cleanupTables(t, pool, "users", "jobs")
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
 
 	employer := createTestUser(t, ctx, pool, "deletejob-employer@test.com", "DeleteJob Employer")
 	otherUser := createTestUser(t, ctx, pool, "deletejob-other@test.com", "DeleteJob Other")
@@ -435,6 +487,19 @@ func TestJobService_Integration_DeleteJob(t *testing.T) {
 			},
 			expectedErr: services.ErrInvalidState,
 		},
+		{
+			// Back to Waiting after the contractor was unassigned, but already invoiced
+			name: "Error_InvalidState_InvoicesExist",
+			setupFunc: func() uuid.UUID {
+				job := createJobForTest(models.JobStateWaiting, nil)
+				createTestInvoice(t, ctx, pool, job.ID, 1, 100, models.InvoiceStateWaiting)
+				return job.ID
+			},
+			req: &dto.DeleteJobRequest{
+				UserID: employer.ID,
+			},
+			expectedErr: services.ErrInvalidState,
+		},
 		{
 			name: "Error_JobNotFound",
 			setupFunc: func() uuid.UUID {
@@ -466,13 +531,469 @@ func TestJobService_Integration_DeleteJob(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 
-				// Verify job is gone from DB
+				// Verify job is hidden from reads
 				_, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: targetJobID})
 				require.Error(t, dbErr)
 				assert.True(t, errors.Is(dbErr, storage.ErrNotFound), "Job should be deleted")
+
+				// Verify the row is kept with deleted_at set
+				var deletedAt *time.Time
+				err = pool.QueryRow(ctx, `SELECT deleted_at FROM jobs WHERE id = $1`, targetJobID).Scan(&deletedAt)
+				require.NoError(t, err, "Soft-deleted job row should still exist")
+				assert.NotNil(t, deletedAt, "deleted_at should be set")
+			}
+		})
+	}
+}
+
+func TestJobService_Integration_TransferOwnership(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)     // Need for verification
+	auditRepo := postgres.NewAuditRepo(pool) // Need for verification
+	defer cleanupTables(t, pool, "users", "jobs", "audit_log")
+
+	employer := createTestUser(t, ctx, pool, "transferjob-employer@test.com", "TransferJob Employer")
+	newEmployer := createTestUser(t, ctx, pool, "transferjob-new@test.com", "TransferJob New Employer")
+	otherUser := createTestUser(t, ctx, pool, "transferjob-other@test.com", "TransferJob Other")
+	contractor := createTestUser(t, ctx, pool, "transferjob-contractor@test.com", "TransferJob Contractor")
+
+	tests := []struct {
+		name        string
+		setupFunc   func() uuid.UUID // Returns JobID for the test
+		req         *dto.TransferJobRequest
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupFunc: func() uuid.UUID {
+				return createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil).ID
+			},
+			req:         &dto.TransferJobRequest{UserID: employer.ID, NewEmployerID: newEmployer.ID},
+			expectedErr: nil,
+		},
+		{
+			name: "Error_Forbidden_NotEmployer",
+			setupFunc: func() uuid.UUID {
+				return createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil).ID
+			},
+			req:         &dto.TransferJobRequest{UserID: otherUser.ID, NewEmployerID: newEmployer.ID},
+			expectedErr: services.ErrForbidden,
+		},
+		{
+			name: "Error_InvalidState_ContractorAssigned",
+			setupFunc: func() uuid.UUID {
+				return createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID).ID
+			},
+			req:         &dto.TransferJobRequest{UserID: employer.ID, NewEmployerID: newEmployer.ID},
+			expectedErr: services.ErrInvalidState,
+		},
+		{
+			name: "Error_Validation_UnknownNewEmployer",
+			setupFunc: func() uuid.UUID {
+				return createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil).ID
+			},
+			req:         &dto.TransferJobRequest{UserID: employer.ID, NewEmployerID: uuid.New()},
+			expectedErr: services.ErrValidation,
+		},
+		{
+			name: "Error_JobNotFound",
+			setupFunc: func() uuid.UUID {
+				return uuid.New() // Non-existent ID
+			},
+			req:         &dto.TransferJobRequest{UserID: employer.ID, NewEmployerID: newEmployer.ID},
+			expectedErr: services.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetJobID := tt.setupFunc()
+			tt.req.JobID = targetJobID
+
+			job, err := jobService.TransferOwnership(ctx, tt.req)
+
+			entries, auditErr := auditRepo.ListByEntity(ctx, models.AuditEntityJob, targetJobID)
+			require.NoError(t, auditErr)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Empty(t, entries, "A rejected transfer must not be audited")
+
+				if !errors.Is(tt.expectedErr, services.ErrNotFound) {
+					dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: targetJobID})
+					require.NoError(t, dbErr)
+					assert.Equal(t, employer.ID, dbJob.EmployerID, "Employer should be unchanged")
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.req.NewEmployerID, job.EmployerID)
+
+			dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: targetJobID})
+			require.NoError(t, dbErr)
+			assert.Equal(t, tt.req.NewEmployerID, dbJob.EmployerID)
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, models.AuditActionJobOwnershipTransferred, entries[0].Action)
+			require.NotNil(t, entries[0].ActorID)
+			assert.Equal(t, employer.ID, *entries[0].ActorID)
+			var transfer models.JobOwnershipTransfer
+			require.NoError(t, json.Unmarshal(entries[0].Details, &transfer))
+			assert.Equal(t, models.JobOwnershipTransfer{FromEmployerID: employer.ID, ToEmployerID: tt.req.NewEmployerID}, transfer)
+
+			// The previous employer no longer owns the job
+			_, err = jobService.TransferOwnership(ctx, &dto.TransferJobRequest{JobID: targetJobID, UserID: employer.ID, NewEmployerID: otherUser.ID})
+			assert.ErrorIs(t, err, services.ErrForbidden)
+		})
+	}
+}
+
+func TestJobService_Integration_ReassignContractor(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)         // Need for verification
+	invoiceRepo := postgres.NewInvoiceRepo(pool) // Need for verification
+	auditRepo := postgres.NewAuditRepo(pool)     // Need for verification
+	invoiceService := services.NewInvoiceService(pool, notifications.NewJobStreams(nil), models.DefaultInvoicePaymentTermDays, models.DefaultInvoiceMaxAdjustmentPercent, cache.Config{})
+	defer cleanupTables(t, pool, "users", "jobs", "invoices", "audit_log")
+
+	employer := createTestUser(t, ctx, pool, "reassign-employer@test.com", "Reassign Employer")
+	previous := createTestUser(t, ctx, pool, "reassign-previous@test.com", "Reassign Previous Contractor")
+	replacement := createTestUser(t, ctx, pool, "reassign-replacement@test.com", "Reassign Replacement Contractor")
+
+	tests := []struct {
+		name         string
+		state        models.JobState
+		userID       uuid.UUID
+		contractorID uuid.UUID
+		expectedErr  error
+	}{
+		{name: "Success", state: models.JobStateOngoing, userID: employer.ID, contractorID: replacement.ID},
+		{name: "Error_Forbidden_NewContractor", state: models.JobStateOngoing, userID: replacement.ID, contractorID: replacement.ID, expectedErr: services.ErrForbidden},
+		{name: "Error_Forbidden_CurrentContractor", state: models.JobStateOngoing, userID: previous.ID, contractorID: replacement.ID, expectedErr: services.ErrForbidden},
+		{name: "Error_Validation_SameContractor", state: models.JobStateOngoing, userID: employer.ID, contractorID: previous.ID, expectedErr: services.ErrValidation},
+		{name: "Error_Validation_UnknownContractor", state: models.JobStateOngoing, userID: employer.ID, contractorID: uuid.New(), expectedErr: services.ErrValidation},
+		{name: "Error_InvalidState_Complete", state: models.JobStateComplete, userID: employer.ID, contractorID: replacement.ID, expectedErr: services.ErrInvalidState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobID := createTestJob(t, ctx, pool, employer.ID, tt.state, &previous.ID).ID
+			invoice := createTestInvoice(t, ctx, pool, jobID, 1, 500, models.InvoiceStateWaiting) // Billed by the previous contractor
+			reason := "Previous contractor moved to another project"
+
+			job, err := jobService.ReassignContractor(ctx, &dto.ReassignContractorRequest{JobID: jobID, UserID: tt.userID, ContractorID: tt.contractorID, Reason: &reason})
+
+			entries, auditErr := auditRepo.ListByEntity(ctx, models.AuditEntityJob, jobID)
+			require.NoError(t, auditErr)
+			dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+			require.NoError(t, dbErr)
+			dbInvoice, invErr := invoiceRepo.GetByID(ctx, &dto.GetInvoiceByIDRequest{ID: invoice.ID})
+			require.NoError(t, invErr)
+			assert.Equal(t, invoice.Value, dbInvoice.Value, "Existing invoices are kept as they are")
+			assert.Equal(t, invoice.State, dbInvoice.State, "Existing invoices are kept as they are")
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, job)
+				assert.Equal(t, tt.state, dbJob.State, "State should be unchanged")
+				require.NotNil(t, dbJob.ContractorID)
+				assert.Equal(t, previous.ID, *dbJob.ContractorID, "The previous contractor should stay assigned")
+				assert.Empty(t, entries, "A rejected reassignment must not be audited")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.JobStateOngoing, job.State)
+			require.NotNil(t, job.ContractorID)
+			assert.Equal(t, replacement.ID, *job.ContractorID)
+			assert.Equal(t, models.JobStateOngoing, dbJob.State, "The job stays Ongoing")
+			require.NotNil(t, dbJob.ContractorID)
+			assert.Equal(t, replacement.ID, *dbJob.ContractorID)
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, models.AuditActionJobContractorReassigned, entries[0].Action)
+			require.NotNil(t, entries[0].ActorID)
+			assert.Equal(t, employer.ID, *entries[0].ActorID)
+			var reassignment models.JobContractorReassignment
+			require.NoError(t, json.Unmarshal(entries[0].Details, &reassignment))
+			assert.Equal(t, previous.ID, reassignment.FromContractorID)
+			assert.Equal(t, replacement.ID, reassignment.ToContractorID)
+			assert.Equal(t, 1, reassignment.LastInvoicedInterval, "Intervals up to here were billed by the previous contractor")
+			require.NotNil(t, reassignment.Reason)
+			assert.Equal(t, reason, *reassignment.Reason)
+
+			// The invoice stays with the contractor who issued it
+			require.NotNil(t, dbInvoice.ContractorID)
+			assert.Equal(t, previous.ID, *dbInvoice.ContractorID)
+			_, err = invoiceService.GetInvoiceByID(ctx, &dto.GetInvoiceByIDRequest{ID: invoice.ID, UserId: previous.ID})
+			assert.NoError(t, err, "The previous contractor keeps access to what they invoiced")
+			_, err = invoiceService.GetInvoiceByID(ctx, &dto.GetInvoiceByIDRequest{ID: invoice.ID, UserId: replacement.ID})
+			assert.ErrorIs(t, err, services.ErrForbidden, "The new contractor can't read invoices they didn't issue")
+			listed, total, err := invoiceService.ListInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: jobID, UserId: previous.ID, Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, 1, total)
+			require.Len(t, listed, 1)
+			assert.Equal(t, invoice.ID, listed[0].ID)
+			_, total, err = invoiceService.ListInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: jobID, UserId: replacement.ID, Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, 0, total, "The new contractor only lists their own invoices")
+
+			// Only the new contractor can now work on the job
+			_, err = jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: jobID, UserID: previous.ID, State: models.JobStateComplete})
+			assert.ErrorIs(t, err, services.ErrForbidden)
+			_, err = jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: jobID, UserID: replacement.ID, State: models.JobStateComplete})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJobService_Integration_ReopenJob(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)     // Need for verification
+	auditRepo := postgres.NewAuditRepo(pool) // Need for verification
+	defer cleanupTables(t, pool, "users", "jobs", "audit_log")
+
+	employer := createTestUser(t, ctx, pool, "reopen-employer@test.com", "Reopen Employer")
+	contractor := createTestUser(t, ctx, pool, "reopen-contractor@test.com", "Reopen Contractor")
+
+	tests := []struct {
+		name         string
+		state        models.JobState
+		contractorID *uuid.UUID
+		userID       uuid.UUID
+		expectedErr  error
+	}{
+		{name: "Success_Employer", state: models.JobStateComplete, contractorID: &contractor.ID, userID: employer.ID},
+		{name: "Error_Forbidden_Contractor", state: models.JobStateComplete, contractorID: &contractor.ID, userID: contractor.ID, expectedErr: services.ErrForbidden},
+		{name: "Error_InvalidTransition_Archived", state: models.JobStateArchived, contractorID: &contractor.ID, userID: employer.ID, expectedErr: services.ErrInvalidTransition},
+		{name: "Error_InvalidTransition_AlreadyOngoing", state: models.JobStateOngoing, contractorID: &contractor.ID, userID: employer.ID, expectedErr: services.ErrInvalidTransition},
+		{name: "Error_InvalidState_NoContractor", state: models.JobStateComplete, contractorID: nil, userID: employer.ID, expectedErr: services.ErrInvalidState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobID := createTestJob(t, ctx, pool, employer.ID, tt.state, tt.contractorID).ID
+
+			job, err := jobService.ReopenJob(ctx, &dto.ReopenJobRequest{JobID: jobID, UserID: tt.userID})
+
+			entries, auditErr := auditRepo.ListByEntity(ctx, models.AuditEntityJob, jobID)
+			require.NoError(t, auditErr)
+			dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+			require.NoError(t, dbErr)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, job)
+				assert.Equal(t, tt.state, dbJob.State, "State should be unchanged")
+				assert.Empty(t, entries, "A rejected re-open must not be audited")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.JobStateOngoing, job.State)
+			assert.Equal(t, models.JobStateOngoing, dbJob.State)
+			require.NotNil(t, dbJob.ContractorID)
+			assert.Equal(t, contractor.ID, *dbJob.ContractorID, "The job goes back to the same contractor")
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, models.AuditActionJobReopened, entries[0].Action)
+			require.NotNil(t, entries[0].ActorID)
+			assert.Equal(t, employer.ID, *entries[0].ActorID)
+			var reopen models.JobReopening
+			require.NoError(t, json.Unmarshal(entries[0].Details, &reopen))
+			assert.Equal(t, contractor.ID, reopen.ContractorID)
+
+			// Once Ongoing again, it can be completed as usual
+			_, err = jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: jobID, UserID: contractor.ID, State: models.JobStateComplete})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJobService_Integration_ReopenJob_OngoingJobLimit(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool) // Need for verification
+	defer cleanupTables(t, pool, "users", "jobs", "audit_log")
+
+	employer := createTestUser(t, ctx, pool, "reopen-limit-employer@test.com", "Reopen Limit Employer")
+	contractor := createTestUser(t, ctx, pool, "reopen-limit-contractor@test.com", "Reopen Limit Contractor")
+	_, err := pool.Exec(ctx, `UPDATE users SET max_ongoing_jobs = 1 WHERE id = $1`, contractor.ID)
+	require.NoError(t, err)
+
+	// The contractor is already at their cap of one Ongoing job
+	createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	completeJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+
+	job, err := jobService.ReopenJob(ctx, &dto.ReopenJobRequest{JobID: completeJob.ID, UserID: employer.ID})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, services.ErrLimitExceeded)
+	assert.Nil(t, job)
+
+	dbJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: completeJob.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateComplete, dbJob.State, "State should be unchanged")
+}
+
+func TestJobService_Integration_CancelJob(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)         // Need for verification
+	invoiceRepo := postgres.NewInvoiceRepo(pool) // Need for verification
+	auditRepo := postgres.NewAuditRepo(pool)     // Need for verification
+	invoiceService := services.NewInvoiceService(pool, notifications.NewJobStreams(nil), models.DefaultInvoicePaymentTermDays, models.DefaultInvoiceMaxAdjustmentPercent, cache.Config{})
+	defer cleanupTables(t, pool, "users", "jobs", "invoices", "audit_log")
+
+	employer := createTestUser(t, ctx, pool, "cancel-employer@test.com", "Cancel Employer")
+	contractor := createTestUser(t, ctx, pool, "cancel-contractor@test.com", "Cancel Contractor")
+
+	// Test jobs bill 50/hour over two 10 hour intervals
+	tests := []struct {
+		name                string
+		state               models.JobState
+		invoicedIntervals   int
+		userID              uuid.UUID
+		hoursWorked         *int
+		expectedErr         error
+		expectedFinalValue  float64 // 0 when no final invoice is expected
+		expectedFinalNumber int
+	}{
+		{name: "Success_NoFinalInvoice", state: models.JobStateOngoing, userID: employer.ID},
+		{name: "Success_ProratedFinalInvoice", state: models.JobStateOngoing, invoicedIntervals: 1, userID: employer.ID, hoursWorked: ptrInt(4), expectedFinalValue: 200, expectedFinalNumber: 2},
+		{name: "Success_FullInterval", state: models.JobStateOngoing, userID: employer.ID, hoursWorked: ptrInt(10), expectedFinalValue: 500, expectedFinalNumber: 1},
+		{name: "Error_Validation_MoreHoursThanInterval", state: models.JobStateOngoing, userID: employer.ID, hoursWorked: ptrInt(11), expectedErr: services.ErrValidation},
+		{name: "Error_Validation_EverythingInvoiced", state: models.JobStateOngoing, invoicedIntervals: 2, userID: employer.ID, hoursWorked: ptrInt(1), expectedErr: services.ErrValidation},
+		{name: "Error_Forbidden_Contractor", state: models.JobStateOngoing, userID: contractor.ID, expectedErr: services.ErrForbidden},
+		{name: "Error_Forbidden_Stranger", state: models.JobStateOngoing, userID: uuid.New(), expectedErr: services.ErrForbidden},
+		{name: "Error_InvalidTransition_Complete", state: models.JobStateComplete, userID: employer.ID, expectedErr: services.ErrInvalidTransition},
+		{name: "Error_InvalidTransition_Waiting", state: models.JobStateWaiting, userID: employer.ID, expectedErr: services.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contractorID *uuid.UUID
+			if tt.state != models.JobStateWaiting {
+				contractorID = &contractor.ID
+			}
+			jobID := createTestJob(t, ctx, pool, employer.ID, tt.state, contractorID).ID
+			for i := 1; i <= tt.invoicedIntervals; i++ {
+				createTestInvoice(t, ctx, pool, jobID, i, 500, models.InvoiceStateWaiting)
+			}
+			reason := "Project was descoped"
+
+			job, finalInvoice, err := jobService.CancelJob(ctx, &dto.CancelJobRequest{JobID: jobID, UserID: tt.userID, Reason: &reason, HoursWorked: tt.hoursWorked})
+
+			entries, auditErr := auditRepo.ListByEntity(ctx, models.AuditEntityJob, jobID)
+			require.NoError(t, auditErr)
+			dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+			require.NoError(t, dbErr)
+			invoices, invErr := invoiceRepo.ListByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: jobID, Limit: 10})
+			require.NoError(t, invErr)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, job)
+				assert.Nil(t, finalInvoice)
+				assert.Equal(t, tt.state, dbJob.State, "State should be unchanged")
+				assert.Equal(t, contractorID, dbJob.ContractorID, "The contractor should stay assigned")
+				assert.Len(t, invoices, tt.invoicedIntervals, "A rejected cancellation must not bill anything")
+				assert.Empty(t, entries, "A rejected cancellation must not be audited")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.JobStateCancelled, job.State)
+			assert.Nil(t, job.ContractorID)
+			assert.Equal(t, models.JobStateCancelled, dbJob.State)
+			assert.Nil(t, dbJob.ContractorID, "The contractor is unassigned")
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, models.AuditActionJobCancelled, entries[0].Action)
+			require.NotNil(t, entries[0].ActorID)
+			assert.Equal(t, employer.ID, *entries[0].ActorID)
+			var cancellation models.JobCancellation
+			require.NoError(t, json.Unmarshal(entries[0].Details, &cancellation))
+			assert.Equal(t, contractor.ID, cancellation.ContractorID)
+			require.NotNil(t, cancellation.Reason)
+			assert.Equal(t, reason, *cancellation.Reason)
+
+			if tt.expectedFinalValue == 0 {
+				assert.Nil(t, finalInvoice)
+				assert.Nil(t, cancellation.FinalInvoiceID)
+				assert.Len(t, invoices, tt.invoicedIntervals)
+			} else {
+				require.NotNil(t, finalInvoice)
+				assert.Equal(t, tt.expectedFinalNumber, finalInvoice.IntervalNumber)
+				assert.Equal(t, tt.expectedFinalValue, finalInvoice.Value)
+				assert.Equal(t, models.InvoiceStateWaiting, finalInvoice.State)
+				assert.NotEmpty(t, finalInvoice.InvoiceNumber)
+				assert.Len(t, invoices, tt.invoicedIntervals+1)
+				require.NotNil(t, cancellation.FinalInvoiceID)
+				assert.Equal(t, finalInvoice.ID, *cancellation.FinalInvoiceID)
+
+				// The unassigned contractor still has access to the invoice for their work
+				require.NotNil(t, finalInvoice.ContractorID)
+				assert.Equal(t, contractor.ID, *finalInvoice.ContractorID)
+				read, readErr := invoiceService.GetInvoiceByID(ctx, &dto.GetInvoiceByIDRequest{ID: finalInvoice.ID, UserId: contractor.ID})
+				require.NoError(t, readErr)
+				assert.Equal(t, finalInvoice.ID, read.ID)
+				listed, total, listErr := invoiceService.ListInvoicesByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: jobID, UserId: contractor.ID, Limit: 10})
+				require.NoError(t, listErr)
+				assert.Equal(t, tt.invoicedIntervals+1, total)
+				assert.Len(t, listed, tt.invoicedIntervals+1)
 			}
+
+			// Cancelled is final
+			_, err = jobService.ReopenJob(ctx, &dto.ReopenJobRequest{JobID: jobID, UserID: employer.ID})
+			assert.True(t, errors.Is(err, services.ErrInvalidTransition), "Expected ErrInvalidTransition, got %v", err)
+			_, _, err = jobService.CancelJob(ctx, &dto.CancelJobRequest{JobID: jobID, UserID: employer.ID})
+			assert.True(t, errors.Is(err, services.ErrInvalidTransition), "Expected ErrInvalidTransition, got %v", err)
 		})
 	}
+
+	t.Run("Error_UpdateJobStateCannotCancel", func(t *testing.T) {
+		jobID := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID).ID
+
+		_, err := jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: jobID, UserID: employer.ID, State: models.JobStateCancelled})
+
+		assert.True(t, errors.Is(err, services.ErrInvalidTransition), "Expected ErrInvalidTransition, got %v", err)
+		dbJob, dbErr := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+		require.NoError(t, dbErr)
+		assert.Equal(t, models.JobStateOngoing, dbJob.State)
+	})
+}
+
+func TestJobService_Integration_RestoreJob(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	employer := createTestUser(t, ctx, pool, "restorejob-employer@test.com", "RestoreJob Employer")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	// Restoring a job that isn't deleted is a not-found
+	_, err := jobService.RestoreJob(ctx, &dto.RestoreJobRequest{ID: job.ID})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrNotFound), "Expected ErrNotFound, got %v", err)
+
+	require.NoError(t, jobService.DeleteJob(ctx, &dto.DeleteJobRequest{ID: job.ID, UserID: employer.ID}))
+
+	// Soft-deleted jobs are excluded from listings and counts
+	jobs, total, err := jobService.ListJobsByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: employer.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+	assert.Equal(t, 0, total)
+
+	restored, err := jobService.RestoreJob(ctx, &dto.RestoreJobRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, restored.ID)
+	assert.Nil(t, restored.DeletedAt)
+
+	fetched, err := jobService.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, fetched.ID)
 }
 
 // TestJobService_Integration_ListAvailableJobs tests listing available jobs with filters.
@@ -484,63 +1005,126 @@ func TestJobService_Integration_ListAvailableJobs(t *testing.T) {
 	emp1 := createTestUser(t, ctx, pool, "listavail-emp1@test.com", "ListAvail Emp1")
 	emp2 := createTestUser(t, ctx, pool, "listavail-emp2@test.com", "ListAvail Emp2")
 
-	// Create jobs with different states and rates
-	job1WaitingLowRate := createTestJob(t, ctx, pool, emp1.ID, models.JobStateWaiting, nil) // Rate 50.0
+	// Create jobs with different states, rates and durations
+	job1WaitingLowRate := createTestJob(t, ctx, pool, emp1.ID, models.JobStateWaiting, nil) // Rate 50.0, duration 20
 	job2WaitingHighRate := createTestJob(t, ctx, pool, emp2.ID, models.JobStateWaiting, nil)
-	_, _ = postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: job2WaitingHighRate.ID, Rate: ptrFloat64(150.0)}) // Update rate
+	_, _ = postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: job2WaitingHighRate.ID, Version: job2WaitingHighRate.Version, Rate: ptrFloat64(150.0), Duration: ptrInt(80), Description: ptrString("Senior Go backend developer for payment APIs")})
 	job4WaitingMidRate := createTestJob(t, ctx, pool, emp1.ID, models.JobStateWaiting, nil)
-	_, _ = postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: job4WaitingMidRate.ID, Rate: ptrFloat64(100.0)}) // Update rate
+	_, _ = postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: job4WaitingMidRate.ID, Version: job4WaitingMidRate.Version, Rate: ptrFloat64(100.0), Duration: ptrInt(40), Description: ptrString("Frontend developer for a React dashboard")})
 
 	// --- Test Cases ---
 	tests := []struct {
 		name          string
 		req           dto.ListAvailableJobsRequest
 		expectedCount int
+		expectedTotal int         // Rows matching the filters, regardless of limit/offset
 		expectedIDs   []uuid.UUID // Check specific IDs returned
 	}{
 		{
 			name:          "ListAllAvailable",
 			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0},
 			expectedCount: 3, // job1, job2, job4
+			expectedTotal: 3,
 			expectedIDs:   []uuid.UUID{job1WaitingLowRate.ID, job2WaitingHighRate.ID, job4WaitingMidRate.ID},
 		},
 		{
 			name: "FilterMinRate",
 			req:  dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MinRate: ptrFloat64(75.0)},
 			expectedCount: 2, // job2, job4
+			expectedTotal: 2,
 			expectedIDs:   []uuid.UUID{job2WaitingHighRate.ID, job4WaitingMidRate.ID},
 		},
 		{
 			name: "FilterMaxRate",
 			req:  dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MaxRate: ptrFloat64(120.0)},
 			expectedCount: 2, // job1, job4
+			expectedTotal: 2,
 			expectedIDs:   []uuid.UUID{job1WaitingLowRate.ID, job4WaitingMidRate.ID},
 		},
 		{
 			name: "FilterMinAndMaxRate",
 			req:  dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MinRate: ptrFloat64(60.0), MaxRate: ptrFloat64(110.0)},
 			expectedCount: 1, // job4
+			expectedTotal: 1,
+			expectedIDs:   []uuid.UUID{job4WaitingMidRate.ID},
+		},
+		{
+			name:          "FilterMinDuration",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MinDuration: ptrInt(30)},
+			expectedCount: 2, // job2 (80h), job4 (40h)
+			expectedTotal: 2,
+			expectedIDs:   []uuid.UUID{job2WaitingHighRate.ID, job4WaitingMidRate.ID},
+		},
+		{
+			name:          "FilterMaxDuration",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MaxDuration: ptrInt(30)},
+			expectedCount: 1, // job1 (20h)
+			expectedTotal: 1,
+			expectedIDs:   []uuid.UUID{job1WaitingLowRate.ID},
+		},
+		{
+			name:          "FilterMinAndMaxDuration",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MinDuration: ptrInt(20), MaxDuration: ptrInt(40)},
+			expectedCount: 2, // job1, job4; both bounds are inclusive
+			expectedTotal: 2,
+			expectedIDs:   []uuid.UUID{job1WaitingLowRate.ID, job4WaitingMidRate.ID},
+		},
+		{
+			name:          "FilterDurationWithRate",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, MinDuration: ptrInt(30), MaxRate: ptrFloat64(120.0)},
+			expectedCount: 1, // job4
+			expectedTotal: 1,
+			expectedIDs:   []uuid.UUID{job4WaitingMidRate.ID},
+		},
+		{
+			name:          "SearchDescription",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, Search: ptrString("backend")},
+			expectedCount: 1, // job2
+			expectedTotal: 1,
+			expectedIDs:   []uuid.UUID{job2WaitingHighRate.ID},
+		},
+		{
+			name:          "SearchMatchesStemmedTerms",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, Search: ptrString("developers")},
+			expectedCount: 2, // job2, job4
+			expectedTotal: 2,
+			expectedIDs:   []uuid.UUID{job2WaitingHighRate.ID, job4WaitingMidRate.ID},
+		},
+		{
+			name:          "SearchWithRateFilter",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, Search: ptrString("developer"), MaxRate: ptrFloat64(120.0)},
+			expectedCount: 1, // job4
+			expectedTotal: 1,
 			expectedIDs:   []uuid.UUID{job4WaitingMidRate.ID},
 		},
+		{
+			name:          "SearchNoMatch",
+			req:           dto.ListAvailableJobsRequest{Limit: 10, Offset: 0, Search: ptrString("blockchain")},
+			expectedCount: 0,
+			expectedTotal: 0,
+		},
 		{
 			name:          "Pagination_Limit",
 			req:           dto.ListAvailableJobsRequest{Limit: 2, Offset: 0},
 			expectedCount: 2,
+			expectedTotal: 3,
 			// Order is DESC by created_at, so likely job4, job2 (or job1 depending on creation order)
 		},
 		{
 			name:          "Pagination_Offset",
 			req:           dto.ListAvailableJobsRequest{Limit: 2, Offset: 1},
 			expectedCount: 2,
+			expectedTotal: 3,
 			// Should get the 2nd and 3rd available jobs based on creation time desc
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			jobs, err := jobService.ListAvailableJobs(ctx, &tt.req)
+			jobs, total, err := jobService.ListAvailableJobs(ctx, &tt.req)
 			require.NoError(t, err)
 			assert.Len(t, jobs, tt.expectedCount)
+			assert.Equal(t, tt.expectedTotal, total, "Total should count filtered rows, not the page or the whole table")
 
 			// Verify all returned jobs are indeed available
 			for _, job := range jobs {
@@ -553,6 +1137,12 @@ func TestJobService_Integration_ListAvailableJobs(t *testing.T) {
 				if tt.req.MaxRate != nil {
 					assert.LessOrEqual(t, job.Rate, *tt.req.MaxRate)
 				}
+				if tt.req.MinDuration != nil {
+					assert.GreaterOrEqual(t, job.Duration, *tt.req.MinDuration)
+				}
+				if tt.req.MaxDuration != nil {
+					assert.LessOrEqual(t, job.Duration, *tt.req.MaxDuration)
+				}
 			}
 
 			// Verify specific IDs if provided
@@ -567,6 +1157,14 @@ func TestJobService_Integration_ListAvailableJobs(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Error_MinDurationAboveMax", func(t *testing.T) {
+		jobs, total, err := jobService.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: 10, MinDuration: ptrInt(50), MaxDuration: ptrInt(20)})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, services.ErrValidation), "Expected ErrValidation, got %v", err)
+		assert.Nil(t, jobs)
+		assert.Zero(t, total)
+	})
 }
 
 // TestJobService_Integration_ListJobsByEmployer tests listing jobs for an employer.
@@ -592,10 +1190,35 @@ func TestJobService_Integration_ListJobsByEmployer(t *testing.T) {
 		Offset:     0,
 	}
 
-	jobs, err := jobService.ListJobsByEmployer(ctx, &req)
+	jobs, total, err := jobService.ListJobsByEmployer(ctx, &req)
 
 	require.NoError(t, err)
 	assert.Len(t, jobs, 2) // Should only list jobs for emp1
+	assert.Equal(t, 2, total)
+
+	// Total follows the state filter and ignores the page size
+	stateReq := dto.ListJobsByEmployerRequest{EmployerID: emp1.ID, State: ptrJobState(models.JobStateOngoing), Limit: 10}
+	_, total, err = jobService.ListJobsByEmployer(ctx, &stateReq)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	pageReq := dto.ListJobsByEmployerRequest{EmployerID: emp1.ID, Limit: 1}
+	jobsPage, total, err := jobService.ListJobsByEmployer(ctx, &pageReq)
+	require.NoError(t, err)
+	assert.Len(t, jobsPage, 1)
+	assert.Equal(t, 2, total)
+
+	// A states filter matches any of the listed states
+	statesReq := dto.ListJobsByEmployerRequest{EmployerID: emp1.ID, States: []models.JobState{models.JobStateOngoing, models.JobStateArchived}, Limit: 10}
+	statesJobs, total, err := jobService.ListJobsByEmployer(ctx, &statesReq)
+	require.NoError(t, err)
+	require.Len(t, statesJobs, 1)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, job2Emp1Ongoing.ID, statesJobs[0].ID)
+	statesReq.States = []models.JobState{models.JobStateWaiting, models.JobStateOngoing}
+	statesJobs, total, err = jobService.ListJobsByEmployer(ctx, &statesReq)
+	require.NoError(t, err)
+	assert.Len(t, statesJobs, 2)
+	assert.Equal(t, 2, total)
 
 	foundJob1 := false
 	foundJob2 := false
@@ -639,10 +1262,36 @@ func TestJobService_Integration_ListJobsByContractor(t *testing.T) {
 		Offset:       0,
 	}
 
-	jobs, err := jobService.ListJobsByContractor(ctx, &req)
+	jobs, total, err := jobService.ListJobsByContractor(ctx, &req)
 
 	require.NoError(t, err)
 	assert.Len(t, jobs, 2) // Should only list jobs for con1
+	assert.Equal(t, 2, total)
+
+	// States filter: each case lists which of con1's jobs it should return
+	statesTests := []struct {
+		name     string
+		states   []models.JobState
+		expected []uuid.UUID
+	}{
+		{name: "Single", states: []models.JobState{models.JobStateOngoing}, expected: []uuid.UUID{job1Con1Ongoing.ID}},
+		{name: "Multiple", states: []models.JobState{models.JobStateOngoing, models.JobStateComplete}, expected: []uuid.UUID{job1Con1Ongoing.ID, job2Con1Complete.ID}},
+		{name: "NoMatch", states: []models.JobState{models.JobStateArchived}, expected: []uuid.UUID{}},
+		{name: "EmptyMeansAll", states: []models.JobState{}, expected: []uuid.UUID{job1Con1Ongoing.ID, job2Con1Complete.ID}},
+	}
+	for _, tt := range statesTests {
+		t.Run("States"+tt.name, func(t *testing.T) {
+			statesReq := dto.ListJobsByContractorRequest{ContractorID: con1.ID, States: tt.states, Limit: 10}
+			statesJobs, statesTotal, err := jobService.ListJobsByContractor(ctx, &statesReq)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), statesTotal)
+			ids := make([]uuid.UUID, 0, len(statesJobs))
+			for _, job := range statesJobs {
+				ids = append(ids, job.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
 
 	foundJob1 := false
 	foundJob2 := false
@@ -660,4 +1309,840 @@ func TestJobService_Integration_ListJobsByContractor(t *testing.T) {
 	}
 	assert.True(t, foundJob1, "Ongoing job for con1 not found")
 	assert.True(t, foundJob2, "Complete job for con1 not found")
-}
\ No newline at end of file
+}
+// TestRepositories_Integration_Exists checks the existence checks used in place of full fetches.
+func TestRepositories_Integration_Exists(t *testing.T) {
+	ctx, _, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	jobRepo := postgres.NewJobRepo(pool)
+	userRepo := postgres.NewUserRepo(pool)
+
+	employer := createTestUser(t, ctx, pool, "exists-emp@test.com", "Exists Emp")
+	deletedUser := createTestUser(t, ctx, pool, "exists-deleted@test.com", "Exists Deleted")
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	deletedJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	require.NoError(t, jobRepo.Delete(ctx, &dto.DeleteJobRequest{ID: deletedJob.ID}))
+	require.NoError(t, userRepo.Delete(ctx, &dto.DeleteUserRequest{ID: deletedUser.ID}))
+
+	tests := []struct {
+		name     string
+		exists   func(ctx context.Context, id uuid.UUID) (bool, error)
+		id       uuid.UUID
+		expected bool
+	}{
+		{name: "Job", exists: jobRepo.Exists, id: job.ID, expected: true},
+		{name: "JobSoftDeleted", exists: jobRepo.Exists, id: deletedJob.ID, expected: false},
+		{name: "JobMissing", exists: jobRepo.Exists, id: uuid.New(), expected: false},
+		{name: "User", exists: userRepo.Exists, id: employer.ID, expected: true},
+		{name: "UserSoftDeleted", exists: userRepo.Exists, id: deletedUser.ID, expected: false},
+		{name: "UserMissing", exists: userRepo.Exists, id: uuid.New(), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := tt.exists(ctx, tt.id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, exists)
+		})
+	}
+}
+
+// TestJobService_Integration_ListJobsSorting tests sort options on job listings.
+func TestJobService_Integration_ListJobsSorting(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	// --- Setup Data ---
+	emp := createTestUser(t, ctx, pool, "sort-emp@test.com", "Sort Emp")
+	jobRepo := postgres.NewJobRepo(pool)
+	jobLow := createTestJob(t, ctx, pool, emp.ID, models.JobStateWaiting, nil) // Rate 50.0
+	jobHigh := createTestJob(t, ctx, pool, emp.ID, models.JobStateWaiting, nil)
+	_, err := jobRepo.Update(ctx, &dto.UpdateJobRequest{ID: jobHigh.ID, Version: jobHigh.Version, Rate: ptrFloat64(150.0)})
+	require.NoError(t, err)
+	jobMid := createTestJob(t, ctx, pool, emp.ID, models.JobStateWaiting, nil)
+	_, err = jobRepo.Update(ctx, &dto.UpdateJobRequest{ID: jobMid.ID, Version: jobMid.Version, Rate: ptrFloat64(100.0)})
+	require.NoError(t, err)
+
+	ids := func(jobs []models.Job) []uuid.UUID {
+		out := make([]uuid.UUID, len(jobs))
+		for i, job := range jobs {
+			out[i] = job.ID
+		}
+		return out
+	}
+
+	// --- Test Execution: Highest rate first ---
+	jobs, _, err := jobService.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: 10, SortBy: ptrString("rate"), SortOrder: ptrString("desc")})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{jobHigh.ID, jobMid.ID, jobLow.ID}, ids(jobs))
+
+	// --- Test Execution: Lowest rate first on the employer listing ---
+	jobs, _, err = jobService.ListJobsByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: emp.ID, Limit: 10, SortBy: ptrString("rate"), SortOrder: ptrString("asc")})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{jobLow.ID, jobMid.ID, jobHigh.ID}, ids(jobs))
+
+	// --- Test Execution: Default is newest first ---
+	jobs, _, err = jobService.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{jobMid.ID, jobHigh.ID, jobLow.ID}, ids(jobs))
+
+	// --- Test Execution: Invalid sort field ---
+	_, _, err = jobService.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: 10, SortBy: ptrString("employer_id")})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrValidation), "Expected ErrValidation, got %v", err)
+}
+
+func TestJobService_Integration_CreateJobIncrementsMetric(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	employer := createTestUser(t, ctx, pool, "metrics-employer@test.com", "Metrics Employer")
+	before := testutil.ToFloat64(services.JobsCreatedTotal)
+
+	_, err := jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, EmployerID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(services.JobsCreatedTotal))
+
+	// A failed create must not be counted
+	_, err = jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, EmployerID: uuid.New()})
+	require.Error(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(services.JobsCreatedTotal))
+}
+
+func TestJobService_Integration_ApplicationDeadline(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	employer := createTestUser(t, ctx, pool, "deadline-employer@test.com", "Deadline Employer")
+	future := time.Now().Add(48 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	// A future deadline is stored and returned
+	job, err := jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, ApplicationDeadline: &future, EmployerID: employer.ID})
+	require.NoError(t, err)
+	require.NotNil(t, job.ApplicationDeadline)
+	assert.WithinDuration(t, future, *job.ApplicationDeadline, time.Second)
+
+	// A deadline in the past is rejected at creation
+	_, err = jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, ApplicationDeadline: &past, EmployerID: employer.ID})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrValidation))
+
+	// ...and when updating
+	_, err = jobService.UpdateJobDetails(ctx, &dto.UpdateJobDetailsRequest{ApplicationDeadline: &past, JobID: job.ID, UserID: employer.ID})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrValidation))
+
+	later := future.Add(24 * time.Hour)
+	updated, err := jobService.UpdateJobDetails(ctx, &dto.UpdateJobDetailsRequest{ApplicationDeadline: &later, Version: job.Version, JobID: job.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	require.NotNil(t, updated.ApplicationDeadline)
+	assert.WithinDuration(t, later, *updated.ApplicationDeadline, time.Second)
+
+	// Jobs whose deadline has passed are no longer listed as available
+	_, err = pool.Exec(ctx, "UPDATE jobs SET application_deadline = NOW() - INTERVAL '1 hour' WHERE id = $1", job.ID)
+	require.NoError(t, err)
+	available, total, err := jobService.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, available)
+}
+
+func TestJobService_Integration_GetEmployerStats(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "stats-employer@test.com", "Stats Employer")
+	otherEmployer := createTestUser(t, ctx, pool, "stats-other@test.com", "Stats Other")
+	newEmployer := createTestUser(t, ctx, pool, "stats-new@test.com", "Stats New")
+
+	seedJob := func(employerID uuid.UUID, rate float64, currency string, state models.JobState) *models.Job {
+		job, err := jobRepo.Create(ctx, &dto.CreateJobRequest{Rate: rate, Currency: currency, Duration: 20, InvoiceInterval: 10, EmployerID: employerID})
+		require.NoError(t, err)
+		if state != models.JobStateWaiting {
+			job, err = jobRepo.Update(ctx, &dto.UpdateJobRequest{ID: job.ID, Version: job.Version, State: &state})
+			require.NoError(t, err)
+		}
+		return job
+	}
+
+	seedJob(employer.ID, 10, "USD", models.JobStateWaiting)
+	seedJob(employer.ID, 20, "USD", models.JobStateWaiting)
+	ongoing := seedJob(employer.ID, 30, "USD", models.JobStateOngoing)
+	complete := seedJob(employer.ID, 40, "USD", models.JobStateComplete)
+	createTestInvoice(t, ctx, pool, ongoing.ID, 1, 100, models.InvoiceStateWaiting)
+	createTestInvoice(t, ctx, pool, ongoing.ID, 2, 200.5, models.InvoiceStateComplete)
+	createTestInvoice(t, ctx, pool, complete.ID, 1, 50, models.InvoiceStateComplete)
+
+	// Rates and invoices in another currency are averaged and summed separately
+	euroJob := seedJob(employer.ID, 100, "EUR", models.JobStateOngoing)
+	createTestInvoice(t, ctx, pool, euroJob.ID, 1, 80, models.InvoiceStateWaiting)
+
+	// Deleted jobs and their invoices are left out
+	deleted := seedJob(employer.ID, 1000, "USD", models.JobStateArchived)
+	createTestInvoice(t, ctx, pool, deleted.ID, 1, 700, models.InvoiceStateComplete)
+	require.NoError(t, jobRepo.Delete(ctx, &dto.DeleteJobRequest{ID: deleted.ID}))
+
+	// Another employer's jobs are left out
+	otherJob := seedJob(otherEmployer.ID, 500, "USD", models.JobStateOngoing)
+	createTestInvoice(t, ctx, pool, otherJob.ID, 1, 999, models.InvoiceStateWaiting)
+
+	tests := []struct {
+		name          string
+		req           *dto.EmployerStatsRequest
+		expectedStats *models.EmployerStats
+		expectedErr   error
+	}{
+		{
+			name: "Success",
+			req:  &dto.EmployerStatsRequest{EmployerID: employer.ID, UserID: employer.ID},
+			expectedStats: &models.EmployerStats{
+				JobsByState: map[models.JobState]int{
+					models.JobStateWaiting:   2,
+					models.JobStateOngoing:   2,
+					models.JobStateComplete:  1,
+					models.JobStateArchived:  0,
+					models.JobStateCancelled: 0,
+				},
+				TotalJobs:     5,
+				AverageRate:   map[string]float64{"USD": 25, "EUR": 100},
+				TotalInvoiced: map[string]float64{"USD": 350.5, "EUR": 80},
+			},
+		},
+		{
+			name: "Success_NoJobs",
+			req:  &dto.EmployerStatsRequest{EmployerID: newEmployer.ID, UserID: newEmployer.ID},
+			expectedStats: &models.EmployerStats{
+				JobsByState: map[models.JobState]int{
+					models.JobStateWaiting:   0,
+					models.JobStateOngoing:   0,
+					models.JobStateComplete:  0,
+					models.JobStateArchived:  0,
+					models.JobStateCancelled: 0,
+				},
+				AverageRate:   map[string]float64{},
+				TotalInvoiced: map[string]float64{},
+			},
+		},
+		{
+			name:        "Error_NotTheEmployer",
+			req:         &dto.EmployerStatsRequest{EmployerID: employer.ID, UserID: otherEmployer.ID},
+			expectedErr: services.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := jobService.GetEmployerStats(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, stats)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, stats)
+			assert.Equal(t, tt.expectedStats.JobsByState, stats.JobsByState)
+			assert.Equal(t, tt.expectedStats.TotalJobs, stats.TotalJobs)
+			assert.InDeltaMapValues(t, tt.expectedStats.AverageRate, stats.AverageRate, 0.001)
+			assert.InDeltaMapValues(t, tt.expectedStats.TotalInvoiced, stats.TotalInvoiced, 0.001)
+		})
+	}
+}
+
+func TestJobService_Integration_SavedJobs(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	jobRepo := postgres.NewJobRepo(pool)
+	defer cleanupTables(t, pool, "users", "jobs", "saved_jobs")
+
+	employer := createTestUser(t, ctx, pool, "saved-employer@test.com", "Saved Employer")
+	contractor := createTestUser(t, ctx, pool, "saved-contractor@test.com", "Saved Contractor")
+	otherContractor := createTestUser(t, ctx, pool, "saved-other@test.com", "Saved Other")
+
+	job1 := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	job2 := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+
+	listSaved := func(userID uuid.UUID) ([]models.Job, int) {
+		jobs, total, err := jobService.ListSavedJobs(ctx, &dto.ListSavedJobsRequest{UserID: userID, Limit: 10, Offset: 0})
+		require.NoError(t, err)
+		return jobs, total
+	}
+
+	t.Run("SaveIsIdempotent", func(t *testing.T) {
+		require.NoError(t, jobService.SaveJob(ctx, &dto.SaveJobRequest{JobID: job1.ID, UserID: contractor.ID}))
+		require.NoError(t, jobService.SaveJob(ctx, &dto.SaveJobRequest{JobID: job1.ID, UserID: contractor.ID}), "Saving twice should succeed")
+
+		jobs, total := listSaved(contractor.ID)
+		assert.Equal(t, 1, total)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, job1.ID, jobs[0].ID)
+	})
+
+	t.Run("ListIsPerUserAndMostRecentFirst", func(t *testing.T) {
+		require.NoError(t, jobService.SaveJob(ctx, &dto.SaveJobRequest{JobID: job2.ID, UserID: contractor.ID}))
+
+		jobs, total := listSaved(contractor.ID)
+		assert.Equal(t, 2, total)
+		require.Len(t, jobs, 2)
+		assert.Equal(t, job2.ID, jobs[0].ID)
+		assert.Equal(t, job1.ID, jobs[1].ID)
+
+		otherJobs, otherTotal := listSaved(otherContractor.ID)
+		assert.Equal(t, 0, otherTotal)
+		assert.Empty(t, otherJobs)
+	})
+
+	t.Run("FilledJobIsLeftOut", func(t *testing.T) {
+		_, err := jobRepo.AssignContractor(ctx, &dto.AssignContractorRequest{JobID: job1.ID, ContractorID: otherContractor.ID})
+		require.NoError(t, err)
+
+		jobs, total := listSaved(contractor.ID)
+		assert.Equal(t, 1, total)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, job2.ID, jobs[0].ID)
+	})
+
+	t.Run("UnsaveIsIdempotent", func(t *testing.T) {
+		require.NoError(t, jobService.UnsaveJob(ctx, &dto.UnsaveJobRequest{JobID: job2.ID, UserID: contractor.ID}))
+		require.NoError(t, jobService.UnsaveJob(ctx, &dto.UnsaveJobRequest{JobID: job2.ID, UserID: contractor.ID}), "Unsaving twice should succeed")
+
+		jobs, total := listSaved(contractor.ID)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, jobs)
+	})
+
+	t.Run("Error_JobNotFound", func(t *testing.T) {
+		err := jobService.SaveJob(ctx, &dto.SaveJobRequest{JobID: uuid.New(), UserID: contractor.ID})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, services.ErrNotFound), "Expected ErrNotFound, got %v", err)
+	})
+}
+
+func TestJobService_Integration_OutboxEvents(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	outboxRepo := postgres.NewOutboxRepo(pool) // For verification
+	cleanupTables(t, pool, "outbox") // Other tests leave events behind
+	defer cleanupTables(t, pool, "users", "jobs", "outbox")
+
+	employer := createTestUser(t, ctx, pool, "outbox-employer@test.com", "Outbox Employer")
+	contractor := createTestUser(t, ctx, pool, "outbox-contractor@test.com", "Outbox Contractor")
+	otherUser := createTestUser(t, ctx, pool, "outbox-other@test.com", "Outbox Other")
+
+	job, err := jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, EmployerID: employer.ID})
+	require.NoError(t, err)
+
+	// Move the job along directly, then through the service so the change is recorded
+	ongoing := models.JobStateOngoing
+	_, err = postgres.NewJobRepo(pool).Update(ctx, &dto.UpdateJobRequest{ID: job.ID, Version: job.Version, State: &ongoing, ContractorID: &contractor.ID})
+	require.NoError(t, err)
+	_, err = jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: job.ID, UserID: employer.ID, State: models.JobStateComplete})
+	require.NoError(t, err)
+
+	// A rejected change rolls back without leaving an event behind
+	_, err = jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: job.ID, UserID: otherUser.ID, State: models.JobStateArchived})
+	require.ErrorIs(t, err, services.ErrForbidden)
+
+	events, err := outboxRepo.ListUnpublished(ctx, &dto.ListUnpublishedOutboxEventsRequest{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.OutboxEventJobCreated, events[0].EventType)
+	assert.Equal(t, models.OutboxEventJobStateChanged, events[1].EventType)
+	for _, event := range events {
+		assert.Equal(t, models.OutboxAggregateJob, event.AggregateType)
+		assert.Equal(t, job.ID, event.AggregateID)
+		assert.Nil(t, event.PublishedAt)
+	}
+	assert.Less(t, events[0].ID, events[1].ID)
+
+	var payload models.Job
+	require.NoError(t, json.Unmarshal(events[1].Payload, &payload))
+	assert.Equal(t, models.JobStateComplete, payload.State)
+
+	// Published events are no longer returned
+	require.NoError(t, outboxRepo.MarkPublished(ctx, &dto.MarkOutboxEventPublishedRequest{ID: events[0].ID}))
+	events, err = outboxRepo.ListUnpublished(ctx, &dto.ListUnpublishedOutboxEventsRequest{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.OutboxEventJobStateChanged, events[0].EventType)
+}
+
+func TestJobService_Integration_CreateJobWithSkills(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "skills")
+
+	employer := createTestUser(t, ctx, pool, "skills-employer@test.com", "Skills Employer")
+
+	// Names are normalized, so differently cased duplicates collapse into one skill
+	job, err := jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, Skills: []string{" Go ", "postgres", "go"}, EmployerID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "postgres"}, job.Skills)
+
+	fetched, err := jobService.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "postgres"}, fetched.Skills)
+
+	// A second job reuses the existing skill rows rather than creating new ones
+	_, err = jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, Skills: []string{"Go", "redis"}, EmployerID: employer.ID})
+	require.NoError(t, err)
+	var skillCount int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM skills").Scan(&skillCount))
+	assert.Equal(t, 3, skillCount)
+
+	// Replacing the skills bumps the version even with no other change
+	updated, err := jobService.UpdateJobDetails(ctx, &dto.UpdateJobDetailsRequest{Skills: []string{"redis"}, Version: fetched.Version, JobID: job.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"redis"}, updated.Skills)
+	assert.Equal(t, fetched.Version+1, updated.Version)
+
+	// Omitting skills keeps them...
+	newDescription := "Now with a description"
+	updated, err = jobService.UpdateJobDetails(ctx, &dto.UpdateJobDetailsRequest{Description: &newDescription, Version: updated.Version, JobID: job.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"redis"}, updated.Skills)
+
+	// ...and an empty list clears them
+	updated, err = jobService.UpdateJobDetails(ctx, &dto.UpdateJobDetailsRequest{Skills: []string{}, Version: updated.Version, JobID: job.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Skills)
+}
+
+func TestSkillRepo_Integration_UpsertConcurrent(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "skills")
+	skillRepo := postgres.NewSkillRepo(pool)
+
+	// Creating the same new skill from many goroutines yields one row, which every caller gets back
+	const workers = 10
+	names := []string{"Rust", " rust", "RUST ", "rust"}
+	skills := make([][]models.Skill, workers)
+	errs := make([]error, workers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			skills[i], errs[i] = skillRepo.Upsert(ctx, []string{names[i%len(names)], "kubernetes"})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		require.NoError(t, errs[i], "worker %d", i)
+		require.Len(t, skills[i], 2, "worker %d", i)
+		assert.Equal(t, "kubernetes", skills[i][0].Name)
+		assert.Equal(t, "rust", skills[i][1].Name)
+		assert.Equal(t, skills[0][1].ID, skills[i][1].ID, "Every caller must get the same row")
+	}
+	var rustCount int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM skills WHERE name = 'rust'").Scan(&rustCount))
+	assert.Equal(t, 1, rustCount)
+
+	// Jobs created concurrently with the same new skill share it too
+	employer := createTestUser(t, ctx, pool, "upsert-employer@test.com", "Upsert Employer")
+	jobErrs := make([]error, workers)
+	start = make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, jobErrs[i] = jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, Skills: []string{"Terraform", "go"}, EmployerID: employer.ID})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i, err := range jobErrs {
+		require.NoError(t, err, "job %d", i)
+	}
+	var terraformCount, linkCount int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM skills WHERE name = 'terraform'").Scan(&terraformCount))
+	assert.Equal(t, 1, terraformCount)
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM job_skills js JOIN skills s ON s.id = js.skill_id WHERE s.name = 'terraform'").Scan(&linkCount))
+	assert.Equal(t, workers, linkCount)
+
+	empty, err := skillRepo.Upsert(ctx, []string{" ", ""})
+	require.NoError(t, err)
+	assert.Empty(t, empty, "Blank names are dropped")
+}
+
+func TestJobService_Integration_ListAvailableJobsBySkills(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "skills")
+
+	employer := createTestUser(t, ctx, pool, "skillfilter-employer@test.com", "Skill Filter Employer")
+	createWithSkills := func(skills ...string) uuid.UUID {
+		job, err := jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 10, Skills: skills, EmployerID: employer.ID})
+		require.NoError(t, err)
+		return job.ID
+	}
+	goOnly := createWithSkills("go")
+	goAndSQL := createWithSkills("go", "sql")
+	sqlOnly := createWithSkills("sql")
+	createWithSkills() // No skills; never matches a skills filter
+
+	tests := []struct {
+		name     string
+		skills   []string
+		match    string
+		expected []uuid.UUID
+	}{
+		{name: "AllSingle", skills: []string{"go"}, expected: []uuid.UUID{goOnly, goAndSQL}},
+		{name: "AllBoth", skills: []string{"go", "sql"}, expected: []uuid.UUID{goAndSQL}},
+		{name: "AllIsDefault", skills: []string{"GO", "sql", "go"}, match: "", expected: []uuid.UUID{goAndSQL}},
+		{name: "AnyBoth", skills: []string{"go", "sql"}, match: models.SkillsMatchAny, expected: []uuid.UUID{goOnly, goAndSQL, sqlOnly}},
+		{name: "AllUnknown", skills: []string{"go", "rust"}, match: models.SkillsMatchAll, expected: nil},
+		{name: "AnyUnknown", skills: []string{"rust"}, match: models.SkillsMatchAny, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, total, err := jobService.ListAvailableJobs(ctx, &dto.ListAvailableJobsRequest{Limit: 10, Skills: tt.skills, SkillsMatch: tt.match})
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), total)
+			ids := make([]uuid.UUID, 0, len(jobs))
+			for _, job := range jobs {
+				ids = append(ids, job.ID)
+				assert.NotEmpty(t, job.Skills, "Listed jobs should carry their skills")
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestJobService_Integration_CanDeleteJob(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "invoices")
+
+	employer := createTestUser(t, ctx, pool, "candelete-employer@test.com", "CanDelete Employer")
+	otherUser := createTestUser(t, ctx, pool, "candelete-other@test.com", "CanDelete Other")
+	contractor := createTestUser(t, ctx, pool, "candelete-contractor@test.com", "CanDelete Contractor")
+
+	tests := []struct {
+		name          string
+		setupFunc     func() uuid.UUID
+		userID        uuid.UUID
+		expectedCodes []string
+		expectedErr   error
+	}{
+		{
+			name:          "AllClear",
+			setupFunc:     func() uuid.UUID { return createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil).ID },
+			userID:        employer.ID,
+			expectedCodes: []string{},
+		},
+		{
+			name:          "InvalidState",
+			setupFunc:     func() uuid.UUID { return createTestJob(t, ctx, pool, employer.ID, models.JobStateArchived, nil).ID },
+			userID:        employer.ID,
+			expectedCodes: []string{models.JobDeleteBlockerInvalidState},
+		},
+		{
+			name:          "ContractorAssigned",
+			setupFunc:     func() uuid.UUID { return createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, &contractor.ID).ID },
+			userID:        employer.ID,
+			expectedCodes: []string{models.JobDeleteBlockerContractorAssigned},
+		},
+		{
+			name: "InvoicesExist",
+			setupFunc: func() uuid.UUID {
+				job := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+				createTestInvoice(t, ctx, pool, job.ID, 1, 100, models.InvoiceStatePaid)
+				return job.ID
+			},
+			userID:        employer.ID,
+			expectedCodes: []string{models.JobDeleteBlockerInvoicesExist},
+		},
+		{
+			// Every blocker is reported, not just the first
+			name: "AllBlockers",
+			setupFunc: func() uuid.UUID {
+				job := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+				createTestInvoice(t, ctx, pool, job.ID, 1, 100, models.InvoiceStateWaiting)
+				return job.ID
+			},
+			userID:        employer.ID,
+			expectedCodes: []string{models.JobDeleteBlockerInvalidState, models.JobDeleteBlockerContractorAssigned, models.JobDeleteBlockerInvoicesExist},
+		},
+		{
+			name:        "Forbidden_NotEmployer",
+			setupFunc:   func() uuid.UUID { return createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil).ID },
+			userID:      otherUser.ID,
+			expectedErr: services.ErrForbidden,
+		},
+		{
+			name:        "NotFound",
+			setupFunc:   func() uuid.UUID { return uuid.New() },
+			userID:      employer.ID,
+			expectedErr: services.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobID := tt.setupFunc()
+			blockers, err := jobService.CanDeleteJob(ctx, &dto.DeleteJobRequest{ID: jobID, UserID: tt.userID})
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			codes := []string{}
+			for _, blocker := range blockers {
+				codes = append(codes, blocker.Code)
+				assert.NotEmpty(t, blocker.Message)
+			}
+			assert.Equal(t, tt.expectedCodes, codes)
+
+			// The dry run never deletes, and agrees with the real delete
+			_, err = jobService.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+			require.NoError(t, err, "Dry run must not delete the job")
+			err = jobService.DeleteJob(ctx, &dto.DeleteJobRequest{ID: jobID, UserID: tt.userID})
+			if len(tt.expectedCodes) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, services.ErrInvalidState)
+			}
+		})
+	}
+}
+
+func TestJobService_Integration_ArchiveStaleJobs(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "outbox")
+
+	employer := createTestUser(t, ctx, pool, "archiver-employer@test.com", "Archiver Employer")
+	contractor := createTestUser(t, ctx, pool, "archiver-contractor@test.com", "Archiver Contractor")
+
+	staleJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	recentJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	staleOngoingJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	for _, id := range []uuid.UUID{staleJob.ID, staleOngoingJob.ID} {
+		_, err := pool.Exec(ctx, `UPDATE jobs SET updated_at = NOW() - INTERVAL '40 days' WHERE id = $1`, id)
+		require.NoError(t, err)
+	}
+
+	archiver := scheduler.NewJobArchiver(jobService, time.Hour, 30*24*time.Hour, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	archived, err := archiver.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	expectedStates := map[uuid.UUID]models.JobState{
+		staleJob.ID:        models.JobStateArchived,
+		recentJob.ID:       models.JobStateComplete, // Not old enough yet
+		staleOngoingJob.ID: models.JobStateOngoing,  // Only Complete jobs are archived
+	}
+	for id, expected := range expectedStates {
+		job, err := jobService.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: id})
+		require.NoError(t, err)
+		assert.Equal(t, expected, job.State, "job %s", id)
+	}
+
+	// The archive is recorded like a manual state change
+	var eventCount int
+	err = pool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox WHERE aggregate_id = $1 AND event_type = $2`, staleJob.ID, models.OutboxEventJobStateChanged).Scan(&eventCount)
+	require.NoError(t, err)
+	assert.Equal(t, 1, eventCount)
+
+	// A second sweep finds nothing left to archive
+	archived, err = archiver.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, archived)
+}
+
+func TestJobService_Integration_PurgeArchivedJobs(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	ratingService := services.NewRatingService(pool, cache.Config{})
+	defer cleanupTables(t, pool, "users", "jobs", "invoices", "job_application", "audit_log", "ratings")
+
+	employer := createTestUser(t, ctx, pool, "purge-employer@test.com", "Purge Employer")
+	contractor := createTestUser(t, ctx, pool, "purge-contractor@test.com", "Purge Contractor")
+
+	expiredJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateArchived, &contractor.ID)
+	createTestInvoice(t, ctx, pool, expiredJob.ID, 1, 100, models.InvoiceStatePaid)
+	createTestInvoice(t, ctx, pool, expiredJob.ID, 2, 200, models.InvoiceStatePaid)
+	_, err := pool.Exec(ctx, `INSERT INTO job_application (id, contractor_id, state, job_id) VALUES ($1, $2, 'Accepted', $3)`, uuid.New(), contractor.ID, expiredJob.ID)
+	require.NoError(t, err)
+	err = postgres.NewAuditRepo(pool).Record(ctx, &models.AuditEntry{ActorID: &employer.ID, Action: models.AuditActionJobCancelled, EntityType: models.AuditEntityJob, EntityID: expiredJob.ID, Details: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+	_, err = postgres.NewRatingRepo(pool).Create(ctx, &models.Rating{JobID: expiredJob.ID, RaterID: employer.ID, RateeID: contractor.ID, Score: 2})
+	require.NoError(t, err)
+	ratedJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	_, err = ratingService.SubmitRating(ctx, &dto.SubmitRatingRequest{JobID: ratedJob.ID, UserID: employer.ID, Score: 5})
+	require.NoError(t, err)
+	ratingBefore, err := ratingService.GetUserAverageRating(ctx, &dto.GetUserRatingRequest{UserID: contractor.ID})
+	require.NoError(t, err)
+
+	recentJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateArchived, &contractor.ID)
+	recentInvoice := createTestInvoice(t, ctx, pool, recentJob.ID, 1, 300, models.InvoiceStatePaid)
+	unpaidJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateArchived, &contractor.ID)
+	createTestInvoice(t, ctx, pool, unpaidJob.ID, 1, 400, models.InvoiceStateComplete)
+	oldCompleteJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	for _, id := range []uuid.UUID{expiredJob.ID, unpaidJob.ID, oldCompleteJob.ID} {
+		_, err := pool.Exec(ctx, `UPDATE jobs SET updated_at = NOW() - INTERVAL '400 days' WHERE id = $1`, id)
+		require.NoError(t, err)
+	}
+
+	purger := scheduler.NewJobPurger(jobService, time.Hour, 365*24*time.Hour, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	purged, err := purger.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	var jobCount, invoiceCount, applicationCount int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM jobs WHERE id = $1`, expiredJob.ID).Scan(&jobCount))
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM invoices WHERE job_id = $1`, expiredJob.ID).Scan(&invoiceCount))
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM job_application WHERE job_id = $1`, expiredJob.ID).Scan(&applicationCount))
+	assert.Zero(t, jobCount, "The job must be deleted for good, not soft-deleted")
+	assert.Zero(t, invoiceCount)
+	assert.Zero(t, applicationCount)
+
+	// The history of the purged job outlives it
+	entries, err := postgres.NewAuditRepo(pool).ListByEntity(ctx, models.AuditEntityJob, expiredJob.ID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// So does its rating: the contractor's average and count don't change
+	ratingAfter, err := ratingService.GetUserAverageRating(ctx, &dto.GetUserRatingRequest{UserID: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, ratingAfter.RatingCount)
+	assert.InDelta(t, ratingBefore.AverageScore, ratingAfter.AverageScore, 0.0001)
+	assert.Equal(t, ratingBefore.RatingCount, ratingAfter.RatingCount)
+
+	// Recently archived, not archived and not fully paid jobs stay
+	for _, id := range []uuid.UUID{recentJob.ID, unpaidJob.ID, oldCompleteJob.ID} {
+		_, err := jobService.GetJobByID(ctx, &dto.GetJobByIDRequest{ID: id})
+		assert.NoError(t, err, "job %s", id)
+	}
+	_, err = postgres.NewInvoiceRepo(pool).GetByID(ctx, &dto.GetInvoiceByIDRequest{ID: recentInvoice.ID})
+	assert.NoError(t, err)
+
+	// A second sweep finds nothing left to purge
+	purged, err = purger.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, purged)
+}
+
+func TestJobService_Integration_ListJobsByEmployer_UpdatedSince(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	employer := createTestUser(t, ctx, pool, "sync-employer@test.com", "Sync Employer")
+	first := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	second := createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	cutoff := latestUpdatedAt(t, ctx, pool, "jobs")
+
+	// Changed after the cutoff, second before first
+	_, err := pool.Exec(ctx, "UPDATE jobs SET description = 'Changed' WHERE id = $1", second.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "UPDATE jobs SET description = 'Changed' WHERE id = $1", first.ID)
+	require.NoError(t, err)
+
+	jobs, total, err := jobService.ListJobsByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: employer.ID, Limit: 10, UpdatedSince: &cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total, "Only jobs changed after the cutoff must be counted")
+	require.Len(t, jobs, 2)
+	assert.Equal(t, []uuid.UUID{second.ID, first.ID}, []uuid.UUID{jobs[0].ID, jobs[1].ID}, "Jobs must be ordered by updated_at ascending")
+
+	// Combines with the other filters and pagination
+	jobs, total, err = jobService.ListJobsByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: employer.ID, Limit: 1, Offset: 1, State: ptrJobState(models.JobStateWaiting), UpdatedSince: &cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, first.ID, jobs[0].ID)
+
+	cutoff = latestUpdatedAt(t, ctx, pool, "jobs")
+	jobs, total, err = jobService.ListJobsByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: employer.ID, Limit: 10, UpdatedSince: &cutoff})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, jobs, "Nothing changed after the newest row")
+}
+
+func TestJobService_Integration_CreatedByUpdatedBy(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "outbox")
+	jobRepo := postgres.NewJobRepo(pool)
+
+	employer := createTestUser(t, ctx, pool, "audit-job-employer@test.com", "Audit Employer")
+	contractor := createTestUser(t, ctx, pool, "audit-job-contractor@test.com", "Audit Contractor")
+
+	// The employer posting the job is both its creator and its last updater
+	job, err := jobService.CreateJob(ctx, &dto.CreateJobRequest{Rate: 40, Duration: 20, InvoiceInterval: 10, EmployerID: employer.ID})
+	require.NoError(t, err)
+	require.NotNil(t, job.CreatedBy)
+	require.NotNil(t, job.UpdatedBy)
+	assert.Equal(t, employer.ID, *job.CreatedBy)
+	assert.Equal(t, employer.ID, *job.UpdatedBy)
+
+	_, err = jobRepo.AssignContractor(ctx, &dto.AssignContractorRequest{JobID: job.ID, ContractorID: contractor.ID, UpdatedBy: employer.ID})
+	require.NoError(t, err)
+
+	// A change by another user moves updated_by but keeps created_by
+	completed, err := jobService.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: job.ID, UserID: contractor.ID, State: models.JobStateComplete})
+	require.NoError(t, err)
+	require.NotNil(t, completed.UpdatedBy)
+	assert.Equal(t, employer.ID, *completed.CreatedBy)
+	assert.Equal(t, contractor.ID, *completed.UpdatedBy)
+
+	dbJob, err := jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, employer.ID, *dbJob.CreatedBy)
+	assert.Equal(t, contractor.ID, *dbJob.UpdatedBy)
+
+	// Background archiving is recorded against the system actor
+	_, err = pool.Exec(ctx, `UPDATE jobs SET updated_at = NOW() - INTERVAL '40 days' WHERE id = $1`, job.ID)
+	require.NoError(t, err)
+	archived, err := jobService.ArchiveStaleJobs(ctx, &dto.ArchiveStaleJobsRequest{CompletedBefore: time.Now().Add(-30 * 24 * time.Hour), Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, archived)
+	dbJob, err = jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, employer.ID, *dbJob.CreatedBy)
+	assert.Equal(t, models.SystemActorID, *dbJob.UpdatedBy)
+}
+
+func TestJobService_Integration_CreateJobValidatesTerms(t *testing.T) {
+	ctx, jobService, pool := setupJobServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	employer := createTestUser(t, ctx, pool, "terms-employer@test.com", "Terms Employer")
+
+	tests := []struct {
+		name    string
+		req     dto.CreateJobRequest
+		wantErr bool
+	}{
+		{name: "ZeroInterval", req: dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 0}, wantErr: true},
+		{name: "IntervalLongerThanDuration", req: dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 21}, wantErr: true},
+		{name: "ZeroDuration", req: dto.CreateJobRequest{Rate: 10, Duration: 0, InvoiceInterval: 10}, wantErr: true},
+		{name: "ZeroRate", req: dto.CreateJobRequest{Rate: 0, Duration: 20, InvoiceInterval: 10}, wantErr: true},
+		{name: "Valid", req: dto.CreateJobRequest{Rate: 10, Duration: 20, InvoiceInterval: 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.EmployerID = employer.ID
+			job, err := jobService.CreateJob(ctx, &req)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, services.ErrValidation)
+				assert.Nil(t, job)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, req.InvoiceInterval, job.InvoiceInterval)
+		})
+	}
+
+	var count int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM jobs WHERE employer_id = $1", employer.ID).Scan(&count))
+	assert.Equal(t, 1, count, "Rejected jobs must not be stored")
+}
@@ -0,0 +1,144 @@
+package integration_tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/storage/cache"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Test Setup ---
+
+// setupRatingServiceIntegrationTest initializes the service with a real DB pool.
+func setupRatingServiceIntegrationTest(t *testing.T) (context.Context, services.RatingService, *pgxpool.Pool) {
+	t.Helper()
+	pool, _ := getTestClients(t)
+	ratingService := services.NewRatingService(pool, cache.Config{})
+	ctx := context.Background()
+	return ctx, ratingService, pool
+}
+
+// --- Test Cases ---
+
+func TestRatingService_Integration_SubmitRating(t *testing.T) {
+	ctx, ratingService, pool := setupRatingServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "ratings")
+
+	employer := createTestUser(t, ctx, pool, "rating-employer@test.com", "Rating Employer")
+	contractor := createTestUser(t, ctx, pool, "rating-contractor@test.com", "Rating Contractor")
+
+	completeJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	ongoingJob := createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+	comment := "Great work"
+
+	tests := []struct {
+		name        string
+		req         *dto.SubmitRatingRequest
+		expectedErr error
+	}{
+		{
+			name: "Success_EmployerRatesCompletedJob",
+			req:  &dto.SubmitRatingRequest{JobID: completeJob.ID, UserID: employer.ID, Score: 4, Comment: &comment},
+		},
+		{
+			name:        "Error_Conflict_AlreadyRated",
+			req:         &dto.SubmitRatingRequest{JobID: completeJob.ID, UserID: employer.ID, Score: 5},
+			expectedErr: services.ErrConflict,
+		},
+		{
+			name:        "Error_InvalidState_JobNotComplete",
+			req:         &dto.SubmitRatingRequest{JobID: ongoingJob.ID, UserID: employer.ID, Score: 3},
+			expectedErr: services.ErrInvalidState,
+		},
+		{
+			name:        "Error_Forbidden_Contractor",
+			req:         &dto.SubmitRatingRequest{JobID: completeJob.ID, UserID: contractor.ID, Score: 5},
+			expectedErr: services.ErrForbidden,
+		},
+		{
+			name:        "Error_JobNotFound",
+			req:         &dto.SubmitRatingRequest{JobID: uuid.New(), UserID: employer.ID, Score: 5},
+			expectedErr: services.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating, err := ratingService.SubmitRating(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, rating)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, rating)
+			assert.Equal(t, tt.req.JobID, rating.JobID)
+			assert.Equal(t, employer.ID, rating.RaterID)
+			assert.Equal(t, contractor.ID, rating.RateeID)
+			assert.Equal(t, tt.req.Score, rating.Score)
+			assert.Equal(t, tt.req.Comment, rating.Comment)
+		})
+	}
+
+	// The rejected attempts must not have changed the stored rating
+	summary, err := ratingService.GetUserAverageRating(ctx, &dto.GetUserRatingRequest{UserID: contractor.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.RatingCount)
+	assert.InDelta(t, 4.0, summary.AverageScore, 0.001)
+}
+
+func TestRatingService_Integration_GetUserAverageRating(t *testing.T) {
+	ctx, ratingService, pool := setupRatingServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "ratings")
+
+	employer := createTestUser(t, ctx, pool, "avg-employer@test.com", "Avg Employer")
+	contractor := createTestUser(t, ctx, pool, "avg-contractor@test.com", "Avg Contractor")
+	unrated := createTestUser(t, ctx, pool, "avg-unrated@test.com", "Avg Unrated")
+
+	for _, score := range []int{5, 4, 2} {
+		job := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+		_, err := ratingService.SubmitRating(ctx, &dto.SubmitRatingRequest{JobID: job.ID, UserID: employer.ID, Score: score})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name            string
+		userID          uuid.UUID
+		expectedAverage float64
+		expectedCount   int
+		expectedErr     error
+	}{
+		{name: "Success_Averaged", userID: contractor.ID, expectedAverage: 11.0 / 3.0, expectedCount: 3},
+		{name: "Success_NoRatings", userID: unrated.ID, expectedAverage: 0, expectedCount: 0},
+		{name: "Error_UserNotFound", userID: uuid.New(), expectedErr: services.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := ratingService.GetUserAverageRating(ctx, &dto.GetUserRatingRequest{UserID: tt.userID})
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "Expected error %v, got %v", tt.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.userID, summary.UserID)
+			assert.Equal(t, tt.expectedCount, summary.RatingCount)
+			assert.InDelta(t, tt.expectedAverage, summary.AverageScore, 0.001)
+		})
+	}
+}
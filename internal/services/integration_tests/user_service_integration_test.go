@@ -2,16 +2,25 @@ package integration_tests
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"go-api-template/internal/database"
+	"go-api-template/internal/mailer"
 	"go-api-template/internal/models"
 	"go-api-template/internal/services"
 	"go-api-template/internal/storage"          // For storage errors
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres" // Need concrete repo for assertion
 	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/auth"
+	"go-api-template/pkg/hasher"
+	"go-api-template/pkg/password"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9" // Import redis
@@ -26,6 +35,7 @@ const (
 	testJwtSecret              = "test-integration-secret"
 	testJwtExpiration          = 1 * time.Minute // Short duration for tests
 	testRefreshTokenExpiration = 5 * time.Minute
+	testImpersonationExpiration = 30 * time.Second
 )
 
 // setupUserServiceIntegrationTest initializes the service with a real DB pool
@@ -33,7 +43,7 @@ const (
 func setupUserServiceIntegrationTest(t *testing.T) (context.Context, services.UserService, *pgxpool.Pool, *redis.Client) {
 	t.Helper()
 	pool, redisClient := getTestClients(t)
-	userService := services.NewUserService(redisClient, testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, pool)
+	userService := services.NewUserService(redisClient, "", testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, mailer.NewLogMailer(), false, password.DefaultPolicy(), hasher.NewBcrypt(bcrypt.DefaultCost), 0, 0, cache.Config{})
 	ctx := context.Background()
 	return ctx, userService, pool, redisClient
 }
@@ -48,7 +58,7 @@ func TestUserService_Integration_RegisterAndGet(t *testing.T) {
 	registerReq := &dto.CreateUserRequest{
 		Email:    "register-get@test.com",
 		Name:     "Register Get User",
-		Password: "password123",
+		Password: "Password123",
 	}
 	createdUser, err := userService.Register(ctx, registerReq)
 
@@ -97,11 +107,21 @@ func TestUserService_Integration_RegisterAndGet(t *testing.T) {
 	duplicateRegisterReq := &dto.CreateUserRequest{
 		Email:    "register-get@test.com", // Same email
 		Name:     "Duplicate User",
-		Password: "password456",
+		Password: "Password456",
 	}
 	_, err = userService.Register(ctx, duplicateRegisterReq)
 	require.Error(t, err)
 	assert.True(t, errors.Is(err, services.ErrConflict), "Expected ErrConflict, got %v", err) // Service maps storage.ErrDuplicateEmail
+
+	// --- Register - Weak Password ---
+	_, err = userService.Register(ctx, &dto.CreateUserRequest{Email: "weak-password@test.com", Name: "Weak User", Password: "password"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrWeakPassword), "Expected ErrWeakPassword, got %v", err)
+	var policyErr *password.PolicyError
+	require.True(t, errors.As(err, &policyErr))
+	assert.Equal(t, []string{"an upper-case letter", "a digit"}, policyErr.Unmet)
+	_, err = userService.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: "weak-password@test.com"})
+	assert.True(t, errors.Is(err, services.ErrNotFound), "Weak password registration must not create the user")
 }
 
 func TestUserService_Integration_Update(t *testing.T) {
@@ -154,6 +174,66 @@ func TestUserService_Integration_Update(t *testing.T) {
 	assert.True(t, errors.Is(err, services.ErrNotFound), "Expected ErrNotFound, got %v", err)
 }
 
+// TestUserService_Integration_UpdateEmail tests changing the email and the re-verification it triggers.
+func TestUserService_Integration_UpdateEmail(t *testing.T) {
+	pool, redisClient := getTestClients(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	ctx := context.Background()
+	recMailer := &recordingMailer{}
+	userService := services.NewUserService(redisClient, "", testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, recMailer, false, password.DefaultPolicy(), hasher.NewBcrypt(bcrypt.DefaultCost), 0, 0, cache.Config{})
+	userRepo := postgres.NewUserRepo(pool) // For setup/verification
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: A verified user with a pending token for the old address, and a second user ---
+	user, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "email-old@test.com", Name: "Email User", Password: "Password123"})
+	require.NoError(t, err)
+	verified := true
+	_, err = userRepo.Update(ctx, &dto.UpdateUserRequest{ID: user.ID, Verified: &verified})
+	require.NoError(t, err)
+	require.NoError(t, redisClient.Set(ctx, services.RedisEmailVerificationPrefix+"old-token", user.ID.String(), time.Hour).Err())
+	otherUser, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "email-taken@test.com", Name: "Other User", Password: "Password123"})
+	require.NoError(t, err)
+
+	// --- Test Execution: Email already used by someone else ---
+	takenEmail := otherUser.Email
+	_, err = userService.Update(ctx, &dto.UpdateUserRequest{ID: user.ID, Email: &takenEmail})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrConflict), "Expected ErrConflict, got %v", err)
+
+	// --- Test Execution: Unchanged email is rejected without resetting verification ---
+	sameEmail := user.Email
+	_, err = userService.Update(ctx, &dto.UpdateUserRequest{ID: user.ID, Email: &sameEmail})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrValidation), "Expected ErrValidation, got %v", err)
+	dbUser, err := userRepo.GetByID(ctx, &dto.GetUserByIdRequest{ID: user.ID})
+	require.NoError(t, err)
+	assert.True(t, dbUser.Verified)
+	assert.Empty(t, recMailer.lastTo, "No verification email for a failed update")
+
+	// --- Test Execution: Successful change ---
+	newEmail := "email-new@test.com"
+	updatedUser, err := userService.Update(ctx, &dto.UpdateUserRequest{ID: user.ID, Email: &newEmail})
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, updatedUser.Email)
+	assert.False(t, updatedUser.Verified)
+	assert.Equal(t, newEmail, recMailer.lastTo, "Verification email goes to the new address")
+
+	// The old address's token no longer works; the new one does
+	_, err = userService.VerifyEmail(ctx, "old-token")
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials))
+	keys, err := redisClient.Keys(ctx, services.RedisEmailVerificationPrefix+"*").Result()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	token := strings.TrimPrefix(keys[0], services.RedisEmailVerificationPrefix)
+	assert.Contains(t, recMailer.lastBody, token)
+	verifiedUser, err := userService.VerifyEmail(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, verifiedUser.Verified)
+}
+
 func TestUserService_Integration_Delete(t *testing.T) {
 	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
 	userRepo := postgres.NewUserRepo(pool) // For setup/verification
@@ -175,12 +255,35 @@ func TestUserService_Integration_Delete(t *testing.T) {
 	// --- Assertions ---
 	require.NoError(t, err)
 
-	// Verify user is gone from DB
+	// Verify user is hidden from reads
 	getReq := &dto.GetUserByIdRequest{ID: createdUser.ID}
 	_, dbErr := userRepo.GetByID(ctx, getReq)
 	require.Error(t, dbErr)
 	assert.True(t, errors.Is(dbErr, storage.ErrNotFound))
 
+	// Verify the row is kept with deleted_at set
+	var deletedAt *time.Time
+	err = pool.QueryRow(ctx, `SELECT deleted_at FROM users WHERE id = $1`, createdUser.ID).Scan(&deletedAt)
+	require.NoError(t, err, "Soft-deleted user row should still exist")
+	assert.NotNil(t, deletedAt, "deleted_at should be set")
+
+	// --- Test Execution: Delete Already Deleted ---
+	err = userService.Delete(ctx, deleteReq)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+	// --- Test Execution: Restore ---
+	restoredUser, err := userService.RestoreUser(ctx, &dto.RestoreUserRequest{ID: createdUser.ID})
+	require.NoError(t, err)
+	assert.Equal(t, createdUser.ID, restoredUser.ID)
+	_, dbErr = userRepo.GetByID(ctx, getReq)
+	assert.NoError(t, dbErr, "Restored user should be readable again")
+
+	// Restoring a user that isn't deleted is a not-found
+	_, err = userService.RestoreUser(ctx, &dto.RestoreUserRequest{ID: createdUser.ID})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrNotFound))
+
 	// --- Test Execution: Delete Not Found ---
 	deleteReqNotFound := &dto.DeleteUserRequest{ID: uuid.New()} // Non-existent ID
 	err = userService.Delete(ctx, deleteReqNotFound)
@@ -218,6 +321,99 @@ func TestUserService_Integration_GetAll(t *testing.T) {
 	assert.Equal(t, "GetAll Two", userMap[user2.ID].Name)
 }
 
+func TestUserService_Integration_ListUsers(t *testing.T) {
+	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+
+	for _, req := range []dto.CreateUserRequest{
+		{Email: "carol@test.com", Name: "Carol", Password: "p"},
+		{Email: "alice@test.com", Name: "Alice", Password: "p"},
+		{Email: "bob@example.org", Name: "Bob", Password: "p"},
+		{Email: "percent@test.com", Name: "100% Dave", Password: "p"},
+	} {
+		_, err := userRepo.Create(ctx, &req)
+		require.NoError(t, err)
+	}
+	search := func(s string) *string { return &s }
+	names := func(users []models.User) []string {
+		out := make([]string, 0, len(users))
+		for _, u := range users {
+			out = append(out, u.Name)
+		}
+		return out
+	}
+
+	// Pages follow name order and each reports the total
+	first, total, err := userService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 2, Offset: 0})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []string{"100% Dave", "Alice"}, names(first))
+	last, total, err := userService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []string{"Bob", "Carol"}, names(last))
+	beyond, total, err := userService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 2, Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Empty(t, beyond)
+
+	// Search matches name or email, ignoring case, and counts only the matches
+	byName, total, err := userService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 10, Search: search("ALI")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"Alice"}, names(byName))
+	byEmail, total, err := userService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 10, Search: search("example.org")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"Bob"}, names(byEmail))
+
+	// Wildcards in the search are taken literally
+	literal, total, err := userService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 10, Search: search("%")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"100% Dave"}, names(literal))
+}
+
+func TestUserService_Integration_GetByIDs(t *testing.T) {
+	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+
+	user1, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "batch1@test.com", Name: "Batch One", Password: "p"})
+	require.NoError(t, err)
+	user2, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "batch2@test.com", Name: "Batch Two", Password: "p"})
+	require.NoError(t, err)
+	user3, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "batch3@test.com", Name: "Batch Three", Password: "p"})
+	require.NoError(t, err)
+	deleted, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "batch-deleted@test.com", Name: "Batch Deleted", Password: "p"})
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Delete(ctx, &dto.DeleteUserRequest{ID: deleted.ID}))
+
+	tests := []struct {
+		name     string
+		ids      []uuid.UUID
+		expected []uuid.UUID
+	}{
+		{name: "RequestOrder", ids: []uuid.UUID{user3.ID, user1.ID, user2.ID}, expected: []uuid.UUID{user3.ID, user1.ID, user2.ID}},
+		{name: "UnknownAndDeletedOmitted", ids: []uuid.UUID{uuid.New(), user2.ID, deleted.ID}, expected: []uuid.UUID{user2.ID}},
+		{name: "DuplicatesCollapsed", ids: []uuid.UUID{user1.ID, user2.ID, user1.ID}, expected: []uuid.UUID{user1.ID, user2.ID}},
+		{name: "NoneFound", ids: []uuid.UUID{uuid.New()}, expected: []uuid.UUID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := userService.GetByIDs(ctx, tt.ids)
+			require.NoError(t, err)
+			ids := make([]uuid.UUID, 0, len(users))
+			for _, u := range users {
+				ids = append(ids, u.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
 // TestUserService_Integration_Login tests the login process including token generation and Redis storage.
 func TestUserService_Integration_Login(t *testing.T) {
 	ctx, userService, pool, redisClient := setupUserServiceIntegrationTest(t)
@@ -282,6 +478,123 @@ func TestUserService_Integration_Login(t *testing.T) {
 	assert.True(t, errors.Is(err, services.ErrInvalidCredentials)) // Service maps NotFound to InvalidCredentials
 }
 
+// TestUserService_Integration_LoginUpgradesPasswordHash tests that a hash below the configured cost is re-hashed on login.
+func TestUserService_Integration_LoginUpgradesPasswordHash(t *testing.T) {
+	pool, redisClient := getTestClients(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	ctx := context.Background()
+	targetCost := bcrypt.MinCost + 1 // Kept low so the test stays fast
+	userService := services.NewUserService(redisClient, "", testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, mailer.NewLogMailer(), false, password.DefaultPolicy(), hasher.NewBcrypt(targetCost), 0, 0, cache.Config{})
+	userRepo := postgres.NewUserRepo(pool)
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: Create a user with a hash below the configured cost ---
+	plainPassword := "rehashPass123"
+	user, err := userRepo.WithHasher(hasher.NewBcrypt(bcrypt.MinCost)).Create(ctx, &dto.CreateUserRequest{
+		Email:    "rehash@test.com",
+		Name:     "Rehash User",
+		Password: plainPassword,
+	})
+	require.NoError(t, err)
+
+	storedHash := func() string {
+		dbUser, err := userRepo.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: user.Email})
+		require.NoError(t, err)
+		return dbUser.PasswordHash
+	}
+	oldHash := storedHash()
+	oldCost, err := bcrypt.Cost([]byte(oldHash))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.MinCost, oldCost)
+
+	// --- Test Execution: Login upgrades the hash ---
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: plainPassword})
+	require.NoError(t, err)
+
+	upgradedHash := storedHash()
+	assert.NotEqual(t, oldHash, upgradedHash)
+	newCost, err := bcrypt.Cost([]byte(upgradedHash))
+	require.NoError(t, err)
+	assert.Equal(t, targetCost, newCost)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(upgradedHash), []byte(plainPassword)), "Upgraded hash should still match the password")
+
+	// --- Test Execution: A hash already at the configured cost is left alone ---
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: plainPassword})
+	require.NoError(t, err)
+	assert.Equal(t, upgradedHash, storedHash())
+
+	// --- Test Execution: A failed login doesn't touch the hash ---
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "wrongPassword"})
+	require.ErrorIs(t, err, services.ErrInvalidCredentials)
+	assert.Equal(t, upgradedHash, storedHash())
+}
+
+// TestUserService_Integration_LoginAfterSwitchingToArgon2id tests that bcrypt hashes still log in once
+// argon2id is the default, and are migrated to argon2id on that login.
+func TestUserService_Integration_LoginAfterSwitchingToArgon2id(t *testing.T) {
+	pool, redisClient := getTestClients(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	ctx := context.Background()
+	argonHasher, err := hasher.New(hasher.Config{
+		Algorithm:  hasher.Argon2id,
+		BcryptCost: bcrypt.MinCost,
+		Argon2id:   hasher.Argon2idParams{MemoryKiB: 1024, Iterations: 1, Parallelism: 1}, // Kept low so the test stays fast
+	})
+	require.NoError(t, err)
+	userService := services.NewUserService(redisClient, "", testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, mailer.NewLogMailer(), false, password.DefaultPolicy(), argonHasher, 0, 0, cache.Config{})
+	userRepo := postgres.NewUserRepo(pool)
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: A user registered while bcrypt was the default ---
+	plainPassword := "switchPass123"
+	user, err := userRepo.WithHasher(hasher.NewBcrypt(bcrypt.MinCost)).Create(ctx, &dto.CreateUserRequest{
+		Email:    "switch-algorithm@test.com",
+		Name:     "Switch Algorithm User",
+		Password: plainPassword,
+	})
+	require.NoError(t, err)
+
+	storedHash := func() string {
+		dbUser, err := userRepo.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: user.Email})
+		require.NoError(t, err)
+		return dbUser.PasswordHash
+	}
+	alg, err := hasher.AlgorithmOf(storedHash())
+	require.NoError(t, err)
+	require.Equal(t, hasher.Bcrypt, alg)
+
+	// --- Test Execution: The bcrypt hash still logs in, and is re-hashed with argon2id ---
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: plainPassword})
+	require.NoError(t, err)
+
+	migratedHash := storedHash()
+	alg, err = hasher.AlgorithmOf(migratedHash)
+	require.NoError(t, err)
+	assert.Equal(t, hasher.Argon2id, alg)
+
+	// --- Test Execution: The argon2id hash logs in too, and is left alone ---
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: plainPassword})
+	require.NoError(t, err)
+	assert.Equal(t, migratedHash, storedHash())
+
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "wrongPassword"})
+	require.ErrorIs(t, err, services.ErrInvalidCredentials)
+
+	// --- Test Execution: ChangePassword hashes with the new default ---
+	require.NoError(t, userService.ChangePassword(ctx, &dto.ChangePasswordRequest{ID: user.ID, CurrentPassword: plainPassword, NewPassword: "switchPass456"}))
+	alg, err = hasher.AlgorithmOf(storedHash())
+	require.NoError(t, err)
+	assert.Equal(t, hasher.Argon2id, alg)
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "switchPass456"})
+	require.NoError(t, err)
+}
+
 // TestUserService_Integration_Refresh tests token refresh using Redis.
 func TestUserService_Integration_Refresh(t *testing.T) {
 	ctx, userService, pool, redisClient := setupUserServiceIntegrationTest(t)
@@ -373,3 +686,549 @@ func TestUserService_Integration_Logout(t *testing.T) {
 	err = userService.Logout(ctx, logoutReqUsed)
 	require.NoError(t, err, "Logout with already invalidated token should not return an error")
 }
+
+// TestUserService_Integration_AccessTokenKeyID tests that access tokens name the key they are signed with.
+func TestUserService_Integration_AccessTokenKeyID(t *testing.T) {
+	pool, redisClient := getTestClients(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	user, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "kid@test.com", Name: "Kid User", Password: "p"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		keyID string
+	}{
+		{name: "ActiveKey", keyID: "2025-03"},
+		{name: "NoRotation", keyID: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService := services.NewUserService(redisClient, tt.keyID, testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, mailer.NewLogMailer(), false, password.DefaultPolicy(), hasher.NewBcrypt(bcrypt.DefaultCost), 0, 0, cache.Config{})
+			_, accessToken, _, err := userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "p"})
+			require.NoError(t, err)
+
+			token, err := jwt.ParseWithClaims(accessToken, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+				return []byte(testJwtSecret), nil
+			})
+			require.NoError(t, err)
+			kid, present := token.Header["kid"]
+			if tt.keyID == "" {
+				assert.False(t, present, "Without rotation, tokens carry no kid header")
+				return
+			}
+			assert.Equal(t, tt.keyID, kid)
+		})
+	}
+}
+
+func TestUserService_Integration_ImpersonateUser(t *testing.T) {
+	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs", "outbox", "audit_log")
+	auditRepo := postgres.NewAuditRepo(pool) // For verification
+
+	admin := createTestUser(t, ctx, pool, "impersonation-admin@test.com", "Support Admin")
+	require.NoError(t, database.SeedAdmin(ctx, pool, admin.Email))
+	employer := createTestUser(t, ctx, pool, "impersonated-employer@test.com", "Impersonated Employer")
+	contractor := createTestUser(t, ctx, pool, "impersonation-contractor@test.com", "Contractor")
+
+	issuedAt := time.Now()
+	user, accessToken, expiresAt, err := userService.ImpersonateUser(ctx, &dto.ImpersonateUserRequest{ID: employer.ID, AdminID: admin.ID})
+	require.NoError(t, err)
+	assert.Equal(t, employer.ID, user.ID)
+	assert.WithinDuration(t, issuedAt.Add(testImpersonationExpiration), expiresAt, 2*time.Second, "Impersonation tokens get the reduced TTL")
+
+	// The token authenticates as the user and names the admin as the real actor
+	claims := &auth.AccessTokenClaims{}
+	_, err = jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(testJwtSecret), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, employer.ID.String(), claims.Subject)
+	assert.Equal(t, string(models.UserRoleUser), claims.Role)
+	require.NotNil(t, claims.Act)
+	assert.Equal(t, admin.ID.String(), claims.Act.Subject)
+
+	// Issuing the token is audited against the admin
+	entries, err := auditRepo.ListByEntity(ctx, models.AuditEntityUser, employer.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditActionUserImpersonated, entries[0].Action)
+	require.NotNil(t, entries[0].ActorID)
+	assert.Equal(t, admin.ID, *entries[0].ActorID)
+	assert.Nil(t, entries[0].ImpersonatorID)
+	var details models.UserImpersonation
+	require.NoError(t, json.Unmarshal(entries[0].Details, &details))
+	assert.Equal(t, claims.ID, details.TokenID)
+
+	// Changes made through the token, whose act claim the middleware puts in the context, record both
+	job := createTestJob(t, ctx, pool, employer.ID, models.JobStateComplete, &contractor.ID)
+	jobService := services.NewJobService(pool, models.DefaultInvoicePaymentTermDays, models.DefaultCurrency, 0, cache.Config{})
+	_, err = jobService.ReopenJob(models.WithImpersonator(ctx, admin.ID), &dto.ReopenJobRequest{JobID: job.ID, UserID: employer.ID})
+	require.NoError(t, err)
+	entries, err = auditRepo.ListByEntity(ctx, models.AuditEntityJob, job.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].ActorID)
+	assert.Equal(t, employer.ID, *entries[0].ActorID)
+	require.NotNil(t, entries[0].ImpersonatorID)
+	assert.Equal(t, admin.ID, *entries[0].ImpersonatorID)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		req         dto.ImpersonateUserRequest
+		expectedErr error
+	}{
+		{name: "Admin", ctx: ctx, req: dto.ImpersonateUserRequest{ID: admin.ID, AdminID: uuid.New()}, expectedErr: services.ErrForbidden},
+		{name: "FromImpersonationToken", ctx: models.WithImpersonator(ctx, admin.ID), req: dto.ImpersonateUserRequest{ID: contractor.ID, AdminID: admin.ID}, expectedErr: services.ErrForbidden},
+		{name: "Self", ctx: ctx, req: dto.ImpersonateUserRequest{ID: admin.ID, AdminID: admin.ID}, expectedErr: services.ErrValidation},
+		{name: "UnknownUser", ctx: ctx, req: dto.ImpersonateUserRequest{ID: uuid.New(), AdminID: admin.ID}, expectedErr: services.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, token, _, err := userService.ImpersonateUser(tt.ctx, &tt.req)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Empty(t, token)
+		})
+	}
+}
+
+func TestUserService_Integration_GetPermissions(t *testing.T) {
+	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "jobs")
+
+	admin := createTestUser(t, ctx, pool, "permissions-admin@test.com", "Permissions Admin")
+	require.NoError(t, database.SeedAdmin(ctx, pool, admin.Email))
+	employer := createTestUser(t, ctx, pool, "permissions-employer@test.com", "Permissions Employer")
+	contractor := createTestUser(t, ctx, pool, "permissions-contractor@test.com", "Permissions Contractor")
+	newcomer := createTestUser(t, ctx, pool, "permissions-newcomer@test.com", "Permissions Newcomer")
+	createTestJob(t, ctx, pool, employer.ID, models.JobStateWaiting, nil)
+	createTestJob(t, ctx, pool, employer.ID, models.JobStateOngoing, &contractor.ID)
+
+	tests := []struct {
+		name         string
+		userID       uuid.UUID
+		expectedRole models.UserRole
+		expected     []models.Capability
+	}{
+		{
+			name:         "Admin",
+			userID:       admin.ID,
+			expectedRole: models.UserRoleAdmin,
+			expected: []models.Capability{
+				models.CapabilityCreateJob, models.CapabilityApplyToJobs, models.CapabilityAccessAdmin, models.CapabilityManageUsers,
+				models.CapabilityRestoreJobs, models.CapabilityManageWebhooks, models.CapabilityManageAPIKeys, models.CapabilityImpersonateUsers,
+			},
+		},
+		{name: "RegularUser", userID: newcomer.ID, expectedRole: models.UserRoleUser, expected: []models.Capability{models.CapabilityCreateJob, models.CapabilityApplyToJobs}},
+		{
+			name:         "EmployerOfWaitingJob",
+			userID:       employer.ID,
+			expectedRole: models.UserRoleUser,
+			expected:     []models.Capability{models.CapabilityCreateJob, models.CapabilityApplyToJobs, models.CapabilityReviewApplications},
+		},
+		{
+			name:         "ContractorOfOngoingJob",
+			userID:       contractor.ID,
+			expectedRole: models.UserRoleUser,
+			expected:     []models.Capability{models.CapabilityCreateJob, models.CapabilityApplyToJobs, models.CapabilityCreateInvoice},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, err := userService.GetPermissions(ctx, tt.userID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRole, permissions.Role)
+			assert.Equal(t, tt.expected, permissions.Capabilities)
+		})
+	}
+
+	_, err := userService.GetPermissions(ctx, uuid.New())
+	assert.ErrorIs(t, err, services.ErrNotFound)
+}
+
+// TestUserService_Integration_RevokeAccessToken tests blacklisting access tokens by jti via Redis.
+func TestUserService_Integration_RevokeAccessToken(t *testing.T) {
+	ctx, userService, pool, redisClient := setupUserServiceIntegrationTest(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: Create user and login to get an access token ---
+	user, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "revoke@test.com", Name: "Revoke User", Password: "p"})
+	require.NoError(t, err)
+	_, accessToken, _, err := userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "p"})
+	require.NoError(t, err)
+
+	claims := &jwt.RegisteredClaims{}
+	_, err = jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(testJwtSecret), nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.ID, "Access token should carry a jti claim")
+
+	// --- Test Execution: Not revoked initially ---
+	revoked, err := userService.IsAccessTokenRevoked(ctx, claims.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// --- Test Execution: Successful Revocation ---
+	err = userService.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time)
+	require.NoError(t, err)
+
+	revoked, err = userService.IsAccessTokenRevoked(ctx, claims.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Verify the blacklist entry expires with the token itself
+	ttl, err := redisClient.TTL(ctx, services.RedisAccessTokenBlacklistPrefix+claims.ID).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, time.Until(claims.ExpiresAt.Time)+time.Second)
+
+	// --- Test Execution: Already Expired Token ---
+	expiredID := uuid.NewString()
+	err = userService.RevokeAccessToken(ctx, expiredID, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	revoked, err = userService.IsAccessTokenRevoked(ctx, expiredID)
+	require.NoError(t, err)
+	assert.False(t, revoked, "An expired token needs no blacklist entry")
+
+	// --- Test Execution: Missing Token ID ---
+	err = userService.RevokeAccessToken(ctx, "", claims.ExpiresAt.Time)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrValidation))
+}
+
+// TestUserService_Integration_LogoutRevokesAccessToken tests that logging out with an access token revokes it.
+func TestUserService_Integration_LogoutRevokesAccessToken(t *testing.T) {
+	ctx, userService, pool, redisClient := setupUserServiceIntegrationTest(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	user, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "logout-revoke@test.com", Name: "Logout User", Password: "p"})
+	require.NoError(t, err)
+	_, accessToken, refreshToken, err := userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "p"})
+	require.NoError(t, err)
+
+	claims := &jwt.RegisteredClaims{}
+	_, err = jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(testJwtSecret), nil
+	})
+	require.NoError(t, err)
+
+	err = userService.Logout(ctx, &dto.LogoutRequest{RefreshToken: refreshToken, AccessTokenID: claims.ID, AccessTokenExpiresAt: claims.ExpiresAt.Time})
+	require.NoError(t, err)
+
+	revoked, err := userService.IsAccessTokenRevoked(ctx, claims.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked, "The access token used to log out must stop working")
+	_, _, err = userService.Refresh(ctx, &dto.RefreshRequest{RefreshToken: refreshToken})
+	assert.Error(t, err, "The refresh token must be invalidated too")
+}
+
+func TestUserService_Integration_RegisterOrGet(t *testing.T) {
+	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users")
+
+	req := &dto.CreateUserRequest{Email: "provisioned@test.com", Name: "Provisioned User", Password: "Password123", ReturnExisting: true}
+
+	// --- First registration creates the user ---
+	first, created, err := userService.RegisterOrGet(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, created)
+	require.NotNil(t, first)
+
+	// --- Retry with the same password returns the same user ---
+	again, created, err := userService.RegisterOrGet(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, created)
+	require.NotNil(t, again)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, req.Email, again.Email)
+
+	// --- Retry with another password is still a conflict ---
+	mismatch := *req
+	mismatch.Password = "OtherPassword456"
+	user, created, err := userService.RegisterOrGet(ctx, &mismatch)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrConflict), "Expected ErrConflict, got %v", err)
+	assert.False(t, created)
+	assert.Nil(t, user)
+
+	// --- Plain Register keeps failing on the duplicate ---
+	_, err = userService.Register(ctx, req)
+	assert.True(t, errors.Is(err, services.ErrConflict), "Expected ErrConflict, got %v", err)
+
+	// --- Only one account exists ---
+	var count int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE email = $1", req.Email).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestUserService_Integration_RegisterOrGet_PasswordPredatesPolicy(t *testing.T) {
+	ctx, userService, pool, _ := setupUserServiceIntegrationTest(t)
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+
+	// Created before the current policy, with a password it would now reject
+	existing, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "legacy@test.com", Name: "Legacy User", Password: "p"})
+	require.NoError(t, err)
+
+	// --- The right password returns the existing user instead of a policy error ---
+	user, created, err := userService.RegisterOrGet(ctx, &dto.CreateUserRequest{Email: existing.Email, Name: existing.Name, Password: "p", ReturnExisting: true})
+	require.NoError(t, err)
+	assert.False(t, created)
+	require.NotNil(t, user)
+	assert.Equal(t, existing.ID, user.ID)
+
+	// --- A wrong, weak password is a conflict, not a policy error ---
+	_, _, err = userService.RegisterOrGet(ctx, &dto.CreateUserRequest{Email: existing.Email, Name: existing.Name, Password: "q", ReturnExisting: true})
+	assert.True(t, errors.Is(err, services.ErrConflict), "Expected ErrConflict, got %v", err)
+
+	// --- New accounts still have to meet the policy ---
+	_, _, err = userService.RegisterOrGet(ctx, &dto.CreateUserRequest{Email: "new-weak@test.com", Name: "New User", Password: "p", ReturnExisting: true})
+	assert.True(t, errors.Is(err, password.ErrWeakPassword), "Expected ErrWeakPassword, got %v", err)
+}
+
+// recordingMailer renders and captures sent emails so tests can read tokens out of them.
+type recordingMailer struct {
+	lastTo   string
+	lastBody string
+}
+
+func (m *recordingMailer) Send(ctx context.Context, to, template string, data any) error {
+	msg, err := mailer.Render(template, data)
+	if err != nil {
+		return err
+	}
+	m.lastTo = to
+	m.lastBody = msg.HTML
+	return nil
+}
+
+// TestUserService_Integration_PasswordReset tests the request/confirm password reset flow via Redis.
+func TestUserService_Integration_PasswordReset(t *testing.T) {
+	pool, redisClient := getTestClients(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	ctx := context.Background()
+	recMailer := &recordingMailer{}
+	userService := services.NewUserService(redisClient, "", testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, recMailer, false, password.DefaultPolicy(), hasher.NewBcrypt(bcrypt.DefaultCost), 0, 0, cache.Config{})
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: Create a user ---
+	user, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "reset@test.com", Name: "Reset User", Password: "oldPassword1"})
+	require.NoError(t, err)
+
+	// --- Test Execution: Unknown email succeeds without sending ---
+	err = userService.RequestPasswordReset(ctx, &dto.PasswordResetRequest{Email: "nosuchuser@test.com"})
+	require.NoError(t, err)
+	assert.Empty(t, recMailer.lastTo)
+
+	// --- Test Execution: Request reset ---
+	err = userService.RequestPasswordReset(ctx, &dto.PasswordResetRequest{Email: user.Email})
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, recMailer.lastTo)
+
+	// Find the token stored in Redis and check it was emailed
+	keys, err := redisClient.Keys(ctx, services.RedisPasswordResetPrefix+"*").Result()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	token := strings.TrimPrefix(keys[0], services.RedisPasswordResetPrefix)
+	assert.Contains(t, recMailer.lastBody, token)
+
+	// --- Test Execution: Weak password is rejected and keeps the token ---
+	err = userService.ConfirmPasswordReset(ctx, &dto.ConfirmPasswordResetRequest{Token: token, NewPassword: "weakpassword"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrWeakPassword))
+
+	// --- Test Execution: Successful confirm ---
+	newPassword := "NewPassword1"
+	err = userService.ConfirmPasswordReset(ctx, &dto.ConfirmPasswordResetRequest{Token: token, NewPassword: newPassword})
+	require.NoError(t, err)
+
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: newPassword})
+	require.NoError(t, err, "Login should succeed with the new password")
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "oldPassword1"})
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials))
+
+	// --- Test Execution: Token is single-use ---
+	err = userService.ConfirmPasswordReset(ctx, &dto.ConfirmPasswordResetRequest{Token: token, NewPassword: "AnotherPass1"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials))
+}
+
+// TestUserService_Integration_ChangePassword tests changing a password and revoking the user's refresh tokens.
+func TestUserService_Integration_ChangePassword(t *testing.T) {
+	ctx, userService, pool, redisClient := setupUserServiceIntegrationTest(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	userRepo := postgres.NewUserRepo(pool) // For setup
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: Two sessions for the user, one for someone else ---
+	oldPassword := "OldPassword1"
+	user, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "change-pw@test.com", Name: "Change User", Password: oldPassword})
+	require.NoError(t, err)
+	other, err := userRepo.Create(ctx, &dto.CreateUserRequest{Email: "change-pw-other@test.com", Name: "Other User", Password: oldPassword})
+	require.NoError(t, err)
+
+	_, _, refreshToken1, err := userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: oldPassword})
+	require.NoError(t, err)
+	_, _, refreshToken2, err := userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: oldPassword})
+	require.NoError(t, err)
+	_, _, otherRefreshToken, err := userService.Login(ctx, &dto.LoginRequest{Email: other.Email, Password: oldPassword})
+	require.NoError(t, err)
+
+	// --- Test Execution: Wrong current password ---
+	err = userService.ChangePassword(ctx, &dto.ChangePasswordRequest{ID: user.ID, CurrentPassword: "WrongPassword1", NewPassword: "NewPassword1"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials))
+
+	// --- Test Execution: Weak new password ---
+	err = userService.ChangePassword(ctx, &dto.ChangePasswordRequest{ID: user.ID, CurrentPassword: oldPassword, NewPassword: "weak"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrWeakPassword))
+
+	// --- Test Execution: Unknown user ---
+	err = userService.ChangePassword(ctx, &dto.ChangePasswordRequest{ID: uuid.New(), CurrentPassword: oldPassword, NewPassword: "NewPassword1"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrNotFound))
+
+	// Failed attempts leave existing sessions alone
+	exists, err := redisClient.Exists(ctx, services.RedisRefreshTokenPrefix+refreshToken1).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+
+	// --- Test Execution: Successful change ---
+	newPassword := "NewPassword1"
+	err = userService.ChangePassword(ctx, &dto.ChangePasswordRequest{ID: user.ID, CurrentPassword: oldPassword, NewPassword: newPassword})
+	require.NoError(t, err)
+
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: oldPassword})
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials), "Old password should no longer work")
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: newPassword})
+	require.NoError(t, err, "Login should succeed with the new password")
+
+	// Every refresh token issued before the change is revoked; other users keep theirs
+	for _, token := range []string{refreshToken1, refreshToken2} {
+		_, _, err = userService.Refresh(ctx, &dto.RefreshRequest{RefreshToken: token})
+		assert.True(t, errors.Is(err, services.ErrInvalidCredentials), "Refresh token should be revoked")
+	}
+	_, _, err = userService.Refresh(ctx, &dto.RefreshRequest{RefreshToken: otherRefreshToken})
+	require.NoError(t, err, "Other users' sessions must survive")
+}
+
+// TestUserService_Integration_EmailVerification tests registration-triggered verification and the login gate.
+func TestUserService_Integration_EmailVerification(t *testing.T) {
+	pool, redisClient := getTestClients(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	ctx := context.Background()
+	recMailer := &recordingMailer{}
+	// Gate login on verification for this test only
+	userService := services.NewUserService(redisClient, "", testJwtSecret, testJwtExpiration, testRefreshTokenExpiration, testImpersonationExpiration, pool, recMailer, true, password.DefaultPolicy(), hasher.NewBcrypt(bcrypt.DefaultCost), 0, 0, cache.Config{})
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	// --- Setup: Register sends a verification email ---
+	user, err := userService.Register(ctx, &dto.CreateUserRequest{Email: "verify@test.com", Name: "Verify User", Password: "Password123"})
+	require.NoError(t, err)
+	assert.False(t, user.Verified)
+	assert.Equal(t, user.Email, recMailer.lastTo)
+
+	keys, err := redisClient.Keys(ctx, services.RedisEmailVerificationPrefix+"*").Result()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	token := strings.TrimPrefix(keys[0], services.RedisEmailVerificationPrefix)
+	assert.Contains(t, recMailer.lastBody, token)
+
+	// --- Test Execution: Unverified login is rejected ---
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "Password123"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrEmailNotVerified))
+
+	// Wrong password still reports invalid credentials, not the verification state
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "wrongPassword"})
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials))
+
+	// --- Test Execution: Verify email ---
+	verifiedUser, err := userService.VerifyEmail(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, verifiedUser.Verified)
+
+	_, _, _, err = userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "Password123"})
+	require.NoError(t, err, "Login should succeed once verified")
+
+	// --- Test Execution: Token is single-use ---
+	_, err = userService.VerifyEmail(ctx, token)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, services.ErrInvalidCredentials))
+
+	// --- Test Execution: Resend is a no-op for verified users ---
+	recMailer.lastTo = ""
+	err = userService.SendVerificationEmail(ctx, &dto.SendVerificationEmailRequest{Email: user.Email})
+	require.NoError(t, err)
+	assert.Empty(t, recMailer.lastTo)
+}
+
+// TestUserService_Integration_RoleClaim tests that access tokens carry the user's role, including seeded admins.
+func TestUserService_Integration_RoleClaim(t *testing.T) {
+	ctx, userService, pool, redisClient := setupUserServiceIntegrationTest(t)
+	if redisClient == nil {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set or connection failed")
+	}
+	defer cleanupTables(t, pool, "users")
+	defer cleanupRedis(t, redisClient)
+
+	parseRole := func(accessToken string) string {
+		claims := &auth.AccessTokenClaims{}
+		_, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(testJwtSecret), nil
+		})
+		require.NoError(t, err)
+		return claims.Role
+	}
+
+	// --- Setup: Create a regular user ---
+	user := createTestUser(t, ctx, pool, "role@test.com", "Role User")
+	assert.Equal(t, models.UserRoleUser, user.Role)
+
+	// --- Test Execution: Regular user token ---
+	_, accessToken, refreshToken, err := userService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, string(models.UserRoleUser), parseRole(accessToken))
+
+	// --- Test Execution: Seed admin, refreshed token picks up the new role ---
+	err = database.SeedAdmin(ctx, pool, user.Email)
+	require.NoError(t, err)
+
+	newAccessToken, _, err := userService.Refresh(ctx, &dto.RefreshRequest{RefreshToken: refreshToken})
+	require.NoError(t, err)
+	assert.Equal(t, string(models.UserRoleAdmin), parseRole(newAccessToken))
+
+	// Seeding an unknown email is a no-op
+	err = database.SeedAdmin(ctx, pool, "nosuchuser@test.com")
+	require.NoError(t, err)
+}
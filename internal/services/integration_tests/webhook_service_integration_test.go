@@ -0,0 +1,145 @@
+package integration_tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/services"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Test Setup ---
+
+// setupWebhookServiceIntegrationTest initializes the service with a real DB pool.
+func setupWebhookServiceIntegrationTest(t *testing.T) (context.Context, services.WebhookService, *pgxpool.Pool) {
+	t.Helper()
+	pool, _ := getTestClients(t)
+	webhookService := services.NewWebhookService(pool)
+	ctx := context.Background()
+	return ctx, webhookService, pool
+}
+
+// --- Test Cases ---
+
+func TestWebhookService_Integration_Lifecycle(t *testing.T) {
+	ctx, webhookService, pool := setupWebhookServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "users", "webhooks", "webhook_deliveries")
+
+	admin := createTestUser(t, ctx, pool, "webhook-admin@test.com", "Webhook Admin")
+
+	_, _, err := webhookService.CreateWebhook(ctx, &dto.CreateWebhookRequest{Owner: "crm", URL: "ftp://crm.example.com/hook", EventTypes: []string{models.OutboxEventJobStateChanged}})
+	assert.True(t, errors.Is(err, services.ErrValidation), "Only http and https URLs can be registered")
+
+	webhook, secret, err := webhookService.CreateWebhook(ctx, &dto.CreateWebhookRequest{
+		Owner:      "crm",
+		URL:        "https://crm.example.com/hook",
+		EventTypes: []string{models.OutboxEventJobStateChanged, models.OutboxEventInvoiceStateChanged},
+		CreatedBy:  admin.ID,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "whsec_"))
+	assert.Equal(t, secret, webhook.Secret)
+	assert.True(t, webhook.Active)
+	require.NotNil(t, webhook.CreatedBy)
+	assert.Equal(t, admin.ID, *webhook.CreatedBy)
+
+	listed, err := webhookService.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, webhook.ID, listed[0].ID)
+	assert.Equal(t, []string{models.OutboxEventJobStateChanged, models.OutboxEventInvoiceStateChanged}, listed[0].EventTypes)
+
+	paused := false
+	updated, err := webhookService.UpdateWebhook(ctx, &dto.UpdateWebhookRequest{ID: webhook.ID, Active: &paused})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+	_, err = webhookService.UpdateWebhook(ctx, &dto.UpdateWebhookRequest{ID: uuid.New(), Active: &paused})
+	assert.True(t, errors.Is(err, services.ErrNotFound))
+
+	require.NoError(t, webhookService.DeleteWebhook(ctx, &dto.DeleteWebhookRequest{ID: webhook.ID}))
+	err = webhookService.DeleteWebhook(ctx, &dto.DeleteWebhookRequest{ID: webhook.ID})
+	assert.True(t, errors.Is(err, services.ErrNotFound))
+	listed, err = webhookService.ListWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestWebhookService_Integration_Deliveries(t *testing.T) {
+	ctx, webhookService, pool := setupWebhookServiceIntegrationTest(t)
+	defer cleanupTables(t, pool, "webhooks", "webhook_deliveries")
+	repo := postgres.NewWebhookRepo(pool)
+
+	invoices, _, err := webhookService.CreateWebhook(ctx, &dto.CreateWebhookRequest{Owner: "billing", URL: "https://billing.example.com/hook", EventTypes: []string{models.OutboxEventInvoiceStateChanged}})
+	require.NoError(t, err)
+	jobs, _, err := webhookService.CreateWebhook(ctx, &dto.CreateWebhookRequest{Owner: "crm", URL: "https://crm.example.com/hook", EventTypes: []string{models.OutboxEventJobStateChanged}})
+	require.NoError(t, err)
+	paused, _, err := webhookService.CreateWebhook(ctx, &dto.CreateWebhookRequest{Owner: "paused", URL: "https://paused.example.com/hook", EventTypes: []string{models.OutboxEventInvoiceStateChanged}})
+	require.NoError(t, err)
+	inactive := false
+	_, err = webhookService.UpdateWebhook(ctx, &dto.UpdateWebhookRequest{ID: paused.ID, Active: &inactive})
+	require.NoError(t, err)
+
+	// Only the active webhook subscribed to the event gets a delivery, and only once
+	event := models.OutboxEvent{ID: 1001, AggregateType: models.OutboxAggregateInvoice, AggregateID: uuid.New(), EventType: models.OutboxEventInvoiceStateChanged}
+	queued, err := repo.EnqueueDeliveries(ctx, event, []byte(`{"id":1001}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+	queued, err = repo.EnqueueDeliveries(ctx, event, []byte(`{"id":1001}`))
+	require.NoError(t, err)
+	assert.Zero(t, queued, "An event handed over again must not be delivered twice")
+
+	// A claimed delivery isn't handed out again while its lease runs
+	claimed, err := repo.ClaimDueDeliveries(ctx, &dto.ClaimWebhookDeliveriesRequest{Limit: 10, Lease: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, invoices.ID, claimed[0].Delivery.WebhookID)
+	assert.Equal(t, invoices.URL, claimed[0].URL)
+	assert.Equal(t, invoices.Secret, claimed[0].Secret)
+	assert.JSONEq(t, `{"id":1001}`, string(claimed[0].Delivery.Payload))
+	again, err := repo.ClaimDueDeliveries(ctx, &dto.ClaimWebhookDeliveriesRequest{Limit: 10, Lease: time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	// A failed attempt is counted and the delivery waits for its next attempt
+	status, message := http.StatusBadGateway, "endpoint answered 502 Bad Gateway"
+	require.NoError(t, repo.RecordDeliveryAttempt(ctx, &dto.RecordWebhookAttemptRequest{
+		ID: claimed[0].Delivery.ID, Status: models.WebhookDeliveryPending, StatusCode: &status, Error: &message, NextAttemptAt: time.Now().Add(time.Hour),
+	}))
+	pending := models.WebhookDeliveryPending
+	deliveries, total, err := webhookService.ListWebhookDeliveries(ctx, &dto.ListWebhookDeliveriesRequest{WebhookID: invoices.ID, Status: &pending, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].LastStatusCode)
+	assert.Equal(t, http.StatusBadGateway, *deliveries[0].LastStatusCode)
+	assert.True(t, deliveries[0].NextAttemptAt.After(time.Now().Add(50*time.Minute)))
+
+	// Delivered on the next attempt
+	ok := http.StatusOK
+	require.NoError(t, repo.RecordDeliveryAttempt(ctx, &dto.RecordWebhookAttemptRequest{ID: claimed[0].Delivery.ID, Status: models.WebhookDeliveryDelivered, StatusCode: &ok}))
+	deliveries, total, err = webhookService.ListWebhookDeliveries(ctx, &dto.ListWebhookDeliveriesRequest{WebhookID: invoices.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, models.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+	assert.Nil(t, deliveries[0].LastError)
+
+	_, total, err = webhookService.ListWebhookDeliveries(ctx, &dto.ListWebhookDeliveriesRequest{WebhookID: jobs.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, _, err = webhookService.ListWebhookDeliveries(ctx, &dto.ListWebhookDeliveriesRequest{WebhookID: uuid.New(), Limit: 10})
+	assert.True(t, errors.Is(err, services.ErrNotFound))
+}
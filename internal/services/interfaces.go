@@ -2,8 +2,12 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 	"go-api-template/internal/models"
 	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=../mocks/mock_services.go -package=mocks
@@ -11,44 +15,120 @@ import (
 // UserService defines the interface for user-related business logic.
 type UserService interface {
 	Register(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error)
+	RegisterOrGet(ctx context.Context, req *dto.CreateUserRequest) (*models.User, bool, error) // Returns the existing user, and false, if the email and password match an account
 	Login(ctx context.Context, req *dto.LoginRequest) (*models.User, string, string, error) // Returns user and token
-	GetAll(ctx context.Context) ([]models.User, error)
+	GetAll(ctx context.Context) ([]models.User, error) // Deprecated: capped at dto.DefaultMaxPageLimit users; use ListUsers
+	ListUsers(ctx context.Context, req *dto.ListUsersRequest) ([]models.User, int, error)
 	GetByID(ctx context.Context, req *dto.GetUserByIdRequest) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) // In request order, unknown IDs omitted
+	SharesActiveJob(ctx context.Context, userID, otherID uuid.UUID) (bool, error) // Employer and contractor of the same Ongoing job
+	GetPermissions(ctx context.Context, userID uuid.UUID) (*models.UserPermissions, error) // Role and relationship capabilities
 	GetByEmail(ctx context.Context, req *dto.GetUserByEmailRequest) (*models.User, error)
 	Update(ctx context.Context, req *dto.UpdateUserRequest) (*models.User, error)
 	Delete(ctx context.Context, req *dto.DeleteUserRequest) error
+	RestoreUser(ctx context.Context, req *dto.RestoreUserRequest) (*models.User, error) // Admin only; undoes a soft delete
+	ImpersonateUser(ctx context.Context, req *dto.ImpersonateUserRequest) (*models.User, string, time.Time, error) // Admin only; returns the user, a short-lived access token and its expiry
 	Refresh(ctx context.Context, req *dto.RefreshRequest) (string, string, error)
 	Logout(ctx context.Context, req *dto.LogoutRequest) error
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error // Blacklists an access token by its jti until its exp
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) // Used by the auth middleware
+	RequestPasswordReset(ctx context.Context, req *dto.PasswordResetRequest) error // Succeeds even for unknown emails
+	ConfirmPasswordReset(ctx context.Context, req *dto.ConfirmPasswordResetRequest) error
+	ChangePassword(ctx context.Context, req *dto.ChangePasswordRequest) error // Also signs the user out of every session
+	SendVerificationEmail(ctx context.Context, req *dto.SendVerificationEmailRequest) error // No-op for unknown or already verified emails
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
 }
 
 // JobService defines the interface for job-related business logic.
 type JobService interface {
 	CreateJob(ctx context.Context, req *dto.CreateJobRequest) (*models.Job, error)
 	GetJobByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error)
-	ListAvailableJobs(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, error)
-	ListJobsByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) ([]models.Job, error)
-	ListJobsByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, error)
+	ListAvailableJobs(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, int, error)
+	ListJobsByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) ([]models.Job, int, error)
+	ListJobsByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, int, error)
 	UpdateJobDetails(ctx context.Context, req *dto.UpdateJobDetailsRequest) (*models.Job, error)
 	UpdateJobState(ctx context.Context, req *dto.UpdateJobStateRequest) (*models.Job, error)
+	GetJobStateMachine(ctx context.Context) *models.JobStateMachine // The rules UpdateJobState enforces
+	ReopenJob(ctx context.Context, req *dto.ReopenJobRequest) (*models.Job, error) // Complete back to Ongoing; employer only
+	CancelJob(ctx context.Context, req *dto.CancelJobRequest) (*models.Job, *models.Invoice, error) // Ongoing to Cancelled, unassigning the contractor; employer only. The invoice is the optional final one
+	TransferOwnership(ctx context.Context, req *dto.TransferJobRequest) (*models.Job, error) // Current employer only; before a contractor is assigned
+	ReassignContractor(ctx context.Context, req *dto.ReassignContractorRequest) (*models.Job, error) // Swaps the contractor of an Ongoing job, which stays Ongoing; employer only
+	SetAutoInvoice(ctx context.Context, req *dto.SetJobAutoInvoiceRequest) (*models.Job, error) // Employer or contractor; not once the job is Complete
 	DeleteJob(ctx context.Context, req *dto.DeleteJobRequest) error
+	CanDeleteJob(ctx context.Context, req *dto.DeleteJobRequest) ([]models.JobDeleteBlocker, error) // Dry run of DeleteJob; empty means deletable
+	RestoreJob(ctx context.Context, req *dto.RestoreJobRequest) (*models.Job, error) // Admin only; undoes a soft delete
+	ArchiveStaleJobs(ctx context.Context, req *dto.ArchiveStaleJobsRequest) (int, error) // Background sweep; returns how many were archived
+	PurgeArchivedJobs(ctx context.Context, req *dto.PurgeArchivedJobsRequest) (*dto.PurgeArchivedJobsResult, error) // Background retention purge; deletes for good
+	GetEmployerStats(ctx context.Context, req *dto.EmployerStatsRequest) (*models.EmployerStats, error)
+	SaveJob(ctx context.Context, req *dto.SaveJobRequest) error     // Idempotent
+	UnsaveJob(ctx context.Context, req *dto.UnsaveJobRequest) error // Idempotent
+	ListSavedJobs(ctx context.Context, req *dto.ListSavedJobsRequest) ([]models.Job, int, error)
 }
 
 // InvoiceService defines the interface for invoice-related business logic.
 type InvoiceService interface {
 	CreateInvoice(ctx context.Context, req *dto.CreateInvoiceRequest) (*models.Invoice, error)
+	GenerateAllInvoices(ctx context.Context, req *dto.GenerateAllInvoicesRequest) ([]models.Invoice, error)
+	GenerateDueAutoInvoices(ctx context.Context, req *dto.GenerateDueAutoInvoicesRequest) (int, error) // Background sweep; returns how many invoices were created
 	GetInvoiceByID(ctx context.Context, req *dto.GetInvoiceByIDRequest) (*models.Invoice, error)
 	UpdateInvoiceState(ctx context.Context, req *dto.UpdateInvoiceStateRequest) (*models.Invoice, error)
+	MarkInvoicePaid(ctx context.Context, req *dto.MarkInvoicePaidRequest) (*models.Invoice, error)
 	DeleteInvoice(ctx context.Context, req *dto.DeleteInvoiceRequest) error
-	ListInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, error)
+	ListInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, int, error)
+	StreamInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest, fn func(invoice *models.Invoice) error) error // Every match, one at a time; for exports
+	ListEmployerInvoices(ctx context.Context, req *dto.ListEmployerInvoicesRequest) ([]models.Invoice, int, error) // Invoices on all of the employer's jobs
+	StreamEmployerInvoices(ctx context.Context, req *dto.ListEmployerInvoicesRequest, fn func(invoice *models.Invoice) error) error
+	AuthorizeInvoiceStream(ctx context.Context, req *dto.StreamJobInvoicesRequest) error // The job's employer and assigned contractor only
+	GetJobBalance(ctx context.Context, req *dto.GetJobBalanceRequest) (*models.JobBalance, error)
+	ListOverdueInvoices(ctx context.Context, employerID uuid.UUID) ([]models.Invoice, error) // Complete but unpaid past their due date
+}
+
+// RatingService defines the interface for rating business logic.
+type RatingService interface {
+	SubmitRating(ctx context.Context, req *dto.SubmitRatingRequest) (*models.Rating, error) // Employer of a completed job only; once per job
+	GetUserAverageRating(ctx context.Context, req *dto.GetUserRatingRequest) (*models.UserRatingSummary, error)
+}
+
+// DashboardService defines the interface for the aggregated views of a user's activity.
+type DashboardService interface {
+	GetContractorDashboard(ctx context.Context, req *dto.ContractorDashboardRequest) (*models.ContractorDashboard, error) // Degrades to a partial dashboard when a section fails
+}
+
+// AvatarService defines the interface for managing users' profile pictures.
+type AvatarService interface {
+	SetAvatar(ctx context.Context, req *dto.UploadAvatarRequest) (*models.User, error) // Replaces and deletes any previous avatar
+	DeleteAvatar(ctx context.Context, req *dto.DeleteAvatarRequest) (*models.User, error)
+}
+
+// APIKeyService defines the interface for minting and checking machine client API keys.
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, req *dto.CreateAPIKeyRequest) (*models.APIKey, string, error) // Also returns the key itself, which is never stored
+	RevokeAPIKey(ctx context.Context, req *dto.RevokeAPIKeyRequest) (*models.APIKey, error)
+	LookupAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) // nil for unknown keys; revoked keys come back with RevokedAt set
+}
+
+// WebhookService defines the interface for managing the webhooks external integrations subscribe with.
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*models.Webhook, string, error) // Also returns the signing secret, which is only shown once
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	UpdateWebhook(ctx context.Context, req *dto.UpdateWebhookRequest) (*models.Webhook, error) // Pauses or resumes deliveries
+	DeleteWebhook(ctx context.Context, req *dto.DeleteWebhookRequest) error
+	ListWebhookDeliveries(ctx context.Context, req *dto.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, int, error)
 }
 
 // JobApplicationService defines the interface for job application business logic.
 type JobApplicationService interface {
 	ApplyToJob(ctx context.Context, req *dto.ApplyToJobRequest) (*models.JobApplication, error)
 	GetApplicationByID(ctx context.Context, req *dto.GetJobApplicationByIDRequest) (*models.JobApplication, error)
-	ListApplicationsByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) ([]models.JobApplication, error)
-	ListApplicationsByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) ([]models.JobApplication, error)
+	ListApplicationsByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) ([]models.JobApplication, int, error)
+	ListAppliedJobs(ctx context.Context, req *dto.ListAppliedJobsRequest) ([]models.AppliedJob, int, error) // Jobs with the contractor's application inline
+	ListApplicationsByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) ([]models.JobApplication, int, error)
+	ExportApplicationsCSV(ctx context.Context, req *dto.ExportJobApplicationsCSVRequest, w io.Writer) error // Streams CSV rows to w
 	AcceptApplication(ctx context.Context, req *dto.AcceptApplicationRequest) (*models.Job, error) // Returns the updated Job
 	RejectApplication(ctx context.Context, req *dto.RejectApplicationRequest) (*models.JobApplication, error)
+	BulkRejectApplications(ctx context.Context, req *dto.BulkRejectRequest) ([]dto.BulkRejectResult, error) // One result per distinct ID, in request order
 	WithdrawApplication(ctx context.Context, req *dto.WithdrawApplicationRequest) (*models.JobApplication, error)
+	InviteContractor(ctx context.Context, req *dto.InviteContractorRequest) (*models.JobApplication, error) // Creates an Invited application
+	AcceptInvitation(ctx context.Context, req *dto.RespondToInvitationRequest) (*models.Job, error)         // Returns the updated Job
+	DeclineInvitation(ctx context.Context, req *dto.RespondToInvitationRequest) (*models.JobApplication, error)
 }
@@ -4,11 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"go-api-template/internal/models"
+	"go-api-template/internal/notifications"
 	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres"
 	"go-api-template/internal/transport/dto"
-	"log"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/money"
+	"go-api-template/pkg/tracing"
+
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -17,39 +24,127 @@ import (
 type invoiceService struct {
 	invoiceRepo storage.InvoiceRepository
 	jobRepo storage.JobRepository
+	outboxRepo  storage.OutboxRepository
 	db          *pgxpool.Pool
+	events      notifications.JobEventPublisher // Streams state changes to everyone watching the job
+	paymentTermDays int // Invoices are due this many days after creation
+	maxAdjustmentPercent float64 // Largest change an adjustment may make to the base value, in percent
 }
 
-func NewInvoiceService(db *pgxpool.Pool) InvoiceService {
+// NewInvoiceService creates an InvoiceService. New invoices are due paymentTermDays after creation;
+// a negative value uses models.DefaultInvoicePaymentTermDays. Adjustments may change an invoice's base
+// value by at most maxAdjustmentPercent; a negative value uses models.DefaultInvoiceMaxAdjustmentPercent.
+// State changes are published to events.
+func NewInvoiceService(db *pgxpool.Pool, events notifications.JobEventPublisher, paymentTermDays int, maxAdjustmentPercent float64, readCache cache.Config) InvoiceService {
+	if paymentTermDays < 0 {
+		paymentTermDays = models.DefaultInvoicePaymentTermDays
+	}
+	if maxAdjustmentPercent < 0 {
+		maxAdjustmentPercent = models.DefaultInvoiceMaxAdjustmentPercent
+	}
 	return &invoiceService{
 		invoiceRepo: postgres.NewInvoiceRepo(db),
-		jobRepo:     postgres.NewJobRepo(db),
+		jobRepo:     readCache.JobRepository(postgres.NewJobRepo(db)),
+		outboxRepo:  postgres.NewOutboxRepo(db),
 		db:          db,
+		events:      events,
+		paymentTermDays: paymentTermDays,
+		maxAdjustmentPercent: maxAdjustmentPercent,
+	}
+}
+
+// invoiceEventData is the payload of the invoice stream events.
+type invoiceEventData struct {
+	InvoiceID     uuid.UUID           `json:"invoice_id"`
+	JobID         uuid.UUID           `json:"job_id"`
+	InvoiceNumber string              `json:"invoice_number"`
+	State         models.InvoiceState `json:"state"`
+	Value         float64             `json:"value"`
+	Currency      string              `json:"currency"`
+}
+
+// publishStateChange tells everyone watching the invoice's job about its new state.
+// It runs after the change is committed, so failures are logged rather than returned.
+func (s *invoiceService) publishStateChange(ctx context.Context, invoice *models.Invoice) {
+	event := notifications.Event{
+		Type: notifications.EventInvoiceStateChanged,
+		Data: invoiceEventData{
+			InvoiceID:     invoice.ID,
+			JobID:         invoice.JobID,
+			InvoiceNumber: invoice.InvoiceNumber,
+			State:         invoice.State,
+			Value:         invoice.Value,
+			Currency:      invoice.Currency,
+		},
+	}
+	if err := s.events.PublishJobEvent(ctx, invoice.JobID, event); err != nil {
+		logger.FromContext(ctx).Warn("Failed to publish invoice state change", "invoice_id", invoice.ID, "job_id", invoice.JobID, "error", err)
+	}
+}
+
+// nextInvoiceNumber takes the employer's next invoice number, as INV-<employer sequence>-<year>.
+// It must run in the transaction creating the invoice, so that the number is only used once the
+// invoice is committed.
+func nextInvoiceNumber(ctx context.Context, txInvoiceRepo storage.InvoiceRepository, employerID uuid.UUID) (string, error) {
+	seq, err := txInvoiceRepo.NextInvoiceSequence(ctx, &dto.NextInvoiceSequenceRequest{EmployerID: employerID})
+	if err != nil {
+		return "", mapRepoError(err, "taking next invoice number")
+	}
+	return formatInvoiceNumber(seq, time.Now().UTC().Year()), nil
+}
+
+// formatInvoiceNumber renders an employer sequence number and year as an invoice number.
+func formatInvoiceNumber(seq int64, year int) string {
+	return fmt.Sprintf("INV-%06d-%d", seq, year)
+}
+
+// adjustedValue applies an optional adjustment to an invoice's base value and rounds the result to the
+// minor units of currency. The result must not be negative and the adjustment must not change the base
+// by more than the configured percentage, which catches mistyped amounts.
+func (s *invoiceService) adjustedValue(baseValue float64, adjustment *float64, currency string) (float64, error) {
+	if adjustment == nil {
+		return money.Round(baseValue, currency), nil
+	}
+	value := baseValue + *adjustment
+	if value < 0 {
+		return 0, fmt.Errorf("%w: adjustment of %s would make the invoice value negative (base value %s)", ErrInvalidAdjustment, money.Format(*adjustment, currency), money.Format(baseValue, currency))
 	}
+	if limit := baseValue * s.maxAdjustmentPercent / 100; math.Abs(*adjustment) > limit {
+		return 0, fmt.Errorf("%w: adjustment of %s exceeds %g%% of the base value %s", ErrInvalidAdjustment, money.Format(*adjustment, currency), s.maxAdjustmentPercent, money.Format(baseValue, currency))
+	}
+	return money.Round(value, currency), nil
+}
+
+// dueDate returns the due date of an invoice created now.
+func (s *invoiceService) dueDate() time.Time {
+	return time.Now().AddDate(0, 0, s.paymentTermDays)
 }
 
 func (s *invoiceService) CreateInvoice(ctx context.Context, req *dto.CreateInvoiceRequest) (*models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.CreateInvoice")
+	defer span.End()
+
 	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
 	job, err := s.jobRepo.GetByID(ctx, &jobReq)
 	if err != nil {
-		log.Printf("CreateInvoice: Error fetching job %s: %v", req.JobID, err)
+		logger.FromContext(ctx).Error("CreateInvoice: Error fetching job", "job_id", req.JobID, "error", err)
 		return nil, mapRepoError(err, "fetching job for invoice creation")
 	}
 
-	// Authorization & State checks
-	if job.ContractorID == nil || *job.ContractorID != req.UserId {
-		log.Printf("CreateInvoice: Forbidden attempt by user %s on job %s (Contractor: %v)", req.UserId, req.JobID, job.ContractorID)
+	// Authorization & State checks; scheduled invoicing acts as the system rather than a user
+	if !isSystem(ctx) && (job.ContractorID == nil || *job.ContractorID != req.UserId) {
+		logger.FromContext(ctx).Warn("CreateInvoice: Forbidden attempt", "user_id", req.UserId, "job_id", req.JobID, "contractor_id", job.ContractorID)
 		return nil, ErrForbidden
 	}
 	if job.State != models.JobStateOngoing {
-		log.Printf("CreateInvoice: Attempt to create invoice for job %s in state %s", req.JobID, job.State)
+		logger.FromContext(ctx).Warn("CreateInvoice: Attempt to create invoice for job in invalid state", "job_id", req.JobID, "job_state", job.State)
 		return nil, ErrInvalidState // Correct error type
 	}
 
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("CreateInvoice: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("CreateInvoice: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
@@ -62,68 +157,272 @@ func (s *invoiceService) CreateInvoice(ctx context.Context, req *dto.CreateInvoi
 	}
 	nextIntervalNumber := maxIntervalNum + 1
 
-	if job.InvoiceInterval <= 0 {
-		return nil, ErrInvalidInvoiceInterval
+	// A client naming the interval it bills makes the create replay-safe: a repeated or concurrent
+	// request for the same interval fails here, or on the (job_id, interval_number) unique constraint
+	// if the other transaction hasn't committed yet, instead of silently billing the following interval.
+	if req.IntervalNumber != nil && *req.IntervalNumber != nextIntervalNumber {
+		if *req.IntervalNumber < nextIntervalNumber {
+			logger.FromContext(ctx).Warn("CreateInvoice: Interval already invoiced", "job_id", req.JobID, "interval_number", *req.IntervalNumber)
+			return nil, fmt.Errorf("%w: interval %d is already invoiced", ErrConflict, *req.IntervalNumber)
+		}
+		return nil, fmt.Errorf("%w: interval %d can't be invoiced before interval %d", ErrValidation, *req.IntervalNumber, nextIntervalNumber)
 	}
 
-	maxPossibleIntervals := job.Duration / job.InvoiceInterval
-	remainderHours := job.Duration % job.InvoiceInterval
-	isPartialLastInterval := remainderHours != 0
-	if isPartialLastInterval {
-		maxPossibleIntervals++
+	if job.InvoiceInterval <= 0 {
+		return nil, ErrInvalidInvoiceInterval
 	}
 
+	maxPossibleIntervals := maxInvoiceIntervals(job)
 	if nextIntervalNumber > maxPossibleIntervals {
 		return nil, ErrInvalidInvoiceInterval
 	}
 
 	// Determine hours for this specific invoice (in case of a partial last interval)
-	var hoursForThisInterval int
-	isLastInterval := (nextIntervalNumber == maxPossibleIntervals)
-
-	if isLastInterval && isPartialLastInterval {
-		hoursForThisInterval = remainderHours
-	} else {
-		// It's either not the last interval, or the last interval is a full one
-		hoursForThisInterval = job.InvoiceInterval
-	}
+	hoursForThisInterval := intervalHours(job, nextIntervalNumber)
 
 	baseValue := job.Rate * float64(hoursForThisInterval) // Use calculated hours
-	finalValue := baseValue
-	if req.Adjustment != nil {
-		finalValue += *req.Adjustment
+	finalValue, err := s.adjustedValue(baseValue, req.Adjustment, job.Currency)
+	if err != nil {
+		logger.FromContext(ctx).Warn("CreateInvoice: Rejected adjustment", "job_id", req.JobID, "adjustment", *req.Adjustment, "base_value", baseValue)
+		return nil, err
 	}
-	if finalValue < 0 { // Ensure non-negative value
-		finalValue = 0
+
+	invoiceNumber, err := nextInvoiceNumber(ctx, txInvoiceRepo, job.EmployerID)
+	if err != nil {
+		return nil, err
 	}
 
+	createdBy := actorID(ctx, req.UserId)
 	invoiceToCreate := &models.Invoice{
 		JobID:          req.JobID,
 		IntervalNumber: nextIntervalNumber,
+		InvoiceNumber:  invoiceNumber,
+		ContractorID:   job.ContractorID,
 		Value:          finalValue,
+		Currency:       job.Currency,
 		State:          models.InvoiceStateWaiting,
+		DueDate:        s.dueDate(),
+		CreatedBy:      &createdBy,
 		ID:			 uuid.New(), // Generate a new UUID for the invoice
 	}
 
 	invoice, err := txInvoiceRepo.Create(ctx, invoiceToCreate) // Use txInvoiceRepo
 	if err != nil {
 		if errors.Is(err, storage.ErrConflict) {
-			return nil, ErrConflict
+			return nil, fmt.Errorf("%w: %w", ErrConflict, err)
 		}
-		log.Printf("CreateInvoice: Error saving invoice in repo: %v", err)
+		logger.FromContext(ctx).Error("CreateInvoice: Error saving invoice in repo", "error", err)
 		return nil, fmt.Errorf("internal error saving invoice: %w", err)
 	}
 
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateInvoice, invoice.ID, models.OutboxEventInvoiceCreated, invoice); err != nil {
+		logger.FromContext(ctx).Error("CreateInvoice: Error recording outbox event", "invoice_id", invoice.ID, "error", err)
+		return nil, err
+	}
+
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("CreateInvoice: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("CreateInvoice: Error committing transaction", "error", err)
 		return nil, mapRepoError(err, "committing invoice creation")
 	}
 	// --- End Transaction ---
+	InvoicesCreatedTotal.Inc()
 	return invoice, nil
 }
 
+// GenerateDueAutoInvoices creates the next interval invoice of up to req.Limit jobs whose automatic
+// invoice is due, through CreateInvoice with a system identity. Each job's next due time is moved on
+// by its cadence before invoicing, so instances sweeping concurrently never invoice a job twice. A job
+// that fails is logged and skipped, and a job whose every interval is invoiced stops being scheduled.
+func (s *invoiceService) GenerateDueAutoInvoices(ctx context.Context, req *dto.GenerateDueAutoInvoicesRequest) (int, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GenerateDueAutoInvoices")
+	defer span.End()
+
+	ids, err := s.jobRepo.ListDueAutoInvoiceIDs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("GenerateDueAutoInvoices: Error listing due jobs", "error", err)
+		return 0, mapRepoError(err, "listing jobs due for automatic invoicing")
+	}
+
+	created := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+		invoiced, err := s.autoInvoiceJob(ctx, id)
+		if err != nil {
+			logger.FromContext(ctx).Warn("GenerateDueAutoInvoices: Skipping job", "job_id", id, "error", err)
+			continue
+		}
+		if invoiced {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// autoInvoiceJob creates the job's next interval invoice if it is still due, and reports whether it did.
+func (s *invoiceService) autoInvoiceJob(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	job, err := s.jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: jobID})
+	if err != nil {
+		return false, mapRepoError(err, "fetching job for automatic invoicing")
+	}
+	if !job.AutoInvoice || job.NextAutoInvoiceAt == nil || job.State != models.JobStateOngoing {
+		return false, nil // Changed since it was listed
+	}
+	dueAt := *job.NextAutoInvoiceAt
+
+	maxInterval, err := s.invoiceRepo.GetMaxIntervalForJob(ctx, &dto.GetMaxIntervalForJobRequest{JobID: jobID})
+	if err != nil {
+		return false, mapRepoError(err, "getting max interval for job")
+	}
+	if maxInterval >= maxInvoiceIntervals(job) {
+		// Every interval is invoiced, so there is nothing left to schedule
+		if _, err := s.jobRepo.AdvanceAutoInvoice(ctx, &dto.AdvanceAutoInvoiceRequest{JobID: jobID, From: dueAt, UpdatedBy: models.SystemActorID}); err != nil {
+			return false, mapRepoError(err, "stopping automatic invoicing")
+		}
+		return false, nil
+	}
+
+	// Claim this due time first; whoever moves it on is the one that invoices
+	nextAt := job.AutoInvoiceCadence.Next(dueAt)
+	claimed, err := s.jobRepo.AdvanceAutoInvoice(ctx, &dto.AdvanceAutoInvoiceRequest{JobID: jobID, From: dueAt, To: &nextAt, UpdatedBy: models.SystemActorID})
+	if err != nil {
+		return false, mapRepoError(err, "scheduling next automatic invoice")
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	if _, err := s.CreateInvoice(asSystem(ctx), &dto.CreateInvoiceRequest{JobID: jobID}); err != nil {
+		// Put the due time back so the next sweep tries again
+		if _, revertErr := s.jobRepo.AdvanceAutoInvoice(ctx, &dto.AdvanceAutoInvoiceRequest{JobID: jobID, From: nextAt, To: &dueAt, UpdatedBy: models.SystemActorID}); revertErr != nil {
+			logger.FromContext(ctx).Error("GenerateDueAutoInvoices: Error restoring due time", "job_id", jobID, "due_at", dueAt, "error", revertErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GenerateAllInvoices creates an invoice for every interval of the job that doesn't have one yet,
+// in a single transaction. Intervals that were already invoiced are skipped.
+func (s *invoiceService) GenerateAllInvoices(ctx context.Context, req *dto.GenerateAllInvoicesRequest) ([]models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GenerateAllInvoices")
+	defer span.End()
+
+	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
+	job, err := s.jobRepo.GetByID(ctx, &jobReq)
+	if err != nil {
+		logger.FromContext(ctx).Error("GenerateAllInvoices: Error fetching job", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "fetching job for invoice generation")
+	}
+
+	// Same authorization & state checks as CreateInvoice
+	if job.ContractorID == nil || *job.ContractorID != req.UserId {
+		logger.FromContext(ctx).Warn("GenerateAllInvoices: Forbidden attempt", "user_id", req.UserId, "job_id", req.JobID, "contractor_id", job.ContractorID)
+		return nil, ErrForbidden
+	}
+	if job.State != models.JobStateOngoing {
+		logger.FromContext(ctx).Warn("GenerateAllInvoices: Attempt to generate invoices for job in invalid state", "job_id", req.JobID, "job_state", job.State)
+		return nil, ErrInvalidState
+	}
+	if job.InvoiceInterval <= 0 {
+		return nil, ErrInvalidInvoiceInterval
+	}
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("GenerateAllInvoices: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txInvoiceRepo := s.invoiceRepo.WithTx(tx)
+	txOutboxRepo := s.outboxRepo.WithTx(tx)
+	existingIntervals, err := txInvoiceRepo.ListIntervalNumbersForJob(ctx, &dto.ListIntervalNumbersForJobRequest{JobID: req.JobID})
+	if err != nil {
+		return nil, mapRepoError(err, "listing existing intervals for job")
+	}
+	invoiced := make(map[int]bool, len(existingIntervals))
+	for _, interval := range existingIntervals {
+		invoiced[interval] = true
+	}
+
+	createdBy := actorID(ctx, req.UserId)
+	created := []models.Invoice{}
+	for intervalNumber := 1; intervalNumber <= maxInvoiceIntervals(job); intervalNumber++ {
+		if invoiced[intervalNumber] {
+			continue
+		}
+
+		invoiceNumber, err := nextInvoiceNumber(ctx, txInvoiceRepo, job.EmployerID)
+		if err != nil {
+			return nil, err
+		}
+
+		invoiceToCreate := &models.Invoice{
+			JobID:          req.JobID,
+			IntervalNumber: intervalNumber,
+			InvoiceNumber:  invoiceNumber,
+			ContractorID:   job.ContractorID,
+			Value:          money.Round(job.Rate*float64(intervalHours(job, intervalNumber)), job.Currency),
+			Currency:       job.Currency,
+			State:          models.InvoiceStateWaiting,
+			DueDate:        s.dueDate(),
+			CreatedBy:      &createdBy,
+			ID:             uuid.New(),
+		}
+		invoice, err := txInvoiceRepo.Create(ctx, invoiceToCreate)
+		if err != nil {
+			if errors.Is(err, storage.ErrConflict) {
+				// Another request invoiced this interval after we listed the existing ones
+				return nil, fmt.Errorf("%w: %w", ErrConflict, err)
+			}
+			logger.FromContext(ctx).Error("GenerateAllInvoices: Error saving invoice in repo", "interval", intervalNumber, "error", err)
+			return nil, fmt.Errorf("internal error saving invoice: %w", err)
+		}
+		if err := recordOutboxEvent(ctx, txOutboxRepo, models.OutboxAggregateInvoice, invoice.ID, models.OutboxEventInvoiceCreated, invoice); err != nil {
+			logger.FromContext(ctx).Error("GenerateAllInvoices: Error recording outbox event", "invoice_id", invoice.ID, "error", err)
+			return nil, err
+		}
+		created = append(created, *invoice)
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("GenerateAllInvoices: Error committing transaction", "error", err)
+		return nil, mapRepoError(err, "committing invoice generation")
+	}
+	// --- End Transaction ---
+	InvoicesCreatedTotal.Add(float64(len(created)))
+	return created, nil
+}
+
+// maxInvoiceIntervals returns how many intervals the job's duration is split into, counting a
+// trailing partial interval. job.InvoiceInterval must be positive.
+func maxInvoiceIntervals(job *models.Job) int {
+	intervals := job.Duration / job.InvoiceInterval
+	if job.Duration%job.InvoiceInterval != 0 {
+		intervals++
+	}
+	return intervals
+}
+
+// intervalHours returns the billable hours for an interval; only a partial last interval is shorter
+// than job.InvoiceInterval.
+func intervalHours(job *models.Job, intervalNumber int) int {
+	remainderHours := job.Duration % job.InvoiceInterval
+	if intervalNumber == maxInvoiceIntervals(job) && remainderHours != 0 {
+		return remainderHours
+	}
+	return job.InvoiceInterval
+}
+
 func (s *invoiceService) GetInvoiceByID(ctx context.Context, req *dto.GetInvoiceByIDRequest) (*models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GetInvoiceByID")
+	defer span.End()
+
 	// Call s.invoiceRepo.GetByID
 	invoice, err := s.invoiceRepo.GetByID(ctx, req)
 	if err != nil {
@@ -137,10 +436,9 @@ func (s *invoiceService) GetInvoiceByID(ctx context.Context, req *dto.GetInvoice
 		return nil, mapRepoError(err, "getting job")
 	}
 
-	// Authorization Check: Verify UserID matches job.EmployerID or job.ContractorID.
+	// Authorization Check: Verify UserID matches job.EmployerID or the contractor who issued the invoice.
 	isEmployer := job.EmployerID == req.UserId
-	isContractor := job.ContractorID != nil && *job.ContractorID == req.UserId
-	if !(isEmployer || isContractor) {
+	if !(isEmployer || issuedBy(invoice, req.UserId)) {
 		return nil, ErrForbidden
 	}
 
@@ -148,10 +446,13 @@ func (s *invoiceService) GetInvoiceByID(ctx context.Context, req *dto.GetInvoice
 }
 
 func (s *invoiceService) UpdateInvoiceState(ctx context.Context, req *dto.UpdateInvoiceStateRequest) (*models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.UpdateInvoiceState")
+	defer span.End()
+
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("UpdateInvoiceState: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("UpdateInvoiceState: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
@@ -177,7 +478,7 @@ func (s *invoiceService) UpdateInvoiceState(ctx context.Context, req *dto.Update
 	// --- Authorization Check: ONLY Employer ---
 	isEmployer := job.EmployerID == req.UserId
 	if !isEmployer {
-		log.Printf("UpdateInvoiceState: Forbidden attempt by user %s on invoice %s (Job Employer: %v)", req.UserId, req.ID, job.EmployerID)
+		logger.FromContext(ctx).Warn("UpdateInvoiceState: Forbidden attempt", "user_id", req.UserId, "invoice_id", req.ID, "employer_id", job.EmployerID)
 		return nil, ErrForbidden
 	}
 	// --- End Auth Check ---
@@ -186,26 +487,111 @@ func (s *invoiceService) UpdateInvoiceState(ctx context.Context, req *dto.Update
 	if !isValidInvoiceStateTransition(invoice.State, req.NewState) {
 		return nil, ErrInvalidTransition
 	}
+	if req.NewState == models.InvoiceStatePaid {
+		return nil, fmt.Errorf("%w: use MarkInvoicePaid to record payment", ErrInvalidTransition)
+	}
 
 	updatedInvoice, err := txInvoiceRepo.UpdateState(ctx, req) // Use txInvoiceRepo
 	if err != nil {
 		return nil, mapRepoError(err, "updating invoice state")
 	}
 
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateInvoice, updatedInvoice.ID, models.OutboxEventInvoiceStateChanged, updatedInvoice); err != nil {
+		logger.FromContext(ctx).Error("UpdateInvoiceState: Error recording outbox event", "invoice_id", req.ID, "error", err)
+		return nil, err
+	}
+
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("UpdateInvoiceState: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("UpdateInvoiceState: Error committing transaction", "error", err)
 		return nil, fmt.Errorf("internal error committing invoice update: %w", err)
 	}
 	// --- End Transaction ---
 
+	s.publishStateChange(ctx, updatedInvoice)
+
 	// TODO: Consider if updating the Job state to Complete should happen here
 	// if all invoices are Complete and the last interval is reached.
 	// This would require another transaction or careful coordination.
 	return updatedInvoice, nil
 }
 
+// MarkInvoicePaid records that the employer has paid a Complete invoice. Payments reported by a
+// machine client (req.ByService) skip the employer check; the route has already checked its scope.
+func (s *invoiceService) MarkInvoicePaid(ctx context.Context, req *dto.MarkInvoicePaidRequest) (*models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.MarkInvoicePaid")
+	defer span.End()
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("MarkInvoicePaid: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txInvoiceRepo := s.invoiceRepo.WithTx(tx)
+	txJobRepo := s.jobRepo.WithTx(tx)
+	// --- End Transaction Setup ---
+
+	// Fetch Invoice
+	getReq := dto.GetInvoiceByIDRequest{ID: req.ID}
+	invoice, err := txInvoiceRepo.GetByID(ctx, &getReq)
+	if err != nil {
+		return nil, mapRepoError(err, "getting invoice")
+	}
+
+	// Fetch Job for Auth Check
+	jobReq := dto.GetJobByIDRequest{ID: invoice.JobID}
+	job, err := txJobRepo.GetByID(ctx, &jobReq)
+	if err != nil {
+		return nil, mapRepoError(err, "getting job")
+	}
+
+	// --- Authorization Check: ONLY Employer, or a client holding the invoices:pay scope ---
+	if !req.ByService && job.EmployerID != req.UserId {
+		logger.FromContext(ctx).Warn("MarkInvoicePaid: Forbidden attempt", "user_id", req.UserId, "invoice_id", req.ID, "employer_id", job.EmployerID)
+		return nil, ErrForbidden
+	}
+	// --- End Auth Check ---
+
+	// Check State Transition
+	if !isValidInvoiceStateTransition(invoice.State, models.InvoiceStatePaid) {
+		return nil, fmt.Errorf("%w: only Complete invoices can be paid", ErrInvalidTransition)
+	}
+
+	if req.ByService {
+		req.UserId = models.SystemActorID // Machine clients aren't users; record the payment as the API's own
+	}
+	paidInvoice, err := txInvoiceRepo.MarkPaid(ctx, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrConflict) {
+			// Paid concurrently after the read above
+			return nil, fmt.Errorf("%w: only Complete invoices can be paid", ErrInvalidTransition)
+		}
+		return nil, mapRepoError(err, "marking invoice as paid")
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateInvoice, paidInvoice.ID, models.OutboxEventInvoiceStateChanged, paidInvoice); err != nil {
+		logger.FromContext(ctx).Error("MarkInvoicePaid: Error recording outbox event", "invoice_id", req.ID, "error", err)
+		return nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("MarkInvoicePaid: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing invoice payment: %w", err)
+	}
+	// --- End Transaction ---
+
+	s.publishStateChange(ctx, paidInvoice)
+	return paidInvoice, nil
+}
+
 func (s *invoiceService) DeleteInvoice(ctx context.Context, req *dto.DeleteInvoiceRequest) error {
+	ctx, span := tracing.Start(ctx, "InvoiceService.DeleteInvoice")
+	defer span.End()
+
 	// Fetch Invoice
 	getReq := dto.GetInvoiceByIDRequest{ID: req.ID}
 	invoice, err := s.invoiceRepo.GetByID(ctx, &getReq)
@@ -214,10 +600,9 @@ func (s *invoiceService) DeleteInvoice(ctx context.Context, req *dto.DeleteInvoi
 		return mapRepoError(err, "getting invoice for deletion check")
 	}
 
-	// Fetch Job for Auth Check
+	// Invoices of deleted jobs can't be deleted on their own
 	jobReq := dto.GetJobByIDRequest{ID: invoice.JobID}
-	job, err := s.jobRepo.GetByID(ctx, &jobReq)
-	if err != nil {
+	if _, err := s.jobRepo.GetByID(ctx, &jobReq); err != nil {
 		return mapRepoError(err, "getting job for deletion check")
 	}
 
@@ -228,8 +613,7 @@ func (s *invoiceService) DeleteInvoice(ctx context.Context, req *dto.DeleteInvoi
 	}
 
 	// --- Authorization Check: ONLY Contractor + State Waiting ---
-	isContractor := job.ContractorID != nil && *job.ContractorID == req.UserId
-	if !isContractor {
+	if !issuedBy(invoice, req.UserId) {
 		return ErrForbidden
 	}
 	if invoice.State != models.InvoiceStateWaiting {
@@ -246,21 +630,186 @@ func (s *invoiceService) DeleteInvoice(ctx context.Context, req *dto.DeleteInvoi
 		return mapRepoError(err, "deleting invoice")
 	}
 
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateInvoice, invoice.ID, models.OutboxEventInvoiceDeleted, invoice); err != nil {
+		tx.Rollback(ctx)
+		logger.FromContext(ctx).Error("DeleteInvoice: Error recording outbox event", "invoice_id", req.ID, "error", err)
+		return err
+	}
+
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("DeleteInvoice: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("DeleteInvoice: Error committing transaction", "error", err)
 		return fmt.Errorf("internal error committing invoice deletion: %w", err)
 	}
 
 	return nil
 }
 
-func (s *invoiceService) ListInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, error) {
+func (s *invoiceService) ListInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, int, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.ListInvoicesByJob")
+	defer span.End()
+
+	if err := s.authorizeJobInvoiceList(ctx, req); err != nil {
+		return nil, 0, err
+	}
+
+	// List and count from the same snapshot so Total matches the page
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListInvoicesByJob: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txInvoiceRepo := s.invoiceRepo.WithTx(tx)
+
+	invoices, err := txInvoiceRepo.ListByJob(ctx, req)
+	if err != nil {
+		return nil, 0, mapRepoError(err, "listing invoices")
+	}
+	total, err := txInvoiceRepo.CountByJob(ctx, req)
+	if err != nil {
+		return nil, 0, mapRepoError(err, "counting invoices")
+	}
+
+	return invoices, total, nil
+}
+
+// StreamInvoicesByJob calls fn with every invoice matching the ListInvoicesByJob filters, in the same order
+// but without pagination, reading them one at a time. Access is checked before fn is first called, so an
+// error returned before then means nothing was streamed. An error from fn stops the stream and is returned.
+func (s *invoiceService) StreamInvoicesByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest, fn func(invoice *models.Invoice) error) error {
+	ctx, span := tracing.Start(ctx, "InvoiceService.StreamInvoicesByJob")
+	defer span.End()
+
+	if err := s.authorizeJobInvoiceList(ctx, req); err != nil {
+		return err
+	}
+
+	if err := s.invoiceRepo.StreamByJob(ctx, req, fn); err != nil {
+		logger.FromContext(ctx).Error("StreamInvoicesByJob: Error streaming invoices", "job_id", req.JobID, "error", err)
+		return fmt.Errorf("internal error streaming invoices for job %s: %w", req.JobID, err)
+	}
+	return nil
+}
+
+// authorizeJobInvoiceList checks the filters of a job's invoice list and that the user is the job's
+// employer, its assigned contractor or a contractor who issued some of its invoices. Contractors only
+// see the invoices they issued, so req is narrowed to those.
+func (s *invoiceService) authorizeJobInvoiceList(ctx context.Context, req *dto.ListInvoicesByJobRequest) error {
+	if req.MinInterval != nil && req.MaxInterval != nil && *req.MinInterval > *req.MaxInterval {
+		return fmt.Errorf("%w: min_interval must not be greater than max_interval", ErrValidation)
+	}
+
 	// Fetch Job using s.jobRepo.GetByID(JobID) to verify existence and for auth check.
 	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
 	job, err := s.jobRepo.GetByID(ctx, &jobReq)
 	if err != nil {
-		return nil, mapRepoError(err, "getting job for listing invoices")
+		return mapRepoError(err, "getting job for listing invoices")
+	}
+
+	// Authorization Check: Verify UserID matches job.EmployerID, job.ContractorID or an invoice's contractor.
+	if job.EmployerID == req.UserId {
+		req.ContractorID = nil
+		return nil
+	}
+	req.ContractorID = &req.UserId
+	if job.ContractorID != nil && *job.ContractorID == req.UserId {
+		return nil
+	}
+	// A contractor the job was reassigned or cancelled away from keeps access to what they invoiced
+	issued, err := s.invoiceRepo.CountByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: req.JobID, ContractorID: &req.UserId})
+	if err != nil {
+		return mapRepoError(err, "counting invoices issued by user")
+	}
+	if issued == 0 {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// issuedBy reports whether userID is the contractor who issued the invoice.
+func issuedBy(invoice *models.Invoice, userID uuid.UUID) bool {
+	return invoice.ContractorID != nil && *invoice.ContractorID == userID
+}
+
+// ListEmployerInvoices returns a page of the invoices on all of the employer's jobs, newest first, and
+// how many match in total. Invoices of deleted jobs are left out.
+func (s *invoiceService) ListEmployerInvoices(ctx context.Context, req *dto.ListEmployerInvoicesRequest) ([]models.Invoice, int, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.ListEmployerInvoices")
+	defer span.End()
+
+	// List and count from the same snapshot so Total matches the page
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListEmployerInvoices: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txInvoiceRepo := s.invoiceRepo.WithTx(tx)
+
+	invoices, err := txInvoiceRepo.ListByEmployer(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListEmployerInvoices: Error listing invoices", "employer_id", req.EmployerID, "error", err)
+		return nil, 0, mapRepoError(err, "listing employer invoices")
+	}
+	total, err := txInvoiceRepo.CountByEmployer(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListEmployerInvoices: Error counting invoices", "employer_id", req.EmployerID, "error", err)
+		return nil, 0, mapRepoError(err, "counting employer invoices")
+	}
+	return invoices, total, nil
+}
+
+// StreamEmployerInvoices calls fn with every invoice matching the ListEmployerInvoices filters, in the
+// same order but without pagination, reading them one at a time. An error from fn stops the stream and is returned.
+func (s *invoiceService) StreamEmployerInvoices(ctx context.Context, req *dto.ListEmployerInvoicesRequest, fn func(invoice *models.Invoice) error) error {
+	ctx, span := tracing.Start(ctx, "InvoiceService.StreamEmployerInvoices")
+	defer span.End()
+
+	if err := s.invoiceRepo.StreamByEmployer(ctx, req, fn); err != nil {
+		logger.FromContext(ctx).Error("StreamEmployerInvoices: Error streaming invoices", "employer_id", req.EmployerID, "error", err)
+		return fmt.Errorf("internal error streaming employer invoices: %w", err)
+	}
+	return nil
+}
+
+// AuthorizeInvoiceStream checks that the user may watch the job's invoices: only the employer and the
+// assigned contractor may, as the stream carries the changes of every invoice on the job.
+func (s *invoiceService) AuthorizeInvoiceStream(ctx context.Context, req *dto.StreamJobInvoicesRequest) error {
+	ctx, span := tracing.Start(ctx, "InvoiceService.AuthorizeInvoiceStream")
+	defer span.End()
+
+	job, err := s.jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		return mapRepoError(err, "getting job for invoice stream")
+	}
+
+	isEmployer := job.EmployerID == req.UserID
+	isContractor := job.ContractorID != nil && *job.ContractorID == req.UserID
+	if !(isEmployer || isContractor) {
+		logger.FromContext(ctx).Warn("AuthorizeInvoiceStream: Forbidden attempt", "user_id", req.UserID, "job_id", req.JobID)
+		return ErrForbidden
+	}
+	return nil
+}
+
+// GetJobBalance returns how much of a job has been invoiced and paid, and how much is left to invoice.
+func (s *invoiceService) GetJobBalance(ctx context.Context, req *dto.GetJobBalanceRequest) (*models.JobBalance, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GetJobBalance")
+	defer span.End()
+
+	// Read the job and its invoice sums from the same snapshot
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("GetJobBalance: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+
+	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
+	job, err := s.jobRepo.WithTx(tx).GetByID(ctx, &jobReq)
+	if err != nil {
+		return nil, mapRepoError(err, "getting job for balance")
 	}
 
 	// Authorization Check: Verify UserID matches job.EmployerID or job.ContractorID.
@@ -269,12 +818,33 @@ func (s *invoiceService) ListInvoicesByJob(ctx context.Context, req *dto.ListInv
 	if !(isEmployer || isContractor) {
 		return nil, ErrForbidden
 	}
-	
-	// Call s.invoiceRepo.ListByJob
-	invoices, err := s.invoiceRepo.ListByJob(ctx, req)
+
+	invoiced, paid, err := s.invoiceRepo.WithTx(tx).SumValuesByJob(ctx, req)
 	if err != nil {
-		return nil, mapRepoError(err, "listing invoices")
-	}
+		return nil, mapRepoError(err, "summing invoices")
+	}
+
+	totalValue := money.Round(job.Rate*float64(job.Duration), job.Currency)
+	return &models.JobBalance{
+		JobID:         job.ID,
+		TotalValue:    totalValue,
+		TotalInvoiced: invoiced,
+		TotalPaid:     paid,
+		Remaining:     money.Round(max(totalValue-invoiced, 0), job.Currency), // Adjusted invoices can push the invoiced total past the job value
+		Currency:      job.Currency,
+	}, nil
+}
+
+// ListOverdueInvoices returns the invoices on the employer's jobs that were accepted (Complete) but
+// are still unpaid after their due date, oldest due first.
+func (s *invoiceService) ListOverdueInvoices(ctx context.Context, employerID uuid.UUID) ([]models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.ListOverdueInvoices")
+	defer span.End()
 
+	invoices, err := s.invoiceRepo.ListOverdueByEmployer(ctx, &dto.ListOverdueInvoicesRequest{EmployerID: employerID})
+	if err != nil {
+		logger.FromContext(ctx).Error("ListOverdueInvoices: Error listing overdue invoices", "employer_id", employerID, "error", err)
+		return nil, mapRepoError(err, "listing overdue invoices")
+	}
 	return invoices, nil
-}
\ No newline at end of file
+}
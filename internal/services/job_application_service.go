@@ -2,33 +2,79 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"go-api-template/internal/models"
+	"go-api-template/internal/notifications"
 	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres"
 	"go-api-template/internal/transport/dto"
-	"log"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
+	"io"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool" // Import pgxpool for transaction handling
 )
 
 type jobApplicationService struct {
 	appRepo storage.JobApplicationRepository
 	jobRepo storage.JobRepository
+	skillRepo storage.SkillRepository
+	userRepo storage.UserRepository
 	db      *pgxpool.Pool 
+	notifier notifications.Notifier
+	maxOngoingJobs int // Default cap on a contractor's Ongoing jobs; 0 means no limit
 }
 
-// NewJobApplicationService creates a new instance of JobApplicationService.
-func NewJobApplicationService(db *pgxpool.Pool) JobApplicationService {
+// NewJobApplicationService creates a new instance of JobApplicationService. maxOngoingJobs is how many
+// Ongoing jobs a contractor may hold at once unless their user row says otherwise; 0 means no limit.
+func NewJobApplicationService(db *pgxpool.Pool, notifier notifications.Notifier, maxOngoingJobs int, readCache cache.Config) JobApplicationService {
 	return &jobApplicationService{
 		appRepo: postgres.NewJobApplicationRepo(db),
-		jobRepo: postgres.NewJobRepo(db),
+		jobRepo: readCache.JobRepository(postgres.NewJobRepo(db)),
+		skillRepo: postgres.NewSkillRepo(db),
+		userRepo: postgres.NewUserRepo(db),
 		db:      db, 
+		notifier: notifier,
+		maxOngoingJobs: maxOngoingJobs,
 	}
 }
 
-// ApplyToJob creates a new job application for a user to a specific job.
+// applicationEventData is the payload of the application notification events.
+type applicationEventData struct {
+	ApplicationID uuid.UUID                  `json:"application_id"`
+	JobID         uuid.UUID                  `json:"job_id"`
+	ContractorID  uuid.UUID                  `json:"contractor_id"`
+	State         models.JobApplicationState `json:"state"`
+}
+
+// notifyApplicationEvent tells userID about a change to an application.
+// It runs after the change is committed, so failures are logged rather than returned.
+func (s *jobApplicationService) notifyApplicationEvent(ctx context.Context, userID uuid.UUID, eventType string, app *models.JobApplication) {
+	event := notifications.Event{
+		Type: eventType,
+		Data: applicationEventData{
+			ApplicationID: app.ID,
+			JobID:         app.JobID,
+			ContractorID:  app.ContractorID,
+			State:         app.State,
+		},
+	}
+	if err := s.notifier.Notify(ctx, userID, event); err != nil {
+		logger.FromContext(ctx).Warn("Failed to send application notification", "event", eventType, "user_id", userID, "application_id", app.ID, "error", err)
+	}
+}
+
+// ApplyToJob creates a new job application for a user to a specific job. Only a Waiting or Accepted
+// application blocks applying again; withdrawn and rejected ones are kept as history.
 func (s *jobApplicationService) ApplyToJob(ctx context.Context, req *dto.ApplyToJobRequest) (*models.JobApplication, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.ApplyToJob")
+	defer span.End()
+
 	// 1. Fetch the Job to check its state
 	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
 	job, err := s.jobRepo.GetByID(ctx, &jobReq)
@@ -38,9 +84,13 @@ func (s *jobApplicationService) ApplyToJob(ctx context.Context, req *dto.ApplyTo
 
 	// 2. Authorization/Validation
 	if job.State != models.JobStateWaiting || job.ContractorID != nil {
-		log.Printf("ApplyToJob: Attempt to apply to non-available job %s (State: %s, Contractor: %v)", req.JobID, job.State, job.ContractorID)
+		logger.FromContext(ctx).Warn("ApplyToJob: Attempt to apply to non-available job", "job_id", req.JobID, "job_state", job.State, "contractor_id", job.ContractorID)
 		return nil, fmt.Errorf("%w: job is not available for applications", ErrInvalidState)
 	}
+	if job.ApplicationDeadline != nil && time.Now().After(*job.ApplicationDeadline) {
+		logger.FromContext(ctx).Warn("ApplyToJob: Attempt to apply after the deadline", "job_id", req.JobID, "application_deadline", job.ApplicationDeadline)
+		return nil, fmt.Errorf("%w: applications are closed", ErrInvalidState)
+	}
 	if job.EmployerID == req.ContractorID {
 		return nil, fmt.Errorf("%w: employer cannot apply to their own job", ErrForbidden)
 	}
@@ -50,22 +100,28 @@ func (s *jobApplicationService) ApplyToJob(ctx context.Context, req *dto.ApplyTo
 	createReq := dto.CreateJobApplicationRequest{
 		JobID:        req.JobID,
 		ContractorID: req.ContractorID, // UserID from context is the ContractorID
+		CoverMessage: req.CoverMessage,
+		ProposedRate: req.ProposedRate,
 	}
 	application, err := s.appRepo.Create(ctx, &createReq)
 	if err != nil {
-		log.Printf("ApplyToJob: Error creating application in repo: %v", err)
+		logger.FromContext(ctx).Error("ApplyToJob: Error creating application in repo", "error", err)
 		return nil, mapRepoError(err, "creating application")
 	}
 
+	s.notifyApplicationEvent(ctx, job.EmployerID, notifications.EventApplicationReceived, application)
 	return application, nil
 }
 
 // AcceptApplication changes application state to Accepted, assigns contractor to job, and sets job state to Ongoing.
 func (s *jobApplicationService) AcceptApplication(ctx context.Context, req *dto.AcceptApplicationRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.AcceptApplication")
+	defer span.End()
+
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("AcceptApplication: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("AcceptApplication: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
@@ -73,6 +129,7 @@ func (s *jobApplicationService) AcceptApplication(ctx context.Context, req *dto.
 	// Use transaction-aware repositories
 	txAppRepo := s.appRepo.WithTx(tx)
 	txJobRepo := s.jobRepo.WithTx(tx)
+	txUserRepo := s.userRepo.WithTx(tx)
 	// --- End Transaction Setup ---
 
 	// 1. Fetch the Application (within transaction)
@@ -87,71 +144,129 @@ func (s *jobApplicationService) AcceptApplication(ctx context.Context, req *dto.
 	job, err := txJobRepo.GetByID(ctx, &jobReq)
 	if err != nil {
 		// Should not happen if application exists, but handle defensively
-		log.Printf("AcceptApplication: Error fetching job %s within transaction: %v", application.JobID, err)
+		logger.FromContext(ctx).Error("AcceptApplication: Error fetching job within transaction", "job_id", application.JobID, "error", err)
 		return nil, mapRepoError(err, fmt.Sprintf("fetching associated job %s within transaction", application.JobID))
 	}
 
 	// 3. Authorization & State Checks
 	if job.EmployerID != req.UserID {
-		log.Printf("AcceptApplication: Forbidden attempt by user %s on job %s owned by %s", req.UserID, job.ID, job.EmployerID)
+		logger.FromContext(ctx).Warn("AcceptApplication: Forbidden attempt", "user_id", req.UserID, "job_id", job.ID, "employer_id", job.EmployerID)
 		return nil, ErrForbidden
 	}
 	if job.State != models.JobStateWaiting || job.ContractorID != nil {
-		log.Printf("AcceptApplication: Attempt to accept application for non-available job %s (State: %s, Contractor: %v)", job.ID, job.State, job.ContractorID)
+		logger.FromContext(ctx).Warn("AcceptApplication: Attempt to accept application for non-available job", "job_id", job.ID, "job_state", job.State, "contractor_id", job.ContractorID)
 		return nil, fmt.Errorf("%w: job is not in a state to accept applications", ErrInvalidState)
 	}
 	if application.State != models.JobApplicationWaiting {
-		log.Printf("AcceptApplication: Attempt to accept non-waiting application %s (State: %s)", application.ID, application.State)
+		logger.FromContext(ctx).Warn("AcceptApplication: Attempt to accept non-waiting application", "application_id", application.ID, "application_state", application.State)
 		return nil, fmt.Errorf("%w: application is not in 'Waiting' state", ErrInvalidState)
 	}
 
-	// 4. Update Application State (within transaction)
-	updateAppReq := dto.UpdateJobApplicationStateRequest{ID: application.ID, State: models.JobApplicationAccepted}
-	_, err = txAppRepo.UpdateState(ctx, &updateAppReq)
+	// 4. Assign the contractor and settle the job's applications (within transaction)
+	updatedJob, acceptedApp, err := s.hireApplicant(ctx, txAppRepo, txJobRepo, txUserRepo, job, application, req.UserID)
 	if err != nil {
-		log.Printf("AcceptApplication: Error updating application state for %s: %v", application.ID, err)
-		return nil, mapRepoError(err, "updating application state")
+		return nil, err
 	}
 
-	// 5. Update Job State and Assign Contractor (within transaction)
-	contractorID := application.ContractorID
-	newState := models.JobStateOngoing
-	updateJobReq := dto.UpdateJobRequest{
-		ID:           job.ID,
-		ContractorID: &contractorID,
-		State:        &newState,
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("AcceptApplication: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing changes: %w", err)
 	}
-	updatedJob, err := txJobRepo.Update(ctx, &updateJobReq)
+	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, updatedJob.ID)
+
+	logger.FromContext(ctx).Info("Job application accepted, job updated to Ongoing", "application_id", application.ID, "job_id", updatedJob.ID, "contractor_id", acceptedApp.ContractorID, "rate", updatedJob.Rate)
+	s.notifyApplicationEvent(ctx, acceptedApp.ContractorID, notifications.EventApplicationAccepted, acceptedApp)
+	return updatedJob, nil
+}
+
+// hireApplicant assigns the contractor of application to job, sets the job to Ongoing with any proposed
+// rate, accepts the application and rejects the job's other pending ones, using repos bound to the
+// caller's transaction. hiredBy is who made the decision: the employer accepting an application, or the
+// contractor accepting an invitation.
+func (s *jobApplicationService) hireApplicant(ctx context.Context, appRepo storage.JobApplicationRepository, jobRepo storage.JobRepository, userRepo storage.UserRepository, job *models.Job, application *models.JobApplication, hiredBy uuid.UUID) (*models.Job, *models.JobApplication, error) {
+	// 1. Make sure the contractor can take on another job
+	if err := checkOngoingJobLimit(ctx, userRepo, jobRepo, application.ContractorID, s.maxOngoingJobs); err != nil {
+		return nil, nil, err
+	}
+
+	// 2. Assign Contractor, set Job to Ongoing and apply any proposed rate
+	// The caller's checks ran on a snapshot; the repo re-verifies them in the UPDATE so a concurrent
+	// accept can't also assign a contractor. This runs before touching any application rows so the
+	// losing transaction waits only on the job row and can't deadlock with step 4 of the winner.
+	assignReq := dto.AssignContractorRequest{JobID: job.ID, ContractorID: application.ContractorID, Rate: application.ProposedRate, UpdatedBy: hiredBy}
+	updatedJob, err := jobRepo.AssignContractor(ctx, &assignReq)
+	if err != nil {
+		if errors.Is(err, storage.ErrConflict) {
+			logger.FromContext(ctx).Warn("hireApplicant: Job was assigned concurrently", "job_id", job.ID)
+			if storage.IsRetryableConflict(err) {
+				return nil, nil, fmt.Errorf("%w: %w", ErrConflict, err)
+			}
+			return nil, nil, fmt.Errorf("%w: job has already been assigned a contractor", ErrConflict)
+		}
+		logger.FromContext(ctx).Error("hireApplicant: Error updating job", "job_id", job.ID, "error", err)
+		return nil, nil, mapRepoError(err, "updating job state")
+	}
+
+	// 3. Update Application State
+	updateAppReq := dto.UpdateJobApplicationStateRequest{ID: application.ID, State: models.JobApplicationAccepted}
+	acceptedApp, err := appRepo.UpdateState(ctx, &updateAppReq)
 	if err != nil {
-		log.Printf("AcceptApplication: Error updating job %s: %v", job.ID, err)
-		return nil, mapRepoError(err, "updating job state")
+		logger.FromContext(ctx).Error("hireApplicant: Error updating application state", "application_id", application.ID, "error", err)
+		return nil, nil, mapRepoError(err, "updating application state")
 	}
 
-	// 6. Reject other 'Waiting' applications for the same job (within transaction)
-	err = txAppRepo.UpdateStateByJobID(ctx, job.ID, models.JobApplicationRejected, &application.ID)
+	// 4. Reject the other pending applications and invitations for the same job
+	err = appRepo.UpdateStateByJobID(ctx, job.ID, models.JobApplicationRejected, &application.ID)
 	if err != nil {
-		log.Printf("AcceptApplication: Error rejecting other applications for job %s: %v", job.ID, err)
-		return nil, mapRepoError(err, "rejecting other applications")
+		logger.FromContext(ctx).Error("hireApplicant: Error rejecting other applications", "job_id", job.ID, "error", err)
+		return nil, nil, mapRepoError(err, "rejecting other applications")
 	}
+	return updatedJob, acceptedApp, nil
+}
 
-	// --- Commit Transaction ---
-	if err := tx.Commit(ctx); err != nil {
-		log.Printf("AcceptApplication: Error committing transaction: %v", err)
-		return nil, fmt.Errorf("internal error committing changes: %w", err)
+// checkOngoingJobLimit returns ErrLimitExceeded if assigning one more job would take the contractor past
+// their cap on Ongoing jobs: their own override, or maxOngoingJobs (0 meaning no limit). It locks the
+// contractor's user row, so two transactions assigning jobs to the same contractor can't both see room
+// for one more.
+func checkOngoingJobLimit(ctx context.Context, userRepo storage.UserRepository, jobRepo storage.JobRepository, contractorID uuid.UUID, maxOngoingJobs int) error {
+	override, err := userRepo.LockMaxOngoingJobs(ctx, contractorID)
+	if err != nil {
+		logger.FromContext(ctx).Error("checkOngoingJobLimit: Error fetching contractor limit", "contractor_id", contractorID, "error", err)
+		return mapRepoError(err, fmt.Sprintf("fetching ongoing job limit of contractor %s", contractorID))
+	}
+	limit := maxOngoingJobs
+	if override != nil {
+		limit = *override
+	} else if limit == 0 {
+		return nil
 	}
-	// --- End Transaction ---
 
-	log.Printf("Job application %s accepted, job %s updated to Ongoing with contractor %s", application.ID, updatedJob.ID, contractorID)
-	return updatedJob, nil
+	ongoing := models.JobStateOngoing
+	countReq := dto.ListJobsByContractorRequest{ContractorID: contractorID, State: &ongoing}
+	count, err := jobRepo.CountByContractor(ctx, &countReq)
+	if err != nil {
+		logger.FromContext(ctx).Error("checkOngoingJobLimit: Error counting ongoing jobs", "contractor_id", contractorID, "error", err)
+		return mapRepoError(err, "counting ongoing jobs")
+	}
+	if count >= limit {
+		logger.FromContext(ctx).Warn("checkOngoingJobLimit: Contractor is at the ongoing job limit", "contractor_id", contractorID, "ongoing", count, "limit", limit)
+		return fmt.Errorf("%w: contractor already has %d ongoing jobs, the maximum allowed", ErrLimitExceeded, count)
+	}
+	return nil
 }
 
 // GetApplicationByID retrieves an application, checking authorization.
 // User must be the applicant or the job employer.
 func (s *jobApplicationService) GetApplicationByID(ctx context.Context, req *dto.GetJobApplicationByIDRequest) (*models.JobApplication, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.GetApplicationByID")
+	defer span.End()
+
 	// 1. Fetch the application
 	application, err := s.appRepo.GetByID(ctx, req)
 	if err != nil {
-		log.Printf("GetApplicationByID: Error fetching application %s: %v", req.ID, err) // Log before mapping
+		logger.FromContext(ctx).Error("GetApplicationByID: Error fetching application", "application_id", req.ID, "error", err) // Log before mapping
 		return nil, mapRepoError(err, fmt.Sprintf("fetching application %s", req.ID))
 	}
 
@@ -160,7 +275,7 @@ func (s *jobApplicationService) GetApplicationByID(ctx context.Context, req *dto
 	job, err := s.jobRepo.GetByID(ctx, &jobReq)
 	if err != nil {
 		// This shouldn't happen if the application exists due to FK constraints, but handle defensively
-		log.Printf("GetApplicationByID: Error fetching job %s associated with application %s: %v", application.JobID, req.ID, err)
+		logger.FromContext(ctx).Error("GetApplicationByID: Error fetching job associated with application", "job_id", application.JobID, "application_id", req.ID, "error", err)
 		return nil, mapRepoError(err, fmt.Sprintf("fetching associated job %s", application.JobID))
 	}
 
@@ -168,7 +283,7 @@ func (s *jobApplicationService) GetApplicationByID(ctx context.Context, req *dto
 	isApplicant := application.ContractorID == req.UserID
 	isEmployer := job.EmployerID == req.UserID
 	if !isApplicant && !isEmployer {
-		log.Printf("GetApplicationByID: Forbidden attempt by user %s on application %s (Applicant: %s, Employer: %s)", req.UserID, req.ID, application.ContractorID, job.EmployerID)
+		logger.FromContext(ctx).Warn("GetApplicationByID: Forbidden attempt", "user_id", req.UserID, "application_id", req.ID, "contractor_id", application.ContractorID, "employer_id", job.EmployerID)
 		return nil, ErrForbidden
 	}
 
@@ -176,45 +291,163 @@ func (s *jobApplicationService) GetApplicationByID(ctx context.Context, req *dto
 }
 
 // ListApplicationsByContractor retrieves applications for the requesting user.
-func (s *jobApplicationService) ListApplicationsByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) ([]models.JobApplication, error) {
-	applications, err := s.appRepo.ListByContractor(ctx, req)
+func (s *jobApplicationService) ListApplicationsByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) ([]models.JobApplication, int, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.ListApplicationsByContractor")
+	defer span.End()
+
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListApplicationsByContractor: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txAppRepo := s.appRepo.WithTx(tx)
+
+	applications, err := txAppRepo.ListByContractor(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListApplicationsByContractor: Error listing applications", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, mapRepoError(err, fmt.Sprintf("listing applications for contractor %s", req.ContractorID))
+	}
+	total, err := txAppRepo.CountByContractor(ctx, req)
 	if err != nil {
-		log.Printf("ListApplicationsByContractor: Error listing applications for contractor %s: %v", req.ContractorID, err)
-		return nil, mapRepoError(err, fmt.Sprintf("listing applications for contractor %s", req.ContractorID))
+		logger.FromContext(ctx).Error("ListApplicationsByContractor: Error counting applications", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, mapRepoError(err, fmt.Sprintf("counting applications for contractor %s", req.ContractorID))
 	}
-	return applications, nil
+	return applications, total, nil
+}
+
+// ListAppliedJobs lists the jobs the contractor applied to with their application inline, whatever the
+// application's outcome. Unlike ListApplicationsByContractor it returns the job details too.
+func (s *jobApplicationService) ListAppliedJobs(ctx context.Context, req *dto.ListAppliedJobsRequest) ([]models.AppliedJob, int, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.ListAppliedJobs")
+	defer span.End()
+
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListAppliedJobs: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txAppRepo := s.appRepo.WithTx(tx)
+
+	appliedJobs, err := txAppRepo.ListAppliedJobs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListAppliedJobs: Error listing applied jobs", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, mapRepoError(err, fmt.Sprintf("listing applied jobs for contractor %s", req.ContractorID))
+	}
+	total, err := txAppRepo.CountAppliedJobs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListAppliedJobs: Error counting applied jobs", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, mapRepoError(err, fmt.Sprintf("counting applied jobs for contractor %s", req.ContractorID))
+	}
+
+	jobs := make([]*models.Job, len(appliedJobs))
+	for i := range appliedJobs {
+		jobs[i] = &appliedJobs[i].Job
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), jobs...); err != nil {
+		logger.FromContext(ctx).Error("ListAppliedJobs: Error loading job skills", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	return appliedJobs, total, nil
 }
 
 // ListApplicationsByJob retrieves applications for a specific job, checking authorization.
-func (s *jobApplicationService) ListApplicationsByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) ([]models.JobApplication, error) {
+func (s *jobApplicationService) ListApplicationsByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) ([]models.JobApplication, int, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.ListApplicationsByJob")
+	defer span.End()
+
 	// 1. Fetch the job to verify existence and check ownership
 	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
 	job, err := s.jobRepo.GetByID(ctx, &jobReq)
 	if err != nil {
-		return nil, mapRepoError(err, fmt.Sprintf("fetching job %s for listing applications", req.JobID))
+		return nil, 0, mapRepoError(err, fmt.Sprintf("fetching job %s for listing applications", req.JobID))
 	}
 
 	// 2. Authorization Check: Only the employer can list applications for their job
 	if job.EmployerID != req.UserID {
-		log.Printf("ListApplicationsByJob: Forbidden attempt by user %s to list applications for job %s owned by %s", req.UserID, req.JobID, job.EmployerID)
-		return nil, ErrForbidden
+		logger.FromContext(ctx).Warn("ListApplicationsByJob: Forbidden attempt to list applications", "user_id", req.UserID, "job_id", req.JobID, "employer_id", job.EmployerID)
+		return nil, 0, ErrForbidden
+	}
+
+	// 3. List and count from the same snapshot so Total matches the page
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListApplicationsByJob: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
 	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txAppRepo := s.appRepo.WithTx(tx)
 
-	// 3. Call repo method
-	applications, err := s.appRepo.ListByJob(ctx, req)
+	applications, err := txAppRepo.ListByJob(ctx, req)
 	if err != nil {
-		log.Printf("ListApplicationsByJob: Error listing applications for job %s: %v", req.JobID, err)
-		return nil, mapRepoError(err, fmt.Sprintf("listing applications for job %s", req.JobID))
+		logger.FromContext(ctx).Error("ListApplicationsByJob: Error listing applications", "job_id", req.JobID, "error", err)
+		return nil, 0, mapRepoError(err, fmt.Sprintf("listing applications for job %s", req.JobID))
 	}
-	return applications, nil
+	total, err := txAppRepo.CountByJob(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListApplicationsByJob: Error counting applications", "job_id", req.JobID, "error", err)
+		return nil, 0, mapRepoError(err, fmt.Sprintf("counting applications for job %s", req.JobID))
+	}
+	return applications, total, nil
+}
+
+// ExportApplicationsCSV writes the applications for a job, only those in req.State if it is set, to w as
+// CSV, one row at a time. Free-text cells are escaped so they can't run as spreadsheet formulas. Only
+// the employer who posted the job may export its applications.
+func (s *jobApplicationService) ExportApplicationsCSV(ctx context.Context, req *dto.ExportJobApplicationsCSVRequest, w io.Writer) error {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.ExportApplicationsCSV")
+	defer span.End()
+
+	// 1. Fetch the job to verify existence and check ownership
+	jobReq := dto.GetJobByIDRequest{ID: req.JobID}
+	job, err := s.jobRepo.GetByID(ctx, &jobReq)
+	if err != nil {
+		return mapRepoError(err, fmt.Sprintf("fetching job %s for exporting applications", req.JobID))
+	}
+
+	// 2. Authorization Check: Only the employer can export applications for their job
+	if job.EmployerID != req.UserID {
+		logger.FromContext(ctx).Warn("ExportApplicationsCSV: Forbidden attempt to export applications", "user_id", req.UserID, "job_id", req.JobID, "employer_id", job.EmployerID)
+		return ErrForbidden
+	}
+
+	// 3. Stream rows straight from the repository into the CSV writer
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"contractor_name", "application_date", "state", "cover_message", "employer_note"}); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	err = s.appRepo.StreamByJob(ctx, req, func(row *models.JobApplicationExportRow) error {
+		return cw.Write([]string{
+			csvCell(row.ContractorName),
+			row.AppliedAt.Format(time.RFC3339),
+			string(row.State),
+			csvCell(derefString(row.CoverMessage)),
+			csvCell(derefString(row.EmployerNote)),
+		})
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("ExportApplicationsCSV: Error streaming applications", "job_id", req.JobID, "error", err)
+		return fmt.Errorf("internal error exporting applications for job %s: %w", req.JobID, err)
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV output: %w", err)
+	}
+	return nil
 }
 
 // RejectApplication changes application state to Rejected.
 func (s *jobApplicationService) RejectApplication(ctx context.Context, req *dto.RejectApplicationRequest) (*models.JobApplication, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.RejectApplication")
+	defer span.End()
+
 	// --- Transaction Start (Read-Check-Write pattern) ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("RejectApplication: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("RejectApplication: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
@@ -227,7 +460,7 @@ func (s *jobApplicationService) RejectApplication(ctx context.Context, req *dto.
 	appReq := dto.GetJobApplicationByIDRequest{ID: req.ApplicationID}
 	application, err := txAppRepo.GetByID(ctx, &appReq)
 	if err != nil {
-		log.Printf("RejectApplication: Error fetching application %s: %v", req.ApplicationID, err) // Log before mapping
+		logger.FromContext(ctx).Error("RejectApplication: Error fetching application", "application_id", req.ApplicationID, "error", err) // Log before mapping
 		return nil, mapRepoError(err, fmt.Sprintf("fetching application %s", req.ApplicationID))
 	}
 
@@ -236,19 +469,19 @@ func (s *jobApplicationService) RejectApplication(ctx context.Context, req *dto.
 	job, err := txJobRepo.GetByID(ctx, &jobReq)
 	if err != nil {
 		// This shouldn't happen if the application exists, but handle defensively
-		log.Printf("RejectApplication: Error fetching job %s for application %s: %v", application.JobID, req.ApplicationID, err)
+		logger.FromContext(ctx).Error("RejectApplication: Error fetching job", "job_id", application.JobID, "application_id", req.ApplicationID, "error", err)
 		return nil, mapRepoError(err, fmt.Sprintf("fetching associated job %s", application.JobID))
 	}
 
 	// 3. Authorization Check: Only the employer can reject
 	if job.EmployerID != req.UserID {
-		log.Printf("RejectApplication: Forbidden attempt by user %s on application %s (Job Employer: %s)", req.UserID, req.ApplicationID, job.EmployerID)
+		logger.FromContext(ctx).Warn("RejectApplication: Forbidden attempt", "user_id", req.UserID, "application_id", req.ApplicationID, "employer_id", job.EmployerID)
 		return nil, ErrForbidden
 	}
 
 	// 4. State Check: Can only reject 'Waiting' applications
 	if application.State != models.JobApplicationWaiting {
-		log.Printf("RejectApplication: Attempt to reject non-waiting application %s (State: %s)", application.ID, application.State)
+		logger.FromContext(ctx).Warn("RejectApplication: Attempt to reject non-waiting application", "application_id", application.ID, "application_state", application.State)
 		return nil, fmt.Errorf("%w: application is not in 'Waiting' state, current state: %s", ErrInvalidState, application.State)
 	}
 
@@ -256,27 +489,116 @@ func (s *jobApplicationService) RejectApplication(ctx context.Context, req *dto.
 	updateReq := dto.UpdateJobApplicationStateRequest{ID: application.ID, State: models.JobApplicationRejected}
 	updatedApp, err := txAppRepo.UpdateState(ctx, &updateReq)
 	if err != nil {
-		log.Printf("RejectApplication: Error updating application state for %s: %v", application.ID, err)
+		logger.FromContext(ctx).Error("RejectApplication: Error updating application state", "application_id", application.ID, "error", err)
 		return nil, mapRepoError(err, "updating application state")
 	}
 
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("RejectApplication: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("RejectApplication: Error committing transaction", "error", err)
 		return nil, fmt.Errorf("internal error committing rejection: %w", err)
 	}
 	// --- End Transaction ---
 
-	log.Printf("Job application %s rejected successfully by user %s", updatedApp.ID, req.UserID)
+	logger.FromContext(ctx).Info("Job application rejected successfully", "application_id", updatedApp.ID, "user_id", req.UserID)
+	s.notifyApplicationEvent(ctx, updatedApp.ContractorID, notifications.EventApplicationRejected, updatedApp)
 	return updatedApp, nil
 }
 
+// BulkRejectApplications rejects each of the requested applications in one transaction. An application
+// that doesn't exist, belongs to another employer's job or isn't 'Waiting' is reported in its result and
+// skipped, without holding back the others; only database failures abort the whole batch.
+func (s *jobApplicationService) BulkRejectApplications(ctx context.Context, req *dto.BulkRejectRequest) ([]dto.BulkRejectResult, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.BulkRejectApplications")
+	defer span.End()
+
+	// --- Transaction Start (Read-Check-Write pattern) ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("BulkRejectApplications: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	txAppRepo := s.appRepo.WithTx(tx)
+	txJobRepo := s.jobRepo.WithTx(tx)
+	// --- End Transaction Setup ---
+
+	results := make([]dto.BulkRejectResult, 0, len(req.ApplicationIDs))
+	seen := make(map[uuid.UUID]bool, len(req.ApplicationIDs))
+	jobs := make(map[uuid.UUID]*models.Job) // Applications to the same job share one lookup
+	for _, id := range req.ApplicationIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result := dto.BulkRejectResult{ApplicationID: id}
+
+		application, err := txAppRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: id})
+		if err != nil {
+			if !errors.Is(err, storage.ErrNotFound) {
+				logger.FromContext(ctx).Error("BulkRejectApplications: Error fetching application", "application_id", id, "error", err)
+				return nil, mapRepoError(err, fmt.Sprintf("fetching application %s", id))
+			}
+			result.Err = mapRepoError(err, fmt.Sprintf("fetching application %s", id))
+			results = append(results, result)
+			continue
+		}
+
+		job, ok := jobs[application.JobID]
+		if !ok {
+			job, err = txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: application.JobID})
+			if err != nil {
+				logger.FromContext(ctx).Error("BulkRejectApplications: Error fetching job", "job_id", application.JobID, "application_id", id, "error", err)
+				return nil, mapRepoError(err, fmt.Sprintf("fetching associated job %s", application.JobID))
+			}
+			jobs[application.JobID] = job
+		}
+
+		switch {
+		case job.EmployerID != req.UserID:
+			logger.FromContext(ctx).Warn("BulkRejectApplications: Forbidden attempt", "user_id", req.UserID, "application_id", id, "employer_id", job.EmployerID)
+			result.Err = ErrForbidden
+		case application.State != models.JobApplicationWaiting:
+			result.Err = fmt.Errorf("%w: application is not in 'Waiting' state, current state: %s", ErrInvalidState, application.State)
+		default:
+			updateReq := dto.UpdateJobApplicationStateRequest{ID: application.ID, State: models.JobApplicationRejected}
+			result.Application, err = txAppRepo.UpdateState(ctx, &updateReq)
+			if err != nil {
+				logger.FromContext(ctx).Error("BulkRejectApplications: Error updating application state", "application_id", id, "error", err)
+				return nil, mapRepoError(err, "updating application state")
+			}
+		}
+		results = append(results, result)
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("BulkRejectApplications: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing rejections: %w", err)
+	}
+	// --- End Transaction ---
+
+	rejected := 0
+	for _, result := range results {
+		if result.Application != nil {
+			rejected++
+			s.notifyApplicationEvent(ctx, result.Application.ContractorID, notifications.EventApplicationRejected, result.Application)
+		}
+	}
+	logger.FromContext(ctx).Info("Job applications bulk rejected", "user_id", req.UserID, "rejected", rejected, "failed", len(results)-rejected)
+	return results, nil
+}
+
 // WithdrawApplication changes application state to Withdrawn.
 func (s *jobApplicationService) WithdrawApplication(ctx context.Context, req *dto.WithdrawApplicationRequest) (*models.JobApplication, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.WithdrawApplication")
+	defer span.End()
+
 	// --- Transaction Start (Read-Check-Write pattern) ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("WithdrawApplication: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("WithdrawApplication: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
@@ -288,19 +610,19 @@ func (s *jobApplicationService) WithdrawApplication(ctx context.Context, req *dt
 	appReq := dto.GetJobApplicationByIDRequest{ID: req.ApplicationID}
 	application, err := txAppRepo.GetByID(ctx, &appReq)
 	if err != nil {
-		log.Printf("WithdrawApplication: Error fetching application %s: %v", req.ApplicationID, err) // Log before mapping
+		logger.FromContext(ctx).Error("WithdrawApplication: Error fetching application", "application_id", req.ApplicationID, "error", err) // Log before mapping
 		return nil, mapRepoError(err, fmt.Sprintf("fetching application %s", req.ApplicationID))
 	}
 
 	// 2. Authorization Check: Only the applicant (contractor) can withdraw
 	if application.ContractorID != req.UserID {
-		log.Printf("WithdrawApplication: Forbidden attempt by user %s on application %s owned by %s", req.UserID, req.ApplicationID, application.ContractorID)
+		logger.FromContext(ctx).Warn("WithdrawApplication: Forbidden attempt", "user_id", req.UserID, "application_id", req.ApplicationID, "contractor_id", application.ContractorID)
 		return nil, ErrForbidden
 	}
 
 	// 3. State Check: Can only withdraw 'Waiting' applications
 	if application.State != models.JobApplicationWaiting {
-		log.Printf("WithdrawApplication: Attempt to withdraw non-waiting application %s (State: %s)", application.ID, application.State)
+		logger.FromContext(ctx).Warn("WithdrawApplication: Attempt to withdraw non-waiting application", "application_id", application.ID, "application_state", application.State)
 		return nil, fmt.Errorf("%w: application is not in 'Waiting' state, current state: %s", ErrInvalidState, application.State)
 	}
 
@@ -308,18 +630,187 @@ func (s *jobApplicationService) WithdrawApplication(ctx context.Context, req *dt
 	updateReq := dto.UpdateJobApplicationStateRequest{ID: application.ID, State: models.JobApplicationWithdrawn}
 	updatedApp, err := txAppRepo.UpdateState(ctx, &updateReq)
 	if err != nil {
-		log.Printf("WithdrawApplication: Error updating application state for %s: %v", application.ID, err)
+		logger.FromContext(ctx).Error("WithdrawApplication: Error updating application state", "application_id", application.ID, "error", err)
 		return nil, mapRepoError(err, "updating application state")
 	}
 
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("WithdrawApplication: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("WithdrawApplication: Error committing transaction", "error", err)
 		return nil, fmt.Errorf("internal error committing withdrawal: %w", err)
 	}
 	// --- End Transaction ---
 
-	log.Printf("Job application %s withdrawn successfully by user %s", updatedApp.ID, req.UserID)
+	logger.FromContext(ctx).Info("Job application withdrawn successfully", "application_id", updatedApp.ID, "user_id", req.UserID)
 	return updatedApp, nil
 }
 
+
+// InviteContractor lets the employer of a Waiting job invite a contractor to it directly. The invitation
+// is an application in the Invited state, which the contractor accepts or declines; it isn't held to the
+// job's application deadline, since the employer chose to send it.
+func (s *jobApplicationService) InviteContractor(ctx context.Context, req *dto.InviteContractorRequest) (*models.JobApplication, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.InviteContractor")
+	defer span.End()
+
+	// 1. Fetch the Job to check ownership and state
+	job, err := s.jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		return nil, mapRepoError(err, fmt.Sprintf("fetching job %s for invitation", req.JobID))
+	}
+
+	// 2. Authorization/Validation
+	if job.EmployerID != req.EmployerID {
+		logger.FromContext(ctx).Warn("InviteContractor: Forbidden attempt", "user_id", req.EmployerID, "job_id", job.ID, "employer_id", job.EmployerID)
+		return nil, ErrForbidden
+	}
+	if req.ContractorID == req.EmployerID {
+		return nil, fmt.Errorf("%w: employer cannot invite themselves", ErrForbidden)
+	}
+	if job.State != models.JobStateWaiting || job.ContractorID != nil {
+		logger.FromContext(ctx).Warn("InviteContractor: Attempt to invite to non-available job", "job_id", job.ID, "job_state", job.State, "contractor_id", job.ContractorID)
+		return nil, fmt.Errorf("%w: job is not available for invitations", ErrInvalidState)
+	}
+	if _, err := s.userRepo.GetByID(ctx, &dto.GetUserByIdRequest{ID: req.ContractorID}); err != nil {
+		return nil, mapRepoError(err, fmt.Sprintf("fetching invited contractor %s", req.ContractorID))
+	}
+
+	// 3. Create the invitation; the unique index refuses it if the contractor already applied or was invited
+	createReq := dto.CreateJobApplicationRequest{
+		JobID:        job.ID,
+		ContractorID: req.ContractorID,
+		CoverMessage: req.Message,
+		State:        models.JobApplicationInvited,
+	}
+	invitation, err := s.appRepo.Create(ctx, &createReq)
+	if err != nil {
+		logger.FromContext(ctx).Error("InviteContractor: Error creating invitation in repo", "job_id", job.ID, "contractor_id", req.ContractorID, "error", err)
+		return nil, mapRepoError(err, "creating invitation")
+	}
+
+	logger.FromContext(ctx).Info("Contractor invited to job", "application_id", invitation.ID, "job_id", job.ID, "contractor_id", req.ContractorID)
+	s.notifyApplicationEvent(ctx, req.ContractorID, notifications.EventInvitationReceived, invitation)
+	return invitation, nil
+}
+
+// AcceptInvitation lets the invited contractor take the job: they are assigned to it, the job becomes
+// Ongoing and its other pending applications are rejected, as when the employer accepts an application.
+func (s *jobApplicationService) AcceptInvitation(ctx context.Context, req *dto.RespondToInvitationRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.AcceptInvitation")
+	defer span.End()
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("AcceptInvitation: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	txAppRepo := s.appRepo.WithTx(tx)
+	txJobRepo := s.jobRepo.WithTx(tx)
+	txUserRepo := s.userRepo.WithTx(tx)
+	// --- End Transaction Setup ---
+
+	// 1. Fetch the Invitation and its Job (within transaction)
+	invitation, err := s.getInvitation(ctx, txAppRepo, req)
+	if err != nil {
+		return nil, err
+	}
+	job, err := txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: invitation.JobID})
+	if err != nil {
+		logger.FromContext(ctx).Error("AcceptInvitation: Error fetching job within transaction", "job_id", invitation.JobID, "error", err)
+		return nil, mapRepoError(err, fmt.Sprintf("fetching associated job %s within transaction", invitation.JobID))
+	}
+	if job.State != models.JobStateWaiting || job.ContractorID != nil {
+		logger.FromContext(ctx).Warn("AcceptInvitation: Attempt to accept invitation to non-available job", "job_id", job.ID, "job_state", job.State, "contractor_id", job.ContractorID)
+		return nil, fmt.Errorf("%w: job is no longer available", ErrInvalidState)
+	}
+
+	// 2. Assign the contractor and settle the job's applications (within transaction)
+	updatedJob, acceptedApp, err := s.hireApplicant(ctx, txAppRepo, txJobRepo, txUserRepo, job, invitation, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("AcceptInvitation: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing changes: %w", err)
+	}
+	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, updatedJob.ID)
+
+	logger.FromContext(ctx).Info("Invitation accepted, job updated to Ongoing", "application_id", invitation.ID, "job_id", updatedJob.ID, "contractor_id", req.UserID)
+	s.notifyApplicationEvent(ctx, job.EmployerID, notifications.EventInvitationAccepted, acceptedApp)
+	return updatedJob, nil
+}
+
+// DeclineInvitation lets the invited contractor turn the invitation down.
+func (s *jobApplicationService) DeclineInvitation(ctx context.Context, req *dto.RespondToInvitationRequest) (*models.JobApplication, error) {
+	ctx, span := tracing.Start(ctx, "JobApplicationService.DeclineInvitation")
+	defer span.End()
+
+	// --- Transaction Start (Read-Check-Write pattern) ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("DeclineInvitation: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	txAppRepo := s.appRepo.WithTx(tx)
+	txJobRepo := s.jobRepo.WithTx(tx)
+	// --- End Transaction Setup ---
+
+	// 1. Fetch the Invitation (within transaction)
+	invitation, err := s.getInvitation(ctx, txAppRepo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Update Invitation State (within transaction)
+	updateReq := dto.UpdateJobApplicationStateRequest{ID: invitation.ID, State: models.JobApplicationDeclined}
+	declined, err := txAppRepo.UpdateState(ctx, &updateReq)
+	if err != nil {
+		logger.FromContext(ctx).Error("DeclineInvitation: Error updating invitation state", "application_id", invitation.ID, "error", err)
+		return nil, mapRepoError(err, "updating invitation state")
+	}
+
+	// 3. Fetch the Job to tell its employer (within transaction)
+	job, err := txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: invitation.JobID})
+	if err != nil {
+		logger.FromContext(ctx).Error("DeclineInvitation: Error fetching job", "job_id", invitation.JobID, "error", err)
+		return nil, mapRepoError(err, fmt.Sprintf("fetching associated job %s", invitation.JobID))
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("DeclineInvitation: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing decline: %w", err)
+	}
+	// --- End Transaction ---
+
+	logger.FromContext(ctx).Info("Invitation declined", "application_id", declined.ID, "user_id", req.UserID)
+	s.notifyApplicationEvent(ctx, job.EmployerID, notifications.EventInvitationDeclined, declined)
+	return declined, nil
+}
+
+// getInvitation fetches the invitation req answers, checking that the user is the invited contractor
+// and that it is still open.
+func (s *jobApplicationService) getInvitation(ctx context.Context, appRepo storage.JobApplicationRepository, req *dto.RespondToInvitationRequest) (*models.JobApplication, error) {
+	invitation, err := appRepo.GetByID(ctx, &dto.GetJobApplicationByIDRequest{ID: req.ApplicationID})
+	if err != nil {
+		logger.FromContext(ctx).Error("getInvitation: Error fetching invitation", "application_id", req.ApplicationID, "error", err)
+		return nil, mapRepoError(err, fmt.Sprintf("fetching invitation %s", req.ApplicationID))
+	}
+	if invitation.ContractorID != req.UserID {
+		logger.FromContext(ctx).Warn("getInvitation: Forbidden attempt", "user_id", req.UserID, "application_id", invitation.ID, "contractor_id", invitation.ContractorID)
+		return nil, ErrForbidden
+	}
+	if invitation.State != models.JobApplicationInvited {
+		logger.FromContext(ctx).Warn("getInvitation: Attempt to answer a closed invitation", "application_id", invitation.ID, "application_state", invitation.State)
+		return nil, fmt.Errorf("%w: application is not an open invitation, current state: %s", ErrInvalidState, invitation.State)
+	}
+	return invitation, nil
+}
@@ -2,82 +2,242 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
 	"go-api-template/internal/models"
 	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres"
 	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/money"
+	"go-api-template/pkg/tracing"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type jobService struct {
 	jobRepo storage.JobRepository
 	userRepo storage.UserRepository
+	outboxRepo storage.OutboxRepository
+	invoiceRepo storage.InvoiceRepository
+	savedJobRepo storage.SavedJobRepository
+	skillRepo storage.SkillRepository
+	auditRepo storage.AuditRepository
 	db      *pgxpool.Pool 
+	paymentTermDays int // The final invoice of a cancelled job is due this many days after creation
+	defaultCurrency string // Currency of jobs posted without one
+	maxOngoingJobs  int    // Cap on a contractor's Ongoing jobs when reassigning one to them; 0 means no limit
 }
 
-// NewJobService creates a new instance of JobService.
-func NewJobService(db *pgxpool.Pool) JobService {
-	return &jobService{jobRepo: postgres.NewJobRepo(db), userRepo: postgres.NewUserRepo(db), db: db}
+// NewJobService creates a new instance of JobService. The final invoice of a cancelled job is due
+// paymentTermDays after creation; a negative value uses models.DefaultInvoicePaymentTermDays. Jobs
+// posted without a currency get defaultCurrency, or models.DefaultCurrency if it is empty. A contractor
+// can't be reassigned a job that takes them past maxOngoingJobs, like when hired through an application.
+func NewJobService(db *pgxpool.Pool, paymentTermDays int, defaultCurrency string, maxOngoingJobs int, readCache cache.Config) JobService {
+	if paymentTermDays < 0 {
+		paymentTermDays = models.DefaultInvoicePaymentTermDays
+	}
+	if defaultCurrency == "" {
+		defaultCurrency = models.DefaultCurrency
+	}
+	return &jobService{jobRepo: readCache.JobRepository(postgres.NewJobRepo(db)), userRepo: readCache.UserRepository(postgres.NewUserRepo(db)), outboxRepo: postgres.NewOutboxRepo(db), invoiceRepo: postgres.NewInvoiceRepo(db), savedJobRepo: postgres.NewSavedJobRepo(db), skillRepo: postgres.NewSkillRepo(db), auditRepo: postgres.NewAuditRepo(db), db: db, paymentTermDays: paymentTermDays, defaultCurrency: defaultCurrency, maxOngoingJobs: maxOngoingJobs}
 }
 
 func (s *jobService) CreateJob(ctx context.Context, req *dto.CreateJobRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.CreateJob")
+	defer span.End()
+
+	if err := validateJobTerms(req.Rate, req.Duration, req.InvoiceInterval); err != nil {
+		return nil, err
+	}
+	if err := validateApplicationDeadline(req.ApplicationDeadline); err != nil {
+		return nil, err
+	}
+	currency, err := resolveCurrency(req.Currency, s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	req.Currency = currency
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("CreateJob: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
 	// EmployerID is already set in the handler from context, passed in req.
-	job, err := s.jobRepo.Create(ctx, req)
+	job, err := s.jobRepo.WithTx(tx).Create(ctx, req)
 	if err != nil {
-		log.Printf("JobService: Error creating job: %v", err)
+		logger.FromContext(ctx).Error("JobService: Error creating job", "error", err)
 		// Map storage errors if necessary (e.g., ErrConflict for FK violation)
 		return nil, fmt.Errorf("internal error creating job: %w", err)
 	}
+
+	if skills := normalizeSkillNames(req.Skills); len(skills) > 0 {
+		if err := s.skillRepo.WithTx(tx).SetForJob(ctx, &dto.SetJobSkillsRequest{JobID: job.ID, Names: skills}); err != nil {
+			logger.FromContext(ctx).Error("CreateJob: Error setting job skills", "job_id", job.ID, "error", err)
+			return nil, fmt.Errorf("internal error setting job skills: %w", err)
+		}
+		job.Skills = skills
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, job.ID, models.OutboxEventJobCreated, job); err != nil {
+		logger.FromContext(ctx).Error("CreateJob: Error recording outbox event", "job_id", job.ID, "error", err)
+		return nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("CreateJob: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing job creation: %w", err)
+	}
+	// --- End Transaction ---
+	JobsCreatedTotal.Inc()
 	return job, nil
 }
 
 func (s *jobService) GetJobByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.GetJobByID")
+	defer span.End()
+
 	job, err := s.jobRepo.GetByID(ctx, req)
 	if err != nil {
-		log.Printf("JobService: Error getting job %s: %v", req.ID, err)
+		logger.FromContext(ctx).Error("JobService: Error getting job", "job_id", req.ID, "error", err)
 		return nil, mapRepoError(err, "getting job by ID")
 	}
+	if err := attachJobSkills(ctx, s.skillRepo, job); err != nil {
+		logger.FromContext(ctx).Error("JobService: Error loading job skills", "job_id", req.ID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
 	return job, nil
 }
 
-func (s *jobService) ListAvailableJobs(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, error) {
-	jobs, err := s.jobRepo.ListAvailable(ctx, req)
+// ListAvailableJobs returns a page of available jobs and the total number matching the filters.
+func (s *jobService) ListAvailableJobs(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, int, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ListAvailableJobs")
+	defer span.End()
+
+	if req.MinDuration != nil && req.MaxDuration != nil && *req.MinDuration > *req.MaxDuration {
+		return nil, 0, fmt.Errorf("%w: min_duration must not be greater than max_duration", ErrValidation)
+	}
+	req.Skills = normalizeSkillNames(req.Skills) // The "all" match counts distinct names
+
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListAvailableJobs: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txJobRepo := s.jobRepo.WithTx(tx)
+
+	jobs, err := txJobRepo.ListAvailable(ctx, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidInput) {
+			return nil, 0, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		logger.FromContext(ctx).Error("JobService: Error listing available jobs", "error", err)
+		return nil, 0, fmt.Errorf("internal error listing available jobs: %w", err)
+	}
+	total, err := txJobRepo.CountAvailable(ctx, req)
 	if err != nil {
-		log.Printf("JobService: Error listing available jobs: %v", err)
-		return nil, fmt.Errorf("internal error listing available jobs: %w", err)
+		logger.FromContext(ctx).Error("JobService: Error counting available jobs", "error", err)
+		return nil, 0, fmt.Errorf("internal error counting available jobs: %w", err)
 	}
-	return jobs, nil
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), jobPointers(jobs)...); err != nil {
+		logger.FromContext(ctx).Error("JobService: Error loading skills of available jobs", "error", err)
+		return nil, 0, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	return jobs, total, nil
 }
 
-func (s *jobService) ListJobsByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) ([]models.Job, error) {
+// ListJobsByEmployer returns a page of the employer's jobs and the total number matching the filters.
+func (s *jobService) ListJobsByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) ([]models.Job, int, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ListJobsByEmployer")
+	defer span.End()
+
 	// EmployerID is set in handler from context and passed in req. (Might change this so it can be overridden to allow listing for other users)
-	jobs, err := s.jobRepo.ListByEmployer(ctx, req)
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListJobsByEmployer: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txJobRepo := s.jobRepo.WithTx(tx)
+
+	jobs, err := txJobRepo.ListByEmployer(ctx, req)
 	if err != nil {
-		log.Printf("JobService: Error listing employer jobs for %s: %v", req.EmployerID, err)
-		return nil, fmt.Errorf("internal error listing employer jobs: %w", err)
+		if errors.Is(err, storage.ErrInvalidInput) {
+			return nil, 0, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		logger.FromContext(ctx).Error("JobService: Error listing employer jobs", "employer_id", req.EmployerID, "error", err)
+		return nil, 0, fmt.Errorf("internal error listing employer jobs: %w", err)
 	}
-	return jobs, nil
+	total, err := txJobRepo.CountByEmployer(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error counting employer jobs", "employer_id", req.EmployerID, "error", err)
+		return nil, 0, fmt.Errorf("internal error counting employer jobs: %w", err)
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), jobPointers(jobs)...); err != nil {
+		logger.FromContext(ctx).Error("JobService: Error loading skills of employer jobs", "employer_id", req.EmployerID, "error", err)
+		return nil, 0, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	return jobs, total, nil
 }
 
-func (s *jobService) ListJobsByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, error) {
+// ListJobsByContractor returns a page of the contractor's jobs and the total number matching the filters.
+func (s *jobService) ListJobsByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, int, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ListJobsByContractor")
+	defer span.End()
+
 	// ContractorID is set in handler from context and passed in req. (Might change this so it can be overridden to allow listing for other users)
-	jobs, err := s.jobRepo.ListByContractor(ctx, req)
+	tx, err := beginSnapshotTx(ctx, s.db)
 	if err != nil {
-		log.Printf("JobService: Error listing contractor jobs for %s: %v", req.ContractorID, err)
-		return nil, fmt.Errorf("internal error listing contractor jobs: %w", err)
+		logger.FromContext(ctx).Error("ListJobsByContractor: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
 	}
-	return jobs, nil
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txJobRepo := s.jobRepo.WithTx(tx)
+
+	jobs, err := txJobRepo.ListByContractor(ctx, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidInput) {
+			return nil, 0, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		logger.FromContext(ctx).Error("JobService: Error listing contractor jobs", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, fmt.Errorf("internal error listing contractor jobs: %w", err)
+	}
+	total, err := txJobRepo.CountByContractor(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error counting contractor jobs", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, fmt.Errorf("internal error counting contractor jobs: %w", err)
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), jobPointers(jobs)...); err != nil {
+		logger.FromContext(ctx).Error("JobService: Error loading skills of contractor jobs", "contractor_id", req.ContractorID, "error", err)
+		return nil, 0, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	return jobs, total, nil
 }
 
 func (s *jobService) UpdateJobDetails(ctx context.Context, req *dto.UpdateJobDetailsRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.UpdateJobDetails")
+	defer span.End()
+
+	if err := validateApplicationDeadline(req.ApplicationDeadline); err != nil {
+		return nil, err
+	}
+
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("UpdateJobDetails: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("UpdateJobDetails: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
@@ -89,96 +249,564 @@ func (s *jobService) UpdateJobDetails(ctx context.Context, req *dto.UpdateJobDet
 	getReq := dto.GetJobByIDRequest{ID: req.JobID}
 	existingJob, err := txJobRepo.GetByID(ctx, &getReq) // Use txJobRepo
 	if err != nil {
-		log.Printf("UpdateJobDetails: Error fetching job %s: %v", req.JobID, err)
+		logger.FromContext(ctx).Error("UpdateJobDetails: Error fetching job", "job_id", req.JobID, "error", err)
 		return nil, mapRepoError(err, "fetching job for update")
 	}
 
 	// Authorization & State Check
 	if !(req.UserID == existingJob.EmployerID && existingJob.State == models.JobStateWaiting && existingJob.ContractorID == nil) {
-		log.Printf("UpdateJobDetails: Forbidden attempt on job %s by user %s. State: %s, Contractor: %v", req.JobID, req.UserID, existingJob.State, existingJob.ContractorID)
+		logger.FromContext(ctx).Warn("UpdateJobDetails: Forbidden attempt", "job_id", req.JobID, "user_id", req.UserID, "job_state", existingJob.State, "contractor_id", existingJob.ContractorID)
 		return nil, ErrForbidden // Or ErrInvalidState
 	}
 
 	updateRepoReq := dto.UpdateJobRequest{
 		ID:       req.JobID,
+		Version:  req.Version,
 		Rate:     req.Rate,
 		Duration: req.Duration,
+		Description: req.Description,
+		ApplicationDeadline: req.ApplicationDeadline,
+		Touch:    req.Skills != nil, // A skills-only change still bumps the version
+		UpdatedBy: req.UserID,
 	}
 	updatedJob, err := txJobRepo.Update(ctx, &updateRepoReq) // Use txJobRepo
 	if err != nil {
-		log.Printf("UpdateJobDetails: Error updating job %s in repo: %v", req.JobID, err)
+		logger.FromContext(ctx).Error("UpdateJobDetails: Error updating job in repo", "job_id", req.JobID, "error", err)
 		return nil, mapRepoError(err, "updating job details")
 	}
 
+	txSkillRepo := s.skillRepo.WithTx(tx)
+	if req.Skills != nil {
+		skills := normalizeSkillNames(req.Skills)
+		if err := txSkillRepo.SetForJob(ctx, &dto.SetJobSkillsRequest{JobID: updatedJob.ID, Names: skills}); err != nil {
+			logger.FromContext(ctx).Error("UpdateJobDetails: Error setting job skills", "job_id", req.JobID, "error", err)
+			return nil, fmt.Errorf("internal error setting job skills: %w", err)
+		}
+		updatedJob.Skills = skills
+	} else if err := attachJobSkills(ctx, txSkillRepo, updatedJob); err != nil {
+		logger.FromContext(ctx).Error("UpdateJobDetails: Error loading job skills", "job_id", req.JobID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, updatedJob.ID, models.OutboxEventJobUpdated, updatedJob); err != nil {
+		logger.FromContext(ctx).Error("UpdateJobDetails: Error recording outbox event", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("UpdateJobDetails: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("UpdateJobDetails: Error committing transaction", "error", err)
 		return nil, fmt.Errorf("internal error committing changes: %w", err)
 	}
 	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, updatedJob.ID)
 	return updatedJob, nil
 }
 
 func (s *jobService) UpdateJobState(ctx context.Context, req *dto.UpdateJobStateRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.UpdateJobState")
+	defer span.End()
+
+	if req.State == models.JobStateCancelled {
+		// Cancelling also unassigns the contractor and may bill the work done, which CancelJob handles
+		rule := jobTransitionRules[jobTransition{models.JobStateOngoing, models.JobStateCancelled}]
+		return nil, fmt.Errorf("%w: cannot set state to %s directly. %s", ErrInvalidTransition, req.State, rule.note)
+	}
+
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("UpdateJobState: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("UpdateJobState: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
 
+	txJobRepo := s.jobRepo.WithTx(tx)
 	getReq := dto.GetJobByIDRequest{ID: req.JobID}
-	existingJob, err := s.jobRepo.WithTx(tx).GetByID(ctx, &getReq) // Use tx repo
+	existingJob, err := txJobRepo.GetByID(ctx, &getReq) // Use tx repo
 	if err != nil {
-		log.Printf("UpdateJobState: Error fetching job %s: %v", req.JobID, err)
+		logger.FromContext(ctx).Error("UpdateJobState: Error fetching job", "job_id", req.JobID, "error", err)
 		return nil, mapRepoError(err, "fetching job for state update")
 	}
 
 	// Authorization check
-	isEmployer := existingJob.EmployerID == req.UserID
-	isCurrentContractor := existingJob.ContractorID != nil && *existingJob.ContractorID == req.UserID
-	if !(isEmployer || isCurrentContractor) {
-		log.Printf("UpdateJobState: Forbidden attempt on job %s by user %s. Role: Employer=%t, Contractor=%t", req.JobID, req.UserID, isEmployer, isCurrentContractor)
+	party, ok := jobPartyOf(existingJob, req.UserID)
+	if !ok {
+		logger.FromContext(ctx).Warn("UpdateJobState: Forbidden attempt", "job_id", req.JobID, "user_id", req.UserID)
 		return nil, ErrForbidden
 	}
 
-	// Prevent manual state change to Ongoing - this should only happen via AcceptApplication
-	if req.State == models.JobStateOngoing && existingJob.State == models.JobStateWaiting {
-		log.Printf("UpdateJobState: Forbidden attempt to manually set job %s to Ongoing by user %s.", req.JobID, req.UserID)
-		return nil, fmt.Errorf("%w: cannot manually set state to Ongoing, use AcceptApplication", ErrInvalidTransition)
-	}
-
-	// Validation: Check state transition
-	if !isValidJobStateTransition(existingJob.State, req.State) {
+	// Validation: Check state transition against the state machine
+	if !isJobStateTransition(existingJob.State, req.State) {
 		return nil, fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, existingJob.State, req.State)
 	}
+	if !canTransitionJob(existingJob.State, req.State, party) {
+		logger.FromContext(ctx).Warn("UpdateJobState: Transition not allowed for party", "job_id", req.JobID, "user_id", req.UserID, "party", party, "from", existingJob.State, "to", req.State)
+		if isAutomaticJobTransition(existingJob.State, req.State) {
+			// e.g. Waiting to Ongoing, which only AcceptApplication does
+			rule := jobTransitionRules[jobTransition{existingJob.State, req.State}]
+			return nil, fmt.Errorf("%w: cannot manually set state to %s from %s. %s", ErrInvalidTransition, req.State, existingJob.State, rule.note)
+		}
+		return nil, fmt.Errorf("%w: a job's %s cannot move it from %s to %s", ErrForbidden, party, existingJob.State, req.State)
+	}
+	reopening := existingJob.State == models.JobStateComplete && req.State == models.JobStateOngoing
+	if reopening && existingJob.ContractorID == nil {
+		return nil, fmt.Errorf("%w: a job can only be re-opened while a contractor is assigned", ErrInvalidState)
+	}
+	if reopening {
+		// Re-opening gives the contractor one more Ongoing job, just like hiring them would
+		if err := checkOngoingJobLimit(ctx, s.userRepo.WithTx(tx), txJobRepo, *existingJob.ContractorID, s.maxOngoingJobs); err != nil {
+			return nil, err
+		}
+	}
 
 	newState := req.State
 	updateRepoReq := dto.UpdateJobRequest{
-		ID:    req.JobID,
-		State: &newState,
+		ID:      req.JobID,
+		Version: existingJob.Version, // Fails if the job changed after it was read above
+		State:   &newState,
+		UpdatedBy: req.UserID,
 	}
-	updatedJob, err := s.jobRepo.WithTx(tx).Update(ctx, &updateRepoReq) // Use tx repo
+	updatedJob, err := txJobRepo.Update(ctx, &updateRepoReq) // Use tx repo
 	if err != nil {
-		log.Printf("UpdateJobState: Error updating job state %s in repo: %v", req.JobID, err)
+		logger.FromContext(ctx).Error("UpdateJobState: Error updating job in repo", "job_id", req.JobID, "error", err)
 		return nil, mapRepoError(err, "updating job state")
 	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), updatedJob); err != nil {
+		logger.FromContext(ctx).Error("UpdateJobState: Error loading job skills", "job_id", req.JobID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, updatedJob.ID, models.OutboxEventJobStateChanged, updatedJob); err != nil {
+		logger.FromContext(ctx).Error("UpdateJobState: Error recording outbox event", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+	if reopening {
+		reopen := models.JobReopening{ContractorID: *existingJob.ContractorID}
+		if err := recordAuditEntry(ctx, s.auditRepo.WithTx(tx), req.UserID, models.AuditActionJobReopened, models.AuditEntityJob, updatedJob.ID, reopen); err != nil {
+			logger.FromContext(ctx).Error("UpdateJobState: Error recording audit entry", "job_id", req.JobID, "error", err)
+			return nil, err
+		}
+	}
 
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("UpdateJobState: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("UpdateJobState: Error committing transaction", "error", err)
 		return nil, fmt.Errorf("internal error committing changes: %w", err)
 	}
 	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, updatedJob.ID)
+	return updatedJob, nil
+}
+
+// ReopenJob moves a Complete job back to Ongoing when it needs more work. It goes through UpdateJobState,
+// so only the employer may do it, only while a contractor is assigned who is below their ongoing job
+// limit, and it is recorded in the audit log.
+func (s *jobService) ReopenJob(ctx context.Context, req *dto.ReopenJobRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ReopenJob")
+	defer span.End()
+
+	return s.UpdateJobState(ctx, &dto.UpdateJobStateRequest{JobID: req.JobID, UserID: req.UserID, State: models.JobStateOngoing})
+}
+
+// CancelJob stops an Ongoing job on behalf of its employer: the contractor is unassigned and the job moves
+// to Cancelled, which is terminal. When req.HoursWorked is set, the work done in the first interval not
+// yet invoiced is billed with a final invoice at the job's rate. The cancellation is recorded in the audit
+// log. The final invoice is nil when none was requested.
+func (s *jobService) CancelJob(ctx context.Context, req *dto.CancelJobRequest) (*models.Job, *models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "JobService.CancelJob")
+	defer span.End()
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error beginning transaction", "error", err)
+		return nil, nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txJobRepo := s.jobRepo.WithTx(tx)
+
+	existingJob, err := txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error fetching job", "job_id", req.JobID, "error", err)
+		return nil, nil, mapRepoError(err, "fetching job for cancellation")
+	}
+
+	// Authorization check
+	party, ok := jobPartyOf(existingJob, req.UserID)
+	if !ok {
+		logger.FromContext(ctx).Warn("CancelJob: Forbidden attempt", "job_id", req.JobID, "user_id", req.UserID)
+		return nil, nil, ErrForbidden
+	}
+	if !isJobStateTransition(existingJob.State, models.JobStateCancelled) {
+		return nil, nil, fmt.Errorf("%w: only Ongoing jobs can be cancelled, this one is %s", ErrInvalidTransition, existingJob.State)
+	}
+	if !canTransitionJob(existingJob.State, models.JobStateCancelled, party) {
+		logger.FromContext(ctx).Warn("CancelJob: Cancellation not allowed for party", "job_id", req.JobID, "user_id", req.UserID, "party", party)
+		return nil, nil, fmt.Errorf("%w: a job's %s cannot cancel it", ErrForbidden, party)
+	}
+	if existingJob.ContractorID == nil {
+		return nil, nil, fmt.Errorf("%w: the job has no contractor to unassign", ErrInvalidState)
+	}
+
+	var finalInvoice *models.Invoice
+	if req.HoursWorked != nil {
+		finalInvoice, err = s.createFinalInvoice(ctx, tx, existingJob, *req.HoursWorked, req.UserID)
+		if err != nil {
+			logger.FromContext(ctx).Warn("CancelJob: Error creating final invoice", "job_id", req.JobID, "hours_worked", *req.HoursWorked, "error", err)
+			return nil, nil, err
+		}
+	}
+
+	cancelled := models.JobStateCancelled
+	updatedJob, err := txJobRepo.Update(ctx, &dto.UpdateJobRequest{
+		ID:                 req.JobID,
+		Version:            existingJob.Version, // Fails if the job changed after it was read above
+		State:              &cancelled,
+		UnassignContractor: true,
+		UpdatedBy:          req.UserID,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error updating job in repo", "job_id", req.JobID, "error", err)
+		return nil, nil, mapRepoError(err, "cancelling job")
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), updatedJob); err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error loading job skills", "job_id", req.JobID, "error", err)
+		return nil, nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, updatedJob.ID, models.OutboxEventJobStateChanged, updatedJob); err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error recording outbox event", "job_id", req.JobID, "error", err)
+		return nil, nil, err
+	}
+	cancellation := models.JobCancellation{ContractorID: *existingJob.ContractorID, Reason: req.Reason}
+	if finalInvoice != nil {
+		cancellation.FinalInvoiceID = &finalInvoice.ID
+	}
+	if err := recordAuditEntry(ctx, s.auditRepo.WithTx(tx), req.UserID, models.AuditActionJobCancelled, models.AuditEntityJob, updatedJob.ID, cancellation); err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error recording audit entry", "job_id", req.JobID, "error", err)
+		return nil, nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("CancelJob: Error committing transaction", "error", err)
+		return nil, nil, fmt.Errorf("internal error committing changes: %w", err)
+	}
+	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, updatedJob.ID)
+	if finalInvoice != nil {
+		InvoicesCreatedTotal.Inc()
+	}
+	return updatedJob, finalInvoice, nil
+}
+
+// createFinalInvoice bills hoursWorked of the job's first interval not yet invoiced at the job's rate, within tx.
+// The hours can't exceed those of the interval.
+func (s *jobService) createFinalInvoice(ctx context.Context, tx pgx.Tx, job *models.Job, hoursWorked int, userID uuid.UUID) (*models.Invoice, error) {
+	txInvoiceRepo := s.invoiceRepo.WithTx(tx)
+	maxIntervalNum, err := txInvoiceRepo.GetMaxIntervalForJob(ctx, &dto.GetMaxIntervalForJobRequest{JobID: job.ID})
+	if err != nil {
+		return nil, mapRepoError(err, "getting max interval for job")
+	}
+	intervalNumber := maxIntervalNum + 1
+	if intervalNumber > maxInvoiceIntervals(job) {
+		return nil, fmt.Errorf("%w: every interval of the job is already invoiced", ErrValidation)
+	}
+	if billable := intervalHours(job, intervalNumber); hoursWorked > billable {
+		return nil, fmt.Errorf("%w: interval %d has only %d billable hours, not %d", ErrValidation, intervalNumber, billable, hoursWorked)
+	}
+
+	invoiceNumber, err := nextInvoiceNumber(ctx, txInvoiceRepo, job.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdBy := userID
+	invoice, err := txInvoiceRepo.Create(ctx, &models.Invoice{
+		ID:             uuid.New(),
+		JobID:          job.ID,
+		IntervalNumber: intervalNumber,
+		InvoiceNumber:  invoiceNumber,
+		ContractorID:   job.ContractorID, // Kept once the cancellation unassigns them, so they can still read it
+		Value:          money.Round(job.Rate*float64(hoursWorked), job.Currency),
+		Currency:       job.Currency,
+		State:          models.InvoiceStateWaiting,
+		DueDate:        time.Now().AddDate(0, 0, s.paymentTermDays),
+		CreatedBy:      &createdBy,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrConflict) {
+			return nil, fmt.Errorf("%w: %w", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("internal error saving final invoice: %w", err)
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateInvoice, invoice.ID, models.OutboxEventInvoiceCreated, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// TransferOwnership moves a job to another employer account. Only the current employer may transfer it,
+// and only before a contractor is assigned. The change is recorded in the audit log.
+func (s *jobService) TransferOwnership(ctx context.Context, req *dto.TransferJobRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.TransferOwnership")
+	defer span.End()
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("TransferOwnership: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txJobRepo := s.jobRepo.WithTx(tx)
+
+	existingJob, err := txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		logger.FromContext(ctx).Error("TransferOwnership: Error fetching job", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "fetching job for transfer")
+	}
+
+	// --- Authorization Check: ONLY Employer ---
+	if existingJob.EmployerID != req.UserID {
+		logger.FromContext(ctx).Warn("TransferOwnership: Forbidden attempt by non-employer", "job_id", req.JobID, "user_id", req.UserID)
+		return nil, ErrForbidden
+	}
+	// --- End Auth Check ---
+
+	if existingJob.ContractorID != nil {
+		return nil, fmt.Errorf("%w: jobs can't be transferred once a contractor is assigned", ErrInvalidState)
+	}
+	if req.NewEmployerID == existingJob.EmployerID {
+		return nil, fmt.Errorf("%w: the job already belongs to this employer", ErrValidation)
+	}
+
+	exists, err := s.userRepo.WithTx(tx).Exists(ctx, req.NewEmployerID)
+	if err != nil {
+		return nil, mapRepoError(err, "checking new employer")
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: new employer %s does not exist", ErrValidation, req.NewEmployerID)
+	}
+
+	transferredJob, err := txJobRepo.TransferOwnership(ctx, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrConflict) && !storage.IsRetryableConflict(err) {
+			// A contractor was assigned after the read above
+			return nil, fmt.Errorf("%w: jobs can't be transferred once a contractor is assigned", ErrInvalidState)
+		}
+		logger.FromContext(ctx).Error("TransferOwnership: Error transferring job in repo", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "transferring job")
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), transferredJob); err != nil {
+		logger.FromContext(ctx).Error("TransferOwnership: Error loading job skills", "job_id", req.JobID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+
+	transfer := models.JobOwnershipTransfer{FromEmployerID: existingJob.EmployerID, ToEmployerID: req.NewEmployerID}
+	if err := recordAuditEntry(ctx, s.auditRepo.WithTx(tx), req.UserID, models.AuditActionJobOwnershipTransferred, models.AuditEntityJob, transferredJob.ID, transfer); err != nil {
+		logger.FromContext(ctx).Error("TransferOwnership: Error recording audit entry", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, transferredJob.ID, models.OutboxEventJobUpdated, transferredJob); err != nil {
+		logger.FromContext(ctx).Error("TransferOwnership: Error recording outbox event", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("TransferOwnership: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing job transfer: %w", err)
+	}
+	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, transferredJob.ID)
+	logger.FromContext(ctx).Info("Job ownership transferred", "job_id", transferredJob.ID, "from_employer_id", transfer.FromEmployerID, "to_employer_id", transfer.ToEmployerID)
+	return transferredJob, nil
+}
+
+// ReassignContractor hands an Ongoing job over to another contractor in one transaction, keeping it
+// Ongoing, unlike cancelling it and hiring again. Only the employer may reassign a job, and the new
+// contractor must be within their limit of Ongoing jobs. The job's invoices are left as they are: they
+// keep their numbers and states and stay attributed to the prior contractor, who created them, while the
+// new contractor invoices from the next interval on. The audit entry records the last interval invoiced
+// before the change.
+func (s *jobService) ReassignContractor(ctx context.Context, req *dto.ReassignContractorRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ReassignContractor")
+	defer span.End()
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txJobRepo := s.jobRepo.WithTx(tx)
+	txUserRepo := s.userRepo.WithTx(tx)
+
+	existingJob, err := txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error fetching job", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "fetching job for reassignment")
+	}
+
+	// --- Authorization Check: ONLY Employer ---
+	if existingJob.EmployerID != req.UserID {
+		logger.FromContext(ctx).Warn("ReassignContractor: Forbidden attempt by non-employer", "job_id", req.JobID, "user_id", req.UserID)
+		return nil, ErrForbidden
+	}
+	// --- End Auth Check ---
+
+	if existingJob.State != models.JobStateOngoing || existingJob.ContractorID == nil {
+		return nil, fmt.Errorf("%w: only Ongoing jobs with a contractor can be reassigned, this one is %s", ErrInvalidState, existingJob.State)
+	}
+	previousContractorID := *existingJob.ContractorID
+	switch req.ContractorID {
+	case previousContractorID:
+		return nil, fmt.Errorf("%w: the contractor is already assigned to this job", ErrValidation)
+	case existingJob.EmployerID:
+		return nil, fmt.Errorf("%w: an employer can't be the contractor of their own job", ErrValidation)
+	}
+
+	exists, err := txUserRepo.Exists(ctx, req.ContractorID)
+	if err != nil {
+		return nil, mapRepoError(err, "checking new contractor")
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: contractor %s does not exist", ErrValidation, req.ContractorID)
+	}
+	if err := checkOngoingJobLimit(ctx, txUserRepo, txJobRepo, req.ContractorID, s.maxOngoingJobs); err != nil {
+		return nil, err
+	}
+
+	lastInvoicedInterval, err := s.invoiceRepo.WithTx(tx).GetMaxIntervalForJob(ctx, &dto.GetMaxIntervalForJobRequest{JobID: req.JobID})
+	if err != nil {
+		return nil, mapRepoError(err, "getting max interval for job")
+	}
+
+	reassignedJob, err := txJobRepo.Update(ctx, &dto.UpdateJobRequest{
+		ID:           req.JobID,
+		Version:      existingJob.Version, // Fails if the job changed after it was read above
+		ContractorID: &req.ContractorID,
+		UpdatedBy:    req.UserID,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error updating job in repo", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "reassigning contractor")
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), reassignedJob); err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error loading job skills", "job_id", req.JobID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+
+	reassignment := models.JobContractorReassignment{
+		FromContractorID:     previousContractorID,
+		ToContractorID:       req.ContractorID,
+		Reason:               req.Reason,
+		LastInvoicedInterval: lastInvoicedInterval,
+	}
+	if err := recordAuditEntry(ctx, s.auditRepo.WithTx(tx), req.UserID, models.AuditActionJobContractorReassigned, models.AuditEntityJob, reassignedJob.ID, reassignment); err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error recording audit entry", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, reassignedJob.ID, models.OutboxEventJobUpdated, reassignedJob); err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error recording outbox event", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("ReassignContractor: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing contractor reassignment: %w", err)
+	}
+	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, reassignedJob.ID)
+	logger.FromContext(ctx).Info("Job contractor reassigned", "job_id", reassignedJob.ID, "from_contractor_id", previousContractorID, "to_contractor_id", req.ContractorID)
+	return reassignedJob, nil
+}
+
+// SetAutoInvoice turns automatic periodic invoicing of the job on or off. Either side of the job may change
+// it until the job is Complete. Enabling it, or changing the cadence, schedules the first automatic invoice
+// one cadence from now; the background invoicer only acts on it while the job is Ongoing.
+func (s *jobService) SetAutoInvoice(ctx context.Context, req *dto.SetJobAutoInvoiceRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.SetAutoInvoice")
+	defer span.End()
+
+	// --- Transaction Start ---
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("SetAutoInvoice: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txJobRepo := s.jobRepo.WithTx(tx)
+
+	existingJob, err := txJobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		logger.FromContext(ctx).Error("SetAutoInvoice: Error fetching job", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "fetching job for auto-invoice update")
+	}
+
+	if _, ok := jobPartyOf(existingJob, req.UserID); !ok {
+		logger.FromContext(ctx).Warn("SetAutoInvoice: Forbidden attempt", "job_id", req.JobID, "user_id", req.UserID)
+		return nil, ErrForbidden
+	}
+	if existingJob.State == models.JobStateComplete || existingJob.State == models.JobStateArchived {
+		return nil, fmt.Errorf("%w: automatic invoicing can't be changed on a %s job", ErrInvalidState, existingJob.State)
+	}
+
+	if req.Cadence == "" {
+		req.Cadence = existingJob.AutoInvoiceCadence
+	}
+	req.NextAutoInvoiceAt = nil
+	if *req.Enabled {
+		if existingJob.AutoInvoice && req.Cadence == existingJob.AutoInvoiceCadence && existingJob.NextAutoInvoiceAt != nil {
+			req.NextAutoInvoiceAt = existingJob.NextAutoInvoiceAt // Unchanged; keep the current schedule
+		} else {
+			nextAt := req.Cadence.Next(time.Now())
+			req.NextAutoInvoiceAt = &nextAt
+		}
+	}
+
+	updatedJob, err := txJobRepo.SetAutoInvoice(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("SetAutoInvoice: Error updating job", "job_id", req.JobID, "error", err)
+		return nil, mapRepoError(err, "setting auto-invoice")
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), updatedJob); err != nil {
+		logger.FromContext(ctx).Error("SetAutoInvoice: Error loading job skills", "job_id", req.JobID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, updatedJob.ID, models.OutboxEventJobUpdated, updatedJob); err != nil {
+		logger.FromContext(ctx).Error("SetAutoInvoice: Error recording outbox event", "job_id", req.JobID, "error", err)
+		return nil, err
+	}
+
+	// --- Commit Transaction ---
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("SetAutoInvoice: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing changes: %w", err)
+	}
+	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, updatedJob.ID)
 	return updatedJob, nil
 }
 
 func (s *jobService) DeleteJob(ctx context.Context, req *dto.DeleteJobRequest) error {
+	ctx, span := tracing.Start(ctx, "JobService.DeleteJob")
+	defer span.End()
+
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("DeleteJob: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("DeleteJob: Error beginning transaction", "error", err)
 		return fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
@@ -186,32 +814,346 @@ func (s *jobService) DeleteJob(ctx context.Context, req *dto.DeleteJobRequest) e
 	getReq := dto.GetJobByIDRequest{ID: req.ID}
 	existingJob, err := s.jobRepo.WithTx(tx).GetByID(ctx, &getReq) // Use tx repo
 	if err != nil {
-		log.Printf("DeleteJob: Error fetching job %s for delete check: %v", req.ID, err)
+		logger.FromContext(ctx).Error("DeleteJob: Error fetching job for delete check", "job_id", req.ID, "error", err)
 		return mapRepoError(err, "fetching job for delete check")
 	}
 
 	// Authorization Check
 	if existingJob.EmployerID != req.UserID {
-		log.Printf("DeleteJob: Forbidden attempt on job %s by non-employer user %s", req.ID, req.UserID)
+		logger.FromContext(ctx).Warn("DeleteJob: Forbidden attempt by non-employer", "job_id", req.ID, "user_id", req.UserID)
 		return ErrForbidden
 	}
-	if !(existingJob.State == models.JobStateWaiting && existingJob.ContractorID == nil) {
-		log.Printf("DeleteJob: Invalid state attempt on job %s. State: %s, Contractor: %v", req.ID, existingJob.State, existingJob.ContractorID)
-		return ErrInvalidState
+	blockers, err := s.jobDeleteBlockers(ctx, s.invoiceRepo.WithTx(tx), existingJob)
+	if err != nil {
+		return err
+	}
+	if len(blockers) > 0 {
+		logger.FromContext(ctx).Warn("DeleteJob: Invalid state attempt", "job_id", req.ID, "job_state", existingJob.State, "contractor_id", existingJob.ContractorID, "blocker", blockers[0].Code)
+		return fmt.Errorf("%w: %s", ErrInvalidState, blockers[0].Message)
 	}
 
-	deleteReq := dto.DeleteJobRequest{ID: req.ID}
+	deleteReq := dto.DeleteJobRequest{ID: req.ID, UserID: req.UserID}
 	err = s.jobRepo.WithTx(tx).Delete(ctx, &deleteReq) // Use tx repo
 	if err != nil {
-		log.Printf("DeleteJob: Error deleting job %s in repo: %v", req.ID, err)
+		logger.FromContext(ctx).Error("DeleteJob: Error deleting job in repo", "job_id", req.ID, "error", err)
 		return mapRepoError(err, "deleting job")
 	}
 
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, existingJob.ID, models.OutboxEventJobDeleted, existingJob); err != nil {
+		logger.FromContext(ctx).Error("DeleteJob: Error recording outbox event", "job_id", req.ID, "error", err)
+		return err
+	}
+
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("DeleteJob: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("DeleteJob: Error committing transaction", "error", err)
 		return fmt.Errorf("internal error committing job deletion: %w", err)
 	}
 	// --- End Transaction ---
+	invalidateCached(ctx, s.jobRepo, existingJob.ID)
 	return nil
 }
+
+// CanDeleteJob runs DeleteJob's checks without deleting anything and returns every reason the job
+// can't be deleted; an empty list means DeleteJob would succeed. Only the employer may ask.
+func (s *jobService) CanDeleteJob(ctx context.Context, req *dto.DeleteJobRequest) ([]models.JobDeleteBlocker, error) {
+	ctx, span := tracing.Start(ctx, "JobService.CanDeleteJob")
+	defer span.End()
+
+	job, err := s.jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.ID})
+	if err != nil {
+		logger.FromContext(ctx).Error("CanDeleteJob: Error fetching job", "job_id", req.ID, "error", err)
+		return nil, mapRepoError(err, "fetching job for delete check")
+	}
+	if job.EmployerID != req.UserID {
+		logger.FromContext(ctx).Warn("CanDeleteJob: Forbidden attempt by non-employer", "job_id", req.ID, "user_id", req.UserID)
+		return nil, ErrForbidden
+	}
+	return s.jobDeleteBlockers(ctx, s.invoiceRepo, job)
+}
+
+// jobDeleteBlockers lists every reason job can't be deleted, in a stable order.
+func (s *jobService) jobDeleteBlockers(ctx context.Context, invoiceRepo storage.InvoiceRepository, job *models.Job) ([]models.JobDeleteBlocker, error) {
+	blockers := []models.JobDeleteBlocker{}
+	if job.State != models.JobStateWaiting {
+		blockers = append(blockers, models.JobDeleteBlocker{Code: models.JobDeleteBlockerInvalidState, Message: fmt.Sprintf("job is %s; only Waiting jobs can be deleted", job.State)})
+	}
+	if job.ContractorID != nil {
+		blockers = append(blockers, models.JobDeleteBlocker{Code: models.JobDeleteBlockerContractorAssigned, Message: "a contractor is assigned to the job"})
+	}
+	invoiceCount, err := invoiceRepo.CountByJob(ctx, &dto.ListInvoicesByJobRequest{JobID: job.ID})
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error counting job invoices", "job_id", job.ID, "error", err)
+		return nil, mapRepoError(err, "counting job invoices")
+	}
+	if invoiceCount > 0 {
+		blockers = append(blockers, models.JobDeleteBlocker{Code: models.JobDeleteBlockerInvoicesExist, Message: fmt.Sprintf("the job has %d invoice(s)", invoiceCount)})
+	}
+	return blockers, nil
+}
+
+// RestoreJob undoes a soft delete. Admin-only; the role check happens in the route middleware.
+func (s *jobService) RestoreJob(ctx context.Context, req *dto.RestoreJobRequest) (*models.Job, error) {
+	ctx, span := tracing.Start(ctx, "JobService.RestoreJob")
+	defer span.End()
+
+	job, err := s.jobRepo.Restore(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error restoring job", "job_id", req.ID, "error", err)
+		return nil, mapRepoError(err, "restoring job")
+	}
+	if err := attachJobSkills(ctx, s.skillRepo, job); err != nil {
+		logger.FromContext(ctx).Error("JobService: Error loading job skills", "job_id", req.ID, "error", err)
+		return nil, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	logger.FromContext(ctx).Info("Job restored", "job_id", req.ID)
+	return job, nil
+}
+
+// ArchiveStaleJobs moves Complete jobs that haven't changed since req.CompletedBefore to Archived, up to
+// req.Limit of them. Each job is archived in its own transaction with the same transition check and
+// outbox event as UpdateJobState. A job that can't be archived (e.g. it changed after it was listed)
+// is logged and skipped; it is picked up again by a later sweep if it is still stale.
+func (s *jobService) ArchiveStaleJobs(ctx context.Context, req *dto.ArchiveStaleJobsRequest) (int, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ArchiveStaleJobs")
+	defer span.End()
+
+	ids, err := s.jobRepo.ListStaleCompletedIDs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("ArchiveStaleJobs: Error listing stale jobs", "error", err)
+		return 0, mapRepoError(err, "listing stale completed jobs")
+	}
+
+	archived := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return archived, ctx.Err()
+		}
+		if err := s.archiveJob(ctx, id); err != nil {
+			logger.FromContext(ctx).Warn("ArchiveStaleJobs: Skipping job", "job_id", id, "error", err)
+			continue
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// archiveJob transitions a single job to Archived, failing if it is no longer in a state that allows it.
+func (s *jobService) archiveJob(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	existingJob, err := s.jobRepo.WithTx(tx).GetByID(ctx, &dto.GetJobByIDRequest{ID: id})
+	if err != nil {
+		return mapRepoError(err, "fetching job for archiving")
+	}
+	if !canTransitionJob(existingJob.State, models.JobStateArchived, models.JobPartySystem) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, existingJob.State, models.JobStateArchived)
+	}
+
+	archivedState := models.JobStateArchived
+	updatedJob, err := s.jobRepo.WithTx(tx).Update(ctx, &dto.UpdateJobRequest{
+		ID:      id,
+		Version: existingJob.Version, // Fails if the job changed after it was read above
+		State:   &archivedState,
+		UpdatedBy: models.SystemActorID,
+	})
+	if err != nil {
+		return mapRepoError(err, "archiving job")
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), updatedJob); err != nil {
+		return fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	if err := recordOutboxEvent(ctx, s.outboxRepo.WithTx(tx), models.OutboxAggregateJob, updatedJob.ID, models.OutboxEventJobStateChanged, updatedJob); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("internal error committing changes: %w", err)
+	}
+	invalidateCached(ctx, s.jobRepo, id)
+	return nil
+}
+
+// PurgeArchivedJobs permanently deletes jobs that have been Archived since before req.ArchivedBefore, up to
+// req.Limit of them, along with their invoices, in one transaction: invoices first, then the jobs, whose
+// applications, saves and skills cascade. Jobs with an invoice that isn't Paid are left alone. Ratings
+// stay, detached from the job, so contractors' averages don't change. Audit log entries aren't tied to
+// the rows by a foreign key, so the history of purged jobs is kept.
+func (s *jobService) PurgeArchivedJobs(ctx context.Context, req *dto.PurgeArchivedJobsRequest) (*dto.PurgeArchivedJobsResult, error) {
+	ctx, span := tracing.Start(ctx, "JobService.PurgeArchivedJobs")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("PurgeArchivedJobs: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Rollback if anything fails
+
+	txJobRepo := s.jobRepo.WithTx(tx)
+	ids, err := txJobRepo.ListPurgeableArchivedIDs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("PurgeArchivedJobs: Error listing purgeable jobs", "error", err)
+		return nil, mapRepoError(err, "listing purgeable archived jobs")
+	}
+	result := &dto.PurgeArchivedJobsResult{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	if result.Invoices, err = s.invoiceRepo.WithTx(tx).DeletePaidByJobs(ctx, ids); err != nil {
+		logger.FromContext(ctx).Error("PurgeArchivedJobs: Error deleting invoices", "error", err)
+		return nil, mapRepoError(err, "deleting invoices of archived jobs")
+	}
+	if result.Jobs, err = txJobRepo.Purge(ctx, ids); err != nil {
+		logger.FromContext(ctx).Error("PurgeArchivedJobs: Error deleting jobs", "error", err)
+		return nil, mapRepoError(err, "purging archived jobs")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("PurgeArchivedJobs: Error committing transaction", "error", err)
+		return nil, fmt.Errorf("internal error committing purge: %w", err)
+	}
+	invalidateCached(ctx, s.jobRepo, ids...)
+	return result, nil
+}
+
+// GetJobStateMachine lists the allowed job state transitions and who may make each one.
+func (s *jobService) GetJobStateMachine(ctx context.Context) *models.JobStateMachine {
+	return jobStateMachine()
+}
+
+// GetEmployerStats summarizes the employer's jobs and invoices with aggregate queries, so no rows are
+// loaded into memory. Only the employer themself may see their stats.
+func (s *jobService) GetEmployerStats(ctx context.Context, req *dto.EmployerStatsRequest) (*models.EmployerStats, error) {
+	ctx, span := tracing.Start(ctx, "JobService.GetEmployerStats")
+	defer span.End()
+
+	if req.UserID != req.EmployerID {
+		logger.FromContext(ctx).Warn("GetEmployerStats: Forbidden attempt", "employer_id", req.EmployerID, "user_id", req.UserID)
+		return nil, fmt.Errorf("%w: can only view your own employer stats", ErrForbidden)
+	}
+
+	// Both aggregates read the same snapshot so the job counts and invoice total agree
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("GetEmployerStats: Error beginning transaction", "error", err)
+		return nil, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+
+	stats, err := s.jobRepo.WithTx(tx).GetEmployerStats(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error aggregating employer jobs", "employer_id", req.EmployerID, "error", err)
+		return nil, fmt.Errorf("internal error aggregating employer jobs: %w", err)
+	}
+	stats.TotalInvoiced, err = s.invoiceRepo.WithTx(tx).SumValueByEmployer(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error summing employer invoices", "employer_id", req.EmployerID, "error", err)
+		return nil, fmt.Errorf("internal error summing employer invoices: %w", err)
+	}
+	return stats, nil
+}
+
+// validateJobTerms rejects terms a job can't be invoiced on: a rate or duration that isn't positive,
+// or an invoice interval that isn't positive or is longer than the whole job.
+func validateJobTerms(rate float64, duration, invoiceInterval int) error {
+	switch {
+	case rate <= 0:
+		return fmt.Errorf("%w: rate must be positive", ErrValidation)
+	case duration <= 0:
+		return fmt.Errorf("%w: duration must be positive", ErrValidation)
+	case invoiceInterval <= 0:
+		return fmt.Errorf("%w: invoice_interval must be positive", ErrValidation)
+	case invoiceInterval > duration:
+		return fmt.Errorf("%w: invoice_interval (%d hours) must not be greater than duration (%d hours)", ErrValidation, invoiceInterval, duration)
+	}
+	return nil
+}
+
+// resolveCurrency returns the ISO 4217 code a new job is priced in: code normalized, or defaultCurrency
+// if none was given. Unsupported codes are rejected.
+func resolveCurrency(code, defaultCurrency string) (string, error) {
+	if strings.TrimSpace(code) == "" {
+		return defaultCurrency, nil
+	}
+	normalized, err := money.Normalize(code)
+	if err != nil {
+		return "", fmt.Errorf("%w: currency %q is not a supported ISO 4217 code", ErrValidation, code)
+	}
+	return normalized, nil
+}
+
+// validateApplicationDeadline rejects a deadline that has already passed; a nil deadline is always valid.
+func validateApplicationDeadline(deadline *time.Time) error {
+	if deadline != nil && !deadline.After(time.Now()) {
+		return fmt.Errorf("%w: application deadline must be in the future", ErrValidation)
+	}
+	return nil
+}
+
+// SaveJob bookmarks a job for the user. Saving an already saved job succeeds without changes.
+func (s *jobService) SaveJob(ctx context.Context, req *dto.SaveJobRequest) error {
+	ctx, span := tracing.Start(ctx, "JobService.SaveJob")
+	defer span.End()
+
+	// Deleted jobs can't be saved; Exists doesn't count them
+	exists, err := s.jobRepo.Exists(ctx, req.JobID)
+	if err != nil {
+		return mapRepoError(err, "checking job to save")
+	}
+	if !exists {
+		return fmt.Errorf("%w: job %s", ErrNotFound, req.JobID)
+	}
+
+	if err := s.savedJobRepo.Save(ctx, req); err != nil {
+		return mapRepoError(err, "saving job")
+	}
+	return nil
+}
+
+// UnsaveJob removes the user's bookmark of a job. Removing a job that isn't saved succeeds without changes.
+func (s *jobService) UnsaveJob(ctx context.Context, req *dto.UnsaveJobRequest) error {
+	ctx, span := tracing.Start(ctx, "JobService.UnsaveJob")
+	defer span.End()
+
+	if err := s.savedJobRepo.Delete(ctx, req); err != nil {
+		return mapRepoError(err, "unsaving job")
+	}
+	return nil
+}
+
+// ListSavedJobs lists the user's saved jobs that are still available. Jobs that have since been
+// filled, closed or deleted stay saved but are left out.
+func (s *jobService) ListSavedJobs(ctx context.Context, req *dto.ListSavedJobsRequest) ([]models.Job, int, error) {
+	ctx, span := tracing.Start(ctx, "JobService.ListSavedJobs")
+	defer span.End()
+
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListSavedJobs: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txSavedJobRepo := s.savedJobRepo.WithTx(tx)
+
+	jobs, err := txSavedJobRepo.ListJobs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error listing saved jobs", "user_id", req.UserID, "error", err)
+		return nil, 0, fmt.Errorf("internal error listing saved jobs: %w", err)
+	}
+	total, err := txSavedJobRepo.CountJobs(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("JobService: Error counting saved jobs", "user_id", req.UserID, "error", err)
+		return nil, 0, fmt.Errorf("internal error counting saved jobs: %w", err)
+	}
+	if err := attachJobSkills(ctx, s.skillRepo.WithTx(tx), jobPointers(jobs)...); err != nil {
+		logger.FromContext(ctx).Error("JobService: Error loading skills of saved jobs", "user_id", req.UserID, "error", err)
+		return nil, 0, fmt.Errorf("internal error loading job skills: %w", err)
+	}
+	return jobs, total, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"slices"
+
+	"go-api-template/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// jobStates lists every job state in lifecycle order.
+var jobStates = []models.JobState{models.JobStateWaiting, models.JobStateOngoing, models.JobStateComplete, models.JobStateArchived, models.JobStateCancelled}
+
+// jobStateTransitions lists the states a job may move to from each state, whoever makes the move.
+// A state with no entry is terminal.
+var jobStateTransitions = map[models.JobState][]models.JobState{
+	models.JobStateWaiting:  {models.JobStateOngoing, models.JobStateArchived},
+	models.JobStateOngoing:  {models.JobStateComplete, models.JobStateCancelled},
+	models.JobStateComplete: {models.JobStateOngoing, models.JobStateArchived},
+}
+
+// jobTransition is an edge of the job state machine.
+type jobTransition struct {
+	from, to models.JobState
+}
+
+// jobTransitionRule says who may make a transition.
+type jobTransitionRule struct {
+	allowedBy []models.JobParty
+	note      string
+}
+
+// jobTransitionRules holds the rule of every transition in jobStateTransitions.
+var jobTransitionRules = map[jobTransition]jobTransitionRule{
+	{models.JobStateWaiting, models.JobStateOngoing}: {
+		allowedBy: []models.JobParty{models.JobPartySystem},
+		note:      "Happens when the employer accepts an application",
+	},
+	{models.JobStateWaiting, models.JobStateArchived}: {
+		allowedBy: []models.JobParty{models.JobPartyEmployer, models.JobPartySystem},
+	},
+	{models.JobStateOngoing, models.JobStateComplete}: {
+		allowedBy: []models.JobParty{models.JobPartyEmployer, models.JobPartyContractor},
+	},
+	{models.JobStateOngoing, models.JobStateCancelled}: {
+		allowedBy: []models.JobParty{models.JobPartyEmployer},
+		note:      "Only through cancellation, which unassigns the contractor and can bill the work done so far",
+	},
+	{models.JobStateComplete, models.JobStateOngoing}: {
+		allowedBy: []models.JobParty{models.JobPartyEmployer},
+		note:      "Re-opens the job for more work; only while a contractor is still assigned",
+	},
+	{models.JobStateComplete, models.JobStateArchived}: {
+		allowedBy: []models.JobParty{models.JobPartyEmployer, models.JobPartyContractor, models.JobPartySystem},
+		note:      "Also done automatically once a job has stayed Complete long enough",
+	},
+}
+
+// isJobStateTransition reports whether the state machine has an edge from one state to the other.
+func isJobStateTransition(from, to models.JobState) bool {
+	return slices.Contains(jobStateTransitions[from], to)
+}
+
+// canTransitionJob reports whether party may move a job from one state to the other.
+func canTransitionJob(from, to models.JobState, party models.JobParty) bool {
+	if !isJobStateTransition(from, to) {
+		return false
+	}
+	return slices.Contains(jobTransitionRules[jobTransition{from, to}].allowedBy, party)
+}
+
+// isAutomaticJobTransition reports whether only the API itself makes the transition, so no user may request it.
+func isAutomaticJobTransition(from, to models.JobState) bool {
+	if !isJobStateTransition(from, to) {
+		return false
+	}
+	return !slices.ContainsFunc(jobTransitionRules[jobTransition{from, to}].allowedBy, func(party models.JobParty) bool {
+		return party != models.JobPartySystem
+	})
+}
+
+// jobPartyOf returns how the user is related to the job; false if they are neither its employer nor its contractor.
+func jobPartyOf(job *models.Job, userID uuid.UUID) (models.JobParty, bool) {
+	switch {
+	case job.EmployerID == userID:
+		return models.JobPartyEmployer, true
+	case job.ContractorID != nil && *job.ContractorID == userID:
+		return models.JobPartyContractor, true
+	default:
+		return "", false
+	}
+}
+
+// jobStateMachine lists the job states and every allowed transition, ordered by source then target state.
+func jobStateMachine() *models.JobStateMachine {
+	transitions := []models.JobStateTransition{}
+	for _, from := range jobStates {
+		for _, to := range jobStateTransitions[from] {
+			rule := jobTransitionRules[jobTransition{from, to}]
+			transitions = append(transitions, models.JobStateTransition{
+				From:      from,
+				To:        to,
+				AllowedBy: slices.Clone(rule.allowedBy),
+				Note:      rule.note,
+			})
+		}
+	}
+	return &models.JobStateMachine{States: slices.Clone(jobStates), Transitions: transitions}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"go-api-template/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionJob(t *testing.T) {
+	// Every (from, to) pair, including staying put; pairs not listed must be refused to everyone
+	allowed := map[jobTransition][]models.JobParty{
+		{models.JobStateWaiting, models.JobStateOngoing}:   {models.JobPartySystem},
+		{models.JobStateWaiting, models.JobStateArchived}:  {models.JobPartyEmployer, models.JobPartySystem},
+		{models.JobStateOngoing, models.JobStateComplete}:  {models.JobPartyEmployer, models.JobPartyContractor},
+		{models.JobStateOngoing, models.JobStateCancelled}: {models.JobPartyEmployer},
+		{models.JobStateComplete, models.JobStateOngoing}:  {models.JobPartyEmployer},
+		{models.JobStateComplete, models.JobStateArchived}: {models.JobPartyEmployer, models.JobPartyContractor, models.JobPartySystem},
+	}
+
+	for _, from := range jobStates {
+		for _, to := range jobStates {
+			for _, party := range []models.JobParty{models.JobPartyEmployer, models.JobPartyContractor, models.JobPartySystem} {
+				t.Run(fmt.Sprintf("%s_%s_%s", from, to, party), func(t *testing.T) {
+					expected := false
+					for _, p := range allowed[jobTransition{from, to}] {
+						expected = expected || p == party
+					}
+					assert.Equal(t, expected, canTransitionJob(from, to, party))
+				})
+			}
+			assert.Equal(t, len(allowed[jobTransition{from, to}]) > 0, isJobStateTransition(from, to), "%s to %s", from, to)
+		}
+	}
+}
+
+func TestIsAutomaticJobTransition(t *testing.T) {
+	assert.True(t, isAutomaticJobTransition(models.JobStateWaiting, models.JobStateOngoing))
+	assert.False(t, isAutomaticJobTransition(models.JobStateWaiting, models.JobStateArchived))
+	assert.False(t, isAutomaticJobTransition(models.JobStateOngoing, models.JobStateComplete))
+	assert.False(t, isAutomaticJobTransition(models.JobStateComplete, models.JobStateOngoing))
+	assert.False(t, isAutomaticJobTransition(models.JobStateOngoing, models.JobStateCancelled))
+	assert.False(t, isAutomaticJobTransition(models.JobStateOngoing, models.JobStateWaiting), "not a transition at all")
+}
+
+func TestJobStateMachine_TablesAgree(t *testing.T) {
+	for from, targets := range jobStateTransitions {
+		assert.Contains(t, jobStates, from)
+		for _, to := range targets {
+			rule, ok := jobTransitionRules[jobTransition{from, to}]
+			if assert.True(t, ok, "%s to %s has no rule", from, to) {
+				assert.NotEmpty(t, rule.allowedBy, "%s to %s can't be made by anyone", from, to)
+			}
+		}
+	}
+	for transition := range jobTransitionRules {
+		assert.True(t, isJobStateTransition(transition.from, transition.to), "rule for %s to %s, which isn't a transition", transition.from, transition.to)
+	}
+}
+
+func TestJobStateMachine_ListsTransitionsInOrder(t *testing.T) {
+	machine := jobStateMachine()
+
+	assert.Equal(t, jobStates, machine.States)
+	require.Len(t, machine.Transitions, 6)
+	var edges []string
+	for _, transition := range machine.Transitions {
+		edges = append(edges, fmt.Sprintf("%s->%s", transition.From, transition.To))
+	}
+	assert.Equal(t, []string{"Waiting->Ongoing", "Waiting->Archived", "Ongoing->Complete", "Ongoing->Cancelled", "Complete->Ongoing", "Complete->Archived"}, edges)
+	assert.NotEmpty(t, machine.Transitions[0].Note)
+
+	// Callers get copies, so they can't change the rules
+	machine.Transitions[0].AllowedBy[0] = models.JobPartyEmployer
+	assert.True(t, isAutomaticJobTransition(models.JobStateWaiting, models.JobStateOngoing))
+}
+
+func TestJobPartyOf(t *testing.T) {
+	employerID, contractorID := uuid.New(), uuid.New()
+	job := &models.Job{EmployerID: employerID, ContractorID: &contractorID}
+
+	party, ok := jobPartyOf(job, employerID)
+	assert.True(t, ok)
+	assert.Equal(t, models.JobPartyEmployer, party)
+
+	party, ok = jobPartyOf(job, contractorID)
+	assert.True(t, ok)
+	assert.Equal(t, models.JobPartyContractor, party)
+
+	_, ok = jobPartyOf(job, uuid.New())
+	assert.False(t, ok)
+
+	_, ok = jobPartyOf(&models.Job{EmployerID: employerID}, contractorID)
+	assert.False(t, ok, "nobody is the contractor of an unassigned job")
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisLoginFailuresPrefix = "login_failures:"
+	RedisLoginLockPrefix     = "login_lock:"
+)
+
+// loginLockout locks an email out of Login after threshold consecutive failed attempts, for cooldown.
+// Unlike the rate limiter it is sticky: the lock holds even for correct credentials until it expires.
+// The failure count and the lock's expiry time are kept in Redis, keyed by the normalized email.
+type loginLockout struct {
+	redisClient *redis.Client
+	threshold   int
+	cooldown    time.Duration
+	now         func() time.Time // Replaced in tests
+}
+
+func newLoginLockout(redisClient *redis.Client, threshold int, cooldown time.Duration) *loginLockout {
+	return &loginLockout{redisClient: redisClient, threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// enabled reports whether lockout is configured; a zero threshold or cooldown turns it off.
+func (l *loginLockout) enabled() bool {
+	return l.redisClient != nil && l.threshold > 0 && l.cooldown > 0
+}
+
+func normalizeLockoutEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// lockedUntil returns when the lock on email expires, or the zero time if it isn't locked.
+func (l *loginLockout) lockedUntil(ctx context.Context, email string) (time.Time, error) {
+	if !l.enabled() {
+		return time.Time{}, nil
+	}
+	val, err := l.redisClient.Get(ctx, RedisLoginLockPrefix+normalizeLockoutEmail(email)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	millis, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	until := time.UnixMilli(millis)
+	if !l.now().Before(until) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+// recordFailure counts a failed attempt for email and locks it once the threshold is reached.
+// Reports whether this failure engaged the lock.
+func (l *loginLockout) recordFailure(ctx context.Context, email string) (bool, error) {
+	if !l.enabled() {
+		return false, nil
+	}
+	email = normalizeLockoutEmail(email)
+	failuresKey := RedisLoginFailuresPrefix + email
+
+	var incr *redis.IntCmd
+	_, err := l.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, failuresKey)
+		pipe.PExpire(ctx, failuresKey, l.cooldown) // Stale failures are forgotten after a quiet cooldown
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if incr.Val() < int64(l.threshold) {
+		return false, nil
+	}
+
+	until := l.now().Add(l.cooldown)
+	_, err = l.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, RedisLoginLockPrefix+email, strconv.FormatInt(until.UnixMilli(), 10), l.cooldown)
+		pipe.Del(ctx, failuresKey) // The count starts over once the lock is released
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reset clears the failure count for email after a successful login.
+func (l *loginLockout) reset(ctx context.Context, email string) error {
+	if !l.enabled() {
+		return nil
+	}
+	return l.redisClient.Del(ctx, RedisLoginFailuresPrefix+normalizeLockoutEmail(email)).Err()
+}
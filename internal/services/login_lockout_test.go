@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLockout builds a lockout backed by a Redis mock with its clock pinned to now.
+func newTestLockout(threshold int, cooldown time.Duration, now time.Time) (*loginLockout, redismock.ClientMock) {
+	redisClient, mock := redismock.NewClientMock()
+	lockout := newLoginLockout(redisClient, threshold, cooldown)
+	lockout.now = func() time.Time { return now }
+	return lockout, mock
+}
+
+func TestLoginLockout_EngagesAtThreshold(t *testing.T) {
+	ctx := context.Background()
+	cooldown := 15 * time.Minute
+	now := time.UnixMilli(1_700_000_000_000)
+	lockout, mock := newTestLockout(3, cooldown, now)
+
+	// The email is normalized, so differently cased attempts count against the same account
+	failuresKey := RedisLoginFailuresPrefix + "user@example.com"
+	lockKey := RedisLoginLockPrefix + "user@example.com"
+	until := now.Add(cooldown)
+
+	for i := int64(1); i <= 3; i++ {
+		mock.ExpectIncr(failuresKey).SetVal(i)
+		mock.ExpectPExpire(failuresKey, cooldown).SetVal(true)
+	}
+	mock.ExpectSet(lockKey, strconv.FormatInt(until.UnixMilli(), 10), cooldown).SetVal("OK")
+	mock.ExpectDel(failuresKey).SetVal(1)
+
+	var engaged []bool
+	for _, email := range []string{"user@example.com", "User@Example.com", " user@example.com "} {
+		locked, err := lockout.recordFailure(ctx, email)
+		require.NoError(t, err)
+		engaged = append(engaged, locked)
+	}
+	assert.Equal(t, []bool{false, false, true}, engaged, "Only the failure reaching the threshold should lock")
+
+	mock.ExpectGet(lockKey).SetVal(strconv.FormatInt(until.UnixMilli(), 10))
+	lockedUntil, err := lockout.lockedUntil(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, lockedUntil.Equal(until))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginLockout_LockedUntil(t *testing.T) {
+	ctx := context.Background()
+	cooldown := 15 * time.Minute
+	lockedAt := time.UnixMilli(1_700_000_000_000)
+	until := lockedAt.Add(cooldown)
+	lockKey := RedisLoginLockPrefix + "user@example.com"
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		setup      func(mock redismock.ClientMock)
+		expectLock bool
+	}{
+		{
+			name:       "NoLock",
+			now:        lockedAt,
+			setup:      func(mock redismock.ClientMock) { mock.ExpectGet(lockKey).RedisNil() },
+			expectLock: false,
+		},
+		{
+			name: "DuringCooldown",
+			now:  until.Add(-time.Second),
+			setup: func(mock redismock.ClientMock) {
+				mock.ExpectGet(lockKey).SetVal(strconv.FormatInt(until.UnixMilli(), 10))
+			},
+			expectLock: true,
+		},
+		{
+			// Redis may not have evicted the key yet; the stored expiry still releases the lock
+			name: "AfterExpiry",
+			now:  until,
+			setup: func(mock redismock.ClientMock) {
+				mock.ExpectGet(lockKey).SetVal(strconv.FormatInt(until.UnixMilli(), 10))
+			},
+			expectLock: false,
+		},
+		{
+			name:       "KeyExpired",
+			now:        until.Add(time.Minute),
+			setup:      func(mock redismock.ClientMock) { mock.ExpectGet(lockKey).RedisNil() },
+			expectLock: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockout, mock := newTestLockout(3, cooldown, tt.now)
+			tt.setup(mock)
+
+			lockedUntil, err := lockout.lockedUntil(ctx, "user@example.com")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectLock, !lockedUntil.IsZero())
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLoginLockout_ResetClearsFailures(t *testing.T) {
+	lockout, mock := newTestLockout(3, time.Minute, time.Now())
+	mock.ExpectDel(RedisLoginFailuresPrefix + "user@example.com").SetVal(1)
+
+	require.NoError(t, lockout.reset(context.Background(), "User@example.com"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginLockout_DisabledSkipsRedis(t *testing.T) {
+	ctx := context.Background()
+	for _, lockout := range []*loginLockout{
+		newLoginLockout(nil, 3, time.Minute),
+		func() *loginLockout { l, _ := newTestLockout(0, time.Minute, time.Now()); return l }(),
+	} {
+		locked, err := lockout.recordFailure(ctx, "user@example.com")
+		require.NoError(t, err)
+		assert.False(t, locked)
+
+		lockedUntil, err := lockout.lockedUntil(ctx, "user@example.com")
+		require.NoError(t, err)
+		assert.True(t, lockedUntil.IsZero())
+		require.NoError(t, lockout.reset(ctx, "user@example.com"))
+	}
+}
@@ -0,0 +1,21 @@
+package services
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Domain event counters. They are incremented only after the surrounding write has been committed.
+var (
+	JobsCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "jobs_created_total",
+		Help: "Total number of jobs created.",
+	})
+
+	InvoicesCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "invoices_created_total",
+		Help: "Total number of invoices created.",
+	})
+)
+
+// MetricsCollectors returns the domain collectors, for registration by the server.
+func MetricsCollectors() []prometheus.Collector {
+	return []prometheus.Collector{JobsCreatedTotal, InvoicesCreatedTotal}
+}
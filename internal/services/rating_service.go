@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/cache"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ratingService struct {
+	ratingRepo storage.RatingRepository
+	jobRepo    storage.JobRepository
+	userRepo   storage.UserRepository
+}
+
+// NewRatingService creates a new instance of RatingService.
+func NewRatingService(db *pgxpool.Pool, readCache cache.Config) RatingService {
+	return &ratingService{
+		ratingRepo: postgres.NewRatingRepo(db),
+		jobRepo:    readCache.JobRepository(postgres.NewJobRepo(db)),
+		userRepo:   readCache.UserRepository(postgres.NewUserRepo(db)),
+	}
+}
+
+// SubmitRating lets the employer of a completed job rate its contractor, once.
+func (s *ratingService) SubmitRating(ctx context.Context, req *dto.SubmitRatingRequest) (*models.Rating, error) {
+	ctx, span := tracing.Start(ctx, "RatingService.SubmitRating")
+	defer span.End()
+
+	job, err := s.jobRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: req.JobID})
+	if err != nil {
+		return nil, mapRepoError(err, "getting job to rate")
+	}
+
+	// --- Authorization Check: ONLY Employer ---
+	if job.EmployerID != req.UserID {
+		logger.FromContext(ctx).Warn("SubmitRating: Forbidden attempt by non-employer", "job_id", req.JobID, "user_id", req.UserID)
+		return nil, ErrForbidden
+	}
+	// --- End Auth Check ---
+
+	if job.State != models.JobStateComplete || job.ContractorID == nil {
+		return nil, fmt.Errorf("%w: only completed jobs can be rated", ErrInvalidState)
+	}
+
+	rating, err := s.ratingRepo.Create(ctx, &models.Rating{
+		JobID:   job.ID,
+		RaterID: req.UserID,
+		RateeID: *job.ContractorID,
+		Score:   req.Score,
+		Comment: req.Comment,
+	})
+	if err != nil {
+		return nil, mapRepoError(err, "creating rating")
+	}
+
+	logger.FromContext(ctx).Info("Rating submitted", "job_id", job.ID, "ratee_id", rating.RateeID, "score", rating.Score)
+	return rating, nil
+}
+
+// GetUserAverageRating returns the average score the user has received across all rated jobs.
+func (s *ratingService) GetUserAverageRating(ctx context.Context, req *dto.GetUserRatingRequest) (*models.UserRatingSummary, error) {
+	ctx, span := tracing.Start(ctx, "RatingService.GetUserAverageRating")
+	defer span.End()
+
+	exists, err := s.userRepo.Exists(ctx, req.UserID)
+	if err != nil {
+		return nil, mapRepoError(err, "checking user for rating")
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: user %s", ErrNotFound, req.UserID)
+	}
+
+	summary, err := s.ratingRepo.GetSummaryForUser(ctx, req)
+	if err != nil {
+		return nil, mapRepoError(err, "getting rating summary")
+	}
+	return summary, nil
+}
@@ -6,160 +6,588 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
+	"go-api-template/internal/mailer"
 	"go-api-template/internal/models"
 	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/cache"
 	"go-api-template/internal/storage/postgres"
 	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/auth"
+	"go-api-template/pkg/hasher"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/password"
+	"go-api-template/pkg/tracing"
 
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	RefreshTokenBytes = 32
 	RedisRefreshTokenPrefix = "refresh_token:"
+	RedisAccessTokenBlacklistPrefix = "access_token_blacklist:"
+	RedisPasswordResetPrefix = "pwreset:"
+	PasswordResetTokenTTL = 30 * time.Minute
+	RedisEmailVerificationPrefix = "email_verify:"
+	EmailVerificationTokenTTL = 24 * time.Hour
 )
 
 type userService struct {
 	repo          storage.UserRepository
+	jobRepo       storage.JobRepository
 	redisClient            *redis.Client
+	jwtKeyID      string // kid header of issued access tokens; empty when keys aren't rotated
 	jwtSecret     string
 	jwtExpiration time.Duration
 	refreshTokenExpiration time.Duration
+	impersonationExpiration time.Duration // Lifetime of the access tokens minted by ImpersonateUser
+	auditRepo     storage.AuditRepository
 	db            *pgxpool.Pool 
+	mailer        mailer.Mailer
+	requireVerifiedEmail bool // If true, Login rejects users who haven't verified their email
+	passwordPolicy password.Policy // Checked on registration and password reset
+	hasher        hasher.Hasher // Hashes with the configured algorithm; older or weaker hashes are upgraded on login
+	lockout       *loginLockout
 }
 
-// NewUserService creates a new instance of UserService.
-func NewUserService(redisClient *redis.Client, jwtSecret string, jwtExpiration, refreshTokenExpiration time.Duration, db *pgxpool.Pool) UserService {
+// NewUserService creates a new instance of UserService. Access tokens are signed with jwtSecret and
+// name jwtKeyID in their kid header, unless it is empty. Impersonation tokens live for impersonationExpiration.
+func NewUserService(redisClient *redis.Client, jwtKeyID, jwtSecret string, jwtExpiration, refreshTokenExpiration, impersonationExpiration time.Duration, db *pgxpool.Pool, mailer mailer.Mailer, requireVerifiedEmail bool, passwordPolicy password.Policy, passwordHasher hasher.Hasher, lockoutThreshold int, lockoutCooldown time.Duration, readCache cache.Config) UserService {
 	return &userService{ 
-		repo:          postgres.NewUserRepo(db),
+		repo:          readCache.UserRepository(postgres.NewUserRepo(db).WithHasher(passwordHasher)),
+		jobRepo:       postgres.NewJobRepo(db),
 		redisClient: redisClient,
+		jwtKeyID:      jwtKeyID,
 		jwtSecret:     jwtSecret,
 		jwtExpiration: jwtExpiration,
 		refreshTokenExpiration: refreshTokenExpiration,
+		impersonationExpiration: impersonationExpiration,
+		auditRepo:     postgres.NewAuditRepo(db),
 		db: db,
+		mailer: mailer,
+		requireVerifiedEmail: requireVerifiedEmail,
+		passwordPolicy: passwordPolicy,
+		hasher: passwordHasher,
+		lockout: newLoginLockout(redisClient, lockoutThreshold, lockoutCooldown),
 	}
 }
 
 func (s *userService) Register(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Register")
+	defer span.End()
+
+	if err := s.passwordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.Create(ctx, req)
 	if err != nil {
 		if errors.Is(err, storage.ErrDuplicateEmail) || errors.Is(err, storage.ErrConflict) {
 			return nil, fmt.Errorf("%w: %w", ErrConflict, err)
 		}
-		log.Printf("UserService: Error creating user: %v", err)
+		logger.FromContext(ctx).Error("UserService: Error creating user", "error", err)
 		return nil, fmt.Errorf("internal error creating user: %w", err)
 	}
+
+	// Registration succeeds even if the email can't be sent; the user can request a new one.
+	// Tokens live in Redis, so skip sending when no client is configured (e.g. tests without Redis).
+	if s.redisClient != nil {
+		if err := s.SendVerificationEmail(ctx, &dto.SendVerificationEmailRequest{Email: user.Email}); err != nil {
+			logger.FromContext(ctx).Warn("Failed to send verification email to new user", "user_id", user.ID, "error", err)
+		}
+	}
 	return user, nil
 }
 
+// RegisterOrGet registers the user like Register, but when the email is already registered and
+// req.Password is that account's password it returns the existing user with created false, so
+// provisioning can safely retry a registration. A wrong password still gets ErrConflict. The existing
+// account is looked up first, so the password policy only applies to accounts being created: one whose
+// password predates the current policy still gets 200 or 409. The password check counts towards the
+// login lockout like a failed login, so it can't be used to guess passwords faster than logging in.
+func (s *userService) RegisterOrGet(ctx context.Context, req *dto.CreateUserRequest) (*models.User, bool, error) {
+	ctx, span := tracing.Start(ctx, "UserService.RegisterOrGet")
+	defer span.End()
+
+	conflictErr := fmt.Errorf("%w: %w", ErrConflict, storage.ErrDuplicateEmail)
+
+	existing, err := s.repo.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: req.Email})
+	if errors.Is(err, storage.ErrNotFound) {
+		user, err := s.Register(ctx, req)
+		if err == nil {
+			return user, true, nil
+		}
+		if !errors.Is(err, storage.ErrDuplicateEmail) {
+			return nil, false, err
+		}
+		// Registered concurrently since the lookup; answer as for any existing account
+		existing, err = s.repo.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: req.Email})
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, false, conflictErr // Deleted since the insert failed; still a conflict for this request
+		}
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("Error fetching existing user during registration", "email", req.Email, "error", err)
+		return nil, false, fmt.Errorf("internal error registering user: %w", err)
+	}
+
+	lockedUntil, err := s.lockout.lockedUntil(ctx, req.Email)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Registration lockout check failed, allowing attempt", "email", req.Email, "error", err)
+	} else if !lockedUntil.IsZero() {
+		logger.FromContext(ctx).Warn("Idempotent registration rejected: account locked", "email", req.Email, "locked_until", lockedUntil)
+		return nil, false, conflictErr
+	}
+
+	if err := s.hasher.Verify(existing.PasswordHash, req.Password); err != nil {
+		logger.FromContext(ctx).Warn("Idempotent registration failed: password does not match existing user", "email", req.Email)
+		s.recordLoginFailure(ctx, req.Email)
+		return nil, false, conflictErr
+	}
+
+	if err := s.lockout.reset(ctx, req.Email); err != nil {
+		logger.FromContext(ctx).Warn("Failed to reset failed login count", "email", req.Email, "error", err)
+	}
+	logger.FromContext(ctx).Info("Registration repeated for existing user, returning it", "user_id", existing.ID)
+	return existing, false, nil
+}
+
 func (s *userService) Login(ctx context.Context, req *dto.LoginRequest) (*models.User, string, string, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	// Checked before the credentials, so a locked account stays locked even for the right password.
+	// If Redis can't be reached the attempt is let through, like the rate limiter does.
+	lockedUntil, err := s.lockout.lockedUntil(ctx, req.Email)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Login lockout check failed, allowing attempt", "email", req.Email, "error", err)
+	} else if !lockedUntil.IsZero() {
+		logger.FromContext(ctx).Warn("Login attempt rejected: account locked", "email", req.Email, "locked_until", lockedUntil)
+		return nil, "", "", ErrAccountLocked
+	}
+
 	emailReq := dto.GetUserByEmailRequest{Email: req.Email}
 	user, err := s.repo.GetByEmail(ctx, &emailReq)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
-			log.Printf("Login attempt failed for email %s: user not found", req.Email)
+			logger.FromContext(ctx).Warn("Login attempt failed: user not found", "email", req.Email)
+			s.recordLoginFailure(ctx, req.Email) // Counted too, so lockout can't reveal which emails exist
 			return nil, "", "", ErrInvalidCredentials // Use specific service error
 		}
-		log.Printf("Error fetching user by email %s during login: %v", req.Email, err)
+		logger.FromContext(ctx).Error("Error fetching user during login", "email", req.Email, "error", err)
 		return nil, "", "", fmt.Errorf("internal error during login: %w", err)
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
+	err = s.hasher.Verify(user.PasswordHash, req.Password)
 	if err != nil {
-		log.Printf("Login attempt failed for email %s: invalid password", req.Email)
+		logger.FromContext(ctx).Warn("Login attempt failed: invalid password", "email", req.Email)
+		s.recordLoginFailure(ctx, req.Email)
 		return nil, "", "", ErrInvalidCredentials // Use specific service error
 	}
 
-	// Generate JWT Token
-	expirationTime := time.Now().Add(s.jwtExpiration)
-	claims := &jwt.RegisteredClaims{
-		Subject:   user.ID.String(),
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	if err := s.lockout.reset(ctx, req.Email); err != nil {
+		logger.FromContext(ctx).Warn("Failed to reset failed login count", "email", req.Email, "error", err)
+	}
+	s.upgradePasswordHash(ctx, user, req.Password)
+
+	// Only checked after the password so it can't reveal whether an email is registered
+	if s.requireVerifiedEmail && !user.Verified {
+		logger.FromContext(ctx).Warn("Login attempt failed: email not verified", "email", req.Email)
+		return nil, "", "", ErrEmailNotVerified
 	}
 
 	// Generate Access Token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	tokenString, err := s.generateAccessToken(user.ID, user.Role)
 	if err != nil {
-		log.Printf("Error generating JWT token for user %s: %v", user.Email, err)
+		logger.FromContext(ctx).Error("Error generating JWT token", "email", user.Email, "error", err)
 		return nil, "", "", fmt.Errorf("failed to generate login token: %w", err)
 	}
 
 	// Generate and Store Refresh Token
 	refreshToken, err := s.generateAndStoreRefreshToken(ctx, user.ID)
 	if err != nil {
-		log.Printf("Error generating/storing refresh token for user %s: %v", user.Email, err)
+		logger.FromContext(ctx).Error("Error generating/storing refresh token", "email", user.Email, "error", err)
 		return nil, "", "", fmt.Errorf("failed to handle refresh token: %w", err)
 	}
 
 	return user, tokenString, refreshToken, nil
 }
 
+// recordLoginFailure counts a failed login towards the lockout threshold. Failures are only logged.
+func (s *userService) recordLoginFailure(ctx context.Context, email string) {
+	locked, err := s.lockout.recordFailure(ctx, email)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to record failed login", "email", email, "error", err)
+		return
+	}
+	if locked {
+		logger.FromContext(ctx).Warn("Account locked after repeated failed logins", "email", email, "threshold", s.lockout.threshold, "cooldown", s.lockout.cooldown)
+	}
+}
+
+// upgradePasswordHash re-hashes the password if the stored hash uses another algorithm than the
+// configured one, or weaker parameters (e.g. a lower bcrypt cost). It runs after a successful login,
+// the only time the plaintext is available. Failures are logged and otherwise ignored; the user can
+// still log in and the upgrade is retried next time.
+func (s *userService) upgradePasswordHash(ctx context.Context, user *models.User, plainPassword string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	oldAlgorithm, _ := hasher.AlgorithmOf(user.PasswordHash)
+
+	passwordHash, err := s.hasher.Hash(plainPassword)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to re-hash password on login", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if _, err := s.repo.Update(ctx, &dto.UpdateUserRequest{ID: user.ID, PasswordHash: &passwordHash}); err != nil {
+		logger.FromContext(ctx).Warn("Failed to store upgraded password hash", "user_id", user.ID, "error", err)
+		return
+	}
+	user.PasswordHash = passwordHash
+	newAlgorithm, _ := hasher.AlgorithmOf(passwordHash)
+	logger.FromContext(ctx).Info("Upgraded password hash", "user_id", user.ID, "old_algorithm", oldAlgorithm, "new_algorithm", newAlgorithm)
+}
+
 // Refresh generates a new access token and potentially a new refresh token using a valid refresh token.
 func (s *userService) Refresh(ctx context.Context, req *dto.RefreshRequest) (string, string, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Refresh")
+	defer span.End()
+
 	userIDStr, err := s.redisClient.Get(ctx, RedisRefreshTokenPrefix+req.RefreshToken).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			log.Printf("Refresh token not found or expired: %s", req)
+			logger.FromContext(ctx).Warn("Refresh token not found or expired", "refresh_token", req.RefreshToken)
 			return "", "", ErrInvalidCredentials // Treat as invalid credentials/token
 		}
-		log.Printf("Error retrieving refresh token from Redis: %v", err)
+		logger.FromContext(ctx).Error("Error retrieving refresh token from Redis", "error", err)
 		return "", "", fmt.Errorf("internal error validating refresh token: %w", err)
 	}
 
 	// Invalidate the used refresh token (Token Rotation)
 	if err := s.redisClient.Del(ctx, RedisRefreshTokenPrefix+req.RefreshToken).Err(); err != nil {
 		// Log the error but proceed, as the main goal is issuing new tokens
-		log.Printf("WARN: Failed to delete used refresh token %s from Redis: %v", req.RefreshToken, err)
+		logger.FromContext(ctx).Warn("Failed to delete used refresh token from Redis", "refresh_token", req.RefreshToken, "error", err)
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		log.Printf("Error parsing userID '%s' from Redis for refresh token %s: %v", userIDStr, req.RefreshToken, err)
+		logger.FromContext(ctx).Error("Error parsing userID from Redis for refresh", "user_id", userIDStr, "refresh_token", req.RefreshToken, "error", err)
 		return "", "", fmt.Errorf("internal error processing refresh token data: %w", err)
 	}
 
+	// Look up the user so the new access token carries their current role
+	user, err := s.repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: userID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.FromContext(ctx).Info("Refresh token belongs to deleted user", "refresh_token", req.RefreshToken, "user_id", userID)
+			return "", "", ErrInvalidCredentials
+		}
+		logger.FromContext(ctx).Error("Error fetching user during refresh", "user_id", userID, "error", err)
+		return "", "", fmt.Errorf("internal error during refresh: %w", err)
+	}
+
 	// Generate new Access Token
-	newAccessToken, err := s.generateAccessToken(userID)
+	newAccessToken, err := s.generateAccessToken(userID, user.Role)
 	if err != nil {
-		log.Printf("Error generating new access token during refresh for user %s: %v", userID, err)
+		logger.FromContext(ctx).Error("Error generating new access token during refresh", "user_id", userID, "error", err)
 		return "", "", fmt.Errorf("failed to generate new access token: %w", err)
 	}
 
 	// Generate and Store new Refresh Token
 	newRefreshToken, err := s.generateAndStoreRefreshToken(ctx, userID)
 	if err != nil {
-		log.Printf("Error generating/storing new refresh token during refresh for user %s: %v", userID, err)
+		logger.FromContext(ctx).Error("Error generating/storing new refresh token during refresh", "user_id", userID, "error", err)
 		return "", "", fmt.Errorf("failed to handle new refresh token: %w", err)
 	}
 
 	return newAccessToken, newRefreshToken, nil
 }
 
-// Logout invalidates a specific refresh token.
+// Logout invalidates a specific refresh token and, when the request was authenticated, revokes the
+// access token it was made with so it stops working before it expires.
 func (s *userService) Logout(ctx context.Context, req *dto.LogoutRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.Logout")
+	defer span.End()
+
 	err := s.redisClient.Del(ctx, RedisRefreshTokenPrefix+req.RefreshToken).Err()
 	if err != nil && !errors.Is(err, redis.Nil) { // Ignore if token already not found
-		log.Printf("Error deleting refresh token %s from Redis during logout: %v", req.RefreshToken, err)
+		logger.FromContext(ctx).Error("Error deleting refresh token from Redis during logout", "refresh_token", req.RefreshToken, "error", err)
 		return fmt.Errorf("failed to invalidate session: %w", err)
 	}
-	log.Printf("Successfully invalidated refresh token: %s", req.RefreshToken)
+	logger.FromContext(ctx).Info("Successfully invalidated refresh token", "refresh_token", req.RefreshToken)
+
+	if req.AccessTokenID != "" {
+		if err := s.RevokeAccessToken(ctx, req.AccessTokenID, req.AccessTokenExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+	return nil
+}
+
+// RevokeAccessToken blacklists an access token by its jti claim so the auth middleware rejects it immediately.
+// The entry lives until expiresAt, the token's exp claim, after which the token is rejected anyway; an
+// already expired token is left alone.
+func (s *userService) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "UserService.RevokeAccessToken")
+	defer span.End()
+
+	if tokenID == "" {
+		return fmt.Errorf("%w: token ID is required", ErrValidation)
+	}
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		return nil // Nothing to revoke: the middleware already rejects expired tokens
+	}
+
+	err := s.redisClient.Set(ctx, RedisAccessTokenBlacklistPrefix+tokenID, "revoked", remaining).Err()
+	if err != nil {
+		logger.FromContext(ctx).Error("Error blacklisting access token in Redis", "token_id", tokenID, "error", err)
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	logger.FromContext(ctx).Info("Successfully revoked access token", "token_id", tokenID)
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the access token with the given jti has been blacklisted.
+func (s *userService) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserService.IsAccessTokenRevoked")
+	defer span.End()
+
+	count, err := s.redisClient.Exists(ctx, RedisAccessTokenBlacklistPrefix+tokenID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check access token blacklist: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RequestPasswordReset emails a single-use reset token to the user.
+// It returns nil for unknown emails so callers can't use it to enumerate accounts.
+func (s *userService) RequestPasswordReset(ctx context.Context, req *dto.PasswordResetRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.RequestPasswordReset")
+	defer span.End()
+
+	user, err := s.repo.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: req.Email})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.FromContext(ctx).Info("Password reset requested for unknown email", "email", req.Email)
+			return nil
+		}
+		logger.FromContext(ctx).Error("Error fetching user during password reset", "email", req.Email, "error", err)
+		return fmt.Errorf("internal error requesting password reset: %w", err)
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	// Store in Redis: Key = "pwreset:<token>", Value = UserID
+	if err := s.redisClient.Set(ctx, RedisPasswordResetPrefix+token, user.ID.String(), PasswordResetTokenTTL).Err(); err != nil {
+		logger.FromContext(ctx).Error("Error storing password reset token", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to store password reset token: %w", err)
+	}
+
+	data := mailer.PasswordResetData{Token: token, ExpiresIn: PasswordResetTokenTTL.String()}
+	if err := s.mailer.Send(ctx, user.Email, mailer.TemplatePasswordReset, data); err != nil {
+		logger.FromContext(ctx).Error("Error sending password reset email", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+	return nil
+}
+
+// ConfirmPasswordReset consumes a reset token and sets the user's new password.
+func (s *userService) ConfirmPasswordReset(ctx context.Context, req *dto.ConfirmPasswordResetRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.ConfirmPasswordReset")
+	defer span.End()
+
+	// Check the password before consuming the token so a weak password doesn't burn it
+	if err := s.passwordPolicy.Validate(req.NewPassword); err != nil {
+		return err
+	}
+
+	// GETDEL makes the token single-use even under concurrent confirmations
+	userIDStr, err := s.redisClient.GetDel(ctx, RedisPasswordResetPrefix+req.Token).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("%w: invalid or expired password reset token", ErrInvalidCredentials)
+		}
+		logger.FromContext(ctx).Error("Error retrieving password reset token from Redis", "error", err)
+		return fmt.Errorf("internal error validating password reset token: %w", err)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error parsing userID from Redis for password reset", "user_id", userIDStr, "error", err)
+		return fmt.Errorf("internal error processing password reset token data: %w", err)
+	}
+
+	passwordHash, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if _, err := s.repo.Update(ctx, &dto.UpdateUserRequest{ID: userID, PasswordHash: &passwordHash}); err != nil {
+		return mapRepoError(err, "resetting user password")
+	}
+
+	logger.FromContext(ctx).Info("Password reset completed", "user_id", userID)
+	return nil
+}
+
+// ChangePassword sets a new password after checking the current one, then revokes all of the user's
+// refresh tokens so other sessions have to log in again.
+func (s *userService) ChangePassword(ctx context.Context, req *dto.ChangePasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.ChangePassword")
+	defer span.End()
+
+	user, err := s.repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: req.ID})
+	if err != nil {
+		return mapRepoError(err, "fetching user for password change")
+	}
+
+	if err := s.hasher.Verify(user.PasswordHash, req.CurrentPassword); err != nil {
+		logger.FromContext(ctx).Warn("Password change failed: invalid current password", "user_id", req.ID)
+		return ErrInvalidCredentials
+	}
+
+	if err := s.passwordPolicy.Validate(req.NewPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if _, err := s.repo.Update(ctx, &dto.UpdateUserRequest{ID: req.ID, PasswordHash: &passwordHash}); err != nil {
+		return mapRepoError(err, "changing user password")
+	}
+
+	if err := s.revokeUserTokens(ctx, RedisRefreshTokenPrefix, req.ID); err != nil {
+		// The password is already changed; report the failure so the client knows sessions may remain
+		logger.FromContext(ctx).Error("Password changed but failed to revoke refresh tokens", "user_id", req.ID, "error", err)
+		return fmt.Errorf("password changed but failed to invalidate sessions: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("Password changed", "user_id", req.ID)
+	return nil
+}
+
+// SendVerificationEmail emails a token the user can use to verify their address.
+// Unknown or already verified emails are ignored so the endpoint can't be used to enumerate accounts.
+func (s *userService) SendVerificationEmail(ctx context.Context, req *dto.SendVerificationEmailRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.SendVerificationEmail")
+	defer span.End()
+
+	user, err := s.repo.GetByEmail(ctx, &dto.GetUserByEmailRequest{Email: req.Email})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.FromContext(ctx).Info("Verification email requested for unknown email", "email", req.Email)
+			return nil
+		}
+		logger.FromContext(ctx).Error("Error fetching user for verification", "email", req.Email, "error", err)
+		return fmt.Errorf("internal error sending verification email: %w", err)
+	}
+	if user.Verified {
+		return nil
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	// Store in Redis: Key = "email_verify:<token>", Value = UserID
+	if err := s.redisClient.Set(ctx, RedisEmailVerificationPrefix+token, user.ID.String(), EmailVerificationTokenTTL).Err(); err != nil {
+		logger.FromContext(ctx).Error("Error storing verification token", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	data := mailer.VerifyEmailData{Token: token, ExpiresIn: EmailVerificationTokenTTL.String()}
+	if err := s.mailer.Send(ctx, user.Email, mailer.TemplateVerifyEmail, data); err != nil {
+		logger.FromContext(ctx).Error("Error sending verification email", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
 	return nil
 }
 
+// VerifyEmail consumes a verification token and marks the user's email as verified.
+func (s *userService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.VerifyEmail")
+	defer span.End()
+
+	userIDStr, err := s.redisClient.GetDel(ctx, RedisEmailVerificationPrefix+token).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("%w: invalid or expired verification token", ErrInvalidCredentials)
+		}
+		logger.FromContext(ctx).Error("Error retrieving verification token from Redis", "error", err)
+		return nil, fmt.Errorf("internal error validating verification token: %w", err)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error parsing userID from Redis for email verification", "user_id", userIDStr, "error", err)
+		return nil, fmt.Errorf("internal error processing verification token data: %w", err)
+	}
+
+	verified := true
+	user, err := s.repo.Update(ctx, &dto.UpdateUserRequest{ID: userID, Verified: &verified})
+	if err != nil {
+		return nil, mapRepoError(err, "verifying user email")
+	}
+
+	logger.FromContext(ctx).Info("Email verified", "user_id", userID)
+	return user, nil
+}
+
+// GetAll returns the first dto.DefaultMaxPageLimit users by name.
+//
+// Deprecated: Use ListUsers, which pages through every user and reports the total.
 func (s *userService) GetAll(ctx context.Context) ([]models.User, error) {
-	return s.repo.GetAll(ctx)
+	ctx, span := tracing.Start(ctx, "UserService.GetAll")
+	defer span.End()
+
+	users, _, err := s.ListUsers(ctx, &dto.ListUsersRequest{Limit: dto.DefaultMaxPageLimit})
+	return users, err
+}
+
+// ListUsers returns a page of users and the total number matching the search.
+func (s *userService) ListUsers(ctx context.Context, req *dto.ListUsersRequest) ([]models.User, int, error) {
+	ctx, span := tracing.Start(ctx, "UserService.ListUsers")
+	defer span.End()
+
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListUsers: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txRepo := s.repo.WithTx(tx)
+
+	users, err := txRepo.List(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("UserService: Error listing users", "error", err)
+		return nil, 0, fmt.Errorf("internal error listing users: %w", err)
+	}
+	total, err := txRepo.Count(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("UserService: Error counting users", "error", err)
+		return nil, 0, fmt.Errorf("internal error counting users: %w", err)
+	}
+	return users, total, nil
 }
 
 func (s *userService) GetByID(ctx context.Context, req *dto.GetUserByIdRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByID")
+	defer span.End()
+
 	user, err := s.repo.GetByID(ctx, req)
 	if errors.Is(err, storage.ErrNotFound) {
 		return nil, ErrNotFound
@@ -167,7 +595,98 @@ func (s *userService) GetByID(ctx context.Context, req *dto.GetUserByIdRequest)
 	return user, err
 }
 
+// SharesActiveJob reports whether the two users are the employer and the contractor of the same
+// Ongoing job, which entitles them to see each other's contact details.
+func (s *userService) SharesActiveJob(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserService.SharesActiveJob")
+	defer span.End()
+
+	shares, err := s.jobRepo.SharesOngoingJob(ctx, userID, otherID)
+	if err != nil {
+		logger.FromContext(ctx).Error("SharesActiveJob: Error checking ongoing jobs", "user_id", userID, "other_id", otherID, "error", err)
+		return false, mapRepoError(err, "checking ongoing jobs between users")
+	}
+	return shares, nil
+}
+
+// GetPermissions works out what the user may do: the capabilities of their current role, which
+// middleware.RequireCapability enforces, plus those that come from their jobs. It is meant for showing
+// or hiding features; the services still check every request.
+func (s *userService) GetPermissions(ctx context.Context, userID uuid.UUID) (*models.UserPermissions, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetPermissions")
+	defer span.End()
+
+	user, err := s.repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: userID})
+	if err != nil {
+		logger.FromContext(ctx).Error("GetPermissions: Error fetching user", "user_id", userID, "error", err)
+		return nil, mapRepoError(err, "fetching user for permissions")
+	}
+
+	granted := make(map[models.Capability]bool)
+	for _, capability := range models.RoleCapabilities(user.Role) {
+		granted[capability] = true
+	}
+
+	ongoing := models.JobStateOngoing
+	ongoingJobs, err := s.jobRepo.CountByContractor(ctx, &dto.ListJobsByContractorRequest{ContractorID: userID, State: &ongoing})
+	if err != nil {
+		logger.FromContext(ctx).Error("GetPermissions: Error counting ongoing jobs", "user_id", userID, "error", err)
+		return nil, mapRepoError(err, "counting ongoing jobs")
+	}
+	granted[models.CapabilityCreateInvoice] = ongoingJobs > 0
+
+	waiting := models.JobStateWaiting
+	waitingJobs, err := s.jobRepo.CountByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: userID, State: &waiting})
+	if err != nil {
+		logger.FromContext(ctx).Error("GetPermissions: Error counting waiting jobs", "user_id", userID, "error", err)
+		return nil, mapRepoError(err, "counting waiting jobs")
+	}
+	granted[models.CapabilityReviewApplications] = waitingJobs > 0
+
+	permissions := &models.UserPermissions{Role: user.Role}
+	for _, capability := range models.AllCapabilities {
+		if granted[capability] {
+			permissions.Capabilities = append(permissions.Capabilities, capability)
+		}
+	}
+	return permissions, nil
+}
+
+// GetByIDs looks up several users at once. Users come back in the order their IDs were requested,
+// with duplicates collapsed; IDs that don't match a user are silently left out.
+func (s *userService) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByIDs")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return []models.User{}, nil
+	}
+
+	found, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, mapRepoError(err, "getting users by IDs")
+	}
+	byID := make(map[uuid.UUID]models.User, len(found))
+	for _, user := range found {
+		byID[user.ID] = user
+	}
+
+	users := make([]models.User, 0, len(found))
+	for _, id := range ids {
+		user, ok := byID[id]
+		if !ok {
+			continue
+		}
+		users = append(users, user)
+		delete(byID, id) // Later duplicates of the same ID are skipped
+	}
+	return users, nil
+}
+
 func (s *userService) GetByEmail(ctx context.Context, req *dto.GetUserByEmailRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByEmail")
+	defer span.End()
+
 	user, err := s.repo.GetByEmail(ctx, req)
 	if errors.Is(err, storage.ErrNotFound) {
 		return nil, ErrNotFound
@@ -176,10 +695,13 @@ func (s *userService) GetByEmail(ctx context.Context, req *dto.GetUserByEmailReq
 }
 
 func (s *userService) Update(ctx context.Context, req *dto.UpdateUserRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Update")
+	defer span.End()
+
 	// --- Transaction Start ---
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		log.Printf("UserService.Update: Error beginning transaction: %v", err)
+		logger.FromContext(ctx).Error("UserService.Update: Error beginning transaction", "error", err)
 		return nil, fmt.Errorf("internal error starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Rollback if anything fails
@@ -188,6 +710,21 @@ func (s *userService) Update(ctx context.Context, req *dto.UpdateUserRequest) (*
 	txUserRepo := s.repo.WithTx(tx)
 	// --- End Transaction Setup ---
 
+	emailChanged := false
+	if req.Email != nil {
+		existingUser, err := txUserRepo.GetByID(ctx, &dto.GetUserByIdRequest{ID: req.ID})
+		if err != nil {
+			return nil, mapRepoError(err, "fetching user for email change")
+		}
+		// Resubmitting the current email would needlessly reset verification
+		if *req.Email == existingUser.Email {
+			return nil, fmt.Errorf("%w: email is unchanged", ErrValidation)
+		}
+		unverified := false
+		req.Verified = &unverified
+		emailChanged = true
+	}
+
 	updatedUser, err := txUserRepo.Update(ctx, req) // Use txUserRepo
 	if err != nil {
 		return nil, mapRepoError(err, "updating user")
@@ -195,28 +732,119 @@ func (s *userService) Update(ctx context.Context, req *dto.UpdateUserRequest) (*
 
 	// --- Commit Transaction ---
 	if err := tx.Commit(ctx); err != nil {
-		log.Printf("UserService.Update: Error committing transaction: %v", err)
+		logger.FromContext(ctx).Error("UserService.Update: Error committing transaction", "error", err)
 		return nil, fmt.Errorf("internal error committing user update: %w", err)
 	}
 	// --- End Transaction ---
+	invalidateCached(ctx, s.repo, updatedUser.ID)
+
+	if emailChanged && s.redisClient != nil {
+		// Tokens sent to the old address must not verify the new one
+		if err := s.revokeUserTokens(ctx, RedisEmailVerificationPrefix, updatedUser.ID); err != nil {
+			logger.FromContext(ctx).Warn("Failed to revoke old email verification tokens", "user_id", updatedUser.ID, "error", err)
+		}
+		// Like Register, the update stands even if the email can't be sent; the user can request a new one
+		if err := s.SendVerificationEmail(ctx, &dto.SendVerificationEmailRequest{Email: updatedUser.Email}); err != nil {
+			logger.FromContext(ctx).Warn("Failed to send verification email after email change", "user_id", updatedUser.ID, "error", err)
+		}
+	}
 
 	return updatedUser, nil
 }
 
 func (s *userService) Delete(ctx context.Context, req *dto.DeleteUserRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.Delete")
+	defer span.End()
+
 	return s.repo.Delete(ctx, req)
 }
 
-// generateAccessToken creates a new JWT access token for the given user ID.
-func (s *userService) generateAccessToken(userID uuid.UUID) (string, error) {
-	expirationTime := time.Now().Add(s.jwtExpiration)
-	claims := &jwt.RegisteredClaims{
-		Subject:   userID.String(),
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+// RestoreUser undoes a soft delete. Admin-only; the role check happens in the route middleware.
+func (s *userService) RestoreUser(ctx context.Context, req *dto.RestoreUserRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.RestoreUser")
+	defer span.End()
+
+	user, err := s.repo.Restore(ctx, req)
+	if err != nil {
+		logger.FromContext(ctx).Error("UserService: Error restoring user", "user_id", req.ID, "error", err)
+		return nil, mapRepoError(err, "restoring user")
+	}
+	logger.FromContext(ctx).Info("User restored", "user_id", req.ID)
+	return user, nil
+}
+
+// ImpersonateUser mints an access token for req.ID on behalf of the admin req.AdminID, so support staff
+// can reproduce an issue as that user. The token carries an act claim naming the admin, which makes the
+// audit entries of changes made with it record both; it lives for the impersonation TTL and comes
+// without a refresh token. Admins can't be impersonated, and an impersonation token can't start another
+// one. Admin-only; the role check happens in the route middleware. The impersonation itself is audited.
+func (s *userService) ImpersonateUser(ctx context.Context, req *dto.ImpersonateUserRequest) (*models.User, string, time.Time, error) {
+	ctx, span := tracing.Start(ctx, "UserService.ImpersonateUser")
+	defer span.End()
+
+	if req.ID == req.AdminID {
+		return nil, "", time.Time{}, fmt.Errorf("%w: cannot impersonate yourself", ErrValidation)
+	}
+	if _, impersonating := models.ImpersonatorFromContext(ctx); impersonating {
+		logger.FromContext(ctx).Warn("ImpersonateUser: Attempt from an impersonation token", "user_id", req.ID)
+		return nil, "", time.Time{}, ErrForbidden
+	}
+
+	user, err := s.repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: req.ID})
+	if err != nil {
+		logger.FromContext(ctx).Error("ImpersonateUser: Error fetching user", "user_id", req.ID, "error", err)
+		return nil, "", time.Time{}, mapRepoError(err, "fetching user to impersonate")
+	}
+	if user.Role.Can(models.CapabilityAccessAdmin) {
+		logger.FromContext(ctx).Warn("ImpersonateUser: Attempt to impersonate an admin", "user_id", req.ID, "admin_id", req.AdminID)
+		return nil, "", time.Time{}, fmt.Errorf("%w: admins cannot be impersonated", ErrForbidden)
+	}
+
+	claims := s.newAccessTokenClaims(user.ID, user.Role, s.impersonationExpiration)
+	claims.Act = &auth.ActorClaims{Subject: req.AdminID.String()}
+	accessToken, err := s.signAccessToken(claims)
+	if err != nil {
+		logger.FromContext(ctx).Error("ImpersonateUser: Error generating access token", "user_id", req.ID, "error", err)
+		return nil, "", time.Time{}, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	expiresAt := claims.ExpiresAt.Time
+	details := models.UserImpersonation{TokenID: claims.ID, ExpiresAt: expiresAt}
+	if err := recordAuditEntry(ctx, s.auditRepo, req.AdminID, models.AuditActionUserImpersonated, models.AuditEntityUser, user.ID, details); err != nil {
+		logger.FromContext(ctx).Error("ImpersonateUser: Error recording audit entry", "user_id", req.ID, "error", err)
+		return nil, "", time.Time{}, err
+	}
+
+	logger.FromContext(ctx).Info("Impersonation token issued", "user_id", user.ID, "admin_id", req.AdminID, "expires_at", expiresAt)
+	return user, accessToken, expiresAt, nil
+}
+
+// generateAccessToken creates a new JWT access token for the given user ID and role.
+func (s *userService) generateAccessToken(userID uuid.UUID, role models.UserRole) (string, error) {
+	return s.signAccessToken(s.newAccessTokenClaims(userID, role, s.jwtExpiration))
+}
+
+// newAccessTokenClaims returns the claims of an access token for the given user ID and role, expiring
+// after ttl.
+func (s *userService) newAccessTokenClaims(userID uuid.UUID, role models.UserRole, ttl time.Duration) *auth.AccessTokenClaims {
+	now := time.Now()
+	return &auth.AccessTokenClaims{
+		Role: string(role),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(), // jti, used as the blacklist key on revocation
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
 	}
+}
 
+// signAccessToken signs claims with the active key.
+func (s *userService) signAccessToken(claims *auth.AccessTokenClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if s.jwtKeyID != "" {
+		token.Header["kid"] = s.jwtKeyID // Lets the middleware pick the key during rotation
+	}
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
 	if err != nil {
 		return "", fmt.Errorf("failed to sign access token: %w", err)
@@ -224,19 +852,53 @@ func (s *userService) generateAccessToken(userID uuid.UUID) (string, error) {
 	return tokenString, nil
 }
 
-// generateAndStoreRefreshToken creates a secure random refresh token and stores it in Redis.
-func (s *userService) generateAndStoreRefreshToken(ctx context.Context, userID uuid.UUID) (string, error) {
+// generateRandomToken returns a URL-safe, base64-encoded random token.
+func generateRandomToken() (string, error) {
 	rb := make([]byte, RefreshTokenBytes)
 	if _, err := rand.Read(rb); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes for refresh token: %w", err)
+		return "", fmt.Errorf("failed to generate random bytes for token: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(rb), nil
+}
+
+// generateAndStoreRefreshToken creates a secure random refresh token and stores it in Redis.
+func (s *userService) generateAndStoreRefreshToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	refreshToken, err := generateRandomToken()
+	if err != nil {
+		return "", err
 	}
-	refreshToken := base64.URLEncoding.EncodeToString(rb)
 
 	// Store in Redis: Key = "refresh_token:<token>", Value = UserID
-	err := s.redisClient.Set(ctx, RedisRefreshTokenPrefix+refreshToken, userID.String(), s.refreshTokenExpiration).Err()
+	err = s.redisClient.Set(ctx, RedisRefreshTokenPrefix+refreshToken, userID.String(), s.refreshTokenExpiration).Err()
 	if err != nil {
 		return "", fmt.Errorf("failed to store refresh token in Redis: %w", err)
 	}
 
 	return refreshToken, nil
 }
+
+// revokeUserTokens deletes every token under prefix (refresh, email verification, ...) stored for the
+// user. Tokens are keyed by token rather than user, so this scans the prefix and checks each stored userID.
+func (s *userService) revokeUserTokens(ctx context.Context, prefix string, userID uuid.UUID) error {
+	iter := s.redisClient.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		storedUserID, err := s.redisClient.Get(ctx, key).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue // Expired or used since the scan returned it
+			}
+			return fmt.Errorf("failed to read token %s: %w", key, err)
+		}
+		if storedUserID != userID.String() {
+			continue
+		}
+		if err := s.redisClient.Del(ctx, key).Err(); err != nil {
+			return fmt.Errorf("failed to delete token %s: %w", key, err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan tokens: %w", err)
+	}
+	return nil
+}
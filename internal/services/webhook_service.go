@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	webhookSecretPrefix      = "whsec_" // Tells a webhook secret apart from an API key
+	webhookSecretRandomBytes = 32
+)
+
+type webhookService struct {
+	db   *pgxpool.Pool
+	repo storage.WebhookRepository
+}
+
+// NewWebhookService creates a new instance of WebhookService.
+func NewWebhookService(db *pgxpool.Pool) WebhookService {
+	return &webhookService{db: db, repo: postgres.NewWebhookRepo(db)}
+}
+
+// CreateWebhook registers an active webhook for req.Owner with a new signing secret. The secret is
+// only returned here, so it must be handed to the integration now.
+func (s *webhookService) CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*models.Webhook, string, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, "", fmt.Errorf("%w: webhook URL must be an absolute http or https URL", ErrValidation)
+	}
+
+	random := make([]byte, webhookSecretRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", fmt.Errorf("internal error generating webhook secret: %w", err)
+	}
+	secret := webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	webhook := &models.Webhook{
+		Owner:      req.Owner,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+	}
+	if req.CreatedBy != uuid.Nil {
+		webhook.CreatedBy = &req.CreatedBy
+	}
+	created, err := s.repo.Create(ctx, webhook)
+	if err != nil {
+		return nil, "", mapRepoError(err, "creating webhook")
+	}
+
+	logger.FromContext(ctx).Info("Webhook created", "webhook_id", created.ID, "owner", created.Owner, "event_types", created.EventTypes)
+	return created, secret, nil
+}
+
+// ListWebhooks returns every webhook, oldest first.
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListWebhooks")
+	defer span.End()
+
+	webhooks, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, mapRepoError(err, "listing webhooks")
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook pauses or resumes a webhook. While paused no deliveries are queued for it, and those
+// already queued wait until it is resumed.
+func (s *webhookService) UpdateWebhook(ctx context.Context, req *dto.UpdateWebhookRequest) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateWebhook")
+	defer span.End()
+
+	webhook, err := s.repo.SetActive(ctx, req)
+	if err != nil {
+		return nil, mapRepoError(err, "updating webhook")
+	}
+	logger.FromContext(ctx).Info("Webhook updated", "webhook_id", webhook.ID, "active", webhook.Active)
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook; deliveries not yet made are dropped with it.
+func (s *webhookService) DeleteWebhook(ctx context.Context, req *dto.DeleteWebhookRequest) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	if err := s.repo.Delete(ctx, req); err != nil {
+		return mapRepoError(err, "deleting webhook")
+	}
+	logger.FromContext(ctx).Info("Webhook deleted", "webhook_id", req.ID)
+	return nil
+}
+
+// ListWebhookDeliveries returns a page of a webhook's deliveries, newest first, and the total number
+// matching the filters.
+func (s *webhookService) ListWebhookDeliveries(ctx context.Context, req *dto.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListWebhookDeliveries")
+	defer span.End()
+
+	tx, err := beginSnapshotTx(ctx, s.db)
+	if err != nil {
+		logger.FromContext(ctx).Error("ListWebhookDeliveries: Error beginning transaction", "error", err)
+		return nil, 0, fmt.Errorf("internal error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; nothing to commit
+	txRepo := s.repo.WithTx(tx)
+
+	if _, err := txRepo.GetByID(ctx, req.WebhookID); err != nil {
+		return nil, 0, mapRepoError(err, "finding webhook")
+	}
+	deliveries, err := txRepo.ListDeliveries(ctx, req)
+	if err != nil {
+		return nil, 0, mapRepoError(err, "listing webhook deliveries")
+	}
+	total, err := txRepo.CountDeliveries(ctx, req)
+	if err != nil {
+		return nil, 0, mapRepoError(err, "counting webhook deliveries")
+	}
+	return deliveries, total, nil
+}
@@ -0,0 +1,83 @@
+// Package cache wraps repositories with a Redis read-through cache for GetByID.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go-api-template/internal/storage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisUserPrefix = "cache:user:"
+	RedisJobPrefix  = "cache:job:"
+)
+
+// Config selects which repositories are cached. The zero value caches nothing.
+type Config struct {
+	Client *redis.Client
+	TTL    time.Duration
+	Users  bool
+	Jobs   bool
+}
+
+// UserRepository wraps repo with the cache when user caching is enabled, and returns it as is otherwise.
+func (c Config) UserRepository(repo storage.UserRepository) storage.UserRepository {
+	if c.Client == nil || !c.Users || c.TTL <= 0 {
+		return repo
+	}
+	return NewUserRepo(repo, c.Client, c.TTL)
+}
+
+// JobRepository wraps repo with the cache when job caching is enabled, and returns it as is otherwise.
+func (c Config) JobRepository(repo storage.JobRepository) storage.JobRepository {
+	if c.Client == nil || !c.Jobs || c.TTL <= 0 {
+		return repo
+	}
+	return NewJobRepo(repo, c.Client, c.TTL)
+}
+
+// readThrough returns the value cached under key, calling load and caching its result on a miss.
+// Redis failures are logged and fall back to load, so an outage only costs the query it would have saved.
+func readThrough[T any](ctx context.Context, client *redis.Client, key string, ttl time.Duration, load func() (*T, error)) (*T, error) {
+	raw, err := client.Get(ctx, key).Bytes()
+	if err == nil {
+		var cached T
+		if err := json.Unmarshal(raw, &cached); err == nil {
+			return &cached, nil
+		}
+		log.Printf("Error decoding cached %s, reloading: %v\n", key, err)
+	} else if !errors.Is(err, redis.Nil) {
+		log.Printf("Error reading cached %s: %v\n", key, err)
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err // Misses such as ErrNotFound aren't cached
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Error encoding %s for the cache: %v\n", key, err)
+		return value, nil
+	}
+	if err := client.Set(ctx, key, encoded, ttl).Err(); err != nil {
+		log.Printf("Error caching %s: %v\n", key, err)
+	}
+	return value, nil
+}
+
+// invalidate drops keys from the cache. A failure is only logged: the write itself succeeded, and
+// the TTL bounds how long the stale copy can be served.
+func invalidate(ctx context.Context, client *redis.Client, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := client.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Error invalidating cached %v: %v\n", keys, err)
+	}
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTTL = 5 * time.Minute
+
+// stubUserRepo serves user from memory, counting GetByID calls.
+type stubUserRepo struct {
+	storage.UserRepository
+	user  models.User
+	reads int
+}
+
+func (r *stubUserRepo) GetByID(ctx context.Context, req *dto.GetUserByIdRequest) (*models.User, error) {
+	r.reads++
+	if req.ID != r.user.ID {
+		return nil, storage.ErrNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *stubUserRepo) Update(ctx context.Context, req *dto.UpdateUserRequest) (*models.User, error) {
+	if req.Name != nil {
+		r.user.Name = *req.Name
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *stubUserRepo) WithTx(tx pgx.Tx) storage.UserRepository { return r }
+
+// stubJobRepo serves job from memory, counting GetByID calls.
+type stubJobRepo struct {
+	storage.JobRepository
+	job   models.Job
+	reads int
+}
+
+func (r *stubJobRepo) GetByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error) {
+	r.reads++
+	if req.ID != r.job.ID {
+		return nil, storage.ErrNotFound
+	}
+	job := r.job
+	return &job, nil
+}
+
+func (r *stubJobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*models.Job, error) {
+	r.job.Version++
+	job := r.job
+	return &job, nil
+}
+
+func (r *stubJobRepo) WithTx(tx pgx.Tx) storage.JobRepository { return r }
+
+func testUser() models.User {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return models.User{ID: uuid.New(), Name: "Cached User", Email: "cached@example.com", PasswordHash: "$2a$10$hash", Role: models.UserRoleUser, CreatedAt: createdAt, UpdatedAt: createdAt}
+}
+
+func TestUserRepo_CacheHitSkipsRepo(t *testing.T) {
+	ctx := context.Background()
+	redisClient, mock := redismock.NewClientMock()
+	inner := &stubUserRepo{user: testUser()}
+	repo := NewUserRepo(inner, redisClient, testTTL)
+	key := RedisUserPrefix + inner.user.ID.String()
+
+	encoded, err := json.Marshal(cachedUser{User: inner.user, PasswordHash: inner.user.PasswordHash})
+	require.NoError(t, err)
+	mock.ExpectGet(key).RedisNil()
+	mock.ExpectSet(key, encoded, testTTL).SetVal("OK")
+	mock.ExpectGet(key).SetVal(string(encoded))
+
+	first, err := repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: inner.user.ID})
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: inner.user.ID})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, inner.reads, "The second read must be served from the cache")
+	assert.Equal(t, first, second)
+	assert.Equal(t, inner.user.PasswordHash, second.PasswordHash, "The cached user must keep its password hash")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepo_UpdateBustsCache(t *testing.T) {
+	ctx := context.Background()
+	redisClient, mock := redismock.NewClientMock()
+	inner := &stubUserRepo{user: testUser()}
+	repo := NewUserRepo(inner, redisClient, testTTL)
+	key := RedisUserPrefix + inner.user.ID.String()
+
+	stale, err := json.Marshal(cachedUser{User: inner.user, PasswordHash: inner.user.PasswordHash})
+	require.NoError(t, err)
+	mock.ExpectGet(key).SetVal(string(stale))
+	cached, err := repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: inner.user.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "Cached User", cached.Name)
+
+	mock.ExpectDel(key).SetVal(1)
+	name := "Renamed"
+	_, err = repo.Update(ctx, &dto.UpdateUserRequest{ID: inner.user.ID, Name: &name})
+	require.NoError(t, err)
+
+	fresh, err := json.Marshal(cachedUser{User: inner.user, PasswordHash: inner.user.PasswordHash})
+	require.NoError(t, err)
+	mock.ExpectGet(key).RedisNil()
+	mock.ExpectSet(key, fresh, testTTL).SetVal("OK")
+	updated, err := repo.GetByID(ctx, &dto.GetUserByIdRequest{ID: inner.user.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Name, "A read after Update must not see the old cached user")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepo_NotFoundIsNotCached(t *testing.T) {
+	redisClient, mock := redismock.NewClientMock()
+	repo := NewUserRepo(&stubUserRepo{user: testUser()}, redisClient, testTTL)
+	missing := uuid.New()
+
+	mock.ExpectGet(RedisUserPrefix + missing.String()).RedisNil()
+	_, err := repo.GetByID(context.Background(), &dto.GetUserByIdRequest{ID: missing})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepo_CacheHitAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	redisClient, mock := redismock.NewClientMock()
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	inner := &stubJobRepo{job: models.Job{ID: uuid.New(), Rate: 50, Duration: 20, InvoiceInterval: 10, EmployerID: uuid.New(), State: models.JobStateWaiting, Version: 1, CreatedAt: createdAt, UpdatedAt: createdAt}}
+	repo := NewJobRepo(inner, redisClient, testTTL)
+	key := RedisJobPrefix + inner.job.ID.String()
+
+	encoded, err := json.Marshal(inner.job)
+	require.NoError(t, err)
+	mock.ExpectGet(key).SetVal(string(encoded))
+	job, err := repo.GetByID(ctx, &dto.GetJobByIDRequest{ID: inner.job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, inner.reads, "A cache hit must not reach the repository")
+	assert.Equal(t, inner.job, *job)
+
+	// Inside a transaction reads bypass the cache and writes still invalidate
+	txRepo := repo.WithTx(nil)
+	_, err = txRepo.GetByID(ctx, &dto.GetJobByIDRequest{ID: inner.job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.reads)
+
+	mock.ExpectDel(key).SetVal(1)
+	_, err = txRepo.Update(ctx, &dto.UpdateJobRequest{ID: inner.job.ID, Version: 1})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConfig_OnlyWrapsEnabledRepositories(t *testing.T) {
+	redisClient, _ := redismock.NewClientMock()
+	users, jobs := &stubUserRepo{}, &stubJobRepo{}
+
+	disabled := Config{Client: redisClient, TTL: testTTL}
+	assert.Same(t, users, disabled.UserRepository(users))
+	assert.Same(t, jobs, disabled.JobRepository(jobs))
+
+	enabled := Config{Client: redisClient, TTL: testTTL, Users: true, Jobs: true}
+	assert.IsType(t, &UserRepo{}, enabled.UserRepository(users))
+	assert.IsType(t, &JobRepo{}, enabled.JobRepository(jobs))
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// JobRepo caches GetByID in front of another storage.JobRepository and invalidates on writes.
+// Every other method goes straight to the wrapped repository.
+type JobRepo struct {
+	storage.JobRepository
+	client *redis.Client
+	ttl    time.Duration
+	inTx   bool // Reads inside a transaction bypass the cache, so uncommitted rows are never cached
+}
+
+// NewJobRepo wraps repo, caching jobs for ttl.
+func NewJobRepo(repo storage.JobRepository, client *redis.Client, ttl time.Duration) *JobRepo {
+	return &JobRepo{JobRepository: repo, client: client, ttl: ttl}
+}
+
+// WithTx creates a new JobRepo with the transaction.
+func (r *JobRepo) WithTx(tx pgx.Tx) storage.JobRepository {
+	return &JobRepo{JobRepository: r.JobRepository.WithTx(tx), client: r.client, ttl: r.ttl, inTx: true}
+}
+
+// Compile-time checks to ensure JobRepo implements JobRepository and CacheInvalidator
+var (
+	_ storage.JobRepository    = (*JobRepo)(nil)
+	_ storage.CacheInvalidator = (*JobRepo)(nil)
+)
+
+func jobKey(id uuid.UUID) string {
+	return RedisJobPrefix + id.String()
+}
+
+func (r *JobRepo) GetByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error) {
+	if r.inTx {
+		return r.JobRepository.GetByID(ctx, req)
+	}
+	return readThrough(ctx, r.client, jobKey(req.ID), r.ttl, func() (*models.Job, error) {
+		return r.JobRepository.GetByID(ctx, req)
+	})
+}
+
+func (r *JobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*models.Job, error) {
+	job, err := r.JobRepository.Update(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.ID)
+	return job, nil
+}
+
+func (r *JobRepo) AssignContractor(ctx context.Context, req *dto.AssignContractorRequest) (*models.Job, error) {
+	job, err := r.JobRepository.AssignContractor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.JobID)
+	return job, nil
+}
+
+func (r *JobRepo) TransferOwnership(ctx context.Context, req *dto.TransferJobRequest) (*models.Job, error) {
+	job, err := r.JobRepository.TransferOwnership(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.JobID)
+	return job, nil
+}
+
+func (r *JobRepo) SetAutoInvoice(ctx context.Context, req *dto.SetJobAutoInvoiceRequest) (*models.Job, error) {
+	job, err := r.JobRepository.SetAutoInvoice(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.JobID)
+	return job, nil
+}
+
+func (r *JobRepo) AdvanceAutoInvoice(ctx context.Context, req *dto.AdvanceAutoInvoiceRequest) (bool, error) {
+	advanced, err := r.JobRepository.AdvanceAutoInvoice(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	if advanced {
+		r.Invalidate(ctx, req.JobID)
+	}
+	return advanced, nil
+}
+
+func (r *JobRepo) Delete(ctx context.Context, req *dto.DeleteJobRequest) error {
+	if err := r.JobRepository.Delete(ctx, req); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, req.ID)
+	return nil
+}
+
+func (r *JobRepo) Restore(ctx context.Context, req *dto.RestoreJobRequest) (*models.Job, error) {
+	job, err := r.JobRepository.Restore(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.ID)
+	return job, nil
+}
+
+// Invalidate drops the cached copies of the given jobs.
+func (r *JobRepo) Invalidate(ctx context.Context, ids ...uuid.UUID) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = jobKey(id)
+	}
+	invalidate(ctx, r.client, keys...)
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// UserRepo caches GetByID in front of another storage.UserRepository and invalidates on writes.
+// Every other method goes straight to the wrapped repository.
+type UserRepo struct {
+	storage.UserRepository
+	client *redis.Client
+	ttl    time.Duration
+	inTx   bool // Reads inside a transaction bypass the cache, so uncommitted rows are never cached
+}
+
+// NewUserRepo wraps repo, caching users for ttl.
+func NewUserRepo(repo storage.UserRepository, client *redis.Client, ttl time.Duration) *UserRepo {
+	return &UserRepo{UserRepository: repo, client: client, ttl: ttl}
+}
+
+// WithTx creates a new UserRepo with the transaction.
+func (r *UserRepo) WithTx(tx pgx.Tx) storage.UserRepository {
+	return &UserRepo{UserRepository: r.UserRepository.WithTx(tx), client: r.client, ttl: r.ttl, inTx: true}
+}
+
+// Compile-time checks to ensure UserRepo implements UserRepository and CacheInvalidator
+var (
+	_ storage.UserRepository   = (*UserRepo)(nil)
+	_ storage.CacheInvalidator = (*UserRepo)(nil)
+)
+
+// cachedUser is the cached form of a user. models.User leaves the password hash out of its JSON,
+// but callers such as ChangePassword need it.
+type cachedUser struct {
+	models.User
+	PasswordHash string `json:"password_hash"`
+}
+
+func userKey(id uuid.UUID) string {
+	return RedisUserPrefix + id.String()
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, req *dto.GetUserByIdRequest) (*models.User, error) {
+	if r.inTx {
+		return r.UserRepository.GetByID(ctx, req)
+	}
+	cached, err := readThrough(ctx, r.client, userKey(req.ID), r.ttl, func() (*cachedUser, error) {
+		user, err := r.UserRepository.GetByID(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &cachedUser{User: *user, PasswordHash: user.PasswordHash}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	user := cached.User
+	user.PasswordHash = cached.PasswordHash
+	return &user, nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, req *dto.UpdateUserRequest) (*models.User, error) {
+	user, err := r.UserRepository.Update(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.ID)
+	return user, nil
+}
+
+func (r *UserRepo) Delete(ctx context.Context, req *dto.DeleteUserRequest) error {
+	if err := r.UserRepository.Delete(ctx, req); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, req.ID)
+	return nil
+}
+
+func (r *UserRepo) Restore(ctx context.Context, req *dto.RestoreUserRequest) (*models.User, error) {
+	user, err := r.UserRepository.Restore(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.ID)
+	return user, nil
+}
+
+func (r *UserRepo) SetAvatarURL(ctx context.Context, req *dto.SetUserAvatarRequest) (*models.User, error) {
+	user, err := r.UserRepository.SetAvatarURL(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, req.ID)
+	return user, nil
+}
+
+// Invalidate drops the cached copies of the given users.
+func (r *UserRepo) Invalidate(ctx context.Context, ids ...uuid.UUID) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = userKey(id)
+	}
+	invalidate(ctx, r.client, keys...)
+}
@@ -1,11 +1,45 @@
 package storage
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // --- Standard Storage Errors ---
 var (
 	ErrNotFound       = errors.New("resource not found")
 	ErrConflict       = errors.New("resource conflict (e.g., duplicate unique field)") // General conflict
 	ErrDuplicateEmail = errors.New("email address already exists") // Specific conflict for email
+	ErrInvalidInput   = errors.New("invalid input")                 // e.g., unsupported sort field
+	ErrCanceled       = errors.New("operation canceled")            // The request was canceled or timed out; wraps the context error
 	// Add other custom errors as needed
-)
\ No newline at end of file
+)
+
+// ConflictError is an ErrConflict raised by the database, keeping its SQLSTATE code so callers can
+// tell a transient conflict, which succeeds when retried, from one that will keep failing.
+type ConflictError struct {
+	Code string // SQLSTATE, e.g. 23505 unique_violation or 40001 serialization_failure
+	Err  error
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v (SQLSTATE %s): %v", ErrConflict, e.Code, e.Err)
+}
+
+// Unwrap makes errors.Is match both ErrConflict and the underlying database error.
+func (e *ConflictError) Unwrap() []error {
+	return []error{ErrConflict, e.Err}
+}
+
+// Retryable reports whether the conflict came from concurrent transactions rather than the data
+// itself: a serialization failure or deadlock goes away when retried, a constraint violation doesn't.
+func (e *ConflictError) Retryable() bool {
+	return e.Code == "40001" || e.Code == "40P01" // serialization_failure, deadlock_detected
+}
+
+// IsRetryableConflict reports whether err wraps a ConflictError that is worth retrying. Conflicts
+// without a database cause, such as a row no longer matching an UPDATE's conditions, are not.
+func IsRetryableConflict(err error) bool {
+	var conflictErr *ConflictError
+	return errors.As(err, &conflictErr) && conflictErr.Retryable()
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIKeyRepo implements the storage.APIKeyRepository interface using PostgreSQL.
+type APIKeyRepo struct {
+	db Querier
+}
+
+// NewAPIKeyRepo creates a new APIKeyRepo.
+func NewAPIKeyRepo(db *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new APIKeyRepo with the transaction.
+func (r *APIKeyRepo) WithTx(tx pgx.Tx) storage.APIKeyRepository {
+	return &APIKeyRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure APIKeyRepo implements APIKeyRepository
+var _ storage.APIKeyRepository = (*APIKeyRepo)(nil)
+
+const apiKeyColumns = `id, key_hash, owner, scopes, created_by, created_at, revoked_at`
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(&key.ID, &key.KeyHash, &key.Owner, &key.Scopes, &key.CreatedBy, &key.CreatedAt, &key.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create saves a new API key. A clashing hash is reported as storage.ErrConflict.
+func (r *APIKeyRepo) Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO api_keys (id, key_hash, owner, scopes, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING ` + apiKeyColumns
+	createdKey, err := scanAPIKey(r.db.QueryRow(ctx, query, key.ID, key.KeyHash, key.Owner, key.Scopes, key.CreatedBy))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			log.Printf("Error creating API key for %s: duplicate key hash\n", key.Owner)
+			return nil, fmt.Errorf("failed to create API key: %w", storage.ErrConflict)
+		}
+		log.Printf("Error creating API key for %s: %v\n", key.Owner, err)
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	log.Printf("API key created successfully with ID: %s", createdKey.ID)
+	return createdKey, nil
+}
+
+// GetByHash returns the key with the given hash, revoked or not.
+func (r *APIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error scanning API key by hash: %v\n", err)
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return key, nil
+}
+
+// Revoke marks the key as revoked. Revoking an already revoked key keeps its original revocation time.
+func (r *APIKeyRepo) Revoke(ctx context.Context, req *dto.RevokeAPIKeyRequest) (*models.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING ` + apiKeyColumns
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, req.ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("API key not found for revocation with ID: %s\n", req.ID)
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error revoking API key %s: %v\n", req.ID, err)
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return key, nil
+}
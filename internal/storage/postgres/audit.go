@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AuditRepo implements the storage.AuditRepository interface using PostgreSQL.
+type AuditRepo struct {
+	db Querier
+}
+
+// NewAuditRepo creates a new AuditRepo.
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new AuditRepo with the transaction.
+func (r *AuditRepo) WithTx(tx pgx.Tx) storage.AuditRepository {
+	return &AuditRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure AuditRepo implements AuditRepository
+var _ storage.AuditRepository = (*AuditRepo)(nil)
+
+// Record stores a new audit entry, filling in its ID and creation time.
+func (r *AuditRepo) Record(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor_id, impersonator_id, action, entity_type, entity_id, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query, entry.ActorID, entry.ImpersonatorID, entry.Action, entry.EntityType, entry.EntityID, entry.Details).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		log.Printf("Error recording audit entry %s for %s %s: %v\n", entry.Action, entry.EntityType, entry.EntityID, err)
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListByEntity retrieves every audit entry about one entity, oldest first.
+func (r *AuditRepo) ListByEntity(ctx context.Context, entityType string, entityID uuid.UUID) ([]models.AuditEntry, error) {
+	query := `
+		SELECT id, actor_id, impersonator_id, action, entity_type, entity_id, details, created_at
+		FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, entityType, entityID)
+	if err != nil {
+		log.Printf("Error querying audit entries for %s %s: %v\n", entityType, entityID, err)
+		return nil, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AuditEntry])
+	if err != nil {
+		log.Printf("Error scanning audit entries for %s %s: %v\n", entityType, entityID, err)
+		return nil, fmt.Errorf("failed to scan audit entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-template/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// cancelAwareQuerier wraps a Querier so that every error caused by the query's context being
+// canceled or timing out wraps storage.ErrCanceled, whichever repository method ran into it.
+type cancelAwareQuerier struct {
+	Querier
+}
+
+// cancelAware wraps db in a cancelAwareQuerier, unless it already is one.
+func cancelAware(db Querier) Querier {
+	if q, ok := db.(cancelAwareQuerier); ok {
+		return q
+	}
+	return cancelAwareQuerier{Querier: db}
+}
+
+func (q cancelAwareQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tag, err := q.Querier.Exec(ctx, sql, arguments...)
+	return tag, mapCanceled(ctx, err)
+}
+
+func (q cancelAwareQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := q.Querier.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapCanceled(ctx, err)
+	}
+	return cancelAwareRows{Rows: rows, ctx: ctx}, nil
+}
+
+func (q cancelAwareQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return cancelAwareRow{Row: q.Querier.QueryRow(ctx, sql, args...), ctx: ctx}
+}
+
+// cancelAwareRows maps the errors of rows read while the context is canceled.
+type cancelAwareRows struct {
+	pgx.Rows
+	ctx context.Context
+}
+
+func (r cancelAwareRows) Scan(dest ...any) error { return mapCanceled(r.ctx, r.Rows.Scan(dest...)) }
+
+func (r cancelAwareRows) Err() error { return mapCanceled(r.ctx, r.Rows.Err()) }
+
+// cancelAwareRow maps the error of a row scanned while the context is canceled.
+type cancelAwareRow struct {
+	pgx.Row
+	ctx context.Context
+}
+
+func (r cancelAwareRow) Scan(dest ...any) error { return mapCanceled(r.ctx, r.Row.Scan(dest...)) }
+
+// mapCanceled wraps err with storage.ErrCanceled and the context error when it was caused by ctx
+// being canceled or timing out, so callers can tell a client going away from a database failure.
+// Other errors, such as pgx.ErrNoRows, are returned unchanged.
+func mapCanceled(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, storage.ErrCanceled) {
+		return err
+	}
+	cause := ctx.Err()
+	if cause == nil {
+		switch {
+		case errors.Is(err, context.Canceled):
+			cause = context.Canceled
+		case errors.Is(err, context.DeadlineExceeded):
+			cause = context.DeadlineExceeded
+		default:
+			return err
+		}
+	}
+	if errors.Is(err, cause) {
+		return fmt.Errorf("%w: %w", storage.ErrCanceled, err)
+	}
+	// e.g. the server's query_canceled error after pgx sent it a cancel request
+	return fmt.Errorf("%w: %w: %w", storage.ErrCanceled, cause, err)
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUnreachablePool returns a pool that never connects: it's only dialed on first use, and every
+// test call gives up on its context before that.
+func newUnreachablePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://test@127.0.0.1:1/test?connect_timeout=1")
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestRepo_CanceledContext(t *testing.T) {
+	repo := NewJobRepo(newUnreachablePool(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repo.GetByID(ctx, &dto.GetJobByIDRequest{ID: uuid.New()})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, storage.ErrNotFound)
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	_, err = repo.ListByEmployer(ctx, &dto.ListJobsByEmployerRequest{EmployerID: uuid.New(), Limit: 10})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrCanceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMapCanceled(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	queryCanceled := &pgconn.PgError{Code: "57014"} // What the server answers pgx's cancel request with
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		expectedIs  []error
+		notCanceled bool
+	}{
+		{name: "Nil", ctx: canceled, err: nil, notCanceled: true},
+		{name: "NoRows", ctx: context.Background(), err: pgx.ErrNoRows, expectedIs: []error{pgx.ErrNoRows}, notCanceled: true},
+		{name: "ContextError", ctx: context.Background(), err: context.Canceled, expectedIs: []error{context.Canceled}},
+		{name: "ServerCancel", ctx: canceled, err: queryCanceled, expectedIs: []error{context.Canceled, queryCanceled}},
+		{name: "AlreadyMapped", ctx: canceled, err: storage.ErrCanceled, expectedIs: []error{storage.ErrCanceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mapCanceled(tt.ctx, tt.err)
+			if tt.notCanceled {
+				assert.False(t, errors.Is(err, storage.ErrCanceled))
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			assert.ErrorIs(t, err, storage.ErrCanceled)
+			for _, target := range tt.expectedIs {
+				assert.ErrorIs(t, err, target)
+			}
+		})
+	}
+}
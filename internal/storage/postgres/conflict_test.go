@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingQuerier answers every query with err, as if Postgres had rejected the statement.
+type failingQuerier struct {
+	err error
+}
+
+func (q failingQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, q.err
+}
+
+func (q failingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, q.err
+}
+
+func (q failingQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return failingRow{err: q.err}
+}
+
+type failingRow struct {
+	err error
+}
+
+func (r failingRow) Scan(dest ...any) error { return r.err }
+
+func TestInvoiceRepo_Create_Conflicts(t *testing.T) {
+	tests := []struct {
+		name              string
+		pgCode            string
+		expectedRetryable bool
+	}{
+		{name: "UniqueViolation", pgCode: "23505", expectedRetryable: false},
+		{name: "ForeignKeyViolation", pgCode: "23503", expectedRetryable: false},
+		{name: "SerializationFailure", pgCode: "40001", expectedRetryable: true},
+		{name: "Deadlock", pgCode: "40P01", expectedRetryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: tt.pgCode}
+			repo := &InvoiceRepo{db: cancelAware(failingQuerier{err: pgErr})}
+
+			_, err := repo.Create(context.Background(), &models.Invoice{ID: uuid.New(), JobID: uuid.New(), IntervalNumber: 1})
+			require.Error(t, err)
+			assert.ErrorIs(t, err, storage.ErrConflict)
+			assert.ErrorIs(t, err, pgErr, "the pg error should stay in the chain")
+			assert.Equal(t, tt.expectedRetryable, storage.IsRetryableConflict(err))
+
+			var conflictErr *storage.ConflictError
+			require.True(t, errors.As(err, &conflictErr))
+			assert.Equal(t, tt.pgCode, conflictErr.Code)
+		})
+	}
+}
+
+func TestJobRepo_AssignContractor_Conflicts(t *testing.T) {
+	req := &dto.AssignContractorRequest{JobID: uuid.New(), ContractorID: uuid.New()}
+
+	// The job no longer matched the UPDATE's conditions: someone else got it, retrying won't help
+	repo := &JobRepo{db: cancelAware(failingQuerier{err: pgx.ErrNoRows})}
+	_, err := repo.AssignContractor(context.Background(), req)
+	assert.ErrorIs(t, err, storage.ErrConflict)
+	assert.False(t, storage.IsRetryableConflict(err))
+
+	repo = &JobRepo{db: cancelAware(failingQuerier{err: &pgconn.PgError{Code: "40001"}})}
+	_, err = repo.AssignContractor(context.Background(), req)
+	assert.ErrorIs(t, err, storage.ErrConflict)
+	assert.True(t, storage.IsRetryableConflict(err))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
+
+	"go-api-template/internal/storage"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -16,8 +19,56 @@ type Querier interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// jobSortColumns whitelists the columns job listings can be sorted by.
+var jobSortColumns = map[string]string{
+	"rate":       "rate",
+	"duration":   "duration",
+	"created_at": "created_at",
+}
+
+// buildJobOrderClause translates the optional sort parameters into an ORDER BY clause.
+// Defaults to created_at DESC; unknown fields or directions return storage.ErrInvalidInput.
+func buildJobOrderClause(sortBy, sortOrder *string) (string, error) {
+	column := "created_at"
+	if sortBy != nil {
+		col, ok := jobSortColumns[*sortBy]
+		if !ok {
+			return "", fmt.Errorf("%w: unsupported sort field %q", storage.ErrInvalidInput, *sortBy)
+		}
+		column = col
+	}
+
+	direction := "DESC"
+	if sortOrder != nil {
+		switch strings.ToLower(*sortOrder) {
+		case "asc":
+			direction = "ASC"
+		case "desc":
+			direction = "DESC"
+		default:
+			return "", fmt.Errorf("%w: unsupported sort order %q", storage.ErrInvalidInput, *sortOrder)
+		}
+	}
+
+	// Tie-break on id so pagination stays stable when sort values repeat
+	return fmt.Sprintf(" ORDER BY %s %s, id ASC", column, direction), nil
+}
+
+// updatedSinceOrderClause orders an incremental sync by last change, oldest first, so a client can pass
+// the updated_at of the last row it received as the next updated_since.
+const updatedSinceOrderClause = " ORDER BY updated_at ASC, id ASC"
+
+// buildJobListOrderClause is buildJobOrderClause for the job listings, which order by last change when
+// filtering on updatedSince and no sort field was asked for.
+func buildJobListOrderClause(sortBy, sortOrder *string, updatedSince *time.Time) (string, error) {
+	if updatedSince != nil && sortBy == nil {
+		return updatedSinceOrderClause, nil
+	}
+	return buildJobOrderClause(sortBy, sortOrder)
+}
+
 // buildJobListQuery constructs the SQL query for listing jobs based on filters.
-func (r *JobRepo) buildJobListQuery(baseQuery string, conditions []string, args *[]interface{}, reqOffset, reqLimit int) string {
+func (r *JobRepo) buildJobListQuery(baseQuery string, conditions []string, args *[]interface{}, orderClause string, reqOffset, reqLimit int) string {
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(baseQuery)
 
@@ -26,7 +77,7 @@ func (r *JobRepo) buildJobListQuery(baseQuery string, conditions []string, args
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
 	}
 
-	queryBuilder.WriteString(" ORDER BY created_at DESC") // Default ordering
+	queryBuilder.WriteString(orderClause)
 
 	// Add LIMIT and OFFSET
 	*args = append(*args, reqLimit)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"strings" // For building SQL query
+	"time"
 
 	"go-api-template/internal/models"
 	"go-api-template/internal/storage"
@@ -26,12 +27,12 @@ type InvoiceRepo struct {
 
 // NewInvoiceRepo creates a new InvoiceRepo.
 func NewInvoiceRepo(db *pgxpool.Pool) *InvoiceRepo {
-	return &InvoiceRepo{db: db}
+	return &InvoiceRepo{db: cancelAware(db)}
 }
 
 // WithTx creates a new InvoiceRepo with the transaction.
 func (r *InvoiceRepo) WithTx(tx pgx.Tx) storage.InvoiceRepository {
-	return &InvoiceRepo{db: tx}
+	return &InvoiceRepo{db: cancelAware(tx)}
 }
 
 // Compile-time check to ensure InvoiceRepo implements InvoiceRepository
@@ -45,28 +46,46 @@ func (r *InvoiceRepo) Create(ctx context.Context, invoice *models.Invoice) (*mod
 	if invoice.State == "" {
 		invoice.State = models.InvoiceStateWaiting // Ensure default if not set
 	}
+	if invoice.DueDate.IsZero() {
+		invoice.DueDate = time.Now().AddDate(0, 0, models.DefaultInvoicePaymentTermDays)
+	}
+	if invoice.Currency == "" {
+		invoice.Currency = models.DefaultCurrency
+	}
 
 	// Insert the Invoice using data from the input model
 	query := `
-		INSERT INTO invoices (id, value, state, job_id, interval_number, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-		RETURNING id, value, state, job_id, interval_number, created_at, updated_at
+		INSERT INTO invoices (id, value, currency, state, job_id, contractor_id, interval_number, invoice_number, due_date, created_by, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10, NOW(), NOW())
+		RETURNING id, value, currency, state, job_id, contractor_id, interval_number, invoice_number, paid_at, due_date, created_by, updated_by, created_at, updated_at
 	`
 	row := r.db.QueryRow(ctx, query,
 		invoice.ID,
 		invoice.Value,          // Use value from input model
+		invoice.Currency,
 		invoice.State,          // Use state from input model
 		invoice.JobID,
+		invoice.ContractorID,
 		invoice.IntervalNumber, // Use interval number from input model
+		invoice.InvoiceNumber,
+		invoice.DueDate,
+		invoice.CreatedBy, // Also the first updated_by
 	)
 
 	var createdInvoice models.Invoice
 	err := row.Scan(
 		&createdInvoice.ID,
 		&createdInvoice.Value,
+		&createdInvoice.Currency,
 		&createdInvoice.State,
 		&createdInvoice.JobID,
+		&createdInvoice.ContractorID,
 		&createdInvoice.IntervalNumber,
+		&createdInvoice.InvoiceNumber,
+		&createdInvoice.PaidAt,
+		&createdInvoice.DueDate,
+		&createdInvoice.CreatedBy,
+		&createdInvoice.UpdatedBy,
 		&createdInvoice.CreatedAt,
 		&createdInvoice.UpdatedAt,
 	)
@@ -77,10 +96,13 @@ func (r *InvoiceRepo) Create(ctx context.Context, invoice *models.Invoice) (*mod
 			switch pgErr.Code {
 			case "23503": // foreign_key_violation (job_id doesn't exist)
 				log.Printf("Error creating invoice: Foreign key violation (job_id: %s): %v\n", invoice.JobID, err)
-				return nil, fmt.Errorf("failed to create invoice: invalid job ID: %w", storage.ErrConflict)
+				return nil, fmt.Errorf("failed to create invoice: invalid job ID: %w", &storage.ConflictError{Code: pgErr.Code, Err: err})
 			case "23505": // unique_violation (job_id + interval_number)
 				log.Printf("Error creating invoice: Unique constraint violation (job_id: %s, interval: %d): %v\n", invoice.JobID, invoice.IntervalNumber, err)
-				return nil, fmt.Errorf("failed to create invoice: invoice for interval %d already exists: %w", invoice.IntervalNumber, storage.ErrConflict)
+				return nil, fmt.Errorf("failed to create invoice: invoice for interval %d already exists: %w", invoice.IntervalNumber, &storage.ConflictError{Code: pgErr.Code, Err: err})
+			case "40001", "40P01": // serialization_failure, deadlock_detected: lost to a concurrent transaction
+				log.Printf("Error creating invoice: Concurrent transaction conflict (job_id: %s): %v\n", invoice.JobID, err)
+				return nil, fmt.Errorf("failed to create invoice: %w", &storage.ConflictError{Code: pgErr.Code, Err: err})
 			}
 		}
 		log.Printf("Error creating invoice: %v\n", err)
@@ -94,7 +116,7 @@ func (r *InvoiceRepo) Create(ctx context.Context, invoice *models.Invoice) (*mod
 // GetByID retrieves a specific invoice by its ID.
 func (r *InvoiceRepo) GetByID(ctx context.Context, req *dto.GetInvoiceByIDRequest) (*models.Invoice, error) {
 	query := `
-		SELECT id, value, state, job_id, interval_number, created_at, updated_at
+		SELECT id, value, currency, state, job_id, contractor_id, interval_number, invoice_number, paid_at, due_date, created_by, updated_by, created_at, updated_at
 		FROM invoices
 		WHERE id = $1
 	`
@@ -104,9 +126,16 @@ func (r *InvoiceRepo) GetByID(ctx context.Context, req *dto.GetInvoiceByIDReques
 	err := row.Scan(
 		&invoice.ID,
 		&invoice.Value,
+		&invoice.Currency,
 		&invoice.State,
 		&invoice.JobID,
+		&invoice.ContractorID,
 		&invoice.IntervalNumber,
+		&invoice.InvoiceNumber,
+		&invoice.PaidAt,
+		&invoice.DueDate,
+		&invoice.CreatedBy,
+		&invoice.UpdatedBy,
 		&invoice.CreatedAt,
 		&invoice.UpdatedAt,
 	)
@@ -123,37 +152,50 @@ func (r *InvoiceRepo) GetByID(ctx context.Context, req *dto.GetInvoiceByIDReques
 	return &invoice, nil
 }
 
-// ListByJob retrieves all invoices associated with a specific job, with optional state filtering.
-func (r *InvoiceRepo) ListByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, error) {
-	var queryBuilder strings.Builder
-	args := []interface{}{}
-	argID := 1
-
-	queryBuilder.WriteString(`
-		SELECT id, value, state, job_id, interval_number, created_at, updated_at
-		FROM invoices
-		WHERE job_id = $1
-	`)
-	args = append(args, req.JobID)
-	argID++
+// invoiceColumns lists the columns every invoice query selects, in models.Invoice field order for RowToStructByName.
+const invoiceColumns = `i.id, i.value, i.currency, i.state, i.job_id, i.contractor_id, i.interval_number, i.invoice_number, i.paid_at, i.due_date, i.created_by, i.updated_by, i.created_at, i.updated_at`
 
-	// Add optional state filter
+// jobInvoiceConditions builds the WHERE conditions and arguments shared by ListByJob, CountByJob and StreamByJob.
+func jobInvoiceConditions(req *dto.ListInvoicesByJobRequest) ([]string, []interface{}) {
+	conditions := []string{"i.job_id = $1"}
+	args := []interface{}{req.JobID}
+	if req.ContractorID != nil {
+		args = append(args, *req.ContractorID)
+		conditions = append(conditions, fmt.Sprintf("i.contractor_id = $%d", len(args)))
+	}
 	if req.State != nil {
-		queryBuilder.WriteString(fmt.Sprintf(" AND state = $%d", argID))
 		args = append(args, *req.State)
-		argID++
+		conditions = append(conditions, fmt.Sprintf("i.state = $%d", len(args)))
 	}
+	if req.MinInterval != nil {
+		args = append(args, *req.MinInterval)
+		conditions = append(conditions, fmt.Sprintf("i.interval_number >= $%d", len(args)))
+	}
+	if req.MaxInterval != nil {
+		args = append(args, *req.MaxInterval)
+		conditions = append(conditions, fmt.Sprintf("i.interval_number <= $%d", len(args)))
+	}
+	if req.UpdatedSince != nil {
+		args = append(args, *req.UpdatedSince)
+		conditions = append(conditions, fmt.Sprintf("i.updated_at > $%d", len(args)))
+	}
+	return conditions, args
+}
 
-	queryBuilder.WriteString(" ORDER BY interval_number ASC") // Order by interval
-
-	// Add LIMIT and OFFSET
-	args = append(args, req.Limit)
-	queryBuilder.WriteString(fmt.Sprintf(" LIMIT $%d", argID))
-	argID++
-	args = append(args, req.Offset)
-	queryBuilder.WriteString(fmt.Sprintf(" OFFSET $%d", argID))
+// jobInvoiceOrder orders a job's invoices by interval, or by last change when only recent changes are asked for.
+func jobInvoiceOrder(req *dto.ListInvoicesByJobRequest) string {
+	if req.UpdatedSince != nil {
+		return updatedSinceOrderClause
+	}
+	return " ORDER BY i.interval_number ASC"
+}
 
-	query := queryBuilder.String()
+// ListByJob retrieves all invoices associated with a specific job, with optional state and interval range filtering.
+func (r *InvoiceRepo) ListByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, error) {
+	conditions, args := jobInvoiceConditions(req)
+	args = append(args, req.Limit, req.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM invoices i WHERE %s%s LIMIT $%d OFFSET $%d`,
+		invoiceColumns, strings.Join(conditions, " AND "), jobInvoiceOrder(req), len(args)-1, len(args))
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -175,23 +217,272 @@ func (r *InvoiceRepo) ListByJob(ctx context.Context, req *dto.ListInvoicesByJobR
 	return invoices, nil
 }
 
+// CountByJob returns how many invoices match the ListByJob filters, ignoring pagination.
+func (r *InvoiceRepo) CountByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) (int, error) {
+	conditions, args := jobInvoiceConditions(req)
+	query := `SELECT COUNT(*) FROM invoices i WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		log.Printf("Error counting invoices by job %s: %v\n", req.JobID, err)
+		return 0, fmt.Errorf("failed to count invoices by job: %w", err)
+	}
+	return total, nil
+}
+
+// StreamByJob calls fn with every invoice matching the ListByJob filters, in the same order, ignoring
+// pagination. Rows are read one at a time, so the whole set is never held in memory. An error from fn
+// stops the stream and is returned as is.
+func (r *InvoiceRepo) StreamByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest, fn func(invoice *models.Invoice) error) error {
+	conditions, args := jobInvoiceConditions(req)
+	query := fmt.Sprintf(`SELECT %s FROM invoices i WHERE %s%s`, invoiceColumns, strings.Join(conditions, " AND "), jobInvoiceOrder(req))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying invoices for export of job %s: %v\n", req.JobID, err)
+		return fmt.Errorf("failed to query invoices for export: %w", err)
+	}
+	return streamInvoices(rows, fn)
+}
+
+// employerInvoiceConditions builds the WHERE conditions and arguments shared by ListByEmployer,
+// CountByEmployer and StreamByEmployer. Invoices of deleted jobs are skipped.
+func employerInvoiceConditions(req *dto.ListEmployerInvoicesRequest) ([]string, []interface{}) {
+	conditions := []string{"j.employer_id = $1", "j.deleted_at IS NULL"}
+	args := []interface{}{req.EmployerID}
+	if req.State != nil {
+		args = append(args, *req.State)
+		conditions = append(conditions, fmt.Sprintf("i.state = $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// ListByEmployer returns a page of the invoices on the employer's jobs, newest first.
+func (r *InvoiceRepo) ListByEmployer(ctx context.Context, req *dto.ListEmployerInvoicesRequest) ([]models.Invoice, error) {
+	conditions, args := employerInvoiceConditions(req)
+	args = append(args, req.Limit, req.Offset)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM invoices i
+		JOIN jobs j ON j.id = i.job_id
+		WHERE %s
+		ORDER BY i.created_at DESC, i.id ASC
+		LIMIT $%d OFFSET $%d
+	`, invoiceColumns, strings.Join(conditions, " AND "), len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying invoices for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to query invoices by employer: %w", err)
+	}
+	defer rows.Close()
+
+	invoices, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Invoice])
+	if err != nil {
+		log.Printf("Error scanning invoices for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to scan invoices by employer: %w", err)
+	}
+
+	if invoices == nil {
+		invoices = []models.Invoice{} // Return empty slice, not nil
+	}
+	return invoices, nil
+}
+
+// CountByEmployer returns how many invoices match the ListByEmployer filters, ignoring pagination.
+func (r *InvoiceRepo) CountByEmployer(ctx context.Context, req *dto.ListEmployerInvoicesRequest) (int, error) {
+	conditions, args := employerInvoiceConditions(req)
+	query := `SELECT COUNT(*) FROM invoices i JOIN jobs j ON j.id = i.job_id WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		log.Printf("Error counting invoices for employer %s: %v\n", req.EmployerID, err)
+		return 0, fmt.Errorf("failed to count invoices by employer: %w", err)
+	}
+	return total, nil
+}
+
+// StreamByEmployer calls fn with every invoice matching the ListByEmployer filters, in the same order,
+// ignoring pagination. An error from fn stops the stream and is returned as is.
+func (r *InvoiceRepo) StreamByEmployer(ctx context.Context, req *dto.ListEmployerInvoicesRequest, fn func(invoice *models.Invoice) error) error {
+	conditions, args := employerInvoiceConditions(req)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM invoices i
+		JOIN jobs j ON j.id = i.job_id
+		WHERE %s
+		ORDER BY i.created_at DESC, i.id ASC
+	`, invoiceColumns, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying invoices for export of employer %s: %v\n", req.EmployerID, err)
+		return fmt.Errorf("failed to query invoices for export: %w", err)
+	}
+	return streamInvoices(rows, fn)
+}
+
+// streamInvoices scans rows one at a time into fn, closing them when done.
+func streamInvoices(rows pgx.Rows, fn func(invoice *models.Invoice) error) error {
+	defer rows.Close()
+	for rows.Next() {
+		invoice, err := pgx.RowToStructByName[models.Invoice](rows)
+		if err != nil {
+			log.Printf("Error scanning invoice export row: %v\n", err)
+			return fmt.Errorf("failed to scan invoice export row: %w", err)
+		}
+		if err := fn(&invoice); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating invoice export rows: %v\n", err)
+		return fmt.Errorf("failed to iterate invoice export rows: %w", err)
+	}
+	return nil
+}
+
+// SumValueByEmployer returns the total value of all invoices on the employer's jobs per currency,
+// skipping deleted jobs. Currencies without invoices are left out.
+func (r *InvoiceRepo) SumValueByEmployer(ctx context.Context, req *dto.EmployerStatsRequest) (map[string]float64, error) {
+	query := `
+		SELECT i.currency, SUM(i.value)
+		FROM invoices i
+		JOIN jobs j ON j.id = i.job_id
+		WHERE j.employer_id = $1 AND j.deleted_at IS NULL
+		GROUP BY i.currency
+	`
+	rows, err := r.db.Query(ctx, query, req.EmployerID)
+	if err != nil {
+		log.Printf("Error summing invoices for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to sum invoices by employer: %w", err)
+	}
+	defer rows.Close()
+
+	totals := map[string]float64{}
+	for rows.Next() {
+		var currency string
+		var total float64
+		if err := rows.Scan(&currency, &total); err != nil {
+			log.Printf("Error scanning invoice sum for employer %s: %v\n", req.EmployerID, err)
+			return nil, fmt.Errorf("failed to scan invoice sum by employer: %w", err)
+		}
+		totals[currency] = total
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating invoice sums for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to read invoice sums by employer: %w", err)
+	}
+	return totals, nil
+}
+
+// SumUnpaidByContractor counts the invoices the contractor issued that aren't Paid and sums them, and
+// those of them that are overdue, per currency. Invoices of deleted jobs are skipped.
+func (r *InvoiceRepo) SumUnpaidByContractor(ctx context.Context, req *dto.ContractorDashboardRequest) (*models.UnpaidInvoiceTotals, error) {
+	query := `
+		SELECT i.currency, COUNT(*), SUM(i.value),
+			COALESCE(SUM(i.value) FILTER (WHERE i.state = $3 AND i.due_date < NOW()), 0)
+		FROM invoices i
+		JOIN jobs j ON j.id = i.job_id
+		WHERE i.contractor_id = $1 AND j.deleted_at IS NULL AND i.state <> $2
+		GROUP BY i.currency
+	`
+	rows, err := r.db.Query(ctx, query, req.UserID, models.InvoiceStatePaid, models.InvoiceStateComplete)
+	if err != nil {
+		log.Printf("Error summing unpaid invoices for contractor %s: %v\n", req.UserID, err)
+		return nil, fmt.Errorf("failed to sum unpaid invoices by contractor: %w", err)
+	}
+	defer rows.Close()
+
+	totals := &models.UnpaidInvoiceTotals{Total: map[string]float64{}, OverdueTotal: map[string]float64{}}
+	for rows.Next() {
+		var currency string
+		var count int
+		var total, overdue float64
+		if err := rows.Scan(&currency, &count, &total, &overdue); err != nil {
+			log.Printf("Error scanning unpaid invoice sums for contractor %s: %v\n", req.UserID, err)
+			return nil, fmt.Errorf("failed to scan unpaid invoice sums by contractor: %w", err)
+		}
+		totals.Count += count
+		totals.Total[currency] = total
+		totals.OverdueTotal[currency] = overdue
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating unpaid invoice sums for contractor %s: %v\n", req.UserID, err)
+		return nil, fmt.Errorf("failed to read unpaid invoice sums by contractor: %w", err)
+	}
+	return totals, nil
+}
+
+// SumValuesByJob returns the total value of all invoices on a job and of its Paid invoices.
+func (r *InvoiceRepo) SumValuesByJob(ctx context.Context, req *dto.GetJobBalanceRequest) (invoiced float64, paid float64, err error) {
+	query := `
+		SELECT COALESCE(SUM(value), 0), COALESCE(SUM(value) FILTER (WHERE state = $2), 0)
+		FROM invoices
+		WHERE job_id = $1
+	`
+
+	if err := r.db.QueryRow(ctx, query, req.JobID, models.InvoiceStatePaid).Scan(&invoiced, &paid); err != nil {
+		log.Printf("Error summing invoices for job %s: %v\n", req.JobID, err)
+		return 0, 0, fmt.Errorf("failed to sum invoices by job: %w", err)
+	}
+	return invoiced, paid, nil
+}
+
+// ListOverdueByEmployer returns the Complete invoices on the employer's jobs whose due date has
+// passed, oldest due first. Paid invoices are never overdue; deleted jobs are skipped.
+func (r *InvoiceRepo) ListOverdueByEmployer(ctx context.Context, req *dto.ListOverdueInvoicesRequest) ([]models.Invoice, error) {
+	query := `
+		SELECT i.id, i.value, i.currency, i.state, i.job_id, i.contractor_id, i.interval_number, i.invoice_number, i.paid_at, i.due_date, i.created_by, i.updated_by, i.created_at, i.updated_at
+		FROM invoices i
+		JOIN jobs j ON j.id = i.job_id
+		WHERE j.employer_id = $1 AND j.deleted_at IS NULL
+		  AND i.state = $2 AND i.due_date < NOW()
+		ORDER BY i.due_date ASC, i.interval_number ASC
+	`
+	rows, err := r.db.Query(ctx, query, req.EmployerID, models.InvoiceStateComplete)
+	if err != nil {
+		log.Printf("Error querying overdue invoices for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to query overdue invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Invoice])
+	if err != nil {
+		log.Printf("Error scanning overdue invoices for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to scan overdue invoices: %w", err)
+	}
+
+	if invoices == nil {
+		invoices = []models.Invoice{} // Return empty slice, not nil
+	}
+	return invoices, nil
+}
+
 // UpdateState modifies the state of an existing invoice.
 func (r *InvoiceRepo) UpdateState(ctx context.Context, req *dto.UpdateInvoiceStateRequest) (*models.Invoice, error) {
 	query := `
 		UPDATE invoices
-		SET state = $1, updated_at = NOW()
+		SET state = $1, updated_by = $3, updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, value, state, job_id, interval_number, created_at, updated_at
+		RETURNING id, value, currency, state, job_id, contractor_id, interval_number, invoice_number, paid_at, due_date, created_by, updated_by, created_at, updated_at
 	`
-	row := r.db.QueryRow(ctx, query, req.NewState, req.ID)
+	row := r.db.QueryRow(ctx, query, req.NewState, req.ID, req.UserId)
 
 	var updatedInvoice models.Invoice
 	err := row.Scan(
 		&updatedInvoice.ID,
 		&updatedInvoice.Value,
+		&updatedInvoice.Currency,
 		&updatedInvoice.State,
 		&updatedInvoice.JobID,
+		&updatedInvoice.ContractorID,
 		&updatedInvoice.IntervalNumber,
+		&updatedInvoice.InvoiceNumber,
+		&updatedInvoice.PaidAt,
+		&updatedInvoice.DueDate,
+		&updatedInvoice.CreatedBy,
+		&updatedInvoice.UpdatedBy,
 		&updatedInvoice.CreatedAt,
 		&updatedInvoice.UpdatedAt,
 	)
@@ -209,6 +500,57 @@ func (r *InvoiceRepo) UpdateState(ctx context.Context, req *dto.UpdateInvoiceSta
 	return &updatedInvoice, nil
 }
 
+// MarkPaid moves a Complete invoice to Paid and stamps paid_at. Returns storage.ErrConflict if the
+// invoice exists but is no longer Complete.
+func (r *InvoiceRepo) MarkPaid(ctx context.Context, req *dto.MarkInvoicePaidRequest) (*models.Invoice, error) {
+	query := `
+		UPDATE invoices
+		SET state = $1, paid_at = NOW(), updated_by = $4, updated_at = NOW()
+		WHERE id = $2 AND state = $3
+		RETURNING id, value, currency, state, job_id, contractor_id, interval_number, invoice_number, paid_at, due_date, created_by, updated_by, created_at, updated_at
+	`
+	row := r.db.QueryRow(ctx, query, models.InvoiceStatePaid, req.ID, models.InvoiceStateComplete, req.UserId)
+
+	var paidInvoice models.Invoice
+	err := row.Scan(
+		&paidInvoice.ID,
+		&paidInvoice.Value,
+		&paidInvoice.Currency,
+		&paidInvoice.State,
+		&paidInvoice.JobID,
+		&paidInvoice.ContractorID,
+		&paidInvoice.IntervalNumber,
+		&paidInvoice.InvoiceNumber,
+		&paidInvoice.PaidAt,
+		&paidInvoice.DueDate,
+		&paidInvoice.CreatedBy,
+		&paidInvoice.UpdatedBy,
+		&paidInvoice.CreatedAt,
+		&paidInvoice.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			var exists bool
+			if existsErr := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM invoices WHERE id = $1)`, req.ID).Scan(&exists); existsErr != nil {
+				log.Printf("Error checking invoice existence %s: %v\n", req.ID, existsErr)
+				return nil, fmt.Errorf("failed to mark invoice %s as paid: %w", req.ID, existsErr)
+			}
+			if exists {
+				log.Printf("Invoice %s is not Complete, cannot mark as paid\n", req.ID)
+				return nil, fmt.Errorf("%w: invoice is not Complete", storage.ErrConflict)
+			}
+			log.Printf("Invoice not found for payment with ID: %s\n", req.ID)
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error marking invoice %s as paid: %v\n", req.ID, err)
+		return nil, fmt.Errorf("failed to mark invoice %s as paid: %w", req.ID, err)
+	}
+
+	log.Printf("Invoice marked as paid successfully for ID: %s", paidInvoice.ID)
+	return &paidInvoice, nil
+}
+
 // Delete removes an invoice by its ID.
 func (r *InvoiceRepo) Delete(ctx context.Context, req *dto.DeleteInvoiceRequest) error {
 	query := `DELETE FROM invoices WHERE id = $1`
@@ -228,6 +570,18 @@ func (r *InvoiceRepo) Delete(ctx context.Context, req *dto.DeleteInvoiceRequest)
 	return nil
 }
 
+// DeletePaidByJobs permanently deletes the Paid invoices of the given jobs and returns how many were deleted.
+func (r *InvoiceRepo) DeletePaidByJobs(ctx context.Context, jobIDs []uuid.UUID) (int, error) {
+	query := `DELETE FROM invoices WHERE job_id = ANY($1) AND state = $2`
+
+	cmdTag, err := r.db.Exec(ctx, query, jobIDs, models.InvoiceStatePaid)
+	if err != nil {
+		log.Printf("Error deleting paid invoices of %d jobs: %v\n", len(jobIDs), err)
+		return 0, fmt.Errorf("failed to delete paid invoices: %w", err)
+	}
+	return int(cmdTag.RowsAffected()), nil
+}
+
 // GetMaxIntervalForJob retrieves the highest interval number for a given job.
 func (r *InvoiceRepo) GetMaxIntervalForJob(ctx context.Context, req *dto.GetMaxIntervalForJobRequest) (int, error) {
 	var maxInterval sql.NullInt32
@@ -251,3 +605,41 @@ func (r *InvoiceRepo) GetMaxIntervalForJob(ctx context.Context, req *dto.GetMaxI
 	return 0, nil // Should be covered by ErrNoRows, but return 0 as default
 }
 
+// NextInvoiceSequence increments and returns the employer's invoice counter, starting at 1. The
+// counter row stays locked until the surrounding transaction ends, so concurrent invoice creations
+// for the same employer are numbered one after another, and a rollback returns the number.
+func (r *InvoiceRepo) NextInvoiceSequence(ctx context.Context, req *dto.NextInvoiceSequenceRequest) (int64, error) {
+	query := `
+		INSERT INTO invoice_number_sequences (employer_id, last_value)
+		VALUES ($1, 1)
+		ON CONFLICT (employer_id) DO UPDATE SET last_value = invoice_number_sequences.last_value + 1
+		RETURNING last_value
+	`
+
+	var seq int64
+	if err := r.db.QueryRow(ctx, query, req.EmployerID).Scan(&seq); err != nil {
+		log.Printf("Error taking next invoice number for employer %s: %v\n", req.EmployerID, err)
+		return 0, fmt.Errorf("failed to take next invoice number: %w", err)
+	}
+	return seq, nil
+}
+
+// ListIntervalNumbersForJob retrieves the interval numbers that already have an invoice for a given job.
+func (r *InvoiceRepo) ListIntervalNumbersForJob(ctx context.Context, req *dto.ListIntervalNumbersForJobRequest) ([]int, error) {
+	query := `SELECT interval_number FROM invoices WHERE job_id = $1 ORDER BY interval_number`
+
+	rows, err := r.db.Query(ctx, query, req.JobID)
+	if err != nil {
+		log.Printf("Error querying interval numbers for job %s: %v\n", req.JobID, err)
+		return nil, fmt.Errorf("failed to query interval numbers for job %s: %w", req.JobID, err)
+	}
+	defer rows.Close()
+
+	intervals, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Printf("Error scanning interval numbers for job %s: %v\n", req.JobID, err)
+		return nil, fmt.Errorf("failed to scan interval numbers for job %s: %w", req.JobID, err)
+	}
+
+	return intervals, nil
+}
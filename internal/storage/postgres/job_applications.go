@@ -24,11 +24,11 @@ type JobApplicationRepo struct {
 
 // NewJobApplicationRepo creates a new JobApplicationRepo.
 func NewJobApplicationRepo(db *pgxpool.Pool) *JobApplicationRepo {
-	return &JobApplicationRepo{db: db}
+	return &JobApplicationRepo{db: cancelAware(db)}
 }
 // WithTx creates a new JobApplicationRepo with the transaction.
 func (r *JobApplicationRepo) WithTx(tx pgx.Tx) storage.JobApplicationRepository {
-	return &JobApplicationRepo{db: tx}
+	return &JobApplicationRepo{db: cancelAware(tx)}
 }
 // Compile-time check to ensure JobApplicationRepo implements JobApplicationRepository
 var _ storage.JobApplicationRepository = (*JobApplicationRepo)(nil)
@@ -39,12 +39,17 @@ func (r *JobApplicationRepo) Create(ctx context.Context, req *dto.CreateJobAppli
 		ContractorID:     req.ContractorID,
 		JobID:           req.JobID,
 		State:           models.JobApplicationWaiting, 
+		CoverMessage:    req.CoverMessage,
+		ProposedRate:    req.ProposedRate,
 	} // CreatedAt and UpdatedAt are set by the database
+	if req.State != "" {
+		jobApplication.State = req.State
+	}
 
 	query := `
-		INSERT INTO job_application (id, contractor_id, job_id, state, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		RETURNING id, contractor_id, job_id, state, created_at, updated_at
+		INSERT INTO job_application (id, contractor_id, job_id, state, cover_message, proposed_rate, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, contractor_id, job_id, state, cover_message, employer_note, proposed_rate, created_at, updated_at
 	`
 
 	row := r.db.QueryRow(ctx, query,
@@ -52,6 +57,8 @@ func (r *JobApplicationRepo) Create(ctx context.Context, req *dto.CreateJobAppli
 		jobApplication.ContractorID,
 		jobApplication.JobID,
 		jobApplication.State,
+		jobApplication.CoverMessage,
+		jobApplication.ProposedRate,
 	)
 
 	var createdJobApplication models.JobApplication
@@ -60,6 +67,9 @@ func (r *JobApplicationRepo) Create(ctx context.Context, req *dto.CreateJobAppli
 		&createdJobApplication.ContractorID, 
 		&createdJobApplication.JobID,
 		&createdJobApplication.State,
+		&createdJobApplication.CoverMessage,
+		&createdJobApplication.EmployerNote,
+		&createdJobApplication.ProposedRate,
 		&createdJobApplication.CreatedAt,
 		&createdJobApplication.UpdatedAt,
 	)
@@ -71,7 +81,7 @@ func (r *JobApplicationRepo) Create(ctx context.Context, req *dto.CreateJobAppli
 				log.Printf("Error creating jobApplication: Foreign key violation (job_id: %s, contractor_id: %s): %v\n", req.JobID, req.ContractorID, err)
 				return nil, fmt.Errorf("failed to create jobApplication: invalid job ID or contractor ID: %w", storage.ErrConflict)
 			}
-			if pgErr.Code == "23505" && pgErr.ConstraintName == "unique_application" { // unique_violation
+			if pgErr.Code == "23505" && pgErr.ConstraintName == "unique_active_application" { // unique_violation: already Waiting, Invited or Accepted
 				log.Printf("Error creating jobApplication: Unique constraint violation (job_id: %s, contractor_id: %s): %v\n", req.JobID, req.ContractorID, err)
 				return nil, fmt.Errorf("failed to create jobApplication: application already exists: %w", storage.ErrConflict)
 			}
@@ -86,7 +96,7 @@ func (r *JobApplicationRepo) Create(ctx context.Context, req *dto.CreateJobAppli
 
 func (r *JobApplicationRepo) GetByID(ctx context.Context, req *dto.GetJobApplicationByIDRequest) (*models.JobApplication, error) {
 	query := `
-		SELECT id, contractor_id, job_id, state, created_at, updated_at
+		SELECT id, contractor_id, job_id, state, cover_message, employer_note, proposed_rate, created_at, updated_at
 		FROM job_application
 		WHERE id = $1
 	`
@@ -99,6 +109,9 @@ func (r *JobApplicationRepo) GetByID(ctx context.Context, req *dto.GetJobApplica
 		&jobApplication.ContractorID,
 		&jobApplication.JobID,
 		&jobApplication.State,
+		&jobApplication.CoverMessage,
+		&jobApplication.EmployerNote,
+		&jobApplication.ProposedRate,
 		&jobApplication.CreatedAt,
 		&jobApplication.UpdatedAt,
 	)
@@ -121,13 +134,26 @@ func (r *JobApplicationRepo) ListByContractor(ctx context.Context, req *dto.List
 	argID := 1
 
 	queryBuilder.WriteString(`
-		SELECT id, contractor_id, job_id, state, created_at, updated_at
+		SELECT id, contractor_id, job_id, state, cover_message, employer_note, proposed_rate, created_at, updated_at
 		FROM job_application
 		WHERE contractor_id = $1 `)
 	args = append(args, req.ContractorID)
 	argID++
 
-	queryBuilder.WriteString("ORDER BY created_at DESC")
+	// Add optional state and last change filters (keep in sync with CountByContractor)
+	if req.State != nil {
+		queryBuilder.WriteString(fmt.Sprintf("AND state = $%d ", argID))
+		args = append(args, *req.State)
+		argID++
+	}
+	if req.UpdatedSince != nil {
+		queryBuilder.WriteString(fmt.Sprintf("AND updated_at > $%d", argID))
+		args = append(args, *req.UpdatedSince)
+		argID++
+		queryBuilder.WriteString(updatedSinceOrderClause)
+	} else {
+		queryBuilder.WriteString("ORDER BY created_at DESC")
+	}
 
 	// Add LIMIT and OFFSET
 	args = append(args, req.Limit)
@@ -166,13 +192,26 @@ func (r *JobApplicationRepo) ListByJob(ctx context.Context, req *dto.ListJobAppl
 	argID := 1
 
 	queryBuilder.WriteString(`
-		SELECT id, contractor_id, job_id, state, created_at, updated_at
+		SELECT id, contractor_id, job_id, state, cover_message, employer_note, proposed_rate, created_at, updated_at
 		FROM job_application
 		WHERE job_id = $1 `)
 	args = append(args, req.JobID)
 	argID++
 
-	queryBuilder.WriteString("ORDER BY created_at DESC")
+	// Add optional state and last change filters (keep in sync with CountByJob)
+	if req.State != nil {
+		queryBuilder.WriteString(fmt.Sprintf("AND state = $%d ", argID))
+		args = append(args, *req.State)
+		argID++
+	}
+	if req.UpdatedSince != nil {
+		queryBuilder.WriteString(fmt.Sprintf("AND updated_at > $%d", argID))
+		args = append(args, *req.UpdatedSince)
+		argID++
+		queryBuilder.WriteString(updatedSinceOrderClause)
+	} else {
+		queryBuilder.WriteString("ORDER BY created_at DESC")
+	}
 
 	// Add LIMIT and OFFSET
 	args = append(args, req.Limit)
@@ -204,12 +243,168 @@ func (r *JobApplicationRepo) ListByJob(ctx context.Context, req *dto.ListJobAppl
 	return applications, nil
 }
 
+// CountByContractor returns how many applications ListByContractor would return without pagination.
+func (r *JobApplicationRepo) CountByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) (int, error) {
+	query := `SELECT COUNT(*) FROM job_application WHERE contractor_id = $1`
+	args := []interface{}{req.ContractorID}
+	if req.State != nil {
+		args = append(args, *req.State)
+		query += fmt.Sprintf(" AND state = $%d", len(args))
+	}
+	if req.UpdatedSince != nil {
+		args = append(args, *req.UpdatedSince)
+		query += fmt.Sprintf(" AND updated_at > $%d", len(args))
+	}
+
+	var total int
+	err := r.db.QueryRow(ctx, query, args...).Scan(&total)
+	if err != nil {
+		log.Printf("Error counting job applications by contractor ID %s: %v\n", req.ContractorID, err)
+		return 0, fmt.Errorf("failed to count job applications by contractor: %w", err)
+	}
+	return total, nil
+}
+
+// CountByJob returns how many applications ListByJob would return without pagination.
+func (r *JobApplicationRepo) CountByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) (int, error) {
+	query := `SELECT COUNT(*) FROM job_application WHERE job_id = $1`
+	args := []interface{}{req.JobID}
+	if req.State != nil {
+		args = append(args, *req.State)
+		query += fmt.Sprintf(" AND state = $%d", len(args))
+	}
+	if req.UpdatedSince != nil {
+		args = append(args, *req.UpdatedSince)
+		query += fmt.Sprintf(" AND updated_at > $%d", len(args))
+	}
+
+	var total int
+	err := r.db.QueryRow(ctx, query, args...).Scan(&total)
+	if err != nil {
+		log.Printf("Error counting job applications by job ID %s: %v\n", req.JobID, err)
+		return 0, fmt.Errorf("failed to count job applications by job: %w", err)
+	}
+	return total, nil
+}
+
+// appliedJobsConditions builds the WHERE conditions shared by ListAppliedJobs and CountAppliedJobs.
+// Deleted jobs are left out, as they are from every other listing.
+func appliedJobsConditions(req *dto.ListAppliedJobsRequest) (string, []interface{}) {
+	conditions := "ja.contractor_id = $1 AND j.deleted_at IS NULL"
+	args := []interface{}{req.ContractorID}
+	if req.State != nil {
+		args = append(args, *req.State)
+		conditions += fmt.Sprintf(" AND ja.state = $%d", len(args))
+	}
+	return conditions, args
+}
+
+// ListAppliedJobs retrieves the jobs a contractor applied to together with their application, whatever
+// its outcome, most recent application first.
+func (r *JobApplicationRepo) ListAppliedJobs(ctx context.Context, req *dto.ListAppliedJobsRequest) ([]models.AppliedJob, error) {
+	conditions, args := appliedJobsConditions(req)
+	args = append(args, req.Limit, req.Offset)
+	query := fmt.Sprintf(`
+		SELECT j.id, j.rate, j.currency, j.duration, j.contractor_id, j.employer_id, j.state, j.invoice_interval, j.description, j.application_deadline, j.auto_invoice, j.auto_invoice_cadence, j.next_auto_invoice_at, j.version, j.created_by, j.updated_by, j.created_at, j.updated_at, j.deleted_at,
+			ja.id, ja.contractor_id, ja.job_id, ja.state, ja.cover_message, ja.employer_note, ja.proposed_rate, ja.created_at, ja.updated_at
+		FROM job_application ja
+		JOIN jobs j ON j.id = ja.job_id
+		WHERE %s
+		ORDER BY ja.created_at DESC, ja.id
+		LIMIT $%d OFFSET $%d
+	`, conditions, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying applied jobs for contractor %s: %v\n", req.ContractorID, err)
+		return nil, fmt.Errorf("failed to list applied jobs: %w", err)
+	}
+	defer rows.Close()
+
+	appliedJobs := []models.AppliedJob{}
+	for rows.Next() {
+		var applied models.AppliedJob
+		job, app := &applied.Job, &applied.Application
+		if err := rows.Scan(
+			&job.ID, &job.Rate, &job.Currency, &job.Duration, &job.ContractorID, &job.EmployerID, &job.State, &job.InvoiceInterval, &job.Description,
+			&job.ApplicationDeadline, &job.AutoInvoice, &job.AutoInvoiceCadence, &job.NextAutoInvoiceAt, &job.Version,
+			&job.CreatedBy, &job.UpdatedBy, &job.CreatedAt, &job.UpdatedAt, &job.DeletedAt,
+			&app.ID, &app.ContractorID, &app.JobID, &app.State, &app.CoverMessage, &app.EmployerNote, &app.ProposedRate, &app.CreatedAt, &app.UpdatedAt,
+		); err != nil {
+			log.Printf("Error scanning applied job for contractor %s: %v\n", req.ContractorID, err)
+			return nil, fmt.Errorf("failed to scan applied job: %w", err)
+		}
+		appliedJobs = append(appliedJobs, applied)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating applied jobs for contractor %s: %v\n", req.ContractorID, err)
+		return nil, fmt.Errorf("failed to iterate applied jobs: %w", err)
+	}
+	return appliedJobs, nil
+}
+
+// CountAppliedJobs returns how many rows ListAppliedJobs would return without pagination.
+func (r *JobApplicationRepo) CountAppliedJobs(ctx context.Context, req *dto.ListAppliedJobsRequest) (int, error) {
+	conditions, args := appliedJobsConditions(req)
+	query := `SELECT COUNT(*) FROM job_application ja JOIN jobs j ON j.id = ja.job_id WHERE ` + conditions
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		log.Printf("Error counting applied jobs for contractor %s: %v\n", req.ContractorID, err)
+		return 0, fmt.Errorf("failed to count applied jobs: %w", err)
+	}
+	return total, nil
+}
+
+// StreamByJob iterates over the applications for a job joined with the applicant's name, only those in
+// req.State if it is set, calling fn once per row so callers can write results out without buffering the whole set.
+func (r *JobApplicationRepo) StreamByJob(ctx context.Context, req *dto.ExportJobApplicationsCSVRequest, fn func(row *models.JobApplicationExportRow) error) error {
+	query := `
+		SELECT u.name, ja.created_at, ja.state, ja.cover_message, ja.employer_note
+		FROM job_application ja
+		JOIN users u ON u.id = ja.contractor_id
+		WHERE ja.job_id = $1 AND ($2::job_application_state IS NULL OR ja.state = $2)
+		ORDER BY ja.created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, req.JobID, req.State)
+	if err != nil {
+		log.Printf("Error querying job applications for export of job %s: %v\n", req.JobID, err)
+		return fmt.Errorf("failed to query job applications for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.JobApplicationExportRow
+		if err := rows.Scan(
+			&row.ContractorName,
+			&row.AppliedAt,
+			&row.State,
+			&row.CoverMessage,
+			&row.EmployerNote,
+		); err != nil {
+			log.Printf("Error scanning job application export row for job %s: %v\n", req.JobID, err)
+			return fmt.Errorf("failed to scan job application export row: %w", err)
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating job application export rows for job %s: %v\n", req.JobID, err)
+		return fmt.Errorf("failed to iterate job application export rows: %w", err)
+	}
+	return nil
+}
+
 func (r *JobApplicationRepo) UpdateState(ctx context.Context, req *dto.UpdateJobApplicationStateRequest) (*models.JobApplication, error) {
 	query := `
 		UPDATE job_application
 		SET state = $2, updated_at = NOW()
 		WHERE id = $1
-		RETURNING id, contractor_id, job_id, state, created_at, updated_at
+		RETURNING id, contractor_id, job_id, state, cover_message, employer_note, proposed_rate, created_at, updated_at
 	`
 	row := r.db.QueryRow(ctx, query, req.ID, req.State)
 
@@ -219,6 +414,9 @@ func (r *JobApplicationRepo) UpdateState(ctx context.Context, req *dto.UpdateJob
 		&updatedApp.ContractorID,
 		&updatedApp.JobID,
 		&updatedApp.State,
+		&updatedApp.CoverMessage,
+		&updatedApp.EmployerNote,
+		&updatedApp.ProposedRate,
 		&updatedApp.CreatedAt,
 		&updatedApp.UpdatedAt,
 	)
@@ -235,17 +433,17 @@ func (r *JobApplicationRepo) UpdateState(ctx context.Context, req *dto.UpdateJob
 	return &updatedApp, nil
 }
 
-// UpdateStateByJobID updates the state of all applications for a specific job.
+// UpdateStateByJobID updates the state of all pending (Waiting or Invited) applications for a specific job.
 // Useful for rejecting other applications when one is accepted.
 func (r *JobApplicationRepo) UpdateStateByJobID(ctx context.Context, jobID uuid.UUID, newState models.JobApplicationState, excludeApplicationID *uuid.UUID) error {
 	query := `
 		UPDATE job_application
 		SET state = $1, updated_at = NOW()
-		WHERE job_id = $2 AND state = $3`
-	args := []interface{}{newState, jobID, models.JobApplicationWaiting} // Only update 'Waiting' applications
+		WHERE job_id = $2 AND state IN ($3, $4)`
+	args := []interface{}{newState, jobID, models.JobApplicationWaiting, models.JobApplicationInvited} // Only update pending applications
 
 	if excludeApplicationID != nil {
-		query += " AND id != $4"
+		query += " AND id != $5"
 		args = append(args, *excludeApplicationID)
 	}
 
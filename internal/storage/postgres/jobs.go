@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"strings" // For building SQL queries
+	"time"
 
 	"go-api-template/internal/models"
 	"go-api-template/internal/storage"
@@ -25,11 +26,11 @@ type JobRepo struct {
 
 // NewJobRepo creates a new JobRepo.
 func NewJobRepo(db *pgxpool.Pool) *JobRepo {
-	return &JobRepo{db: db}
+	return &JobRepo{db: cancelAware(db)}
 }
 // WithTx creates a new JobRepo with the transaction.
 func (r *JobRepo) WithTx(tx pgx.Tx) storage.JobRepository {
-	return &JobRepo{db: tx}
+	return &JobRepo{db: cancelAware(tx)}
 }
 
 // Compile-time check to ensure JobRepo implements JobRepository
@@ -40,39 +41,58 @@ func (r *JobRepo) Create(ctx context.Context, req *dto.CreateJobRequest) (*model
 	job := &models.Job{
 		ID:              uuid.New(), // Generate ID server-side
 		Rate:            req.Rate,
+		Currency:        req.Currency,
 		Duration:        req.Duration,
 		EmployerID:      req.EmployerID, // Assumes EmployerID is set in the DTO by the handler
 		State:           models.JobStateWaiting, // Default state
 		InvoiceInterval: req.InvoiceInterval,
+		Description:     req.Description,
+		ApplicationDeadline: req.ApplicationDeadline,
 		// ContractorID is initially NULL
 	}
+	if job.Currency == "" {
+		job.Currency = models.DefaultCurrency // The service normally resolves it from config
+	}
 
 	query := `
-		INSERT INTO jobs (id, rate, duration, employer_id, state, invoice_interval, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-		RETURNING id, rate, duration, contractor_id, employer_id, state, invoice_interval, created_at, updated_at
+		INSERT INTO jobs (id, rate, currency, duration, employer_id, state, invoice_interval, description, application_deadline, created_by, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $5, $5, NOW(), NOW())
+		RETURNING id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
 	`
 
 	row := r.db.QueryRow(ctx, query,
 		job.ID,
 		job.Rate,
+		job.Currency,
 		job.Duration,
 		job.EmployerID,
 		job.State,
 		job.InvoiceInterval,
+		job.Description,
+		job.ApplicationDeadline,
 	)
 
 	var createdJob models.Job
 	err := row.Scan(
 		&createdJob.ID,
 		&createdJob.Rate,
+		&createdJob.Currency,
 		&createdJob.Duration,
 		&createdJob.ContractorID, // Will scan as NULL if not set
 		&createdJob.EmployerID,
 		&createdJob.State,
 		&createdJob.InvoiceInterval,
+		&createdJob.Description,
+		&createdJob.ApplicationDeadline,
+		&createdJob.AutoInvoice,
+		&createdJob.AutoInvoiceCadence,
+		&createdJob.NextAutoInvoiceAt,
+		&createdJob.Version,
+		&createdJob.CreatedBy,
+		&createdJob.UpdatedBy,
 		&createdJob.CreatedAt,
 		&createdJob.UpdatedAt,
+		&createdJob.DeletedAt,
 	)
 
 	if err != nil {
@@ -93,9 +113,9 @@ func (r *JobRepo) Create(ctx context.Context, req *dto.CreateJobRequest) (*model
 // GetByID retrieves a specific job by its ID.
 func (r *JobRepo) GetByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error) {
 	query := `
-		SELECT id, rate, duration, contractor_id, employer_id, state, invoice_interval, created_at, updated_at
+		SELECT id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
 		FROM jobs
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	row := r.db.QueryRow(ctx, query, req.ID)
 
@@ -103,13 +123,23 @@ func (r *JobRepo) GetByID(ctx context.Context, req *dto.GetJobByIDRequest) (*mod
 	err := row.Scan(
 		&job.ID,
 		&job.Rate,
+		&job.Currency,
 		&job.Duration,
 		&job.ContractorID,
 		&job.EmployerID,
 		&job.State,
 		&job.InvoiceInterval,
+		&job.Description,
+		&job.ApplicationDeadline,
+		&job.AutoInvoice,
+		&job.AutoInvoiceCadence,
+		&job.NextAutoInvoiceAt,
+		&job.Version,
+		&job.CreatedBy,
+		&job.UpdatedBy,
 		&job.CreatedAt,
 		&job.UpdatedAt,
+		&job.DeletedAt,
 	)
 
 	if err != nil {
@@ -124,26 +154,47 @@ func (r *JobRepo) GetByID(ctx context.Context, req *dto.GetJobByIDRequest) (*mod
 	return &job, nil
 }
 
+// Exists reports whether a job with the given ID exists and isn't soft-deleted, without loading the row.
+func (r *JobRepo) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM jobs WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking existence of job %s: %v\n", id, err)
+		return false, fmt.Errorf("failed to check job existence: %w", err)
+	}
+	return exists, nil
+}
+
+// SharesOngoingJob reports whether one user is the employer and the other the contractor of an Ongoing,
+// undeleted job, whichever way round.
+func (r *JobRepo) SharesOngoingJob(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM jobs
+			WHERE state = 'Ongoing' AND deleted_at IS NULL
+			AND ((employer_id = $1 AND contractor_id = $2) OR (employer_id = $2 AND contractor_id = $1))
+		)`
+	var shares bool
+	if err := r.db.QueryRow(ctx, query, userID, otherID).Scan(&shares); err != nil {
+		log.Printf("Error checking ongoing jobs between users %s and %s: %v\n", userID, otherID, err)
+		return false, fmt.Errorf("failed to check ongoing jobs between users: %w", err)
+	}
+	return shares, nil
+}
+
 // ListAvailable retrieves jobs that have no contractor assigned yet.
 func (r *JobRepo) ListAvailable(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, error) {
 	baseQuery := `
-		SELECT id, rate, duration, contractor_id, employer_id, state, invoice_interval, created_at, updated_at
+		SELECT id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
 		FROM jobs
 	`
-	conditions := []string{"contractor_id IS NULL", "state = $1"} // Base conditions for available jobs
-	args := []interface{}{models.JobStateWaiting} // Start args with state
+	conditions, args := availableJobConditions(req)
 
-	// Add optional filters
-	if req.MinRate != nil {
-		args = append(args, *req.MinRate)
-		conditions = append(conditions, fmt.Sprintf("rate >= $%d", len(args)))
-	}
-	if req.MaxRate != nil {
-		args = append(args, *req.MaxRate)
-		conditions = append(conditions, fmt.Sprintf("rate <= $%d", len(args)))
+	orderClause, err := buildJobListOrderClause(req.SortBy, req.SortOrder, req.UpdatedSince)
+	if err != nil {
+		return nil, err
 	}
-
-	query := r.buildJobListQuery(baseQuery, conditions, &args, req.Offset, req.Limit)
+	query := r.buildJobListQuery(baseQuery, conditions, &args, orderClause, req.Offset, req.Limit)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -168,27 +219,16 @@ func (r *JobRepo) ListAvailable(ctx context.Context, req *dto.ListAvailableJobsR
 // ListByEmployer retrieves jobs posted by a specific employer.
 func (r *JobRepo) ListByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) ([]models.Job, error) {
 	baseQuery := `
-		SELECT id, rate, duration, contractor_id, employer_id, state, invoice_interval, created_at, updated_at
+		SELECT id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
 		FROM jobs
 	`
-	conditions := []string{"employer_id = $1"}
-	args := []interface{}{req.EmployerID}
+	conditions, args := employerJobConditions(req)
 
-	// Add optional filters
-	if req.State != nil {
-		args = append(args, *req.State)
-		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
-	}
-	if req.MinRate != nil {
-		args = append(args, *req.MinRate)
-		conditions = append(conditions, fmt.Sprintf("rate >= $%d", len(args)))
-	}
-	if req.MaxRate != nil {
-		args = append(args, *req.MaxRate)
-		conditions = append(conditions, fmt.Sprintf("rate <= $%d", len(args)))
+	orderClause, err := buildJobListOrderClause(req.SortBy, req.SortOrder, req.UpdatedSince)
+	if err != nil {
+		return nil, err
 	}
-
-	query := r.buildJobListQuery(baseQuery, conditions, &args, req.Offset, req.Limit)
+	query := r.buildJobListQuery(baseQuery, conditions, &args, orderClause, req.Offset, req.Limit)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -213,27 +253,16 @@ func (r *JobRepo) ListByEmployer(ctx context.Context, req *dto.ListJobsByEmploye
 // ListByContractor retrieves jobs taken by a specific contractor.
 func (r *JobRepo) ListByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, error) {
 	baseQuery := `
-		SELECT id, rate, duration, contractor_id, employer_id, state, invoice_interval, created_at, updated_at
+		SELECT id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
 		FROM jobs
 	`
-	conditions := []string{"contractor_id = $1"}
-	args := []interface{}{req.ContractorID}
+	conditions, args := contractorJobConditions(req)
 
-	// Add optional filters
-	if req.State != nil {
-		args = append(args, *req.State)
-		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
-	}
-	if req.MinRate != nil {
-		args = append(args, *req.MinRate)
-		conditions = append(conditions, fmt.Sprintf("rate >= $%d", len(args)))
-	}
-	if req.MaxRate != nil {
-		args = append(args, *req.MaxRate)
-		conditions = append(conditions, fmt.Sprintf("rate <= $%d", len(args)))
+	orderClause, err := buildJobListOrderClause(req.SortBy, req.SortOrder, req.UpdatedSince)
+	if err != nil {
+		return nil, err
 	}
-
-	query := r.buildJobListQuery(baseQuery, conditions, &args, req.Offset, req.Limit)
+	query := r.buildJobListQuery(baseQuery, conditions, &args, orderClause, req.Offset, req.Limit)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -255,7 +284,145 @@ func (r *JobRepo) ListByContractor(ctx context.Context, req *dto.ListJobsByContr
 	return jobs, nil
 }
 
-// Update modifies an existing job based on non-nil fields in the request DTO.
+// availableJobConditions builds the WHERE conditions shared by ListAvailable and CountAvailable.
+// SavedJobRepo applies the same base conditions in savedAvailableJobsFrom.
+func availableJobConditions(req *dto.ListAvailableJobsRequest) ([]string, []interface{}) {
+	// Base conditions for available jobs; jobs whose application deadline has passed are closed
+	conditions := []string{"contractor_id IS NULL", "state = $1", "deleted_at IS NULL", "(application_deadline IS NULL OR application_deadline > NOW())"}
+	args := []interface{}{models.JobStateWaiting} // Start args with state
+
+	// Add optional filters
+	if req.MinRate != nil {
+		args = append(args, *req.MinRate)
+		conditions = append(conditions, fmt.Sprintf("rate >= $%d", len(args)))
+	}
+	if req.MaxRate != nil {
+		args = append(args, *req.MaxRate)
+		conditions = append(conditions, fmt.Sprintf("rate <= $%d", len(args)))
+	}
+	if req.MinDuration != nil {
+		args = append(args, *req.MinDuration)
+		conditions = append(conditions, fmt.Sprintf("duration >= $%d", len(args)))
+	}
+	if req.MaxDuration != nil {
+		args = append(args, *req.MaxDuration)
+		conditions = append(conditions, fmt.Sprintf("duration <= $%d", len(args)))
+	}
+	if req.Search != nil && strings.TrimSpace(*req.Search) != "" {
+		// Must match the idx_jobs_description_fts expression for the index to be used
+		args = append(args, strings.TrimSpace(*req.Search))
+		conditions = append(conditions, fmt.Sprintf("to_tsvector('english', description) @@ plainto_tsquery('english', $%d)", len(args)))
+	}
+	if len(req.Skills) > 0 {
+		// Skills are normalized and deduplicated by the service, so counting matches works for "all"
+		args = append(args, req.Skills)
+		matching := fmt.Sprintf("SELECT 1 FROM job_skills js JOIN skills sk ON sk.id = js.skill_id WHERE js.job_id = jobs.id AND sk.name = ANY($%d::text[])", len(args))
+		if req.SkillsMatch == models.SkillsMatchAny {
+			conditions = append(conditions, "EXISTS ("+matching+")")
+		} else {
+			args = append(args, len(req.Skills))
+			conditions = append(conditions, fmt.Sprintf("(SELECT COUNT(*) FROM (%s) m) = $%d", matching, len(args)))
+		}
+	}
+	if req.UpdatedSince != nil {
+		args = append(args, *req.UpdatedSince)
+		conditions = append(conditions, fmt.Sprintf("updated_at > $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// employerJobConditions builds the WHERE conditions shared by ListByEmployer and CountByEmployer.
+func employerJobConditions(req *dto.ListJobsByEmployerRequest) ([]string, []interface{}) {
+	conditions := []string{"employer_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{req.EmployerID}
+	return appendJobFilterConditions(conditions, args, req.State, req.States, req.MinRate, req.MaxRate, req.UpdatedSince)
+}
+
+// contractorJobConditions builds the WHERE conditions shared by ListByContractor and CountByContractor.
+func contractorJobConditions(req *dto.ListJobsByContractorRequest) ([]string, []interface{}) {
+	conditions := []string{"contractor_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{req.ContractorID}
+	return appendJobFilterConditions(conditions, args, req.State, req.States, req.MinRate, req.MaxRate, req.UpdatedSince)
+}
+
+// appendJobFilterConditions adds the optional state, rate and last change filters used by the per-user job listings.
+func appendJobFilterConditions(conditions []string, args []interface{}, state *models.JobState, states []models.JobState, minRate, maxRate *float64, updatedSince *time.Time) ([]string, []interface{}) {
+	if state != nil {
+		args = append(args, *state)
+		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
+	}
+	if len(states) > 0 {
+		names := make([]string, len(states))
+		for i, st := range states {
+			names[i] = string(st)
+		}
+		args = append(args, names)
+		conditions = append(conditions, fmt.Sprintf("state = ANY($%d::job_state[])", len(args)))
+	}
+	if minRate != nil {
+		args = append(args, *minRate)
+		conditions = append(conditions, fmt.Sprintf("rate >= $%d", len(args)))
+	}
+	if maxRate != nil {
+		args = append(args, *maxRate)
+		conditions = append(conditions, fmt.Sprintf("rate <= $%d", len(args)))
+	}
+	if updatedSince != nil {
+		args = append(args, *updatedSince)
+		conditions = append(conditions, fmt.Sprintf("updated_at > $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// countJobs returns the number of jobs matching conditions, ignoring pagination.
+func (r *JobRepo) countJobs(ctx context.Context, conditions []string, args []interface{}) (int, error) {
+	query := "SELECT COUNT(*) FROM jobs"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// CountAvailable returns how many jobs match the ListAvailable filters, ignoring pagination.
+func (r *JobRepo) CountAvailable(ctx context.Context, req *dto.ListAvailableJobsRequest) (int, error) {
+	conditions, args := availableJobConditions(req)
+	total, err := r.countJobs(ctx, conditions, args)
+	if err != nil {
+		log.Printf("Error counting available jobs: %v\n", err)
+		return 0, fmt.Errorf("failed to count available jobs: %w", err)
+	}
+	return total, nil
+}
+
+// CountByEmployer returns how many jobs match the ListByEmployer filters, ignoring pagination.
+func (r *JobRepo) CountByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) (int, error) {
+	conditions, args := employerJobConditions(req)
+	total, err := r.countJobs(ctx, conditions, args)
+	if err != nil {
+		log.Printf("Error counting jobs by employer %s: %v\n", req.EmployerID, err)
+		return 0, fmt.Errorf("failed to count jobs by employer: %w", err)
+	}
+	return total, nil
+}
+
+// CountByContractor returns how many jobs match the ListByContractor filters, ignoring pagination.
+func (r *JobRepo) CountByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) (int, error) {
+	conditions, args := contractorJobConditions(req)
+	total, err := r.countJobs(ctx, conditions, args)
+	if err != nil {
+		log.Printf("Error counting jobs by contractor %s: %v\n", req.ContractorID, err)
+		return 0, fmt.Errorf("failed to count jobs by contractor: %w", err)
+	}
+	return total, nil
+}
+
+// Update modifies an existing job based on non-nil fields in the request DTO. The update only applies
+// if the job is still at req.Version, and bumps the version; a stale version gets storage.ErrConflict.
 func (r *JobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*models.Job, error) {
 	var setClauses []string
 	args := []interface{}{}
@@ -272,7 +439,9 @@ func (r *JobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*model
 		setClauses = append(setClauses, fmt.Sprintf("duration = $%d", argID))
 		argID++
 	}
-	if req.ContractorID != nil {
+	if req.UnassignContractor {
+		setClauses = append(setClauses, "contractor_id = NULL")
+	} else if req.ContractorID != nil {
 		args = append(args, *req.ContractorID)
 		setClauses = append(setClauses, fmt.Sprintf("contractor_id = $%d", argID))
 		argID++
@@ -282,22 +451,34 @@ func (r *JobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*model
 		setClauses = append(setClauses, fmt.Sprintf("state = $%d", argID))
 		argID++
 	}
+	if req.Description != nil {
+		args = append(args, *req.Description)
+		setClauses = append(setClauses, fmt.Sprintf("description = $%d", argID))
+		argID++
+	}
+	if req.ApplicationDeadline != nil {
+		args = append(args, *req.ApplicationDeadline)
+		setClauses = append(setClauses, fmt.Sprintf("application_deadline = $%d", argID))
+		argID++
+	}
 
-	if len(setClauses) == 0 {
+	if len(setClauses) == 0 && !req.Touch {
 		log.Printf("Update called for job %s with no fields to change.", req.ID)
 		return nil, fmt.Errorf("no fields provided for update on job %s", req.ID)
 	}
 
-	// Add updated_at and WHERE clause
-	setClauses = append(setClauses, "updated_at = NOW()")
-	args = append(args, req.ID) 
+	// Add updated_by, updated_at and WHERE clause
+	args = append(args, req.UpdatedBy)
+	setClauses = append(setClauses, fmt.Sprintf("updated_by = $%d", argID), "updated_at = NOW()", "version = version + 1")
+	argID++
+	args = append(args, req.ID, req.Version)
 
 	query := fmt.Sprintf(`
 		UPDATE jobs
 		SET %s
-		WHERE id = $%d
-		RETURNING id, rate, duration, contractor_id, employer_id, state, invoice_interval, created_at, updated_at
-	`, strings.Join(setClauses, ", "), argID)
+		WHERE id = $%d AND version = $%d AND deleted_at IS NULL
+		RETURNING id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
+	`, strings.Join(setClauses, ", "), argID, argID+1)
 
 	row := r.db.QueryRow(ctx, query, args...)
 
@@ -305,17 +486,37 @@ func (r *JobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*model
 	err := row.Scan(
 		&updatedJob.ID,
 		&updatedJob.Rate,
+		&updatedJob.Currency,
 		&updatedJob.Duration,
 		&updatedJob.ContractorID,
 		&updatedJob.EmployerID,
 		&updatedJob.State,
 		&updatedJob.InvoiceInterval,
+		&updatedJob.Description,
+		&updatedJob.ApplicationDeadline,
+		&updatedJob.AutoInvoice,
+		&updatedJob.AutoInvoiceCadence,
+		&updatedJob.NextAutoInvoiceAt,
+		&updatedJob.Version,
+		&updatedJob.CreatedBy,
+		&updatedJob.UpdatedBy,
 		&updatedJob.CreatedAt,
 		&updatedJob.UpdatedAt,
+		&updatedJob.DeletedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			// Either the job is gone or someone else updated it since the caller read it
+			var exists bool
+			if existsErr := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM jobs WHERE id = $1 AND deleted_at IS NULL)`, req.ID).Scan(&exists); existsErr != nil {
+				log.Printf("Error checking job %s after failed update: %v\n", req.ID, existsErr)
+				return nil, fmt.Errorf("failed to update job %s: %w", req.ID, existsErr)
+			}
+			if exists {
+				log.Printf("Stale version %d for update of job %s\n", req.Version, req.ID)
+				return nil, fmt.Errorf("job %s was modified since version %d: %w", req.ID, req.Version, storage.ErrConflict)
+			}
 			log.Printf("Job not found for update with ID: %s\n", req.ID)
 			return nil, storage.ErrNotFound
 		}
@@ -333,11 +534,120 @@ func (r *JobRepo) Update(ctx context.Context, req *dto.UpdateJobRequest) (*model
 	return &updatedJob, nil
 }
 
-// Delete removes a job by its ID.
+// AssignContractor sets the contractor and moves the job to Ongoing, but only if the job is
+// still Waiting without a contractor. A non-nil req.Rate replaces the job's rate in the same UPDATE. The condition is re-checked in the UPDATE itself, so of two
+// concurrent assignments only one can match; the other gets storage.ErrConflict. Serialization failures
+// and deadlocks come back as a retryable *storage.ConflictError.
+func (r *JobRepo) AssignContractor(ctx context.Context, req *dto.AssignContractorRequest) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET contractor_id = $2, state = $3, rate = COALESCE($5, rate), updated_by = $6, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND contractor_id IS NULL AND state = $4 AND deleted_at IS NULL
+		RETURNING id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
+	`
+
+	var job models.Job
+	err := r.db.QueryRow(ctx, query, req.JobID, req.ContractorID, models.JobStateOngoing, models.JobStateWaiting, req.Rate, req.UpdatedBy).Scan(
+		&job.ID,
+		&job.Rate,
+		&job.Currency,
+		&job.Duration,
+		&job.ContractorID,
+		&job.EmployerID,
+		&job.State,
+		&job.InvoiceInterval,
+		&job.Description,
+		&job.ApplicationDeadline,
+		&job.AutoInvoice,
+		&job.AutoInvoiceCadence,
+		&job.NextAutoInvoiceAt,
+		&job.Version,
+		&job.CreatedBy,
+		&job.UpdatedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Job %s was not available for assignment (already assigned or not waiting)\n", req.JobID)
+			return nil, fmt.Errorf("job %s is no longer available: %w", req.JobID, storage.ErrConflict)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				log.Printf("Error assigning contractor to job %s: Foreign key violation: %v\n", req.JobID, err)
+				return nil, fmt.Errorf("failed to assign contractor: invalid reference: %w", &storage.ConflictError{Code: pgErr.Code, Err: err})
+			case "40001", "40P01": // serialization_failure, deadlock_detected: lost to a concurrent transaction
+				log.Printf("Error assigning contractor to job %s: Concurrent transaction conflict: %v\n", req.JobID, err)
+				return nil, fmt.Errorf("failed to assign contractor: %w", &storage.ConflictError{Code: pgErr.Code, Err: err})
+			}
+		}
+		log.Printf("Error assigning contractor to job %s: %v\n", req.JobID, err)
+		return nil, fmt.Errorf("failed to assign contractor to job %s: %w", req.JobID, err)
+	}
+
+	log.Printf("Contractor %s assigned to job %s", req.ContractorID, job.ID)
+	return &job, nil
+}
+
+// TransferOwnership moves the job to req.NewEmployerID, but only while no contractor is assigned; the
+// condition is re-checked in the UPDATE so a concurrent assignment can't slip in between. A job that
+// no longer matches gets storage.ErrConflict.
+func (r *JobRepo) TransferOwnership(ctx context.Context, req *dto.TransferJobRequest) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET employer_id = $2, updated_by = $3, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND contractor_id IS NULL AND deleted_at IS NULL
+		RETURNING id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
+	`
+
+	var job models.Job
+	err := r.db.QueryRow(ctx, query, req.JobID, req.NewEmployerID, req.UserID).Scan(
+		&job.ID,
+		&job.Rate,
+		&job.Currency,
+		&job.Duration,
+		&job.ContractorID,
+		&job.EmployerID,
+		&job.State,
+		&job.InvoiceInterval,
+		&job.Description,
+		&job.ApplicationDeadline,
+		&job.AutoInvoice,
+		&job.AutoInvoiceCadence,
+		&job.NextAutoInvoiceAt,
+		&job.Version,
+		&job.CreatedBy,
+		&job.UpdatedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Job %s could not be transferred (contractor assigned or deleted)\n", req.JobID)
+			return nil, fmt.Errorf("job %s can no longer be transferred: %w", req.JobID, storage.ErrConflict)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+			log.Printf("Error transferring job %s: Foreign key violation: %v\n", req.JobID, err)
+			return nil, fmt.Errorf("failed to transfer job: invalid employer ID: %w", &storage.ConflictError{Code: pgErr.Code, Err: err})
+		}
+		log.Printf("Error transferring job %s: %v\n", req.JobID, err)
+		return nil, fmt.Errorf("failed to transfer job %s: %w", req.JobID, err)
+	}
+
+	log.Printf("Job %s transferred to employer %s", job.ID, req.NewEmployerID)
+	return &job, nil
+}
+
+// Delete soft-deletes a job by setting deleted_at, keeping the row (and anything referencing it) for auditing.
 func (r *JobRepo) Delete(ctx context.Context, req *dto.DeleteJobRequest) error {
-	query := `DELETE FROM jobs WHERE id = $1`
+	query := `UPDATE jobs SET deleted_at = NOW(), updated_by = $2, updated_at = NOW(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL`
 
-	cmdTag, err := r.db.Exec(ctx, query, req.ID)
+	cmdTag, err := r.db.Exec(ctx, query, req.ID, req.UserID)
 	if err != nil {
 		log.Printf("Error deleting job %s: %v\n", req.ID, err)
 		return fmt.Errorf("failed to delete job %s: %w", req.ID, err)
@@ -352,3 +662,250 @@ func (r *JobRepo) Delete(ctx context.Context, req *dto.DeleteJobRequest) error {
 	return nil
 }
 
+// Restore clears deleted_at on a soft-deleted job. Returns storage.ErrNotFound if no deleted job has that ID.
+func (r *JobRepo) Restore(ctx context.Context, req *dto.RestoreJobRequest) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET deleted_at = NULL, updated_by = $2, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
+	`
+
+	var job models.Job
+	err := r.db.QueryRow(ctx, query, req.ID, req.UserID).Scan(
+		&job.ID,
+		&job.Rate,
+		&job.Currency,
+		&job.Duration,
+		&job.ContractorID,
+		&job.EmployerID,
+		&job.State,
+		&job.InvoiceInterval,
+		&job.Description,
+		&job.ApplicationDeadline,
+		&job.AutoInvoice,
+		&job.AutoInvoiceCadence,
+		&job.NextAutoInvoiceAt,
+		&job.Version,
+		&job.CreatedBy,
+		&job.UpdatedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Deleted job not found for restore with ID: %s\n", req.ID)
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error restoring job %s: %v\n", req.ID, err)
+		return nil, fmt.Errorf("failed to restore job %s: %w", req.ID, err)
+	}
+
+	log.Printf("Job restored successfully: %s", job.ID)
+	return &job, nil
+}
+
+// ListStaleCompletedIDs returns the IDs of Complete jobs last updated before req.CompletedBefore, oldest
+// first and at most req.Limit of them. Soft-deleted jobs are skipped.
+func (r *JobRepo) ListStaleCompletedIDs(ctx context.Context, req *dto.ArchiveStaleJobsRequest) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM jobs
+		WHERE state = $1 AND updated_at < $2 AND deleted_at IS NULL
+		ORDER BY updated_at ASC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, query, models.JobStateComplete, req.CompletedBefore, req.Limit)
+	if err != nil {
+		log.Printf("Error querying stale completed jobs: %v\n", err)
+		return nil, fmt.Errorf("failed to query stale completed jobs: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		log.Printf("Error scanning stale completed jobs: %v\n", err)
+		return nil, fmt.Errorf("failed to scan stale completed jobs: %w", err)
+	}
+	return ids, nil
+}
+
+// ListPurgeableArchivedIDs returns the IDs of Archived jobs last updated before req.ArchivedBefore whose
+// invoices are all Paid, oldest first and at most req.Limit of them, and locks them until the transaction
+// ends. Soft-deleted jobs are included, and rows another transaction holds are skipped.
+func (r *JobRepo) ListPurgeableArchivedIDs(ctx context.Context, req *dto.PurgeArchivedJobsRequest) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM jobs j
+		WHERE state = $1 AND updated_at < $2
+		  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.job_id = j.id AND i.state <> $3)
+		ORDER BY updated_at ASC
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := r.db.Query(ctx, query, models.JobStateArchived, req.ArchivedBefore, models.InvoiceStatePaid, req.Limit)
+	if err != nil {
+		log.Printf("Error querying purgeable archived jobs: %v\n", err)
+		return nil, fmt.Errorf("failed to query purgeable archived jobs: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		log.Printf("Error scanning purgeable archived jobs: %v\n", err)
+		return nil, fmt.Errorf("failed to scan purgeable archived jobs: %w", err)
+	}
+	return ids, nil
+}
+
+// Purge permanently deletes the jobs with the given IDs and returns how many were deleted. Their
+// applications, saves and skill links go with them through ON DELETE CASCADE; their ratings are kept,
+// with job_id cleared.
+func (r *JobRepo) Purge(ctx context.Context, ids []uuid.UUID) (int, error) {
+	query := `DELETE FROM jobs WHERE id = ANY($1)`
+
+	cmdTag, err := r.db.Exec(ctx, query, ids)
+	if err != nil {
+		log.Printf("Error purging %d jobs: %v\n", len(ids), err)
+		return 0, fmt.Errorf("failed to purge jobs: %w", err)
+	}
+	return int(cmdTag.RowsAffected()), nil
+}
+
+// SetAutoInvoice stores the job's automatic invoicing settings, with req.NextAutoInvoiceAt as the time the
+// next invoice is due.
+func (r *JobRepo) SetAutoInvoice(ctx context.Context, req *dto.SetJobAutoInvoiceRequest) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET auto_invoice = $2, auto_invoice_cadence = $3, next_auto_invoice_at = $4, updated_by = $5, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, rate, currency, duration, contractor_id, employer_id, state, invoice_interval, description, application_deadline, auto_invoice, auto_invoice_cadence, next_auto_invoice_at, version, created_by, updated_by, created_at, updated_at, deleted_at
+	`
+
+	var job models.Job
+	err := r.db.QueryRow(ctx, query, req.JobID, *req.Enabled, req.Cadence, req.NextAutoInvoiceAt, req.UserID).Scan(
+		&job.ID,
+		&job.Rate,
+		&job.Currency,
+		&job.Duration,
+		&job.ContractorID,
+		&job.EmployerID,
+		&job.State,
+		&job.InvoiceInterval,
+		&job.Description,
+		&job.ApplicationDeadline,
+		&job.AutoInvoice,
+		&job.AutoInvoiceCadence,
+		&job.NextAutoInvoiceAt,
+		&job.Version,
+		&job.CreatedBy,
+		&job.UpdatedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Job not found for auto-invoice update: %s\n", req.JobID)
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error setting auto-invoice for job %s: %v\n", req.JobID, err)
+		return nil, fmt.Errorf("failed to set auto-invoice for job %s: %w", req.JobID, err)
+	}
+	return &job, nil
+}
+
+// ListDueAutoInvoiceIDs returns the IDs of Ongoing jobs with automatic invoicing on whose next invoice is
+// due at or before req.DueBefore, most overdue first and at most req.Limit of them.
+func (r *JobRepo) ListDueAutoInvoiceIDs(ctx context.Context, req *dto.GenerateDueAutoInvoicesRequest) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM jobs
+		WHERE auto_invoice AND state = $1 AND next_auto_invoice_at <= $2 AND deleted_at IS NULL
+		ORDER BY next_auto_invoice_at ASC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, query, models.JobStateOngoing, req.DueBefore, req.Limit)
+	if err != nil {
+		log.Printf("Error querying jobs due for auto-invoicing: %v\n", err)
+		return nil, fmt.Errorf("failed to query jobs due for auto-invoicing: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		log.Printf("Error scanning jobs due for auto-invoicing: %v\n", err)
+		return nil, fmt.Errorf("failed to scan jobs due for auto-invoicing: %w", err)
+	}
+	return ids, nil
+}
+
+// AdvanceAutoInvoice moves the job's next automatic invoice time from req.From to req.To. The update only
+// applies while the time is still req.From, so when several instances pick the same job only one wins.
+func (r *JobRepo) AdvanceAutoInvoice(ctx context.Context, req *dto.AdvanceAutoInvoiceRequest) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET next_auto_invoice_at = $3, updated_by = $4, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND next_auto_invoice_at = $2 AND deleted_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, query, req.JobID, req.From, req.To, req.UpdatedBy)
+	if err != nil {
+		log.Printf("Error advancing auto-invoice for job %s: %v\n", req.JobID, err)
+		return false, fmt.Errorf("failed to advance auto-invoice for job %s: %w", req.JobID, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetEmployerStats counts the employer's jobs per state and averages their rate per currency in the
+// database. Each grouping set leaves the other column NULL: (state) rows hold the per-state counts,
+// (currency) rows the average rate in that currency, and the () row the total count.
+func (r *JobRepo) GetEmployerStats(ctx context.Context, req *dto.EmployerStatsRequest) (*models.EmployerStats, error) {
+	query := `
+		SELECT state, currency, COUNT(*), AVG(rate)
+		FROM jobs
+		WHERE employer_id = $1 AND deleted_at IS NULL
+		GROUP BY GROUPING SETS ((state), (currency), ())
+	`
+	rows, err := r.db.Query(ctx, query, req.EmployerID)
+	if err != nil {
+		log.Printf("Error querying job stats for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to query employer job stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.EmployerStats{
+		JobsByState: map[models.JobState]int{
+			models.JobStateWaiting:   0,
+			models.JobStateOngoing:   0,
+			models.JobStateComplete:  0,
+			models.JobStateArchived:  0,
+			models.JobStateCancelled: 0,
+		},
+		AverageRate: map[string]float64{},
+	}
+	for rows.Next() {
+		var state *models.JobState
+		var currency *string
+		var count int
+		var avgRate *float64 // NULL only in the totals row of an employer without jobs
+		if err := rows.Scan(&state, &currency, &count, &avgRate); err != nil {
+			log.Printf("Error scanning job stats for employer %s: %v\n", req.EmployerID, err)
+			return nil, fmt.Errorf("failed to scan employer job stats: %w", err)
+		}
+		switch {
+		case state != nil:
+			stats.JobsByState[*state] = count
+		case currency != nil:
+			stats.AverageRate[*currency] = *avgRate
+		default: // The totals row
+			stats.TotalJobs = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating job stats for employer %s: %v\n", req.EmployerID, err)
+		return nil, fmt.Errorf("failed to read employer job stats: %w", err)
+	}
+
+	return stats, nil
+}
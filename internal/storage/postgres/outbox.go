@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OutboxRepo implements the storage.OutboxRepository interface using PostgreSQL.
+type OutboxRepo struct {
+	db Querier
+}
+
+// NewOutboxRepo creates a new OutboxRepo.
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new OutboxRepo with the transaction.
+func (r *OutboxRepo) WithTx(tx pgx.Tx) storage.OutboxRepository {
+	return &OutboxRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure OutboxRepo implements OutboxRepository
+var _ storage.OutboxRepository = (*OutboxRepo)(nil)
+
+// Insert stores a new unpublished event.
+func (r *OutboxRepo) Insert(ctx context.Context, event *models.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query, event.AggregateType, event.AggregateID, event.EventType, event.Payload).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		log.Printf("Error inserting outbox event %s for %s %s: %v\n", event.EventType, event.AggregateType, event.AggregateID, err)
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// ListUnpublished retrieves the oldest unpublished events, in insertion order.
+func (r *OutboxRepo) ListUnpublished(ctx context.Context, req *dto.ListUnpublishedOutboxEventsRequest) ([]models.OutboxEvent, error) {
+	query := `
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at, published_at
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+	rows, err := r.db.Query(ctx, query, req.Limit)
+	if err != nil {
+		log.Printf("Error querying unpublished outbox events: %v\n", err)
+		return nil, fmt.Errorf("failed to query unpublished outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.OutboxEvent])
+	if err != nil {
+		log.Printf("Error scanning unpublished outbox events: %v\n", err)
+		return nil, fmt.Errorf("failed to scan unpublished outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// MarkPublished records that an event was delivered. Marking an already published event is a no-op.
+func (r *OutboxRepo) MarkPublished(ctx context.Context, req *dto.MarkOutboxEventPublishedRequest) error {
+	query := `UPDATE outbox SET published_at = NOW() WHERE id = $1 AND published_at IS NULL`
+	if _, err := r.db.Exec(ctx, query, req.ID); err != nil {
+		log.Printf("Error marking outbox event %d as published: %v\n", req.ID, err)
+		return fmt.Errorf("failed to mark outbox event %d as published: %w", req.ID, err)
+	}
+	return nil
+}
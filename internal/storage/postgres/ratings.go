@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RatingRepo implements the storage.RatingRepository interface using PostgreSQL.
+type RatingRepo struct {
+	db Querier
+}
+
+// NewRatingRepo creates a new RatingRepo.
+func NewRatingRepo(db *pgxpool.Pool) *RatingRepo {
+	return &RatingRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new RatingRepo with the transaction.
+func (r *RatingRepo) WithTx(tx pgx.Tx) storage.RatingRepository {
+	return &RatingRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure RatingRepo implements RatingRepository
+var _ storage.RatingRepository = (*RatingRepo)(nil)
+
+// Create saves a new rating. The unique (job_id, rater_id) constraint turns a second rating into storage.ErrConflict.
+func (r *RatingRepo) Create(ctx context.Context, rating *models.Rating) (*models.Rating, error) {
+	if rating.ID == uuid.Nil {
+		rating.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO ratings (id, job_id, rater_id, ratee_id, score, comment, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, job_id, rater_id, ratee_id, score, comment, created_at
+	`
+	row := r.db.QueryRow(ctx, query, rating.ID, rating.JobID, rating.RaterID, rating.RateeID, rating.Score, rating.Comment)
+
+	var createdRating models.Rating
+	err := row.Scan(
+		&createdRating.ID,
+		&createdRating.JobID,
+		&createdRating.RaterID,
+		&createdRating.RateeID,
+		&createdRating.Score,
+		&createdRating.Comment,
+		&createdRating.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" && pgErr.ConstraintName == "unique_job_rating" { // unique_violation
+				log.Printf("Error creating rating: job %s already rated by %s\n", rating.JobID, rating.RaterID)
+				return nil, fmt.Errorf("failed to create rating: job already rated: %w", storage.ErrConflict)
+			}
+			if pgErr.Code == "23503" { // foreign_key_violation
+				log.Printf("Error creating rating: Foreign key violation (job_id: %s): %v\n", rating.JobID, err)
+				return nil, fmt.Errorf("failed to create rating: invalid job or user ID: %w", storage.ErrConflict)
+			}
+		}
+		log.Printf("Error creating rating for job %s: %v\n", rating.JobID, err)
+		return nil, fmt.Errorf("failed to create rating: %w", err)
+	}
+
+	log.Printf("Rating created successfully with ID: %s", createdRating.ID)
+	return &createdRating, nil
+}
+
+// GetSummaryForUser returns the average score and number of ratings the user has received.
+func (r *RatingRepo) GetSummaryForUser(ctx context.Context, req *dto.GetUserRatingRequest) (*models.UserRatingSummary, error) {
+	query := `SELECT COALESCE(AVG(score), 0)::float8, COUNT(*) FROM ratings WHERE ratee_id = $1`
+
+	summary := &models.UserRatingSummary{UserID: req.UserID}
+	if err := r.db.QueryRow(ctx, query, req.UserID).Scan(&summary.AverageScore, &summary.RatingCount); err != nil {
+		log.Printf("Error averaging ratings for user %s: %v\n", req.UserID, err)
+		return nil, fmt.Errorf("failed to get rating summary: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SavedJobRepo implements the storage.SavedJobRepository interface using PostgreSQL.
+type SavedJobRepo struct {
+	db Querier
+}
+
+// NewSavedJobRepo creates a new SavedJobRepo.
+func NewSavedJobRepo(db *pgxpool.Pool) *SavedJobRepo {
+	return &SavedJobRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new SavedJobRepo with the transaction.
+func (r *SavedJobRepo) WithTx(tx pgx.Tx) storage.SavedJobRepository {
+	return &SavedJobRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure SavedJobRepo implements SavedJobRepository
+var _ storage.SavedJobRepository = (*SavedJobRepo)(nil)
+
+// savedAvailableJobsFrom joins a user's saved jobs back to jobs, keeping only those still available.
+// $1 is the user ID and $2 the Waiting state; keep the job conditions in sync with availableJobConditions.
+const savedAvailableJobsFrom = `
+	FROM saved_jobs s
+	JOIN jobs j ON j.id = s.job_id
+	WHERE s.user_id = $1
+		AND j.contractor_id IS NULL
+		AND j.state = $2
+		AND j.deleted_at IS NULL
+		AND (j.application_deadline IS NULL OR j.application_deadline > NOW())
+`
+
+// Save bookmarks a job for a user. Saving a job that is already saved is a no-op.
+func (r *SavedJobRepo) Save(ctx context.Context, req *dto.SaveJobRequest) error {
+	query := `
+		INSERT INTO saved_jobs (user_id, job_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, job_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, req.UserID, req.JobID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+			log.Printf("Error saving job %s for user %s: job or user does not exist\n", req.JobID, req.UserID)
+			return storage.ErrNotFound
+		}
+		log.Printf("Error saving job %s for user %s: %v\n", req.JobID, req.UserID, err)
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a user's bookmark of a job. Removing a job that isn't saved is a no-op.
+func (r *SavedJobRepo) Delete(ctx context.Context, req *dto.UnsaveJobRequest) error {
+	query := `DELETE FROM saved_jobs WHERE user_id = $1 AND job_id = $2`
+	if _, err := r.db.Exec(ctx, query, req.UserID, req.JobID); err != nil {
+		log.Printf("Error unsaving job %s for user %s: %v\n", req.JobID, req.UserID, err)
+		return fmt.Errorf("failed to unsave job: %w", err)
+	}
+	return nil
+}
+
+// ListJobs retrieves the user's saved jobs that are still available, most recently saved first.
+func (r *SavedJobRepo) ListJobs(ctx context.Context, req *dto.ListSavedJobsRequest) ([]models.Job, error) {
+	query := `
+		SELECT j.id, j.rate, j.currency, j.duration, j.contractor_id, j.employer_id, j.state, j.invoice_interval, j.description, j.application_deadline, j.auto_invoice, j.auto_invoice_cadence, j.next_auto_invoice_at, j.version, j.created_by, j.updated_by, j.created_at, j.updated_at, j.deleted_at
+	` + savedAvailableJobsFrom + `
+		ORDER BY s.created_at DESC, j.id
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, req.UserID, models.JobStateWaiting, req.Limit, req.Offset)
+	if err != nil {
+		log.Printf("Error querying saved jobs for user %s: %v\n", req.UserID, err)
+		return nil, fmt.Errorf("failed to query saved jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Job])
+	if err != nil {
+		log.Printf("Error scanning saved jobs for user %s: %v\n", req.UserID, err)
+		return nil, fmt.Errorf("failed to scan saved jobs: %w", err)
+	}
+
+	if jobs == nil {
+		jobs = []models.Job{} // Return empty slice, not nil
+	}
+
+	return jobs, nil
+}
+
+// CountJobs returns how many jobs ListJobs would return, ignoring pagination.
+func (r *SavedJobRepo) CountJobs(ctx context.Context, req *dto.ListSavedJobsRequest) (int, error) {
+	query := `SELECT COUNT(*)` + savedAvailableJobsFrom
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, req.UserID, models.JobStateWaiting).Scan(&total); err != nil {
+		log.Printf("Error counting saved jobs for user %s: %v\n", req.UserID, err)
+		return 0, fmt.Errorf("failed to count saved jobs: %w", err)
+	}
+	return total, nil
+}
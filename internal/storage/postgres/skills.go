@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SkillRepo implements the storage.SkillRepository interface using PostgreSQL.
+type SkillRepo struct {
+	db Querier
+}
+
+// NewSkillRepo creates a new SkillRepo.
+func NewSkillRepo(db *pgxpool.Pool) *SkillRepo {
+	return &SkillRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new SkillRepo with the transaction.
+func (r *SkillRepo) WithTx(tx pgx.Tx) storage.SkillRepository {
+	return &SkillRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure SkillRepo implements SkillRepository
+var _ storage.SkillRepository = (*SkillRepo)(nil)
+
+// Upsert creates the skills named in names that don't exist yet and returns every one of them, sorted
+// by name. Names are normalized first, so "Go " and "go" are one skill. Each insert that collides
+// with an existing name, including one committed meanwhile by a concurrent caller, returns the existing
+// row instead, so a skill is never created twice nor reported missing.
+func (r *SkillRepo) Upsert(ctx context.Context, names []string) ([]models.Skill, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = models.NormalizeSkillName(name)
+		if name == "" || seen[name] {
+			continue // DO UPDATE can't touch the same row twice in one statement
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	if len(normalized) == 0 {
+		return []models.Skill{}, nil
+	}
+	sort.Strings(normalized) // Concurrent callers lock existing rows in the same order, so they can't deadlock
+
+	ids := make([]uuid.UUID, len(normalized))
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	// DO UPDATE rather than DO NOTHING, so RETURNING also yields the rows that already existed
+	query := `
+		INSERT INTO skills (id, name)
+		SELECT * FROM unnest($1::uuid[], $2::text[])
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id, name, created_at
+	`
+	rows, err := r.db.Query(ctx, query, ids, normalized)
+	if err != nil {
+		log.Printf("Error upserting %d skills: %v\n", len(normalized), err)
+		return nil, fmt.Errorf("failed to upsert skills: %w", err)
+	}
+	skills, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Skill])
+	if err != nil {
+		log.Printf("Error reading upserted skills: %v\n", err)
+		return nil, fmt.Errorf("failed to read upserted skills: %w", err)
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	return skills, nil
+}
+
+// SetForJob replaces the job's skills with req.Names, creating any skill that doesn't exist yet.
+// It runs several statements, so call it inside a transaction.
+func (r *SkillRepo) SetForJob(ctx context.Context, req *dto.SetJobSkillsRequest) error {
+	skills, err := r.Upsert(ctx, req.Names)
+	if err != nil {
+		log.Printf("Error creating skills for job %s: %v\n", req.JobID, err)
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, `DELETE FROM job_skills WHERE job_id = $1`, req.JobID); err != nil {
+		log.Printf("Error clearing skills of job %s: %v\n", req.JobID, err)
+		return fmt.Errorf("failed to clear job skills: %w", err)
+	}
+	if len(skills) == 0 {
+		return nil
+	}
+
+	skillIDs := make([]uuid.UUID, len(skills))
+	for i, skill := range skills {
+		skillIDs[i] = skill.ID
+	}
+	linkQuery := `
+		INSERT INTO job_skills (job_id, skill_id)
+		SELECT $1, unnest($2::uuid[])
+	`
+	if _, err := r.db.Exec(ctx, linkQuery, req.JobID, skillIDs); err != nil {
+		log.Printf("Error linking skills to job %s: %v\n", req.JobID, err)
+		return fmt.Errorf("failed to link job skills: %w", err)
+	}
+	return nil
+}
+
+// ListByJobIDs returns the skill names of each of the given jobs, sorted by name. Jobs without
+// skills are absent from the map.
+func (r *SkillRepo) ListByJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	skills := make(map[uuid.UUID][]string)
+	if len(jobIDs) == 0 {
+		return skills, nil
+	}
+
+	query := `
+		SELECT js.job_id, s.name
+		FROM job_skills js
+		JOIN skills s ON s.id = js.skill_id
+		WHERE js.job_id = ANY($1::uuid[])
+		ORDER BY s.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, jobIDs)
+	if err != nil {
+		log.Printf("Error querying skills for %d jobs: %v\n", len(jobIDs), err)
+		return nil, fmt.Errorf("failed to query job skills: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobID uuid.UUID
+		var name string
+		if err := rows.Scan(&jobID, &name); err != nil {
+			log.Printf("Error scanning job skill row: %v\n", err)
+			return nil, fmt.Errorf("failed to scan job skills: %w", err)
+		}
+		skills[jobID] = append(skills[jobID], name)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating job skill rows: %v\n", err)
+		return nil, fmt.Errorf("failed to read job skills: %w", err)
+	}
+	return skills, nil
+}
@@ -5,10 +5,12 @@ import (
 	"errors" // Import errors package
 	"fmt"
 	"log" // For logging errors
+	"strings"
 
 	"go-api-template/internal/models"
 	"go-api-template/internal/storage" // Import the interface package
 	"go-api-template/internal/transport/dto"
+	"go-api-template/pkg/hasher"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,41 +21,50 @@ import (
 
 // UserRepo implements the storage.UserRepository interface using PostgreSQL.
 type UserRepo struct {
-	db Querier
+	db     Querier
+	hasher hasher.Hasher // Hashes passwords in Create
 }
 
-// NewUserRepo creates a new UserRepo.
+// NewUserRepo creates a new UserRepo that hashes passwords with bcrypt.DefaultCost.
 func NewUserRepo(db *pgxpool.Pool) *UserRepo {
-	return &UserRepo{db: db}
+	return &UserRepo{db: cancelAware(db), hasher: hasher.NewBcrypt(bcrypt.DefaultCost)}
+}
+
+// WithHasher returns a copy of the repo that hashes passwords with h.
+func (r *UserRepo) WithHasher(h hasher.Hasher) *UserRepo {
+	return &UserRepo{db: r.db, hasher: h}
 }
 
 // WithTx creates a new UserRepo with the transaction.
 func (r *UserRepo) WithTx(tx pgx.Tx) storage.UserRepository {
-	return &UserRepo{db: tx}
+	return &UserRepo{db: cancelAware(tx), hasher: r.hasher}
 }
 
 // Compile-time check to ensure UserRepo implements UserRepository
 var _ storage.UserRepository = (*UserRepo)(nil)
 
-func (r *UserRepo) GetAll(ctx context.Context) ([]models.User, error) {
-	query := `SELECT id, name, email, created_at, updated_at FROM users ORDER BY name ASC;` // Select needed fields
-	rows, err := r.db.Query(ctx, query)
+// List retrieves a page of users ordered by name, optionally only those whose name or email contains
+// the search term.
+func (r *UserRepo) List(ctx context.Context, req *dto.ListUsersRequest) ([]models.User, error) {
+	conditions, args := userListConditions(req)
+	args = append(args, req.Limit, req.Offset)
+	query := fmt.Sprintf(`SELECT id, name, email, verified, role, avatar_url, created_at, updated_at FROM users WHERE %s ORDER BY name ASC, id ASC LIMIT $%d OFFSET $%d;`,
+		strings.Join(conditions, " AND "), len(args)-1, len(args))
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		log.Printf("Error querying all users: %v\n", err)
-		return nil, err
+		log.Printf("Error querying users: %v\n", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
 	defer rows.Close()
 
-	// Scan manually or use RowToStructByName if all struct fields are selected
 	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.User, error) {
 		var u models.User
-		// Explicitly scan only the selected columns
-		err := row.Scan(&u.ID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt)
+		err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Verified, &u.Role, &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
 		return u, err
 	})
 	if err != nil {
 		log.Printf("Error scanning users: %v\n", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to scan users: %w", err)
 	}
 
 	// Return empty slice instead of nil if no users found
@@ -64,12 +75,41 @@ func (r *UserRepo) GetAll(ctx context.Context) ([]models.User, error) {
 	return users, nil
 }
 
+// Count returns how many users match the List filters, ignoring pagination.
+func (r *UserRepo) Count(ctx context.Context, req *dto.ListUsersRequest) (int, error) {
+	conditions, args := userListConditions(req)
+	query := "SELECT COUNT(*) FROM users WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		log.Printf("Error counting users: %v\n", err)
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return total, nil
+}
+
+// likeEscaper escapes the LIKE wildcards, so a search term matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// userListConditions builds the WHERE conditions shared by List and Count.
+func userListConditions(req *dto.ListUsersRequest) ([]string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if req.Search != nil {
+		if term := strings.TrimSpace(*req.Search); term != "" {
+			args = append(args, "%"+likeEscaper.Replace(term)+"%")
+			conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d)", len(args), len(args)))
+		}
+	}
+	return conditions, args
+}
+
 func (r *UserRepo) GetByID(ctx context.Context, id *dto.GetUserByIdRequest) (*models.User, error) {
-	query := `SELECT id, name, email FROM users WHERE id = $1;`
+	query := `SELECT id, name, email, verified, role, avatar_url FROM users WHERE id = $1 AND deleted_at IS NULL;`
 	row := r.db.QueryRow(ctx, query, id.ID)
 
 	var user models.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Verified, &user.Role, &user.AvatarURL)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, storage.ErrNotFound // Use a custom error type later if needed
@@ -80,10 +120,64 @@ func (r *UserRepo) GetByID(ctx context.Context, id *dto.GetUserByIdRequest) (*mo
 	return &user, nil
 }
 
+// GetByIDs retrieves the users with the given IDs in a single query. IDs that don't match a
+// (non-deleted) user are skipped, and the rows come back in no particular order.
+func (r *UserRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	query := `SELECT id, name, email, verified, role, avatar_url, created_at, updated_at FROM users WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL;`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		log.Printf("Error querying users by IDs: %v\n", err)
+		return nil, fmt.Errorf("failed to query users by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.User, error) {
+		var u models.User
+		err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Verified, &u.Role, &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
+		return u, err
+	})
+	if err != nil {
+		log.Printf("Error scanning users by IDs: %v\n", err)
+		return nil, fmt.Errorf("failed to scan users by IDs: %w", err)
+	}
+
+	if users == nil {
+		users = []models.User{}
+	}
+	return users, nil
+}
+
+// Exists reports whether a user with the given ID exists and isn't soft-deleted, without loading the row.
+func (r *UserRepo) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking existence of user %s: %v\n", id, err)
+		return false, fmt.Errorf("failed to check user existence: %w", err)
+	}
+	return exists, nil
+}
+
+// LockMaxOngoingJobs returns the user's own cap on Ongoing jobs, or nil if the configured default
+// applies. The user row stays locked until the transaction ends, so concurrent assignments to the same
+// contractor are counted one after the other.
+func (r *UserRepo) LockMaxOngoingJobs(ctx context.Context, id uuid.UUID) (*int, error) {
+	var limit *int
+	err := r.db.QueryRow(ctx, `SELECT max_ongoing_jobs FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&limit)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error locking ongoing job limit of user %s: %v\n", id, err)
+		return nil, fmt.Errorf("failed to get ongoing job limit: %w", err)
+	}
+	return limit, nil
+}
+
 // GetByEmail retrieves a single user by Email, including the password hash.
 func (r *UserRepo) GetByEmail(ctx context.Context, email *dto.GetUserByEmailRequest) (*models.User, error) {
 	// Select all fields needed for authentication comparison
-	query := `SELECT id, name, email, password_hash, created_at, updated_at FROM users WHERE email = $1;`
+	query := `SELECT id, name, email, password_hash, verified, role, avatar_url, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL;`
 	row := r.db.QueryRow(ctx, query, email.Email)
 
 	var user models.User
@@ -93,6 +187,9 @@ func (r *UserRepo) GetByEmail(ctx context.Context, email *dto.GetUserByEmailRequ
 		&user.Name,
 		&user.Email,
 		&user.PasswordHash, // Include password hash
+		&user.Verified,
+		&user.Role,
+		&user.AvatarURL,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -109,7 +206,7 @@ func (r *UserRepo) GetByEmail(ctx context.Context, email *dto.GetUserByEmailRequ
 
 func (r *UserRepo) Create(ctx context.Context, userReq *dto.CreateUserRequest) (*models.User, error) {
 	// --- Password Hashing ---
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userReq.Password), bcrypt.DefaultCost)
+	hashedPassword, err := r.hasher.Hash(userReq.Password)
 	if err != nil {
 		log.Printf("Error hashing password for email %s: %v\n", userReq.Email, err)
 		return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -120,15 +217,17 @@ func (r *UserRepo) Create(ctx context.Context, userReq *dto.CreateUserRequest) (
 	// Use NOW() for timestamps assuming DB columns are TIMESTAMPTZ
 	sql := `INSERT INTO users (id, name, email, password_hash, created_at, updated_at)
              VALUES ($1, $2, $3, $4, NOW(), NOW())
-             RETURNING id, name, email, created_at, updated_at` // Return safe fields
+             RETURNING id, name, email, verified, role, created_at, updated_at` // Return safe fields
 
 	createdUser := &models.User{} // To store the returned values
 
 	// Execute the query, passing the hashed password
-	err = r.db.QueryRow(ctx, sql, uuid.New(), userReq.Name, userReq.Email, string(hashedPassword)).Scan(
+	err = r.db.QueryRow(ctx, sql, uuid.New(), userReq.Name, userReq.Email, hashedPassword).Scan(
 		&createdUser.ID,
 		&createdUser.Name,
 		&createdUser.Email,
+		&createdUser.Verified,
+		&createdUser.Role,
 		&createdUser.CreatedAt,
 		&createdUser.UpdatedAt,
 		// Note: We are NOT returning/scanning the password_hash back
@@ -156,17 +255,25 @@ func (r *UserRepo) Create(ctx context.Context, userReq *dto.CreateUserRequest) (
 }
 
 func (r *UserRepo) Update(ctx context.Context, user *dto.UpdateUserRequest) (*models.User, error) {
+	// COALESCE keeps the current value for any field left nil in the request
 	sql := `UPDATE users
-             SET name = $1
-             WHERE id = $2
-             RETURNING id, name, email, created_at, updated_at` // Return all needed fields
+             SET name = COALESCE($1, name),
+                 password_hash = COALESCE($2, password_hash),
+                 verified = COALESCE($3, verified),
+                 role = COALESCE($4, role),
+                 email = COALESCE($5, email)
+             WHERE id = $6 AND deleted_at IS NULL
+             RETURNING id, name, email, verified, role, avatar_url, created_at, updated_at` // Return all needed fields
 
 	updatedUser := &models.User{}
 
-	err := r.db.QueryRow(ctx, sql, user.Name, user.ID).Scan( // Pass values for SET and WHERE
+	err := r.db.QueryRow(ctx, sql, user.Name, user.PasswordHash, user.Verified, user.Role, user.Email, user.ID).Scan( // Pass values for SET and WHERE
         &updatedUser.ID,
         &updatedUser.Name,
         &updatedUser.Email,
+        &updatedUser.Verified,
+        &updatedUser.Role,
+        &updatedUser.AvatarURL,
         &updatedUser.CreatedAt,
         &updatedUser.UpdatedAt, // This will contain the trigger-set value
     )
@@ -188,8 +295,9 @@ func (r *UserRepo) Update(ctx context.Context, user *dto.UpdateUserRequest) (*mo
 	return updatedUser, nil
 }
 
+// Delete soft-deletes a user by setting deleted_at; the row is kept so it can be audited or restored.
 func (r *UserRepo) Delete(ctx context.Context, id *dto.DeleteUserRequest) error {
-	query := `DELETE FROM users WHERE id = $1;`
+	query := `UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL;`
 
 	cmdTag, err := r.db.Exec(ctx, query, id.ID)
 	if err != nil {
@@ -203,3 +311,61 @@ func (r *UserRepo) Delete(ctx context.Context, id *dto.DeleteUserRequest) error
 
 	return nil
 }
+
+// Restore clears deleted_at on a soft-deleted user. Returns storage.ErrNotFound if no deleted user has that ID.
+func (r *UserRepo) Restore(ctx context.Context, id *dto.RestoreUserRequest) (*models.User, error) {
+	sql := `UPDATE users
+             SET deleted_at = NULL, updated_at = NOW()
+             WHERE id = $1 AND deleted_at IS NOT NULL
+             RETURNING id, name, email, verified, role, avatar_url, created_at, updated_at`
+
+	restoredUser := &models.User{}
+	err := r.db.QueryRow(ctx, sql, id.ID).Scan(
+		&restoredUser.ID,
+		&restoredUser.Name,
+		&restoredUser.Email,
+		&restoredUser.Verified,
+		&restoredUser.Role,
+		&restoredUser.AvatarURL,
+		&restoredUser.CreatedAt,
+		&restoredUser.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error restoring user %s: %v\n", id.ID, err)
+		return nil, err
+	}
+
+	return restoredUser, nil
+}
+
+// SetAvatarURL sets the user's avatar_url, or clears it when req.AvatarURL is nil.
+func (r *UserRepo) SetAvatarURL(ctx context.Context, req *dto.SetUserAvatarRequest) (*models.User, error) {
+	sql := `UPDATE users
+             SET avatar_url = $1
+             WHERE id = $2 AND deleted_at IS NULL
+             RETURNING id, name, email, verified, role, avatar_url, created_at, updated_at`
+
+	updatedUser := &models.User{}
+	err := r.db.QueryRow(ctx, sql, req.AvatarURL, req.ID).Scan(
+		&updatedUser.ID,
+		&updatedUser.Name,
+		&updatedUser.Email,
+		&updatedUser.Verified,
+		&updatedUser.Role,
+		&updatedUser.AvatarURL,
+		&updatedUser.CreatedAt,
+		&updatedUser.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error setting avatar of user %s: %v\n", req.ID, err)
+		return nil, fmt.Errorf("failed to set user avatar: %w", err)
+	}
+
+	return updatedUser, nil
+}
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/storage"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WebhookRepo implements the storage.WebhookRepository interface using PostgreSQL.
+type WebhookRepo struct {
+	db Querier
+}
+
+// NewWebhookRepo creates a new WebhookRepo.
+func NewWebhookRepo(db *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{db: cancelAware(db)}
+}
+
+// WithTx creates a new WebhookRepo with the transaction.
+func (r *WebhookRepo) WithTx(tx pgx.Tx) storage.WebhookRepository {
+	return &WebhookRepo{db: cancelAware(tx)}
+}
+
+// Compile-time check to ensure WebhookRepo implements WebhookRepository
+var _ storage.WebhookRepository = (*WebhookRepo)(nil)
+
+const webhookColumns = `id, owner, url, secret, event_types, active, created_by, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var w models.Webhook
+	err := row.Scan(&w.ID, &w.Owner, &w.URL, &w.Secret, &w.EventTypes, &w.Active, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Create saves a new webhook.
+func (r *WebhookRepo) Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO webhooks (id, owner, url, secret, event_types, active, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING ` + webhookColumns
+	created, err := scanWebhook(r.db.QueryRow(ctx, query, webhook.ID, webhook.Owner, webhook.URL, webhook.Secret, webhook.EventTypes, webhook.Active, webhook.CreatedBy))
+	if err != nil {
+		log.Printf("Error creating webhook for %s: %v\n", webhook.Owner, err)
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	log.Printf("Webhook created successfully with ID: %s", created.ID)
+	return created, nil
+}
+
+// GetByID returns the webhook with the given ID.
+func (r *WebhookRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+	webhook, err := scanWebhook(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error scanning webhook %s: %v\n", id, err)
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// List retrieves every webhook, oldest first.
+func (r *WebhookRepo) List(ctx context.Context) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY created_at ASC, id ASC`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		log.Printf("Error querying webhooks: %v\n", err)
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Webhook, error) {
+		w, err := scanWebhook(row)
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		return *w, nil
+	})
+	if err != nil {
+		log.Printf("Error scanning webhooks: %v\n", err)
+		return nil, fmt.Errorf("failed to scan webhooks: %w", err)
+	}
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+	return webhooks, nil
+}
+
+// SetActive pauses or resumes a webhook. Deliveries are only queued for active webhooks, and those
+// already queued wait while it is paused.
+func (r *WebhookRepo) SetActive(ctx context.Context, req *dto.UpdateWebhookRequest) (*models.Webhook, error) {
+	query := `
+		UPDATE webhooks
+		SET active = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + webhookColumns
+	webhook, err := scanWebhook(r.db.QueryRow(ctx, query, req.ID, *req.Active))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Webhook not found for update with ID: %s\n", req.ID)
+			return nil, storage.ErrNotFound
+		}
+		log.Printf("Error updating webhook %s: %v\n", req.ID, err)
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// Delete removes a webhook together with its deliveries.
+func (r *WebhookRepo) Delete(ctx context.Context, req *dto.DeleteWebhookRequest) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, req.ID)
+	if err != nil {
+		log.Printf("Error deleting webhook %s: %v\n", req.ID, err)
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		log.Printf("Webhook not found for deletion with ID: %s\n", req.ID)
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// EnqueueDeliveries queues payload for every active webhook subscribed to the event's type and
+// returns how many were queued. A webhook that already has a delivery for the event is skipped, so
+// handing over the same event twice queues nothing new.
+func (r *WebhookRepo) EnqueueDeliveries(ctx context.Context, event models.OutboxEvent, payload []byte) (int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $3
+		FROM webhooks
+		WHERE active AND $2 = ANY(event_types)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+	result, err := r.db.Exec(ctx, query, event.ID, event.EventType, payload)
+	if err != nil {
+		log.Printf("Error queueing webhook deliveries for outbox event %d: %v\n", event.ID, err)
+		return 0, fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// ClaimDueDeliveries takes up to req.Limit pending deliveries of active webhooks whose next attempt
+// is due, oldest first, and pushes their next attempt req.Lease into the future. A worker that dies
+// before recording the outcome thus has its deliveries retried once the lease runs out, and other
+// workers skip the rows being claimed rather than waiting for them.
+func (r *WebhookRepo) ClaimDueDeliveries(ctx context.Context, req *dto.ClaimWebhookDeliveriesRequest) ([]models.ClaimedWebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+			ORDER BY d.next_attempt_at ASC, d.id ASC
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond', updated_at = NOW()
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+			d.last_status_code, d.last_error, d.delivered_at, d.created_at, d.updated_at, w.url, w.secret
+	`
+	rows, err := r.db.Query(ctx, query, req.Limit, req.Lease.Milliseconds())
+	if err != nil {
+		log.Printf("Error claiming webhook deliveries: %v\n", err)
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	claimed, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ClaimedWebhookDelivery, error) {
+		var c models.ClaimedWebhookDelivery
+		d := &c.Delivery
+		err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt, &d.UpdatedAt, &c.URL, &c.Secret)
+		return c, err
+	})
+	if err != nil {
+		log.Printf("Error scanning claimed webhook deliveries: %v\n", err)
+		return nil, fmt.Errorf("failed to scan claimed webhook deliveries: %w", err)
+	}
+	return claimed, nil
+}
+
+// RecordDeliveryAttempt stores the outcome of an attempt and counts it. req.NextAttemptAt only applies
+// to a delivery that stays pending.
+func (r *WebhookRepo) RecordDeliveryAttempt(ctx context.Context, req *dto.RecordWebhookAttemptRequest) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2,
+			attempts = attempts + 1,
+			last_status_code = $3,
+			last_error = $4,
+			next_attempt_at = CASE WHEN $2 = 'pending' THEN $5 ELSE next_attempt_at END,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.db.Exec(ctx, query, req.ID, req.Status, req.StatusCode, req.Error, req.NextAttemptAt)
+	if err != nil {
+		log.Printf("Error recording attempt of webhook delivery %d: %v\n", req.ID, err)
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return storage.ErrNotFound // The webhook was deleted while the attempt was made
+	}
+	return nil
+}
+
+// ListDeliveries retrieves a page of a webhook's deliveries, newest first.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, req *dto.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, error) {
+	conditions, args := webhookDeliveryConditions(req)
+	args = append(args, req.Limit, req.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries WHERE %s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		webhookDeliveryColumns, strings.Join(conditions, " AND "), len(args)-1, len(args))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying deliveries of webhook %s: %v\n", req.WebhookID, err)
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.WebhookDelivery])
+	if err != nil {
+		log.Printf("Error scanning deliveries of webhook %s: %v\n", req.WebhookID, err)
+		return nil, fmt.Errorf("failed to scan webhook deliveries: %w", err)
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// CountDeliveries returns how many deliveries match the ListDeliveries filters, ignoring pagination.
+func (r *WebhookRepo) CountDeliveries(ctx context.Context, req *dto.ListWebhookDeliveriesRequest) (int, error) {
+	conditions, args := webhookDeliveryConditions(req)
+	query := "SELECT COUNT(*) FROM webhook_deliveries WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		log.Printf("Error counting deliveries of webhook %s: %v\n", req.WebhookID, err)
+		return 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+	return total, nil
+}
+
+// webhookDeliveryConditions builds the WHERE conditions shared by ListDeliveries and CountDeliveries.
+func webhookDeliveryConditions(req *dto.ListWebhookDeliveriesRequest) ([]string, []interface{}) {
+	conditions := []string{"webhook_id = $1"}
+	args := []interface{}{req.WebhookID}
+	if req.Status != nil {
+		args = append(args, *req.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	return conditions, args
+}
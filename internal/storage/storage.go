@@ -13,12 +13,18 @@ import (
 
 // UserRepository defines the interface for user data operations.
 type UserRepository interface {
-	GetAll(ctx context.Context) ([]models.User, error)
+	List(ctx context.Context, req *dto.ListUsersRequest) ([]models.User, error) // Ordered by name
+	Count(ctx context.Context, req *dto.ListUsersRequest) (int, error)
 	GetByID(ctx context.Context, id *dto.GetUserByIdRequest) (*models.User, error)
+	Exists(ctx context.Context, id uuid.UUID) (bool, error) // False for soft-deleted users
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) // Unknown and soft-deleted IDs are skipped; order is unspecified
 	GetByEmail(ctx context.Context, id *dto.GetUserByEmailRequest) (*models.User, error)
 	Create(ctx context.Context, user *dto.CreateUserRequest) (*models.User, error) // Modify to return created user ID or full user if needed
 	Update(ctx context.Context, user *dto.UpdateUserRequest) (*models.User, error) // Modify to return updated user if needed
-	Delete(ctx context.Context, id *dto.DeleteUserRequest) error
+	Delete(ctx context.Context, id *dto.DeleteUserRequest) error // Soft delete; sets deleted_at
+	Restore(ctx context.Context, id *dto.RestoreUserRequest) (*models.User, error)
+	SetAvatarURL(ctx context.Context, req *dto.SetUserAvatarRequest) (*models.User, error)
+	LockMaxOngoingJobs(ctx context.Context, id uuid.UUID) (*int, error) // Nil means the configured default applies; call within a transaction
 	WithTx(tx pgx.Tx) UserRepository
 }
 
@@ -26,11 +32,26 @@ type UserRepository interface {
 type JobRepository interface {
 	Create(ctx context.Context, req *dto.CreateJobRequest) (*models.Job, error) 
 	GetByID(ctx context.Context, req *dto.GetJobByIDRequest) (*models.Job, error)
+	Exists(ctx context.Context, id uuid.UUID) (bool, error) // False for soft-deleted jobs
+	SharesOngoingJob(ctx context.Context, userID, otherID uuid.UUID) (bool, error) // Employer and contractor of the same Ongoing job, either way round
 	ListAvailable(ctx context.Context, req *dto.ListAvailableJobsRequest) ([]models.Job, error)
 	ListByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) ([]models.Job, error)
 	ListByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) ([]models.Job, error)
+	CountAvailable(ctx context.Context, req *dto.ListAvailableJobsRequest) (int, error)
+	CountByEmployer(ctx context.Context, req *dto.ListJobsByEmployerRequest) (int, error)
+	CountByContractor(ctx context.Context, req *dto.ListJobsByContractorRequest) (int, error)
 	Update(ctx context.Context, req *dto.UpdateJobRequest) (*models.Job, error)
-	Delete(ctx context.Context, req *dto.DeleteJobRequest) error
+	AssignContractor(ctx context.Context, req *dto.AssignContractorRequest) (*models.Job, error)
+	TransferOwnership(ctx context.Context, req *dto.TransferJobRequest) (*models.Job, error) // Returns ErrConflict once a contractor is assigned
+	Delete(ctx context.Context, req *dto.DeleteJobRequest) error // Soft delete; sets deleted_at
+	Restore(ctx context.Context, req *dto.RestoreJobRequest) (*models.Job, error)
+	ListStaleCompletedIDs(ctx context.Context, req *dto.ArchiveStaleJobsRequest) ([]uuid.UUID, error) // Oldest first
+	ListPurgeableArchivedIDs(ctx context.Context, req *dto.PurgeArchivedJobsRequest) ([]uuid.UUID, error) // Oldest first; locks the rows, so call through WithTx
+	Purge(ctx context.Context, ids []uuid.UUID) (int, error) // Hard delete; applications, saves and skills cascade, ratings are kept
+	SetAutoInvoice(ctx context.Context, req *dto.SetJobAutoInvoiceRequest) (*models.Job, error)
+	ListDueAutoInvoiceIDs(ctx context.Context, req *dto.GenerateDueAutoInvoicesRequest) ([]uuid.UUID, error) // Most overdue first
+	AdvanceAutoInvoice(ctx context.Context, req *dto.AdvanceAutoInvoiceRequest) (bool, error) // False if someone else moved it first
+	GetEmployerStats(ctx context.Context, req *dto.EmployerStatsRequest) (*models.EmployerStats, error) // Fills everything but TotalInvoiced
 	WithTx(tx pgx.Tx) JobRepository
 }
 
@@ -39,9 +60,22 @@ type InvoiceRepository interface {
 	Create(ctx context.Context, invoice *models.Invoice) (*models.Invoice, error)
 	GetByID(ctx context.Context, req *dto.GetInvoiceByIDRequest) (*models.Invoice, error)
 	ListByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) ([]models.Invoice, error)
+	CountByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest) (int, error)
+	StreamByJob(ctx context.Context, req *dto.ListInvoicesByJobRequest, fn func(invoice *models.Invoice) error) error // ListByJob without pagination, one row at a time
+	ListByEmployer(ctx context.Context, req *dto.ListEmployerInvoicesRequest) ([]models.Invoice, error) // Newest first
+	CountByEmployer(ctx context.Context, req *dto.ListEmployerInvoicesRequest) (int, error)
+	StreamByEmployer(ctx context.Context, req *dto.ListEmployerInvoicesRequest, fn func(invoice *models.Invoice) error) error
 	UpdateState(ctx context.Context, req *dto.UpdateInvoiceStateRequest) (*models.Invoice, error)
+	MarkPaid(ctx context.Context, req *dto.MarkInvoicePaidRequest) (*models.Invoice, error)
 	Delete(ctx context.Context, req *dto.DeleteInvoiceRequest) error
+	DeletePaidByJobs(ctx context.Context, jobIDs []uuid.UUID) (int, error)
 	GetMaxIntervalForJob(ctx context.Context, req *dto.GetMaxIntervalForJobRequest) (int, error)
+	ListIntervalNumbersForJob(ctx context.Context, req *dto.ListIntervalNumbersForJobRequest) ([]int, error)
+	NextInvoiceSequence(ctx context.Context, req *dto.NextInvoiceSequenceRequest) (int64, error)
+	SumValueByEmployer(ctx context.Context, req *dto.EmployerStatsRequest) (map[string]float64, error) // Keyed by currency
+	SumValuesByJob(ctx context.Context, req *dto.GetJobBalanceRequest) (invoiced float64, paid float64, err error)
+	SumUnpaidByContractor(ctx context.Context, req *dto.ContractorDashboardRequest) (*models.UnpaidInvoiceTotals, error)
+	ListOverdueByEmployer(ctx context.Context, req *dto.ListOverdueInvoicesRequest) ([]models.Invoice, error)
 	WithTx(tx pgx.Tx) InvoiceRepository
 }
 
@@ -51,12 +85,86 @@ type JobApplicationRepository interface {
 	GetByID(ctx context.Context, req *dto.GetJobApplicationByIDRequest) (*models.JobApplication, error)
 	ListByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) ([]models.JobApplication, error)
 	ListByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) ([]models.JobApplication, error)
+	CountByContractor(ctx context.Context, req *dto.ListJobApplicationsByContractorRequest) (int, error)
+	ListAppliedJobs(ctx context.Context, req *dto.ListAppliedJobsRequest) ([]models.AppliedJob, error) // Joined with the job; deleted jobs are skipped
+	CountAppliedJobs(ctx context.Context, req *dto.ListAppliedJobsRequest) (int, error)
+	CountByJob(ctx context.Context, req *dto.ListJobApplicationsByJobRequest) (int, error)
+	StreamByJob(ctx context.Context, req *dto.ExportJobApplicationsCSVRequest, fn func(row *models.JobApplicationExportRow) error) error
 	UpdateState(ctx context.Context, req *dto.UpdateJobApplicationStateRequest) (*models.JobApplication, error)
 	UpdateStateByJobID(ctx context.Context, jobID uuid.UUID, newState models.JobApplicationState, excludeApplicationID *uuid.UUID) error
 	Delete(ctx context.Context, req *dto.DeleteJobApplicationRequest) error
 	WithTx(tx pgx.Tx) JobApplicationRepository
 }
 
+// SavedJobRepository defines the interface for users' saved (bookmarked) jobs.
+type SavedJobRepository interface {
+	Save(ctx context.Context, req *dto.SaveJobRequest) error // Idempotent
+	Delete(ctx context.Context, req *dto.UnsaveJobRequest) error // Idempotent
+	ListJobs(ctx context.Context, req *dto.ListSavedJobsRequest) ([]models.Job, error) // Only jobs that are still available
+	CountJobs(ctx context.Context, req *dto.ListSavedJobsRequest) (int, error)
+	WithTx(tx pgx.Tx) SavedJobRepository
+}
+
+// RatingRepository defines the interface for rating storage operations.
+type RatingRepository interface {
+	Create(ctx context.Context, rating *models.Rating) (*models.Rating, error) // Returns ErrConflict if the rater already rated the job
+	GetSummaryForUser(ctx context.Context, req *dto.GetUserRatingRequest) (*models.UserRatingSummary, error)
+	WithTx(tx pgx.Tx) RatingRepository
+}
+
+// SkillRepository defines the interface for the skills attached to jobs.
+type SkillRepository interface {
+	SetForJob(ctx context.Context, req *dto.SetJobSkillsRequest) error  // Creates missing skills; use inside a transaction
+	Upsert(ctx context.Context, names []string) ([]models.Skill, error) // Creates or fetches each skill, safe against concurrent creation
+	ListByJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+	WithTx(tx pgx.Tx) SkillRepository
+}
+
+// OutboxRepository defines the interface for transactional outbox storage operations.
+type OutboxRepository interface {
+	Insert(ctx context.Context, event *models.OutboxEvent) error // Call through WithTx so the event commits with the state change
+	ListUnpublished(ctx context.Context, req *dto.ListUnpublishedOutboxEventsRequest) ([]models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, req *dto.MarkOutboxEventPublishedRequest) error
+	WithTx(tx pgx.Tx) OutboxRepository
+}
+
+// AuditRepository defines the interface for the audit log.
+type AuditRepository interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error // Call through WithTx so the entry commits with the change
+	ListByEntity(ctx context.Context, entityType string, entityID uuid.UUID) ([]models.AuditEntry, error) // Oldest first
+	WithTx(tx pgx.Tx) AuditRepository
+}
+
+// APIKeyRepository defines the interface for machine client API keys.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) // Includes revoked keys
+	Revoke(ctx context.Context, req *dto.RevokeAPIKeyRequest) (*models.APIKey, error) // Idempotent
+	WithTx(tx pgx.Tx) APIKeyRepository
+}
+
+// WebhookRepository defines the interface for webhook subscriptions and their deliveries.
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	List(ctx context.Context) ([]models.Webhook, error) // Oldest first
+	SetActive(ctx context.Context, req *dto.UpdateWebhookRequest) (*models.Webhook, error)
+	Delete(ctx context.Context, req *dto.DeleteWebhookRequest) error // Also deletes its deliveries
+	EnqueueDeliveries(ctx context.Context, event models.OutboxEvent, payload []byte) (int, error) // One per active webhook subscribed to the event; repeats are ignored
+	ClaimDueDeliveries(ctx context.Context, req *dto.ClaimWebhookDeliveriesRequest) ([]models.ClaimedWebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, req *dto.RecordWebhookAttemptRequest) error
+	ListDeliveries(ctx context.Context, req *dto.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, error) // Newest first
+	CountDeliveries(ctx context.Context, req *dto.ListWebhookDeliveriesRequest) (int, error)
+	WithTx(tx pgx.Tx) WebhookRepository
+}
+
+// CacheInvalidator is implemented by repositories that cache reads. Their writes invalidate right
+// away, but inside a transaction a concurrent read can cache the old row again before the commit,
+// so services also invalidate once a transaction that wrote the rows has committed.
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, ids ...uuid.UUID)
+}
+
 // TransactionBeginner defines an interface for repositories that can operate within a transaction.
 type TransactionBeginner interface {
 	// WithTx returns a new repository instance that uses the provided transaction.
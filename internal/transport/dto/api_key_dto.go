@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAPIKeyRequest defines the structure for minting an API key for a machine client.
+type CreateAPIKeyRequest struct {
+	Owner     string    `json:"owner" validate:"required,max=100"`
+	Scopes    []string  `json:"scopes" validate:"required,min=1,dive,oneof=invoices:pay"`
+	CreatedBy uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
+// RevokeAPIKeyRequest defines the structure for revoking an API key.
+type RevokeAPIKeyRequest struct {
+	ID uuid.UUID `json:"-" validate:"required"` // From URL path
+}
+
+// APIKeyResponse defines the API key data returned to the client. It never includes the key.
+type APIKeyResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Owner     string     `json:"owner"`
+	Scopes    []string   `json:"scopes"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyResponse is returned once when a key is minted; the key can't be retrieved later.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// ContractorDashboardRequest defines the structure for getting the current user's contractor dashboard.
+type ContractorDashboardRequest struct {
+	UserID uuid.UUID `json:"-"` // From auth context; every section is scoped to this user
+}
+
+// UnpaidInvoiceTotalsResponse defines the totals of a contractor's unpaid invoices returned to the client.
+type UnpaidInvoiceTotalsResponse struct {
+	Count        int                `json:"count"`
+	Total        map[string]float64 `json:"total"`         // Keyed by ISO 4217 currency code
+	OverdueTotal map[string]float64 `json:"overdue_total"` // Keyed by ISO 4217 currency code
+}
+
+// ContractorDashboardResponse defines the contractor dashboard returned to the client.
+// When Partial is true the sections named in FailedSections are empty because they couldn't be loaded.
+type ContractorDashboardResponse struct {
+	ActiveJobs               []JobResponse               `json:"active_jobs"`
+	TotalActiveJobs          int                         `json:"total_active_jobs"`
+	PendingApplications      []JobApplicationResponse    `json:"pending_applications"`
+	TotalPendingApplications int                         `json:"total_pending_applications"`
+	UnpaidInvoices           UnpaidInvoiceTotalsResponse `json:"unpaid_invoices"`
+	Partial                  bool                        `json:"partial"`
+	FailedSections           []string                    `json:"failed_sections"` // active_jobs, pending_applications or unpaid_invoices
+}
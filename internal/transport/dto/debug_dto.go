@@ -0,0 +1,17 @@
+package dto
+
+// DBStatsResponse is a snapshot of the database connection pool, for diagnosing exhaustion.
+type DBStatsResponse struct {
+	MaxConns                int32 `json:"max_conns"`
+	TotalConns              int32 `json:"total_conns"`        // Acquired + idle + constructing
+	AcquiredConns           int32 `json:"acquired_conns"`     // Currently in use
+	IdleConns               int32 `json:"idle_conns"`
+	ConstructingConns       int32 `json:"constructing_conns"` // Being opened
+	AcquireCount            int64 `json:"acquire_count"`
+	AcquireDurationMs       int64 `json:"acquire_duration_ms"`  // Total time spent waiting for connections
+	EmptyAcquireCount       int64 `json:"empty_acquire_count"`  // Acquires that had to wait; a growing count means the pool is too small
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	NewConnsCount           int64 `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+// Machine-readable error codes returned in ErrorResponse.Code. Clients can rely on these staying
+// the same; messages are for humans and may change.
+const (
+	ErrorCodeBadRequest             = "bad_request"
+	ErrorCodeValidationFailed       = "validation_failed"
+	ErrorCodeUnauthorized           = "unauthorized"
+	ErrorCodeInvalidCredentials     = "invalid_credentials"
+	ErrorCodeInvalidToken           = "invalid_token"
+	ErrorCodeEmailNotVerified       = "email_not_verified"
+	ErrorCodeForbidden              = "forbidden"
+	ErrorCodeNotFound               = "not_found"
+	ErrorCodeConflict               = "conflict"
+	ErrorCodeInvalidState           = "invalid_state"
+	ErrorCodeInvalidTransition      = "invalid_transition"
+	ErrorCodeInvalidInvoiceInterval = "invalid_invoice_interval"
+	ErrorCodeInvalidAdjustment      = "invalid_adjustment"
+	ErrorCodeWeakPassword           = "weak_password"
+	ErrorCodeAccountLocked          = "account_locked"
+	ErrorCodeLimitExceeded          = "limit_exceeded"
+	ErrorCodePayloadTooLarge        = "payload_too_large"
+	ErrorCodeRateLimited            = "rate_limited"
+	ErrorCodeRequestCanceled        = "request_canceled"
+	ErrorCodeRequestTimeout         = "request_timeout"
+	ErrorCodeServiceUnavailable     = "service_unavailable"
+	ErrorCodeInternal               = "internal_error"
+)
+
+// ErrorResponse is the body of every error response the API sends.
+type ErrorResponse struct {
+	Code      string `json:"code" example:"not_found"`                                    // One of the ErrorCode constants
+	Message   string `json:"message" example:"Job not found"`                             // Human readable description
+	RequestID string `json:"request_id,omitempty" example:"5f0c6f1e-2f7a-4a43-9a52-0c1d"` // X-Request-ID of the failed request, for support
+	Details   any    `json:"details,omitempty" swaggertype:"object"`                      // Extra context, e.g. []FieldError for validation_failed
+}
+
+// ConflictDetails is the Details of a conflict error, telling clients whether sending the same
+// request again may succeed.
+type ConflictDetails struct {
+	Retryable         bool `json:"retryable"`                     // Lost a race with a concurrent request, rather than conflicting with stored data
+	RetryAfterSeconds int  `json:"retry_after_seconds,omitempty"` // Suggested wait before retrying; also sent as Retry-After
+}
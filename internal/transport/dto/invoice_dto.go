@@ -13,7 +13,8 @@ import (
 // JobID might come from the URL path or context.
 type CreateInvoiceRequest struct {
 	JobID          uuid.UUID `json:"job_id" validate:"required"`
-	Adjustment *float64  `json:"adjustment,omitempty" validate:"omitempty"`
+	Adjustment *float64  `json:"adjustment,omitempty" validate:"omitempty"` // Added to the base value; bounded by INVOICE_MAX_ADJUSTMENT_PERCENT
+	IntervalNumber *int `json:"interval_number,omitempty" validate:"omitempty,gt=0"` // Interval the client means to bill; a replayed request then conflicts instead of billing the next interval
 	UserId uuid.UUID `json:"-"`
 }
 
@@ -28,10 +29,20 @@ type ListInvoicesByJobRequest struct {
 	JobID  uuid.UUID            `json:"-" validate:"required"` // From URL path
 	Limit  int                  `form:"limit,default=10"`
 	Offset int                  `form:"offset,default=0"`
-	State  *models.InvoiceState `form:"state" validate:"omitempty,oneof=Waiting Complete"`
+	State  *models.InvoiceState `form:"state" validate:"omitempty,oneof=Waiting Complete Paid"`
+	MinInterval *int `form:"min_interval" validate:"omitempty,gte=1"` // Inclusive billing window; the service checks min <= max
+	MaxInterval *int `form:"max_interval" validate:"omitempty,gte=1"`
+	UpdatedSince *time.Time `form:"updated_since"` // RFC 3339; only invoices changed after it, oldest change first
+	ContractorID *uuid.UUID `form:"-"` // Set by the service: contractors only see the invoices they issued
 	UserId uuid.UUID `json:"-"`
 }
 
+// StreamJobInvoicesRequest asks to watch a job's invoice state changes.
+type StreamJobInvoicesRequest struct {
+	JobID  uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserID uuid.UUID `json:"-"`                     // Set from user context
+}
+
 // UpdateInvoiceStateRequest defines the structure for updating an invoice's state.
 // ID usually comes from the URL path.
 type UpdateInvoiceStateRequest struct {
@@ -40,6 +51,13 @@ type UpdateInvoiceStateRequest struct {
 	UserId uuid.UUID `json:"-"`
 }
 
+// MarkInvoicePaidRequest defines the structure for recording payment of a Complete invoice.
+type MarkInvoicePaidRequest struct {
+	ID        uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserId    uuid.UUID `json:"-"`
+	ByService bool      `json:"-"` // Set when a machine client with the invoices:pay scope records the payment
+}
+
 // DeleteInvoiceRequest defines the structure for deleting an invoice.
 type DeleteInvoiceRequest struct {
 	ID uuid.UUID `json:"-" validate:"required"`
@@ -51,14 +69,66 @@ type GetMaxIntervalForJobRequest struct {
 	JobID uuid.UUID `validate:"required"` // JobID is the input needed
 }
 
+// ListIntervalNumbersForJobRequest defines the structure for listing the intervals already invoiced for a job.
+type ListIntervalNumbersForJobRequest struct {
+	JobID uuid.UUID `validate:"required"`
+}
+
+// NextInvoiceSequenceRequest defines the structure for taking the next invoice number of an employer.
+type NextInvoiceSequenceRequest struct {
+	EmployerID uuid.UUID `validate:"required"`
+}
+
+// GenerateAllInvoicesRequest defines the structure for creating every outstanding invoice of a job.
+type GenerateAllInvoicesRequest struct {
+	JobID  uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserId uuid.UUID `json:"-"`
+}
+
+// ListEmployerInvoicesRequest defines the structure for listing the invoices on all of an employer's jobs.
+type ListEmployerInvoicesRequest struct {
+	EmployerID uuid.UUID            `json:"-"` // From auth context
+	Limit      int                  `form:"limit,default=10"`
+	Offset     int                  `form:"offset,default=0"`
+	State      *models.InvoiceState `form:"state" validate:"omitempty,oneof=Waiting Complete Paid"`
+}
+
+// ListOverdueInvoicesRequest defines the structure for listing the overdue invoices on an employer's jobs.
+type ListOverdueInvoicesRequest struct {
+	EmployerID uuid.UUID `json:"-"` // From auth context
+}
+
+// GetJobBalanceRequest defines the structure for getting the invoiced and paid totals of a job.
+type GetJobBalanceRequest struct {
+	JobID  uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserId uuid.UUID `json:"-"`
+}
+
 // InvoiceResponse defines the standard invoice data returned to the client.
 type InvoiceResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Value          float64   `json:"value"`
-	State          string    `json:"state"` // Return state as string
-	JobID          uuid.UUID `json:"job_id"`
-	IntervalNumber int       `json:"interval_number"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	InvoiceNumber  string     `json:"invoice_number"` // Sequential per employer, e.g. INV-000042-2025
+	Value          float64    `json:"value"`
+	Currency       string     `json:"currency"`        // ISO 4217 code, the job's currency
+	FormattedValue string     `json:"formatted_value"` // The value for display, e.g. "€1,250.00"
+	State          string     `json:"state"` // Return state as string
+	JobID          uuid.UUID  `json:"job_id"`
+	IntervalNumber int        `json:"interval_number"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	DueDate        time.Time  `json:"due_date"`
+	IsOverdue      bool       `json:"is_overdue"` // Complete but still unpaid after due_date
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"` // 00000000-0000-0000-0000-000000000001 for invoices the API created itself
+	UpdatedBy      *uuid.UUID `json:"updated_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
+// JobBalanceResponse defines the invoiced and paid totals of a job returned to the client.
+type JobBalanceResponse struct {
+	JobID         uuid.UUID `json:"job_id"`
+	TotalValue    float64   `json:"total_value"`
+	TotalInvoiced float64   `json:"total_invoiced"`
+	TotalPaid     float64   `json:"total_paid"`
+	Remaining     float64   `json:"remaining"`
+	Currency      string    `json:"currency"`
+}
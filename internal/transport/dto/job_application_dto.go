@@ -2,14 +2,18 @@ package dto
 
 import (
 	"go-api-template/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// CreateJobApplicationRequest is used internally by the ApplyToJob service method.
+// CreateJobApplicationRequest is used internally by the ApplyToJob and InviteContractor service methods.
 type CreateJobApplicationRequest struct {
 	JobID        uuid.UUID `json:"job_id"`      // Provided by the user
 	ContractorID uuid.UUID `json:"contractor_id"` // Set from user context
+	CoverMessage *string   `json:"cover_message,omitempty"`
+	ProposedRate *float64  `json:"proposed_rate,omitempty"`
+	State        models.JobApplicationState `json:"-"` // Waiting when empty; Invited for invitations
 }
 
 type JobApplicationResponse struct {
@@ -17,8 +21,16 @@ type JobApplicationResponse struct {
 	ContractorID uuid.UUID                `json:"contractor_id"`
 	JobID        uuid.UUID                `json:"job_id"`
 	State        models.JobApplicationState `json:"state"`
-	CreatedAt    string                   `json:"created_at"`
-	UpdatedAt    string                   `json:"updated_at"`
+	CoverMessage *string                  `json:"cover_message,omitempty"`
+	ProposedRate *float64                 `json:"proposed_rate,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+// AppliedJobResponse pairs a job the user applied to with their application.
+type AppliedJobResponse struct {
+	Job         JobResponse            `json:"job"`
+	Application JobApplicationResponse `json:"application"`
 }
 
 type GetJobApplicationByIDRequest struct {
@@ -28,9 +40,19 @@ type GetJobApplicationByIDRequest struct {
 
 // ListJobApplicationsByContractorRequest defines parameters for listing applications by contractor.
 type ListJobApplicationsByContractorRequest struct {
-	ContractorID uuid.UUID `json:"-" validate:"required"` // Set from user context
-	Limit        int       `form:"limit,default=10" validate:"omitempty,gte=0"`
-	Offset       int       `form:"offset,default=0" validate:"omitempty,gte=0"`
+	ContractorID uuid.UUID                  `json:"-" validate:"required"` // Set from user context
+	Limit        int                        `form:"limit,default=10" validate:"omitempty,gte=0"`
+	Offset       int                        `form:"offset,default=0" validate:"omitempty,gte=0"`
+	State        *models.JobApplicationState `form:"state" validate:"omitempty,oneof=Waiting Invited Accepted Rejected Withdrawn Declined"`
+	UpdatedSince *time.Time                 `form:"updated_since"` // RFC 3339; only applications changed after it, oldest change first
+}
+
+// ListAppliedJobsRequest defines parameters for listing the jobs a contractor applied to.
+type ListAppliedJobsRequest struct {
+	ContractorID uuid.UUID                   `json:"-" validate:"required"` // Set from user context
+	Limit        int                         `form:"limit,default=10" validate:"omitempty,gte=0"`
+	Offset       int                         `form:"offset,default=0" validate:"omitempty,gte=0"`
+	State        *models.JobApplicationState `form:"state" validate:"omitempty,oneof=Waiting Invited Accepted Rejected Withdrawn Declined"` // Filters on the application's state
 }
 
 // ListJobApplicationsByJobRequest defines parameters for listing applications by job.
@@ -39,6 +61,15 @@ type ListJobApplicationsByJobRequest struct {
 	UserID uuid.UUID `json:"-"`                          // Set from user context for auth check
 	Limit        int       `form:"limit,default=10" validate:"omitempty,gte=0"`
 	Offset       int       `form:"offset,default=0" validate:"omitempty,gte=0"`
+	State        *models.JobApplicationState `form:"state" validate:"omitempty,oneof=Waiting Invited Accepted Rejected Withdrawn Declined"`
+	UpdatedSince *time.Time `form:"updated_since"` // RFC 3339; only applications changed after it, oldest change first
+}
+
+// ExportJobApplicationsCSVRequest defines parameters for exporting a job's applications as CSV.
+type ExportJobApplicationsCSVRequest struct {
+	JobID  uuid.UUID `json:"-" validate:"required"` // From path
+	UserID uuid.UUID `json:"-"`                          // Set from user context for auth check
+	State  *models.JobApplicationState `form:"state" validate:"omitempty,oneof=Waiting Invited Accepted Rejected Withdrawn Declined"` // Same filter as the list endpoint
 }
 
 type UpdateJobApplicationStateRequest struct {
@@ -54,6 +85,8 @@ type DeleteJobApplicationRequest struct {
 type ApplyToJobRequest struct {
 	JobID        uuid.UUID `json:"job_id" validate:"required"` // Job ID to apply for (from request body or path)
 	ContractorID uuid.UUID `json:"-"`                               // Set from user context
+	CoverMessage *string   `json:"cover_message,omitempty" validate:"omitempty,max=2000"`
+	ProposedRate *float64  `json:"proposed_rate,omitempty" validate:"omitempty,gt=0"` // Counter-offer; becomes the job's rate if accepted
 }
 
 type AcceptApplicationRequest struct {
@@ -66,6 +99,48 @@ type RejectApplicationRequest struct {
 	UserID        uuid.UUID `json:"-"`                          // Set from user context (employer or applicant)
 }
 
+// BulkRejectRequest rejects several 'Waiting' applications at once; each is checked on its own.
+type BulkRejectRequest struct {
+	ApplicationIDs []uuid.UUID `json:"application_ids" validate:"required,min=1,max=100,dive,required"`
+	UserID         uuid.UUID   `json:"-"` // Set from user context (must be the employer of every job)
+}
+
+// BulkRejectResult is the outcome for one application of a BulkRejectRequest.
+type BulkRejectResult struct {
+	ApplicationID uuid.UUID
+	Application   *models.JobApplication // The rejected application; nil when Err is set
+	Err           error                  // Why it wasn't rejected, e.g. services.ErrForbidden
+}
+
+// BulkRejectItemResponse reports whether one application was rejected, and if not, why.
+type BulkRejectItemResponse struct {
+	ApplicationID uuid.UUID               `json:"application_id"`
+	Rejected      bool                    `json:"rejected"`
+	Application   *JobApplicationResponse `json:"application,omitempty"`
+	Error         *ErrorResponse          `json:"error,omitempty"` // Same code and message the single reject endpoint would answer with
+}
+
+// BulkRejectResponse lists the outcome for every requested application, in request order.
+type BulkRejectResponse struct {
+	Results  []BulkRejectItemResponse `json:"results"`
+	Rejected int                      `json:"rejected"`
+	Failed   int                      `json:"failed"`
+}
+
+// InviteContractorRequest invites a contractor to a job, as an application they only have to accept.
+type InviteContractorRequest struct {
+	JobID        uuid.UUID `json:"-"`                                               // From path
+	ContractorID uuid.UUID `json:"contractor_id" validate:"required"`               // The contractor being invited
+	Message      *string   `json:"message,omitempty" validate:"omitempty,max=2000"` // Shown to the contractor in place of a cover message
+	EmployerID   uuid.UUID `json:"-"`                                               // Set from user context (must be the job's employer)
+}
+
+// RespondToInvitationRequest is used to accept or decline an invitation.
+type RespondToInvitationRequest struct {
+	ApplicationID uuid.UUID `json:"-" validate:"required"` // From path
+	UserID        uuid.UUID `json:"-"`                     // Set from user context (must be the invited contractor)
+}
+
 type WithdrawApplicationRequest struct {
 	ApplicationID uuid.UUID `json:"-" validate:"required"` // From path
 	UserID        uuid.UUID `json:"-"`                          // Set from user context (must be applicant)
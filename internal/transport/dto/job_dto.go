@@ -13,8 +13,12 @@ import (
 // CreateJobRequest defines the structure for creating a new job posting.
 type CreateJobRequest struct {
 	Rate            float64 `json:"rate" validate:"required,gt=0"`              // Rate per hour, must be positive
+	Currency        string  `json:"currency,omitempty" validate:"omitempty,len=3"` // ISO 4217 code of the rate; defaults to the configured currency
 	Duration        int     `json:"duration" validate:"required,gt=0"`          // Duration in hours, must be positive
-	InvoiceInterval int     `json:"invoice_interval" validate:"required,gt=0"` // Interval in hours, must be positive
+	InvoiceInterval int     `json:"invoice_interval" validate:"required,gt=0,ltefield=Duration"` // Interval in hours, must be positive and fit in the duration
+	Description     string  `json:"description" validate:"omitempty,max=5000"`
+	ApplicationDeadline *time.Time `json:"application_deadline,omitempty"` // Must be in the future; applications close after it
+	Skills          []string `json:"skills,omitempty" validate:"omitempty,max=20,dive,required,max=50"` // Skill names; unknown skills are created
 	EmployerID      uuid.UUID `json:"-"` // Set internally by handler from auth context
 }
 
@@ -29,6 +33,14 @@ type ListAvailableJobsRequest struct {
 	Offset  int      `form:"offset,default=0"`
 	MinRate *float64 `form:"min_rate" validate:"omitempty,gt=0"` 
 	MaxRate *float64 `form:"max_rate" validate:"omitempty,gt=0,gtefield=MinRate"`
+	MinDuration *int `form:"min_duration" validate:"omitempty,gt=0"`                          // In hours
+	MaxDuration *int `form:"max_duration" validate:"omitempty,gt=0"`   // In hours; must not be below MinDuration
+	Search  *string  `form:"search" validate:"omitempty,max=200"` // Full-text match against the description
+	Skills      []string `form:"skills" validate:"omitempty,max=20,dive,required,max=50"`
+	SkillsMatch string   `form:"skills_match" validate:"omitempty,oneof=all any"` // Whether jobs need all of Skills (default) or any of them
+	SortBy    *string `form:"sort_by" validate:"omitempty,oneof=rate duration created_at"` // Defaults to created_at
+	SortOrder *string `form:"sort_order" validate:"omitempty,oneof=asc desc"`              // Defaults to desc
+	UpdatedSince *time.Time `form:"updated_since"` // RFC 3339; only jobs changed after it, oldest change first unless sort_by is set
 }
 
 // ListJobsByEmployerRequest defines parameters for listing jobs by employer.
@@ -36,9 +48,13 @@ type ListJobsByEmployerRequest struct {
 	EmployerID uuid.UUID        `json:"-" validate:"required"` // Set internally by handler
 	Limit      int              `form:"limit,default=10"`
 	Offset     int              `form:"offset,default=0"`
-	State      *models.JobState `form:"state" validate:"omitempty,oneof=Waiting Ongoing Complete Archived"` 
+	State      *models.JobState `form:"state" validate:"omitempty,oneof=Waiting Ongoing Complete Archived Cancelled"` 
+	States     []models.JobState `form:"states" validate:"omitempty,dive,oneof=Waiting Ongoing Complete Archived Cancelled"` // Any of these; empty means all states
 	MinRate    *float64         `form:"min_rate" validate:"omitempty,gt=0"`                         
 	MaxRate    *float64         `form:"max_rate" validate:"omitempty,gt=0,gtefield=MinRate"`        
+	SortBy     *string          `form:"sort_by" validate:"omitempty,oneof=rate duration created_at"` // Defaults to created_at
+	SortOrder  *string          `form:"sort_order" validate:"omitempty,oneof=asc desc"`              // Defaults to desc
+	UpdatedSince *time.Time `form:"updated_since"` // RFC 3339; only jobs changed after it, oldest change first unless sort_by is set
 }
 
 // ListJobsByContractorRequest defines parameters for listing jobs by contractor.
@@ -46,9 +62,13 @@ type ListJobsByContractorRequest struct {
 	ContractorID uuid.UUID        `json:"-" validate:"required"` // Set internally by handler
 	Limit        int              `form:"limit,default=10"`
 	Offset       int              `form:"offset,default=0"`
-	State        *models.JobState `form:"state" validate:"omitempty,oneof=Waiting Ongoing Complete Archived"` 
+	State        *models.JobState `form:"state" validate:"omitempty,oneof=Waiting Ongoing Complete Archived Cancelled"` 
+	States       []models.JobState `form:"states" validate:"omitempty,dive,oneof=Waiting Ongoing Complete Archived Cancelled"` // Any of these; empty means all states
 	MinRate      *float64         `form:"min_rate" validate:"omitempty,gt=0"`                         
 	MaxRate      *float64         `form:"max_rate" validate:"omitempty,gt=0,gtefield=MinRate"`        
+	SortBy       *string          `form:"sort_by" validate:"omitempty,oneof=rate duration created_at"` // Defaults to created_at
+	SortOrder    *string          `form:"sort_order" validate:"omitempty,oneof=asc desc"`              // Defaults to desc
+	UpdatedSince *time.Time `form:"updated_since"` // RFC 3339; only jobs changed after it, oldest change first unless sort_by is set
 }
 
 // UpdateJobRequest defines the structure for updating a job.
@@ -56,17 +76,35 @@ type ListJobsByContractorRequest struct {
 // This is a general example; refine based on allowed updates.
 type UpdateJobRequest struct {
 	ID           uuid.UUID        `json:"-" validate:"required"` // From URL path
+	Version      int              `json:"version" validate:"required,gt=0"` // The version last read; the update fails if the job has changed since
 	Rate         *float64         `json:"rate,omitempty" validate:"omitempty,gt=0"`
 	Duration     *int             `json:"duration,omitempty" validate:"omitempty,gt=0"`
 	ContractorID *uuid.UUID       `json:"contractor_id,omitempty" validate:"omitempty"` // For assigning/unassigning
-	State        *models.JobState `json:"state,omitempty" validate:"omitempty,oneof=Waiting Ongoing Complete Archived"`
+	UnassignContractor bool       `json:"-"` // Clears contractor_id; ContractorID is ignored when set
+	State        *models.JobState `json:"state,omitempty" validate:"omitempty,oneof=Waiting Ongoing Complete Archived Cancelled"`
+	Description  *string          `json:"description,omitempty" validate:"omitempty,max=5000"`
+	ApplicationDeadline *time.Time `json:"application_deadline,omitempty"`
+	Touch        bool             `json:"-"` // Bump version and updated_at even if no column changes, e.g. when only the skills did
+	UpdatedBy    uuid.UUID        `json:"-"` // The user making the change, or models.SystemActorID
 	// InvoiceInterval might not be updatable after creation
 }
 
+// AssignContractorRequest assigns a contractor to a job that is still waiting for one.
+type AssignContractorRequest struct {
+	JobID        uuid.UUID `validate:"required"`
+	ContractorID uuid.UUID `validate:"required"`
+	Rate         *float64  `validate:"omitempty,gt=0"` // Replaces the job's rate when set (an accepted counter-offer)
+	UpdatedBy    uuid.UUID // The user accepting the contractor
+}
+
 // UpdateJobDetailsRequest defines the structure for updating rate/duration.
 type UpdateJobDetailsRequest struct {
 	Rate     *float64 `json:"rate,omitempty" validate:"omitempty,gt=0"`
 	Duration *int     `json:"duration,omitempty" validate:"omitempty,gt=0"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+	ApplicationDeadline *time.Time `json:"application_deadline,omitempty"` // Must be in the future
+	Skills   []string `json:"skills,omitempty" validate:"omitempty,max=20,dive,required,max=50"` // Replaces the job's skills; omit to keep them, send [] to clear them
+	Version  int      `json:"version" validate:"required,gt=0"` // The version last read; the update fails with 409 if the job has changed since
 	JobID uuid.UUID `json:"-"` // Set internally by handler from auth context
 	UserID uuid.UUID `json:"-"` // Set internally by handler from auth context
 }
@@ -78,23 +116,184 @@ type UpdateJobStateRequest struct {
 	UserID uuid.UUID `json:"-"` // Set internally by handler from auth context
 }
 
+// ReopenJobRequest moves a Complete job back to Ongoing.
+type ReopenJobRequest struct {
+	JobID  uuid.UUID `json:"-"` // Set internally by handler from path
+	UserID uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
+// ReassignContractorRequest replaces the contractor of an Ongoing job, which stays Ongoing.
+type ReassignContractorRequest struct {
+	ContractorID uuid.UUID `json:"contractor_id" validate:"required"` // The contractor taking over
+	Reason       *string   `json:"reason,omitempty" validate:"omitempty,max=500"`
+	JobID        uuid.UUID `json:"-"` // Set internally by handler from path
+	UserID       uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
+// CancelJobRequest stops an Ongoing job, unassigning its contractor. HoursWorked bills the work done in
+// the interval not yet invoiced with a final, prorated invoice.
+type CancelJobRequest struct {
+	Reason      *string   `json:"reason,omitempty" validate:"omitempty,max=500"`
+	HoursWorked *int      `json:"hours_worked,omitempty" validate:"omitempty,gt=0"` // Omit to cancel without a final invoice
+	JobID       uuid.UUID `json:"-"` // Set internally by handler from path
+	UserID      uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
 // DeleteJobRequest defines the structure for deleting a job.
 type DeleteJobRequest struct {
 	ID uuid.UUID `json:"-" validate:"required"`
 	UserID uuid.UUID `json:"-"` // Set internally by handler from auth context
 }
 
+// TransferJobRequest moves a job to another employer account.
+type TransferJobRequest struct {
+	NewEmployerID uuid.UUID `json:"new_employer_id" validate:"required"`
+	JobID         uuid.UUID `json:"-"` // Set internally by handler from path
+	UserID        uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
+// RestoreJobRequest defines the structure for restoring a soft-deleted job (admin only).
+type RestoreJobRequest struct {
+	ID uuid.UUID `json:"-" validate:"required"`
+	UserID uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
+// SetJobAutoInvoiceRequest turns automatic periodic invoicing of a job on or off.
+type SetJobAutoInvoiceRequest struct {
+	JobID   uuid.UUID             `json:"-" validate:"required"` // From URL path
+	UserID  uuid.UUID             `json:"-"`                     // Set from user context
+	Enabled *bool                 `json:"enabled" validate:"required"`
+	Cadence models.InvoiceCadence `json:"cadence,omitempty" validate:"omitempty,oneof=daily weekly biweekly monthly"` // Keeps the job's cadence when omitted
+
+	NextAutoInvoiceAt *time.Time `json:"-"` // Set by the service; nil when disabled
+}
+
+// GenerateDueAutoInvoicesRequest defines which jobs the background invoicer creates invoices for.
+type GenerateDueAutoInvoicesRequest struct {
+	DueBefore time.Time `validate:"required"`      // Jobs whose next automatic invoice is due at or before this
+	Limit     int       `validate:"required,gt=0"` // Max jobs invoiced per call
+}
+
+// AdvanceAutoInvoiceRequest moves a job's next automatic invoice time, if it is still From.
+type AdvanceAutoInvoiceRequest struct {
+	JobID uuid.UUID
+	From  time.Time
+	To    *time.Time // nil stops automatic invoicing until it is enabled again
+	UpdatedBy uuid.UUID // models.SystemActorID when the scheduler advances it
+}
+
+// ArchiveStaleJobsRequest defines which completed jobs the background archiver moves to Archived.
+type ArchiveStaleJobsRequest struct {
+	CompletedBefore time.Time `validate:"required"`    // Complete jobs last updated before this are archived
+	Limit           int       `validate:"required,gt=0"` // Max jobs archived per call
+}
+
+// PurgeArchivedJobsRequest defines which archived jobs the background retention purge deletes for good.
+type PurgeArchivedJobsRequest struct {
+	ArchivedBefore time.Time `validate:"required"`      // Archived jobs last updated before this are purged
+	Limit          int       `validate:"required,gt=0"` // Max jobs purged per call
+}
+
+// PurgeArchivedJobsResult counts what one retention purge deleted.
+type PurgeArchivedJobsResult struct {
+	Jobs     int
+	Invoices int
+}
+
+
+// EmployerStatsRequest defines the structure for getting an employer's posting statistics.
+type EmployerStatsRequest struct {
+	EmployerID uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserID     uuid.UUID `json:"-"`                     // Set internally by handler from auth context
+}
+
+// SaveJobRequest defines the structure for bookmarking a job.
+type SaveJobRequest struct {
+	JobID  uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserID uuid.UUID `json:"-"`                     // Set internally by handler from auth context
+}
+
+// SetJobSkillsRequest defines the structure for replacing the skills attached to a job.
+type SetJobSkillsRequest struct {
+	JobID uuid.UUID
+	Names []string // Normalized by the repo as well, see models.NormalizeSkillName
+}
+
+// UnsaveJobRequest defines the structure for removing a job bookmark.
+type UnsaveJobRequest struct {
+	JobID  uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserID uuid.UUID `json:"-"`                     // Set internally by handler from auth context
+}
+
+// ListSavedJobsRequest defines parameters for listing the authenticated user's saved jobs.
+type ListSavedJobsRequest struct {
+	UserID uuid.UUID `json:"-" validate:"required"` // Set internally by handler
+	Limit  int       `form:"limit,default=10"`
+	Offset int       `form:"offset,default=0"`
+}
 
 // JobResponse defines the standard job data returned to the client.
 type JobResponse struct {
 	ID              uuid.UUID  `json:"id"`
 	Rate            float64    `json:"rate"`
+	Currency        string     `json:"currency"`       // ISO 4217 code of the rate and of the job's invoices
+	FormattedRate   string     `json:"formatted_rate"` // The rate for display, e.g. "$45.00"
 	Duration        int        `json:"duration"`
 	ContractorID    *uuid.UUID `json:"contractor_id,omitempty"`
 	EmployerID      uuid.UUID  `json:"employer_id"`
 	State           string     `json:"state"`
 	InvoiceInterval int        `json:"invoice_interval"`
+	Description     string     `json:"description"`
+	ApplicationDeadline *time.Time `json:"application_deadline,omitempty"`
+	AutoInvoice        bool       `json:"auto_invoice"`
+	AutoInvoiceCadence string     `json:"auto_invoice_cadence"` // daily, weekly, biweekly or monthly
+	NextAutoInvoiceAt  *time.Time `json:"next_auto_invoice_at,omitempty"`
+	Version         int        `json:"version"` // Send back when updating the job
+	Skills          []string   `json:"skills"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty"` // Omitted for jobs from before tracking
+	UpdatedBy       *uuid.UUID `json:"updated_by,omitempty"` // 00000000-0000-0000-0000-000000000001 when the API changed the job itself
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 	// Consider adding Employer/Contractor details (names/emails) if needed
 }
+
+// CancelJobResponse is the cancelled job and, if one was requested, its final prorated invoice.
+type CancelJobResponse struct {
+	Job          JobResponse      `json:"job"`
+	FinalInvoice *InvoiceResponse `json:"final_invoice,omitempty"`
+}
+
+// EmployerStatsResponse defines the posting statistics returned to an employer.
+type EmployerStatsResponse struct {
+	JobsByState   map[string]int     `json:"jobs_by_state"`
+	TotalJobs     int                `json:"total_jobs"`
+	AverageRate   map[string]float64 `json:"average_rate"`   // Keyed by ISO 4217 currency code
+	TotalInvoiced map[string]float64 `json:"total_invoiced"` // Keyed by ISO 4217 currency code
+}
+
+// JobStateTransitionResponse is one move allowed by the job state machine.
+type JobStateTransitionResponse struct {
+	From      models.JobState   `json:"from"`
+	To        models.JobState   `json:"to"`
+	AllowedBy []models.JobParty `json:"allowed_by"` // employer, contractor and/or system
+	Note      string            `json:"note,omitempty"`
+}
+
+// JobStateMachineResponse documents the job states and the transitions PATCH /jobs/{id}/state accepts.
+type JobStateMachineResponse struct {
+	States      []models.JobState            `json:"states"`
+	Transitions []JobStateTransitionResponse `json:"transitions"`
+}
+
+// JobDeleteBlockerResponse is one reason a job can't be deleted.
+type JobDeleteBlockerResponse struct {
+	Code    string `json:"code"` // invalid_state, contractor_assigned or invoices_exist
+	Message string `json:"message"`
+}
+
+// JobDeletableResponse reports whether DELETE /jobs/{id} would succeed, and if not, why.
+type JobDeletableResponse struct {
+	JobID     uuid.UUID                  `json:"job_id"`
+	Deletable bool                       `json:"deletable"`
+	Blockers  []JobDeleteBlockerResponse `json:"blockers"` // Empty when deletable
+}
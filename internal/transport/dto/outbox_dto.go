@@ -0,0 +1,11 @@
+package dto
+
+// ListUnpublishedOutboxEventsRequest defines the structure for fetching the next batch of events to publish.
+type ListUnpublishedOutboxEventsRequest struct {
+	Limit int `validate:"required,gt=0"`
+}
+
+// MarkOutboxEventPublishedRequest defines the structure for marking an event as published.
+type MarkOutboxEventPublishedRequest struct {
+	ID int64 `validate:"required"`
+}
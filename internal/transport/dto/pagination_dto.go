@@ -0,0 +1,41 @@
+package dto
+
+// PaginatedResponse wraps one page of a list endpoint with the information needed to render pagination.
+type PaginatedResponse[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`  // Number of rows matching the filters, ignoring limit/offset
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// NewPaginatedResponse builds a PaginatedResponse, normalizing nil items to an empty list.
+func NewPaginatedResponse[T any](items []T, total, limit, offset int) PaginatedResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return PaginatedResponse[T]{Items: items, Total: total, Limit: limit, Offset: offset}
+}
+
+const (
+	DefaultPageLimit    = 10  // Limit used when a list request doesn't set one
+	DefaultMaxPageLimit = 100 // Largest limit accepted unless configured otherwise
+)
+
+// ClampPagination normalizes a list request's limit and offset before it reaches the repositories:
+// a limit below 1 becomes DefaultPageLimit, one above maxLimit is lowered to maxLimit, and a negative
+// offset becomes 0. A maxLimit below 1 means DefaultMaxPageLimit.
+func ClampPagination(limit, offset, maxLimit int) (int, int) {
+	if maxLimit < 1 {
+		maxLimit = DefaultMaxPageLimit
+	}
+	if limit < 1 {
+		limit = DefaultPageLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClampPagination(t *testing.T) {
+	tests := []struct {
+		name           string
+		limit          int
+		offset         int
+		maxLimit       int
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{name: "Unchanged", limit: 20, offset: 40, maxLimit: 100, expectedLimit: 20, expectedOffset: 40},
+		{name: "ZeroLimitDefaults", limit: 0, offset: 0, maxLimit: 100, expectedLimit: DefaultPageLimit, expectedOffset: 0},
+		{name: "NegativeLimitDefaults", limit: -5, offset: 0, maxLimit: 100, expectedLimit: DefaultPageLimit, expectedOffset: 0},
+		{name: "LimitOne", limit: 1, offset: 0, maxLimit: 100, expectedLimit: 1, expectedOffset: 0},
+		{name: "LimitAtMax", limit: 100, offset: 0, maxLimit: 100, expectedLimit: 100, expectedOffset: 0},
+		{name: "LimitAboveMaxClamped", limit: 101, offset: 0, maxLimit: 100, expectedLimit: 100, expectedOffset: 0},
+		{name: "HugeLimitClamped", limit: 1 << 30, offset: 0, maxLimit: 50, expectedLimit: 50, expectedOffset: 0},
+		{name: "DefaultAboveSmallMax", limit: 0, offset: 0, maxLimit: 5, expectedLimit: 5, expectedOffset: 0},
+		{name: "UnsetMaxUsesDefault", limit: 500, offset: 0, maxLimit: 0, expectedLimit: DefaultMaxPageLimit, expectedOffset: 0},
+		{name: "NegativeOffsetFloored", limit: 10, offset: -1, maxLimit: 100, expectedLimit: 10, expectedOffset: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset := ClampPagination(tt.limit, tt.offset, tt.maxLimit)
+			assert.Equal(t, tt.expectedLimit, limit)
+			assert.Equal(t, tt.expectedOffset, offset)
+		})
+	}
+}
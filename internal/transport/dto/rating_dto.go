@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubmitRatingRequest defines the structure for rating the contractor of a completed job.
+type SubmitRatingRequest struct {
+	JobID   uuid.UUID `json:"-" validate:"required"` // From URL path
+	UserID  uuid.UUID `json:"-"`                     // Set internally by handler from auth context
+	Score   int       `json:"score" validate:"required,min=1,max=5"`
+	Comment *string   `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// GetUserRatingRequest defines the structure for getting a user's average rating.
+type GetUserRatingRequest struct {
+	UserID uuid.UUID `json:"-" validate:"required"` // From URL path
+}
+
+// RatingResponse defines the rating data returned to the client.
+type RatingResponse struct {
+	ID        uuid.UUID `json:"id"`
+	JobID     uuid.UUID `json:"job_id"`
+	RaterID   uuid.UUID `json:"rater_id"`
+	RateeID   uuid.UUID `json:"ratee_id"`
+	Score     int       `json:"score"`
+	Comment   *string   `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserRatingResponse defines a user's average rating returned to the client.
+type UserRatingResponse struct {
+	UserID       uuid.UUID `json:"user_id"`
+	AverageScore float64   `json:"average_score"`
+	RatingCount  int       `json:"rating_count"`
+}
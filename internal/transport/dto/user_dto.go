@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"go-api-template/internal/models"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +12,18 @@ type GetUserByIdRequest struct {
 	ID        uuid.UUID    `json:"id" validate:"required"` 
 }
 
+// ListUsersRequest defines parameters for listing users.
+type ListUsersRequest struct {
+	Limit  int     `form:"limit,default=10"`
+	Offset int     `form:"offset,default=0"`
+	Search *string `form:"search" validate:"omitempty,max=200"` // Case-insensitive match anywhere in the name or email
+}
+
+// BatchGetUsersRequest defines the body for looking up several users at once.
+type BatchGetUsersRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1"` // The handler caps how many may be sent
+}
+
 // GetUserByEmailRequest defines the structure for getting a user by email.
 type GetUserByEmailRequest struct {
 	Email        string   `json:"email" validate:"required,email"` 
@@ -20,14 +33,36 @@ type GetUserByEmailRequest struct {
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Name string `json:"name" validate:"omitempty,max=100"`     // Optional field
-	Password string `json:"password" validate:"required,min=8"` // Required field
+	Password string `json:"password" validate:"required"` // Required field; strength is checked against the configured policy
+	ReturnExisting bool `json:"return_existing"` // If the email is taken and the password matches, return that user instead of 409; only honored when AUTH_IDEMPOTENT_REGISTRATION is on
 }
 
 // UpdateUserRequest defines the structure for updating an existing user.
 type UpdateUserRequest struct {
-	// Email    *string `json:"email" validate:"omitempty,email"` 
+	Email    *string `json:"email" validate:"omitempty,email"` // Changing it marks the user unverified until the new address is confirmed
 	Name *string `json:"name" validate:"omitempty,max=100"`
 	ID        uuid.UUID    `json:"id" validate:"required"`
+	PasswordHash *string `json:"-" validate:"-"` // Set internally (e.g. password reset), never bound from the client
+	Verified     *bool   `json:"-" validate:"-"` // Set internally by email verification
+	Role         *models.UserRole `json:"-" validate:"-"` // Set internally, users can't change their own role
+}
+
+// SetUserAvatarRequest points a user's avatar at an uploaded picture, or clears it.
+type SetUserAvatarRequest struct {
+	ID        uuid.UUID `json:"-" validate:"required"`
+	AvatarURL *string   `json:"-"` // nil removes the avatar
+}
+
+// UploadAvatarRequest carries a profile picture uploaded by the user.
+type UploadAvatarRequest struct {
+	UserID      uuid.UUID `json:"-"` // Set from user context
+	ContentType string    `json:"-"` // Sniffed from the data, not taken from the client
+	Data        []byte    `json:"-"`
+}
+
+// DeleteAvatarRequest removes the user's profile picture.
+type DeleteAvatarRequest struct {
+	UserID uuid.UUID `json:"-"` // Set from user context
 }
 
 // DeleteUserRequest defines the structure for deleting a user.
@@ -35,6 +70,24 @@ type DeleteUserRequest struct {
 	ID        uuid.UUID    `json:"id" validate:"required"` 
 }
 
+// RestoreUserRequest defines the structure for restoring a soft-deleted user (admin only).
+type RestoreUserRequest struct {
+	ID        uuid.UUID    `json:"id" validate:"required"` 
+}
+
+// PermissionsResponse defines what the current user may do: every capability, keyed by name (e.g.
+// "can_create_job"), with whether it is granted.
+type PermissionsResponse struct {
+	Role         string          `json:"role"`
+	Capabilities map[string]bool `json:"capabilities"`
+}
+
+// ImpersonateUserRequest defines the structure for an admin minting a token to act as another user.
+type ImpersonateUserRequest struct {
+	ID      uuid.UUID `json:"-" validate:"required"` // The user to act as; from URL path
+	AdminID uuid.UUID `json:"-" validate:"required"` // The admin asking; from auth context
+}
+
 // LoginRequest defines the structure for the login request body.
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -46,10 +99,20 @@ type UserResponse struct {
 	ID        uuid.UUID `json:"id"` // Use uuid.UUID to match your model
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Verified  bool      `json:"verified"`
+	Role      string    `json:"role"`
+	AvatarURL *string   `json:"avatar_url,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// PublicUserResponse is the view of a user given to requesters with no job in progress with them.
+type PublicUserResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AvatarURL *string   `json:"avatar_url,omitempty"`
+}
+
 // LoginResponse defines the data returned after successful login.
 type LoginResponse struct {
 	User  UserResponse `json:"user"`
@@ -57,6 +120,14 @@ type LoginResponse struct {
 	RefreshToken string `json:"refreshToken"` // Refresh Token
 }
 
+// ImpersonationResponse defines the data returned when an admin starts impersonating a user. There is
+// no refresh token: once the access token expires, the admin has to ask for a new one.
+type ImpersonationResponse struct {
+	User      UserResponse `json:"user"` // The impersonated user
+	Token     string       `json:"accessToken"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+}
+
 // RefreshRequest defines the structure for requesting a new access token.
 type RefreshRequest struct {
 	RefreshToken string `json:"refreshToken" binding:"required"`
@@ -64,5 +135,35 @@ type RefreshRequest struct {
 
 // LogoutRequest defines the structure for requesting logout.
 type LogoutRequest struct {
-	RefreshToken string `json:"refreshToken" binding:"required"`
-}
\ No newline at end of file
+	RefreshToken         string    `json:"refreshToken" binding:"required"`
+	AccessTokenID        string    `json:"-"` // jti of the access token the request was made with, if any; revoked too
+	AccessTokenExpiresAt time.Time `json:"-"` // exp of that access token
+}
+
+// PasswordResetRequest defines the structure for requesting a password reset email.
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmPasswordResetRequest defines the structure for setting a new password with a reset token.
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required"` // Strength is checked against the configured policy
+}
+
+// ChangePasswordRequest defines the structure for an authenticated user changing their own password.
+type ChangePasswordRequest struct {
+	ID              uuid.UUID `json:"-" validate:"required"` // From URL path
+	CurrentPassword string    `json:"currentPassword" validate:"required"`
+	NewPassword     string    `json:"newPassword" validate:"required"` // Strength is checked against the configured policy
+}
+
+// SendVerificationEmailRequest defines the structure for (re)sending an email verification token.
+type SendVerificationEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// VerifyEmailRequest defines the structure for confirming an email address with a verification token.
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
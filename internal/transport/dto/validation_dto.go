@@ -0,0 +1,8 @@
+package dto
+
+// FieldError describes one failed validation rule.
+type FieldError struct {
+	Field   string `json:"field"`   // Validator namespace without the request struct name, e.g. "Email" or "Items[0].Name"
+	Tag     string `json:"tag"`     // The validation tag that failed, e.g. "required"
+	Message string `json:"message"` // Human readable description
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhookRequest defines the structure for registering a webhook for an external integration.
+type CreateWebhookRequest struct {
+	Owner      string    `json:"owner" validate:"required,max=100"`
+	URL        string    `json:"url" validate:"required,url,max=2048"` // Must be http or https
+	EventTypes []string  `json:"event_types" validate:"required,min=1,dive,oneof=job.created job.updated job.state_changed job.deleted invoice.created invoice.state_changed invoice.deleted"`
+	CreatedBy  uuid.UUID `json:"-"` // Set internally by handler from auth context
+}
+
+// UpdateWebhookRequest defines the structure for pausing or resuming a webhook.
+type UpdateWebhookRequest struct {
+	ID     uuid.UUID `json:"-" validate:"required"` // From URL path
+	Active *bool     `json:"active" validate:"required"`
+}
+
+// DeleteWebhookRequest defines the structure for deleting a webhook.
+type DeleteWebhookRequest struct {
+	ID uuid.UUID `json:"-" validate:"required"` // From URL path
+}
+
+// ListWebhookDeliveriesRequest defines parameters for listing a webhook's deliveries, newest first.
+type ListWebhookDeliveriesRequest struct {
+	WebhookID uuid.UUID `json:"-" validate:"required"` // From URL path
+	Status    *string   `form:"status" validate:"omitempty,oneof=pending delivered failed"`
+	Limit     int       `form:"limit,default=10"`
+	Offset    int       `form:"offset,default=0"`
+}
+
+// ClaimWebhookDeliveriesRequest defines the structure for taking the next due deliveries to attempt.
+type ClaimWebhookDeliveriesRequest struct {
+	Limit int           `validate:"required,gt=0"`
+	Lease time.Duration `validate:"required,gt=0"` // Claimed deliveries aren't handed out again for this long
+}
+
+// RecordWebhookAttemptRequest defines the structure for storing the outcome of a delivery attempt.
+type RecordWebhookAttemptRequest struct {
+	ID            int64     `validate:"required"`
+	Status        string    `validate:"required,oneof=pending delivered failed"`
+	StatusCode    *int      // nil if no response came back
+	Error         *string   // nil once delivered
+	NextAttemptAt time.Time // Only used while the delivery stays pending
+}
+
+// WebhookResponse defines the webhook data returned to the client. It never includes the secret.
+type WebhookResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Owner      string     `json:"owner"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	Active     bool       `json:"active"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CreateWebhookResponse is returned once when a webhook is registered; the secret can't be retrieved later.
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryResponse defines the delivery data returned to the client.
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // Only set while pending
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,68 @@
+// Package webhooks delivers domain events to the HTTP endpoints external integrations registered.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api-template/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Headers sent with every delivery.
+const (
+	SignatureHeader = "X-Signature"        // "sha256=" followed by the hex HMAC-SHA256 of the body
+	EventHeader     = "X-Webhook-Event"    // The event type, e.g. invoice.state_changed
+	DeliveryHeader  = "X-Webhook-Delivery" // The delivery ID; repeated on every attempt of the delivery
+	signaturePrefix = "sha256="
+)
+
+// Payload is the JSON body POSTed for an event. ID is the same for every webhook and attempt, so
+// receivers can use it to drop repeats.
+type Payload struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"` // The job or invoice as it was after the change
+}
+
+// NewPayload builds the body delivered for event.
+func NewPayload(event models.OutboxEvent) ([]byte, error) {
+	body, err := json.Marshal(Payload{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		CreatedAt:     event.CreatedAt,
+		Data:          event.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize webhook payload for event %d: %w", event.ID, err)
+	}
+	return body, nil
+}
+
+// Sign returns the X-Signature value for body: the HMAC-SHA256 of the body keyed with the
+// webhook's secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the X-Signature of body under secret, comparing in constant
+// time. Receivers written in Go can use it as is.
+func Verify(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// Same HMAC-SHA256 as any other implementation, so receivers can check it with their own tools
+	assert.Equal(t,
+		"sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		Sign("It's a Secret to Everybody", []byte("Hello, World!")))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":1,"type":"invoice.state_changed"}`)
+	signature := Sign("whsec_secret", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		expected  bool
+	}{
+		{name: "Valid", secret: "whsec_secret", body: body, signature: signature, expected: true},
+		{name: "TamperedBody", secret: "whsec_secret", body: []byte(`{"id":2,"type":"invoice.state_changed"}`), signature: signature},
+		{name: "WrongSecret", secret: "whsec_other", body: body, signature: signature},
+		{name: "MissingPrefix", secret: "whsec_secret", body: body, signature: signature[len(signaturePrefix):]},
+		{name: "Empty", secret: "whsec_secret", body: body, signature: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Verify(tt.secret, tt.body, tt.signature))
+		})
+	}
+}
+
+func TestNewPayload(t *testing.T) {
+	event := models.OutboxEvent{
+		ID:            42,
+		AggregateType: models.OutboxAggregateInvoice,
+		AggregateID:   uuid.New(),
+		EventType:     models.OutboxEventInvoiceStateChanged,
+		Payload:       json.RawMessage(`{"state":"Paid"}`),
+		CreatedAt:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	body, err := NewPayload(event)
+	require.NoError(t, err)
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, int64(42), payload.ID)
+	assert.Equal(t, models.OutboxEventInvoiceStateChanged, payload.Type)
+	assert.Equal(t, event.AggregateID, payload.AggregateID)
+	assert.True(t, event.CreatedAt.Equal(payload.CreatedAt))
+	assert.JSONEq(t, `{"state":"Paid"}`, string(payload.Data))
+}
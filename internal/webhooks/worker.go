@@ -0,0 +1,184 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+)
+
+// Defaults for the wait between attempts of a delivery.
+const (
+	DefaultBackoffBase = 30 * time.Second // Before the second attempt
+	DefaultBackoffMax  = time.Hour
+)
+
+// Store is the subset of storage.WebhookRepository the worker needs.
+type Store interface {
+	ClaimDueDeliveries(ctx context.Context, req *dto.ClaimWebhookDeliveriesRequest) ([]models.ClaimedWebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, req *dto.RecordWebhookAttemptRequest) error
+}
+
+// Worker polls for due webhook deliveries and POSTs them, signed with their webhook's secret.
+//
+// A delivery succeeds when the endpoint answers 2xx. Otherwise it is retried with exponential backoff
+// until maxAttempts attempts have been made, after which it is marked failed. Deliveries are claimed
+// for a lease longer than the request timeout, so several instances can run workers without sending
+// the same delivery at the same time.
+type Worker struct {
+	store       Store
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	lease       time.Duration
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	logger      *slog.Logger
+	now         func() time.Time // Replaced in tests
+}
+
+// NewWorker creates a new Worker that checks for due deliveries every interval, up to batchSize at a
+// time, giving each endpoint timeout to answer.
+func NewWorker(store Store, interval time.Duration, batchSize, maxAttempts int, timeout time.Duration, logger *slog.Logger) *Worker {
+	return &Worker{
+		store:       store,
+		client:      &http.Client{Timeout: timeout},
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		lease:       2 * timeout, // Outlasts the request, so a claimed delivery is never sent twice at once
+		backoffBase: DefaultBackoffBase,
+		backoffMax:  DefaultBackoffMax,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Run polls until ctx is cancelled. A full batch is followed immediately by another poll, so a
+// backlog drains without waiting for the interval.
+func (w *Worker) Run(ctx context.Context) {
+	w.logger.Info("Webhook worker started", "interval", w.interval, "batch_size", w.batchSize, "max_attempts", w.maxAttempts)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		attempted, err := w.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.Error("Webhook worker: Failed to process batch", "error", err)
+		}
+		if err == nil && attempted == w.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Webhook worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch attempts the next batch of due deliveries and returns how many were attempted,
+// successfully or not. A failure to record an outcome is logged; the delivery is attempted again
+// once its lease runs out.
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	claimed, err := w.store.ClaimDueDeliveries(ctx, &dto.ClaimWebhookDeliveriesRequest{Limit: w.batchSize, Lease: w.lease})
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for _, c := range claimed {
+		if ctx.Err() != nil {
+			return attempted, ctx.Err() // The rest are attempted again once their lease runs out
+		}
+		outcome := w.attempt(ctx, c)
+		if err := w.store.RecordDeliveryAttempt(ctx, outcome); err != nil {
+			w.logger.Error("Webhook worker: Failed to record delivery attempt", "delivery_id", c.Delivery.ID, "webhook_id", c.Delivery.WebhookID, "error", err)
+		}
+		attempted++
+	}
+	return attempted, nil
+}
+
+// attempt POSTs the delivery and works out what to record: delivered, pending with the time of the
+// next attempt, or failed once it has run out of attempts.
+func (w *Worker) attempt(ctx context.Context, c models.ClaimedWebhookDelivery) *dto.RecordWebhookAttemptRequest {
+	d := c.Delivery
+	outcome := &dto.RecordWebhookAttemptRequest{ID: d.ID}
+
+	statusCode, err := w.post(ctx, c)
+	if statusCode != 0 {
+		outcome.StatusCode = &statusCode
+	}
+	if err == nil {
+		outcome.Status = models.WebhookDeliveryDelivered
+		return outcome
+	}
+
+	message := err.Error()
+	outcome.Error = &message
+	attempts := d.Attempts + 1
+	if attempts >= w.maxAttempts {
+		outcome.Status = models.WebhookDeliveryFailed
+		w.logger.Warn("Webhook delivery failed, giving up", "delivery_id", d.ID, "webhook_id", d.WebhookID, "event_id", d.EventID, "attempts", attempts, "error", err)
+		return outcome
+	}
+	outcome.Status = models.WebhookDeliveryPending
+	outcome.NextAttemptAt = w.now().Add(w.retryDelay(attempts))
+	w.logger.Info("Webhook delivery failed, will retry", "delivery_id", d.ID, "webhook_id", d.WebhookID, "event_id", d.EventID, "attempts", attempts, "next_attempt_at", outcome.NextAttemptAt, "error", err)
+	return outcome
+}
+
+// post sends the delivery and returns the response status, or 0 if no response came back. Any
+// status other than 2xx is an error.
+func (w *Worker) post(ctx context.Context, c models.ClaimedWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(c.Delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-api-template-webhooks")
+	req.Header.Set(SignatureHeader, Sign(c.Secret, c.Delivery.Payload))
+	req.Header.Set(EventHeader, c.Delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(c.Delivery.ID, 10))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Lets the connection be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay returns the wait after the given number of failed attempts: exponential from
+// backoffBase up to backoffMax, with jitter so deliveries that failed together don't all retry at
+// the same moment.
+func (w *Worker) retryDelay(attempts int) time.Duration {
+	delay := w.backoffMax
+	if shift := attempts - 1; shift < 32 {
+		if grown := w.backoffBase << shift; grown > 0 { // Guards against overflow
+			delay = min(grown, w.backoffMax)
+		}
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1) // Somewhere in [delay/2, delay]
+}
@@ -0,0 +1,251 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/models"
+	"go-api-template/internal/transport/dto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store that hands out pending deliveries due at the worker's clock;
+// recordErr makes RecordDeliveryAttempt fail.
+type memoryStore struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	url        string
+	secret     string
+	deliveries map[int64]*models.WebhookDelivery
+	recorded   []dto.RecordWebhookAttemptRequest
+	recordErr  error
+}
+
+func newMemoryStore(now func() time.Time, url, secret string, deliveries ...models.WebhookDelivery) *memoryStore {
+	s := &memoryStore{now: now, url: url, secret: secret, deliveries: map[int64]*models.WebhookDelivery{}}
+	for i := range deliveries {
+		s.deliveries[deliveries[i].ID] = &deliveries[i]
+	}
+	return s
+}
+
+func (s *memoryStore) ClaimDueDeliveries(ctx context.Context, req *dto.ClaimWebhookDeliveriesRequest) ([]models.ClaimedWebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed []models.ClaimedWebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(s.now()) && len(claimed) < req.Limit {
+			d.NextAttemptAt = s.now().Add(req.Lease)
+			claimed = append(claimed, models.ClaimedWebhookDelivery{Delivery: *d, URL: s.url, Secret: s.secret})
+		}
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) RecordDeliveryAttempt(ctx context.Context, req *dto.RecordWebhookAttemptRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.recordErr != nil {
+		return s.recordErr
+	}
+	s.recorded = append(s.recorded, *req)
+	d := s.deliveries[req.ID]
+	d.Status = req.Status
+	d.Attempts++
+	d.LastStatusCode = req.StatusCode
+	d.LastError = req.Error
+	if req.Status == models.WebhookDeliveryPending {
+		d.NextAttemptAt = req.NextAttemptAt
+	}
+	return nil
+}
+
+func (s *memoryStore) delivery(id int64) models.WebhookDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.deliveries[id]
+}
+
+// flakyEndpoint answers 503 to the first failures requests and 204 after that, recording whether
+// each request carried a valid signature.
+type flakyEndpoint struct {
+	mu         sync.Mutex
+	secret     string
+	failures   int
+	requests   int
+	signedOK   []bool
+	deliveries []string
+}
+
+func (e *flakyEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests++
+	e.signedOK = append(e.signedOK, Verify(e.secret, body, r.Header.Get(SignatureHeader)))
+	e.deliveries = append(e.deliveries, r.Header.Get(DeliveryHeader))
+	if e.requests <= e.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestWorker(store Store, maxAttempts int, clock *time.Time) *Worker {
+	worker := NewWorker(store, time.Second, 10, maxAttempts, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	worker.backoffBase = time.Minute
+	worker.backoffMax = 10 * time.Minute
+	worker.now = func() time.Time { return *clock }
+	return worker
+}
+
+func pendingDelivery(id int64) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:        id,
+		WebhookID: uuid.New(),
+		EventID:   id * 10,
+		EventType: models.OutboxEventInvoiceStateChanged,
+		Payload:   []byte(`{"id":1,"type":"invoice.state_changed","data":{"state":"Paid"}}`),
+		Status:    models.WebhookDeliveryPending,
+	}
+}
+
+func TestWorker_RetriesFailingEndpointUntilDelivered(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpoint := &flakyEndpoint{secret: "whsec_test", failures: 2}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+	store := newMemoryStore(func() time.Time { return clock }, server.URL, "whsec_test", pendingDelivery(1))
+	worker := newTestWorker(store, 5, &clock)
+
+	// First attempt fails and is put off by the first backoff step
+	attempted, err := worker.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	first := store.delivery(1)
+	assert.Equal(t, models.WebhookDeliveryPending, first.Status)
+	assert.Equal(t, 1, first.Attempts)
+	require.NotNil(t, first.LastStatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, *first.LastStatusCode)
+	require.NotNil(t, first.LastError)
+	assert.Contains(t, *first.LastError, "503")
+	firstDelay := first.NextAttemptAt.Sub(clock)
+	assert.GreaterOrEqual(t, firstDelay, 30*time.Second)
+	assert.LessOrEqual(t, firstDelay, time.Minute)
+
+	// Nothing is due until the backoff has passed
+	attempted, err = worker.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted)
+
+	// The second attempt fails too and waits about twice as long
+	clock = first.NextAttemptAt
+	_, err = worker.ProcessBatch(ctx)
+	require.NoError(t, err)
+	second := store.delivery(1)
+	assert.Equal(t, 2, second.Attempts)
+	secondDelay := second.NextAttemptAt.Sub(clock)
+	assert.GreaterOrEqual(t, secondDelay, time.Minute)
+	assert.LessOrEqual(t, secondDelay, 2*time.Minute)
+
+	// The third goes through
+	clock = second.NextAttemptAt
+	_, err = worker.ProcessBatch(ctx)
+	require.NoError(t, err)
+	delivered := store.delivery(1)
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivered.Status)
+	assert.Equal(t, 3, delivered.Attempts)
+	require.NotNil(t, delivered.LastStatusCode)
+	assert.Equal(t, http.StatusNoContent, *delivered.LastStatusCode)
+	assert.Nil(t, delivered.LastError)
+
+	assert.Equal(t, 3, endpoint.requests)
+	assert.Equal(t, []bool{true, true, true}, endpoint.signedOK, "Every attempt must be signed with the webhook's secret")
+	assert.Equal(t, []string{"1", "1", "1"}, endpoint.deliveries, "Retries keep the delivery ID")
+}
+
+func TestWorker_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpoint := &flakyEndpoint{secret: "whsec_test", failures: 100}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+	store := newMemoryStore(func() time.Time { return clock }, server.URL, "whsec_test", pendingDelivery(1))
+	worker := newTestWorker(store, 3, &clock)
+
+	for i := 0; i < 5; i++ {
+		_, err := worker.ProcessBatch(ctx)
+		require.NoError(t, err)
+		clock = clock.Add(time.Hour) // Past any backoff
+	}
+
+	failed := store.delivery(1)
+	assert.Equal(t, models.WebhookDeliveryFailed, failed.Status)
+	assert.Equal(t, 3, failed.Attempts)
+	assert.Equal(t, 3, endpoint.requests, "No attempts are made once the delivery failed")
+}
+
+func TestWorker_UnreachableEndpoint(t *testing.T) {
+	clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close() // Nothing listens there any more
+	store := newMemoryStore(func() time.Time { return clock }, url, "whsec_test", pendingDelivery(1))
+	worker := newTestWorker(store, 5, &clock)
+
+	_, err := worker.ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	d := store.delivery(1)
+	assert.Equal(t, models.WebhookDeliveryPending, d.Status)
+	assert.Nil(t, d.LastStatusCode, "No response, so no status code")
+	require.NotNil(t, d.LastError)
+}
+
+func TestWorker_RecordFailureIsLogged(t *testing.T) {
+	clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(&flakyEndpoint{secret: "whsec_test"})
+	defer server.Close()
+	store := newMemoryStore(func() time.Time { return clock }, server.URL, "whsec_test", pendingDelivery(1), pendingDelivery(2))
+	store.recordErr = errors.New("database unavailable")
+	worker := newTestWorker(store, 5, &clock)
+
+	attempted, err := worker.ProcessBatch(context.Background())
+
+	require.NoError(t, err, "One delivery's bookkeeping must not fail the batch")
+	assert.Equal(t, 2, attempted)
+}
+
+func TestWorker_RetryDelay(t *testing.T) {
+	clock := time.Now()
+	worker := newTestWorker(nil, 5, &clock)
+
+	tests := []struct {
+		attempts int
+		min, max time.Duration
+	}{
+		{attempts: 1, min: 30 * time.Second, max: time.Minute},
+		{attempts: 2, min: time.Minute, max: 2 * time.Minute},
+		{attempts: 4, min: 4 * time.Minute, max: 8 * time.Minute},
+		{attempts: 5, min: 5 * time.Minute, max: 10 * time.Minute}, // Capped at backoffMax
+		{attempts: 100, min: 5 * time.Minute, max: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := worker.retryDelay(tt.attempts)
+			assert.GreaterOrEqual(t, delay, tt.min, "attempts=%d", tt.attempts)
+			assert.LessOrEqual(t, delay, tt.max, "attempts=%d", tt.attempts)
+		}
+	}
+}
@@ -5,19 +5,34 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go-api-template/config"
 	"go-api-template/internal/app"
 	"go-api-template/internal/blockchain"
 	"go-api-template/internal/database"
+	"go-api-template/internal/flags"
+	"go-api-template/internal/mailer"
+	"go-api-template/internal/notifications"
+	"go-api-template/internal/outbox"
+	"go-api-template/internal/scheduler"
 	"go-api-template/internal/server"
+	"go-api-template/internal/services"
+	"go-api-template/internal/storage/cache"
+	"go-api-template/internal/storage/postgres"
+	"go-api-template/internal/webhooks"
+	"go-api-template/pkg/lock"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tracing"
 
 	_ "go-api-template/docs" // Import generated docs (will be created by swag init)
 
 	"github.com/go-playground/validator"
+	"github.com/redis/go-redis/v9"
 )
 
 // @title           Go API Template API
@@ -40,40 +55,169 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key for machine clients, created by an admin under /admin/api-keys.
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// --- Initialize Structured Logger ---
+	appLogger := logger.New(cfg.Log.Level, cfg.Log.Format)
+	slog.SetDefault(appLogger)
+
+	// --- Initialize Tracing ---
+	// Installed before the clients below are created; without an endpoint every span is a no-op
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		ServiceName:  cfg.Tracing.ServiceName,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		appLogger.Error("Failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	if cfg.Tracing.OTLPEndpoint != "" {
+		appLogger.Info("Tracing enabled", "otlp_endpoint", cfg.Tracing.OTLPEndpoint, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+
 	// --- Initialize Redis Client ---
 	redisClient, err := database.NewRedisClient(cfg.Redis)
 	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		appLogger.Error("Failed to connect to Redis", "error", err)
+		os.Exit(1)
 	}
 	defer redisClient.Close()
 
 	dbPool, err := database.NewConnectionPool(cfg.DB)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		appLogger.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 	defer dbPool.Close()
 
+	// --- Seed Admin ---
+	if cfg.Auth.AdminEmail != "" {
+		if err := database.SeedAdmin(context.Background(), dbPool, cfg.Auth.AdminEmail); err != nil {
+			appLogger.Warn("Failed to seed admin user", "error", err)
+		}
+	}
+
 	// --- Initialize Blockchain Event Listener ---
 	var eventListener *blockchain.EventListener
 	if cfg.Blockchain.RPCURL != "" && cfg.Blockchain.ContractAddress != "" && cfg.Blockchain.ContractABIPath != "" {
 		var err error
-		eventListener, err = blockchain.NewEventListener(cfg.Blockchain.RPCURL, cfg.Blockchain.ContractAddress, cfg.Blockchain.ContractABIPath /*, pass services here */)
+		blockCursor := blockchain.NewRedisBlockCursor(redisClient, cfg.Blockchain.ContractAddress)
+		eventListener, err = blockchain.NewEventListener(cfg.Blockchain.RPCURL, cfg.Blockchain.ContractAddress, cfg.Blockchain.ContractABIPath, blockCursor, cfg.Blockchain.StartBlock, cfg.Blockchain.ReconnectMaxRetries, appLogger /*, pass services here */)
 		if err != nil {
-			log.Printf("WARN: Failed to initialize blockchain event listener: %v. Continuing without listener.", err)
+			appLogger.Warn("Failed to initialize blockchain event listener, continuing without listener", "error", err)
+			eventListener = nil // Keep readiness from probing a listener that never started
 		} else {
 			eventListener.Start(context.Background()) // Start listening in the background
-			log.Println("Blockchain event listener initialized and started")
+			appLogger.Info("Blockchain event listener initialized and started")
+		}
+	} else {
+		appLogger.Info("Blockchain listener configuration missing (RPC URL, Address, or ABI Path), skipping initialization")
+	}
+
+	// --- Initialize Notification Hub ---
+	notificationHub := notifications.NewHub(redisClient, cfg.CORS.AllowedOrigins)
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	defer stopHub()
+	go notificationHub.Run(hubCtx) // Delivers events published by any instance to local connections
+	jobStreams := notifications.NewJobStreams(redisClient)
+	go jobStreams.Run(hubCtx) // Same fan-out for the per-job server-sent event streams
+
+	// --- Initialize Mailer ---
+	deliveryMailer := mailer.New(mailer.SMTPConfig{
+		Host:     cfg.Mailer.SMTPHost,
+		Port:     cfg.Mailer.SMTPPort,
+		Username: cfg.Mailer.SMTPUsername,
+		Password: cfg.Mailer.SMTPPassword,
+		From:     cfg.Mailer.From,
+	})
+	if cfg.Mailer.SMTPHost == "" {
+		appLogger.Info("SMTP not configured, emails will only be logged")
+	}
+
+	// --- Initialize Outbox Relay ---
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	serviceMailer := deliveryMailer
+	if cfg.Outbox.Enabled {
+		var next outbox.Publisher = outbox.NewLogPublisher(appLogger)
+		if cfg.Webhook.Enabled {
+			next = outbox.NewWebhookPublisher(postgres.NewWebhookRepo(dbPool), next) // Queues a delivery for each subscribed webhook
+		}
+		publisher := outbox.NewMailPublisher(deliveryMailer, next)
+		relay := outbox.NewRelay(postgres.NewOutboxRepo(dbPool), publisher, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize, appLogger)
+		go relay.Run(relayCtx) // Publishes domain events recorded by the services and sends queued emails
+		serviceMailer = mailer.NewQueue(postgres.NewOutboxRepo(dbPool)) // Services queue emails for the relay instead of waiting on SMTP
+	} else {
+		appLogger.Info("Outbox relay disabled, events will accumulate in the outbox table and emails are sent inline")
+	}
+
+	// --- Initialize Webhook Worker ---
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	defer stopWebhooks()
+	if cfg.Webhook.Enabled {
+		worker := webhooks.NewWorker(postgres.NewWebhookRepo(dbPool), cfg.Webhook.PollInterval, cfg.Webhook.BatchSize, cfg.Webhook.MaxAttempts, cfg.Webhook.Timeout, appLogger)
+		go worker.Run(webhookCtx) // POSTs the deliveries the relay queued, retrying failed ones with backoff
+		if !cfg.Outbox.Enabled {
+			appLogger.Warn("Webhook worker running without the outbox relay, no new deliveries will be queued")
 		}
 	} else {
-		log.Println("Blockchain listener configuration missing (RPC URL, Address, or ABI Path), skipping initialization.")
+		appLogger.Info("Webhook delivery disabled")
+	}
+
+	// Opt-in read-through cache for user and job lookups; services invalidate it on every write
+	readCache := cache.Config{Client: redisClient, TTL: cfg.Cache.TTL, Users: cfg.Cache.Users, Jobs: cfg.Cache.Jobs}
+
+	// Only one instance at a time runs each background sweep
+	schedulerLock := lock.NewLocker(redisClient, cfg.Scheduler.LockTTL)
+
+	// --- Initialize Job Archiver ---
+	archiverCtx, stopArchiver := context.WithCancel(context.Background())
+	defer stopArchiver()
+	if cfg.Archiver.Enabled {
+		archiver := scheduler.NewJobArchiver(services.NewJobService(dbPool, cfg.Invoice.PaymentTermDays, cfg.Currency.Default, cfg.Contractor.MaxOngoingJobs, readCache), cfg.Archiver.Interval, cfg.Archiver.After, cfg.Archiver.BatchSize, appLogger).WithLock(schedulerLock)
+		go archiver.Run(archiverCtx) // Archives jobs that have stayed Complete past the threshold
+	} else {
+		appLogger.Info("Job archiver disabled")
+	}
+
+	// --- Initialize Retention Purge ---
+	purgerCtx, stopPurger := context.WithCancel(context.Background())
+	defer stopPurger()
+	if cfg.Retention.Enabled {
+		purger := scheduler.NewJobPurger(services.NewJobService(dbPool, cfg.Invoice.PaymentTermDays, cfg.Currency.Default, cfg.Contractor.MaxOngoingJobs, readCache), cfg.Retention.Interval, cfg.Retention.Archived, cfg.Retention.BatchSize, appLogger).WithLock(schedulerLock)
+		go purger.Run(purgerCtx) // Deletes jobs archived past the retention period, with their paid invoices
+	} else {
+		appLogger.Info("Retention purge disabled")
 	}
 
+	// --- Initialize Auto-Invoicer ---
+	autoInvoicerCtx, stopAutoInvoicer := context.WithCancel(context.Background())
+	defer stopAutoInvoicer()
+	if cfg.AutoInvoice.Enabled {
+		invoiceService := services.NewInvoiceService(dbPool, jobStreams, cfg.Invoice.PaymentTermDays, cfg.Invoice.MaxAdjustmentPercent, readCache)
+		autoInvoicer := scheduler.NewAutoInvoicer(invoiceService, cfg.AutoInvoice.Interval, cfg.AutoInvoice.BatchSize, appLogger).WithLock(schedulerLock)
+		go autoInvoicer.Run(autoInvoicerCtx) // Creates the next interval invoice of jobs with automatic invoicing on
+	} else {
+		appLogger.Info("Auto-invoicer disabled")
+	}
+
+	// --- Initialize Feature Flags ---
+	var flagOverrides *redis.Client
+	if cfg.Flags.Redis {
+		flagOverrides = redisClient // flag:<name> keys toggle features at runtime
+	}
+	featureFlags := flags.New(cfg.Flags.States, flagOverrides)
+
 	validate := validator.New()
 
 	application := &app.Application{
@@ -81,6 +225,13 @@ func main() {
 		DBPool:   dbPool,
 		RedisClient: redisClient,
 		Validator: validate,
+		EventListener: eventListener,
+		Notifications: notificationHub,
+		JobStreams:    jobStreams,
+		Mailer:        serviceMailer,
+		Flags:         featureFlags,
+		Logger:    appLogger,
+		ReadCache: readCache,
 	}
 
 	srv := server.NewServer(application)
@@ -88,7 +239,7 @@ func main() {
 	// --- Graceful Shutdown Handling ---
 	go func() {
 		if err := srv.Start(); err != nil {
-			log.Printf("Server error: %v", err)
+			appLogger.Error("Server error", "error", err)
 		}
 	}()
 
@@ -96,15 +247,34 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit // Block until a signal is received
 
-	log.Println("Shutting down server and listener...")
+	appLogger.Info("Shutting down server and listener")
+
+	// Stop taking requests and let the ones in progress finish while their dependencies are still running
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.Server.ShutdownDrain)
+	if err := srv.Shutdown(drainCtx); err != nil {
+		appLogger.Warn("HTTP server did not drain cleanly", "error", err)
+	}
+	cancelDrain()
 
 	// Stop the listener (if initialized)
 	if eventListener != nil {
 		eventListener.Stop()
 	}
 
-	//Gin shutdowns on its own
+	stopHub()
+	stopRelay()
+	stopWebhooks()
+	stopArchiver()
+	stopPurger()
+	stopAutoInvoicer()
+
+	// Flush spans still buffered in the exporter
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		appLogger.Warn("Failed to flush traces", "error", err)
+	}
 
-	log.Println("Application gracefully stopped.")
+	appLogger.Info("Application gracefully stopped")
 }
 
@@ -0,0 +1,19 @@
+// Package auth defines the JWT claims of access tokens, shared by the code that mints them and the
+// middleware that verifies them.
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AccessTokenClaims are the JWT claims carried by access tokens.
+type AccessTokenClaims struct {
+	Role string       `json:"role"`          // Read by middleware.RequireCapability; empty on tokens minted before roles existed
+	Act  *ActorClaims `json:"act,omitempty"` // Set on impersonation tokens (RFC 8693)
+	jwt.RegisteredClaims
+}
+
+// ActorClaims identify who is really acting when a token is used on another user's behalf.
+type ActorClaims struct {
+	Subject string `json:"sub"` // ID of the admin impersonating the token's subject
+}
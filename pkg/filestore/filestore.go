@@ -0,0 +1,19 @@
+// Package filestore stores uploaded files, such as user avatars, and hands back the URL they are served from.
+package filestore
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidName is returned when a file name is empty, hidden or contains a path separator.
+var ErrInvalidName = errors.New("invalid file name")
+
+// Storage keeps uploaded files. The local disk implementation is Local; an S3-compatible one can
+// be swapped in without touching callers.
+type Storage interface {
+	// Put stores data under name, replacing any file of that name, and returns its public URL.
+	Put(ctx context.Context, name, contentType string, data []byte) (string, error)
+	// Delete removes the file served at url. Deleting a file that doesn't exist is not an error.
+	Delete(ctx context.Context, url string) error
+}
@@ -0,0 +1,85 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores files in a directory on disk, served by the API under baseURL.
+type Local struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocal creates a Local store, creating dir if it doesn't exist yet.
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating file store directory: %w", err)
+	}
+	return &Local{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+// Compile-time check to ensure Local implements Storage
+var _ Storage = (*Local)(nil)
+
+// Put writes data to a temporary file first and renames it into place, so a file is never served half written.
+func (l *Local) Put(ctx context.Context, name, contentType string, data []byte) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("writing file %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("writing file %s: %w", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("setting permissions of file %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(l.dir, name)); err != nil {
+		return "", fmt.Errorf("storing file %s: %w", name, err)
+	}
+
+	return l.baseURL + "/" + name, nil
+}
+
+// Delete removes the file served at url. URLs outside baseURL are rejected with ErrInvalidName.
+func (l *Local) Delete(ctx context.Context, url string) error {
+	dir, name := path.Split(url)
+	if strings.TrimSuffix(dir, "/") != l.baseURL {
+		return fmt.Errorf("%w: %s is not served by this store", ErrInvalidName, url)
+	}
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(l.dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("deleting file %s: %w", name, err)
+	}
+	return nil
+}
+
+// validateName only allows plain file names, so callers can never write outside the store's directory.
+func validateName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocal(dir, "/uploads/avatars/")
+	require.NoError(t, err)
+
+	url, err := store.Put(context.Background(), "avatar.png", "image/png", []byte("png"))
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/avatars/avatar.png", url)
+
+	data, err := os.ReadFile(filepath.Join(dir, "avatar.png"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png"), data)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file should be renamed into place")
+
+	require.NoError(t, store.Delete(context.Background(), url))
+	_, err = os.Stat(filepath.Join(dir, "avatar.png"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	assert.NoError(t, store.Delete(context.Background(), url), "deleting a missing file is not an error")
+}
+
+func TestLocal_RejectsInvalidNames(t *testing.T) {
+	store, err := NewLocal(t.TempDir(), "/uploads/avatars")
+	require.NoError(t, err)
+
+	for _, name := range []string{"", ".hidden", "../escape.png", `dir\file.png`} {
+		_, err := store.Put(context.Background(), name, "image/png", []byte("png"))
+		assert.ErrorIs(t, err, ErrInvalidName, "name %q", name)
+	}
+
+	assert.ErrorIs(t, store.Delete(context.Background(), "/elsewhere/avatar.png"), ErrInvalidName)
+	assert.ErrorIs(t, store.Delete(context.Background(), "/uploads/avatars/../avatar.png"), ErrInvalidName)
+}
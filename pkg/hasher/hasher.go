@@ -0,0 +1,105 @@
+// Package hasher hashes and verifies passwords with bcrypt or Argon2id.
+//
+// Hashes are stored in each algorithm's standard encoding, which starts with an identifier ("$2a$"
+// or "$2b$" for bcrypt, "$argon2id$" for Argon2id). The hasher returned by New reads that prefix to
+// pick the verifier, so hashes made under an earlier default keep working after the default changes.
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Algorithm names a supported password hashing algorithm.
+type Algorithm string
+
+const (
+	Bcrypt   Algorithm = "bcrypt"
+	Argon2id Algorithm = "argon2id"
+)
+
+var (
+	ErrMismatch         = errors.New("password does not match hash")
+	ErrUnknownAlgorithm = errors.New("unknown password hashing algorithm")
+	ErrMalformedHash    = errors.New("malformed password hash")
+)
+
+// Hasher hashes new passwords and verifies passwords against stored hashes.
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(encodedHash, password string) error // ErrMismatch if the password is wrong
+	NeedsRehash(encodedHash string) bool       // True if the hash is weaker than, or differs from, what Hash produces now
+}
+
+// Config selects the algorithm for new hashes and the parameters of each algorithm.
+type Config struct {
+	Algorithm  Algorithm // Used for new hashes; every supported algorithm is still verified
+	BcryptCost int
+	Argon2id   Argon2idParams
+}
+
+// ParseAlgorithm returns the Algorithm named by s (case-insensitive).
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch alg := Algorithm(strings.ToLower(strings.TrimSpace(s))); alg {
+	case Bcrypt, Argon2id:
+		return alg, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownAlgorithm, s)
+	}
+}
+
+// AlgorithmOf identifies the algorithm of an encoded hash from its prefix.
+func AlgorithmOf(encodedHash string) (Algorithm, error) {
+	switch {
+	case strings.HasPrefix(encodedHash, "$argon2id$"):
+		return Argon2id, nil
+	case strings.HasPrefix(encodedHash, "$2a$"), strings.HasPrefix(encodedHash, "$2b$"), strings.HasPrefix(encodedHash, "$2y$"):
+		return Bcrypt, nil
+	default:
+		return "", ErrUnknownAlgorithm
+	}
+}
+
+// multiHasher hashes with the default algorithm and verifies with whichever one made the hash.
+type multiHasher struct {
+	defaultAlgorithm Algorithm
+	hashers          map[Algorithm]Hasher
+}
+
+// New creates a Hasher that hashes with cfg.Algorithm and verifies hashes of any supported algorithm.
+func New(cfg Config) (Hasher, error) {
+	defaultAlgorithm, err := ParseAlgorithm(string(cfg.Algorithm))
+	if err != nil {
+		return nil, err
+	}
+	return &multiHasher{
+		defaultAlgorithm: defaultAlgorithm,
+		hashers: map[Algorithm]Hasher{
+			Bcrypt:   NewBcrypt(cfg.BcryptCost),
+			Argon2id: NewArgon2id(cfg.Argon2id),
+		},
+	}, nil
+}
+
+func (h *multiHasher) Hash(password string) (string, error) {
+	return h.hashers[h.defaultAlgorithm].Hash(password)
+}
+
+func (h *multiHasher) Verify(encodedHash, password string) error {
+	alg, err := AlgorithmOf(encodedHash)
+	if err != nil {
+		return err
+	}
+	return h.hashers[alg].Verify(encodedHash, password)
+}
+
+// NeedsRehash reports true for hashes of another algorithm, so switching the default migrates
+// users as they log in.
+func (h *multiHasher) NeedsRehash(encodedHash string) bool {
+	alg, err := AlgorithmOf(encodedHash)
+	if err != nil || alg != h.defaultAlgorithm {
+		return true
+	}
+	return h.hashers[alg].NeedsRehash(encodedHash)
+}
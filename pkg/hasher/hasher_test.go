@@ -0,0 +1,103 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2idParams keeps Argon2id cheap so the tests stay fast.
+var testArgon2idParams = Argon2idParams{MemoryKiB: 1024, Iterations: 1, Parallelism: 1}
+
+func newTestHasher(t *testing.T, alg Algorithm) Hasher {
+	t.Helper()
+	h, err := New(Config{Algorithm: alg, BcryptCost: bcrypt.MinCost, Argon2id: testArgon2idParams})
+	require.NoError(t, err)
+	return h
+}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	tests := []struct {
+		algorithm Algorithm
+		prefix    string
+	}{
+		{algorithm: Bcrypt, prefix: "$2a$"},
+		{algorithm: Argon2id, prefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.algorithm), func(t *testing.T) {
+			h := newTestHasher(t, tt.algorithm)
+			encoded, err := h.Hash("correct horse")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(encoded, tt.prefix), "unexpected encoding %q", encoded)
+
+			alg, err := AlgorithmOf(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tt.algorithm, alg)
+
+			assert.NoError(t, h.Verify(encoded, "correct horse"))
+			assert.ErrorIs(t, h.Verify(encoded, "wrong horse"), ErrMismatch)
+			assert.False(t, h.NeedsRehash(encoded))
+
+			again, err := h.Hash("correct horse")
+			require.NoError(t, err)
+			assert.NotEqual(t, encoded, again, "Hashes must be salted")
+		})
+	}
+}
+
+func TestHasher_CrossAlgorithmVerification(t *testing.T) {
+	bcryptDefault := newTestHasher(t, Bcrypt)
+	argonDefault := newTestHasher(t, Argon2id)
+
+	bcryptHash, err := bcryptDefault.Hash("s3cret-Pass")
+	require.NoError(t, err)
+	argonHash, err := argonDefault.Hash("s3cret-Pass")
+	require.NoError(t, err)
+
+	// Whatever the default, both kinds of stored hash verify
+	for _, h := range []Hasher{bcryptDefault, argonDefault} {
+		assert.NoError(t, h.Verify(bcryptHash, "s3cret-Pass"))
+		assert.NoError(t, h.Verify(argonHash, "s3cret-Pass"))
+		assert.ErrorIs(t, h.Verify(bcryptHash, "wrong"), ErrMismatch)
+		assert.ErrorIs(t, h.Verify(argonHash, "wrong"), ErrMismatch)
+	}
+
+	// Only hashes of another algorithm are due for a rehash
+	assert.True(t, argonDefault.NeedsRehash(bcryptHash))
+	assert.False(t, argonDefault.NeedsRehash(argonHash))
+	assert.True(t, bcryptDefault.NeedsRehash(argonHash))
+	assert.False(t, bcryptDefault.NeedsRehash(bcryptHash))
+}
+
+func TestHasher_NeedsRehashOnWeakerParameters(t *testing.T) {
+	weakBcrypt, err := NewBcrypt(bcrypt.MinCost).Hash("pw")
+	require.NoError(t, err)
+	assert.True(t, NewBcrypt(bcrypt.MinCost+1).NeedsRehash(weakBcrypt))
+
+	weakArgon, err := NewArgon2id(testArgon2idParams).Hash("pw")
+	require.NoError(t, err)
+	stronger := testArgon2idParams
+	stronger.Iterations++
+	assert.True(t, NewArgon2id(stronger).NeedsRehash(weakArgon))
+	assert.NoError(t, NewArgon2id(stronger).Verify(weakArgon, "pw"), "Older parameters must still verify")
+}
+
+func TestHasher_RejectsUnknownAndMalformedHashes(t *testing.T) {
+	h := newTestHasher(t, Argon2id)
+	assert.ErrorIs(t, h.Verify("plaintext", "plaintext"), ErrUnknownAlgorithm)
+	assert.ErrorIs(t, h.Verify("$argon2id$v=19$m=1024,t=1,p=1$!!$!!", "pw"), ErrMalformedHash)
+	assert.ErrorIs(t, h.Verify("$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5", "pw"), ErrMalformedHash)
+
+	_, err := New(Config{Algorithm: "md5"})
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+	_, err = ParseAlgorithm("md5")
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+	alg, err := ParseAlgorithm(" Argon2ID ")
+	require.NoError(t, err)
+	assert.Equal(t, Argon2id, alg)
+}
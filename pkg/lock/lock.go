@@ -0,0 +1,172 @@
+// Package lock provides named locks held in Redis, so work that must only happen once across all
+// instances, such as a scheduled sweep, is done by whichever instance takes the lock.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLockPrefix prefixes the key holding a lock; RedisFencePrefix the counter its tokens come from.
+const (
+	RedisLockPrefix  = "lock:"
+	RedisFencePrefix = "lock_fence:"
+)
+
+var (
+	// ErrNotAcquired is returned by TryAcquire when another holder has the lock.
+	ErrNotAcquired = errors.New("lock is held by someone else")
+	// ErrLost is returned when a lock expired, and may have been taken by someone else, before it
+	// was refreshed or released.
+	ErrLost = errors.New("lock was lost")
+)
+
+// Only touch the key while it still holds our value, so a holder whose lock expired can't release or
+// extend the lock of whoever took it next.
+var (
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+// Locker hands out locks that expire after ttl unless refreshed, so a crashed holder can't keep a
+// lock forever.
+type Locker struct {
+	client   redis.Cmdable
+	ttl      time.Duration
+	newOwner func() (string, error) // Replaced in tests
+}
+
+// NewLocker creates a Locker whose locks expire ttl after they were taken or last refreshed.
+func NewLocker(client redis.Cmdable, ttl time.Duration) *Locker {
+	return &Locker{client: client, ttl: ttl, newOwner: randomOwner}
+}
+
+// Lock is a lock taken by TryAcquire.
+type Lock struct {
+	locker *Locker
+	key    string
+	value  string // Unique to this acquisition, so only it can refresh or release the lock
+	// Token is the fencing token of this acquisition. Tokens of later acquisitions of the same lock
+	// are always greater, so a store handed the token can reject writes from a holder that has since
+	// lost the lock.
+	Token int64
+}
+
+// TryAcquire takes the lock called name, returning ErrNotAcquired if someone else holds it.
+func (l *Locker) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	owner, err := l.newOwner()
+	if err != nil {
+		return nil, fmt.Errorf("generating lock owner: %w", err)
+	}
+	// Drawn before SET so tokens grow with every acquisition; a failed attempt just skips one
+	token, err := l.client.Incr(ctx, RedisFencePrefix+name).Result()
+	if err != nil {
+		return nil, fmt.Errorf("drawing fencing token for lock %q: %w", name, err)
+	}
+
+	lock := &Lock{locker: l, key: RedisLockPrefix + name, value: strconv.FormatInt(token, 10) + ":" + owner, Token: token}
+	acquired, err := l.client.SetNX(ctx, lock.key, lock.value, l.ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring lock %q: %w", name, err)
+	}
+	if !acquired {
+		return nil, ErrNotAcquired
+	}
+	return lock, nil
+}
+
+// Refresh extends the lock to a full ttl from now, or returns ErrLost if it has already expired.
+func (lk *Lock) Refresh(ctx context.Context) error {
+	extended, err := refreshScript.Run(ctx, lk.locker.client, []string{lk.key}, lk.value, lk.locker.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("refreshing lock %q: %w", lk.key, err)
+	}
+	if extended == 0 {
+		return ErrLost
+	}
+	return nil
+}
+
+// Release gives the lock up, or returns ErrLost if it had already expired.
+func (lk *Lock) Release(ctx context.Context) error {
+	deleted, err := releaseScript.Run(ctx, lk.locker.client, []string{lk.key}, lk.value).Int64()
+	if err != nil {
+		return fmt.Errorf("releasing lock %q: %w", lk.key, err)
+	}
+	if deleted == 0 {
+		return ErrLost
+	}
+	return nil
+}
+
+// Do runs fn while holding the lock called name and reports whether it ran; it returns false without
+// calling fn if someone else holds the lock. The lock is refreshed every third of its ttl while fn
+// runs, and fn's context is cancelled if it is lost anyway, e.g. because Redis was unreachable for
+// longer than the ttl. The error is fn's, or ErrLost if the lock was lost while it ran.
+func (l *Locker) Do(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	lock, err := l.TryAcquire(ctx, name)
+	if errors.Is(err, ErrNotAcquired) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fnCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-fnCtx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Refresh(fnCtx); errors.Is(err, ErrLost) {
+					cancel(ErrLost)
+					return
+				}
+				// Other errors are retried on the next tick; the lock only goes once its ttl runs out
+			}
+		}
+	}()
+
+	err = fn(fnCtx)
+	cancel(nil)
+	<-stopped
+	if lost := context.Cause(fnCtx); errors.Is(lost, ErrLost) {
+		return true, errors.Join(ErrLost, err)
+	}
+
+	// Released with the caller's context, which outlives fnCtx; a lock that can't be released expires
+	if releaseErr := lock.Release(context.WithoutCancel(ctx)); releaseErr != nil && !errors.Is(releaseErr, ErrLost) {
+		return true, errors.Join(err, releaseErr)
+	}
+	return true, err
+}
+
+// randomOwner returns a random value telling one acquisition apart from every other.
+func randomOwner() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,156 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLocker builds a Locker on client whose acquisitions are all owned by owner.
+func newTestLocker(client redis.Cmdable, ttl time.Duration, owner string) *Locker {
+	locker := NewLocker(client, ttl)
+	locker.newOwner = func() (string, error) { return owner, nil }
+	return locker
+}
+
+func TestLocker_MutualExclusionAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	ttl := 30 * time.Second
+	client, mock := redismock.NewClientMock()
+	first := newTestLocker(client, ttl, "first")
+	second := newTestLocker(client, ttl, "second") // Another instance, sharing the same Redis
+
+	// first takes the lock; second is turned away while first holds it
+	mock.ExpectIncr("lock_fence:archiver").SetVal(1)
+	mock.ExpectSetNX("lock:archiver", "1:first", ttl).SetVal(true)
+	mock.ExpectIncr("lock_fence:archiver").SetVal(2)
+	mock.ExpectSetNX("lock:archiver", "2:second", ttl).SetVal(false)
+
+	held, err := first.TryAcquire(ctx, "archiver")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), held.Token)
+	_, err = second.TryAcquire(ctx, "archiver")
+	assert.ErrorIs(t, err, ErrNotAcquired)
+
+	// The lock expires without being refreshed, so second takes it and first can no longer use it
+	mock.ExpectIncr("lock_fence:archiver").SetVal(3)
+	mock.ExpectSetNX("lock:archiver", "3:second", ttl).SetVal(true)
+	mock.ExpectEvalSha(refreshScript.Hash(), []string{"lock:archiver"}, "1:first", ttl.Milliseconds()).SetVal(int64(0))
+	mock.ExpectEvalSha(releaseScript.Hash(), []string{"lock:archiver"}, "1:first").SetVal(int64(0))
+	mock.ExpectEvalSha(releaseScript.Hash(), []string{"lock:archiver"}, "3:second").SetVal(int64(1))
+
+	taken, err := second.TryAcquire(ctx, "archiver")
+	require.NoError(t, err)
+	assert.Greater(t, taken.Token, held.Token, "A later holder gets a greater fencing token")
+	assert.ErrorIs(t, held.Refresh(ctx), ErrLost)
+	assert.ErrorIs(t, held.Release(ctx), ErrLost, "An expired holder must not release its successor's lock")
+	assert.NoError(t, taken.Release(ctx))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLocker_DoSkipsWhenHeld(t *testing.T) {
+	ctx := context.Background()
+	ttl := 30 * time.Second
+	client, mock := redismock.NewClientMock()
+	locker := newTestLocker(client, ttl, "owner")
+
+	mock.ExpectIncr("lock_fence:auto_invoicer").SetVal(4)
+	mock.ExpectSetNX("lock:auto_invoicer", "4:owner", ttl).SetVal(false)
+
+	ran, err := locker.Do(ctx, "auto_invoicer", func(ctx context.Context) error {
+		t.Fatal("fn must not run without the lock")
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.False(t, ran)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLocker_DoRefreshesUntilDone(t *testing.T) {
+	ctx := context.Background()
+	ttl := 30 * time.Millisecond // Refreshed every 10ms
+	client, mock := redismock.NewClientMock()
+	mock.MatchExpectationsInOrder(false)
+	locker := newTestLocker(client, ttl, "owner")
+
+	mock.ExpectIncr("lock_fence:archiver").SetVal(1)
+	mock.ExpectSetNX("lock:archiver", "1:owner", ttl).SetVal(true)
+	for i := 0; i < 50; i++ { // More than the run can use
+		mock.ExpectEvalSha(refreshScript.Hash(), []string{"lock:archiver"}, "1:owner", ttl.Milliseconds()).SetVal(int64(1))
+	}
+	mock.ExpectEvalSha(releaseScript.Hash(), []string{"lock:archiver"}, "1:owner").SetVal(int64(1))
+
+	var refreshedDuringRun atomic.Bool
+	ran, err := locker.Do(ctx, "archiver", func(ctx context.Context) error {
+		time.Sleep(5 * ttl) // Outlives the ttl several times over
+		refreshedDuringRun.Store(ctx.Err() == nil)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.True(t, refreshedDuringRun.Load(), "A refreshed lock must not cancel the run")
+}
+
+func TestLocker_DoCancelsWhenLost(t *testing.T) {
+	ctx := context.Background()
+	ttl := 30 * time.Millisecond
+	client, mock := redismock.NewClientMock()
+	locker := newTestLocker(client, ttl, "owner")
+
+	mock.ExpectIncr("lock_fence:archiver").SetVal(1)
+	mock.ExpectSetNX("lock:archiver", "1:owner", ttl).SetVal(true)
+	mock.ExpectEvalSha(refreshScript.Hash(), []string{"lock:archiver"}, "1:owner", ttl.Milliseconds()).SetVal(int64(0))
+
+	ran, err := locker.Do(ctx, "archiver", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("run was not cancelled")
+		}
+	})
+
+	assert.True(t, ran)
+	assert.ErrorIs(t, err, ErrLost)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestLocker_Redis checks the same guarantees against a real Redis, including the expiry itself.
+func TestLocker_Redis(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_URL")
+	if addr == "" {
+		t.Skip("Skipping Redis test: TEST_REDIS_URL not set")
+	}
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() }) // Registered first so it runs after the key cleanup
+	require.NoError(t, client.Ping(ctx).Err())
+	name := "test:" + t.Name() + ":" + time.Now().Format(time.RFC3339Nano)
+	t.Cleanup(func() { client.Del(context.Background(), RedisLockPrefix+name, RedisFencePrefix+name) })
+
+	ttl := 200 * time.Millisecond
+	first, second := NewLocker(client, ttl), NewLocker(client, ttl)
+
+	held, err := first.TryAcquire(ctx, name)
+	require.NoError(t, err)
+	_, err = second.TryAcquire(ctx, name)
+	assert.ErrorIs(t, err, ErrNotAcquired)
+
+	require.Eventually(t, func() bool {
+		_, err := second.TryAcquire(ctx, name)
+		return err == nil
+	}, 2*time.Second, 20*time.Millisecond, "The lock must expire once its ttl runs out")
+	assert.ErrorIs(t, held.Refresh(ctx), ErrLost)
+	assert.ErrorIs(t, held.Release(ctx), ErrLost)
+}
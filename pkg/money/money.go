@@ -0,0 +1,118 @@
+// Package money validates ISO 4217 currency codes and rounds and formats amounts in them.
+//
+// Only the currencies listed here are accepted. Each has its number of minor units (2 for cents, 0 for
+// currencies like JPY without any), which decides how amounts are rounded and how many decimals they show.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownCurrency is returned for codes that aren't a supported ISO 4217 currency.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+type currency struct {
+	symbol   string // Put before the amount; codes without a well-known symbol use the code itself
+	decimals int    // ISO 4217 minor units
+}
+
+var currencies = map[string]currency{
+	"AUD": {symbol: "A$", decimals: 2},
+	"BRL": {symbol: "R$", decimals: 2},
+	"CAD": {symbol: "CA$", decimals: 2},
+	"CHF": {symbol: "CHF ", decimals: 2},
+	"CNY": {symbol: "CN¥", decimals: 2},
+	"DKK": {symbol: "DKK ", decimals: 2},
+	"EUR": {symbol: "€", decimals: 2},
+	"GBP": {symbol: "£", decimals: 2},
+	"HKD": {symbol: "HK$", decimals: 2},
+	"INR": {symbol: "₹", decimals: 2},
+	"JPY": {symbol: "¥", decimals: 0},
+	"KRW": {symbol: "₩", decimals: 0},
+	"MXN": {symbol: "MX$", decimals: 2},
+	"NOK": {symbol: "NOK ", decimals: 2},
+	"NZD": {symbol: "NZ$", decimals: 2},
+	"PLN": {symbol: "PLN ", decimals: 2},
+	"SEK": {symbol: "SEK ", decimals: 2},
+	"SGD": {symbol: "SGD ", decimals: 2},
+	"USD": {symbol: "$", decimals: 2},
+	"ZAR": {symbol: "ZAR ", decimals: 2},
+}
+
+// Normalize returns code upper-cased and trimmed, or ErrUnknownCurrency if it isn't supported.
+func Normalize(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := currencies[normalized]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return normalized, nil
+}
+
+// IsValid reports whether code is a supported currency as given, i.e. already upper-case.
+func IsValid(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// Codes returns the supported currency codes, sorted.
+func Codes() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Decimals returns the number of minor units of code, defaulting to 2 for unsupported codes.
+func Decimals(code string) int {
+	if c, ok := currencies[code]; ok {
+		return c.decimals
+	}
+	return 2
+}
+
+// Round rounds amount to the minor units of code, half away from zero.
+func Round(amount float64, code string) float64 {
+	scale := math.Pow(10, float64(Decimals(code)))
+	return math.Round(amount*scale) / scale
+}
+
+// FormatAmount renders amount with exactly the minor units of code and no symbol, e.g. "1234.50" for
+// USD or "1235" for JPY, for machine-readable output such as CSV.
+func FormatAmount(amount float64, code string) string {
+	return strconv.FormatFloat(Round(amount, code), 'f', Decimals(code), 64)
+}
+
+// Format renders amount for people: the currency's symbol, thousands separated by commas and the
+// currency's minor units, e.g. "$1,234.50", "-€12.00" or "¥1,235".
+func Format(amount float64, code string) string {
+	digits := FormatAmount(math.Abs(amount), code)
+	whole, fraction, hasFraction := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	if Round(amount, code) < 0 {
+		b.WriteByte('-')
+	}
+	if c, ok := currencies[code]; ok {
+		b.WriteString(c.symbol)
+	} else {
+		b.WriteString(code + " ")
+	}
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteByte('.')
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
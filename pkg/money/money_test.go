@@ -0,0 +1,57 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	code, err := Normalize(" eur ")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", code)
+
+	for _, invalid := range []string{"", "US", "DOLLAR", "XYZ", "usd1"} {
+		_, err := Normalize(invalid)
+		assert.True(t, errors.Is(err, ErrUnknownCurrency), "%q should be rejected", invalid)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, IsValid("USD"))
+	assert.False(t, IsValid("usd"), "Codes must already be normalized")
+	assert.False(t, IsValid("ABC"))
+	assert.Contains(t, Codes(), "JPY")
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, 10.13, Round(10.125, "USD"))
+	assert.Equal(t, 1235.0, Round(1234.5, "JPY"))
+	assert.Equal(t, -2.5, Round(-2.499, "EUR"))
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		code     string
+		expected string
+	}{
+		{amount: 0, code: "USD", expected: "$0.00"},
+		{amount: 1234.5, code: "USD", expected: "$1,234.50"},
+		{amount: 1234567.891, code: "EUR", expected: "€1,234,567.89"},
+		{amount: -12, code: "GBP", expected: "-£12.00"},
+		{amount: 1234.5, code: "JPY", expected: "¥1,235"},
+		{amount: 999.999, code: "CHF", expected: "CHF 1,000.00"},
+		{amount: -0.001, code: "USD", expected: "$0.00"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Format(tt.amount, tt.code), "%v %s", tt.amount, tt.code)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "1234.50", FormatAmount(1234.5, "USD"))
+	assert.Equal(t, "1235", FormatAmount(1234.5, "JPY"))
+}
@@ -0,0 +1,86 @@
+// Package password checks candidate passwords against a configurable strength policy.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrWeakPassword is returned (wrapped in a *PolicyError) when a password doesn't satisfy the policy.
+var ErrWeakPassword = errors.New("password does not meet strength requirements")
+
+// Policy describes the rules a password must satisfy.
+type Policy struct {
+	MinLength     int  // Minimum number of characters; zero or less disables the check
+	RequireUpper  bool // At least one upper-case letter
+	RequireLower  bool // At least one lower-case letter
+	RequireDigit  bool // At least one digit
+	RequireSymbol bool // At least one punctuation or symbol character
+}
+
+// DefaultPolicy returns the policy used when nothing is configured: at least 8 characters,
+// with an upper-case letter, a lower-case letter and a digit.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:    8,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+	}
+}
+
+// PolicyError lists every requirement a password failed, so clients can show them all at once.
+type PolicyError struct {
+	Unmet []string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s: must contain %s", ErrWeakPassword, strings.Join(e.Unmet, ", "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// Validate checks password against the policy. It returns nil if every rule is met, otherwise a
+// *PolicyError wrapping ErrWeakPassword.
+func (p Policy) Validate(password string) error {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	var unmet []string
+	if p.MinLength > 0 && utf8.RuneCountInString(password) < p.MinLength {
+		unmet = append(unmet, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !hasUpper {
+		unmet = append(unmet, "an upper-case letter")
+	}
+	if p.RequireLower && !hasLower {
+		unmet = append(unmet, "a lower-case letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		unmet = append(unmet, "a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		unmet = append(unmet, "a symbol")
+	}
+
+	if len(unmet) > 0 {
+		return &PolicyError{Unmet: unmet}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package password
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	strict := Policy{MinLength: 12, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name          string
+		policy        Policy
+		password      string
+		expectedUnmet []string // nil means the password is accepted
+	}{
+		{name: "Default_Valid", policy: DefaultPolicy(), password: "Password1"},
+		{name: "Default_TooShort", policy: DefaultPolicy(), password: "Pass1", expectedUnmet: []string{"at least 8 characters"}},
+		{name: "Default_MissingUpper", policy: DefaultPolicy(), password: "password1", expectedUnmet: []string{"an upper-case letter"}},
+		{name: "Default_MissingLower", policy: DefaultPolicy(), password: "PASSWORD1", expectedUnmet: []string{"a lower-case letter"}},
+		{name: "Default_MissingDigit", policy: DefaultPolicy(), password: "Passwords", expectedUnmet: []string{"a digit"}},
+		{name: "Default_SymbolNotRequired", policy: DefaultPolicy(), password: "Password1!"},
+		{name: "Strict_MissingSymbol", policy: strict, password: "LongPassword1", expectedUnmet: []string{"a symbol"}},
+		{name: "Strict_Valid", policy: strict, password: "LongPassword1!"},
+		{
+			name:          "Strict_ReportsEveryUnmetRule",
+			policy:        strict,
+			password:      "short",
+			expectedUnmet: []string{"at least 12 characters", "an upper-case letter", "a digit", "a symbol"},
+		},
+		{name: "LengthCountsCharactersNotBytes", policy: Policy{MinLength: 4}, password: "ñßçé"},
+		{name: "EmptyPolicy_AcceptsAnything", policy: Policy{}, password: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password)
+
+			if tt.expectedUnmet == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrWeakPassword))
+			var policyErr *PolicyError
+			require.True(t, errors.As(err, &policyErr))
+			assert.Equal(t, tt.expectedUnmet, policyErr.Unmet)
+		})
+	}
+}